@@ -1,23 +1,29 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
 
+	"github.com/guavi/osrs-ge-tracker/internal/activities"
+	"github.com/guavi/osrs-ge-tracker/internal/aliases"
 	"github.com/guavi/osrs-ge-tracker/internal/config"
 	"github.com/guavi/osrs-ge-tracker/internal/database"
 	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/marketindex"
 	"github.com/guavi/osrs-ge-tracker/internal/middleware"
 	"github.com/guavi/osrs-ge-tracker/internal/repository"
 	"github.com/guavi/osrs-ge-tracker/internal/scheduler"
 	"github.com/guavi/osrs-ge-tracker/internal/services"
+	"github.com/guavi/osrs-ge-tracker/internal/tagging"
 )
 
 func main() {
@@ -55,12 +61,68 @@ func main() {
 	// reduces chaining and makes dependencies explicit
 	itemRepo := repository.NewItemRepository(dbClient, logger)
 	priceRepo := repository.NewPriceRepository(dbClient, logger)
+	backtestRepo := repository.NewBacktestRepository(dbClient, logger)
+	paperRepo := repository.NewPaperTradingRepository(dbClient, logger)
+	journalRepo := repository.NewTradeJournalRepository(dbClient, logger)
+	buyLimitRepo := repository.NewBuyLimitRepository(dbClient, logger)
+	bankSnapshotRepo := repository.NewBankSnapshotRepository(dbClient, logger)
+	itemSetRepo := repository.NewItemSetRepository(dbClient, logger)
+	recipeRepo := repository.NewRecipeRepository(dbClient, logger)
+	marketIndexRepo := repository.NewMarketIndexRepository(dbClient, logger)
+	itemTagRepo := repository.NewItemTagRepository(dbClient, logger)
+	itemAliasRepo := repository.NewItemAliasRepository(dbClient, logger)
+	screenRepo := repository.NewScreenRepository(dbClient, logger)
+	itemStatsRepo := repository.NewItemStatsRepository(dbClient, logger)
 
 	// Initialize services
 	cacheService := services.NewCacheService(redisClient, logger)
 	itemService := services.NewItemService(itemRepo, cacheService, cfg.WikiPricesBaseURL, logger)
 	priceService := services.NewPriceService(priceRepo, itemRepo, cacheService, cfg.WikiPricesBaseURL, logger)
 	watchlistService := services.NewWatchlistService(dbClient, logger)
+	analyticsService := services.NewAnalyticsService(priceRepo, cacheService, logger)
+	backtestService := services.NewBacktestService(backtestRepo, priceRepo, itemRepo, logger)
+	paperService := services.NewPaperTradingService(paperRepo, priceRepo, itemRepo, logger)
+	portfolioService := services.NewPortfolioService(journalRepo, priceRepo, itemRepo, logger)
+	buyLimitService := services.NewBuyLimitService(buyLimitRepo, itemRepo, logger)
+	valuationService := services.NewValuationService(itemRepo, priceRepo, logger)
+	arbitrageService := services.NewArbitrageService(itemSetRepo, itemRepo, priceRepo, cacheService, logger)
+	recipeService := services.NewRecipeService(recipeRepo, itemRepo, priceRepo, logger)
+	activityDefs, err := activities.Load(cfg.ActivitiesDir)
+	if err != nil {
+		logger.Fatalf("Failed to load activities: %v", err)
+	}
+	activityService := services.NewActivityService(activityDefs, itemRepo, priceRepo, logger)
+	marketIndices, err := marketindex.Load(cfg.MarketIndicesFile)
+	if err != nil {
+		logger.Fatalf("Failed to load market indices: %v", err)
+	}
+	marketIndexService := services.NewMarketIndexService(marketIndices, marketIndexRepo, itemRepo, priceRepo, priceService, logger)
+	classifier, err := tagging.DefaultClassifier()
+	if err != nil {
+		logger.Fatalf("Failed to load item tagging rules: %v", err)
+	}
+	itemTagService := services.NewItemTagService(classifier, itemTagRepo, itemRepo, logger)
+	seedAliases, err := aliases.Seed()
+	if err != nil {
+		logger.Fatalf("Failed to load item aliases: %v", err)
+	}
+	itemAliasService := services.NewItemAliasService(seedAliases, itemAliasRepo, itemRepo, logger)
+	autocompleteService := services.NewAutocompleteService(itemRepo, itemAliasRepo, priceRepo, logger)
+	screenerService := services.NewScreenerService(screenRepo, itemRepo, priceRepo, priceService, logger)
+	marketTableService := services.NewMarketTableService(itemStatsRepo, screenerService, logger)
+	denominationService := services.NewDenominationService(priceService, cfg.BondRealPrice, cfg.BondRealCurrency, logger)
+	bankSnapshotService := services.NewBankSnapshotService(bankSnapshotRepo, itemRepo, priceRepo, valuationService, logger)
+	if failed, err := backtestService.FailInterrupted(context.Background()); err != nil {
+		logger.Warnf("Failed to clean up interrupted backtests: %v", err)
+	} else if failed > 0 {
+		logger.Infof("Marked %d interrupted backtests as failed", failed)
+	}
+	if _, err := itemAliasService.SeedAliases(context.Background()); err != nil {
+		logger.Warnf("Failed to seed item aliases: %v", err)
+	}
+	if err := autocompleteService.Rebuild(context.Background()); err != nil {
+		logger.Warnf("Failed to build autocomplete index: %v", err)
+	}
 
 	// Initialize SSE Hub if enabled
 	var sseHub *services.SSEHub
@@ -73,8 +135,24 @@ func main() {
 	// Initialize handlers
 	healthHandler := handlers.NewHealthHandler(dbClient, redisClient, logger)
 	itemHandler := handlers.NewItemHandler(itemService, priceService, logger)
-	priceHandler := handlers.NewPriceHandler(priceService, logger)
+	priceHandler := handlers.NewPriceHandler(priceService, watchlistService, denominationService, logger)
 	watchlistHandler := handlers.NewWatchlistHandler(watchlistService, logger)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService, watchlistService, logger)
+	backtestHandler := handlers.NewBacktestHandler(backtestService, logger)
+	paperHandler := handlers.NewPaperTradingHandler(paperService, logger)
+	portfolioHandler := handlers.NewPortfolioHandler(portfolioService, logger)
+	buyLimitHandler := handlers.NewBuyLimitHandler(buyLimitService, watchlistService, logger)
+	valuationHandler := handlers.NewValuationHandler(valuationService, denominationService, logger)
+	bankSnapshotHandler := handlers.NewBankSnapshotHandler(bankSnapshotService, denominationService, logger)
+	arbitrageHandler := handlers.NewArbitrageHandler(arbitrageService, logger)
+	recipeHandler := handlers.NewRecipeHandler(recipeService, logger)
+	activityHandler := handlers.NewActivityHandler(activityService, logger)
+	marketIndexHandler := handlers.NewMarketIndexHandler(marketIndexService, logger)
+	itemTagHandler := handlers.NewItemTagHandler(itemTagService, logger)
+	itemAliasHandler := handlers.NewItemAliasHandler(itemAliasService, logger)
+	autocompleteHandler := handlers.NewAutocompleteHandler(autocompleteService, logger)
+	screenerHandler := handlers.NewScreenerHandler(screenerService, logger)
+	marketTableHandler := handlers.NewMarketTableHandler(marketTableService, logger)
 
 	// Initialize SSE handler if enabled
 	var sseHandler *handlers.SSEHandler
@@ -148,10 +226,19 @@ func main() {
 
 	// Item routes
 	items := api.Group("/items")
-	items.Get("/", itemHandler.ListItems)         // GET /api/v1/items
-	items.Get("/search", itemHandler.SearchItems) // GET /api/v1/items/search?q=...
-	items.Get("/count", itemHandler.GetItemCount) // GET /api/v1/items/count
-	items.Get("/:id", itemHandler.GetItemByID)    // GET /api/v1/items/:id
+	items.Get("/", itemHandler.ListItems)                      // GET /api/v1/items
+	items.Get("/search", itemHandler.SearchItems)              // GET /api/v1/items/search?q=...
+	items.Get("/count", itemHandler.GetItemCount)              // GET /api/v1/items/count
+	items.Get("/resolve", itemAliasHandler.ResolveNames)       // GET /api/v1/items/resolve?name=bgs&name=tbow
+	items.Get("/autocomplete", autocompleteHandler.Complete)   // GET /api/v1/items/autocomplete?q=...
+	items.Get("/:id", itemHandler.GetItemByID)                 // GET /api/v1/items/:id
+	items.Get("/:id/tags", itemTagHandler.GetItemTags)         // GET /api/v1/items/:id/tags
+	items.Get("/:id/aliases", itemAliasHandler.GetItemAliases) // GET /api/v1/items/:id/aliases
+	// POST /api/v1/items/:id/aliases (queued for admin review; scoped to the X-User-ID header)
+	items.Post("/:id/aliases", middleware.RequireUserID(), itemAliasHandler.SubmitAlias)
+
+	// Tag routes
+	api.Get("/tags", itemTagHandler.ListTags) // GET /api/v1/tags
 
 	// Price routes
 	prices := api.Group("/prices")
@@ -160,6 +247,122 @@ func main() {
 	prices.Get("/current/:id", priceHandler.GetCurrentPrice)         // GET /api/v1/prices/current/:id
 	// GET /api/v1/prices/history/:id?period=7d&sample=150
 	prices.Get("/history/:id", priceHandler.GetPriceHistory)
+	// GET /api/v1/prices/compare?ids=11802,11804&period=30d&ratios=true
+	prices.Get("/compare", priceHandler.ComparePrices)
+	prices.Get("/at", priceHandler.GetBatchPricesAt) // GET /api/v1/prices/at?ids=1,2,3&ts=... or ?share=<token>&ts=...
+	prices.Get("/at/:id", priceHandler.GetPriceAt)   // GET /api/v1/prices/at/:id?ts=2026-03-14T18:00:00Z
+
+	// Analytics routes
+	analytics := api.Group("/analytics")
+	analytics.Get("/seasonality/:id", analyticsHandler.GetSeasonality) // GET /api/v1/analytics/seasonality/:id?lookback=28d
+	analytics.Get("/forecast/:id", analyticsHandler.GetForecast)       // GET /api/v1/analytics/forecast/:id?horizon=6
+	analytics.Get("/correlation", analyticsHandler.GetCorrelation)     // GET /api/v1/analytics/correlation?ids=1,2&window=30d
+
+	// Backtest routes
+	backtests := api.Group("/backtests")
+	backtests.Post("/", backtestHandler.CreateBacktest) // POST /api/v1/backtests
+	backtests.Get("/", backtestHandler.ListBacktests)   // GET /api/v1/backtests?limit=20
+	backtests.Get("/:id", backtestHandler.GetBacktest)  // GET /api/v1/backtests/:id
+
+	// Paper trading routes
+	paper := api.Group("/paper/accounts")
+	paper.Post("/", middleware.RequireUserID(), paperHandler.CreateAccount) // POST /api/v1/paper/accounts
+	paper.Get("/:id", paperHandler.GetAccount)                              // GET /api/v1/paper/accounts/:id
+	paper.Get("/:id/positions", paperHandler.ListPositions)                 // GET /api/v1/paper/accounts/:id/positions
+	paper.Get("/:id/offers", paperHandler.ListOffers)                       // GET /api/v1/paper/accounts/:id/offers?status=open
+	paper.Post("/:id/offers", paperHandler.PlaceOffer)                      // POST /api/v1/paper/accounts/:id/offers
+	paper.Delete("/:id/offers/:offerId", paperHandler.CancelOffer)          // DELETE /api/v1/paper/accounts/:id/offers/:offerId
+	paper.Get("/:id/fills", paperHandler.ListFills)                         // GET /api/v1/paper/accounts/:id/fills?limit=50
+
+	// Portfolio routes (scoped to the X-User-ID header)
+	portfolio := api.Group("/portfolio", middleware.RequireUserID())
+	portfolio.Get("/summary", portfolioHandler.GetSummary)                // GET /api/v1/portfolio/summary
+	portfolio.Get("/journal/export", portfolioHandler.ExportJournal)      // GET /api/v1/portfolio/journal/export
+	portfolio.Post("/journal/import", portfolioHandler.ImportJournal)     // POST /api/v1/portfolio/journal/import
+	portfolio.Get("/journal", portfolioHandler.ListJournal)               // GET /api/v1/portfolio/journal?itemId=4151&limit=100
+	portfolio.Post("/journal", portfolioHandler.AddJournalEntry)          // POST /api/v1/portfolio/journal
+	portfolio.Delete("/journal/:id", portfolioHandler.DeleteJournalEntry) // DELETE /api/v1/portfolio/journal/:id
+
+	// Buy limit routes (scoped to the X-User-ID header)
+	buyLimits := api.Group("/buy-limits", middleware.RequireUserID())
+	buyLimits.Get("/", buyLimitHandler.ListActive)                     // GET /api/v1/buy-limits
+	buyLimits.Get("/available", buyLimitHandler.ListAvailable)         // GET /api/v1/buy-limits/available?ids=1,2,3|share=token
+	buyLimits.Post("/purchases", buyLimitHandler.RecordPurchase)       // POST /api/v1/buy-limits/purchases
+	buyLimits.Delete("/purchases/:id", buyLimitHandler.DeletePurchase) // DELETE /api/v1/buy-limits/purchases/:id
+	buyLimits.Get("/:itemId", buyLimitHandler.GetStatus)               // GET /api/v1/buy-limits/:itemId
+
+	// Bank valuation routes
+	api.Post("/valuation", valuationHandler.ValueBank) // POST /api/v1/valuation
+
+	// Saved bank routes (scoped to the X-User-ID header)
+	bankSnapshots := api.Group("/bank-snapshots", middleware.RequireUserID())
+	bankSnapshots.Post("/", bankSnapshotHandler.CreateSnapshot)       // POST /api/v1/bank-snapshots?name=Main
+	bankSnapshots.Get("/", bankSnapshotHandler.ListSnapshots)         // GET /api/v1/bank-snapshots
+	bankSnapshots.Get("/:id", bankSnapshotHandler.GetSnapshot)        // GET /api/v1/bank-snapshots/:id
+	bankSnapshots.Get("/:id/history", bankSnapshotHandler.GetHistory) // GET /api/v1/bank-snapshots/:id/history?interval=1d
+	bankSnapshots.Delete("/:id", bankSnapshotHandler.DeleteSnapshot)  // DELETE /api/v1/bank-snapshots/:id
+
+	// Arbitrage routes
+	arbitrageGroup := api.Group("/arbitrage")
+	arbitrageGroup.Get("/sets/definitions", arbitrageHandler.ListSets)        // GET /api/v1/arbitrage/sets/definitions
+	arbitrageGroup.Get("/sets", arbitrageHandler.GetSetArbitrage)             // GET /api/v1/arbitrage/sets?pricing=offer&sort=profit
+	arbitrageGroup.Get("/doses/definitions", arbitrageHandler.ListDoseGroups) // GET /api/v1/arbitrage/doses/definitions
+	arbitrageGroup.Get("/doses", arbitrageHandler.GetDoseArbitrage)           // GET /api/v1/arbitrage/doses?pricing=offer&sort=profit
+
+	// Recipe routes
+	recipeGroup := api.Group("/recipes")
+	recipeGroup.Get("/definitions", recipeHandler.ListRecipes) // GET /api/v1/recipes/definitions
+	recipeGroup.Get("/", recipeHandler.GetRecipeProfits)       // GET /api/v1/recipes?sort=gp_per_hour&skill=Herblore
+
+	// PvM activity routes
+	activityGroup := api.Group("/activities")
+	activityGroup.Get("/", activityHandler.ListActivityValues)                       // GET /api/v1/activities?pricing=offer
+	activityGroup.Get("/definitions", activityHandler.ListActivities)                // GET /api/v1/activities/definitions
+	activityGroup.Get("/:id/value", activityHandler.GetActivityValue)                // GET /api/v1/activities/:id/value
+	activityGroup.Get("/:id/value/history", activityHandler.GetActivityValueHistory) // GET /api/v1/activities/:id/value/history?from=...
+
+	// Market table route (precomputed per-item stats for the dashboard)
+	api.Get("/market/table", marketTableHandler.GetTable) // GET /api/v1/market/table?sort_by=change_24h&order=desc&min_volume_24h=100
+
+	// Market index routes (custom indices are scoped to the X-User-ID header)
+	marketIndex := api.Group("/market/index")
+	marketIndex.Get("/", marketIndexHandler.ListIndices) // GET /api/v1/market/index
+	customIndex := marketIndex.Group("/custom", middleware.RequireUserID())
+	customIndex.Get("/", marketIndexHandler.ListIndices)                     // GET /api/v1/market/index/custom
+	customIndex.Post("/", marketIndexHandler.CreateCustomIndex)              // POST /api/v1/market/index/custom
+	customIndex.Get("/:id", marketIndexHandler.GetIndex)                     // GET /api/v1/market/index/custom/:id
+	customIndex.Get("/:id/history", marketIndexHandler.GetHistory)           // GET /api/v1/market/index/custom/:id/history
+	customIndex.Get("/:id/constituents", marketIndexHandler.GetConstituents) // GET /api/v1/market/index/custom/:id/constituents
+	customIndex.Delete("/:id", marketIndexHandler.DeleteCustomIndex)         // DELETE /api/v1/market/index/custom/:id
+	marketIndex.Get("/:id", marketIndexHandler.GetIndex)                     // GET /api/v1/market/index/:id
+	marketIndex.Get("/:id/history", marketIndexHandler.GetHistory)           // GET /api/v1/market/index/:id/history?from=...&to=...
+	marketIndex.Get("/:id/constituents", marketIndexHandler.GetConstituents) // GET /api/v1/market/index/:id/constituents
+
+	// Screener routes (saved screens are scoped to the X-User-ID header)
+	screenerGroup := api.Group("/screener")
+	screenerGroup.Get("/", screenerHandler.Screen)           // GET /api/v1/screener?q=margin > 50k AND volume24h > 500
+	screenerGroup.Get("/fields", screenerHandler.ListFields) // GET /api/v1/screener/fields
+	screens := screenerGroup.Group("/screens", middleware.RequireUserID())
+	screens.Get("/", screenerHandler.ListScreens)          // GET /api/v1/screener/screens
+	screens.Post("/", screenerHandler.CreateScreen)        // POST /api/v1/screener/screens
+	screens.Get("/:id", screenerHandler.GetScreen)         // GET /api/v1/screener/screens/:id
+	screens.Put("/:id", screenerHandler.UpdateScreen)      // PUT /api/v1/screener/screens/:id
+	screens.Delete("/:id", screenerHandler.DeleteScreen)   // DELETE /api/v1/screener/screens/:id
+	screens.Get("/:id/results", screenerHandler.RunScreen) // GET /api/v1/screener/screens/:id/results?limit=50
+
+	// Admin routes (require the X-Admin-Key header; disabled without ADMIN_API_KEY)
+	admin := api.Group("/admin", middleware.RequireAdminKey(cfg.AdminAPIKey))
+	admin.Put("/arbitrage/sets/:setItemId", arbitrageHandler.SaveSet)      // PUT /api/v1/admin/arbitrage/sets/:setItemId
+	admin.Delete("/arbitrage/sets/:setItemId", arbitrageHandler.DeleteSet) // DELETE /api/v1/admin/arbitrage/sets/:setItemId
+	admin.Put("/recipes/:id", recipeHandler.SaveRecipe)                    // PUT /api/v1/admin/recipes/:id
+	admin.Delete("/recipes/:id", recipeHandler.DeleteRecipe)               // DELETE /api/v1/admin/recipes/:id
+	admin.Put("/items/:id/tags/:tag", itemTagHandler.AddItemTag)           // PUT /api/v1/admin/items/:id/tags/:tag
+	admin.Delete("/items/:id/tags/:tag", itemTagHandler.RemoveItemTag)     // DELETE /api/v1/admin/items/:id/tags/:tag
+	admin.Post("/tags/classify", itemTagHandler.ClassifyItems)             // POST /api/v1/admin/tags/classify
+	admin.Get("/aliases", itemAliasHandler.ListAliases)                    // GET /api/v1/admin/aliases?status=pending
+	admin.Post("/items/:id/aliases", itemAliasHandler.AddAlias)            // POST /api/v1/admin/items/:id/aliases
+	admin.Put("/aliases/:id", itemAliasHandler.ReviewAlias)                // PUT /api/v1/admin/aliases/:id
+	admin.Delete("/aliases/:id", itemAliasHandler.DeleteAlias)             // DELETE /api/v1/admin/aliases/:id
 
 	// Watchlist routes
 	watchlists := api.Group("/watchlists")
@@ -178,6 +381,16 @@ func main() {
 
 	// Initialize and start scheduler (pass SSE hub if enabled)
 	sched := scheduler.NewScheduler(priceService, itemService, watchlistService, sseHub, logger)
+	sched.SetAnalyticsService(analyticsService)
+	sched.SetPaperTradingService(paperService)
+	sched.SetBuyLimitService(buyLimitService)
+	sched.SetBankSnapshotService(bankSnapshotService)
+	sched.SetArbitrageService(arbitrageService)
+	sched.SetMarketIndexService(marketIndexService)
+	sched.SetItemTagService(itemTagService)
+	sched.SetAutocompleteService(autocompleteService)
+	sched.SetScreenerService(screenerService)
+	sched.SetMarketTableService(marketTableService)
 	if err := sched.Start(); err != nil {
 		logger.Fatalf("Failed to start scheduler: %v", err)
 	}
@@ -209,6 +422,13 @@ func main() {
 		logger.Errorf("Server shutdown error: %v", err)
 	}
 
+	// Let running backtests record that they were interrupted
+	backtestCtx, cancelBacktests := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := backtestService.Shutdown(backtestCtx); err != nil {
+		logger.Warnf("Backtests did not stop in time: %v", err)
+	}
+	cancelBacktests()
+
 	// Close database connection
 	sqlDB, err := dbClient.DB()
 	if err == nil {
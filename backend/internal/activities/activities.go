@@ -0,0 +1,150 @@
+// Package activities values PvM activities by the expected worth of their
+// drop tables.
+package activities
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// Activity definition limits.
+const (
+	maxActivityNameLength = 100
+	maxDrops              = 200
+	maxDropQuantity       = 1_000_000
+	maxKillsPerHour       = 10_000
+)
+
+//go:embed activities.yaml
+var defaultActivitiesYAML []byte
+
+var activityIDPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Load returns the shipped activities with those defined in dir added. A
+// file's activity replaces a shipped one with the same ID. Files must end in
+// .yaml, .yml or .json and hold a list of activities; other files are
+// ignored. An empty dir loads only the shipped activities.
+func Load(dir string) ([]models.Activity, error) {
+	defaults, err := Parse(defaultActivitiesYAML, ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("embedded activities: %w", err)
+	}
+	byID := make(map[string]models.Activity, len(defaults))
+	for _, a := range defaults {
+		a.Source = models.ActivitySourceBuiltin
+		byID[a.ID] = a
+	}
+
+	if dir != "" {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("read activities dir: %w", err)
+		}
+		seen := make(map[string]string)
+		for _, entry := range entries {
+			ext := strings.ToLower(filepath.Ext(entry.Name()))
+			if entry.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("read %s: %w", path, err)
+			}
+			parsed, err := Parse(data, ext)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			for _, a := range parsed {
+				if other, dup := seen[a.ID]; dup {
+					return nil, fmt.Errorf("%s: activity %q is also defined in %s", path, a.ID, other)
+				}
+				seen[a.ID] = path
+				a.Source = entry.Name()
+				byID[a.ID] = a
+			}
+		}
+	}
+
+	list := make([]models.Activity, 0, len(byID))
+	for _, a := range byID {
+		list = append(list, a)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list, nil
+}
+
+// Parse reads a list of activities from YAML or JSON, chosen by the file
+// extension ext, and validates each. Errors wrap models.ErrInvalidActivity.
+func Parse(data []byte, ext string) ([]models.Activity, error) {
+	var list []models.Activity
+	var err error
+	if strings.EqualFold(ext, ".json") {
+		err = json.Unmarshal(data, &list)
+	} else {
+		err = yaml.Unmarshal(data, &list)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidActivity, err)
+	}
+
+	ids := make(map[string]struct{}, len(list))
+	for i := range list {
+		if err := validateActivity(&list[i]); err != nil {
+			return nil, fmt.Errorf("activity %d: %w", i+1, err)
+		}
+		if _, dup := ids[list[i].ID]; dup {
+			return nil, invalidActivity("activity %q is listed twice", list[i].ID)
+		}
+		ids[list[i].ID] = struct{}{}
+	}
+	return list, nil
+}
+
+// validateActivity checks a definition, trimming its name and defaulting
+// drop quantities to 1.
+func validateActivity(a *models.Activity) error {
+	a.Name = strings.TrimSpace(a.Name)
+	switch {
+	case !activityIDPattern.MatchString(a.ID):
+		return invalidActivity("id must be lowercase letters, digits and single dashes")
+	case a.Name == "" || len(a.Name) > maxActivityNameLength:
+		return invalidActivity("%s: name must be 1-%d characters", a.ID, maxActivityNameLength)
+	case a.KillsPerHour <= 0 || a.KillsPerHour > maxKillsPerHour:
+		return invalidActivity("%s: killsPerHour must be above 0 and at most %d", a.ID, maxKillsPerHour)
+	case len(a.Drops) == 0 || len(a.Drops) > maxDrops:
+		return invalidActivity("%s: an activity must have 1-%d drops", a.ID, maxDrops)
+	}
+
+	for i := range a.Drops {
+		d := &a.Drops[i]
+		if d.MinQuantity == 0 && d.MaxQuantity == 0 {
+			d.MinQuantity, d.MaxQuantity = 1, 1
+		} else if d.MaxQuantity == 0 {
+			d.MaxQuantity = d.MinQuantity
+		}
+		switch {
+		case d.ItemID <= 0:
+			return invalidActivity("%s: drop %d: itemId is required", a.ID, i+1)
+		case d.Rate <= 0 || d.Rate > 1:
+			return invalidActivity("%s: drop %d: rate must be above 0 and at most 1", a.ID, i+1)
+		case d.MinQuantity < 1 || d.MaxQuantity > maxDropQuantity || d.MinQuantity > d.MaxQuantity:
+			return invalidActivity("%s: drop %d: quantity must be a range within 1-%d", a.ID, i+1, maxDropQuantity)
+		}
+	}
+	return nil
+}
+
+func invalidActivity(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", models.ErrInvalidActivity, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,113 @@
+package activities
+
+import (
+	"time"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
+	"github.com/guavi/osrs-ge-tracker/internal/valuation"
+)
+
+// ItemIDs returns the distinct items an activity drops.
+func ItemIDs(a models.Activity) []int {
+	seen := make(map[int]struct{}, len(a.Drops))
+	ids := make([]int, 0, len(a.Drops))
+	for _, d := range a.Drops {
+		if _, dup := seen[d.ItemID]; !dup {
+			seen[d.ItemID] = struct{}{}
+			ids = append(ids, d.ItemID)
+		}
+	}
+	return ids
+}
+
+// Value computes an activity's expected loot per kill and per hour. Drops
+// sell at the mode's sell price less GE tax; items without a price fall back
+// to their high alchemy value, and currencies count at face value. items
+// supplies names and alchemy values; prices are keyed by item ID.
+func Value(
+	a models.Activity,
+	items map[int]models.Item,
+	prices map[int]models.CurrentPrice,
+	mode models.PricingMode,
+) models.ActivityValue {
+	v := models.ActivityValue{
+		ID:           a.ID,
+		Name:         a.Name,
+		Pricing:      mode,
+		KillsPerHour: a.KillsPerHour,
+		Drops:        make([]models.ActivityDropValue, 0, len(a.Drops)),
+		Unpriced:     make([]int, 0),
+	}
+	for _, d := range a.Drops {
+		dv := models.ActivityDropValue{
+			ItemID:           d.ItemID,
+			Name:             items[d.ItemID].Name,
+			MinQuantity:      d.MinQuantity,
+			MaxQuantity:      d.MaxQuantity,
+			Rate:             float64(d.Rate),
+			ExpectedQuantity: expectedQuantity(d),
+		}
+		dv.UnitPrice, dv.PriceSource = unitPrice(d.ItemID, mode.SellPrice(prices[d.ItemID]), items[d.ItemID])
+		if dv.UnitPrice == nil {
+			v.Unpriced = append(v.Unpriced, d.ItemID)
+		} else {
+			dv.ExpectedValue = dv.ExpectedQuantity * float64(*dv.UnitPrice)
+		}
+		v.ValuePerKill += dv.ExpectedValue
+		v.Drops = append(v.Drops, dv)
+	}
+	v.ValuePerHour = v.ValuePerKill * a.KillsPerHour
+	return v
+}
+
+// History computes an activity's expected value on each day from the daily
+// price rollups, keyed by item ID then day. The sell side of the rollup is
+// chosen by mode as for current prices, with the same fallbacks.
+func History(
+	a models.Activity,
+	items map[int]models.Item,
+	daily map[int]map[time.Time]models.PriceTimeseriesDaily,
+	days []time.Time,
+	mode models.PricingMode,
+) []models.ActivityValuePoint {
+	points := make([]models.ActivityValuePoint, 0, len(days))
+	for _, day := range days {
+		p := models.ActivityValuePoint{Day: day}
+		for _, d := range a.Drops {
+			rollup := daily[d.ItemID][day]
+			price := models.CurrentPrice{HighPrice: rollup.AvgHighPrice, LowPrice: rollup.AvgLowPrice}
+			unit, _ := unitPrice(d.ItemID, mode.SellPrice(price), items[d.ItemID])
+			if unit == nil {
+				p.Unpriced++
+				continue
+			}
+			p.ValuePerKill += expectedQuantity(d) * float64(*unit)
+		}
+		p.ValuePerHour = p.ValuePerKill * a.KillsPerHour
+		points = append(points, p)
+	}
+	return points
+}
+
+// expectedQuantity is the mean stack dropped per kill.
+func expectedQuantity(d models.ActivityDrop) float64 {
+	return float64(d.Rate) * float64(d.MinQuantity+d.MaxQuantity) / 2
+}
+
+// unitPrice values one unit of a drop sold at sell: after GE tax when the
+// item trades, otherwise at its high alchemy value or face value.
+func unitPrice(itemID int, sell *int64, item models.Item) (*int64, string) {
+	if value, ok := valuation.CurrencyValue(itemID); ok {
+		return &value, models.DropPriceCoins
+	}
+	if sell != nil {
+		net := *sell - utils.GETax(itemID, *sell)
+		return &net, models.DropPriceGE
+	}
+	if item.HighAlch != nil {
+		alch := int64(*item.HighAlch)
+		return &alch, models.DropPriceAlch
+	}
+	return nil, ""
+}
@@ -0,0 +1,81 @@
+// Package aliases holds the item nicknames shipped with the application and
+// the normalization shared by aliases, slugs and resolved names.
+package aliases
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+//go:embed seed.json
+var seedJSON []byte
+
+var (
+	aliasPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9 '().+-]*$`)
+	slugStrip    = regexp.MustCompile(`['’]`)
+	slugSplit    = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Seed returns the shipped aliases, approved and normalized.
+func Seed() ([]models.ItemAlias, error) {
+	var file []struct {
+		Aliases []string `json:"aliases"`
+		ItemID  int      `json:"itemId"`
+	}
+	if err := json.Unmarshal(seedJSON, &file); err != nil {
+		return nil, fmt.Errorf("parse embedded item aliases: %w", err)
+	}
+
+	seen := make(map[string]int)
+	var aliases []models.ItemAlias
+	for _, entry := range file {
+		for _, raw := range entry.Aliases {
+			alias, err := Normalize(raw)
+			if err != nil {
+				return nil, fmt.Errorf("embedded item aliases: item %d: %w", entry.ItemID, err)
+			}
+			if other, dup := seen[alias]; dup {
+				return nil, fmt.Errorf("embedded item aliases: %q is used by items %d and %d", alias, other, entry.ItemID)
+			}
+			seen[alias] = entry.ItemID
+			aliases = append(aliases, models.ItemAlias{
+				Alias:  alias,
+				ItemID: entry.ItemID,
+				Status: models.ItemAliasStatusApproved,
+				Source: models.ItemAliasSourceSeed,
+			})
+		}
+	}
+	return aliases, nil
+}
+
+// Fold lowercases name, trims it and collapses runs of whitespace, the form
+// aliases are stored and looked up in.
+func Fold(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// Normalize folds an alias and checks that it is 1-48 characters of
+// lowercase letters, digits, spaces and ' ( ) . + -, starting with a letter
+// or digit. Errors wrap models.ErrInvalidItemAlias.
+func Normalize(alias string) (string, error) {
+	alias = Fold(alias)
+	if len(alias) > models.MaxItemAliasLength || !aliasPattern.MatchString(alias) {
+		return "", fmt.Errorf("%w: alias must be 1-%d letters, digits, spaces and ' ( ) . + -",
+			models.ErrInvalidItemAlias, models.MaxItemAliasLength)
+	}
+	return alias, nil
+}
+
+// Slug returns the URL slug of an item name: lowercase words joined by
+// dashes, with apostrophes dropped ("Zulrah's scales" is zulrahs-scales).
+// Keep in step with the SQL in repository.itemSlugSQL.
+func Slug(name string) string {
+	slug := slugStrip.ReplaceAllString(strings.ToLower(name), "")
+	return strings.Trim(slugSplit.ReplaceAllString(slug, "-"), "-")
+}
@@ -0,0 +1,170 @@
+package arbitrage
+
+import (
+	"sort"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
+)
+
+// EvaluateDoseGroups prices every decant between the dose variants of each
+// potion, best profit per dose first, and returns the names of potions with
+// no priced decant. items supplies names and buy limits; prices are keyed
+// by item ID.
+func EvaluateDoseGroups(
+	groups []models.DoseGroup,
+	items map[int]models.Item,
+	prices map[int]models.CurrentPrice,
+	mode models.PricingMode,
+) ([]models.DoseArbitrage, []string) {
+	results := make([]models.DoseArbitrage, 0, len(groups))
+	unpriced := make([]string, 0)
+	for _, g := range groups {
+		a := evaluateDoseGroup(g, items, prices, mode)
+		if a.Best == nil {
+			unpriced = append(unpriced, g.Name)
+			continue
+		}
+		results = append(results, a)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i].Best.ProfitPerDose, results[j].Best.ProfitPerDose
+		if a != b {
+			return a > b
+		}
+		return results[i].Name < results[j].Name
+	})
+	sort.Strings(unpriced)
+	return results, unpriced
+}
+
+func evaluateDoseGroup(
+	g models.DoseGroup,
+	items map[int]models.Item,
+	prices map[int]models.CurrentPrice,
+	mode models.PricingMode,
+) models.DoseArbitrage {
+	a := models.DoseArbitrage{
+		Name:     g.Name,
+		Variants: make([]models.DoseVariantPrice, 0, len(g.Variants)),
+		Trades:   make([]models.DecantTrade, 0),
+	}
+	for _, v := range g.Variants {
+		p := prices[v.ItemID]
+		vp := models.DoseVariantPrice{
+			ItemID:    v.ItemID,
+			Name:      items[v.ItemID].Name,
+			Doses:     v.Doses,
+			HighPrice: p.HighPrice,
+			LowPrice:  p.LowPrice,
+			BuyLimit:  items[v.ItemID].BuyLimit,
+		}
+		if buy := mode.BuyPrice(p); buy != nil {
+			perDose := float64(*buy) / float64(v.Doses)
+			vp.BuyPerDose = &perDose
+		}
+		if sell := mode.SellPrice(p); sell != nil {
+			perDose := float64(*sell-utils.GETax(v.ItemID, *sell)) / float64(v.Doses)
+			vp.SellPerDose = &perDose
+		}
+		a.Variants = append(a.Variants, vp)
+	}
+
+	for _, from := range g.Variants {
+		for _, to := range g.Variants {
+			if from.ItemID == to.ItemID {
+				continue
+			}
+			if t := decantTrade(from, to, items, prices, mode); t != nil {
+				a.Trades = append(a.Trades, *t)
+			}
+		}
+	}
+	sort.SliceStable(a.Trades, func(i, j int) bool {
+		return a.Trades[i].ProfitPerDose > a.Trades[j].ProfitPerDose
+	})
+	if len(a.Trades) > 0 {
+		a.Best = &a.Trades[0]
+	}
+	return a
+}
+
+// decantTrade buys the from variant, decants it into the to variant and
+// sells that, in batches of the fewest doses both divide evenly.
+func decantTrade(
+	from, to models.DoseVariant,
+	items map[int]models.Item,
+	prices map[int]models.CurrentPrice,
+	mode models.PricingMode,
+) *models.DecantTrade {
+	buy := mode.BuyPrice(prices[from.ItemID])
+	sell := mode.SellPrice(prices[to.ItemID])
+	if buy == nil || sell == nil {
+		return nil
+	}
+
+	doses := int64(lcm(from.Doses, to.Doses))
+	bought := doses / int64(from.Doses)
+	sold := doses / int64(to.Doses)
+	unitTax := utils.GETax(to.ItemID, *sell)
+
+	t := &models.DecantTrade{
+		FromItemID: from.ItemID,
+		ToItemID:   to.ItemID,
+		FromDoses:  from.Doses,
+		ToDoses:    to.Doses,
+		Bought:     bought,
+		Sold:       sold,
+		Cost:       *buy * bought,
+		Revenue:    (*sell - unitTax) * sold,
+		Tax:        unitTax * sold,
+	}
+	t.Profit = t.Revenue - t.Cost
+	t.ProfitPerDose = float64(t.Profit) / float64(doses)
+	if t.Cost > 0 {
+		t.ROI = float64(t.Profit) / float64(t.Cost) * 100
+	}
+	if limit := items[from.ItemID].BuyLimit; limit != nil {
+		batches := int64(*limit) / bought
+		perWindow := t.Profit * batches
+		t.MaxBatches = &batches
+		t.ProfitPerWindow = &perWindow
+	}
+	return t
+}
+
+// SortDoseGroups orders potions by their best decant, highest first, using
+// the same orders as SortSets; SortByProfit ranks by profit per dose.
+// Potions without a value for the field sort last.
+func SortDoseGroups(groups []models.DoseArbitrage, by string) {
+	key := func(a models.DoseArbitrage) (float64, bool) {
+		switch by {
+		case SortByROI:
+			return a.Best.ROI, true
+		case SortByProfitPerWindow:
+			if a.Best.ProfitPerWindow == nil {
+				return 0, false
+			}
+			return float64(*a.Best.ProfitPerWindow), true
+		default:
+			return a.Best.ProfitPerDose, true
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, aok := key(groups[i])
+		b, bok := key(groups[j])
+		if aok != bok {
+			return aok
+		}
+		return a > b
+	})
+}
+
+func lcm(a, b int) int {
+	x, y := a, b
+	for y != 0 {
+		x, y = y, x%y
+	}
+	return a / x * b
+}
@@ -0,0 +1,60 @@
+package arbitrage
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// maxPotionDoses is the most doses a tradeable potion holds.
+const maxPotionDoses = 4
+
+//go:embed doses.json
+var defaultDoseGroupsJSON []byte
+
+// DefaultDoseGroups returns the potion dose groups shipped with the
+// application.
+func DefaultDoseGroups() ([]models.DoseGroup, error) {
+	var groups []models.DoseGroup
+	if err := json.Unmarshal(defaultDoseGroupsJSON, &groups); err != nil {
+		return nil, fmt.Errorf("parse embedded dose groups: %w", err)
+	}
+	for _, g := range groups {
+		if err := validateDoseGroup(g); err != nil {
+			return nil, fmt.Errorf("embedded dose group %q: %w", g.Name, err)
+		}
+	}
+	return groups, nil
+}
+
+func validateDoseGroup(g models.DoseGroup) error {
+	if g.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(g.Variants) < 2 {
+		return errors.New("a dose group needs at least 2 variants")
+	}
+
+	items := make(map[int]struct{}, len(g.Variants))
+	doses := make(map[int]struct{}, len(g.Variants))
+	for _, v := range g.Variants {
+		if v.ItemID <= 0 {
+			return errors.New("itemId is required")
+		}
+		if v.Doses < 1 || v.Doses > maxPotionDoses {
+			return fmt.Errorf("item %d: doses must be between 1 and %d", v.ItemID, maxPotionDoses)
+		}
+		if _, dup := items[v.ItemID]; dup {
+			return fmt.Errorf("item %d is listed twice", v.ItemID)
+		}
+		if _, dup := doses[v.Doses]; dup {
+			return fmt.Errorf("%d-dose variant is listed twice", v.Doses)
+		}
+		items[v.ItemID] = struct{}{}
+		doses[v.Doses] = struct{}{}
+	}
+	return nil
+}
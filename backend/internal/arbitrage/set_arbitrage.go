@@ -0,0 +1,204 @@
+package arbitrage
+
+import (
+	"sort"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
+)
+
+// EvaluateSets compares each set with its pieces at current prices, most
+// profitable first, and returns the IDs of sets with no priced direction.
+// items supplies names and buy limits; prices are keyed by item ID.
+func EvaluateSets(
+	sets []models.ItemSet,
+	items map[int]models.Item,
+	prices map[int]models.CurrentPrice,
+	mode models.PricingMode,
+) ([]models.SetArbitrage, []int) {
+	results := make([]models.SetArbitrage, 0, len(sets))
+	unpriced := make([]int, 0)
+	for _, set := range sets {
+		a := evaluateSet(set, items, prices, mode)
+		if a.Combine == nil && a.Split == nil {
+			unpriced = append(unpriced, set.SetItemID)
+			continue
+		}
+		results = append(results, a)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i].BestTrade().Profit, results[j].BestTrade().Profit
+		if a != b {
+			return a > b
+		}
+		return results[i].SetItemID < results[j].SetItemID
+	})
+	sort.Ints(unpriced)
+	return results, unpriced
+}
+
+func evaluateSet(
+	set models.ItemSet,
+	items map[int]models.Item,
+	prices map[int]models.CurrentPrice,
+	mode models.PricingMode,
+) models.SetArbitrage {
+	setPrice := prices[set.SetItemID]
+	a := models.SetArbitrage{
+		SetItemID:    set.SetItemID,
+		Name:         set.Name,
+		SetHighPrice: setPrice.HighPrice,
+		SetLowPrice:  setPrice.LowPrice,
+		SetBuyLimit:  items[set.SetItemID].BuyLimit,
+		Components:   make([]models.SetArbitrageComponent, 0, len(set.Components)),
+	}
+	for _, c := range set.Components {
+		p := prices[c.ItemID]
+		a.Components = append(a.Components, models.SetArbitrageComponent{
+			ItemID:    c.ItemID,
+			Name:      items[c.ItemID].Name,
+			Quantity:  c.Quantity,
+			HighPrice: p.HighPrice,
+			LowPrice:  p.LowPrice,
+			BuyLimit:  items[c.ItemID].BuyLimit,
+		})
+	}
+
+	a.Combine = combineTrade(set, items, prices, mode)
+	a.Split = splitTrade(set, items, prices, mode)
+	switch {
+	case a.Combine == nil:
+		a.Best = models.SetDirectionSplit
+	case a.Split == nil || a.Combine.Profit >= a.Split.Profit:
+		a.Best = models.SetDirectionCombine
+	default:
+		a.Best = models.SetDirectionSplit
+	}
+	return a
+}
+
+// combineTrade buys the pieces, has the clerk combine them and sells the set.
+// The pieces' buy limits cap the sets per window.
+func combineTrade(
+	set models.ItemSet,
+	items map[int]models.Item,
+	prices map[int]models.CurrentPrice,
+	mode models.PricingMode,
+) *models.SetArbitrageTrade {
+	sell := mode.SellPrice(prices[set.SetItemID])
+	if sell == nil {
+		return nil
+	}
+
+	var cost, sets int64
+	limited := true
+	for i, c := range set.Components {
+		buy := mode.BuyPrice(prices[c.ItemID])
+		if buy == nil {
+			return nil
+		}
+		cost += *buy * int64(c.Quantity)
+
+		// Any piece without a known limit leaves the cap unknown.
+		limit := items[c.ItemID].BuyLimit
+		if limit == nil {
+			limited = false
+			continue
+		}
+		if pieceSets := int64(*limit / c.Quantity); i == 0 || pieceSets < sets {
+			sets = pieceSets
+		}
+	}
+
+	var maxSets *int64
+	if limited {
+		maxSets = &sets
+	}
+	tax := utils.GETax(set.SetItemID, *sell)
+	return newTrade(cost, *sell-tax, tax, maxSets)
+}
+
+// splitTrade buys the set, has the clerk split it and sells the pieces. The
+// set's buy limit caps the sets per window.
+func splitTrade(
+	set models.ItemSet,
+	items map[int]models.Item,
+	prices map[int]models.CurrentPrice,
+	mode models.PricingMode,
+) *models.SetArbitrageTrade {
+	buy := mode.BuyPrice(prices[set.SetItemID])
+	if buy == nil {
+		return nil
+	}
+
+	var revenue, tax int64
+	for _, c := range set.Components {
+		sell := mode.SellPrice(prices[c.ItemID])
+		if sell == nil {
+			return nil
+		}
+		pieceTax := utils.GETax(c.ItemID, *sell)
+		revenue += (*sell - pieceTax) * int64(c.Quantity)
+		tax += pieceTax * int64(c.Quantity)
+	}
+
+	var maxSets *int64
+	if limit := items[set.SetItemID].BuyLimit; limit != nil {
+		v := int64(*limit)
+		maxSets = &v
+	}
+	return newTrade(*buy, revenue, tax, maxSets)
+}
+
+func newTrade(cost, revenue, tax int64, maxSets *int64) *models.SetArbitrageTrade {
+	t := &models.SetArbitrageTrade{
+		Cost:    cost,
+		Revenue: revenue,
+		Tax:     tax,
+		Profit:  revenue - cost,
+		MaxSets: maxSets,
+	}
+	if cost > 0 {
+		t.ROI = float64(t.Profit) / float64(cost) * 100
+	}
+	if maxSets != nil {
+		perWindow := t.Profit * *maxSets
+		t.ProfitPerWindow = &perWindow
+	}
+	return t
+}
+
+// Set arbitrage sort orders. Each ranks by the set's best direction.
+const (
+	SortByProfit          = "profit"
+	SortByROI             = "roi"
+	SortByProfitPerWindow = "profit_per_window"
+)
+
+// SortSets orders sets by their best direction, highest first. Sets without
+// a value for the field sort last.
+func SortSets(sets []models.SetArbitrage, by string) {
+	key := func(a models.SetArbitrage) (float64, bool) {
+		t := a.BestTrade()
+		switch by {
+		case SortByROI:
+			return t.ROI, true
+		case SortByProfitPerWindow:
+			if t.ProfitPerWindow == nil {
+				return 0, false
+			}
+			return float64(*t.ProfitPerWindow), true
+		default:
+			return float64(t.Profit), true
+		}
+	}
+	sort.SliceStable(sets, func(i, j int) bool {
+		a, aok := key(sets[i])
+		b, bok := key(sets[j])
+		if aok != bok {
+			return aok
+		}
+		return a > b
+	})
+}
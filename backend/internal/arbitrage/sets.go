@@ -0,0 +1,113 @@
+// Package arbitrage finds price gaps between items that can be converted
+// into one another outside the Grand Exchange.
+package arbitrage
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+//go:embed sets.json
+var defaultSetsJSON []byte
+
+// DefaultSets returns the item sets shipped with the application.
+func DefaultSets() ([]models.ItemSet, error) {
+	var sets []models.ItemSet
+	if err := json.Unmarshal(defaultSetsJSON, &sets); err != nil {
+		return nil, fmt.Errorf("parse embedded item sets: %w", err)
+	}
+	for i := range sets {
+		if err := ValidateSet(&sets[i]); err != nil {
+			return nil, fmt.Errorf("embedded item set %d: %w", sets[i].SetItemID, err)
+		}
+		sets[i].Source = models.ItemSetSourceBuiltin
+	}
+	return sets, nil
+}
+
+// ValidateSet checks a set definition, trimming its name and defaulting
+// component quantities to 1. Errors wrap models.ErrInvalidItemSet.
+func ValidateSet(set *models.ItemSet) error {
+	set.Name = strings.TrimSpace(set.Name)
+	switch {
+	case set.SetItemID <= 0:
+		return invalidSet("setItemId is required")
+	case set.Name == "" || utf8.RuneCountInString(set.Name) > models.MaxItemSetNameLength:
+		return invalidSet("name must be 1-%d characters", models.MaxItemSetNameLength)
+	case len(set.Components) < 2 || len(set.Components) > models.MaxItemSetComponents:
+		return invalidSet("a set must have 2-%d components", models.MaxItemSetComponents)
+	}
+
+	seen := make(map[int]struct{}, len(set.Components))
+	for i := range set.Components {
+		c := &set.Components[i]
+		if c.Quantity == 0 {
+			c.Quantity = 1
+		}
+		switch {
+		case c.ItemID <= 0:
+			return invalidSet("component %d: itemId is required", i+1)
+		case c.ItemID == set.SetItemID:
+			return invalidSet("component %d: a set cannot contain itself", i+1)
+		case c.Quantity < 0 || c.Quantity > models.MaxItemSetComponentQuantity:
+			return invalidSet("component %d: quantity must be between 1 and %d", i+1, models.MaxItemSetComponentQuantity)
+		}
+		if _, dup := seen[c.ItemID]; dup {
+			return invalidSet("component %d: item %d is listed twice", i+1, c.ItemID)
+		}
+		seen[c.ItemID] = struct{}{}
+	}
+	return nil
+}
+
+// MergeSets applies admin overrides to the shipped sets: an override replaces
+// the set with the same ID or adds a new one, and a removed override hides
+// it. Invalid overrides are skipped and reported in the returned error. The
+// result is ordered by name.
+func MergeSets(defaults []models.ItemSet, overrides []models.ItemSetOverride) ([]models.ItemSet, error) {
+	byID := make(map[int]models.ItemSet, len(defaults)+len(overrides))
+	for _, set := range defaults {
+		byID[set.SetItemID] = set
+	}
+
+	var errs []error
+	for _, o := range overrides {
+		if o.Removed {
+			delete(byID, o.SetItemID)
+			continue
+		}
+		set := models.ItemSet{SetItemID: o.SetItemID, Name: o.Name, Source: models.ItemSetSourceCustom}
+		if err := json.Unmarshal(o.Components, &set.Components); err != nil {
+			errs = append(errs, fmt.Errorf("item set override %d: %w", o.SetItemID, err))
+			continue
+		}
+		if err := ValidateSet(&set); err != nil {
+			errs = append(errs, fmt.Errorf("item set override %d: %w", o.SetItemID, err))
+			continue
+		}
+		byID[o.SetItemID] = set
+	}
+
+	sets := make([]models.ItemSet, 0, len(byID))
+	for _, set := range byID {
+		sets = append(sets, set)
+	}
+	sort.Slice(sets, func(i, j int) bool {
+		if sets[i].Name != sets[j].Name {
+			return sets[i].Name < sets[j].Name
+		}
+		return sets[i].SetItemID < sets[j].SetItemID
+	})
+	return sets, errors.Join(errs...)
+}
+
+func invalidSet(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", models.ErrInvalidItemSet, fmt.Sprintf(format, args...))
+}
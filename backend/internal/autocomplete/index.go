@@ -0,0 +1,207 @@
+// Package autocomplete serves item name completions from an in-memory trie
+// of item names and aliases.
+package autocomplete
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/guavi/osrs-ge-tracker/internal/aliases"
+)
+
+// MaxResults bounds the completions one lookup returns. Every trie node keeps
+// its best MaxResults entries, so lookups never walk a subtree.
+const MaxResults = 10
+
+// Entry is an item that can be completed.
+type Entry struct {
+	Name       string
+	IconURL    string
+	Aliases    []string
+	Popularity int64
+	ItemID     int
+}
+
+// Index is an immutable trie over every word suffix of the entries' names
+// ("abyssal whip" and "whip") and over their aliases. It is safe for
+// concurrent use.
+type Index struct {
+	root    *node
+	entries []Entry
+}
+
+type edge struct {
+	child *node
+	b     byte
+}
+
+type node struct {
+	edges []edge
+	// exact holds the entries with a key ending at this node, best first.
+	exact []int32
+	// top holds the best MaxResults entries in this node's subtree.
+	top []int32
+}
+
+// NewIndex builds an index. Entries rank by popularity, then by shorter and
+// alphabetically earlier name.
+func NewIndex(entries []Entry) *Index {
+	sorted := make([]Entry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Popularity != b.Popularity {
+			return a.Popularity > b.Popularity
+		}
+		if len(a.Name) != len(b.Name) {
+			return len(a.Name) < len(b.Name)
+		}
+		return a.Name < b.Name
+	})
+
+	idx := &Index{root: &node{}, entries: sorted}
+	// Entries are inserted best first, so exact lists stay sorted.
+	for i, entry := range sorted {
+		for _, key := range keys(entry) {
+			idx.insert(key, int32(i))
+		}
+	}
+	fillTop(idx.root)
+	return idx
+}
+
+// Len returns the number of entries.
+func (idx *Index) Len() int {
+	return len(idx.entries)
+}
+
+// Complete returns up to limit entries with a name word or alias starting
+// with query. Entries whose alias equals query, or whose name ends with it
+// from a word start, come first; the rest follow by rank.
+func (idx *Index) Complete(query string, limit int) []Entry {
+	query = Normalize(query)
+	if query == "" || limit <= 0 {
+		return nil
+	}
+	if limit > MaxResults {
+		limit = MaxResults
+	}
+
+	n := idx.root
+	for i := 0; i < len(query) && n != nil; i++ {
+		n = n.child(query[i])
+	}
+	if n == nil {
+		return nil
+	}
+
+	results := make([]Entry, 0, limit)
+	seen := make(map[int32]struct{}, limit)
+	for _, list := range [][]int32{n.exact, n.top} {
+		for _, i := range list {
+			if len(results) == limit {
+				return results
+			}
+			if _, dup := seen[i]; dup {
+				continue
+			}
+			seen[i] = struct{}{}
+			results = append(results, idx.entries[i])
+		}
+	}
+	return results
+}
+
+// Normalize folds s the way keys are stored: lowercase, single spaces and
+// no apostrophes, so "zulrahs" finds "Zulrah's scales".
+func Normalize(s string) string {
+	return aliases.Fold(strings.NewReplacer("'", "", "’", "").Replace(s))
+}
+
+// keys returns the normalized name from each word start, and each alias.
+func keys(entry Entry) []string {
+	name := Normalize(entry.Name)
+	var out []string
+	for i := 0; i < len(name); i++ {
+		if isWordByte(name[i]) && (i == 0 || !isWordByte(name[i-1])) {
+			out = append(out, name[i:])
+		}
+	}
+	for _, alias := range entry.Aliases {
+		if alias = Normalize(alias); alias != "" {
+			out = append(out, alias)
+		}
+	}
+	return out
+}
+
+func isWordByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= '0' && b <= '9' || b >= 0x80
+}
+
+func (idx *Index) insert(key string, entry int32) {
+	n := idx.root
+	for i := 0; i < len(key); i++ {
+		next := n.child(key[i])
+		if next == nil {
+			next = &node{}
+			n.edges = append(n.edges, edge{b: key[i], child: next})
+		}
+		n = next
+	}
+	if len(n.exact) == 0 || n.exact[len(n.exact)-1] != entry {
+		n.exact = append(n.exact, entry)
+	}
+}
+
+func (n *node) child(b byte) *node {
+	for _, e := range n.edges {
+		if e.b == b {
+			return e.child
+		}
+	}
+	return nil
+}
+
+// fillTop computes every node's top list bottom-up by merging its exact
+// list with its children's top lists.
+func fillTop(n *node) {
+	lists := make([][]int32, 0, len(n.edges)+1)
+	if len(n.exact) > 0 {
+		lists = append(lists, n.exact)
+	}
+	for _, e := range n.edges {
+		fillTop(e.child)
+		lists = append(lists, e.child.top)
+	}
+	if len(lists) == 1 && len(lists[0]) <= MaxResults {
+		// A chain node shares its only list instead of copying it.
+		n.top = lists[0]
+		return
+	}
+	n.top = mergeTop(lists)
+}
+
+// mergeTop merges ascending entry lists into the MaxResults smallest
+// distinct entries, ascending.
+func mergeTop(lists [][]int32) []int32 {
+	top := make([]int32, 0, MaxResults)
+	pos := make([]int, len(lists))
+	for len(top) < MaxResults {
+		best := -1
+		for i, list := range lists {
+			if pos[i] < len(list) && (best < 0 || list[pos[i]] < lists[best][pos[best]]) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		v := lists[best][pos[best]]
+		pos[best]++
+		if len(top) == 0 || top[len(top)-1] != v {
+			top = append(top, v)
+		}
+	}
+	return top
+}
@@ -0,0 +1,49 @@
+package backtest
+
+import (
+	"time"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// BuyLimitWindow is how long the Grand Exchange buy limit applies after the
+// first purchase in a window.
+const BuyLimitWindow = models.BuyLimitWindow
+
+// buyLimitTracker enforces per-item buy limits. A window opens at the first
+// purchase and everything bought until it closes counts against the limit.
+type buyLimitTracker struct {
+	limits      map[int]int
+	windowStart map[int]time.Time
+	bought      map[int]int64
+}
+
+func newBuyLimitTracker(limits map[int]int) *buyLimitTracker {
+	return &buyLimitTracker{
+		limits:      limits,
+		windowStart: make(map[int]time.Time),
+		bought:      make(map[int]int64),
+	}
+}
+
+// remaining returns how many units of an item can still be bought at ts, or
+// -1 when the item has no known limit.
+func (t *buyLimitTracker) remaining(itemID int, ts time.Time) int64 {
+	limit, ok := t.limits[itemID]
+	if !ok || limit <= 0 {
+		return -1
+	}
+	if start, open := t.windowStart[itemID]; open && ts.Sub(start) < BuyLimitWindow {
+		return max(int64(limit)-t.bought[itemID], 0)
+	}
+	return int64(limit)
+}
+
+// record counts a purchase of quantity units at ts.
+func (t *buyLimitTracker) record(itemID int, ts time.Time, quantity int64) {
+	if start, open := t.windowStart[itemID]; !open || ts.Sub(start) >= BuyLimitWindow {
+		t.windowStart[itemID] = ts
+		t.bought[itemID] = 0
+	}
+	t.bought[itemID] += quantity
+}
@@ -0,0 +1,297 @@
+package backtest
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
+)
+
+// Exit reasons recorded on trades.
+const (
+	ExitSignal     = "signal"
+	ExitTakeProfit = "take_profit"
+	ExitStopLoss   = "stop_loss"
+	ExitMaxHold    = "max_hold"
+	ExitEndOfData  = "end_of_data"
+)
+
+// position is an open holding. Sales may span several bars when volume is thin.
+type position struct {
+	entryTime time.Time
+	entryBar  int
+	bought    int64
+	quantity  int64
+	cost      int64
+	proceeds  int64
+	tax       int64
+}
+
+type itemState struct {
+	series       *series
+	pos          *position
+	exitReason   string
+	itemID       int
+	next         int
+	lastSell     int64
+	pendingEntry bool
+}
+
+// Run simulates a normalized strategy. bars holds each item's buckets in any
+// order; buyLimits holds the Grand Exchange buy limit per item, and items
+// without one are not limited.
+//
+// Signals are evaluated on bar close and filled on the item's next bar, so a
+// rule never trades on a price it has not seen yet. Each fill is capped by the
+// strategy's share of the bar's volume on that side, and buys also by the
+// remaining 4-hour buy limit. Sales pay GE tax per unit.
+func Run(strategy models.BacktestStrategy, bars map[int][]models.PriceTimeseriesPoint, buyLimits map[int]int) models.BacktestResult {
+	itemIDs := append([]int(nil), strategy.ItemIDs...)
+	sort.Ints(itemIDs)
+
+	states := make([]*itemState, 0, len(itemIDs))
+	timestamps := make(map[time.Time]struct{})
+	for _, itemID := range itemIDs {
+		inWindow := make([]models.PriceTimeseriesPoint, 0, len(bars[itemID]))
+		for _, b := range bars[itemID] {
+			if !b.Timestamp.Before(strategy.Start) && !b.Timestamp.After(strategy.End) {
+				b.Timestamp = b.Timestamp.UTC()
+				inWindow = append(inWindow, b)
+				timestamps[b.Timestamp] = struct{}{}
+			}
+		}
+		sort.SliceStable(inWindow, func(i, j int) bool {
+			return inWindow[i].Timestamp.Before(inWindow[j].Timestamp)
+		})
+		states = append(states, &itemState{itemID: itemID, series: newSeries(inWindow)})
+	}
+
+	clock := make([]time.Time, 0, len(timestamps))
+	for ts := range timestamps {
+		clock = append(clock, ts)
+	}
+	sort.Slice(clock, func(i, j int) bool { return clock[i].Before(clock[j]) })
+
+	sim := &simulation{
+		strategy: strategy,
+		limits:   newBuyLimitTracker(buyLimits),
+		cash:     strategy.InitialCapital,
+		result: models.BacktestResult{
+			Trades: []models.BacktestTrade{},
+			Equity: make([]models.BacktestEquityPoint, 0, len(clock)),
+		},
+	}
+
+	for _, ts := range clock {
+		for _, st := range states {
+			if st.next < len(st.series.bars) && st.series.bars[st.next].Timestamp.Equal(ts) {
+				sim.step(st, st.next)
+				st.next++
+			}
+		}
+		sim.result.Equity = append(sim.result.Equity, models.BacktestEquityPoint{
+			Timestamp: ts,
+			Equity:    sim.equity(states),
+			Cash:      sim.cash,
+		})
+	}
+
+	for _, st := range states {
+		if st.pos != nil {
+			reason := st.exitReason
+			if reason == "" {
+				reason = ExitEndOfData
+			}
+			last := st.series.bars[len(st.series.bars)-1].Timestamp
+			sim.sell(st, st.pos.quantity, st.lastSell)
+			sim.close(st, reason, last)
+		}
+	}
+
+	sim.summarize(len(clock))
+	return sim.result
+}
+
+type simulation struct {
+	limits   *buyLimitTracker
+	result   models.BacktestResult
+	strategy models.BacktestStrategy
+	cash     int64
+}
+
+// step fills pending orders at bar i, then evaluates signals on it.
+func (sim *simulation) step(st *itemState, i int) {
+	bar := st.series.bars[i]
+	s := sim.strategy
+
+	if st.pendingEntry && st.pos == nil && bar.AvgLowPrice != nil && *bar.AvgLowPrice > 0 {
+		price := *bar.AvgLowPrice
+		budget := min(sim.cash, int64(float64(s.InitialCapital)*s.PositionSizePct/100))
+		quantity := min(budget/price, volumeCap(bar.LowPriceVolume, s.MaxVolumeSharePct))
+		if remaining := sim.limits.remaining(st.itemID, bar.Timestamp); remaining >= 0 {
+			quantity = min(quantity, remaining)
+		}
+		if quantity > 0 {
+			sim.cash -= quantity * price
+			sim.limits.record(st.itemID, bar.Timestamp, quantity)
+			st.pos = &position{
+				entryTime: bar.Timestamp,
+				entryBar:  i,
+				bought:    quantity,
+				quantity:  quantity,
+				cost:      quantity * price,
+			}
+		}
+	}
+	st.pendingEntry = false
+
+	if st.exitReason != "" && st.pos != nil && bar.AvgHighPrice != nil && *bar.AvgHighPrice > 0 {
+		quantity := min(st.pos.quantity, volumeCap(bar.HighPriceVolume, s.MaxVolumeSharePct))
+		sim.sell(st, quantity, *bar.AvgHighPrice)
+		if st.pos.quantity == 0 {
+			sim.close(st, st.exitReason, bar.Timestamp)
+		}
+	}
+
+	switch {
+	case bar.AvgHighPrice != nil:
+		st.lastSell = *bar.AvgHighPrice
+	case bar.AvgLowPrice != nil:
+		st.lastSell = *bar.AvgLowPrice
+	}
+
+	if st.pos == nil {
+		st.pendingEntry = allHold(st.series, s.Entry, i)
+		return
+	}
+	if st.exitReason == "" {
+		st.exitReason = sim.exitSignal(st, i)
+	}
+}
+
+// exitSignal returns why an open position should be closed after bar i, if at all.
+func (sim *simulation) exitSignal(st *itemState, i int) string {
+	s := sim.strategy
+	if st.lastSell > 0 {
+		net := float64(st.pos.quantity*(st.lastSell-utils.GETax(st.itemID, st.lastSell)) + st.pos.proceeds - st.pos.tax)
+		change := (net/float64(st.pos.cost) - 1) * 100
+		if s.TakeProfitPct != nil && change >= *s.TakeProfitPct {
+			return ExitTakeProfit
+		}
+		if s.StopLossPct != nil && change <= -*s.StopLossPct {
+			return ExitStopLoss
+		}
+	}
+	if s.MaxHoldBars > 0 && i-st.pos.entryBar >= s.MaxHoldBars {
+		return ExitMaxHold
+	}
+	for _, c := range s.Exit {
+		if st.series.holds(c, i) {
+			return ExitSignal
+		}
+	}
+	return ""
+}
+
+func (sim *simulation) sell(st *itemState, quantity, price int64) {
+	if quantity <= 0 {
+		return
+	}
+	tax := quantity * utils.GETax(st.itemID, price)
+	st.pos.quantity -= quantity
+	st.pos.proceeds += quantity * price
+	st.pos.tax += tax
+	sim.cash += quantity*price - tax
+}
+
+func (sim *simulation) close(st *itemState, reason string, ts time.Time) {
+	p := st.pos
+	profit := p.proceeds - p.tax - p.cost
+	sim.result.Trades = append(sim.result.Trades, models.BacktestTrade{
+		ItemID:     st.itemID,
+		EntryTime:  p.entryTime,
+		ExitTime:   ts,
+		ExitReason: reason,
+		Quantity:   p.bought,
+		EntryPrice: float64(p.cost) / float64(p.bought),
+		ExitPrice:  float64(p.proceeds) / float64(p.bought),
+		Tax:        p.tax,
+		Profit:     profit,
+		ReturnPct:  float64(profit) / float64(p.cost) * 100,
+	})
+	st.pos = nil
+	st.exitReason = ""
+}
+
+// equity values cash plus open positions at their after-tax sale price.
+func (sim *simulation) equity(states []*itemState) int64 {
+	total := sim.cash
+	for _, st := range states {
+		if st.pos != nil {
+			total += st.pos.quantity * (st.lastSell - utils.GETax(st.itemID, st.lastSell))
+		}
+	}
+	return total
+}
+
+func (sim *simulation) summarize(bars int) {
+	summary := models.BacktestSummary{
+		InitialCapital: sim.strategy.InitialCapital,
+		FinalEquity:    sim.cash,
+		NetProfit:      sim.cash - sim.strategy.InitialCapital,
+		Trades:         len(sim.result.Trades),
+		Bars:           bars,
+	}
+	if summary.InitialCapital > 0 {
+		summary.TotalReturnPct = float64(summary.NetProfit) / float64(summary.InitialCapital) * 100
+	}
+
+	var grossProfit, grossLoss int64
+	var held time.Duration
+	for _, t := range sim.result.Trades {
+		summary.TaxPaid += t.Tax
+		held += t.ExitTime.Sub(t.EntryTime)
+		switch {
+		case t.Profit > 0:
+			summary.Wins++
+			grossProfit += t.Profit
+		case t.Profit < 0:
+			summary.Losses++
+			grossLoss -= t.Profit
+		}
+	}
+	if summary.Trades > 0 {
+		summary.WinRatePct = float64(summary.Wins) / float64(summary.Trades) * 100
+		summary.AvgHoldMinutes = held.Minutes() / float64(summary.Trades)
+	}
+	if grossLoss > 0 {
+		pf := float64(grossProfit) / float64(grossLoss)
+		summary.ProfitFactor = &pf
+	}
+
+	peak := int64(math.MinInt64)
+	for _, p := range sim.result.Equity {
+		peak = max(peak, p.Equity)
+		if peak > 0 {
+			summary.MaxDrawdownPct = math.Max(summary.MaxDrawdownPct, float64(peak-p.Equity)/float64(peak)*100)
+		}
+	}
+
+	sim.result.Summary = summary
+}
+
+func allHold(s *series, conditions []models.BacktestCondition, i int) bool {
+	for _, c := range conditions {
+		if !s.holds(c, i) {
+			return false
+		}
+	}
+	return len(conditions) > 0
+}
+
+// volumeCap is the most units a fill may take from a bar's volume.
+func volumeCap(volume int64, sharePct float64) int64 {
+	return int64(float64(volume) * sharePct / 100)
+}
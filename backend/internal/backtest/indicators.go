@@ -0,0 +1,174 @@
+package backtest
+
+import (
+	"math"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// indicatorKey identifies one computed indicator series.
+type indicatorKey struct {
+	indicator models.BacktestIndicator
+	period    int
+}
+
+// series holds one item's bars in ascending order with lazily computed
+// indicator values aligned to them.
+type series struct {
+	bars       []models.PriceTimeseriesPoint
+	mid        []*float64
+	indicators map[indicatorKey][]*float64
+}
+
+func newSeries(bars []models.PriceTimeseriesPoint) *series {
+	s := &series{
+		bars:       bars,
+		mid:        make([]*float64, len(bars)),
+		indicators: make(map[indicatorKey][]*float64),
+	}
+	for i, b := range bars {
+		s.mid[i] = models.PriceFieldMid.Value(b.AvgHighPrice, b.AvgLowPrice)
+	}
+	return s
+}
+
+// holds reports whether condition c is true at bar i. Missing values never hold.
+func (s *series) holds(c models.BacktestCondition, i int) bool {
+	v := s.indicator(c.Indicator, c.Period)[i]
+	if v == nil {
+		return false
+	}
+	switch c.Operator {
+	case "<":
+		return *v < c.Value
+	case "<=":
+		return *v <= c.Value
+	case ">":
+		return *v > c.Value
+	case ">=":
+		return *v >= c.Value
+	}
+	return false
+}
+
+func (s *series) indicator(indicator models.BacktestIndicator, period int) []*float64 {
+	key := indicatorKey{indicator: indicator, period: period}
+	if values, ok := s.indicators[key]; ok {
+		return values
+	}
+
+	var values []*float64
+	switch indicator {
+	case models.BacktestIndicatorPrice:
+		values = s.mid
+	case models.BacktestIndicatorSpreadPct:
+		values = make([]*float64, len(s.bars))
+		for i, b := range s.bars {
+			if b.AvgHighPrice != nil && b.AvgLowPrice != nil && *b.AvgLowPrice > 0 {
+				v := float64(*b.AvgHighPrice-*b.AvgLowPrice) / float64(*b.AvgLowPrice) * 100
+				values[i] = &v
+			}
+		}
+	case models.BacktestIndicatorVolume:
+		values = make([]*float64, len(s.bars))
+		for i, b := range s.bars {
+			v := float64(b.HighPriceVolume + b.LowPriceVolume)
+			values[i] = &v
+		}
+	case models.BacktestIndicatorRSI:
+		values = rsi(s.mid, period)
+	case models.BacktestIndicatorSMADeviationPct:
+		values = smaDeviationPct(s.mid, period)
+	case models.BacktestIndicatorReturnPct:
+		values = returnPct(s.mid, period)
+	default:
+		values = make([]*float64, len(s.bars))
+	}
+
+	s.indicators[key] = values
+	return values
+}
+
+// rsi is Wilder's RSI over the bars with a price. The first value appears
+// once period price changes have been seen.
+func rsi(prices []*float64, period int) []*float64 {
+	out := make([]*float64, len(prices))
+	var prev *float64
+	var avgGain, avgLoss float64
+	changes := 0
+
+	for i, p := range prices {
+		if p == nil {
+			continue
+		}
+		if prev != nil {
+			change := *p - *prev
+			gain, loss := math.Max(change, 0), math.Max(-change, 0)
+			changes++
+			if changes <= period {
+				// Seed with a simple average of the first period changes.
+				avgGain += gain / float64(period)
+				avgLoss += loss / float64(period)
+			} else {
+				avgGain = (avgGain*float64(period-1) + gain) / float64(period)
+				avgLoss = (avgLoss*float64(period-1) + loss) / float64(period)
+			}
+			if changes >= period {
+				v := 100.0
+				if avgLoss > 0 {
+					v = 100 - 100/(1+avgGain/avgLoss)
+				} else if avgGain == 0 {
+					v = 50
+				}
+				out[i] = &v
+			}
+		}
+		prev = p
+	}
+	return out
+}
+
+// smaDeviationPct compares each price with the mean of the last period prices.
+func smaDeviationPct(prices []*float64, period int) []*float64 {
+	out := make([]*float64, len(prices))
+	window := make([]float64, 0, period)
+	var sum float64
+
+	for i, p := range prices {
+		if p == nil {
+			continue
+		}
+		window = append(window, *p)
+		sum += *p
+		if len(window) > period {
+			sum -= window[0]
+			window = window[1:]
+		}
+		if len(window) == period && sum > 0 {
+			v := (*p/(sum/float64(period)) - 1) * 100
+			out[i] = &v
+		}
+	}
+	return out
+}
+
+// returnPct is the change from the price period priced bars earlier.
+func returnPct(prices []*float64, period int) []*float64 {
+	out := make([]*float64, len(prices))
+	history := make([]float64, 0, len(prices))
+
+	for i, p := range prices {
+		if p == nil {
+			continue
+		}
+		if len(history) >= period {
+			base := history[len(history)-period]
+			if base > 0 {
+				v := (*p/base - 1) * 100
+				out[i] = &v
+			}
+		}
+		history = append(history, *p)
+	}
+	return out
+}
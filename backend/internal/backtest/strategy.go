@@ -0,0 +1,170 @@
+// Package backtest replays declarative trading strategies against stored price
+// buckets. Simulation is deterministic: the same strategy and bars always
+// produce the same trades.
+package backtest
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// ErrInvalidStrategy is wrapped by every strategy validation error.
+var ErrInvalidStrategy = errors.New("invalid strategy")
+
+// Strategy limits. Window caps follow the retention of each bucket table.
+const (
+	MaxItems         = 10
+	MaxConditions    = 8
+	MaxPeriod        = 200
+	MaxNameLength    = 100
+	Max5mWindow      = 7 * 24 * time.Hour
+	Max1hWindow      = 90 * 24 * time.Hour
+	DefaultCapital   = 10_000_000
+	DefaultSizePct   = 25
+	DefaultVolumePct = 10
+)
+
+// defaultPeriods are used when a condition leaves Period unset.
+var defaultPeriods = map[models.BacktestIndicator]int{
+	models.BacktestIndicatorRSI:             14,
+	models.BacktestIndicatorSMADeviationPct: 20,
+	models.BacktestIndicatorReturnPct:       1,
+}
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidStrategy, fmt.Sprintf(format, args...))
+}
+
+// Normalize validates a strategy and fills in defaults. An unset End means
+// now; an unset Start means the longest window the timestep allows.
+func Normalize(s models.BacktestStrategy, now time.Time) (models.BacktestStrategy, error) {
+	if s.Timestep == "" {
+		s.Timestep = "1h"
+	}
+	var maxWindow time.Duration
+	switch s.Timestep {
+	case "5m":
+		maxWindow = Max5mWindow
+	case "1h":
+		maxWindow = Max1hWindow
+	default:
+		return s, invalid("timestep must be one of: 5m, 1h")
+	}
+
+	if len(s.Name) > MaxNameLength {
+		return s, invalid("name must be at most %d characters", MaxNameLength)
+	}
+
+	if len(s.ItemIDs) == 0 {
+		return s, invalid("itemIds is required")
+	}
+	if len(s.ItemIDs) > MaxItems {
+		return s, invalid("maximum %d items per backtest", MaxItems)
+	}
+	seen := make(map[int]struct{}, len(s.ItemIDs))
+	for _, id := range s.ItemIDs {
+		if id <= 0 {
+			return s, invalid("invalid item ID: %d", id)
+		}
+		if _, dup := seen[id]; dup {
+			return s, invalid("duplicate item ID: %d", id)
+		}
+		seen[id] = struct{}{}
+	}
+
+	if s.End.IsZero() || s.End.After(now) {
+		s.End = now
+	}
+	s.End = s.End.UTC()
+	if s.Start.IsZero() {
+		s.Start = s.End.Add(-maxWindow)
+	}
+	s.Start = s.Start.UTC()
+	if !s.Start.Before(s.End) {
+		return s, invalid("start must be before end")
+	}
+	if s.End.Sub(s.Start) > maxWindow {
+		return s, invalid("window for %s buckets must not exceed %d days", s.Timestep, int(maxWindow.Hours()/24))
+	}
+
+	if len(s.Entry) == 0 {
+		return s, invalid("at least one entry condition is required")
+	}
+	if len(s.Entry)+len(s.Exit) > MaxConditions {
+		return s, invalid("maximum %d conditions per strategy", MaxConditions)
+	}
+	// Copy so defaults are never written into the caller's slices.
+	s.Entry = append([]models.BacktestCondition(nil), s.Entry...)
+	s.Exit = append([]models.BacktestCondition(nil), s.Exit...)
+	for i := range s.Entry {
+		if err := normalizeCondition(&s.Entry[i]); err != nil {
+			return s, err
+		}
+	}
+	for i := range s.Exit {
+		if err := normalizeCondition(&s.Exit[i]); err != nil {
+			return s, err
+		}
+	}
+	if len(s.Exit) == 0 && s.TakeProfitPct == nil && s.StopLossPct == nil && s.MaxHoldBars == 0 {
+		return s, invalid("an exit condition, takeProfitPct, stopLossPct or maxHoldBars is required")
+	}
+
+	if s.TakeProfitPct != nil && *s.TakeProfitPct <= 0 {
+		return s, invalid("takeProfitPct must be positive")
+	}
+	if s.StopLossPct != nil && (*s.StopLossPct <= 0 || *s.StopLossPct >= 100) {
+		return s, invalid("stopLossPct must be between 0 and 100")
+	}
+	if s.MaxHoldBars < 0 {
+		return s, invalid("maxHoldBars must not be negative")
+	}
+
+	if s.InitialCapital == 0 {
+		s.InitialCapital = DefaultCapital
+	}
+	if s.InitialCapital < 0 {
+		return s, invalid("initialCapital must be positive")
+	}
+	if s.PositionSizePct == 0 {
+		s.PositionSizePct = DefaultSizePct
+	}
+	if s.PositionSizePct < 0 || s.PositionSizePct > 100 {
+		return s, invalid("positionSizePct must be between 0 and 100")
+	}
+	if s.MaxVolumeSharePct == 0 {
+		s.MaxVolumeSharePct = DefaultVolumePct
+	}
+	if s.MaxVolumeSharePct < 0 || s.MaxVolumeSharePct > 100 {
+		return s, invalid("maxVolumeSharePct must be between 0 and 100")
+	}
+
+	return s, nil
+}
+
+func normalizeCondition(c *models.BacktestCondition) error {
+	if !c.Indicator.IsValid() {
+		return invalid("unknown indicator %q", c.Indicator)
+	}
+	switch c.Operator {
+	case "<", "<=", ">", ">=":
+	default:
+		return invalid("op must be one of: <, <=, >, >=")
+	}
+
+	def, usesPeriod := defaultPeriods[c.Indicator]
+	if !usesPeriod {
+		c.Period = 0
+		return nil
+	}
+	if c.Period == 0 {
+		c.Period = def
+	}
+	if c.Period < 1 || c.Period > MaxPeriod {
+		return invalid("%s period must be between 1 and %d", c.Indicator, MaxPeriod)
+	}
+	return nil
+}
@@ -42,6 +42,11 @@ type Config struct {
 	Environment       string
 	CorsOrigins       string
 	WikiPricesBaseURL string
+	AdminAPIKey       string
+	ActivitiesDir     string
+	MarketIndicesFile string
+	BondRealCurrency  string
+	BondRealPrice     float64
 	Cache             RedisConfig
 	SSE               SSEConfig
 }
@@ -102,6 +107,19 @@ func LoadConfig() (*Config, error) {
 
 		WikiPricesBaseURL: viper.GetString("WIKI_PRICES_BASE_URL"),
 
+		// Admin endpoints are disabled unless a key is set
+		AdminAPIKey: viper.GetString("ADMIN_API_KEY"),
+
+		// Extra drop table files added to the shipped activities
+		ActivitiesDir: viper.GetString("ACTIVITIES_DIR"),
+
+		// JSON file replacing the shipped market index definitions
+		MarketIndicesFile: viper.GetString("MARKET_INDICES_FILE"),
+
+		// Real-money price of one Old School Bond; zero disables denomination=real
+		BondRealPrice:    viper.GetFloat64("BOND_REAL_PRICE"),
+		BondRealCurrency: viper.GetString("BOND_REAL_CURRENCY"),
+
 		SSE: SSEConfig{
 			Enabled:           viper.GetBool("SSE_ENABLED"),
 			ConnectionTimeout: viper.GetDuration("SSE_CONNECTION_TIMEOUT"),
@@ -136,6 +154,10 @@ func setDefaults() {
 	// OSRS Wiki prices API defaults
 	viper.SetDefault("WIKI_PRICES_BASE_URL", "https://prices.runescape.wiki/api/v1/osrs")
 
+	// Bond denomination defaults
+	viper.SetDefault("BOND_REAL_PRICE", 0)
+	viper.SetDefault("BOND_REAL_CURRENCY", "USD")
+
 	// SSE defaults
 	viper.SetDefault("SSE_ENABLED", true)
 	viper.SetDefault("SSE_CONNECTION_TIMEOUT", 30*time.Minute)
@@ -0,0 +1,60 @@
+package forecast
+
+import "math"
+
+// Accuracy summarises out-of-sample forecast errors.
+type Accuracy struct {
+	MAE  float64
+	RMSE float64
+	// MAPE is the mean absolute percentage error (0.05 = 5%), skipping zero actuals.
+	MAPE float64
+	// Folds is the number of forecast origins evaluated.
+	Folds int
+}
+
+// Backtest evaluates a model with rolling-origin holdouts: for each of up to
+// folds origins, ending at the last value, the model is fitted on everything
+// before the origin and scored on the next horizon values. Origins that leave
+// too little training data are skipped; ErrInsufficientData is returned when
+// none remain.
+func Backtest(m Model, series []float64, horizon, folds int) (Accuracy, error) {
+	var absSum, sqSum, pctSum float64
+	var n, pctN, used int
+
+	for k := folds; k >= 1; k-- {
+		origin := len(series) - k*horizon
+		if origin < 1 {
+			continue
+		}
+		result, err := m.Forecast(series[:origin], horizon)
+		if err != nil {
+			continue
+		}
+		for h := 0; h < horizon; h++ {
+			actual := series[origin+h]
+			diff := actual - result.Values[h]
+			absSum += math.Abs(diff)
+			sqSum += diff * diff
+			n++
+			if actual != 0 {
+				pctSum += math.Abs(diff / actual)
+				pctN++
+			}
+		}
+		used++
+	}
+
+	if used == 0 {
+		return Accuracy{}, ErrInsufficientData
+	}
+
+	acc := Accuracy{
+		MAE:   absSum / float64(n),
+		RMSE:  math.Sqrt(sqSum / float64(n)),
+		Folds: used,
+	}
+	if pctN > 0 {
+		acc.MAPE = pctSum / float64(pctN)
+	}
+	return acc, nil
+}
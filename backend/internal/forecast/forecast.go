@@ -0,0 +1,62 @@
+// Package forecast provides deterministic short-horizon forecasting models for
+// evenly spaced price series. Models have no hidden state or randomness, so the
+// same input always produces the same forecast.
+package forecast
+
+import (
+	"errors"
+	"math"
+)
+
+// z95 is the two-sided 95% normal quantile used for prediction intervals.
+const z95 = 1.96
+
+// ErrInsufficientData is returned when a series is too short for a model.
+var ErrInsufficientData = errors.New("not enough data to fit the model")
+
+// Result is a point forecast with 95% prediction intervals.
+type Result struct {
+	// Params holds the fitted smoothing parameters, keyed by name.
+	Params map[string]float64
+	Values []float64
+	Lower  []float64
+	Upper  []float64
+	// Sigma is the standard deviation of the in-sample one-step errors.
+	Sigma float64
+}
+
+// Model fits a series and forecasts the next values.
+type Model interface {
+	// Name identifies the model in responses.
+	Name() string
+	// Forecast fits the model to series and predicts the next horizon values.
+	Forecast(series []float64, horizon int) (Result, error)
+}
+
+// newResult fills intervals as value ± z95·sigma·spread(h) for h = 1..horizon.
+func newResult(values []float64, sigma float64, spread func(h int) float64) Result {
+	result := Result{
+		Values: values,
+		Lower:  make([]float64, len(values)),
+		Upper:  make([]float64, len(values)),
+		Sigma:  sigma,
+	}
+	for i, v := range values {
+		width := z95 * sigma * spread(i+1)
+		result.Lower[i] = v - width
+		result.Upper[i] = v + width
+	}
+	return result
+}
+
+// rms returns the root mean square of values (0 when empty).
+func rms(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var ss float64
+	for _, v := range values {
+		ss += v * v
+	}
+	return math.Sqrt(ss / float64(len(values)))
+}
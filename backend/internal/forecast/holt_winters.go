@@ -0,0 +1,163 @@
+package forecast
+
+import "math"
+
+// Smoothing parameter grids searched when a HoltWinters parameter is zero.
+// The grid is fixed so fitting stays deterministic.
+var (
+	alphaGrid = []float64{0.05, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9}
+	betaGrid  = []float64{0.001, 0.01, 0.05, 0.1, 0.2}
+	gammaGrid = []float64{0.01, 0.05, 0.1, 0.2, 0.3, 0.5}
+)
+
+// HoltWinters is additive triple exponential smoothing (level, trend and a
+// seasonal cycle of Period steps). When the series holds fewer than two full
+// seasons, or Period is below 2, it falls back to Holt's linear trend method.
+// Zero Alpha, Beta or Gamma are fitted by grid search on one-step squared error.
+type HoltWinters struct {
+	Period int
+	Alpha  float64
+	Beta   float64
+	Gamma  float64
+}
+
+// Name identifies the model in responses.
+func (m HoltWinters) Name() string { return "holt_winters" }
+
+type hwState struct {
+	season []float64
+	level  float64
+	trend  float64
+	sse    float64
+	count  int
+}
+
+// Forecast fits the model and predicts the next horizon values.
+func (m HoltWinters) Forecast(series []float64, horizon int) (Result, error) {
+	period := m.Period
+	if period < 2 || len(series) < 2*period {
+		period = 0
+	}
+	if len(series) < 3 || horizon < 1 {
+		return Result{}, ErrInsufficientData
+	}
+
+	alphas := pick(m.Alpha, alphaGrid)
+	betas := pick(m.Beta, betaGrid)
+	gammas := []float64{0}
+	if period > 0 {
+		gammas = pick(m.Gamma, gammaGrid)
+	}
+
+	var best hwState
+	var bestAlpha, bestBeta, bestGamma float64
+	found := false
+	for _, alpha := range alphas {
+		for _, beta := range betas {
+			for _, gamma := range gammas {
+				state := runHoltWinters(series, period, alpha, beta, gamma)
+				if !found || state.sse < best.sse {
+					best, bestAlpha, bestBeta, bestGamma = state, alpha, beta, gamma
+					found = true
+				}
+			}
+		}
+	}
+
+	values := make([]float64, horizon)
+	for h := 1; h <= horizon; h++ {
+		values[h-1] = best.level + float64(h)*best.trend
+		if period > 0 {
+			values[h-1] += best.season[(len(series)+h-1)%period]
+		}
+	}
+
+	sigma := 0.0
+	if best.count > 0 {
+		sigma = math.Sqrt(best.sse / float64(best.count))
+	}
+
+	// Approximate ETS(A,A,A) forecast variance:
+	// σ²·(1 + Σ_{j=1}^{h-1} (α(1+jβ) + γ·[j mod m = 0])²).
+	result := newResult(values, sigma, func(h int) float64 {
+		v := 1.0
+		for j := 1; j < h; j++ {
+			c := bestAlpha * (1 + float64(j)*bestBeta)
+			if period > 0 && j%period == 0 {
+				c += bestGamma
+			}
+			v += c * c
+		}
+		return math.Sqrt(v)
+	})
+	result.Params = map[string]float64{"alpha": bestAlpha, "beta": bestBeta}
+	if period > 0 {
+		result.Params["gamma"] = bestGamma
+		result.Params["period"] = float64(period)
+	}
+	return result, nil
+}
+
+// runHoltWinters smooths the whole series and accumulates one-step squared
+// errors after the initialisation window.
+func runHoltWinters(series []float64, period int, alpha, beta, gamma float64) hwState {
+	state := hwState{}
+	start := 2
+
+	if period > 0 {
+		first := meanOf(series[:period])
+		second := meanOf(series[period : 2*period])
+		state.level = first
+		state.trend = (second - first) / float64(period)
+		state.season = make([]float64, period)
+		for i := 0; i < period; i++ {
+			state.season[i] = series[i] - first
+		}
+		start = period
+	} else {
+		state.level = series[0]
+		state.trend = series[1] - series[0]
+	}
+
+	for t := 0; t < len(series); t++ {
+		s := 0.0
+		if period > 0 {
+			s = state.season[t%period]
+		}
+		if t >= start {
+			err := series[t] - (state.level + state.trend + s)
+			state.sse += err * err
+			state.count++
+		}
+		if period == 0 && t < 2 {
+			// Holt initialisation consumes the first two points.
+			if t == 1 {
+				state.level = series[1]
+			}
+			continue
+		}
+
+		level := alpha*(series[t]-s) + (1-alpha)*(state.level+state.trend)
+		state.trend = beta*(level-state.level) + (1-beta)*state.trend
+		if period > 0 {
+			state.season[t%period] = gamma*(series[t]-level) + (1-gamma)*s
+		}
+		state.level = level
+	}
+	return state
+}
+
+func pick(fixed float64, grid []float64) []float64 {
+	if fixed > 0 {
+		return []float64{fixed}
+	}
+	return grid
+}
+
+func meanOf(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
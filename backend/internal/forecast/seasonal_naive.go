@@ -0,0 +1,36 @@
+package forecast
+
+import "math"
+
+// SeasonalNaive repeats the last observed season: the forecast for a step is
+// the value one Period earlier. With Period 1 it is the plain naive forecast.
+type SeasonalNaive struct {
+	Period int
+}
+
+// Name identifies the model in responses.
+func (m SeasonalNaive) Name() string { return "seasonal_naive" }
+
+// Forecast predicts the next horizon values. Intervals use the seasonal naive
+// variance σ²·(k+1), where k is the number of whole seasons ahead.
+func (m SeasonalNaive) Forecast(series []float64, horizon int) (Result, error) {
+	period := max(m.Period, 1)
+	if len(series) <= period || horizon < 1 {
+		return Result{}, ErrInsufficientData
+	}
+
+	residuals := make([]float64, 0, len(series)-period)
+	for t := period; t < len(series); t++ {
+		residuals = append(residuals, series[t]-series[t-period])
+	}
+
+	n := len(series)
+	values := make([]float64, horizon)
+	for h := 1; h <= horizon; h++ {
+		values[h-1] = series[n-period+(h-1)%period]
+	}
+
+	return newResult(values, rms(residuals), func(h int) float64 {
+		return math.Sqrt(float64((h-1)/period + 1))
+	}), nil
+}
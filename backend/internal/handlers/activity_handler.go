@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// ActivityHandler handles PvM activity value endpoints.
+type ActivityHandler struct {
+	activityService services.ActivityService
+	logger          *zap.SugaredLogger
+}
+
+// NewActivityHandler creates a new activity handler.
+func NewActivityHandler(activityService services.ActivityService, logger *zap.SugaredLogger) *ActivityHandler {
+	return &ActivityHandler{
+		activityService: activityService,
+		logger:          logger,
+	}
+}
+
+// ListActivityValues handles GET /api/v1/activities[?pricing=offer|instant].
+// Activities are ranked by value per hour; drop breakdowns are left out.
+func (h *ActivityHandler) ListActivityValues(c *fiber.Ctx) error {
+	mode, err := pricingModeFromQuery(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	values, err := h.activityService.ActivityValues(c.Context(), mode)
+	if err != nil {
+		h.logger.Errorf("Failed to value activities: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to value activities")
+	}
+	for i := range values {
+		values[i].Drops = nil
+	}
+
+	return c.JSON(fiber.Map{
+		"data": values,
+		"meta": fiber.Map{
+			"count":   len(values),
+			"pricing": mode,
+		},
+	})
+}
+
+// ListActivities handles GET /api/v1/activities/definitions.
+func (h *ActivityHandler) ListActivities(c *fiber.Ctx) error {
+	list := h.activityService.ListActivities(c.Context())
+	return c.JSON(fiber.Map{
+		"data": list,
+		"meta": fiber.Map{
+			"count": len(list),
+		},
+	})
+}
+
+// GetActivityValue handles GET /api/v1/activities/:id/value[?pricing=offer|instant].
+func (h *ActivityHandler) GetActivityValue(c *fiber.Ctx) error {
+	mode, err := pricingModeFromQuery(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	value, err := h.activityService.ActivityValue(c.Context(), c.Params("id"), mode)
+	if err != nil {
+		h.logger.Errorf("Failed to value activity %s: %v", c.Params("id"), err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to value activity")
+	}
+	if value == nil {
+		return errorResponse(c, fiber.StatusNotFound, "activity not found")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": value,
+	})
+}
+
+// GetActivityValueHistory handles GET /api/v1/activities/:id/value/history
+// [?from=...&to=...&pricing=offer|instant].
+func (h *ActivityHandler) GetActivityValueHistory(c *fiber.Ctx) error {
+	params := models.ActivityHistoryParams{Pricing: models.PricingMode(c.Query("pricing"))}
+	if raw := c.Query("from"); raw != "" {
+		from, err := parseTimeParam(raw)
+		if err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, "invalid from timestamp")
+		}
+		params.From = from
+	}
+	if raw := c.Query("to"); raw != "" {
+		to, err := parseTimeParam(raw)
+		if err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, "invalid to timestamp")
+		}
+		params.To = to
+	}
+
+	history, err := h.activityService.ActivityValueHistory(c.Context(), c.Params("id"), params)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidActivity) {
+			return errorResponse(c, fiber.StatusBadRequest, strings.TrimPrefix(err.Error(), models.ErrInvalidActivity.Error()+": "))
+		}
+		h.logger.Errorf("Failed to get value history for activity %s: %v", c.Params("id"), err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to get activity value history")
+	}
+	if history == nil {
+		return errorResponse(c, fiber.StatusNotFound, "activity not found")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": history,
+		"meta": fiber.Map{
+			"count": len(history.Points),
+		},
+	})
+}
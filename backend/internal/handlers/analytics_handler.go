@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/forecast"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// AnalyticsHandler handles derived price analytics endpoints.
+type AnalyticsHandler struct {
+	analyticsService services.AnalyticsService
+	watchlistService services.WatchlistService
+	logger           *zap.SugaredLogger
+}
+
+// NewAnalyticsHandler creates a new analytics handler. watchlistService
+// resolves share tokens for multi-item endpoints and may be nil.
+func NewAnalyticsHandler(
+	analyticsService services.AnalyticsService,
+	watchlistService services.WatchlistService,
+	logger *zap.SugaredLogger,
+) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+		watchlistService: watchlistService,
+		logger:           logger,
+	}
+}
+
+// GetSeasonality handles GET /api/v1/analytics/seasonality/:id?lookback=28d.
+func (h *AnalyticsHandler) GetSeasonality(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	itemID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid item ID")
+	}
+
+	lookbackDays, err := parseDaysParam("lookback", c.Query("lookback"),
+		models.DefaultSeasonalityLookbackDays, 1, slices.Max(models.SeasonalityLookbackDays))
+	if err != nil || !models.IsSeasonalityLookback(lookbackDays) {
+		return errorResponse(c, fiber.StatusBadRequest, seasonalityLookbackMessage())
+	}
+
+	profile, err := h.analyticsService.GetSeasonality(ctx, models.SeasonalityParams{
+		ItemID:       itemID,
+		LookbackDays: lookbackDays,
+		Refresh:      c.Query("refresh") == "true",
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to get seasonality for item %d: %v", itemID, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to compute seasonality")
+	}
+	if profile.Observations == 0 {
+		return errorResponse(c, fiber.StatusNotFound, "not enough price history for seasonality analysis")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": profile,
+		"meta": fiber.Map{
+			"item_id":       profile.ItemID,
+			"lookback_days": profile.LookbackDays,
+			"generated_at":  profile.GeneratedAt,
+			"timezone":      "UTC",
+		},
+	})
+}
+
+// GetForecast handles GET /api/v1/analytics/forecast/:id?horizon=6&field=mid&model=auto.
+func (h *AnalyticsHandler) GetForecast(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	itemID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid item ID")
+	}
+
+	horizon := models.DefaultForecastHorizon
+	if horizonStr := c.Query("horizon"); horizonStr != "" {
+		horizon, err = strconv.Atoi(horizonStr)
+		if err != nil || horizon < 1 || horizon > models.MaxForecastHorizon {
+			return errorResponse(c, fiber.StatusBadRequest,
+				fmt.Sprintf("horizon must be between 1 and %d hours", models.MaxForecastHorizon))
+		}
+	}
+
+	field := models.PriceField(c.Query("field", string(models.PriceFieldMid)))
+	if !field.IsValid() {
+		return errorResponse(c, fiber.StatusBadRequest, "field must be one of: mid, high, low")
+	}
+
+	model := models.ForecastModel(c.Query("model", string(models.ForecastModelAuto)))
+	if !model.IsValid() {
+		return errorResponse(c, fiber.StatusBadRequest, "model must be one of: auto, holt_winters, seasonal_naive")
+	}
+
+	result, err := h.analyticsService.GetForecast(ctx, models.ForecastParams{
+		ItemID:  itemID,
+		Horizon: horizon,
+		Field:   field,
+		Model:   model,
+	})
+	if err != nil {
+		if errors.Is(err, forecast.ErrInsufficientData) {
+			return errorResponse(c, fiber.StatusNotFound, "not enough price history to forecast")
+		}
+		h.logger.Errorf("Failed to forecast item %d: %v", itemID, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to compute forecast")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": result,
+		"meta": fiber.Map{
+			"item_id":          result.ItemID,
+			"horizon":          result.Horizon,
+			"model":            result.Model,
+			"field":            result.Field,
+			"interval_seconds": result.IntervalSeconds,
+		},
+	})
+}
+
+// GetCorrelation handles GET /api/v1/analytics/correlation?ids=1,2,3|share=token&window=30d&field=mid.
+func (h *AnalyticsHandler) GetCorrelation(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	itemIDs, err := itemIDsFromQuery(c, h.watchlistService, models.MaxCorrelationItems)
+	if err != nil {
+		h.logger.Debugw("Rejected correlation request", "error", err)
+		return respondQueryError(c, err, "failed to resolve items")
+	}
+	if len(itemIDs) < 2 {
+		return errorResponse(c, fiber.StatusBadRequest, "at least 2 distinct item IDs are required")
+	}
+
+	windowDays, err := parseDaysParam("window", c.Query("window"),
+		models.DefaultCorrelationWindowDays, models.MinCorrelationWindowDays, models.MaxCorrelationWindowDays)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	field := models.PriceField(c.Query("field", string(models.PriceFieldMid)))
+	if !field.IsValid() {
+		return errorResponse(c, fiber.StatusBadRequest, "field must be one of: mid, high, low")
+	}
+
+	result, err := h.analyticsService.GetCorrelation(ctx, models.CorrelationParams{
+		ItemIDs:    itemIDs,
+		WindowDays: windowDays,
+		Field:      field,
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to compute correlation for items %v: %v", itemIDs, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to compute correlation")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": result,
+		"meta": fiber.Map{
+			"item_count":       len(result.ItemIDs),
+			"window_days":      result.WindowDays,
+			"field":            result.Field,
+			"timestep":         result.Timestep,
+			"interval_seconds": result.IntervalSeconds,
+		},
+	})
+}
+
+// seasonalityLookbackMessage lists the supported lookbacks, e.g. "lookback
+// must be one of 7d, 14d, 28d".
+func seasonalityLookbackMessage() string {
+	days := make([]string, len(models.SeasonalityLookbackDays))
+	for i, d := range models.SeasonalityLookbackDays {
+		days[i] = strconv.Itoa(d) + "d"
+	}
+	return "lookback must be one of " + strings.Join(days, ", ")
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/arbitrage"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// ArbitrageHandler handles arbitrage endpoints and the admin routes that
+// edit their registries.
+type ArbitrageHandler struct {
+	arbitrageService services.ArbitrageService
+	logger           *zap.SugaredLogger
+}
+
+// NewArbitrageHandler creates a new arbitrage handler.
+func NewArbitrageHandler(arbitrageService services.ArbitrageService, logger *zap.SugaredLogger) *ArbitrageHandler {
+	return &ArbitrageHandler{
+		arbitrageService: arbitrageService,
+		logger:           logger,
+	}
+}
+
+// itemSetRequest is the body of PUT /api/v1/admin/arbitrage/sets/:setItemId.
+type itemSetRequest struct {
+	Name       string                    `json:"name"`
+	Components []models.ItemSetComponent `json:"components"`
+}
+
+// arbitrageQuery holds the options shared by the arbitrage report endpoints.
+type arbitrageQuery struct {
+	pricing models.PricingMode
+	sort    string
+	limit   int
+}
+
+// GetSetArbitrage handles GET /api/v1/arbitrage/sets
+// [?pricing=offer|instant&sort=profit|roi|profit_per_window&limit=50].
+func (h *ArbitrageHandler) GetSetArbitrage(c *fiber.Ctx) error {
+	q, err := parseArbitrageQuery(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	report, err := h.arbitrageService.SetArbitrage(c.Context(), q.pricing)
+	if err != nil {
+		h.logger.Errorf("Failed to compute set arbitrage: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to compute set arbitrage")
+	}
+
+	total := len(report.Sets)
+	arbitrage.SortSets(report.Sets, q.sort)
+	if q.limit > 0 && len(report.Sets) > q.limit {
+		report.Sets = report.Sets[:q.limit]
+	}
+
+	return c.JSON(fiber.Map{
+		"data": report,
+		"meta": fiber.Map{
+			"count": len(report.Sets),
+			"total": total,
+			"sort":  q.sort,
+		},
+	})
+}
+
+// GetDoseArbitrage handles GET /api/v1/arbitrage/doses
+// [?pricing=offer|instant&sort=profit|roi|profit_per_window&limit=50].
+func (h *ArbitrageHandler) GetDoseArbitrage(c *fiber.Ctx) error {
+	q, err := parseArbitrageQuery(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	report, err := h.arbitrageService.DoseArbitrage(c.Context(), q.pricing)
+	if err != nil {
+		h.logger.Errorf("Failed to compute dose arbitrage: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to compute dose arbitrage")
+	}
+
+	total := len(report.Groups)
+	arbitrage.SortDoseGroups(report.Groups, q.sort)
+	if q.limit > 0 && len(report.Groups) > q.limit {
+		report.Groups = report.Groups[:q.limit]
+	}
+
+	return c.JSON(fiber.Map{
+		"data": report,
+		"meta": fiber.Map{
+			"count": len(report.Groups),
+			"total": total,
+			"sort":  q.sort,
+		},
+	})
+}
+
+// ListDoseGroups handles GET /api/v1/arbitrage/doses/definitions.
+func (h *ArbitrageHandler) ListDoseGroups(c *fiber.Ctx) error {
+	groups, err := h.arbitrageService.ListDoseGroups(c.Context())
+	if err != nil {
+		h.logger.Errorf("Failed to list dose groups: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to list dose groups")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": groups,
+		"meta": fiber.Map{
+			"count": len(groups),
+		},
+	})
+}
+
+// ListSets handles GET /api/v1/arbitrage/sets/definitions.
+func (h *ArbitrageHandler) ListSets(c *fiber.Ctx) error {
+	sets, err := h.arbitrageService.ListSets(c.Context())
+	if err != nil {
+		h.logger.Errorf("Failed to list item sets: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to list item sets")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": sets,
+		"meta": fiber.Map{
+			"count": len(sets),
+		},
+	})
+}
+
+// SaveSet handles PUT /api/v1/admin/arbitrage/sets/:setItemId.
+func (h *ArbitrageHandler) SaveSet(c *fiber.Ctx) error {
+	setItemID, err := strconv.Atoi(c.Params("setItemId"))
+	if err != nil || setItemID <= 0 {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid set item ID")
+	}
+
+	var req itemSetRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debugw("Invalid item set request body", "error", err)
+		return errorResponse(c, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	set, err := h.arbitrageService.SaveSet(c.Context(), models.ItemSet{
+		SetItemID:  setItemID,
+		Name:       req.Name,
+		Components: req.Components,
+	})
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidItemSet) {
+			return errorResponse(c, fiber.StatusBadRequest, strings.TrimPrefix(err.Error(), models.ErrInvalidItemSet.Error()+": "))
+		}
+		h.logger.Errorf("Failed to save item set %d: %v", setItemID, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to save item set")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": set,
+	})
+}
+
+// DeleteSet handles DELETE /api/v1/admin/arbitrage/sets/:setItemId.
+func (h *ArbitrageHandler) DeleteSet(c *fiber.Ctx) error {
+	setItemID, err := strconv.Atoi(c.Params("setItemId"))
+	if err != nil || setItemID <= 0 {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid set item ID")
+	}
+
+	deleted, err := h.arbitrageService.DeleteSet(c.Context(), setItemID)
+	if err != nil {
+		h.logger.Errorf("Failed to delete item set %d: %v", setItemID, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to delete item set")
+	}
+	if !deleted {
+		return errorResponse(c, fiber.StatusNotFound, "item set not found")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// parseArbitrageQuery reads the pricing, sort and limit query parameters,
+// defaulting to offer pricing ranked by profit with no limit.
+func parseArbitrageQuery(c *fiber.Ctx) (arbitrageQuery, error) {
+	q := arbitrageQuery{sort: c.Query("sort", arbitrage.SortByProfit)}
+	pricing, err := pricingModeFromQuery(c)
+	if err != nil {
+		return q, err
+	}
+	q.pricing = pricing
+
+	switch q.sort {
+	case arbitrage.SortByProfit, arbitrage.SortByROI, arbitrage.SortByProfitPerWindow:
+	default:
+		return q, errors.New("sort must be one of profit, roi, profit_per_window")
+	}
+
+	if q.limit, err = limitFromQuery(c); err != nil {
+		return q, err
+	}
+	return q, nil
+}
+
+// pricingModeFromQuery reads the "pricing" query parameter, defaulting to
+// offer pricing.
+func pricingModeFromQuery(c *fiber.Ctx) (models.PricingMode, error) {
+	mode := models.PricingMode(c.Query("pricing", string(models.PricingOffer)))
+	if !mode.IsValid() {
+		return "", errors.New("pricing must be offer or instant")
+	}
+	return mode, nil
+}
+
+// limitFromQuery reads the optional "limit" query parameter; 0 means no
+// limit.
+func limitFromQuery(c *fiber.Ctx) (int, error) {
+	raw := c.Query("limit")
+	if raw == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 1 {
+		return 0, errors.New("limit must be a positive integer")
+	}
+	return limit, nil
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/autocomplete"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// AutocompleteHandler handles item name autocomplete.
+type AutocompleteHandler struct {
+	autocompleteService services.AutocompleteService
+	logger              *zap.SugaredLogger
+}
+
+// NewAutocompleteHandler creates a new autocomplete handler.
+func NewAutocompleteHandler(autocompleteService services.AutocompleteService, logger *zap.SugaredLogger) *AutocompleteHandler {
+	return &AutocompleteHandler{
+		autocompleteService: autocompleteService,
+		logger:              logger,
+	}
+}
+
+// Complete handles GET /api/v1/items/autocomplete?q=...&limit=8.
+// Results are served from memory and ranked by recent trade volume.
+func (h *AutocompleteHandler) Complete(c *fiber.Ctx) error {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		return errorResponse(c, fiber.StatusBadRequest, "search query parameter 'q' is required")
+	}
+	if len(query) > models.MaxAutocompleteQuery {
+		return errorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("q must be at most %d characters", models.MaxAutocompleteQuery))
+	}
+
+	limit := models.DefaultAutocompleteLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > autocomplete.MaxResults {
+			return errorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", autocomplete.MaxResults))
+		}
+	}
+
+	if !h.autocompleteService.Ready() {
+		return errorResponse(c, fiber.StatusServiceUnavailable, "autocomplete index is not ready")
+	}
+
+	items := h.autocompleteService.Complete(query, limit)
+	return c.JSON(fiber.Map{
+		"data": items,
+		"meta": fiber.Map{
+			"count": len(items),
+			"query": query,
+		},
+	})
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/backtest"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// Backtest list size bounds.
+const (
+	defaultBacktestListLimit = 20
+	maxBacktestListLimit     = 100
+)
+
+// BacktestHandler handles strategy backtest endpoints.
+type BacktestHandler struct {
+	backtestService services.BacktestService
+	logger          *zap.SugaredLogger
+}
+
+// NewBacktestHandler creates a new backtest handler.
+func NewBacktestHandler(backtestService services.BacktestService, logger *zap.SugaredLogger) *BacktestHandler {
+	return &BacktestHandler{
+		backtestService: backtestService,
+		logger:          logger,
+	}
+}
+
+// CreateBacktest handles POST /api/v1/backtests. The job runs asynchronously;
+// poll GET /api/v1/backtests/:id for its result.
+func (h *BacktestHandler) CreateBacktest(c *fiber.Ctx) error {
+	var strategy models.BacktestStrategy
+	if err := c.BodyParser(&strategy); err != nil {
+		h.logger.Debugw("Invalid backtest request body", "error", err)
+		return errorResponse(c, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	job, err := h.backtestService.Submit(c.Context(), strategy)
+	if err != nil {
+		switch {
+		case errors.Is(err, backtest.ErrInvalidStrategy):
+			return errorResponse(c, fiber.StatusBadRequest, strings.TrimPrefix(err.Error(), backtest.ErrInvalidStrategy.Error()+": "))
+		case errors.Is(err, models.ErrBacktestShuttingDown):
+			return errorResponse(c, fiber.StatusServiceUnavailable, err.Error())
+		case errors.Is(err, models.ErrBacktestQueueFull):
+			c.Set(fiber.HeaderRetryAfter, "30")
+			return errorResponse(c, fiber.StatusTooManyRequests, err.Error())
+		}
+		h.logger.Errorf("Failed to submit backtest: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to submit backtest")
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"data": job,
+		"meta": fiber.Map{
+			"status_url": "/api/v1/backtests/" + job.ID,
+		},
+	})
+}
+
+// GetBacktest handles GET /api/v1/backtests/:id.
+func (h *BacktestHandler) GetBacktest(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	job, err := h.backtestService.Get(c.Context(), id)
+	if err != nil {
+		h.logger.Errorf("Failed to get backtest %s: %v", id, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to get backtest")
+	}
+	if job == nil {
+		return errorResponse(c, fiber.StatusNotFound, "backtest not found")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": job,
+	})
+}
+
+// ListBacktests handles GET /api/v1/backtests?limit=20.
+func (h *BacktestHandler) ListBacktests(c *fiber.Ctx) error {
+	limit := defaultBacktestListLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxBacktestListLimit {
+			return errorResponse(c, fiber.StatusBadRequest, "limit must be between 1 and 100")
+		}
+	}
+
+	jobs, err := h.backtestService.List(c.Context(), limit)
+	if err != nil {
+		h.logger.Errorf("Failed to list backtests: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to list backtests")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": jobs,
+		"meta": fiber.Map{
+			"count": len(jobs),
+			"limit": limit,
+		},
+	})
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/middleware"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+	"github.com/guavi/osrs-ge-tracker/internal/valuation"
+)
+
+// BankSnapshotHandler handles saved bank endpoints. Every route is scoped to
+// the caller's X-User-ID.
+type BankSnapshotHandler struct {
+	bankSnapshotService services.BankSnapshotService
+	denominationService services.DenominationService
+	logger              *zap.SugaredLogger
+}
+
+// NewBankSnapshotHandler creates a new bank snapshot handler.
+// denominationService may be nil, in which case only gp amounts are served.
+func NewBankSnapshotHandler(
+	bankSnapshotService services.BankSnapshotService,
+	denominationService services.DenominationService,
+	logger *zap.SugaredLogger,
+) *BankSnapshotHandler {
+	return &BankSnapshotHandler{
+		bankSnapshotService: bankSnapshotService,
+		denominationService: denominationService,
+		logger:              logger,
+	}
+}
+
+// CreateSnapshot handles POST /api/v1/bank-snapshots[?name=Main]. The body is
+// a bank export in any format accepted by POST /api/v1/valuation.
+func (h *BankSnapshotHandler) CreateSnapshot(c *fiber.Ctx) error {
+	body, err := exportBody(c)
+	if err != nil {
+		return respondQueryError(c, err, "failed to read uploaded file")
+	}
+	entries, _, err := valuation.Parse(body)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest,
+			strings.TrimPrefix(err.Error(), models.ErrInvalidBankExport.Error()+": "))
+	}
+
+	snapshot, err := h.bankSnapshotService.Create(c.Context(), middleware.UserID(c), c.Query("name"), entries)
+	if err != nil {
+		return h.respondSnapshotError(c, err, "failed to save bank snapshot")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"data": snapshot,
+	})
+}
+
+// ListSnapshots handles GET /api/v1/bank-snapshots.
+func (h *BankSnapshotHandler) ListSnapshots(c *fiber.Ctx) error {
+	snapshots, err := h.bankSnapshotService.List(c.Context(), middleware.UserID(c))
+	if err != nil {
+		h.logger.Errorf("Failed to list bank snapshots: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to list bank snapshots")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": snapshots,
+		"meta": fiber.Map{
+			"count": len(snapshots),
+			"limit": models.MaxBankSnapshotsPerUser,
+		},
+	})
+}
+
+// GetSnapshot handles GET /api/v1/bank-snapshots/:id.
+func (h *BankSnapshotHandler) GetSnapshot(c *fiber.Ctx) error {
+	id, ok := snapshotID(c)
+	if !ok {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid snapshot ID")
+	}
+
+	snapshot, err := h.bankSnapshotService.Get(c.Context(), middleware.UserID(c), id)
+	if err != nil {
+		h.logger.Errorf("Failed to get bank snapshot %d: %v", id, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to get bank snapshot")
+	}
+	if snapshot == nil {
+		return errorResponse(c, fiber.StatusNotFound, "snapshot not found")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": snapshot,
+	})
+}
+
+// GetHistory handles GET /api/v1/bank-snapshots/:id/history
+// [?from=...&to=...&interval=1d&source=revalued|recorded&denomination=gp|bonds|real].
+func (h *BankSnapshotHandler) GetHistory(c *fiber.Ctx) error {
+	id, ok := snapshotID(c)
+	if !ok {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid snapshot ID")
+	}
+
+	params := models.NetWorthHistoryParams{Source: c.Query("source")}
+	if raw := c.Query("from"); raw != "" {
+		from, err := parseTimeParam(raw)
+		if err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, "invalid from timestamp")
+		}
+		params.From = from
+	}
+	if raw := c.Query("to"); raw != "" {
+		to, err := parseTimeParam(raw)
+		if err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, "invalid to timestamp")
+		}
+		params.To = to
+	}
+	if raw := c.Query("interval"); raw != "" {
+		interval, ok := models.NetWorthIntervals[raw]
+		if !ok {
+			return errorResponse(c, fiber.StatusBadRequest, "interval must be one of 1h, 6h, 1d, 7d")
+		}
+		params.Interval = interval
+	}
+	denomination, err := denominationFromQuery(c, h.denominationService)
+	if err != nil {
+		return respondQueryError(c, err, "failed to get net worth history")
+	}
+
+	history, err := h.bankSnapshotService.History(c.Context(), middleware.UserID(c), id, params)
+	if err != nil {
+		return h.respondSnapshotError(c, err, "failed to get net worth history")
+	}
+	if history == nil {
+		return errorResponse(c, fiber.StatusNotFound, "snapshot not found")
+	}
+
+	if denomination == models.DenominationGP {
+		return c.JSON(fiber.Map{
+			"data": history,
+			"meta": fiber.Map{
+				"count": len(history.Points),
+			},
+		})
+	}
+
+	// Each point is converted at the bond price in effect at its timestamp.
+	converted, err := h.denominationService.DenominateNetWorth(c.Context(), history, denomination)
+	if err != nil {
+		h.logger.Errorf("Failed to denominate net worth history for snapshot %d: %v", id, err)
+		return respondQueryError(c, denominationError(err), "failed to convert net worth history")
+	}
+	return c.JSON(fiber.Map{
+		"data": converted,
+		"meta": fiber.Map{
+			"count":        len(converted.Points),
+			"denomination": denomination,
+			"unconverted":  converted.Unconverted,
+		},
+	})
+}
+
+// DeleteSnapshot handles DELETE /api/v1/bank-snapshots/:id.
+func (h *BankSnapshotHandler) DeleteSnapshot(c *fiber.Ctx) error {
+	id, ok := snapshotID(c)
+	if !ok {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid snapshot ID")
+	}
+
+	deleted, err := h.bankSnapshotService.Delete(c.Context(), middleware.UserID(c), id)
+	if err != nil {
+		h.logger.Errorf("Failed to delete bank snapshot %d: %v", id, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to delete bank snapshot")
+	}
+	if !deleted {
+		return errorResponse(c, fiber.StatusNotFound, "snapshot not found")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *BankSnapshotHandler) respondSnapshotError(c *fiber.Ctx, err error, fallback string) error {
+	if errors.Is(err, models.ErrInvalidBankSnapshot) {
+		return errorResponse(c, fiber.StatusBadRequest, strings.TrimPrefix(err.Error(), models.ErrInvalidBankSnapshot.Error()+": "))
+	}
+	h.logger.Errorf("Bank snapshot request failed: %v", err)
+	return errorResponse(c, fiber.StatusInternalServerError, fallback)
+}
+
+func snapshotID(c *fiber.Ctx) (int64, bool) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	return id, err == nil && id > 0
+}
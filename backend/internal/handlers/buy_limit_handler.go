@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/middleware"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// BuyLimitHandler handles buy limit tracker endpoints. Every route is scoped
+// to the caller's X-User-ID.
+type BuyLimitHandler struct {
+	buyLimitService  services.BuyLimitService
+	watchlistService services.WatchlistService
+	logger           *zap.SugaredLogger
+}
+
+// NewBuyLimitHandler creates a new buy limit handler.
+func NewBuyLimitHandler(
+	buyLimitService services.BuyLimitService,
+	watchlistService services.WatchlistService,
+	logger *zap.SugaredLogger,
+) *BuyLimitHandler {
+	return &BuyLimitHandler{
+		buyLimitService:  buyLimitService,
+		watchlistService: watchlistService,
+		logger:           logger,
+	}
+}
+
+// ListActive handles GET /api/v1/buy-limits.
+func (h *BuyLimitHandler) ListActive(c *fiber.Ctx) error {
+	statuses, err := h.buyLimitService.ListActive(c.Context(), middleware.UserID(c))
+	if err != nil {
+		h.logger.Errorf("Failed to list buy limit windows: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to list buy limits")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": statuses,
+		"meta": fiber.Map{
+			"count":          len(statuses),
+			"window_seconds": int(models.BuyLimitWindow.Seconds()),
+		},
+	})
+}
+
+// ListAvailable handles GET /api/v1/buy-limits/available[?ids=1,2,3|share=token].
+// Without ids or share it checks every item the user has logged purchases of.
+func (h *BuyLimitHandler) ListAvailable(c *fiber.Ctx) error {
+	var itemIDs []int
+	if c.Query("ids") != "" || c.Query("share") != "" {
+		var err error
+		itemIDs, err = itemIDsFromQuery(c, h.watchlistService, models.MaxBuyLimitItems)
+		if err != nil {
+			h.logger.Debugw("Rejected buy limit availability request", "error", err)
+			return respondQueryError(c, err, "failed to resolve items")
+		}
+		if len(itemIDs) == 0 {
+			return errorResponse(c, fiber.StatusBadRequest, "at least one item ID is required")
+		}
+	}
+
+	statuses, err := h.buyLimitService.ListAvailable(c.Context(), middleware.UserID(c), itemIDs)
+	if err != nil {
+		h.logger.Errorf("Failed to list available items: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to list available items")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": statuses,
+		"meta": fiber.Map{
+			"count": len(statuses),
+		},
+	})
+}
+
+// GetStatus handles GET /api/v1/buy-limits/:itemId.
+func (h *BuyLimitHandler) GetStatus(c *fiber.Ctx) error {
+	itemID, err := strconv.Atoi(c.Params("itemId"))
+	if err != nil || itemID <= 0 {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid item ID")
+	}
+
+	status, err := h.buyLimitService.GetStatus(c.Context(), middleware.UserID(c), itemID)
+	if err != nil {
+		h.logger.Errorf("Failed to get buy limit status for item %d: %v", itemID, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to get buy limit status")
+	}
+	if status == nil {
+		return errorResponse(c, fiber.StatusNotFound, "item not found")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": status,
+	})
+}
+
+// RecordPurchase handles POST /api/v1/buy-limits/purchases.
+func (h *BuyLimitHandler) RecordPurchase(c *fiber.Ctx) error {
+	var req models.BuyLimitPurchaseRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debugw("Invalid buy limit purchase body", "error", err)
+		return errorResponse(c, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	status, err := h.buyLimitService.RecordPurchase(c.Context(), middleware.UserID(c), req)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidBuyLimitPurchase) {
+			return errorResponse(c, fiber.StatusBadRequest, strings.TrimPrefix(err.Error(), models.ErrInvalidBuyLimitPurchase.Error()+": "))
+		}
+		h.logger.Errorf("Failed to record buy limit purchase: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to record purchase")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"data": status,
+	})
+}
+
+// DeletePurchase handles DELETE /api/v1/buy-limits/purchases/:id.
+func (h *BuyLimitHandler) DeletePurchase(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid purchase ID")
+	}
+
+	deleted, err := h.buyLimitService.DeletePurchase(c.Context(), middleware.UserID(c), id)
+	if err != nil {
+		h.logger.Errorf("Failed to delete buy limit purchase %d: %v", id, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to delete purchase")
+	}
+	if !deleted {
+		return errorResponse(c, fiber.StatusNotFound, "purchase not found")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
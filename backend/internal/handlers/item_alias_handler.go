@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/middleware"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// ItemAliasHandler handles item alias and name resolution endpoints.
+type ItemAliasHandler struct {
+	aliasService services.ItemAliasService
+	logger       *zap.SugaredLogger
+}
+
+// NewItemAliasHandler creates a new item alias handler.
+func NewItemAliasHandler(aliasService services.ItemAliasService, logger *zap.SugaredLogger) *ItemAliasHandler {
+	return &ItemAliasHandler{
+		aliasService: aliasService,
+		logger:       logger,
+	}
+}
+
+type itemAliasRequest struct {
+	Alias string `json:"alias"`
+}
+
+type reviewItemAliasRequest struct {
+	Status string `json:"status"`
+}
+
+// ResolveNames handles GET /api/v1/items/resolve?name=...[&name=...].
+// Each name may be an item name, an approved alias or a slug.
+func (h *ItemAliasHandler) ResolveNames(c *fiber.Ctx) error {
+	var names []string
+	for _, raw := range c.Context().QueryArgs().PeekMulti("name") {
+		name := strings.TrimSpace(string(raw))
+		if name == "" {
+			return errorResponse(c, fiber.StatusBadRequest, "name must not be empty")
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return errorResponse(c, fiber.StatusBadRequest, "name query parameter is required")
+	}
+	if len(names) > models.MaxResolveNames {
+		return errorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("maximum %d names per request", models.MaxResolveNames))
+	}
+
+	resolutions, err := h.aliasService.Resolve(c.Context(), names)
+	if err != nil {
+		h.logger.Errorf("Failed to resolve item names: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to resolve item names")
+	}
+
+	resolved := 0
+	for _, resolution := range resolutions {
+		if resolution.ItemID != nil {
+			resolved++
+		}
+	}
+	return c.JSON(fiber.Map{
+		"data": resolutions,
+		"meta": fiber.Map{
+			"count":    len(resolutions),
+			"resolved": resolved,
+		},
+	})
+}
+
+// GetItemAliases handles GET /api/v1/items/:id/aliases.
+func (h *ItemAliasHandler) GetItemAliases(c *fiber.Ctx) error {
+	itemID, ok := itemIDParam(c)
+	if !ok {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid item ID")
+	}
+
+	found, err := h.aliasService.ItemAliases(c.Context(), itemID)
+	if err != nil {
+		h.logger.Errorf("Failed to get aliases for item %d: %v", itemID, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to get item aliases")
+	}
+	if found == nil {
+		return errorResponse(c, fiber.StatusNotFound, "item not found")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": found,
+		"meta": fiber.Map{
+			"count": len(found),
+		},
+	})
+}
+
+// SubmitAlias handles POST /api/v1/items/:id/aliases. The alias waits for
+// an admin to review it.
+func (h *ItemAliasHandler) SubmitAlias(c *fiber.Ctx) error {
+	return h.createAlias(c, func(itemID int, alias string) (*models.ItemAlias, error) {
+		return h.aliasService.SubmitAlias(c.Context(), middleware.UserID(c), itemID, alias)
+	}, fiber.StatusAccepted)
+}
+
+// AddAlias handles POST /api/v1/admin/items/:id/aliases. The alias is
+// approved at once.
+func (h *ItemAliasHandler) AddAlias(c *fiber.Ctx) error {
+	return h.createAlias(c, func(itemID int, alias string) (*models.ItemAlias, error) {
+		return h.aliasService.AddAlias(c.Context(), itemID, alias)
+	}, fiber.StatusCreated)
+}
+
+func (h *ItemAliasHandler) createAlias(
+	c *fiber.Ctx,
+	create func(itemID int, alias string) (*models.ItemAlias, error),
+	status int,
+) error {
+	itemID, ok := itemIDParam(c)
+	if !ok {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid item ID")
+	}
+	var req itemAliasRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debugw("Invalid item alias request body", "error", err)
+		return errorResponse(c, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	alias, err := create(itemID, req.Alias)
+	if err != nil {
+		return h.respondAliasError(c, err, "failed to save item alias")
+	}
+	if alias == nil {
+		return errorResponse(c, fiber.StatusNotFound, "item not found")
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"data": alias,
+	})
+}
+
+// ListAliases handles GET /api/v1/admin/aliases[?status=pending|approved|rejected].
+func (h *ItemAliasHandler) ListAliases(c *fiber.Ctx) error {
+	found, err := h.aliasService.ListAliases(c.Context(), c.Query("status", models.ItemAliasStatusPending))
+	if err != nil {
+		return h.respondAliasError(c, err, "failed to list item aliases")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": found,
+		"meta": fiber.Map{
+			"count": len(found),
+		},
+	})
+}
+
+// ReviewAlias handles PUT /api/v1/admin/aliases/:id with {"status": "approved"|"rejected"}.
+func (h *ItemAliasHandler) ReviewAlias(c *fiber.Ctx) error {
+	id, ok := aliasIDParam(c)
+	if !ok {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid alias ID")
+	}
+	var req reviewItemAliasRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debugw("Invalid alias review request body", "error", err)
+		return errorResponse(c, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	alias, err := h.aliasService.ReviewAlias(c.Context(), id, req.Status)
+	if err != nil {
+		return h.respondAliasError(c, err, "failed to review item alias")
+	}
+	if alias == nil {
+		return errorResponse(c, fiber.StatusNotFound, "item alias not found")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": alias,
+	})
+}
+
+// DeleteAlias handles DELETE /api/v1/admin/aliases/:id.
+func (h *ItemAliasHandler) DeleteAlias(c *fiber.Ctx) error {
+	id, ok := aliasIDParam(c)
+	if !ok {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid alias ID")
+	}
+
+	deleted, err := h.aliasService.DeleteAlias(c.Context(), id)
+	if err != nil {
+		h.logger.Errorf("Failed to delete item alias %d: %v", id, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to delete item alias")
+	}
+	if !deleted {
+		return errorResponse(c, fiber.StatusNotFound, "item alias not found")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// respondAliasError maps item alias errors to responses. Anything
+// unexpected is logged and reported as a 500 with fallback.
+func (h *ItemAliasHandler) respondAliasError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, models.ErrInvalidItemAlias):
+		return errorResponse(c, fiber.StatusBadRequest, strings.TrimPrefix(err.Error(), models.ErrInvalidItemAlias.Error()+": "))
+	case errors.Is(err, models.ErrItemAliasTaken), errors.Is(err, models.ErrItemAliasLimit):
+		return errorResponse(c, fiber.StatusConflict, err.Error())
+	}
+	h.logger.Errorf("Item alias request failed: %v", err)
+	return errorResponse(c, fiber.StatusInternalServerError, fallback)
+}
+
+func aliasIDParam(c *fiber.Ctx) (uint, bool) {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	return uint(id), err == nil && id > 0
+}
@@ -31,7 +31,7 @@ func NewItemHandler(
 	}
 }
 
-// ListItems handles GET /api/v1/items.
+// ListItems handles GET /api/v1/items[?category=herb&tag=members].
 func (h *ItemHandler) ListItems(c *fiber.Ctx) error {
 	ctx := c.Context()
 
@@ -53,6 +53,13 @@ func (h *ItemHandler) ListItems(c *fiber.Ctx) error {
 		return errorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
+	category, tags, err := tagFiltersFromQuery(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+	params.Category = category
+	params.Tags = tags
+
 	// Compute offset from page/limit for repository queries.
 	params.Offset = (params.Page - 1) * params.Limit
 
@@ -116,7 +123,8 @@ func (h *ItemHandler) GetItemByID(c *fiber.Ctx) error {
 	})
 }
 
-// SearchItems handles GET /api/v1/items/search.
+// SearchItems handles GET /api/v1/items/search[?mode=fuzzy|exact|prefix&category=...&tag=...].
+// Results are ranked best match first and carry their score.
 func (h *ItemHandler) SearchItems(c *fiber.Ctx) error {
 	ctx := c.Context()
 
@@ -130,10 +138,17 @@ func (h *ItemHandler) SearchItems(c *fiber.Ctx) error {
 
 	params := models.ItemSearchParams{
 		Query:   query,
+		Mode:    c.Query("mode", models.ItemSearchModeFuzzy),
 		Members: utils.ParseNullableBool(c.Query("members")),
 		Limit:   c.QueryInt("limit", 50),
 	}
 
+	switch params.Mode {
+	case models.ItemSearchModeFuzzy, models.ItemSearchModeExact, models.ItemSearchModePrefix:
+	default:
+		return errorResponse(c, fiber.StatusBadRequest, "mode must be fuzzy, exact or prefix")
+	}
+
 	// Validate limit
 	if params.Limit < 1 || params.Limit > 200 {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -141,6 +156,13 @@ func (h *ItemHandler) SearchItems(c *fiber.Ctx) error {
 		})
 	}
 
+	category, tags, err := tagFiltersFromQuery(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+	params.Category = category
+	params.Tags = tags
+
 	// Search items
 	items, err := h.itemService.SearchItems(ctx, params)
 	if err != nil {
@@ -154,6 +176,7 @@ func (h *ItemHandler) SearchItems(c *fiber.Ctx) error {
 		"data": items,
 		"meta": fiber.Map{
 			"query": params.Query,
+			"mode":  params.Mode,
 			"count": len(items),
 			"limit": params.Limit,
 		},
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// ItemTagHandler handles item category and tag endpoints.
+type ItemTagHandler struct {
+	tagService services.ItemTagService
+	logger     *zap.SugaredLogger
+}
+
+// NewItemTagHandler creates a new item tag handler.
+func NewItemTagHandler(tagService services.ItemTagService, logger *zap.SugaredLogger) *ItemTagHandler {
+	return &ItemTagHandler{
+		tagService: tagService,
+		logger:     logger,
+	}
+}
+
+// ListTags handles GET /api/v1/tags.
+func (h *ItemTagHandler) ListTags(c *fiber.Ctx) error {
+	tags, err := h.tagService.ListTags(c.Context())
+	if err != nil {
+		h.logger.Errorf("Failed to list tags: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to list tags")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": tags,
+		"meta": fiber.Map{
+			"count":      len(tags),
+			"categories": h.tagService.Categories(),
+		},
+	})
+}
+
+// GetItemTags handles GET /api/v1/items/:id/tags.
+func (h *ItemTagHandler) GetItemTags(c *fiber.Ctx) error {
+	itemID, ok := itemIDParam(c)
+	if !ok {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid item ID")
+	}
+
+	tags, err := h.tagService.ItemTags(c.Context(), itemID)
+	if err != nil {
+		h.logger.Errorf("Failed to get tags for item %d: %v", itemID, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to get item tags")
+	}
+	if tags == nil {
+		return errorResponse(c, fiber.StatusNotFound, "item not found")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": tags,
+		"meta": fiber.Map{
+			"count": len(tags),
+		},
+	})
+}
+
+// AddItemTag handles PUT /api/v1/admin/items/:id/tags/:tag.
+func (h *ItemTagHandler) AddItemTag(c *fiber.Ctx) error {
+	itemID, ok := itemIDParam(c)
+	if !ok {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid item ID")
+	}
+
+	tag, err := h.tagService.AddTag(c.Context(), itemID, c.Params("tag"))
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidItemTag) {
+			return errorResponse(c, fiber.StatusBadRequest, strings.TrimPrefix(err.Error(), models.ErrInvalidItemTag.Error()+": "))
+		}
+		h.logger.Errorf("Failed to tag item %d: %v", itemID, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to tag item")
+	}
+	if tag == nil {
+		return errorResponse(c, fiber.StatusNotFound, "item not found")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": tag,
+	})
+}
+
+// RemoveItemTag handles DELETE /api/v1/admin/items/:id/tags/:tag.
+func (h *ItemTagHandler) RemoveItemTag(c *fiber.Ctx) error {
+	itemID, ok := itemIDParam(c)
+	if !ok {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid item ID")
+	}
+
+	removed, err := h.tagService.RemoveTag(c.Context(), itemID, c.Params("tag"))
+	if err != nil {
+		h.logger.Errorf("Failed to untag item %d: %v", itemID, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to remove item tag")
+	}
+	if !removed {
+		return errorResponse(c, fiber.StatusNotFound, "item tag not found")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ClassifyItems handles POST /api/v1/admin/tags/classify.
+func (h *ItemTagHandler) ClassifyItems(c *fiber.Ctx) error {
+	assigned, err := h.tagService.ClassifyItems(c.Context())
+	if err != nil {
+		h.logger.Errorf("Failed to classify items: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to classify items")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": fiber.Map{
+			"assigned": assigned,
+		},
+	})
+}
+
+// itemIDParam parses the :id route parameter as a positive item ID.
+func itemIDParam(c *fiber.Ctx) (int, bool) {
+	itemID, err := strconv.Atoi(c.Params("id"))
+	return itemID, err == nil && itemID > 0
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/middleware"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// MarketIndexHandler handles market index endpoints. The read routes serve
+// builtin indices, or the caller's custom indices when mounted behind
+// middleware.RequireUserID.
+type MarketIndexHandler struct {
+	marketIndexService services.MarketIndexService
+	logger             *zap.SugaredLogger
+}
+
+// NewMarketIndexHandler creates a new market index handler.
+func NewMarketIndexHandler(marketIndexService services.MarketIndexService, logger *zap.SugaredLogger) *MarketIndexHandler {
+	return &MarketIndexHandler{
+		marketIndexService: marketIndexService,
+		logger:             logger,
+	}
+}
+
+// createMarketIndexRequest is the body of POST /api/v1/market/index/custom.
+type createMarketIndexRequest struct {
+	Name      string                      `json:"name"`
+	Weighting models.MarketIndexWeighting `json:"weighting"`
+	ItemIDs   []int                       `json:"itemIds"`
+}
+
+// ListIndices handles GET /api/v1/market/index and
+// GET /api/v1/market/index/custom.
+func (h *MarketIndexHandler) ListIndices(c *fiber.Ctx) error {
+	indices, err := h.marketIndexService.ListIndices(c.Context(), middleware.UserID(c))
+	if err != nil {
+		h.logger.Errorf("Failed to list market indices: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to list market indices")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": indices,
+		"meta": fiber.Map{
+			"count": len(indices),
+		},
+	})
+}
+
+// GetIndex handles GET /api/v1/market/index/:id.
+func (h *MarketIndexHandler) GetIndex(c *fiber.Ctx) error {
+	index, err := h.index(c)
+	if err != nil || index == nil {
+		return err
+	}
+
+	summary, err := h.marketIndexService.Summary(c.Context(), *index)
+	if err != nil {
+		h.logger.Errorf("Failed to get market index %s: %v", index.ID, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to get market index")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": summary,
+	})
+}
+
+// GetHistory handles GET /api/v1/market/index/:id/history[?from=...&to=...].
+func (h *MarketIndexHandler) GetHistory(c *fiber.Ctx) error {
+	var from, to time.Time
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := parseTimeParam(raw)
+		if err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, "invalid from timestamp")
+		}
+		from = parsed
+	}
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := parseTimeParam(raw)
+		if err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, "invalid to timestamp")
+		}
+		to = parsed
+	}
+
+	index, err := h.index(c)
+	if err != nil || index == nil {
+		return err
+	}
+
+	history, err := h.marketIndexService.History(c.Context(), *index, from, to)
+	if err != nil {
+		return h.respondIndexError(c, err, "failed to get market index history")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": history,
+		"meta": fiber.Map{
+			"count": len(history.Points),
+		},
+	})
+}
+
+// GetConstituents handles GET /api/v1/market/index/:id/constituents.
+func (h *MarketIndexHandler) GetConstituents(c *fiber.Ctx) error {
+	index, err := h.index(c)
+	if err != nil || index == nil {
+		return err
+	}
+
+	constituents, err := h.marketIndexService.Constituents(c.Context(), *index)
+	if err != nil {
+		h.logger.Errorf("Failed to get market index %s constituents: %v", index.ID, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to get market index constituents")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": constituents,
+		"meta": fiber.Map{
+			"count": len(constituents.Constituents),
+		},
+	})
+}
+
+// CreateCustomIndex handles POST /api/v1/market/index/custom.
+func (h *MarketIndexHandler) CreateCustomIndex(c *fiber.Ctx) error {
+	var req createMarketIndexRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debugw("Invalid market index request body", "error", err)
+		return errorResponse(c, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	index, err := h.marketIndexService.CreateCustomIndex(c.Context(), middleware.UserID(c), models.MarketIndex{
+		Name:      req.Name,
+		Weighting: req.Weighting,
+		ItemIDs:   req.ItemIDs,
+	})
+	if err != nil {
+		return h.respondIndexError(c, err, "failed to create market index")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"data": index,
+	})
+}
+
+// DeleteCustomIndex handles DELETE /api/v1/market/index/custom/:id.
+func (h *MarketIndexHandler) DeleteCustomIndex(c *fiber.Ctx) error {
+	id := c.Params("id")
+	deleted, err := h.marketIndexService.DeleteCustomIndex(c.Context(), middleware.UserID(c), id)
+	if err != nil {
+		h.logger.Errorf("Failed to delete market index %s: %v", id, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to delete market index")
+	}
+	if !deleted {
+		return errorResponse(c, fiber.StatusNotFound, "market index not found")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// index resolves the :id route parameter. When it returns a nil index the
+// error response has already been written.
+func (h *MarketIndexHandler) index(c *fiber.Ctx) (*models.MarketIndex, error) {
+	id := c.Params("id")
+	index, err := h.marketIndexService.GetIndex(c.Context(), middleware.UserID(c), id)
+	if err != nil {
+		h.logger.Errorf("Failed to get market index %s: %v", id, err)
+		return nil, errorResponse(c, fiber.StatusInternalServerError, "failed to get market index")
+	}
+	if index == nil {
+		return nil, errorResponse(c, fiber.StatusNotFound, "market index not found")
+	}
+	return index, nil
+}
+
+func (h *MarketIndexHandler) respondIndexError(c *fiber.Ctx, err error, fallback string) error {
+	if errors.Is(err, models.ErrInvalidMarketIndex) {
+		return errorResponse(c, fiber.StatusBadRequest, strings.TrimPrefix(err.Error(), models.ErrInvalidMarketIndex.Error()+": "))
+	}
+	h.logger.Errorf("Market index request failed: %v", err)
+	return errorResponse(c, fiber.StatusInternalServerError, fallback)
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+	"github.com/guavi/osrs-ge-tracker/internal/tagging"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
+)
+
+// MarketTableHandler handles the dashboard's market table.
+type MarketTableHandler struct {
+	marketTableService services.MarketTableService
+	logger             *zap.SugaredLogger
+}
+
+// NewMarketTableHandler creates a new market table handler.
+func NewMarketTableHandler(marketTableService services.MarketTableService, logger *zap.SugaredLogger) *MarketTableHandler {
+	return &MarketTableHandler{
+		marketTableService: marketTableService,
+		logger:             logger,
+	}
+}
+
+// GetTable handles GET /api/v1/market/table.
+// Query: page, limit, sort_by, order, q, members, category, and
+// min_<column>/max_<column> for numeric columns. The response carries an
+// ETag that changes with every refresh of the table, so a client polling
+// with If-None-Match gets 304 until the next price sync.
+func (h *MarketTableHandler) GetTable(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	params, err := marketTableParams(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	updatedAt, err := h.marketTableService.LastUpdated(ctx)
+	if err != nil {
+		h.logger.Errorf("Failed to get market table refresh time: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to fetch market table")
+	}
+	var asOf time.Time
+	if updatedAt != nil {
+		asOf = *updatedAt
+	}
+
+	c.Set(fiber.HeaderETag, marketTableETag(asOf, params))
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	if c.Get(fiber.HeaderIfNoneMatch) != "" && c.Fresh() {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	rows, total, err := h.marketTableService.Query(ctx, params)
+	if err != nil {
+		h.logger.Errorf("Failed to query market table: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to fetch market table")
+	}
+
+	totalPages := (total + int64(params.Limit) - 1) / int64(params.Limit)
+	meta := fiber.Map{
+		"page":        params.Page,
+		"limit":       params.Limit,
+		"total":       total,
+		"total_pages": totalPages,
+		"sort_by":     params.SortBy,
+		"order":       params.Order,
+	}
+	if updatedAt != nil {
+		meta["asOf"] = asOf
+	}
+	return c.JSON(fiber.Map{
+		"data": rows,
+		"meta": meta,
+	})
+}
+
+// marketTableParams parses and validates the market table query. The table
+// is sorted by 24h volume by default; numeric columns sort descending and
+// the rest ascending unless order is given.
+func marketTableParams(c *fiber.Ctx) (models.MarketTableParams, error) {
+	params := models.MarketTableParams{
+		Page:    c.QueryInt("page", 1),
+		Limit:   c.QueryInt("limit", models.DefaultMarketTableLimit),
+		Members: utils.ParseNullableBool(c.Query("members")),
+		SortBy:  c.Query("sort_by", "volume_24h"),
+		Query:   strings.TrimSpace(c.Query("q")),
+	}
+	if params.Page < MinPage {
+		return params, errors.New("page must be greater than 0")
+	}
+	if params.Limit < 1 || params.Limit > models.MaxMarketTableLimit {
+		return params, fmt.Errorf("limit must be between 1 and %d", models.MaxMarketTableLimit)
+	}
+	params.Offset = (params.Page - 1) * params.Limit
+
+	numeric, ok := models.MarketTableColumns[params.SortBy]
+	if !ok {
+		return params, errors.New("invalid sort_by field")
+	}
+	params.Order = "asc"
+	if numeric {
+		params.Order = "desc"
+	}
+	params.Order = c.Query("order", params.Order)
+	if !ValidSortOrders[params.Order] {
+		return params, errors.New("order must be 'asc' or 'desc'")
+	}
+
+	if utf8.RuneCountInString(params.Query) > models.MaxMarketTableQuery {
+		return params, fmt.Errorf("q must be at most %d characters", models.MaxMarketTableQuery)
+	}
+	if raw := c.Query("category"); raw != "" {
+		category, err := tagging.NormalizeTag(raw)
+		if err != nil {
+			return params, errors.New("invalid category")
+		}
+		params.Category = category
+	}
+
+	var err error
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		name := string(key)
+		bounds := &params.Min
+		column, isMin := strings.CutPrefix(name, "min_")
+		if !isMin {
+			var isMax bool
+			if column, isMax = strings.CutPrefix(name, "max_"); !isMax {
+				return
+			}
+			bounds = &params.Max
+		}
+		if err != nil {
+			return
+		}
+		if !models.MarketTableColumns[column] {
+			err = fmt.Errorf("cannot filter on '%s'", column)
+			return
+		}
+		bound, parseErr := strconv.ParseFloat(string(value), 64)
+		if parseErr != nil || math.IsNaN(bound) || math.IsInf(bound, 0) {
+			err = fmt.Errorf("%s must be a number", name)
+			return
+		}
+		if *bounds == nil {
+			*bounds = make(map[string]float64)
+		}
+		(*bounds)[column] = bound
+	})
+	return params, err
+}
+
+// marketTableETag identifies a page of the table as of one refresh. It is
+// weak because rows are re-encoded on every request.
+func marketTableETag(asOf time.Time, params models.MarketTableParams) string {
+	encoded, _ := json.Marshal(params)
+	sum := sha256.Sum256(append(encoded, asOf.UTC().Format(time.RFC3339Nano)...))
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/middleware"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// Paper trading list size bounds.
+const (
+	defaultPaperListLimit = 50
+	maxPaperListLimit     = 500
+)
+
+// PaperTradingHandler handles paper trading account endpoints.
+type PaperTradingHandler struct {
+	paperService services.PaperTradingService
+	logger       *zap.SugaredLogger
+}
+
+// NewPaperTradingHandler creates a new paper trading handler.
+func NewPaperTradingHandler(paperService services.PaperTradingService, logger *zap.SugaredLogger) *PaperTradingHandler {
+	return &PaperTradingHandler{
+		paperService: paperService,
+		logger:       logger,
+	}
+}
+
+// createPaperAccountRequest is the body of POST /api/v1/paper/accounts.
+type createPaperAccountRequest struct {
+	Name            string `json:"name"`
+	StartingBalance int64  `json:"startingBalance"`
+}
+
+// CreateAccount handles POST /api/v1/paper/accounts. The account belongs to
+// the caller's X-User-ID, which is capped at models.MaxPaperAccountsPerUser
+// accounts.
+func (h *PaperTradingHandler) CreateAccount(c *fiber.Ctx) error {
+	var req createPaperAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debugw("Invalid paper account request body", "error", err)
+		return errorResponse(c, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	account, err := h.paperService.CreateAccount(c.Context(), middleware.UserID(c), req.Name, req.StartingBalance)
+	if errors.Is(err, models.ErrPaperAccountLimit) {
+		return errorResponse(c, fiber.StatusConflict,
+			fmt.Sprintf("at most %d paper accounts can be opened per user", models.MaxPaperAccountsPerUser))
+	}
+	if err != nil {
+		return h.respondPaperError(c, err, "failed to create paper account")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"data": account,
+	})
+}
+
+// GetAccount handles GET /api/v1/paper/accounts/:id.
+func (h *PaperTradingHandler) GetAccount(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	summary, err := h.paperService.GetAccount(c.Context(), id)
+	if err != nil {
+		h.logger.Errorf("Failed to get paper account %s: %v", id, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to get paper account")
+	}
+	if summary == nil {
+		return errorResponse(c, fiber.StatusNotFound, models.ErrPaperAccountNotFound.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"data": summary,
+	})
+}
+
+// ListPositions handles GET /api/v1/paper/accounts/:id/positions.
+func (h *PaperTradingHandler) ListPositions(c *fiber.Ctx) error {
+	positions, err := h.paperService.ListPositions(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.respondPaperError(c, err, "failed to list paper positions")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": positions,
+		"meta": fiber.Map{
+			"count": len(positions),
+		},
+	})
+}
+
+// ListOffers handles GET /api/v1/paper/accounts/:id/offers?status=open&limit=50.
+func (h *PaperTradingHandler) ListOffers(c *fiber.Ctx) error {
+	status := models.PaperOfferStatus(c.Query("status"))
+	if status != "" && !status.IsValid() {
+		return errorResponse(c, fiber.StatusBadRequest, "status must be one of: open, filled, cancelled")
+	}
+	limit, err := parsePaperListLimit(c.Query("limit"))
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	offers, err := h.paperService.ListOffers(c.Context(), c.Params("id"), status, limit)
+	if err != nil {
+		return h.respondPaperError(c, err, "failed to list paper offers")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": offers,
+		"meta": fiber.Map{
+			"count":  len(offers),
+			"status": status,
+			"limit":  limit,
+		},
+	})
+}
+
+// PlaceOffer handles POST /api/v1/paper/accounts/:id/offers.
+func (h *PaperTradingHandler) PlaceOffer(c *fiber.Ctx) error {
+	var req models.PaperOfferRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debugw("Invalid paper offer request body", "error", err)
+		return errorResponse(c, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	offer, err := h.paperService.PlaceOffer(c.Context(), c.Params("id"), req)
+	if err != nil {
+		return h.respondPaperError(c, err, "failed to place paper offer")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"data": offer,
+	})
+}
+
+// CancelOffer handles DELETE /api/v1/paper/accounts/:id/offers/:offerId.
+func (h *PaperTradingHandler) CancelOffer(c *fiber.Ctx) error {
+	offerID, err := strconv.ParseInt(c.Params("offerId"), 10, 64)
+	if err != nil || offerID <= 0 {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid offer ID")
+	}
+
+	offer, err := h.paperService.CancelOffer(c.Context(), c.Params("id"), offerID)
+	if err != nil {
+		return h.respondPaperError(c, err, "failed to cancel paper offer")
+	}
+	if offer == nil {
+		return errorResponse(c, fiber.StatusNotFound, "offer not found")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": offer,
+	})
+}
+
+// ListFills handles GET /api/v1/paper/accounts/:id/fills?limit=50.
+func (h *PaperTradingHandler) ListFills(c *fiber.Ctx) error {
+	limit, err := parsePaperListLimit(c.Query("limit"))
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	fills, err := h.paperService.ListFills(c.Context(), c.Params("id"), limit)
+	if err != nil {
+		return h.respondPaperError(c, err, "failed to list paper fills")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": fills,
+		"meta": fiber.Map{
+			"count": len(fills),
+			"limit": limit,
+		},
+	})
+}
+
+// respondPaperError maps paper trading errors to responses. Anything
+// unexpected is logged and reported as a 500 with fallback.
+func (h *PaperTradingHandler) respondPaperError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, models.ErrInvalidPaperRequest):
+		return errorResponse(c, fiber.StatusBadRequest, strings.TrimPrefix(err.Error(), models.ErrInvalidPaperRequest.Error()+": "))
+	case errors.Is(err, models.ErrPaperAccountNotFound):
+		return errorResponse(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, models.ErrPaperInsufficientFunds),
+		errors.Is(err, models.ErrPaperInsufficientItems),
+		errors.Is(err, models.ErrPaperOfferSlotsFull),
+		errors.Is(err, models.ErrPaperOfferClosed):
+		return errorResponse(c, fiber.StatusConflict, err.Error())
+	}
+	h.logger.Errorf("Paper trading request failed: %v", err)
+	return errorResponse(c, fiber.StatusInternalServerError, fallback)
+}
+
+// parsePaperListLimit parses the limit query parameter of paper trading lists.
+func parsePaperListLimit(raw string) (int, error) {
+	if raw == "" {
+		return defaultPaperListLimit, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 1 || limit > maxPaperListLimit {
+		return 0, fmt.Errorf("limit must be between 1 and %d", maxPaperListLimit)
+	}
+	return limit, nil
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+	"github.com/guavi/osrs-ge-tracker/internal/tagging"
+)
+
+// parseItemIDList parses a comma-separated list of item IDs, rejecting lists
+// longer than maxItems. Duplicate IDs are dropped while preserving order.
+func parseItemIDList(raw string, maxItems int) ([]int, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) > maxItems {
+		return nil, fmt.Errorf("maximum %d items per request", maxItems)
+	}
+
+	seen := make(map[int]struct{}, len(parts))
+	itemIDs := make([]int, 0, len(parts))
+	for _, part := range parts {
+		trimmed := strings.TrimSpace(part)
+		if trimmed == "" {
+			continue
+		}
+		id, err := strconv.Atoi(trimmed)
+		if err != nil || id < 0 {
+			return nil, fmt.Errorf("invalid item ID: %s", part)
+		}
+		if _, dup := seen[id]; dup {
+			continue
+		}
+		seen[id] = struct{}{}
+		itemIDs = append(itemIDs, id)
+	}
+	return itemIDs, nil
+}
+
+// tagFiltersFromQuery reads the category and tag query parameters. Tags are
+// comma-separated and may repeat; duplicates are dropped.
+func tagFiltersFromQuery(c *fiber.Ctx) (string, []string, error) {
+	var category string
+	if raw := c.Query("category"); raw != "" {
+		normalized, err := tagging.NormalizeTag(raw)
+		if err != nil {
+			return "", nil, errors.New("invalid category")
+		}
+		category = normalized
+	}
+
+	var tags []string
+	seen := make(map[string]struct{})
+	for _, raw := range c.Context().QueryArgs().PeekMulti("tag") {
+		for _, part := range strings.Split(string(raw), ",") {
+			if strings.TrimSpace(part) == "" {
+				continue
+			}
+			tag, err := tagging.NormalizeTag(part)
+			if err != nil {
+				return "", nil, fmt.Errorf("invalid tag: %s", part)
+			}
+			if _, dup := seen[tag]; dup {
+				continue
+			}
+			seen[tag] = struct{}{}
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > models.MaxItemTagFilters {
+		return "", nil, fmt.Errorf("maximum %d tags per request", models.MaxItemTagFilters)
+	}
+	return category, tags, nil
+}
+
+// parseTimeParam parses a timestamp query parameter. Accepted formats are
+// RFC 3339, "2006-01-02 15:04[:05]" and "2006-01-02" (all UTC when no offset
+// is given) and unix seconds.
+func parseTimeParam(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if seconds, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+
+	layouts := []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04",
+		"2006-01-02 15:04:05",
+		"2006-01-02 15:04",
+		"2006-01-02",
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", raw)
+}
+
+// parseDaysParam parses the query parameter name given as days ("28" or "28d"),
+// returning def when raw is empty and rejecting values outside [minDays, maxDays].
+func parseDaysParam(name, raw string, def, minDays, maxDays int) (int, error) {
+	raw = strings.TrimSuffix(strings.TrimSpace(raw), "d")
+	if raw == "" {
+		return def, nil
+	}
+	days, err := strconv.Atoi(raw)
+	if err != nil || days < minDays || days > maxDays {
+		return 0, fmt.Errorf("%s must be between %dd and %dd", name, minDays, maxDays)
+	}
+	return days, nil
+}
+
+// itemIDsFromQuery resolves the item IDs for a multi-item request from either
+// the "ids" list or a watchlist "share" token. Errors are fiber errors carrying
+// the HTTP status to respond with.
+func itemIDsFromQuery(c *fiber.Ctx, watchlists services.WatchlistService, maxItems int) ([]int, error) {
+	idsStr := c.Query("ids")
+	token := c.Query("share")
+
+	switch {
+	case idsStr != "" && token != "":
+		return nil, fiber.NewError(fiber.StatusBadRequest, "provide either ids or share, not both")
+	case idsStr != "":
+		itemIDs, err := parseItemIDList(idsStr, maxItems)
+		if err != nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		return itemIDs, nil
+	case token != "":
+		if watchlists == nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "watchlist shares are not supported")
+		}
+		itemIDs, err := watchlists.GetShareItemIDs(c.Context(), token)
+		switch {
+		case errors.Is(err, models.ErrShareNotFound), errors.Is(err, models.ErrShareExpired):
+			return nil, fiber.NewError(fiber.StatusNotFound, err.Error())
+		case errors.Is(err, models.ErrInvalidShareToken):
+			return nil, fiber.NewError(fiber.StatusBadRequest, "invalid share token format")
+		case err != nil:
+			return nil, fmt.Errorf("resolve watchlist share: %w", err)
+		}
+		if len(itemIDs) > maxItems {
+			return nil, fiber.NewError(fiber.StatusBadRequest,
+				fmt.Sprintf("maximum %d items per request", maxItems))
+		}
+		return itemIDs, nil
+	default:
+		return nil, fiber.NewError(fiber.StatusBadRequest, "ids or share query parameter is required")
+	}
+}
+
+// denominationFromQuery reads the optional "denomination" query parameter,
+// defaulting to gp. Errors are fiber errors carrying the HTTP status to
+// respond with.
+func denominationFromQuery(c *fiber.Ctx, denominations services.DenominationService) (models.Denomination, error) {
+	d := models.Denomination(strings.ToLower(c.Query("denomination", string(models.DenominationGP))))
+	switch {
+	case !d.IsValid():
+		return "", fiber.NewError(fiber.StatusBadRequest, "denomination must be gp, bonds or real")
+	case d != models.DenominationGP && denominations == nil:
+		return "", fiber.NewError(fiber.StatusBadRequest, "denominations are not supported")
+	}
+	return d, nil
+}
+
+// denominationError maps a DenominationService error to a fiber error:
+// unsupported denominations are a 400 and a missing bond price a 503.
+// Anything else is returned as is.
+func denominationError(err error) error {
+	switch {
+	case errors.Is(err, models.ErrInvalidDenomination):
+		return fiber.NewError(fiber.StatusBadRequest,
+			strings.TrimPrefix(err.Error(), models.ErrInvalidDenomination.Error()+": "))
+	case errors.Is(err, models.ErrBondPriceUnavailable):
+		return fiber.NewError(fiber.StatusServiceUnavailable, "no bond price is available to convert with")
+	}
+	return err
+}
+
+// respondQueryError writes the error produced by a query helper, mapping fiber
+// errors to their status and everything else to a 500 with fallbackMessage.
+func respondQueryError(c *fiber.Ctx, err error, fallbackMessage string) error {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return errorResponse(c, fiberErr.Code, fiberErr.Message)
+	}
+	return errorResponse(c, fiber.StatusInternalServerError, fallbackMessage)
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/middleware"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// PortfolioHandler handles trade journal and portfolio P&L endpoints. Every
+// route is scoped to the caller's X-User-ID.
+type PortfolioHandler struct {
+	portfolioService services.PortfolioService
+	logger           *zap.SugaredLogger
+}
+
+// NewPortfolioHandler creates a new portfolio handler.
+func NewPortfolioHandler(portfolioService services.PortfolioService, logger *zap.SugaredLogger) *PortfolioHandler {
+	return &PortfolioHandler{
+		portfolioService: portfolioService,
+		logger:           logger,
+	}
+}
+
+// journalEntryRequest is the body of POST /api/v1/portfolio/journal.
+type journalEntryRequest struct {
+	TradedAt time.Time        `json:"tradedAt"`
+	Side     models.TradeSide `json:"side"`
+	Note     string           `json:"note"`
+	Quantity int64            `json:"quantity"`
+	Price    int64            `json:"price"`
+	ItemID   int              `json:"itemId"`
+}
+
+// GetSummary handles GET /api/v1/portfolio/summary.
+func (h *PortfolioHandler) GetSummary(c *fiber.Ctx) error {
+	userID := middleware.UserID(c)
+
+	summary, err := h.portfolioService.GetSummary(c.Context(), userID)
+	if err != nil {
+		h.logger.Errorf("Failed to compute portfolio summary for %s: %v", userID, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to compute portfolio summary")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": summary,
+		"meta": fiber.Map{
+			"item_count": len(summary.Items),
+			"method":     "fifo",
+		},
+	})
+}
+
+// ListJournal handles GET /api/v1/portfolio/journal?itemId=4151&from=...&to=...&limit=100&offset=0.
+func (h *PortfolioHandler) ListJournal(c *fiber.Ctx) error {
+	params := models.JournalListParams{Limit: models.DefaultJournalPageSize}
+
+	if raw := c.Query("itemId"); raw != "" {
+		itemID, err := strconv.Atoi(raw)
+		if err != nil || itemID <= 0 {
+			return errorResponse(c, fiber.StatusBadRequest, "invalid itemId")
+		}
+		params.ItemID = &itemID
+	}
+	for name, dest := range map[string]**time.Time{"from": &params.From, "to": &params.To} {
+		if raw := c.Query(name); raw != "" {
+			t, err := parseTimeParam(raw)
+			if err != nil {
+				return errorResponse(c, fiber.StatusBadRequest, "invalid "+name+" timestamp")
+			}
+			*dest = &t
+		}
+	}
+	if params.From != nil && params.To != nil && params.From.After(*params.To) {
+		return errorResponse(c, fiber.StatusBadRequest, "from must not be after to")
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > models.MaxJournalPageSize {
+			return errorResponse(c, fiber.StatusBadRequest, "limit must be between 1 and 1000")
+		}
+		params.Limit = limit
+	}
+	if raw := c.Query("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return errorResponse(c, fiber.StatusBadRequest, "offset must not be negative")
+		}
+		params.Offset = offset
+	}
+
+	entries, total, err := h.portfolioService.ListEntries(c.Context(), middleware.UserID(c), params)
+	if err != nil {
+		h.logger.Errorf("Failed to list journal entries: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to list journal entries")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": entries,
+		"meta": fiber.Map{
+			"total":  total,
+			"limit":  params.Limit,
+			"offset": params.Offset,
+		},
+	})
+}
+
+// AddJournalEntry handles POST /api/v1/portfolio/journal.
+func (h *PortfolioHandler) AddJournalEntry(c *fiber.Ctx) error {
+	var req journalEntryRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debugw("Invalid journal entry request body", "error", err)
+		return errorResponse(c, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	entry, err := h.portfolioService.AddEntry(c.Context(), middleware.UserID(c), models.TradeJournalEntry{
+		ItemID:   req.ItemID,
+		Side:     req.Side,
+		Quantity: req.Quantity,
+		Price:    req.Price,
+		TradedAt: req.TradedAt,
+		Note:     req.Note,
+	})
+	if err != nil {
+		return h.respondJournalError(c, err, "failed to add journal entry")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"data": entry,
+	})
+}
+
+// DeleteJournalEntry handles DELETE /api/v1/portfolio/journal/:id.
+func (h *PortfolioHandler) DeleteJournalEntry(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid journal entry ID")
+	}
+
+	deleted, err := h.portfolioService.DeleteEntry(c.Context(), middleware.UserID(c), id)
+	if err != nil {
+		h.logger.Errorf("Failed to delete journal entry %d: %v", id, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to delete journal entry")
+	}
+	if !deleted {
+		return errorResponse(c, fiber.StatusNotFound, "journal entry not found")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ImportJournal handles POST /api/v1/portfolio/journal/import. The CSV is
+// either the raw request body or a multipart upload in the "file" field.
+func (h *PortfolioHandler) ImportJournal(c *fiber.Ctx) error {
+	var body io.Reader
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, "multipart upload must include a file field")
+		}
+		file, err := header.Open()
+		if err != nil {
+			h.logger.Errorf("Failed to open uploaded journal: %v", err)
+			return errorResponse(c, fiber.StatusBadRequest, "failed to read uploaded file")
+		}
+		defer file.Close()
+		body = file
+	} else {
+		body = bytes.NewReader(c.Body())
+	}
+
+	imported, err := h.portfolioService.ImportCSV(c.Context(), middleware.UserID(c), body)
+	if err != nil {
+		return h.respondJournalError(c, err, "failed to import journal")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"data": fiber.Map{
+			"imported": imported,
+		},
+	})
+}
+
+// ExportJournal handles GET /api/v1/portfolio/journal/export.
+func (h *PortfolioHandler) ExportJournal(c *fiber.Ctx) error {
+	var buf bytes.Buffer
+	if err := h.portfolioService.ExportCSV(c.Context(), middleware.UserID(c), &buf); err != nil {
+		h.logger.Errorf("Failed to export journal: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to export journal")
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Attachment("trade-journal.csv")
+	return c.Send(buf.Bytes())
+}
+
+// respondJournalError maps validation errors to 400 and logs anything else as a 500.
+func (h *PortfolioHandler) respondJournalError(c *fiber.Ctx, err error, fallback string) error {
+	if errors.Is(err, models.ErrInvalidJournalEntry) {
+		return errorResponse(c, fiber.StatusBadRequest, strings.TrimPrefix(err.Error(), models.ErrInvalidJournalEntry.Error()+": "))
+	}
+	h.logger.Errorf("Journal request failed: %v", err)
+	return errorResponse(c, fiber.StatusInternalServerError, fallback)
+}
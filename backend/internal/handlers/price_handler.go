@@ -1,27 +1,41 @@
 package handlers
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
 
 	"github.com/guavi/osrs-ge-tracker/internal/models"
 	"github.com/guavi/osrs-ge-tracker/internal/services"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
 )
 
 // PriceHandler handles price-related endpoints.
 type PriceHandler struct {
-	priceService services.PriceService
-	logger       *zap.SugaredLogger
+	priceService        services.PriceService
+	watchlistService    services.WatchlistService
+	denominationService services.DenominationService
+	logger              *zap.SugaredLogger
 }
 
 // NewPriceHandler creates a new price handler.
-func NewPriceHandler(priceService services.PriceService, logger *zap.SugaredLogger) *PriceHandler {
+// watchlistService may be nil, in which case share-token lookups are rejected;
+// denominationService may be nil, in which case only gp amounts are served.
+func NewPriceHandler(
+	priceService services.PriceService,
+	watchlistService services.WatchlistService,
+	denominationService services.DenominationService,
+	logger *zap.SugaredLogger,
+) *PriceHandler {
 	return &PriceHandler{
-		priceService: priceService,
-		logger:       logger,
+		priceService:        priceService,
+		watchlistService:    watchlistService,
+		denominationService: denominationService,
+		logger:              logger,
 	}
 }
 
@@ -29,6 +43,11 @@ func NewPriceHandler(priceService services.PriceService, logger *zap.SugaredLogg
 func (h *PriceHandler) GetAllCurrentPrices(c *fiber.Ctx) error {
 	ctx := c.Context()
 
+	denomination, err := denominationFromQuery(c, h.denominationService)
+	if err != nil {
+		return respondQueryError(c, err, "failed to fetch current prices")
+	}
+
 	prices, err := h.priceService.GetAllCurrentPrices(ctx)
 	if err != nil {
 		h.logger.Errorf("Failed to get all current prices: %v", err)
@@ -37,11 +56,8 @@ func (h *PriceHandler) GetAllCurrentPrices(c *fiber.Ctx) error {
 		})
 	}
 
-	return c.JSON(fiber.Map{
-		"data": prices,
-		"meta": fiber.Map{
-			"count": len(prices),
-		},
+	return h.respondCurrentPrices(c, prices, denomination, fiber.Map{
+		"count": len(prices),
 	})
 }
 
@@ -58,6 +74,11 @@ func (h *PriceHandler) GetCurrentPrice(c *fiber.Ctx) error {
 		})
 	}
 
+	denomination, err := denominationFromQuery(c, h.denominationService)
+	if err != nil {
+		return respondQueryError(c, err, "failed to fetch current price")
+	}
+
 	// Get current price
 	price, err := h.priceService.GetCurrentPrice(ctx, itemID)
 	if err != nil {
@@ -72,6 +93,21 @@ func (h *PriceHandler) GetCurrentPrice(c *fiber.Ctx) error {
 		})
 	}
 
+	if denomination != models.DenominationGP {
+		converted, rate, err := h.denominationService.DenominateCurrentPrices(ctx, []models.CurrentPrice{*price}, denomination)
+		if err != nil {
+			h.logger.Errorf("Failed to denominate current price for item %d: %v", itemID, err)
+			return respondQueryError(c, denominationError(err), "failed to convert current price")
+		}
+		return c.JSON(fiber.Map{
+			"data": converted[0],
+			"meta": fiber.Map{
+				"denomination": denomination,
+				"bond":         rate,
+			},
+		})
+	}
+
 	return c.JSON(fiber.Map{
 		"data": price,
 	})
@@ -97,6 +133,11 @@ func (h *PriceHandler) GetBatchCurrentPrices(c *fiber.Ctx) error {
 		})
 	}
 
+	denomination, err := denominationFromQuery(c, h.denominationService)
+	if err != nil {
+		return respondQueryError(c, err, "failed to fetch batch prices")
+	}
+
 	itemIDs := make([]int, 0, len(idStrings))
 	for _, idStr := range idStrings {
 		id, err := strconv.Atoi(strings.TrimSpace(idStr))
@@ -117,12 +158,38 @@ func (h *PriceHandler) GetBatchCurrentPrices(c *fiber.Ctx) error {
 		})
 	}
 
+	return h.respondCurrentPrices(c, prices, denomination, fiber.Map{
+		"requested": len(itemIDs),
+		"found":     len(prices),
+	})
+}
+
+// respondCurrentPrices writes a list of current prices, converted to
+// denomination when it is not gp. The denomination and bond rate used are
+// added to meta.
+func (h *PriceHandler) respondCurrentPrices(
+	c *fiber.Ctx,
+	prices []models.CurrentPrice,
+	denomination models.Denomination,
+	meta fiber.Map,
+) error {
+	if denomination == models.DenominationGP {
+		return c.JSON(fiber.Map{
+			"data": prices,
+			"meta": meta,
+		})
+	}
+
+	converted, rate, err := h.denominationService.DenominateCurrentPrices(c.Context(), prices, denomination)
+	if err != nil {
+		h.logger.Errorf("Failed to denominate current prices: %v", err)
+		return respondQueryError(c, denominationError(err), "failed to convert current prices")
+	}
+	meta["denomination"] = denomination
+	meta["bond"] = rate
 	return c.JSON(fiber.Map{
-		"data": prices,
-		"meta": fiber.Map{
-			"requested": len(itemIDs),
-			"found":     len(prices),
-		},
+		"data": converted,
+		"meta": meta,
 	})
 }
 
@@ -176,12 +243,26 @@ func (h *PriceHandler) GetPriceHistory(c *fiber.Ctx) error {
 		maxPoints = &points
 	}
 
+	// Parse sampling mode
+	sampling := utils.SamplingMode(c.Query("sampling", string(utils.DefaultSamplingMode)))
+	if !sampling.IsValid() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "sampling must be one of: voronoi, lttb, minmax, average",
+		})
+	}
+
+	denomination, err := denominationFromQuery(c, h.denominationService)
+	if err != nil {
+		return respondQueryError(c, err, "failed to fetch price history")
+	}
+
 	refresh := refreshStr == "true"
 
 	params := models.PriceHistoryParams{
 		ItemID:    itemID,
 		Period:    period,
 		MaxPoints: maxPoints,
+		Sampling:  sampling,
 		Refresh:   refresh,
 	}
 
@@ -194,19 +275,244 @@ func (h *PriceHandler) GetPriceHistory(c *fiber.Ctx) error {
 		})
 	}
 
+	meta := fiber.Map{
+		"item_id":    history.ItemID,
+		"period":     history.Period,
+		"count":      history.Count,
+		"first_date": history.FirstDate,
+		"last_date":  history.LastDate,
+		"sampled":    maxPoints != nil && history.Count > *maxPoints,
+		"sampling":   history.Sampling,
+	}
+	if denomination == models.DenominationGP {
+		return c.JSON(fiber.Map{
+			"data": history.Data,
+			"meta": meta,
+		})
+	}
+
+	// Each point is converted at the bond price in effect at its timestamp.
+	converted, err := h.denominationService.DenominatePriceHistory(ctx, history, denomination)
+	if err != nil {
+		h.logger.Errorf("Failed to denominate price history for item %d: %v", itemID, err)
+		return respondQueryError(c, denominationError(err), "failed to convert price history")
+	}
+	meta["count"] = len(converted.Points)
+	meta["denomination"] = denomination
+	meta["unconverted"] = converted.Unconverted
+	return c.JSON(fiber.Map{
+		"data": converted.Points,
+		"meta": meta,
+	})
+}
+
+// maxPriceAtItems caps how many items one point-in-time batch may resolve.
+const maxPriceAtItems = 500
+
+// parsePriceAtTimestamp reads the required ts query parameter.
+func parsePriceAtTimestamp(c *fiber.Ctx) (time.Time, error) {
+	tsStr := c.Query("ts")
+	if tsStr == "" {
+		return time.Time{}, fiber.NewError(fiber.StatusBadRequest, "ts query parameter is required")
+	}
+	ts, err := parseTimeParam(tsStr)
+	if err != nil {
+		return time.Time{}, fiber.NewError(fiber.StatusBadRequest,
+			"invalid ts, expected RFC3339, 'YYYY-MM-DD HH:MM' or unix seconds")
+	}
+	if ts.After(time.Now().UTC().Add(time.Minute)) {
+		return time.Time{}, fiber.NewError(fiber.StatusBadRequest, "ts must not be in the future")
+	}
+	return ts, nil
+}
+
+// GetPriceAt handles GET /api/v1/prices/at/:id?ts=2026-03-14T18:00:00Z.
+func (h *PriceHandler) GetPriceAt(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	itemID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid item ID")
+	}
+	ts, err := parsePriceAtTimestamp(c)
+	if err != nil {
+		return respondQueryError(c, err, "invalid ts")
+	}
+
+	prices, err := h.priceService.GetPricesAt(ctx, []int{itemID}, ts)
+	if err != nil {
+		h.logger.Errorf("Failed to get price at %s for item %d: %v", ts, itemID, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to fetch price")
+	}
+	if len(prices) == 0 {
+		return errorResponse(c, fiber.StatusNotFound, "no stored price covers the requested timestamp")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": prices[0],
+	})
+}
+
+// GetBatchPricesAt handles GET /api/v1/prices/at?ids=1,2,3&ts=... (or share=<token> for a whole watchlist).
+func (h *PriceHandler) GetBatchPricesAt(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	ts, err := parsePriceAtTimestamp(c)
+	if err != nil {
+		return respondQueryError(c, err, "invalid ts")
+	}
+	itemIDs, err := itemIDsFromQuery(c, h.watchlistService, maxPriceAtItems)
+	if err != nil {
+		h.logger.Debugw("Rejected point-in-time batch request", "error", err)
+		return respondQueryError(c, err, "failed to resolve items")
+	}
+
+	prices, err := h.priceService.GetPricesAt(ctx, itemIDs, ts)
+	if err != nil {
+		h.logger.Errorf("Failed to get batch prices at %s: %v", ts, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to fetch prices")
+	}
+
+	found := make(map[int]struct{}, len(prices))
+	for _, p := range prices {
+		found[p.ItemID] = struct{}{}
+	}
+	missing := make([]int, 0, len(itemIDs)-len(prices))
+	for _, id := range itemIDs {
+		if _, ok := found[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+
 	return c.JSON(fiber.Map{
-		"data": history.Data,
+		"data": prices,
 		"meta": fiber.Map{
-			"item_id":    history.ItemID,
-			"period":     history.Period,
-			"count":      history.Count,
-			"first_date": history.FirstDate,
-			"last_date":  history.LastDate,
-			"sampled":    maxPoints != nil && history.Count > *maxPoints,
+			"ts":        ts,
+			"requested": len(itemIDs),
+			"found":     len(prices),
+			"missing":   missing,
 		},
 	})
 }
 
+// maxCompareItems caps how many items a single comparison may include.
+const maxCompareItems = 10
+
+// ComparePrices handles GET /api/v1/prices/compare?ids=1,2&period=30d&field=mid&ratios=true.
+func (h *PriceHandler) ComparePrices(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	idsStr := c.Query("ids")
+	if idsStr == "" {
+		return errorResponse(c, fiber.StatusBadRequest, "ids query parameter is required")
+	}
+	itemIDs, err := parseItemIDList(idsStr, maxCompareItems)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+	if len(itemIDs) < 2 {
+		return errorResponse(c, fiber.StatusBadRequest, "at least 2 distinct item IDs are required")
+	}
+
+	period := models.TimePeriod(c.Query("period", "30d"))
+	if !period.IsValid() {
+		return errorResponse(c, fiber.StatusBadRequest,
+			"invalid period, must be one of: 1h, 12h, 24h, 3d, 7d, 30d, 90d, 1y, all")
+	}
+
+	field := models.PriceField(c.Query("field", string(models.PriceFieldMid)))
+	if !field.IsValid() {
+		return errorResponse(c, fiber.StatusBadRequest, "field must be one of: mid, high, low")
+	}
+
+	params := models.PriceCompareParams{
+		ItemIDs:   itemIDs,
+		Period:    period,
+		Field:     field,
+		AllRatios: c.Query("ratios") == "true",
+	}
+
+	if sampleStr := c.Query("sample"); sampleStr != "" {
+		points, err := strconv.Atoi(sampleStr)
+		if err != nil || points < 10 || points > 1000 {
+			return errorResponse(c, fiber.StatusBadRequest, "sample must be between 10 and 1000")
+		}
+		params.MaxPoints = &points
+	}
+
+	if startStr := c.Query("start"); startStr != "" {
+		start, err := parseTimeParam(startStr)
+		if err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, "invalid start timestamp")
+		}
+		params.StartTime = &start
+	}
+	if endStr := c.Query("end"); endStr != "" {
+		end, err := parseTimeParam(endStr)
+		if err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, "invalid end timestamp")
+		}
+		params.EndTime = &end
+	}
+	if params.StartTime != nil && params.EndTime != nil && !params.StartTime.Before(*params.EndTime) {
+		return errorResponse(c, fiber.StatusBadRequest, "start must be before end")
+	}
+
+	if pairsStr := c.Query("pairs"); pairsStr != "" {
+		pairs, err := parseComparePairs(pairsStr, itemIDs)
+		if err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, err.Error())
+		}
+		params.Pairs = pairs
+	}
+
+	comparison, err := h.priceService.ComparePrices(ctx, params)
+	if err != nil {
+		h.logger.Errorf("Failed to compare prices for items %v: %v", itemIDs, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to compare prices")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": comparison,
+		"meta": fiber.Map{
+			"item_ids":         itemIDs,
+			"period":           comparison.Period,
+			"field":            comparison.Field,
+			"timestep":         comparison.Timestep,
+			"interval_seconds": comparison.IntervalSeconds,
+			"count":            comparison.Count,
+		},
+	})
+}
+
+// parseComparePairs parses "a:b,c:d" ratio pairs; both IDs must be part of the comparison.
+func parseComparePairs(raw string, itemIDs []int) ([]models.ItemPair, error) {
+	known := make(map[int]struct{}, len(itemIDs))
+	for _, id := range itemIDs {
+		known[id] = struct{}{}
+	}
+
+	var pairs []models.ItemPair
+	for _, part := range strings.Split(raw, ",") {
+		sides := strings.Split(strings.TrimSpace(part), ":")
+		if len(sides) != 2 {
+			return nil, fmt.Errorf("invalid pair %q, expected numerator:denominator", part)
+		}
+		numerator, errA := strconv.Atoi(sides[0])
+		denominator, errB := strconv.Atoi(sides[1])
+		if errA != nil || errB != nil {
+			return nil, fmt.Errorf("invalid pair %q, expected numerator:denominator", part)
+		}
+		_, okA := known[numerator]
+		_, okB := known[denominator]
+		if !okA || !okB {
+			return nil, fmt.Errorf("pair %q references an item not listed in ids", part)
+		}
+		pairs = append(pairs, models.ItemPair{NumeratorID: numerator, DenominatorID: denominator})
+	}
+	return pairs, nil
+}
+
 // SyncCurrentPrices handles POST /api/v1/prices/sync (admin endpoint).
 func (h *PriceHandler) SyncCurrentPrices(c *fiber.Ctx) error {
 	ctx := c.Context()
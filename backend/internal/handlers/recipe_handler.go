@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/recipes"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// RecipeHandler handles processing recipe endpoints and the admin routes that
+// edit the recipe registry.
+type RecipeHandler struct {
+	recipeService services.RecipeService
+	logger        *zap.SugaredLogger
+}
+
+// NewRecipeHandler creates a new recipe handler.
+func NewRecipeHandler(recipeService services.RecipeService, logger *zap.SugaredLogger) *RecipeHandler {
+	return &RecipeHandler{
+		recipeService: recipeService,
+		logger:        logger,
+	}
+}
+
+// GetRecipeProfits handles GET /api/v1/recipes
+// [?pricing=offer|instant&sort=profit|gp_per_hour|gp_per_xp&skill=Herblore&limit=50].
+func (h *RecipeHandler) GetRecipeProfits(c *fiber.Ctx) error {
+	mode, err := pricingModeFromQuery(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+	sortBy := c.Query("sort", recipes.SortByProfit)
+	switch sortBy {
+	case recipes.SortByProfit, recipes.SortByGPPerHour, recipes.SortByGPPerXP:
+	default:
+		return errorResponse(c, fiber.StatusBadRequest, "sort must be one of profit, gp_per_hour, gp_per_xp")
+	}
+	limit, err := limitFromQuery(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+	skill := strings.TrimSpace(c.Query("skill"))
+
+	report, err := h.recipeService.RecipeProfits(c.Context(), mode)
+	if err != nil {
+		h.logger.Errorf("Failed to compute recipe profits: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to compute recipe profits")
+	}
+
+	if skill != "" {
+		filtered := make([]models.RecipeProfit, 0, len(report.Recipes))
+		for _, r := range report.Recipes {
+			if strings.EqualFold(r.Skill, skill) {
+				filtered = append(filtered, r)
+			}
+		}
+		report.Recipes = filtered
+	}
+	total := len(report.Recipes)
+	recipes.Sort(report.Recipes, sortBy)
+	if limit > 0 && len(report.Recipes) > limit {
+		report.Recipes = report.Recipes[:limit]
+	}
+
+	return c.JSON(fiber.Map{
+		"data": report,
+		"meta": fiber.Map{
+			"count": len(report.Recipes),
+			"total": total,
+			"sort":  sortBy,
+		},
+	})
+}
+
+// ListRecipes handles GET /api/v1/recipes/definitions.
+func (h *RecipeHandler) ListRecipes(c *fiber.Ctx) error {
+	list, err := h.recipeService.ListRecipes(c.Context())
+	if err != nil {
+		h.logger.Errorf("Failed to list recipes: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to list recipes")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": list,
+		"meta": fiber.Map{
+			"count": len(list),
+		},
+	})
+}
+
+// SaveRecipe handles PUT /api/v1/admin/recipes/:id.
+func (h *RecipeHandler) SaveRecipe(c *fiber.Ctx) error {
+	var recipe models.Recipe
+	if err := c.BodyParser(&recipe); err != nil {
+		h.logger.Debugw("Invalid recipe request body", "error", err)
+		return errorResponse(c, fiber.StatusBadRequest, "invalid request body")
+	}
+	recipe.ID = c.Params("id")
+
+	saved, err := h.recipeService.SaveRecipe(c.Context(), recipe)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidRecipe) {
+			return errorResponse(c, fiber.StatusBadRequest, strings.TrimPrefix(err.Error(), models.ErrInvalidRecipe.Error()+": "))
+		}
+		h.logger.Errorf("Failed to save recipe %s: %v", recipe.ID, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to save recipe")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": saved,
+	})
+}
+
+// DeleteRecipe handles DELETE /api/v1/admin/recipes/:id.
+func (h *RecipeHandler) DeleteRecipe(c *fiber.Ctx) error {
+	id := c.Params("id")
+	deleted, err := h.recipeService.DeleteRecipe(c.Context(), id)
+	if err != nil {
+		h.logger.Errorf("Failed to delete recipe %s: %v", id, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to delete recipe")
+	}
+	if !deleted {
+		return errorResponse(c, fiber.StatusNotFound, "recipe not found")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
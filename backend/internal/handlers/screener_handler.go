@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/middleware"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// ScreenerHandler handles the market screener and saved screen endpoints.
+type ScreenerHandler struct {
+	screenerService services.ScreenerService
+	logger          *zap.SugaredLogger
+}
+
+// NewScreenerHandler creates a new screener handler.
+func NewScreenerHandler(screenerService services.ScreenerService, logger *zap.SugaredLogger) *ScreenerHandler {
+	return &ScreenerHandler{
+		screenerService: screenerService,
+		logger:          logger,
+	}
+}
+
+// screenRequest is the body of POST and PUT /api/v1/screener/screens.
+type screenRequest struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+	Alert      bool   `json:"alert"`
+}
+
+// Screen handles GET /api/v1/screener?q=...&limit=50.
+func (h *ScreenerHandler) Screen(c *fiber.Ctx) error {
+	expression := c.Query("q")
+	if strings.TrimSpace(expression) == "" {
+		return errorResponse(c, fiber.StatusBadRequest, "screener expression parameter 'q' is required")
+	}
+	limit, err := screenerLimit(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.screenerService.Run(c.Context(), expression, limit)
+	if err != nil {
+		return h.respondScreenError(c, err, "failed to run screener")
+	}
+	return h.respondResult(c, result)
+}
+
+// ListFields handles GET /api/v1/screener/fields.
+func (h *ScreenerHandler) ListFields(c *fiber.Ctx) error {
+	fields := h.screenerService.Fields()
+	return c.JSON(fiber.Map{
+		"data": fields,
+		"meta": fiber.Map{
+			"count": len(fields),
+		},
+	})
+}
+
+// ListScreens handles GET /api/v1/screener/screens.
+func (h *ScreenerHandler) ListScreens(c *fiber.Ctx) error {
+	screens, err := h.screenerService.ListScreens(c.Context(), middleware.UserID(c))
+	if err != nil {
+		h.logger.Errorf("Failed to list screens: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to list screens")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": screens,
+		"meta": fiber.Map{
+			"count": len(screens),
+		},
+	})
+}
+
+// GetScreen handles GET /api/v1/screener/screens/:id.
+func (h *ScreenerHandler) GetScreen(c *fiber.Ctx) error {
+	id, ok := screenIDParam(c)
+	if !ok {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid screen ID")
+	}
+
+	screen, err := h.screenerService.GetScreen(c.Context(), middleware.UserID(c), id)
+	if err != nil {
+		h.logger.Errorf("Failed to get screen %d: %v", id, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to get screen")
+	}
+	if screen == nil {
+		return errorResponse(c, fiber.StatusNotFound, "screen not found")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": screen,
+	})
+}
+
+// CreateScreen handles POST /api/v1/screener/screens.
+func (h *ScreenerHandler) CreateScreen(c *fiber.Ctx) error {
+	var req screenRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debugw("Invalid screen request body", "error", err)
+		return errorResponse(c, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	screen, err := h.screenerService.CreateScreen(c.Context(), middleware.UserID(c), req.screen())
+	if err != nil {
+		return h.respondScreenError(c, err, "failed to create screen")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"data": screen,
+	})
+}
+
+// UpdateScreen handles PUT /api/v1/screener/screens/:id.
+func (h *ScreenerHandler) UpdateScreen(c *fiber.Ctx) error {
+	id, ok := screenIDParam(c)
+	if !ok {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid screen ID")
+	}
+	var req screenRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debugw("Invalid screen request body", "error", err)
+		return errorResponse(c, fiber.StatusBadRequest, "invalid request body")
+	}
+
+	screen, err := h.screenerService.UpdateScreen(c.Context(), middleware.UserID(c), id, req.screen())
+	if err != nil {
+		return h.respondScreenError(c, err, "failed to update screen")
+	}
+	if screen == nil {
+		return errorResponse(c, fiber.StatusNotFound, "screen not found")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": screen,
+	})
+}
+
+// DeleteScreen handles DELETE /api/v1/screener/screens/:id.
+func (h *ScreenerHandler) DeleteScreen(c *fiber.Ctx) error {
+	id, ok := screenIDParam(c)
+	if !ok {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid screen ID")
+	}
+
+	deleted, err := h.screenerService.DeleteScreen(c.Context(), middleware.UserID(c), id)
+	if err != nil {
+		h.logger.Errorf("Failed to delete screen %d: %v", id, err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to delete screen")
+	}
+	if !deleted {
+		return errorResponse(c, fiber.StatusNotFound, "screen not found")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RunScreen handles GET /api/v1/screener/screens/:id/results?limit=50.
+func (h *ScreenerHandler) RunScreen(c *fiber.Ctx) error {
+	id, ok := screenIDParam(c)
+	if !ok {
+		return errorResponse(c, fiber.StatusBadRequest, "invalid screen ID")
+	}
+	limit, err := screenerLimit(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.screenerService.RunScreen(c.Context(), middleware.UserID(c), id, limit)
+	if err != nil {
+		return h.respondScreenError(c, err, "failed to run screen")
+	}
+	if result == nil {
+		return errorResponse(c, fiber.StatusNotFound, "screen not found")
+	}
+	return h.respondResult(c, result)
+}
+
+func (h *ScreenerHandler) respondResult(c *fiber.Ctx, result *models.ScreenerResult) error {
+	return c.JSON(fiber.Map{
+		"data": result.Rows,
+		"meta": fiber.Map{
+			"count":      len(result.Rows),
+			"total":      result.Total,
+			"expression": result.Expression,
+			"asOf":       result.AsOf,
+		},
+	})
+}
+
+func (h *ScreenerHandler) respondScreenError(c *fiber.Ctx, err error, fallback string) error {
+	if errors.Is(err, models.ErrInvalidScreen) {
+		return errorResponse(c, fiber.StatusBadRequest, strings.TrimPrefix(err.Error(), models.ErrInvalidScreen.Error()+": "))
+	}
+	h.logger.Errorf("Screener request failed: %v", err)
+	return errorResponse(c, fiber.StatusInternalServerError, fallback)
+}
+
+func (r screenRequest) screen() models.Screen {
+	return models.Screen{Name: r.Name, Expression: r.Expression, Alert: r.Alert}
+}
+
+// screenerLimit reads the optional "limit" query parameter; 0 means the
+// expression's LIMIT or the default applies.
+func screenerLimit(c *fiber.Ctx) (int, error) {
+	raw := c.Query("limit")
+	if raw == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 1 || limit > models.MaxScreenerLimit {
+		return 0, fmt.Errorf("limit must be between 1 and %d", models.MaxScreenerLimit)
+	}
+	return limit, nil
+}
+
+func screenIDParam(c *fiber.Ctx) (int64, bool) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	return id, err == nil && id > 0
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"github.com/guavi/osrs-ge-tracker/internal/middleware"
 	"github.com/guavi/osrs-ge-tracker/internal/services"
 )
 
@@ -72,6 +73,15 @@ func (h *SSEHandler) Stream(c *fiber.Ctx) error {
 		})
 	}
 
+	// Optional user ID for per-user events such as buy limit resets; EventSource
+	// cannot send the X-User-ID header, so it comes from the query string.
+	userID := c.Query("user")
+	if userID != "" && !middleware.ValidUserID(userID) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "user must be 1-64 letters, digits or . _ @ -",
+		})
+	}
+
 	// Set SSE headers
 	c.Set("Content-Type", "text/event-stream")
 	c.Set("Cache-Control", "no-cache")
@@ -90,6 +100,7 @@ func (h *SSEHandler) Stream(c *fiber.Ctx) error {
 		ID:          clientID,
 		MessageChan: messageChan,
 		ItemFilters: itemFilters,
+		UserID:      userID,
 		ConnectedAt: time.Now(),
 	}
 
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"io"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+	"github.com/guavi/osrs-ge-tracker/internal/valuation"
+)
+
+// ValuationHandler handles bank valuation endpoints.
+type ValuationHandler struct {
+	valuationService    services.ValuationService
+	denominationService services.DenominationService
+	logger              *zap.SugaredLogger
+}
+
+// NewValuationHandler creates a new valuation handler. denominationService
+// may be nil, in which case only gp amounts are served.
+func NewValuationHandler(
+	valuationService services.ValuationService,
+	denominationService services.DenominationService,
+	logger *zap.SugaredLogger,
+) *ValuationHandler {
+	return &ValuationHandler{
+		valuationService:    valuationService,
+		denominationService: denominationService,
+		logger:              logger,
+	}
+}
+
+// ValueBank handles POST /api/v1/valuation. The export is JSON, TSV or CSV,
+// sent as the raw request body or a multipart upload in the "file" field.
+func (h *ValuationHandler) ValueBank(c *fiber.Ctx) error {
+	denomination, err := denominationFromQuery(c, h.denominationService)
+	if err != nil {
+		return respondQueryError(c, err, "failed to value bank")
+	}
+
+	body, err := exportBody(c)
+	if err != nil {
+		return respondQueryError(c, err, "failed to read uploaded file")
+	}
+
+	entries, format, err := valuation.Parse(body)
+	if err != nil {
+		h.logger.Debugw("Rejected bank export", "error", err)
+		return errorResponse(c, fiber.StatusBadRequest,
+			strings.TrimPrefix(err.Error(), models.ErrInvalidBankExport.Error()+": "))
+	}
+
+	result, err := h.valuationService.ValueBank(c.Context(), entries)
+	if err != nil {
+		h.logger.Errorf("Failed to value bank export: %v", err)
+		return errorResponse(c, fiber.StatusInternalServerError, "failed to value bank")
+	}
+
+	meta := fiber.Map{
+		"format":  format,
+		"entries": len(entries),
+	}
+	if denomination == models.DenominationGP {
+		return c.JSON(fiber.Map{
+			"data": result,
+			"meta": meta,
+		})
+	}
+
+	converted, rate, err := h.denominationService.DenominateValuation(c.Context(), result, denomination)
+	if err != nil {
+		h.logger.Errorf("Failed to denominate bank valuation: %v", err)
+		return respondQueryError(c, denominationError(err), "failed to convert bank value")
+	}
+	meta["denomination"] = denomination
+	meta["bond"] = rate
+	return c.JSON(fiber.Map{
+		"data": converted,
+		"meta": meta,
+	})
+}
+
+// exportBody returns an uploaded export: the multipart "file" field when the
+// request is a form upload, otherwise the raw body. Errors are fiber errors.
+func exportBody(c *fiber.Ctx) ([]byte, error) {
+	if !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		return c.Body(), nil
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "multipart upload must include a file field")
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "failed to read uploaded file")
+	}
+	defer file.Close()
+	body, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "failed to read uploaded file")
+	}
+	return body, nil
+}
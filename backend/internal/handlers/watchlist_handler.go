@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gofiber/fiber/v2"
@@ -69,13 +70,13 @@ func (h *WatchlistHandler) GetShare(c *fiber.Ctx) error {
 	// Get share
 	response, err := h.service.GetShare(c.Context(), token)
 	if err != nil {
-		if err.Error() == "share not found" || err.Error() == "share has expired" {
+		if errors.Is(err, models.ErrShareNotFound) || errors.Is(err, models.ErrShareExpired) {
 			return c.Status(http.StatusNotFound).JSON(fiber.Map{
 				"error": err.Error(),
 			})
 		}
 
-		if err.Error() == "invalid token format" {
+		if errors.Is(err, models.ErrInvalidShareToken) {
 			return c.Status(http.StatusBadRequest).JSON(fiber.Map{
 				"error": "Invalid token format",
 			})
@@ -0,0 +1,153 @@
+package marketindex
+
+import (
+	"sort"
+	"time"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// Compute extends an index over the buckets in series (1h points per
+// constituent, in any order) that come after last. Each bucket moves the
+// index by the weighted average of the constituents' price relatives
+// against their previous traded bucket, so constituents that did not trade
+// simply sit the bucket out. Points at or before last only seed previous
+// prices.
+//
+// With no last value the first bucket with a priced constituent becomes the
+// base, at models.MarketIndexBaseValue. Buckets where no constituent moved
+// the index are skipped.
+func Compute(
+	indexID string,
+	weighting models.MarketIndexWeighting,
+	series map[int][]models.PriceTimeseriesPoint,
+	last *models.MarketIndexValue,
+) []models.MarketIndexValue {
+	type observation struct {
+		itemID int
+		mid    float64
+		volume float64
+	}
+	buckets := make(map[time.Time][]observation)
+	for itemID, points := range series {
+		for _, p := range points {
+			mid, ok := Mid(p)
+			if !ok {
+				continue
+			}
+			ts := p.Timestamp.UTC()
+			buckets[ts] = append(buckets[ts], observation{
+				itemID: itemID,
+				mid:    float64(mid),
+				volume: float64(p.HighPriceVolume + p.LowPriceVolume),
+			})
+		}
+	}
+
+	timestamps := make([]time.Time, 0, len(buckets))
+	for ts := range buckets {
+		timestamps = append(timestamps, ts)
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i].Before(timestamps[j]) })
+
+	value := models.MarketIndexBaseValue
+	if last != nil {
+		value = last.Value
+	}
+	previous := make(map[int]float64, len(series))
+	values := make([]models.MarketIndexValue, 0, len(timestamps))
+
+	for _, ts := range timestamps {
+		observed := buckets[ts]
+		if last != nil && !ts.After(last.Timestamp) {
+			for _, o := range observed {
+				previous[o.itemID] = o.mid
+			}
+			continue
+		}
+
+		var weighted, total float64
+		moved := 0
+		for _, o := range observed {
+			prev, ok := previous[o.itemID]
+			previous[o.itemID] = o.mid
+			if !ok {
+				continue
+			}
+			weight := 1.0
+			if weighting == models.WeightingVolume {
+				weight = o.mid * o.volume
+			}
+			if weight <= 0 {
+				continue
+			}
+			weighted += weight * o.mid / prev
+			total += weight
+			moved++
+		}
+
+		switch {
+		case total > 0:
+			value *= weighted / total
+		case last == nil && len(values) == 0:
+			// The first priced bucket sets the base.
+			moved = len(observed)
+		default:
+			continue
+		}
+		values = append(values, models.MarketIndexValue{
+			Timestamp:    ts,
+			IndexID:      indexID,
+			Value:        value,
+			Constituents: moved,
+		})
+	}
+	return values
+}
+
+// Weights returns each constituent's share of the index from its recent
+// points: the GP it traded for volume weighting, or an equal share of the
+// items that traded at all. Shares sum to 1 unless nothing traded.
+func Weights(weighting models.MarketIndexWeighting, series map[int][]models.PriceTimeseriesPoint) map[int]float64 {
+	raw := make(map[int]float64, len(series))
+	var total float64
+	for itemID, points := range series {
+		var w float64
+		for _, p := range points {
+			mid, ok := Mid(p)
+			if !ok {
+				continue
+			}
+			if weighting == models.WeightingEqual {
+				w = 1
+				break
+			}
+			w += float64(mid) * float64(p.HighPriceVolume+p.LowPriceVolume)
+		}
+		if w > 0 {
+			raw[itemID] = w
+			total += w
+		}
+	}
+
+	weights := make(map[int]float64, len(raw))
+	for itemID, w := range raw {
+		weights[itemID] = w / total
+	}
+	return weights
+}
+
+// Mid returns a bucket's mid price, or the one side that traded.
+func Mid(p models.PriceTimeseriesPoint) (int64, bool) {
+	high := p.AvgHighPrice != nil && *p.AvgHighPrice > 0
+	low := p.AvgLowPrice != nil && *p.AvgLowPrice > 0
+	switch {
+	case high && low:
+		return (*p.AvgHighPrice + *p.AvgLowPrice) / 2, true
+	case high:
+		return *p.AvgHighPrice, true
+	case low:
+		return *p.AvgLowPrice, true
+	}
+	return 0, false
+}
@@ -0,0 +1,98 @@
+// Package marketindex computes chain-linked price indices over groups of
+// Grand Exchange items.
+package marketindex
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// maxIndexIDLength bounds a builtin index ID, leaving room in the
+// varchar(64) value key.
+const maxIndexIDLength = 48
+
+//go:embed indices.json
+var defaultIndicesJSON []byte
+
+var indexIDPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Load returns the builtin indices: the shipped ones, or those in the JSON
+// file at path when it is set. The file holds a list of index definitions
+// and replaces the shipped list entirely.
+func Load(path string) ([]models.MarketIndex, error) {
+	data := defaultIndicesJSON
+	source := "embedded indices"
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("read market indices: %w", err)
+		}
+		source = path
+	}
+
+	var indices []models.MarketIndex
+	if err := json.Unmarshal(data, &indices); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", source, err)
+	}
+	seen := make(map[string]struct{}, len(indices))
+	for i := range indices {
+		index := &indices[i]
+		switch {
+		case len(index.ID) > maxIndexIDLength || !indexIDPattern.MatchString(index.ID):
+			return nil, fmt.Errorf("%s: index %q: id must be 1-%d lowercase letters, digits and single dashes",
+				source, index.ID, maxIndexIDLength)
+		case index.ID == models.MarketIndexSourceCustom || strings.HasPrefix(index.ID, models.MarketIndexSourceCustom+"-"):
+			return nil, fmt.Errorf("%s: index %q: ids starting with %q are reserved", source, index.ID, models.MarketIndexSourceCustom)
+		}
+		if _, dup := seen[index.ID]; dup {
+			return nil, fmt.Errorf("%s: index %q is defined twice", source, index.ID)
+		}
+		seen[index.ID] = struct{}{}
+		if err := Validate(index); err != nil {
+			return nil, fmt.Errorf("%s: index %q: %w", source, index.ID, err)
+		}
+		index.Source = models.MarketIndexSourceBuiltin
+	}
+	return indices, nil
+}
+
+// Validate checks an index definition, trimming its name and defaulting the
+// weighting to volume. The ID is not checked. Errors wrap
+// models.ErrInvalidMarketIndex.
+func Validate(index *models.MarketIndex) error {
+	index.Name = strings.TrimSpace(index.Name)
+	if index.Weighting == "" {
+		index.Weighting = models.WeightingVolume
+	}
+	switch {
+	case index.Name == "" || utf8.RuneCountInString(index.Name) > models.MaxMarketIndexNameLength:
+		return invalidIndex("name must be 1-%d characters", models.MaxMarketIndexNameLength)
+	case !index.Weighting.IsValid():
+		return invalidIndex("weighting must be %s or %s", models.WeightingVolume, models.WeightingEqual)
+	case len(index.ItemIDs) < models.MinMarketIndexConstituents || len(index.ItemIDs) > models.MaxMarketIndexConstituents:
+		return invalidIndex("an index must have %d-%d items", models.MinMarketIndexConstituents, models.MaxMarketIndexConstituents)
+	}
+
+	seen := make(map[int]struct{}, len(index.ItemIDs))
+	for _, id := range index.ItemIDs {
+		if id <= 0 {
+			return invalidIndex("invalid item ID %d", id)
+		}
+		if _, dup := seen[id]; dup {
+			return invalidIndex("item %d is listed twice", id)
+		}
+		seen[id] = struct{}{}
+	}
+	return nil
+}
+
+func invalidIndex(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", models.ErrInvalidMarketIndex, fmt.Sprintf(format, args...))
+}
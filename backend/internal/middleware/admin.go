@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"crypto/subtle"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminKeyHeader carries the shared admin API key.
+const AdminKeyHeader = "X-Admin-Key"
+
+// RequireAdminKey rejects requests whose X-Admin-Key header does not match
+// key. When no key is configured the admin API is disabled and every request
+// is rejected.
+func RequireAdminKey(key string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if key == "" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "admin API is disabled",
+			})
+		}
+		if subtle.ConstantTimeCompare([]byte(c.Get(AdminKeyHeader)), []byte(key)) != 1 {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "invalid or missing X-Admin-Key header",
+			})
+		}
+		return c.Next()
+	}
+}
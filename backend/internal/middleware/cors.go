@@ -16,7 +16,7 @@ func NewCORSMiddleware(config CORSConfig) fiber.Handler {
 		AllowOrigins: joinOrigins(config.AllowedOrigins),
 		AllowMethods: "GET,POST,PUT,DELETE,OPTIONS,PATCH",
 		AllowHeaders: "Origin,Content-Type,Accept,Authorization,X-Requested-With," +
-			"X-Request-Id,X-Request-ID,X-Request-Time,Cache-Control,X-Accel-Buffering",
+			"X-Request-Id,X-Request-ID,X-Request-Time,X-User-ID,Cache-Control,X-Accel-Buffering",
 		AllowCredentials: false,
 		ExposeHeaders: "Content-Length,Content-Range,Content-Type,Cache-Control,Connection," +
 			"X-Accel-Buffering",
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UserIDHeader carries the caller's user ID. There are no user accounts; the
+// ID is an opaque handle chosen by the client that scopes per-user data such
+// as the trade journal.
+const UserIDHeader = "X-User-ID"
+
+// userIDLocal is the fiber.Ctx locals key holding the validated user ID.
+const userIDLocal = "userID"
+
+var userIDPattern = regexp.MustCompile(`^[A-Za-z0-9_.@-]{1,64}$`)
+
+// RequireUserID rejects requests without a valid X-User-ID header and stores
+// the ID for UserID.
+func RequireUserID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID := c.Get(UserIDHeader)
+		if userID == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "X-User-ID header is required",
+			})
+		}
+		if !ValidUserID(userID) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "X-User-ID must be 1-64 letters, digits or . _ @ -",
+			})
+		}
+		c.Locals(userIDLocal, userID)
+		return c.Next()
+	}
+}
+
+// ValidUserID reports whether id is an acceptable user ID. Used for the SSE
+// stream, where browsers cannot send custom headers.
+func ValidUserID(id string) bool {
+	return userIDPattern.MatchString(id)
+}
+
+// UserID returns the user ID stored by RequireUserID, or "" outside it.
+func UserID(c *fiber.Ctx) string {
+	userID, _ := c.Locals(userIDLocal).(string)
+	return userID
+}
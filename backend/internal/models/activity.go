@@ -0,0 +1,147 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Activity value history limits.
+const (
+	DefaultActivityHistoryRange = 90 * 24 * time.Hour
+	MaxActivityHistoryRange     = 366 * 24 * time.Hour
+)
+
+// ErrInvalidActivity is wrapped by every activity definition error.
+var ErrInvalidActivity = errors.New("invalid activity")
+
+// DropRate is the chance of a drop per kill. Definitions may write it as a
+// number ("0.25") or a fraction ("1/128").
+type DropRate float64
+
+// UnmarshalJSON accepts a number or a "1/128" fraction string.
+func (r *DropRate) UnmarshalJSON(data []byte) error {
+	var n float64
+	if err := json.Unmarshal(data, &n); err == nil {
+		*r = DropRate(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("rate must be a number or a fraction like \"1/128\"")
+	}
+	return r.parse(s)
+}
+
+// UnmarshalYAML accepts a number or a 1/128 fraction.
+func (r *DropRate) UnmarshalYAML(node *yaml.Node) error {
+	return r.parse(node.Value)
+}
+
+func (r *DropRate) parse(s string) error {
+	s = strings.TrimSpace(s)
+	num, den, isFraction := strings.Cut(s, "/")
+	n, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil {
+		return fmt.Errorf("rate %q must be a number or a fraction like \"1/128\"", s)
+	}
+	if isFraction {
+		d, err := strconv.ParseFloat(strings.TrimSpace(den), 64)
+		if err != nil || d == 0 {
+			return fmt.Errorf("rate %q must be a number or a fraction like \"1/128\"", s)
+		}
+		n /= d
+	}
+	*r = DropRate(n)
+	return nil
+}
+
+// ActivityDrop is one entry of a drop table: an item dropped at Rate per
+// kill in a stack of MinQuantity to MaxQuantity, uniformly distributed.
+type ActivityDrop struct {
+	ItemID      int      `json:"itemId" yaml:"itemId"`
+	MinQuantity int      `json:"minQuantity" yaml:"minQuantity"`
+	MaxQuantity int      `json:"maxQuantity" yaml:"maxQuantity"`
+	Rate        DropRate `json:"rate" yaml:"rate"`
+}
+
+// Activity is a PvM activity with its drop table. Source is "builtin" for
+// shipped definitions, otherwise the file it was loaded from.
+type Activity struct {
+	ID           string         `json:"id" yaml:"id"`
+	Name         string         `json:"name" yaml:"name"`
+	Source       string         `json:"source,omitempty" yaml:"-"`
+	Drops        []ActivityDrop `json:"drops" yaml:"drops"`
+	KillsPerHour float64        `json:"killsPerHour" yaml:"killsPerHour"`
+}
+
+// ActivitySourceBuiltin marks activities shipped with the application.
+const ActivitySourceBuiltin = "builtin"
+
+// Activity drop price sources.
+const (
+	DropPriceGE    = "ge"
+	DropPriceAlch  = "alch"
+	DropPriceCoins = "coins"
+)
+
+// ActivityDropValue is a drop with its expected value per kill. UnitPrice is
+// what one unit sells for after GE tax, or its high alchemy value when the
+// item has no Grand Exchange price; both are nil when neither is known.
+type ActivityDropValue struct {
+	UnitPrice        *int64  `json:"unitPrice"`
+	Name             string  `json:"name"`
+	PriceSource      string  `json:"priceSource,omitempty"`
+	ItemID           int     `json:"itemId"`
+	MinQuantity      int     `json:"minQuantity"`
+	MaxQuantity      int     `json:"maxQuantity"`
+	Rate             float64 `json:"rate"`
+	ExpectedQuantity float64 `json:"expectedQuantity"`
+	ExpectedValue    float64 `json:"expectedValue"`
+}
+
+// ActivityValue is the expected loot value of an activity at current prices.
+// Unpriced lists drops valued at zero because no price is known.
+type ActivityValue struct {
+	ComputedAt   time.Time           `json:"computedAt"`
+	ID           string              `json:"id"`
+	Name         string              `json:"name"`
+	Pricing      PricingMode         `json:"pricing"`
+	Drops        []ActivityDropValue `json:"drops,omitempty"`
+	Unpriced     []int               `json:"unpriced"`
+	KillsPerHour float64             `json:"killsPerHour"`
+	ValuePerKill float64             `json:"valuePerKill"`
+	ValuePerHour float64             `json:"valuePerHour"`
+}
+
+// ActivityHistoryParams selects the days of an activity value history. Zero
+// values fall back to the last 90 days.
+type ActivityHistoryParams struct {
+	From    time.Time
+	To      time.Time
+	Pricing PricingMode
+}
+
+// ActivityValuePoint is an activity's expected value on one day, from the
+// daily price rollups. Unpriced counts drops with no price that day.
+type ActivityValuePoint struct {
+	Day          time.Time `json:"day"`
+	ValuePerKill float64   `json:"valuePerKill"`
+	ValuePerHour float64   `json:"valuePerHour"`
+	Unpriced     int       `json:"unpriced"`
+}
+
+// ActivityValueHistory is an activity's expected value over time, oldest
+// day first.
+type ActivityValueHistory struct {
+	From    time.Time            `json:"from"`
+	To      time.Time            `json:"to"`
+	ID      string               `json:"id"`
+	Pricing PricingMode          `json:"pricing"`
+	Points  []ActivityValuePoint `json:"points"`
+}
@@ -0,0 +1,219 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Item set limits.
+const (
+	MaxItemSetNameLength        = 100
+	MaxItemSetComponents        = 10
+	MaxItemSetComponentQuantity = 1000
+)
+
+// ErrInvalidItemSet is wrapped by every item set definition validation error.
+var ErrInvalidItemSet = errors.New("invalid item set")
+
+// PricingMode chooses which side of the spread an arbitrage trade is
+// assumed to fill at.
+type PricingMode string
+
+const (
+	// PricingOffer buys with offers at the instant-sell (low) price and sells
+	// with offers at the instant-buy (high) price, as backtests do.
+	PricingOffer PricingMode = "offer"
+	// PricingInstant buys at the instant-buy (high) price and sells at the
+	// instant-sell (low) price.
+	PricingInstant PricingMode = "instant"
+)
+
+// IsValid checks if the pricing mode is supported.
+func (m PricingMode) IsValid() bool {
+	return m == PricingOffer || m == PricingInstant
+}
+
+// BuyPrice returns the price paid per unit under the mode.
+func (m PricingMode) BuyPrice(p CurrentPrice) *int64 {
+	if m == PricingInstant {
+		return p.HighPrice
+	}
+	return p.LowPrice
+}
+
+// SellPrice returns the price received per unit, before tax, under the mode.
+func (m PricingMode) SellPrice(p CurrentPrice) *int64 {
+	if m == PricingInstant {
+		return p.LowPrice
+	}
+	return p.HighPrice
+}
+
+// ItemSetComponent is one piece of an item set.
+type ItemSetComponent struct {
+	ItemID   int `json:"itemId"`
+	Quantity int `json:"quantity"`
+}
+
+// ItemSet is a Grand Exchange set that a clerk exchanges for its components
+// and back. Source is "builtin" for shipped definitions and "custom" for
+// ones added or edited by an admin.
+type ItemSet struct {
+	Name       string             `json:"name"`
+	Source     string             `json:"source,omitempty"`
+	Components []ItemSetComponent `json:"components"`
+	SetItemID  int                `json:"setItemId"`
+}
+
+// Item set definition sources.
+const (
+	ItemSetSourceBuiltin = "builtin"
+	ItemSetSourceCustom  = "custom"
+)
+
+// ItemSetOverride is an admin edit to the set registry. It replaces the
+// shipped definition with the same set item ID, adds a new one, or, when
+// Removed is set, hides it.
+type ItemSetOverride struct {
+	UpdatedAt  time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+	Name       string         `gorm:"column:name;type:varchar(100);not null" json:"name"`
+	Components datatypes.JSON `gorm:"column:components;type:jsonb;not null" json:"components"`
+	SetItemID  int            `gorm:"primaryKey;column:set_item_id;autoIncrement:false" json:"setItemId"`
+	Removed    bool           `gorm:"column:removed;not null;default:false" json:"removed"`
+}
+
+// TableName specifies the table name for GORM.
+func (ItemSetOverride) TableName() string {
+	return "item_set_overrides"
+}
+
+// Set arbitrage directions.
+const (
+	SetDirectionCombine = "combine"
+	SetDirectionSplit   = "split"
+)
+
+// SetArbitrageComponent is a set piece with its current prices.
+type SetArbitrageComponent struct {
+	HighPrice *int64 `json:"highPrice"`
+	LowPrice  *int64 `json:"lowPrice"`
+	BuyLimit  *int   `json:"buyLimit"`
+	Name      string `json:"name"`
+	ItemID    int    `json:"itemId"`
+	Quantity  int    `json:"quantity"`
+}
+
+// SetArbitrageTrade is the profit of one way through the clerk: combining
+// bought pieces into a set and selling it, or splitting a bought set and
+// selling the pieces. Amounts are per set. MaxSets is how many sets the buy
+// limits of the bought items allow per 4-hour window; nil when a limit is
+// unknown.
+type SetArbitrageTrade struct {
+	MaxSets         *int64  `json:"maxSets"`
+	ProfitPerWindow *int64  `json:"profitPerWindow"`
+	Cost            int64   `json:"cost"`
+	Revenue         int64   `json:"revenue"`
+	Tax             int64   `json:"tax"`
+	Profit          int64   `json:"profit"`
+	ROI             float64 `json:"roi"`
+}
+
+// SetArbitrage compares a set with its pieces. A direction is nil when a
+// price it needs is missing; Best names the more profitable priced one.
+type SetArbitrage struct {
+	SetHighPrice *int64                  `json:"setHighPrice"`
+	SetLowPrice  *int64                  `json:"setLowPrice"`
+	SetBuyLimit  *int                    `json:"setBuyLimit"`
+	Combine      *SetArbitrageTrade      `json:"combine"`
+	Split        *SetArbitrageTrade      `json:"split"`
+	Name         string                  `json:"name"`
+	Best         string                  `json:"best"`
+	Components   []SetArbitrageComponent `json:"components"`
+	SetItemID    int                     `json:"setItemId"`
+}
+
+// BestTrade returns the direction named by Best.
+func (a SetArbitrage) BestTrade() *SetArbitrageTrade {
+	if a.Best == SetDirectionSplit {
+		return a.Split
+	}
+	return a.Combine
+}
+
+// SetArbitrageReport ranks every priced set, most profitable first. Unpriced
+// lists sets with no priced direction.
+type SetArbitrageReport struct {
+	ComputedAt time.Time      `json:"computedAt"`
+	Pricing    PricingMode    `json:"pricing"`
+	Sets       []SetArbitrage `json:"sets"`
+	Unpriced   []int          `json:"unpriced"`
+}
+
+// DoseVariant is one dose count of a potion.
+type DoseVariant struct {
+	ItemID int `json:"itemId"`
+	Doses  int `json:"doses"`
+}
+
+// DoseGroup lists the dose variants of one potion. Bob Barter at the Grand
+// Exchange decants between them for free.
+type DoseGroup struct {
+	Name     string        `json:"name"`
+	Variants []DoseVariant `json:"variants"`
+}
+
+// DoseVariantPrice is a dose variant with its current prices. BuyPerDose is
+// the price paid per dose and SellPerDose the price received per dose after
+// tax, under the report's pricing mode.
+type DoseVariantPrice struct {
+	HighPrice   *int64   `json:"highPrice"`
+	LowPrice    *int64   `json:"lowPrice"`
+	BuyPerDose  *float64 `json:"buyPerDose"`
+	SellPerDose *float64 `json:"sellPerDose"`
+	BuyLimit    *int     `json:"buyLimit"`
+	Name        string   `json:"name"`
+	ItemID      int      `json:"itemId"`
+	Doses       int      `json:"doses"`
+}
+
+// DecantTrade buys one dose variant, decants it into another and sells the
+// result. Amounts are per batch: the fewest doses that decant with nothing
+// left over, bought as Bought potions and sold as Sold potions. MaxBatches
+// is how many batches the bought variant's buy limit allows per 4-hour
+// window; nil when the limit is unknown.
+type DecantTrade struct {
+	MaxBatches      *int64  `json:"maxBatches"`
+	ProfitPerWindow *int64  `json:"profitPerWindow"`
+	FromItemID      int     `json:"fromItemId"`
+	ToItemID        int     `json:"toItemId"`
+	FromDoses       int     `json:"fromDoses"`
+	ToDoses         int     `json:"toDoses"`
+	Bought          int64   `json:"bought"`
+	Sold            int64   `json:"sold"`
+	Cost            int64   `json:"cost"`
+	Revenue         int64   `json:"revenue"`
+	Tax             int64   `json:"tax"`
+	Profit          int64   `json:"profit"`
+	ProfitPerDose   float64 `json:"profitPerDose"`
+	ROI             float64 `json:"roi"`
+}
+
+// DoseArbitrage compares the dose variants of a potion. Trades holds every
+// priced decant, most profit per dose first; Best is the first of them.
+type DoseArbitrage struct {
+	Best     *DecantTrade       `json:"best"`
+	Name     string             `json:"name"`
+	Variants []DoseVariantPrice `json:"variants"`
+	Trades   []DecantTrade      `json:"trades"`
+}
+
+// DoseArbitrageReport ranks every potion with a priced decant by its best
+// trade. Unpriced lists potions with none.
+type DoseArbitrageReport struct {
+	ComputedAt time.Time       `json:"computedAt"`
+	Pricing    PricingMode     `json:"pricing"`
+	Groups     []DoseArbitrage `json:"groups"`
+	Unpriced   []string        `json:"unpriced"`
+}
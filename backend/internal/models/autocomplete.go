@@ -0,0 +1,16 @@
+package models
+
+// Autocomplete limits.
+const (
+	DefaultAutocompleteLimit = 8
+	MaxAutocompleteQuery     = 64
+)
+
+// AutocompleteItem is one completion for the item search box.
+type AutocompleteItem struct {
+	HighPrice *int64 `json:"highPrice"`
+	LowPrice  *int64 `json:"lowPrice"`
+	Name      string `json:"name"`
+	IconURL   string `json:"iconUrl"`
+	ItemID    int    `json:"itemId"`
+}
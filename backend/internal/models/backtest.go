@@ -0,0 +1,157 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// MaxUnfinishedBacktests caps the backtests queued or running at once.
+const MaxUnfinishedBacktests = 20
+
+var (
+	// ErrBacktestShuttingDown is returned when a backtest is submitted after
+	// shutdown has begun.
+	ErrBacktestShuttingDown = errors.New("backtest service is shutting down")
+	// ErrBacktestQueueFull is returned while MaxUnfinishedBacktests backtests
+	// are queued or running.
+	ErrBacktestQueueFull = errors.New("too many backtests are queued; try again later")
+)
+
+// BacktestStatus is the lifecycle state of a backtest job.
+type BacktestStatus string
+
+const (
+	BacktestStatusPending   BacktestStatus = "pending"
+	BacktestStatusRunning   BacktestStatus = "running"
+	BacktestStatusCompleted BacktestStatus = "completed"
+	BacktestStatusFailed    BacktestStatus = "failed"
+)
+
+// BacktestIndicator is a per-bar value a strategy condition can test.
+type BacktestIndicator string
+
+const (
+	// BacktestIndicatorPrice is the mid price.
+	BacktestIndicatorPrice BacktestIndicator = "price"
+	// BacktestIndicatorSpreadPct is (high - low) / low in percent.
+	BacktestIndicatorSpreadPct BacktestIndicator = "spread_pct"
+	// BacktestIndicatorRSI is Wilder's relative strength index of the mid price (0-100).
+	BacktestIndicatorRSI BacktestIndicator = "rsi"
+	// BacktestIndicatorSMADeviationPct is how far the mid price is above its simple moving average, in percent.
+	BacktestIndicatorSMADeviationPct BacktestIndicator = "sma_deviation_pct"
+	// BacktestIndicatorReturnPct is the mid price change over Period bars, in percent.
+	BacktestIndicatorReturnPct BacktestIndicator = "return_pct"
+	// BacktestIndicatorVolume is the bar's total traded volume.
+	BacktestIndicatorVolume BacktestIndicator = "volume"
+)
+
+// IsValid checks if the indicator is supported.
+func (i BacktestIndicator) IsValid() bool {
+	switch i {
+	case BacktestIndicatorPrice, BacktestIndicatorSpreadPct, BacktestIndicatorRSI,
+		BacktestIndicatorSMADeviationPct, BacktestIndicatorReturnPct, BacktestIndicatorVolume:
+		return true
+	default:
+		return false
+	}
+}
+
+// BacktestCondition compares an indicator with a constant, e.g. rsi(14) < 30.
+type BacktestCondition struct {
+	Indicator BacktestIndicator `json:"indicator"`
+	// Operator is one of <, <=, >, >=.
+	Operator string  `json:"op"`
+	Value    float64 `json:"value"`
+	// Period is the lookback in bars for rsi, sma_deviation_pct and return_pct.
+	Period int `json:"period,omitempty"`
+}
+
+// BacktestStrategy is a declarative trading rule replayed against stored buckets.
+// A position is opened when every Entry condition holds and closed when any
+// Exit condition, the take-profit, the stop-loss or the holding limit triggers.
+// Orders are filled on the bar after the signal: buys at the bar's average low
+// price and sells at its average high price.
+type BacktestStrategy struct {
+	Start           time.Time           `json:"start"`
+	End             time.Time           `json:"end"`
+	TakeProfitPct   *float64            `json:"takeProfitPct,omitempty"`
+	StopLossPct     *float64            `json:"stopLossPct,omitempty"`
+	Name            string              `json:"name,omitempty"`
+	Timestep        string              `json:"timestep"`
+	ItemIDs         []int               `json:"itemIds"`
+	Entry           []BacktestCondition `json:"entry"`
+	Exit            []BacktestCondition `json:"exit,omitempty"`
+	InitialCapital  int64               `json:"initialCapital"`
+	PositionSizePct float64             `json:"positionSizePct"`
+	// MaxVolumeSharePct caps each fill at this share of the bar's traded volume on that side.
+	MaxVolumeSharePct float64 `json:"maxVolumeSharePct"`
+	MaxHoldBars       int     `json:"maxHoldBars,omitempty"`
+}
+
+// BacktestTrade is one round trip. Prices are per-unit averages; Profit is net of tax.
+type BacktestTrade struct {
+	EntryTime  time.Time `json:"entryTime"`
+	ExitTime   time.Time `json:"exitTime"`
+	ExitReason string    `json:"exitReason"`
+	EntryPrice float64   `json:"entryPrice"`
+	ExitPrice  float64   `json:"exitPrice"`
+	ReturnPct  float64   `json:"returnPct"`
+	ItemID     int       `json:"itemId"`
+	Quantity   int64     `json:"quantity"`
+	Tax        int64     `json:"tax"`
+	Profit     int64     `json:"profit"`
+}
+
+// BacktestEquityPoint is the portfolio value at a bar, with open positions
+// valued at their after-tax sale price.
+type BacktestEquityPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Equity    int64     `json:"equity"`
+	Cash      int64     `json:"cash"`
+}
+
+// BacktestSummary aggregates a backtest run.
+type BacktestSummary struct {
+	// ProfitFactor is gross profit / gross loss, nil without losing trades.
+	ProfitFactor   *float64 `json:"profitFactor"`
+	TotalReturnPct float64  `json:"totalReturnPct"`
+	MaxDrawdownPct float64  `json:"maxDrawdownPct"`
+	WinRatePct     float64  `json:"winRatePct"`
+	AvgHoldMinutes float64  `json:"avgHoldMinutes"`
+	InitialCapital int64    `json:"initialCapital"`
+	FinalEquity    int64    `json:"finalEquity"`
+	NetProfit      int64    `json:"netProfit"`
+	TaxPaid        int64    `json:"taxPaid"`
+	Trades         int      `json:"trades"`
+	Wins           int      `json:"wins"`
+	Losses         int      `json:"losses"`
+	Bars           int      `json:"bars"`
+}
+
+// BacktestResult is the output of a completed backtest.
+type BacktestResult struct {
+	Trades  []BacktestTrade       `json:"trades"`
+	Equity  []BacktestEquityPoint `json:"equity"`
+	Summary BacktestSummary       `json:"summary"`
+}
+
+// Backtest is a stored backtest job. Strategy and Result hold JSON-encoded
+// BacktestStrategy and BacktestResult values.
+type Backtest struct {
+	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	StartedAt   *time.Time     `gorm:"column:started_at" json:"startedAt,omitempty"`
+	CompletedAt *time.Time     `gorm:"column:completed_at" json:"completedAt,omitempty"`
+	Error       *string        `gorm:"column:error;type:text" json:"error,omitempty"`
+	ID          string         `gorm:"primaryKey;column:id;type:uuid" json:"id"`
+	Name        string         `gorm:"column:name;type:varchar(100)" json:"name,omitempty"`
+	Status      BacktestStatus `gorm:"column:status;type:varchar(20);not null" json:"status"`
+	Strategy    datatypes.JSON `gorm:"column:strategy;type:jsonb;not null" json:"strategy"`
+	Result      datatypes.JSON `gorm:"column:result;type:jsonb" json:"result,omitempty"`
+}
+
+// TableName specifies the table name for GORM.
+func (Backtest) TableName() string {
+	return "backtests"
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Bank snapshot limits.
+const (
+	MaxBankSnapshotsPerUser     = 25
+	MaxBankSnapshotNameLength   = 100
+	DefaultNetWorthHistoryRange = 30 * 24 * time.Hour
+	// MaxNetWorthPoints bounds a revalued history request, which loads every
+	// stored price of the snapshot's items across the range.
+	MaxNetWorthPoints = 120
+)
+
+// ErrInvalidBankSnapshot is wrapped by every bank snapshot validation error.
+var ErrInvalidBankSnapshot = errors.New("invalid bank snapshot")
+
+// NetWorthIntervals are the spacings accepted between history points.
+var NetWorthIntervals = map[string]time.Duration{
+	"1h": time.Hour,
+	"6h": 6 * time.Hour,
+	"1d": 24 * time.Hour,
+	"7d": 7 * 24 * time.Hour,
+}
+
+// Net worth history sources.
+const (
+	NetWorthSourceRevalued = "revalued"
+	NetWorthSourceRecorded = "recorded"
+)
+
+// BankSnapshot is a saved bank. Items hold resolved Grand Exchange item IDs
+// (and currencies), so a snapshot can be revalued without the original export.
+type BankSnapshot struct {
+	CreatedAt time.Time          `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UserID    string             `gorm:"column:user_id;type:varchar(64);not null" json:"userId"`
+	Name      string             `gorm:"column:name;type:varchar(100);not null" json:"name"`
+	Items     []BankSnapshotItem `gorm:"-" json:"items,omitempty"`
+	ID        int64              `gorm:"primaryKey;column:id" json:"id"`
+	ItemCount int                `gorm:"column:item_count;->" json:"itemCount"`
+}
+
+// TableName specifies the table name for GORM.
+func (BankSnapshot) TableName() string {
+	return "bank_snapshots"
+}
+
+// BankSnapshotItem is one stack in a saved bank.
+type BankSnapshotItem struct {
+	SnapshotID int64 `gorm:"primaryKey;column:snapshot_id" json:"-"`
+	Quantity   int64 `gorm:"column:quantity;not null" json:"quantity"`
+	ItemID     int   `gorm:"primaryKey;column:item_id" json:"itemId"`
+}
+
+// TableName specifies the table name for GORM.
+func (BankSnapshotItem) TableName() string {
+	return "bank_snapshot_items"
+}
+
+// BankSnapshotValue is a snapshot's value recorded by the daily job.
+type BankSnapshotValue struct {
+	ValuedOn      time.Time `gorm:"primaryKey;column:valued_on;type:date" json:"valuedOn"`
+	RecordedAt    time.Time `gorm:"column:recorded_at;not null" json:"recordedAt"`
+	SnapshotID    int64     `gorm:"primaryKey;column:snapshot_id" json:"snapshotId"`
+	TotalHigh     int64     `gorm:"column:total_high;not null" json:"totalHigh"`
+	TotalLow      int64     `gorm:"column:total_low;not null" json:"totalLow"`
+	TotalMid      int64     `gorm:"column:total_mid;not null" json:"totalMid"`
+	TotalHighAlch int64     `gorm:"column:total_high_alch;not null" json:"totalHighAlch"`
+	UnpricedItems int       `gorm:"column:unpriced_items;not null" json:"unpricedItems"`
+}
+
+// TableName specifies the table name for GORM.
+func (BankSnapshotValue) TableName() string {
+	return "bank_snapshot_values"
+}
+
+// BankSnapshotDetail is a snapshot with its value at current prices.
+type BankSnapshotDetail struct {
+	BankSnapshot
+	Valuation BankValuation `json:"valuation"`
+}
+
+// NetWorthHistoryParams selects the points of a net worth history. Interval
+// only applies to revalued history; recorded history has one point per day.
+type NetWorthHistoryParams struct {
+	From     time.Time
+	To       time.Time
+	Source   string
+	Interval time.Duration
+}
+
+// NetWorthPoint is a snapshot's value at one point in time.
+type NetWorthPoint struct {
+	Timestamp     time.Time `json:"timestamp"`
+	TotalHigh     int64     `json:"totalHigh"`
+	TotalLow      int64     `json:"totalLow"`
+	TotalMid      int64     `json:"totalMid"`
+	TotalHighAlch int64     `json:"totalHighAlch"`
+	UnpricedItems int       `json:"unpricedItems"`
+}
+
+// NetWorthHistory is a snapshot's value over time, oldest point first.
+type NetWorthHistory struct {
+	Points     []NetWorthPoint `json:"points"`
+	Source     string          `json:"source"`
+	SnapshotID int64           `json:"snapshotId"`
+}
+
+// NewNetWorthPoint takes the totals of a valuation.
+func NewNetWorthPoint(ts time.Time, v BankValuation) NetWorthPoint {
+	return NetWorthPoint{
+		Timestamp:     ts,
+		TotalHigh:     v.TotalHigh,
+		TotalLow:      v.TotalLow,
+		TotalMid:      v.TotalMid,
+		TotalHighAlch: v.TotalHighAlch,
+		UnpricedItems: v.UnpricedItems,
+	}
+}
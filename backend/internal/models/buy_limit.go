@@ -0,0 +1,105 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// MaxBuyLimitItems caps the item IDs checked in one availability request.
+const MaxBuyLimitItems = 100
+
+// ErrInvalidBuyLimitPurchase is wrapped by every purchase validation error.
+var ErrInvalidBuyLimitPurchase = errors.New("invalid purchase")
+
+// BuyLimitPurchase is one purchase a user logged against an item's buy limit.
+type BuyLimitPurchase struct {
+	PurchasedAt time.Time `gorm:"column:purchased_at;not null" json:"purchasedAt"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UserID      string    `gorm:"column:user_id;type:varchar(64);not null" json:"userId"`
+	ID          int64     `gorm:"primaryKey;column:id" json:"id"`
+	Quantity    int64     `gorm:"column:quantity;not null" json:"quantity"`
+	ItemID      int       `gorm:"column:item_id;not null" json:"itemId"`
+}
+
+// TableName specifies the table name for GORM.
+func (BuyLimitPurchase) TableName() string {
+	return "buy_limit_purchases"
+}
+
+// BuyLimitTimer is a user's current buy limit window for one item. Like the
+// Grand Exchange, a window opens at the first purchase and everything bought
+// until ResetsAt counts against the limit. Notified is set once the reset has
+// been announced.
+type BuyLimitTimer struct {
+	WindowStart time.Time `gorm:"column:window_start;not null" json:"windowStart"`
+	ResetsAt    time.Time `gorm:"column:resets_at;not null" json:"resetsAt"`
+	UserID      string    `gorm:"primaryKey;column:user_id;type:varchar(64)" json:"userId"`
+	Bought      int64     `gorm:"column:bought;not null" json:"bought"`
+	ItemID      int       `gorm:"primaryKey;column:item_id" json:"itemId"`
+	Notified    bool      `gorm:"column:notified;not null" json:"-"`
+}
+
+// TableName specifies the table name for GORM.
+func (BuyLimitTimer) TableName() string {
+	return "buy_limit_timers"
+}
+
+// Active reports whether the window is still open at `at`.
+func (t *BuyLimitTimer) Active(at time.Time) bool {
+	return t != nil && at.Before(t.ResetsAt)
+}
+
+// BuyLimitPurchaseRequest is the body of a purchase log request. An unset
+// PurchasedAt means now.
+type BuyLimitPurchaseRequest struct {
+	PurchasedAt *time.Time `json:"purchasedAt"`
+	Quantity    int64      `json:"quantity"`
+	ItemID      int        `json:"itemId"`
+}
+
+// BuyLimitStatus is how much of an item a user may still buy. Limit and
+// Remaining are nil when the item's buy limit is unknown; ResetsAt and
+// ResetsInSeconds are nil when no window is open. Purchases lists the open
+// window's purchases and is only filled for single-item lookups.
+type BuyLimitStatus struct {
+	ResetsAt        *time.Time         `json:"resetsAt"`
+	Limit           *int               `json:"limit"`
+	Remaining       *int64             `json:"remaining"`
+	ResetsInSeconds *int64             `json:"resetsInSeconds"`
+	ItemName        string             `json:"itemName,omitempty"`
+	Purchases       []BuyLimitPurchase `json:"purchases,omitempty"`
+	Bought          int64              `json:"bought"`
+	ItemID          int                `json:"itemId"`
+	Available       bool               `json:"available"`
+}
+
+// NewBuyLimitStatus computes an item's status at `at` from its buy limit and
+// the user's timer, which may be nil.
+func NewBuyLimitStatus(itemID int, limit *int, timer *BuyLimitTimer, at time.Time) BuyLimitStatus {
+	status := BuyLimitStatus{ItemID: itemID, Available: true}
+	if timer.Active(at) {
+		resetsAt := timer.ResetsAt
+		resetsIn := int64(resetsAt.Sub(at).Seconds())
+		status.Bought = timer.Bought
+		status.ResetsAt = &resetsAt
+		status.ResetsInSeconds = &resetsIn
+	}
+	if limit != nil && *limit > 0 {
+		l := *limit
+		remaining := max(int64(l)-status.Bought, 0)
+		status.Limit = &l
+		status.Remaining = &remaining
+		status.Available = remaining > 0
+	}
+	return status
+}
+
+// BuyLimitReset announces that a user's buy limit window for an item closed.
+type BuyLimitReset struct {
+	ResetAt  time.Time `json:"resetAt"`
+	Limit    *int      `json:"limit"`
+	UserID   string    `json:"userId"`
+	ItemName string    `json:"itemName,omitempty"`
+	Bought   int64     `json:"bought"`
+	ItemID   int       `json:"itemId"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Correlation window bounds in days, and the largest window computed from the
+// 1h table; longer windows use 24h buckets and daily rollups.
+const (
+	DefaultCorrelationWindowDays = 30
+	MinCorrelationWindowDays     = 7
+	MaxCorrelationWindowDays     = 365
+	CorrelationHourlyMaxDays     = 30
+	// MaxCorrelationItems caps the matrix size; pairs grow quadratically.
+	MaxCorrelationItems = 20
+	// CorrelationMinObservations is the fewest overlapping returns a pair needs.
+	CorrelationMinObservations = 10
+)
+
+// CorrelationParams contains parameters for a correlation matrix.
+type CorrelationParams struct {
+	Field      PriceField
+	ItemIDs    []int
+	WindowDays int
+}
+
+// CorrelationPair is the correlation of one pair of items.
+type CorrelationPair struct {
+	Pearson  *float64 `json:"pearson"`
+	Spearman *float64 `json:"spearman"`
+	ItemA    int      `json:"itemA"`
+	ItemB    int      `json:"itemB"`
+	// Observations is the number of returns both items had.
+	Observations int `json:"observations"`
+}
+
+// CorrelationResponse holds return correlation matrices for a set of items.
+// Row and column i of every matrix refer to ItemIDs[i]; cells are nil when a
+// pair has too few overlapping returns or one side never moved.
+type CorrelationResponse struct {
+	GeneratedAt  time.Time    `json:"generatedAt"`
+	Start        time.Time    `json:"start"`
+	End          time.Time    `json:"end"`
+	ItemIDs      []int        `json:"itemIds"`
+	Pearson      [][]*float64 `json:"pearson"`
+	Spearman     [][]*float64 `json:"spearman"`
+	Observations [][]int      `json:"observations"`
+	// Pairs lists every distinct pair, strongest absolute Pearson correlation first.
+	Pairs           []CorrelationPair `json:"pairs"`
+	Field           string            `json:"field"`
+	Timestep        string            `json:"timestep"`
+	IntervalSeconds int64             `json:"intervalSeconds"`
+	WindowDays      int               `json:"windowDays"`
+}
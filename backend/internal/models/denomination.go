@@ -0,0 +1,148 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// BondItemID is the Old School Bond, the only item with an official
+// real-money price.
+const BondItemID = 13190
+
+// Denomination is the unit API amounts are expressed in.
+type Denomination string
+
+const (
+	// DenominationGP leaves amounts in coins. It is the default.
+	DenominationGP Denomination = "gp"
+	// DenominationBonds divides amounts by the bond's GP price.
+	DenominationBonds Denomination = "bonds"
+	// DenominationReal converts bond units at the configured real-money
+	// price per bond.
+	DenominationReal Denomination = "real"
+)
+
+var (
+	// ErrInvalidDenomination is wrapped by denomination validation errors.
+	ErrInvalidDenomination = errors.New("invalid denomination")
+	// ErrBondPriceUnavailable is returned when no stored bond price covers
+	// an amount being converted.
+	ErrBondPriceUnavailable = errors.New("bond price unavailable")
+)
+
+// IsValid reports whether d is a known denomination.
+func (d Denomination) IsValid() bool {
+	switch d {
+	case DenominationGP, DenominationBonds, DenominationReal:
+		return true
+	}
+	return false
+}
+
+// BondRate is the bond's GP price at a point in time, plus the configured
+// real-money price when one is set.
+type BondRate struct {
+	Timestamp time.Time `json:"timestamp"`
+	RealPrice *float64  `json:"realPrice,omitempty"`
+	Currency  string    `json:"currency,omitempty"`
+	Price     int64     `json:"price"`
+}
+
+// Convert expresses a GP amount in d. Callers check that the rate has a
+// real price before converting to DenominationReal.
+func (r BondRate) Convert(gp int64, d Denomination) float64 {
+	switch d {
+	case DenominationBonds:
+		return float64(gp) / float64(r.Price)
+	case DenominationReal:
+		return float64(gp) / float64(r.Price) * *r.RealPrice
+	default:
+		return float64(gp)
+	}
+}
+
+// ConvertPtr is Convert for optional amounts; nil stays nil.
+func (r BondRate) ConvertPtr(gp *int64, d Denomination) *float64 {
+	if gp == nil {
+		return nil
+	}
+	v := r.Convert(*gp, d)
+	return &v
+}
+
+// DenominatedCurrentPrice is a CurrentPrice with its prices converted.
+type DenominatedCurrentPrice struct {
+	UpdatedAt     time.Time  `json:"updatedAt"`
+	HighPrice     *float64   `json:"highPrice"`
+	HighPriceTime *time.Time `json:"highPriceTime"`
+	LowPrice      *float64   `json:"lowPrice"`
+	LowPriceTime  *time.Time `json:"lowPriceTime"`
+	ItemID        int        `json:"itemId"`
+}
+
+// DenominatedPricePoint is a history point converted at the bond price in
+// effect at its timestamp.
+type DenominatedPricePoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	HighPrice float64   `json:"highPrice"`
+	LowPrice  float64   `json:"lowPrice"`
+	BondPrice int64     `json:"bondPrice"`
+}
+
+// DenominatedPriceHistory is a converted price history. Points older than
+// the first stored bond price cannot be converted and are counted in
+// Unconverted.
+type DenominatedPriceHistory struct {
+	Points      []DenominatedPricePoint `json:"points"`
+	Unconverted int                     `json:"unconverted"`
+}
+
+// DenominatedValuationItem is a BankValuationItem with its prices and values
+// converted.
+type DenominatedValuationItem struct {
+	HighPrice     *float64 `json:"highPrice"`
+	LowPrice      *float64 `json:"lowPrice"`
+	MidPrice      *float64 `json:"midPrice"`
+	HighValue     *float64 `json:"highValue"`
+	LowValue      *float64 `json:"lowValue"`
+	MidValue      *float64 `json:"midValue"`
+	HighAlchValue *float64 `json:"highAlchValue"`
+	Name          string   `json:"name"`
+	Resolution    string   `json:"resolution"`
+	SourceItemIDs []int    `json:"sourceItemIds"`
+	Quantity      int64    `json:"quantity"`
+	ItemID        int      `json:"itemId"`
+}
+
+// DenominatedValuation is a BankValuation converted at one bond rate.
+type DenominatedValuation struct {
+	Items         []DenominatedValuationItem `json:"items"`
+	Unresolved    []BankValuationUnresolved  `json:"unresolved"`
+	TotalHigh     float64                    `json:"totalHigh"`
+	TotalLow      float64                    `json:"totalLow"`
+	TotalMid      float64                    `json:"totalMid"`
+	TotalHighAlch float64                    `json:"totalHighAlch"`
+	Placeholders  int                        `json:"placeholders"`
+	UnpricedItems int                        `json:"unpricedItems"`
+}
+
+// DenominatedNetWorthPoint is a NetWorthPoint converted at the bond price in
+// effect at its timestamp.
+type DenominatedNetWorthPoint struct {
+	Timestamp     time.Time `json:"timestamp"`
+	TotalHigh     float64   `json:"totalHigh"`
+	TotalLow      float64   `json:"totalLow"`
+	TotalMid      float64   `json:"totalMid"`
+	TotalHighAlch float64   `json:"totalHighAlch"`
+	BondPrice     int64     `json:"bondPrice"`
+	UnpricedItems int       `json:"unpricedItems"`
+}
+
+// DenominatedNetWorthHistory is a converted NetWorthHistory. Points with no
+// stored bond price at their timestamp are counted in Unconverted.
+type DenominatedNetWorthHistory struct {
+	Points      []DenominatedNetWorthPoint `json:"points"`
+	Source      string                     `json:"source"`
+	SnapshotID  int64                      `json:"snapshotId"`
+	Unconverted int                        `json:"unconverted"`
+}
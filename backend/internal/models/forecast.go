@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// ForecastModel selects the forecasting model.
+type ForecastModel string
+
+const (
+	// ForecastModelAuto picks the model with the lowest backtested MAE.
+	ForecastModelAuto          ForecastModel = "auto"
+	ForecastModelHoltWinters   ForecastModel = "holt_winters"
+	ForecastModelSeasonalNaive ForecastModel = "seasonal_naive"
+)
+
+// IsValid checks if the forecast model is supported.
+func (m ForecastModel) IsValid() bool {
+	switch m {
+	case ForecastModelAuto, ForecastModelHoltWinters, ForecastModelSeasonalNaive:
+		return true
+	default:
+		return false
+	}
+}
+
+// Forecast horizon bounds in hours, and the history each forecast is fitted on.
+const (
+	DefaultForecastHorizon  = 6
+	MaxForecastHorizon      = 48
+	ForecastLookbackDays    = 14
+	ForecastMinObservations = 72
+	ForecastSeasonalPeriod  = 24
+	ForecastBacktestFolds   = 3
+)
+
+// ForecastParams contains parameters for a price forecast.
+type ForecastParams struct {
+	Field   PriceField
+	Model   ForecastModel
+	ItemID  int
+	Horizon int
+}
+
+// ForecastPoint is a predicted hourly value with its 95% prediction interval.
+type ForecastPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+	Lower     float64   `json:"lower"`
+	Upper     float64   `json:"upper"`
+}
+
+// ForecastAccuracy is a model's rolling-origin backtest error on the item's own history.
+type ForecastAccuracy struct {
+	Model string  `json:"model"`
+	MAE   float64 `json:"mae"`
+	RMSE  float64 `json:"rmse"`
+	// MAPE is a fraction (0.05 = 5%).
+	MAPE  float64 `json:"mape"`
+	Folds int     `json:"folds"`
+}
+
+// ForecastResponse is a short-horizon forecast for one item.
+type ForecastResponse struct {
+	GeneratedAt     time.Time          `json:"generatedAt"`
+	LastTimestamp   time.Time          `json:"lastTimestamp"`
+	Parameters      map[string]float64 `json:"parameters,omitempty"`
+	Model           string             `json:"model"`
+	Field           string             `json:"field"`
+	Points          []ForecastPoint    `json:"points"`
+	Backtests       []ForecastAccuracy `json:"backtests"`
+	LastValue       float64            `json:"lastValue"`
+	IntervalSeconds int64              `json:"intervalSeconds"`
+	ItemID          int                `json:"itemId"`
+	Horizon         int                `json:"horizon"`
+	Observations    int                `json:"observations"`
+}
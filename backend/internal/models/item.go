@@ -8,20 +8,23 @@ import (
 
 // Item represents an OSRS Grand Exchange item.
 type Item struct {
-	CreatedAt time.Time      `json:"createdAt"`
-	UpdatedAt time.Time      `json:"updatedAt"`
-	HighAlch  *int           `gorm:"type:integer" json:"highAlch"`
-	Examine   *string        `gorm:"type:text" json:"examine,omitempty"`
-	Value     *int           `gorm:"type:integer" json:"value,omitempty"`
-	IconName  *string        `gorm:"type:text" json:"iconName,omitempty"`
-	BuyLimit  *int           `gorm:"type:integer" json:"buyLimit"`
-	LowAlch   *int           `gorm:"type:integer" json:"lowAlch"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
-	IconURL   string         `gorm:"type:text" json:"iconUrl"`
-	Name      string         `gorm:"size:255;not null;index" json:"name" validate:"required"`
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	ItemID    int            `gorm:"uniqueIndex;not null" json:"itemId" validate:"required"`
-	Members   bool           `gorm:"default:false" json:"members"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+	HighAlch    *int           `gorm:"type:integer" json:"highAlch"`
+	Examine     *string        `gorm:"type:text" json:"examine,omitempty"`
+	Value       *int           `gorm:"type:integer" json:"value,omitempty"`
+	IconName    *string        `gorm:"type:text" json:"iconName,omitempty"`
+	BuyLimit    *int           `gorm:"type:integer" json:"buyLimit"`
+	LowAlch     *int           `gorm:"type:integer" json:"lowAlch"`
+	SearchScore *float64       `gorm:"->;-:migration" json:"score,omitempty"` // Set on search results only
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	Tags        []string       `gorm:"-" json:"tags,omitempty"`
+	IconURL     string         `gorm:"type:text" json:"iconUrl"`
+	Category    string         `gorm:"-" json:"category,omitempty"`
+	Name        string         `gorm:"size:255;not null;index" json:"name" validate:"required"`
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	ItemID      int            `gorm:"uniqueIndex;not null" json:"itemId" validate:"required"`
+	Members     bool           `gorm:"default:false" json:"members"`
 }
 
 // TableName overrides the table name.
@@ -29,24 +32,46 @@ func (Item) TableName() string {
 	return "items"
 }
 
+// BuyLimitWindow is how long the Grand Exchange buy limit applies after the
+// first purchase in a window.
+const BuyLimitWindow = 4 * time.Hour
+
+// Item search modes. All of them ignore case.
+const (
+	// ItemSearchModeFuzzy tolerates typos and matches words anywhere in the name.
+	ItemSearchModeFuzzy = "fuzzy"
+	// ItemSearchModeExact matches the whole name.
+	ItemSearchModeExact = "exact"
+	// ItemSearchModePrefix matches the start of the name.
+	ItemSearchModePrefix = "prefix"
+)
+
 // ItemSearchParams contains parameters for searching items.
+// Category and Tags narrow the results to items carrying all of them.
+// Mode defaults to ItemSearchModeFuzzy; results are ranked by score unless
+// SortBy is set.
 type ItemSearchParams struct {
 	Members   *bool
 	Query     string
+	Mode      string
 	SortBy    string
 	SortOrder string
+	Category  string
+	Tags      []string
 	Limit     int
 	Offset    int
 }
 
 // ItemListParams contains parameters for listing items.
 type ItemListParams struct {
-	Members *bool  `query:"members"`
-	SortBy  string `query:"sortBy" validate:"omitempty,oneof=name item_id members"`
-	Order   string `query:"order" validate:"omitempty,oneof=asc desc"`
-	Page    int    `query:"page" validate:"min=1"`
-	Limit   int    `query:"limit" validate:"min=1,max=200"`
-	Offset  int    `query:"offset" validate:"min=0"`
+	Members  *bool    `query:"members"`
+	SortBy   string   `query:"sortBy" validate:"omitempty,oneof=name item_id members"`
+	Order    string   `query:"order" validate:"omitempty,oneof=asc desc"`
+	Category string   `query:"category"`
+	Tags     []string `query:"tag"`
+	Page     int      `query:"page" validate:"min=1"`
+	Limit    int      `query:"limit" validate:"min=1,max=200"`
+	Offset   int      `query:"offset" validate:"min=0"`
 }
 
 // DefaultItemListParams returns default parameters for item listing.
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Item alias limits.
+const (
+	MaxItemAliasLength = 48
+	// MaxPendingItemAliases bounds the aliases one user can have awaiting review.
+	MaxPendingItemAliases = 20
+	// MaxResolveNames bounds the names of one resolve request.
+	MaxResolveNames = 50
+)
+
+// Item alias errors.
+var (
+	// ErrInvalidItemAlias is wrapped by every item alias validation error.
+	ErrInvalidItemAlias = errors.New("invalid item alias")
+	// ErrItemAliasTaken is returned for an alias that already exists, in any status.
+	ErrItemAliasTaken = errors.New("alias already exists")
+	// ErrItemAliasLimit is returned once a user has MaxPendingItemAliases awaiting review.
+	ErrItemAliasLimit = errors.New("too many aliases awaiting review")
+)
+
+// Item alias statuses. Only approved aliases are used by search and
+// resolution.
+const (
+	ItemAliasStatusPending  = "pending"
+	ItemAliasStatusApproved = "approved"
+	ItemAliasStatusRejected = "rejected"
+)
+
+// Where an item alias comes from. Seed aliases ship with the application and
+// are approved on insert, as are admin aliases; user aliases start pending.
+const (
+	ItemAliasSourceSeed  = "seed"
+	ItemAliasSourceAdmin = "admin"
+	ItemAliasSourceUser  = "user"
+)
+
+// ItemAlias is a nickname for an item, such as "bgs" for Bandos godsword.
+type ItemAlias struct {
+	CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	ReviewedAt  *time.Time `gorm:"column:reviewed_at" json:"reviewedAt,omitempty"`
+	SubmittedBy *string    `gorm:"column:submitted_by;type:varchar(64)" json:"submittedBy,omitempty"`
+	Alias       string     `gorm:"column:alias;type:varchar(48);not null" json:"alias"`
+	Status      string     `gorm:"column:status;type:varchar(16);not null" json:"status"`
+	Source      string     `gorm:"column:source;type:varchar(16);not null" json:"source"`
+	ID          uint       `gorm:"primaryKey;column:id" json:"id"`
+	ItemID      int        `gorm:"column:item_id;not null" json:"itemId"`
+}
+
+// TableName specifies the table name for GORM.
+func (ItemAlias) TableName() string {
+	return "item_aliases"
+}
+
+// How a name was resolved to an item.
+const (
+	ItemMatchName  = "name"
+	ItemMatchAlias = "alias"
+	ItemMatchSlug  = "slug"
+)
+
+// ItemResolution is the item a name resolves to. ItemID is nil when nothing
+// matched.
+type ItemResolution struct {
+	ItemID    *int   `json:"itemId"`
+	Query     string `json:"query"`
+	Name      string `json:"name,omitempty"`
+	MatchedBy string `json:"matchedBy,omitempty"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Item tag limits.
+const (
+	MaxItemTagLength = 32
+	// MaxItemTagFilters bounds the tag filters of one item list or search.
+	MaxItemTagFilters = 5
+)
+
+// ErrInvalidItemTag is wrapped by every item tag validation error.
+var ErrInvalidItemTag = errors.New("invalid item tag")
+
+// Item tag kinds. An item has at most one category from the classifier;
+// other tags describe it further.
+const (
+	ItemTagKindCategory = "category"
+	ItemTagKindTag      = "tag"
+)
+
+// Where an item tag comes from. Auto tags are replaced every time the
+// classifier runs; admin tags, including removals, are kept.
+const (
+	ItemTagSourceAuto  = "auto"
+	ItemTagSourceAdmin = "admin"
+)
+
+// ItemTag links an item to a tag. Removed marks an admin removal, which
+// stops the classifier from adding the tag again.
+type ItemTag struct {
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	Tag       string    `gorm:"primaryKey;column:tag;type:varchar(32)" json:"tag"`
+	Kind      string    `gorm:"column:kind;type:varchar(16);not null" json:"kind"`
+	Source    string    `gorm:"column:source;type:varchar(16);not null" json:"source"`
+	ItemID    int       `gorm:"primaryKey;column:item_id" json:"itemId"`
+	Removed   bool      `gorm:"column:removed;not null;default:false" json:"removed,omitempty"`
+}
+
+// TableName specifies the table name for GORM.
+func (ItemTag) TableName() string {
+	return "item_tags"
+}
+
+// ItemTagCount is a tag with the number of items carrying it.
+type ItemTagCount struct {
+	Tag   string `json:"tag"`
+	Kind  string `json:"kind"`
+	Items int64  `json:"items"`
+}
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Market index limits and defaults.
+const (
+	// MarketIndexBaseValue is the value every index starts from.
+	MarketIndexBaseValue = 1000.0
+	// MarketIndexBackfill is how far back a new index is computed from the
+	// 1h table on its first refresh.
+	MarketIndexBackfill = 30 * 24 * time.Hour
+	// DefaultMarketIndexHistoryRange is the history returned without a from.
+	DefaultMarketIndexHistoryRange = 30 * 24 * time.Hour
+	// MaxMarketIndexHistoryRange bounds one history request.
+	MaxMarketIndexHistoryRange = 366 * 24 * time.Hour
+
+	MinMarketIndexConstituents = 2
+	MaxMarketIndexConstituents = 100
+	MaxMarketIndexNameLength   = 100
+	// MaxCustomMarketIndices caps the custom indices one user may define.
+	MaxCustomMarketIndices = 10
+	// MaxSyncedCustomIndexItems caps the items held only by custom indices
+	// whose 1h buckets one refresh syncs from the Wiki. The rest move on the
+	// buckets stored by other syncs.
+	MaxSyncedCustomIndexItems = 100
+)
+
+// ErrInvalidMarketIndex is wrapped by market index validation errors.
+var ErrInvalidMarketIndex = errors.New("invalid market index")
+
+// MarketIndexWeighting is how constituents are weighted when the index moves.
+type MarketIndexWeighting string
+
+const (
+	// WeightingVolume weights each constituent by the GP traded in the bucket.
+	WeightingVolume MarketIndexWeighting = "volume"
+	// WeightingEqual gives every priced constituent the same weight.
+	WeightingEqual MarketIndexWeighting = "equal"
+)
+
+// IsValid reports whether w is a known weighting.
+func (w MarketIndexWeighting) IsValid() bool {
+	return w == WeightingVolume || w == WeightingEqual
+}
+
+// Where a market index definition comes from.
+const (
+	MarketIndexSourceBuiltin = "builtin"
+	MarketIndexSourceCustom  = "custom"
+)
+
+// MarketIndex defines an index: the items it tracks and how they are
+// weighted. Custom indices are owned by one user and have IDs of the form
+// "custom-<n>".
+type MarketIndex struct {
+	ID          string               `json:"id"`
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	Weighting   MarketIndexWeighting `json:"weighting"`
+	Source      string               `json:"source"`
+	ItemIDs     []int                `json:"itemIds"`
+}
+
+// CustomMarketIndex is a user-defined index as stored.
+type CustomMarketIndex struct {
+	CreatedAt time.Time      `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UserID    string         `gorm:"column:user_id;type:varchar(64);not null;index" json:"-"`
+	Name      string         `gorm:"column:name;type:varchar(100);not null" json:"name"`
+	Weighting string         `gorm:"column:weighting;type:varchar(16);not null" json:"weighting"`
+	ItemIDs   datatypes.JSON `gorm:"column:item_ids;type:jsonb;not null" json:"itemIds"`
+	ID        int64          `gorm:"primaryKey;column:id" json:"id"`
+}
+
+// TableName specifies the table name for GORM.
+func (CustomMarketIndex) TableName() string {
+	return "market_indices"
+}
+
+// CustomMarketIndexID is the index ID of the custom index stored under id.
+func CustomMarketIndexID(id int64) string {
+	return fmt.Sprintf("%s-%d", MarketIndexSourceCustom, id)
+}
+
+// MarketIndexValue is an index's value at the end of one 1h bucket.
+// Constituents counts the items that moved it.
+type MarketIndexValue struct {
+	Timestamp    time.Time `gorm:"primaryKey;column:timestamp;type:timestamp with time zone" json:"timestamp"`
+	IndexID      string    `gorm:"primaryKey;column:index_id;type:varchar(64)" json:"-"`
+	Value        float64   `gorm:"column:value;not null" json:"value"`
+	Constituents int       `gorm:"column:constituents;not null" json:"constituents"`
+}
+
+// TableName specifies the table name for GORM.
+func (MarketIndexValue) TableName() string {
+	return "market_index_values"
+}
+
+// MarketIndexSummary is an index with its latest value and percentage
+// changes. Value and the changes are nil until the index has been computed
+// far enough back.
+type MarketIndexSummary struct {
+	Timestamp *time.Time `json:"timestamp"`
+	Value     *float64   `json:"value"`
+	Change1h  *float64   `json:"change1h"`
+	Change24h *float64   `json:"change24h"`
+	Change7d  *float64   `json:"change7d"`
+	MarketIndex
+}
+
+// MarketIndexHistory is an index's stored values, oldest first.
+type MarketIndexHistory struct {
+	From    time.Time          `json:"from"`
+	To      time.Time          `json:"to"`
+	IndexID string             `json:"indexId"`
+	Points  []MarketIndexValue `json:"points"`
+}
+
+// MarketIndexConstituent is one item of an index with its current weight,
+// from the GP traded over the last 24 hours for volume-weighted indices.
+type MarketIndexConstituent struct {
+	Price     *int64   `json:"price"`
+	Change24h *float64 `json:"change24h"`
+	Name      string   `json:"name"`
+	Weight    float64  `json:"weight"`
+	Volume24h int64    `json:"volume24h"`
+	ItemID    int      `json:"itemId"`
+}
+
+// MarketIndexConstituents lists an index's items, heaviest first.
+type MarketIndexConstituents struct {
+	ComputedAt   time.Time                `json:"computedAt"`
+	IndexID      string                   `json:"indexId"`
+	Weighting    MarketIndexWeighting     `json:"weighting"`
+	Constituents []MarketIndexConstituent `json:"constituents"`
+}
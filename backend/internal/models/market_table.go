@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// Market table limits and defaults.
+const (
+	DefaultMarketTableLimit = 50
+	MaxMarketTableLimit     = 200
+	MaxMarketTableQuery     = 100
+)
+
+// MarketTableColumns lists the market table columns that can be sorted on,
+// mapped to whether they are numeric and so can be filtered with the
+// min_<column> and max_<column> query parameters.
+var MarketTableColumns = map[string]bool{
+	"item_id":    true,
+	"name":       false,
+	"members":    false,
+	"category":   false,
+	"buy_limit":  true,
+	"high_price": true,
+	"low_price":  true,
+	"spread":     true,
+	"change_1h":  true,
+	"change_24h": true,
+	"change_7d":  true,
+	"volume_1h":  true,
+	"volume_24h": true,
+}
+
+// ItemStats is one item's row of the market table, precomputed after every
+// current prices sync. Price-derived fields are nil when unknown.
+type ItemStats struct {
+	UpdatedAt time.Time `gorm:"column:updated_at" json:"-"`
+	HighPrice *int64    `gorm:"column:high_price" json:"high"`
+	LowPrice  *int64    `gorm:"column:low_price" json:"low"`
+	Spread    *int64    `gorm:"column:spread" json:"spread"`
+	BuyLimit  *int64    `gorm:"column:buy_limit" json:"buyLimit"`
+	Change1h  *float64  `gorm:"column:change_1h" json:"change1h"`
+	Change24h *float64  `gorm:"column:change_24h" json:"change24h"`
+	Change7d  *float64  `gorm:"column:change_7d" json:"change7d"`
+	Name      string    `gorm:"column:name" json:"name"`
+	IconURL   string    `gorm:"column:icon_url" json:"iconUrl"`
+	Category  string    `gorm:"column:category" json:"category,omitempty"`
+	Volume1h  int64     `gorm:"column:volume_1h" json:"volume1h"`
+	Volume24h int64     `gorm:"column:volume_24h" json:"volume24h"`
+	ItemID    int       `gorm:"column:item_id;primaryKey" json:"itemId"`
+	Members   bool      `gorm:"column:members" json:"members"`
+}
+
+// TableName specifies the table name for GORM.
+func (ItemStats) TableName() string {
+	return "item_stats"
+}
+
+// MarketTableParams selects, sorts and pages market table rows. Min and Max
+// bound numeric columns (inclusive); rows without a value for a bounded
+// column are excluded. JSON-encoding the params identifies a query.
+type MarketTableParams struct {
+	Members  *bool              `json:"members,omitempty"`
+	Min      map[string]float64 `json:"min,omitempty"`
+	Max      map[string]float64 `json:"max,omitempty"`
+	Query    string             `json:"q,omitempty"`
+	Category string             `json:"category,omitempty"`
+	SortBy   string             `json:"sortBy"`
+	Order    string             `json:"order"`
+	Page     int                `json:"page"`
+	Limit    int                `json:"limit"`
+	Offset   int                `json:"-"`
+}
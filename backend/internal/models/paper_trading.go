@@ -0,0 +1,247 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"time"
+)
+
+// Paper trading limits. Balances, prices and quantities are capped at the
+// in-game maximum stack of 2,147,483,647.
+const (
+	DefaultPaperStartingBalance = 10_000_000
+	MaxPaperAmount              = math.MaxInt32
+	// MaxPaperOpenOffers matches the eight Grand Exchange offer slots.
+	MaxPaperOpenOffers = 8
+	// MaxPaperAccountsPerUser caps the accounts one user may open, which
+	// bounds the open offers every price sync has to check.
+	MaxPaperAccountsPerUser = 5
+)
+
+// Paper trading errors. Validation errors wrap ErrInvalidPaperRequest; the
+// others are returned unwrapped when an offer cannot be placed or cancelled.
+var (
+	ErrInvalidPaperRequest    = errors.New("invalid paper trading request")
+	ErrPaperAccountNotFound   = errors.New("paper account not found")
+	ErrPaperAccountLimit      = errors.New("paper account limit reached")
+	ErrPaperInsufficientFunds = errors.New("insufficient cash for this offer")
+	ErrPaperInsufficientItems = errors.New("not enough unreserved items to sell")
+	ErrPaperOfferSlotsFull    = errors.New("all offer slots are in use")
+	ErrPaperOfferClosed       = errors.New("offer is no longer open")
+)
+
+// PaperOfferSide is the direction of a simulated offer.
+type PaperOfferSide string
+
+const (
+	PaperOfferBuy  PaperOfferSide = "buy"
+	PaperOfferSell PaperOfferSide = "sell"
+)
+
+// IsValid checks if the side is supported.
+func (s PaperOfferSide) IsValid() bool {
+	return s == PaperOfferBuy || s == PaperOfferSell
+}
+
+// PaperOfferStatus is the lifecycle state of a simulated offer.
+type PaperOfferStatus string
+
+const (
+	PaperOfferOpen      PaperOfferStatus = "open"
+	PaperOfferFilled    PaperOfferStatus = "filled"
+	PaperOfferCancelled PaperOfferStatus = "cancelled"
+)
+
+// IsValid checks if the status is supported.
+func (s PaperOfferStatus) IsValid() bool {
+	switch s {
+	case PaperOfferOpen, PaperOfferFilled, PaperOfferCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// PaperAccount is a practice account trading simulated offers. Cash excludes
+// gold held by open buy offers.
+type PaperAccount struct {
+	CreatedAt       time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt       time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+	ID              string    `gorm:"primaryKey;column:id;type:uuid" json:"id"`
+	UserID          string    `gorm:"column:user_id;type:varchar(64);not null" json:"-"`
+	Name            string    `gorm:"column:name;type:varchar(100);not null" json:"name"`
+	StartingBalance int64     `gorm:"column:starting_balance;not null" json:"startingBalance"`
+	Cash            int64     `gorm:"column:cash;not null" json:"cash"`
+}
+
+// TableName specifies the table name for GORM.
+func (PaperAccount) TableName() string {
+	return "paper_accounts"
+}
+
+// PaperOffer is a simulated Grand Exchange offer. Buy offers hold
+// Price * Quantity gold until they fill or are cancelled; sell offers reserve
+// the items they sell.
+type PaperOffer struct {
+	CreatedAt   time.Time        `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time        `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+	CompletedAt *time.Time       `gorm:"column:completed_at" json:"completedAt,omitempty"`
+	AccountID   string           `gorm:"column:account_id;type:uuid;not null" json:"accountId"`
+	Side        PaperOfferSide   `gorm:"column:side;type:varchar(4);not null" json:"side"`
+	Status      PaperOfferStatus `gorm:"column:status;type:varchar(10);not null" json:"status"`
+	ID          int64            `gorm:"primaryKey;column:id" json:"id"`
+	Price       int64            `gorm:"column:price;not null" json:"price"`
+	Quantity    int64            `gorm:"column:quantity;not null" json:"quantity"`
+	Filled      int64            `gorm:"column:filled;not null" json:"filled"`
+	TaxPaid     int64            `gorm:"column:tax_paid;not null" json:"taxPaid"`
+	ItemID      int              `gorm:"column:item_id;not null" json:"itemId"`
+}
+
+// TableName specifies the table name for GORM.
+func (PaperOffer) TableName() string {
+	return "paper_offers"
+}
+
+// Remaining returns how many units are still unfilled.
+func (o *PaperOffer) Remaining() int64 {
+	return o.Quantity - o.Filled
+}
+
+// CrossedBy reports whether a later trade in update reached the offer price:
+// a buy fills once someone sells at or below it, a sell once someone buys at
+// or above it. Trades observed before the offer was placed do not count.
+// The returned time is when the crossing trade happened.
+func (o *PaperOffer) CrossedBy(update BulkPriceUpdate) (time.Time, bool) {
+	price, at := update.LowPrice, update.LowPriceTime
+	if o.Side == PaperOfferSell {
+		price, at = update.HighPrice, update.HighPriceTime
+	}
+	if price == nil || at == nil || !at.After(o.CreatedAt) {
+		return time.Time{}, false
+	}
+	if o.Side == PaperOfferBuy {
+		return *at, *price <= o.Price
+	}
+	return *at, *price >= o.Price
+}
+
+// PaperPosition is an account's holding of one item. Reserved units are
+// committed to open sell offers. CostBasis is what the held units cost, so
+// sales realize profit against the average cost.
+type PaperPosition struct {
+	UpdatedAt       time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+	BuyWindowStart  *time.Time `gorm:"column:buy_window_start" json:"-"`
+	AccountID       string     `gorm:"primaryKey;column:account_id;type:uuid" json:"accountId"`
+	Quantity        int64      `gorm:"column:quantity;not null" json:"quantity"`
+	Reserved        int64      `gorm:"column:reserved;not null" json:"reserved"`
+	CostBasis       int64      `gorm:"column:cost_basis;not null" json:"costBasis"`
+	RealizedProfit  int64      `gorm:"column:realized_profit;not null" json:"realizedProfit"`
+	TaxPaid         int64      `gorm:"column:tax_paid;not null" json:"taxPaid"`
+	BuyWindowBought int64      `gorm:"column:buy_window_bought;not null" json:"-"`
+	ItemID          int        `gorm:"primaryKey;column:item_id" json:"itemId"`
+}
+
+// TableName specifies the table name for GORM.
+func (PaperPosition) TableName() string {
+	return "paper_positions"
+}
+
+// BuyAllowance returns how many units may still be bought at `at` under the
+// item's buy limit, or -1 when the item has no known limit. A window opens at
+// the first purchase and lasts BuyLimitWindow.
+func (p *PaperPosition) BuyAllowance(limit int, at time.Time) int64 {
+	if limit <= 0 {
+		return -1
+	}
+	if p.BuyWindowStart != nil && at.Sub(*p.BuyWindowStart) < BuyLimitWindow {
+		return max(int64(limit)-p.BuyWindowBought, 0)
+	}
+	return int64(limit)
+}
+
+// RecordBuy adds quantity units bought at price at time `at`.
+func (p *PaperPosition) RecordBuy(quantity, price int64, at time.Time) {
+	if p.BuyWindowStart == nil || at.Sub(*p.BuyWindowStart) >= BuyLimitWindow {
+		start := at
+		p.BuyWindowStart = &start
+		p.BuyWindowBought = 0
+	}
+	p.BuyWindowBought += quantity
+	p.Quantity += quantity
+	p.CostBasis += quantity * price
+}
+
+// RecordSell removes quantity reserved units sold at price, paying tax in
+// total, and returns the realized profit.
+func (p *PaperPosition) RecordSell(quantity, price, tax int64) int64 {
+	var cost int64
+	if p.Quantity > 0 {
+		// CostBasis * quantity / Quantity without overflowing; quantity never
+		// exceeds Quantity, so the quotient fits.
+		hi, lo := bits.Mul64(uint64(p.CostBasis), uint64(quantity))
+		q, _ := bits.Div64(hi, lo, uint64(p.Quantity))
+		cost = int64(q)
+	}
+	profit := quantity*price - tax - cost
+	p.Quantity -= quantity
+	p.Reserved -= quantity
+	p.CostBasis -= cost
+	p.RealizedProfit += profit
+	p.TaxPaid += tax
+	return profit
+}
+
+// PaperFill records one simulated execution against an offer. Profit is set
+// for sells only.
+type PaperFill struct {
+	FilledAt  time.Time      `gorm:"column:filled_at;not null" json:"filledAt"`
+	Profit    *int64         `gorm:"column:profit" json:"profit,omitempty"`
+	AccountID string         `gorm:"column:account_id;type:uuid;not null" json:"accountId"`
+	Side      PaperOfferSide `gorm:"column:side;type:varchar(4);not null" json:"side"`
+	ID        int64          `gorm:"primaryKey;column:id" json:"id"`
+	OfferID   int64          `gorm:"column:offer_id;not null" json:"offerId"`
+	Quantity  int64          `gorm:"column:quantity;not null" json:"quantity"`
+	Price     int64          `gorm:"column:price;not null" json:"price"`
+	Tax       int64          `gorm:"column:tax;not null" json:"tax"`
+	ItemID    int            `gorm:"column:item_id;not null" json:"itemId"`
+	// OfferStatus is the offer's status after this fill.
+	OfferStatus PaperOfferStatus `gorm:"-" json:"offerStatus"`
+}
+
+// TableName specifies the table name for GORM.
+func (PaperFill) TableName() string {
+	return "paper_fills"
+}
+
+// PaperOfferRequest is the body of a new simulated offer.
+type PaperOfferRequest struct {
+	Side     PaperOfferSide `json:"side"`
+	Price    int64          `json:"price"`
+	Quantity int64          `json:"quantity"`
+	ItemID   int            `json:"itemId"`
+}
+
+// PaperPositionView is a position marked to the current instant-buy price.
+// MarketValue is after GE tax. Mark fields are nil without a current price.
+type PaperPositionView struct {
+	MarkPrice        *int64 `json:"markPrice"`
+	MarketValue      *int64 `json:"marketValue"`
+	UnrealizedProfit *int64 `json:"unrealizedProfit"`
+	// BuyLimitRemaining is nil when the item has no known buy limit.
+	BuyLimitRemaining *int64 `json:"buyLimitRemaining"`
+	ItemName          string `json:"itemName,omitempty"`
+	PaperPosition
+}
+
+// PaperAccountSummary is an account with its positions and open offers.
+// Equity counts cash, gold held by buy offers and marked positions; items
+// without a current price count at cost.
+type PaperAccountSummary struct {
+	Positions  []PaperPositionView `json:"positions"`
+	OpenOffers []PaperOffer        `json:"openOffers"`
+	PaperAccount
+	Escrow     int64 `json:"escrow"`
+	Equity     int64 `json:"equity"`
+	ProfitLoss int64 `json:"profitLoss"`
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Trade journal limits.
+const (
+	MaxJournalNoteLength   = 500
+	MaxJournalImportRows   = 10_000
+	DefaultJournalPageSize = 100
+	MaxJournalPageSize     = 1000
+)
+
+// ErrInvalidJournalEntry is wrapped by every journal validation and CSV
+// import error.
+var ErrInvalidJournalEntry = errors.New("invalid journal entry")
+
+// TradeSide is the direction of a recorded trade.
+type TradeSide string
+
+const (
+	TradeSideBuy  TradeSide = "buy"
+	TradeSideSell TradeSide = "sell"
+)
+
+// IsValid checks if the side is supported.
+func (s TradeSide) IsValid() bool {
+	return s == TradeSideBuy || s == TradeSideSell
+}
+
+// TradeJournalEntry is one real Grand Exchange trade recorded by a user.
+// Price is per unit; sells pay GE tax on top of it.
+type TradeJournalEntry struct {
+	TradedAt  time.Time `gorm:"column:traded_at;not null" json:"tradedAt"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UserID    string    `gorm:"column:user_id;type:varchar(64);not null" json:"userId"`
+	Side      TradeSide `gorm:"column:side;type:varchar(4);not null" json:"side"`
+	Note      string    `gorm:"column:note;type:text" json:"note,omitempty"`
+	ID        int64     `gorm:"primaryKey;column:id" json:"id"`
+	Quantity  int64     `gorm:"column:quantity;not null" json:"quantity"`
+	Price     int64     `gorm:"column:price;not null" json:"price"`
+	ItemID    int       `gorm:"column:item_id;not null" json:"itemId"`
+}
+
+// TableName specifies the table name for GORM.
+func (TradeJournalEntry) TableName() string {
+	return "trade_journal_entries"
+}
+
+// JournalListParams filters and pages a user's journal.
+type JournalListParams struct {
+	ItemID *int
+	From   *time.Time
+	To     *time.Time
+	Limit  int
+	Offset int
+}
+
+// PortfolioItemSummary is the FIFO P&L of one item. Held units are the buy
+// lots no sell has consumed yet; UnmatchedSellQuantity counts units sold
+// without a recorded buy, which realize no profit. Mark fields are nil
+// without a current price, and ProfitPerHour when the trades span under a
+// minute.
+type PortfolioItemSummary struct {
+	FirstTradeAt          time.Time `json:"firstTradeAt"`
+	LastTradeAt           time.Time `json:"lastTradeAt"`
+	AvgHeldCost           *float64  `json:"avgHeldCost"`
+	MarkPrice             *int64    `json:"markPrice"`
+	MarketValue           *int64    `json:"marketValue"`
+	UnrealizedProfit      *int64    `json:"unrealizedProfit"`
+	ProfitPerHour         *float64  `json:"profitPerHour"`
+	ItemName              string    `json:"itemName,omitempty"`
+	BoughtQuantity        int64     `json:"boughtQuantity"`
+	SoldQuantity          int64     `json:"soldQuantity"`
+	HeldQuantity          int64     `json:"heldQuantity"`
+	UnmatchedSellQuantity int64     `json:"unmatchedSellQuantity"`
+	HeldCost              int64     `json:"heldCost"`
+	RealizedProfit        int64     `json:"realizedProfit"`
+	TaxPaid               int64     `json:"taxPaid"`
+	Trades                int       `json:"trades"`
+	ItemID                int       `json:"itemId"`
+}
+
+// PortfolioSummary totals a user's journal. UnrealizedProfit sums the items
+// that have a current price; ProfitPerHour divides realized profit by the time
+// between the first and last trade.
+type PortfolioSummary struct {
+	FirstTradeAt     *time.Time             `json:"firstTradeAt"`
+	LastTradeAt      *time.Time             `json:"lastTradeAt"`
+	ProfitPerHour    *float64               `json:"profitPerHour"`
+	Items            []PortfolioItemSummary `json:"items"`
+	RealizedProfit   int64                  `json:"realizedProfit"`
+	UnrealizedProfit int64                  `json:"unrealizedProfit"`
+	TotalProfit      int64                  `json:"totalProfit"`
+	TaxPaid          int64                  `json:"taxPaid"`
+	HeldCost         int64                  `json:"heldCost"`
+	MarketValue      int64                  `json:"marketValue"`
+	Trades           int                    `json:"trades"`
+}
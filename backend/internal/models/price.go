@@ -3,6 +3,8 @@ package models
 
 import (
 	"time"
+
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
 )
 
 // CurrentPrice represents the latest price for an item.
@@ -22,6 +24,7 @@ type PriceHistoryParams struct {
 	EndTime   *time.Time
 	MaxPoints *int
 	Period    TimePeriod
+	Sampling  utils.SamplingMode
 	ItemID    int
 	Limit     int
 	Refresh   bool
@@ -32,6 +35,7 @@ type PriceHistoryResponse struct {
 	FirstDate *time.Time   `json:"firstDate,omitempty"`
 	LastDate  *time.Time   `json:"lastDate,omitempty"`
 	Period    string       `json:"period"`
+	Sampling  string       `json:"sampling"`
 	Data      []PricePoint `json:"data"`
 	ItemID    int          `json:"itemId"`
 	Count     int          `json:"count"`
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// PriceResolution identifies the table a stored price was read from.
+type PriceResolution string
+
+const (
+	ResolutionLatest PriceResolution = "latest"
+	Resolution5m     PriceResolution = "5m"
+	Resolution1h     PriceResolution = "1h"
+	Resolution6h     PriceResolution = "6h"
+	Resolution24h    PriceResolution = "24h"
+	ResolutionDaily  PriceResolution = "daily"
+)
+
+// PointInTimeResolutions lists resolutions from finest to coarsest; point-in-time
+// lookups use the first one that covers the requested timestamp.
+var PointInTimeResolutions = []PriceResolution{
+	ResolutionLatest,
+	Resolution5m,
+	Resolution1h,
+	Resolution6h,
+	Resolution24h,
+	ResolutionDaily,
+}
+
+// Coverage returns how far after a stored row's timestamp that row is still
+// considered to describe the market. Timeseries rows are bucket starts, so
+// they cover one bucket width; minute snapshots allow one missed sync.
+func (r PriceResolution) Coverage() time.Duration {
+	switch r {
+	case ResolutionLatest:
+		return 2 * time.Minute
+	case Resolution5m:
+		return 5 * time.Minute
+	case Resolution1h:
+		return time.Hour
+	case Resolution6h:
+		return 6 * time.Hour
+	default:
+		return 24 * time.Hour
+	}
+}
+
+// PriceAt is an item's stored price at (or covering) a requested timestamp.
+type PriceAt struct {
+	RequestedAt time.Time       `json:"requestedAt"`
+	Timestamp   time.Time       `json:"timestamp"`
+	HighPrice   *int64          `json:"highPrice"`
+	LowPrice    *int64          `json:"lowPrice"`
+	Resolution  PriceResolution `json:"resolution"`
+	ItemID      int             `json:"itemId"`
+}
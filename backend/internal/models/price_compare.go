@@ -0,0 +1,100 @@
+package models
+
+import "time"
+
+// PriceField selects which side of the market a derived series is built from.
+type PriceField string
+
+const (
+	PriceFieldMid  PriceField = "mid"
+	PriceFieldHigh PriceField = "high"
+	PriceFieldLow  PriceField = "low"
+)
+
+// IsValid checks if the price field is supported.
+func (f PriceField) IsValid() bool {
+	switch f {
+	case PriceFieldMid, PriceFieldHigh, PriceFieldLow:
+		return true
+	default:
+		return false
+	}
+}
+
+// Value picks the field from a high/low pair. Mid falls back to whichever
+// side is present when the other is missing.
+func (f PriceField) Value(high, low *int64) *float64 {
+	var v float64
+	switch f {
+	case PriceFieldHigh:
+		if high == nil {
+			return nil
+		}
+		v = float64(*high)
+	case PriceFieldLow:
+		if low == nil {
+			return nil
+		}
+		v = float64(*low)
+	default:
+		switch {
+		case high != nil && low != nil:
+			v = (float64(*high) + float64(*low)) / 2
+		case high != nil:
+			v = float64(*high)
+		case low != nil:
+			v = float64(*low)
+		default:
+			return nil
+		}
+	}
+	return &v
+}
+
+// ItemPair identifies an ordered A/B pair of items for ratio series.
+type ItemPair struct {
+	NumeratorID   int `json:"numeratorId"`
+	DenominatorID int `json:"denominatorId"`
+}
+
+// PriceCompareParams contains parameters for comparing several items.
+type PriceCompareParams struct {
+	StartTime *time.Time
+	EndTime   *time.Time
+	MaxPoints *int
+	Period    TimePeriod
+	Field     PriceField
+	ItemIDs   []int
+	// Pairs lists explicit ratio pairs; when empty and AllRatios is set every
+	// ordered combination of ItemIDs (i < j) is returned.
+	Pairs     []ItemPair
+	AllRatios bool
+}
+
+// CompareSeries is one item's aligned series in a comparison.
+type CompareSeries struct {
+	BaseTimestamp *time.Time `json:"baseTimestamp"`
+	BasePrice     *float64   `json:"basePrice"`
+	Prices        []*float64 `json:"prices"`
+	Index         []*float64 `json:"index"`
+	ItemID        int        `json:"itemId"`
+}
+
+// CompareRatio is an aligned A/B price ratio series.
+type CompareRatio struct {
+	Values []*float64 `json:"values"`
+	ItemPair
+}
+
+// PriceCompareResponse contains aligned series for several items on a common grid.
+type PriceCompareResponse struct {
+	Timestamps []time.Time     `json:"timestamps"`
+	Series     []CompareSeries `json:"series"`
+	Ratios     []CompareRatio  `json:"ratios,omitempty"`
+	Period     string          `json:"period"`
+	Field      string          `json:"field"`
+	Timestep   string          `json:"timestep"`
+	// IntervalSeconds is the spacing of the grid, a multiple of the timestep.
+	IntervalSeconds int64 `json:"intervalSeconds"`
+	Count           int   `json:"count"`
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Recipe limits.
+const (
+	MaxRecipeIDLength       = 64
+	MaxRecipeNameLength     = 100
+	MaxRecipeItems          = 10
+	MaxRecipeItemQuantity   = 10_000
+	MaxRecipeActionsPerHour = 100_000
+)
+
+// ErrInvalidRecipe is wrapped by every recipe definition validation error.
+var ErrInvalidRecipe = errors.New("invalid recipe")
+
+// RecipeItem is an item consumed or produced by one action of a recipe.
+type RecipeItem struct {
+	ItemID   int `json:"itemId"`
+	Quantity int `json:"quantity"`
+}
+
+// Recipe is a processing method: each action turns Inputs into Outputs. XP
+// is the experience per action, nil when the method gives none worth
+// counting. Source is "builtin" for shipped recipes and "custom" for ones
+// added or edited by an admin.
+type Recipe struct {
+	XP             *float64     `json:"xp,omitempty"`
+	ID             string       `json:"id"`
+	Name           string       `json:"name"`
+	Skill          string       `json:"skill,omitempty"`
+	Source         string       `json:"source,omitempty"`
+	Inputs         []RecipeItem `json:"inputs"`
+	Outputs        []RecipeItem `json:"outputs"`
+	ActionsPerHour int          `json:"actionsPerHour"`
+}
+
+// Recipe definition sources.
+const (
+	RecipeSourceBuiltin = "builtin"
+	RecipeSourceCustom  = "custom"
+)
+
+// RecipeOverride is an admin edit to the recipe registry. Definition holds
+// the recipe as JSON; it replaces the shipped recipe with the same ID, adds
+// a new one, or, when Removed is set, hides it.
+type RecipeOverride struct {
+	UpdatedAt  time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+	RecipeID   string         `gorm:"primaryKey;column:recipe_id;type:varchar(64)" json:"recipeId"`
+	Definition datatypes.JSON `gorm:"column:definition;type:jsonb;not null" json:"definition"`
+	Removed    bool           `gorm:"column:removed;not null;default:false" json:"removed"`
+}
+
+// TableName specifies the table name for GORM.
+func (RecipeOverride) TableName() string {
+	return "recipe_overrides"
+}
+
+// RecipeProfitItem is a recipe input or output with its current prices.
+type RecipeProfitItem struct {
+	HighPrice *int64 `json:"highPrice"`
+	LowPrice  *int64 `json:"lowPrice"`
+	Name      string `json:"name"`
+	ItemID    int    `json:"itemId"`
+	Quantity  int    `json:"quantity"`
+}
+
+// RecipeProfit is the profit of a recipe at current prices. Cost, Revenue,
+// Tax and Profit are per action; revenue is after GE tax on the outputs.
+// GPPerXP is profit per experience point, negative when training costs
+// money, and nil for recipes without XP.
+type RecipeProfit struct {
+	XP             *float64           `json:"xp"`
+	GPPerXP        *float64           `json:"gpPerXp"`
+	ID             string             `json:"id"`
+	Name           string             `json:"name"`
+	Skill          string             `json:"skill,omitempty"`
+	Inputs         []RecipeProfitItem `json:"inputs"`
+	Outputs        []RecipeProfitItem `json:"outputs"`
+	Cost           int64              `json:"cost"`
+	Revenue        int64              `json:"revenue"`
+	Tax            int64              `json:"tax"`
+	Profit         int64              `json:"profit"`
+	GPPerHour      int64              `json:"gpPerHour"`
+	ROI            float64            `json:"roi"`
+	ActionsPerHour int                `json:"actionsPerHour"`
+}
+
+// RecipeReport ranks every fully priced recipe. Unpriced lists the IDs of
+// recipes missing a price.
+type RecipeReport struct {
+	ComputedAt time.Time      `json:"computedAt"`
+	Pricing    PricingMode    `json:"pricing"`
+	Recipes    []RecipeProfit `json:"recipes"`
+	Unpriced   []string       `json:"unpriced"`
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Screener limits and defaults.
+const (
+	MaxScreenExpressionLength = 1000
+	MaxScreenNameLength       = 100
+	// MaxSavedScreens caps the screens one user may save.
+	MaxSavedScreens = 25
+
+	DefaultScreenerLimit = 50
+	MaxScreenerLimit     = 500
+	// MaxScreenAlertItems bounds the items listed in one screen alert.
+	MaxScreenAlertItems = 20
+)
+
+// ErrInvalidScreen is wrapped by screener expression and saved screen
+// validation errors.
+var ErrInvalidScreen = errors.New("invalid screen")
+
+// Screener field types.
+const (
+	ScreenerFieldNumber = "number"
+	ScreenerFieldString = "string"
+	ScreenerFieldBool   = "bool"
+	// ScreenerFieldTags is a set of strings; "tag = 'x'" tests membership.
+	ScreenerFieldTags = "tags"
+)
+
+// ScreenerField describes a field screens can filter and sort on.
+type ScreenerField struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+// ScreenerRow is one item's computed market fields, as screens see them.
+// Price-derived fields are nil when the prices they need are unknown.
+type ScreenerRow struct {
+	HighPrice   *int64   `json:"high"`
+	LowPrice    *int64   `json:"low"`
+	MidPrice    *int64   `json:"mid"`
+	Spread      *int64   `json:"spread"`
+	Margin      *int64   `json:"margin"`
+	ROI         *float64 `json:"roi"`
+	LimitProfit *int64   `json:"limitProfit"`
+	HighAlch    *int64   `json:"highAlch"`
+	AlchProfit  *int64   `json:"alchProfit"`
+	BuyLimit    *int64   `json:"buyLimit"`
+	Change1h    *float64 `json:"change1h"`
+	Change24h   *float64 `json:"change24h"`
+	Change7d    *float64 `json:"change7d"`
+	Name        string   `json:"name"`
+	IconURL     string   `json:"iconUrl"`
+	Category    string   `json:"category,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Volume1h    int64    `json:"volume1h"`
+	Volume24h   int64    `json:"volume24h"`
+	ItemID      int      `json:"itemId"`
+	Members     bool     `json:"members"`
+}
+
+// ScreenerResult is the items matching a screen, in its order.
+type ScreenerResult struct {
+	AsOf       time.Time     `json:"asOf"`
+	Expression string        `json:"expression"`
+	Rows       []ScreenerRow `json:"rows"`
+	Total      int           `json:"total"`
+}
+
+// Screen is a saved screener expression owned by one user. With Alert set
+// it is checked after every price sync and notifies its owner when items
+// start matching.
+type Screen struct {
+	CreatedAt    time.Time      `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt    time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+	LastAlertAt  *time.Time     `gorm:"column:last_alert_at" json:"lastAlertAt"`
+	UserID       string         `gorm:"column:user_id;type:varchar(64);not null;index" json:"-"`
+	Name         string         `gorm:"column:name;type:varchar(100);not null" json:"name"`
+	Expression   string         `gorm:"column:expression;type:text;not null" json:"expression"`
+	AlertItemIDs datatypes.JSON `gorm:"column:alert_item_ids;type:jsonb" json:"-"`
+	ID           int64          `gorm:"primaryKey;column:id" json:"id"`
+	Alert        bool           `gorm:"column:alert;not null;default:false" json:"alert"`
+}
+
+// TableName specifies the table name for GORM.
+func (Screen) TableName() string {
+	return "screens"
+}
+
+// ScreenAlert announces the items that started matching an alerting screen
+// since it was last checked.
+type ScreenAlert struct {
+	Timestamp time.Time `json:"timestamp"`
+	UserID    string    `json:"userId"`
+	Name      string    `json:"name"`
+	ItemIDs   []int     `json:"itemIds"`
+	ScreenID  int64     `json:"screenId"`
+	// Count is the number of items that started matching; ItemIDs lists at
+	// most MaxScreenAlertItems of them.
+	Count int `json:"count"`
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// DefaultSeasonalityLookbackDays is the lookback used when none is given.
+const DefaultSeasonalityLookbackDays = 28
+
+// MaxTrackedSeasonalityProfiles caps the profiles kept warm by the refresh
+// job; the least recently requested is dropped first.
+const MaxTrackedSeasonalityProfiles = 500
+
+// SeasonalityLookbackDays lists the supported lookbacks in days. The longest
+// follows the 1h table retention.
+var SeasonalityLookbackDays = []int{7, 14, 28, 56, 90}
+
+// IsSeasonalityLookback reports whether days is a supported lookback.
+func IsSeasonalityLookback(days int) bool {
+	return slices.Contains(SeasonalityLookbackDays, days)
+}
+
+// HourlyPricePoint is one hour of average prices and traded volume for an item.
+type HourlyPricePoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	HighPrice *float64  `json:"highPrice"`
+	LowPrice  *float64  `json:"lowPrice"`
+	MidPrice  float64   `json:"midPrice"`
+	Volume    int64     `json:"volume"`
+}
+
+// FieldValue returns the requested side of the hour, or nil when that side had no trades.
+func (p HourlyPricePoint) FieldValue(field PriceField) *float64 {
+	switch field {
+	case PriceFieldHigh:
+		return p.HighPrice
+	case PriceFieldLow:
+		return p.LowPrice
+	default:
+		mid := p.MidPrice
+		return &mid
+	}
+}
+
+// SeasonalityParams contains parameters for a seasonality analysis.
+type SeasonalityParams struct {
+	ItemID       int
+	LookbackDays int
+	Refresh      bool
+}
+
+// SeasonalityBucket is the average behaviour of one hour-of-day (0-23, UTC) or
+// day-of-week (0 = Sunday) slot. Relative values are ratios to the surrounding
+// average, so 1.02 means 2% above normal.
+type SeasonalityBucket struct {
+	RelativePrice  *float64 `json:"relativePrice"`
+	RelativeVolume *float64 `json:"relativeVolume"`
+	// PriceStdErr is the standard error of RelativePrice.
+	PriceStdErr *float64 `json:"priceStdErr"`
+	// PriceCILow and PriceCIHigh bound the 95% confidence interval of RelativePrice.
+	PriceCILow  *float64 `json:"priceCiLow"`
+	PriceCIHigh *float64 `json:"priceCiHigh"`
+	Index       int      `json:"index"`
+	Samples     int      `json:"samples"`
+}
+
+// SeasonalityPattern is a full cycle of buckets plus how much the cycle explains.
+type SeasonalityPattern struct {
+	// Strength is the share of relative price variance explained by the bucket
+	// (eta squared, 0-1). Values near 0 mean the pattern is mostly noise.
+	Strength    *float64            `json:"strength"`
+	PeakIndex   *int                `json:"peakIndex"`
+	TroughIndex *int                `json:"troughIndex"`
+	Buckets     []SeasonalityBucket `json:"buckets"`
+}
+
+// SeasonalityProfile is the hour-of-day and day-of-week price/volume profile of an item.
+type SeasonalityProfile struct {
+	GeneratedAt    time.Time          `json:"generatedAt"`
+	FirstTimestamp *time.Time         `json:"firstTimestamp,omitempty"`
+	LastTimestamp  *time.Time         `json:"lastTimestamp,omitempty"`
+	HourOfDay      SeasonalityPattern `json:"hourOfDay"`
+	DayOfWeek      SeasonalityPattern `json:"dayOfWeek"`
+	// Coverage is the fraction of hours in the lookback that had data.
+	Coverage     float64 `json:"coverage"`
+	ItemID       int     `json:"itemId"`
+	LookbackDays int     `json:"lookbackDays"`
+	Observations int     `json:"observations"`
+	Days         int     `json:"days"`
+}
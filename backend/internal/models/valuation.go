@@ -0,0 +1,68 @@
+package models
+
+import "errors"
+
+// MaxBankExportEntries caps the rows in one bank export. A full bank with
+// every tab holds well under this.
+const MaxBankExportEntries = 5000
+
+// ErrInvalidBankExport is wrapped by every bank export parse error.
+var ErrInvalidBankExport = errors.New("invalid bank export")
+
+// BankEntry is one row of a bank export. Name is optional and only used to
+// resolve IDs the item mapping does not know, such as noted items and
+// placeholders. Placeholders have a zero quantity.
+type BankEntry struct {
+	Name     string `json:"name,omitempty"`
+	Quantity int64  `json:"quantity"`
+	ItemID   int    `json:"itemId"`
+}
+
+// How a bank entry's ID was matched to a Grand Exchange item.
+const (
+	BankResolutionDirect   = "direct"
+	BankResolutionName     = "name"
+	BankResolutionNoted    = "noted"
+	BankResolutionCurrency = "currency"
+)
+
+// BankValuationItem is the value of one item across every export row that
+// resolved to it. Price fields are per unit and nil without a current price;
+// value fields multiply them by Quantity.
+type BankValuationItem struct {
+	HighPrice     *int64 `json:"highPrice"`
+	LowPrice      *int64 `json:"lowPrice"`
+	MidPrice      *int64 `json:"midPrice"`
+	HighValue     *int64 `json:"highValue"`
+	LowValue      *int64 `json:"lowValue"`
+	MidValue      *int64 `json:"midValue"`
+	HighAlch      *int   `json:"highAlch"`
+	HighAlchValue *int64 `json:"highAlchValue"`
+	Name          string `json:"name"`
+	Resolution    string `json:"resolution"`
+	SourceItemIDs []int  `json:"sourceItemIds"`
+	Quantity      int64  `json:"quantity"`
+	ItemID        int    `json:"itemId"`
+}
+
+// BankValuationUnresolved is an export row that could not be valued.
+type BankValuationUnresolved struct {
+	Name     string `json:"name,omitempty"`
+	Reason   string `json:"reason"`
+	Quantity int64  `json:"quantity"`
+	ItemID   int    `json:"itemId"`
+}
+
+// BankValuation totals a bank at current prices. Items without a price on
+// one side contribute nothing to that side's total and are counted in
+// UnpricedItems.
+type BankValuation struct {
+	Items         []BankValuationItem       `json:"items"`
+	Unresolved    []BankValuationUnresolved `json:"unresolved"`
+	TotalHigh     int64                     `json:"totalHigh"`
+	TotalLow      int64                     `json:"totalLow"`
+	TotalMid      int64                     `json:"totalMid"`
+	TotalHighAlch int64                     `json:"totalHighAlch"`
+	Placeholders  int                       `json:"placeholders"`
+	UnpricedItems int                       `json:"unpricedItems"`
+}
@@ -1,11 +1,19 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"gorm.io/datatypes"
 )
 
+// Watchlist share lookup errors.
+var (
+	ErrInvalidShareToken = errors.New("invalid token format")
+	ErrShareNotFound     = errors.New("share not found")
+	ErrShareExpired      = errors.New("share has expired")
+)
+
 // WatchlistShare represents a temporarily shared watchlist with a memorable token.
 type WatchlistShare struct {
 	// Token is the memorable share identifier (adjective-adjective-noun format)
@@ -0,0 +1,202 @@
+package portfolio
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// CSV column names. Import requires the first five and ignores unknown
+// columns, so an exported file can be imported again unchanged.
+const (
+	colItemID   = "item_id"
+	colSide     = "side"
+	colQuantity = "quantity"
+	colPrice    = "price"
+	colTradedAt = "traded_at"
+	colNote     = "note"
+	colItemName = "item_name"
+	colID       = "id"
+)
+
+var requiredColumns = []string{colItemID, colSide, colQuantity, colPrice, colTradedAt}
+
+// formulaTriggers are the leading characters that make spreadsheets evaluate
+// a cell as a formula.
+const formulaTriggers = "=+-@\t\r"
+
+// csvTimeLayouts are the timestamp formats accepted on import. Timestamps
+// without a zone are read as UTC.
+var csvTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ReadCSV parses a journal CSV with a header row. Entries are validated with
+// Validate; the first bad row fails the whole import with its line number.
+// Errors wrap models.ErrInvalidJournalEntry.
+func ReadCSV(r io.Reader, now time.Time) ([]models.TradeJournalEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, invalid("CSV is empty")
+	}
+	if err != nil {
+		return nil, invalid("malformed CSV: %v", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, invalid("CSV header must include %s", strings.Join(requiredColumns, ", "))
+		}
+	}
+
+	entries := make([]models.TradeJournalEntry, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			return nil, invalid("malformed CSV: %v", err)
+		}
+		if isBlank(record) {
+			continue
+		}
+		if len(entries) == models.MaxJournalImportRows {
+			return nil, invalid("CSV must have at most %d rows", models.MaxJournalImportRows)
+		}
+
+		entry, err := parseRecord(record, columns)
+		if err == nil {
+			err = Validate(&entry, now)
+		}
+		if err != nil {
+			return nil, invalid("line %d: %s", line, strings.TrimPrefix(err.Error(), models.ErrInvalidJournalEntry.Error()+": "))
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		return nil, invalid("CSV has no rows")
+	}
+	return entries, nil
+}
+
+func parseRecord(record []string, columns map[string]int) (models.TradeJournalEntry, error) {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var entry models.TradeJournalEntry
+	itemID, err := strconv.Atoi(field(colItemID))
+	if err != nil {
+		return entry, invalid("item_id must be a number")
+	}
+	quantity, err := parseAmount(field(colQuantity))
+	if err != nil {
+		return entry, invalid("quantity must be a whole number")
+	}
+	price, err := parseAmount(field(colPrice))
+	if err != nil {
+		return entry, invalid("price must be a whole number")
+	}
+	tradedAt, err := parseCSVTime(field(colTradedAt))
+	if err != nil {
+		return entry, invalid("traded_at must be an RFC3339 or YYYY-MM-DD HH:MM:SS timestamp")
+	}
+
+	entry.ItemID = itemID
+	entry.Side = models.TradeSide(field(colSide))
+	entry.Quantity = quantity
+	entry.Price = price
+	entry.TradedAt = tradedAt
+	entry.Note = unescapeCell(field(colNote))
+	return entry, nil
+}
+
+// parseAmount parses a whole number, allowing the thousands separators
+// spreadsheets add.
+func parseAmount(raw string) (int64, error) {
+	return strconv.ParseInt(strings.NewReplacer(",", "", "_", "", " ", "").Replace(raw), 10, 64)
+}
+
+func parseCSVTime(raw string) (time.Time, error) {
+	for _, layout := range csvTimeLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized timestamp %q", raw)
+}
+
+func isBlank(record []string) bool {
+	for _, f := range record {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteCSV writes a journal with a header row. names supplies the optional
+// item_name column. Text cells that start like a formula are escaped.
+func WriteCSV(w io.Writer, entries []models.TradeJournalEntry, names map[int]string) error {
+	writer := csv.NewWriter(w)
+	header := []string{colID, colItemID, colItemName, colSide, colQuantity, colPrice, colTradedAt, colNote}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		record := []string{
+			strconv.FormatInt(e.ID, 10),
+			strconv.Itoa(e.ItemID),
+			escapeCell(names[e.ItemID]),
+			string(e.Side),
+			strconv.FormatInt(e.Quantity, 10),
+			strconv.FormatInt(e.Price, 10),
+			e.TradedAt.UTC().Format(time.RFC3339),
+			escapeCell(e.Note),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// escapeCell prefixes text that a spreadsheet would run as a formula with an
+// apostrophe, so an exported note such as "=HYPERLINK(...)" stays text.
+func escapeCell(s string) string {
+	if s != "" && strings.ContainsRune(formulaTriggers, rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// unescapeCell reverses escapeCell, so exported journals import unchanged.
+func unescapeCell(s string) string {
+	if len(s) > 1 && s[0] == '\'' && strings.ContainsRune(formulaTriggers, rune(s[1])) {
+		return s[1:]
+	}
+	return s
+}
@@ -0,0 +1,162 @@
+// Package portfolio computes profit and loss from a user's trade journal and
+// converts the journal to and from CSV.
+package portfolio
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
+)
+
+// maxClockSkew is how far in the future a trade timestamp may be, to allow for
+// client clocks running ahead.
+const maxClockSkew = 5 * time.Minute
+
+// Validate checks a journal entry and normalizes its fields. Errors wrap
+// models.ErrInvalidJournalEntry.
+func Validate(entry *models.TradeJournalEntry, now time.Time) error {
+	entry.Side = models.TradeSide(strings.ToLower(strings.TrimSpace(string(entry.Side))))
+	entry.Note = strings.TrimSpace(entry.Note)
+
+	switch {
+	case entry.ItemID <= 0:
+		return invalid("itemId is required")
+	case !entry.Side.IsValid():
+		return invalid("side must be one of: buy, sell")
+	case entry.Quantity < 1 || entry.Quantity > math.MaxInt32:
+		return invalid("quantity must be between 1 and %d", math.MaxInt32)
+	case entry.Price < 1 || entry.Price > math.MaxInt32:
+		return invalid("price must be between 1 and %d", math.MaxInt32)
+	case entry.TradedAt.IsZero():
+		return invalid("tradedAt is required")
+	case entry.TradedAt.After(now.Add(maxClockSkew)):
+		return invalid("tradedAt must not be in the future")
+	case len(entry.Note) > models.MaxJournalNoteLength:
+		return invalid("note must be at most %d characters", models.MaxJournalNoteLength)
+	}
+	entry.TradedAt = entry.TradedAt.UTC()
+	return nil
+}
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", models.ErrInvalidJournalEntry, fmt.Sprintf(format, args...))
+}
+
+// lot is the unsold part of one buy.
+type lot struct {
+	quantity int64
+	price    int64
+}
+
+// Summarize computes FIFO profit and loss for a journal. Each sell consumes
+// the oldest open buy lots of its item and realizes proceeds after GE tax
+// minus their cost. Lots still open are marked to marks (the current
+// instant-buy price per item) after tax. Entries may be in any order; ties on
+// TradedAt are broken by ID so a buy and sell logged together stay in order.
+func Summarize(entries []models.TradeJournalEntry, marks map[int]int64) models.PortfolioSummary {
+	sorted := append([]models.TradeJournalEntry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].TradedAt.Equal(sorted[j].TradedAt) {
+			return sorted[i].TradedAt.Before(sorted[j].TradedAt)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	items := make(map[int]*models.PortfolioItemSummary)
+	lots := make(map[int][]lot)
+	for _, e := range sorted {
+		item, ok := items[e.ItemID]
+		if !ok {
+			item = &models.PortfolioItemSummary{ItemID: e.ItemID, FirstTradeAt: e.TradedAt}
+			items[e.ItemID] = item
+		}
+		item.Trades++
+		item.LastTradeAt = e.TradedAt
+
+		if e.Side == models.TradeSideBuy {
+			item.BoughtQuantity += e.Quantity
+			lots[e.ItemID] = append(lots[e.ItemID], lot{quantity: e.Quantity, price: e.Price})
+			continue
+		}
+
+		item.SoldQuantity += e.Quantity
+		unitTax := utils.GETax(e.ItemID, e.Price)
+		item.TaxPaid += e.Quantity * unitTax
+
+		remaining := e.Quantity
+		open := lots[e.ItemID]
+		for remaining > 0 && len(open) > 0 {
+			matched := min(remaining, open[0].quantity)
+			item.RealizedProfit += matched * (e.Price - unitTax - open[0].price)
+			open[0].quantity -= matched
+			remaining -= matched
+			if open[0].quantity == 0 {
+				open = open[1:]
+			}
+		}
+		lots[e.ItemID] = open
+		item.UnmatchedSellQuantity += remaining
+	}
+
+	summary := models.PortfolioSummary{Items: make([]models.PortfolioItemSummary, 0, len(items))}
+	for itemID, item := range items {
+		for _, l := range lots[itemID] {
+			item.HeldQuantity += l.quantity
+			item.HeldCost += l.quantity * l.price
+		}
+		if item.HeldQuantity > 0 {
+			avg := float64(item.HeldCost) / float64(item.HeldQuantity)
+			item.AvgHeldCost = &avg
+			if mark, ok := marks[itemID]; ok && mark > 0 {
+				value := item.HeldQuantity * (mark - utils.GETax(itemID, mark))
+				unrealized := value - item.HeldCost
+				item.MarkPrice = &mark
+				item.MarketValue = &value
+				item.UnrealizedProfit = &unrealized
+				summary.MarketValue += value
+				summary.UnrealizedProfit += unrealized
+			}
+		}
+		item.ProfitPerHour = perHour(item.RealizedProfit, item.FirstTradeAt, item.LastTradeAt)
+
+		summary.RealizedProfit += item.RealizedProfit
+		summary.TaxPaid += item.TaxPaid
+		summary.HeldCost += item.HeldCost
+		summary.Trades += item.Trades
+		summary.Items = append(summary.Items, *item)
+	}
+	summary.TotalProfit = summary.RealizedProfit + summary.UnrealizedProfit
+
+	if len(sorted) > 0 {
+		first, last := sorted[0].TradedAt, sorted[len(sorted)-1].TradedAt
+		summary.FirstTradeAt = &first
+		summary.LastTradeAt = &last
+		summary.ProfitPerHour = perHour(summary.RealizedProfit, first, last)
+	}
+
+	// Biggest realized profit first, then by item ID for a stable order.
+	sort.Slice(summary.Items, func(i, j int) bool {
+		a, b := summary.Items[i], summary.Items[j]
+		if a.RealizedProfit != b.RealizedProfit {
+			return a.RealizedProfit > b.RealizedProfit
+		}
+		return a.ItemID < b.ItemID
+	})
+	return summary
+}
+
+// perHour spreads profit over the time between first and last, or returns nil
+// when they are less than a minute apart.
+func perHour(profit int64, first, last time.Time) *float64 {
+	span := last.Sub(first)
+	if span < time.Minute {
+		return nil
+	}
+	rate := float64(profit) / span.Hours()
+	return &rate
+}
@@ -0,0 +1,142 @@
+package recipes
+
+import (
+	"sort"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
+)
+
+// Recipe sort orders.
+const (
+	SortByProfit    = "profit"
+	SortByGPPerHour = "gp_per_hour"
+	SortByGPPerXP   = "gp_per_xp"
+)
+
+// Evaluate prices each recipe at current prices, most profit per action
+// first, and returns the IDs of recipes missing a price. Inputs are bought
+// and outputs sold under mode, with GE tax charged on each output. items
+// supplies names; prices are keyed by item ID.
+func Evaluate(
+	recipes []models.Recipe,
+	items map[int]models.Item,
+	prices map[int]models.CurrentPrice,
+	mode models.PricingMode,
+) ([]models.RecipeProfit, []string) {
+	results := make([]models.RecipeProfit, 0, len(recipes))
+	unpriced := make([]string, 0)
+	for _, recipe := range recipes {
+		p, ok := evaluate(recipe, items, prices, mode)
+		if !ok {
+			unpriced = append(unpriced, recipe.ID)
+			continue
+		}
+		results = append(results, p)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Profit != results[j].Profit {
+			return results[i].Profit > results[j].Profit
+		}
+		return results[i].ID < results[j].ID
+	})
+	sort.Strings(unpriced)
+	return results, unpriced
+}
+
+func evaluate(
+	recipe models.Recipe,
+	items map[int]models.Item,
+	prices map[int]models.CurrentPrice,
+	mode models.PricingMode,
+) (models.RecipeProfit, bool) {
+	p := models.RecipeProfit{
+		ID:             recipe.ID,
+		Name:           recipe.Name,
+		Skill:          recipe.Skill,
+		XP:             recipe.XP,
+		ActionsPerHour: recipe.ActionsPerHour,
+		Inputs:         profitItems(recipe.Inputs, items, prices),
+		Outputs:        profitItems(recipe.Outputs, items, prices),
+	}
+
+	priced := true
+	for _, in := range recipe.Inputs {
+		buy := mode.BuyPrice(prices[in.ItemID])
+		if buy == nil {
+			priced = false
+			continue
+		}
+		p.Cost += *buy * int64(in.Quantity)
+	}
+	for _, out := range recipe.Outputs {
+		sell := mode.SellPrice(prices[out.ItemID])
+		if sell == nil {
+			priced = false
+			continue
+		}
+		tax := utils.GETax(out.ItemID, *sell)
+		p.Revenue += (*sell - tax) * int64(out.Quantity)
+		p.Tax += tax * int64(out.Quantity)
+	}
+	if !priced {
+		return p, false
+	}
+
+	p.Profit = p.Revenue - p.Cost
+	p.GPPerHour = p.Profit * int64(recipe.ActionsPerHour)
+	if p.Cost > 0 {
+		p.ROI = float64(p.Profit) / float64(p.Cost) * 100
+	}
+	if recipe.XP != nil {
+		gpPerXP := float64(p.Profit) / *recipe.XP
+		p.GPPerXP = &gpPerXP
+	}
+	return p, true
+}
+
+func profitItems(
+	recipeItems []models.RecipeItem,
+	items map[int]models.Item,
+	prices map[int]models.CurrentPrice,
+) []models.RecipeProfitItem {
+	out := make([]models.RecipeProfitItem, 0, len(recipeItems))
+	for _, ri := range recipeItems {
+		p := prices[ri.ItemID]
+		out = append(out, models.RecipeProfitItem{
+			ItemID:    ri.ItemID,
+			Name:      items[ri.ItemID].Name,
+			Quantity:  ri.Quantity,
+			HighPrice: p.HighPrice,
+			LowPrice:  p.LowPrice,
+		})
+	}
+	return out
+}
+
+// Sort orders recipes by the field, highest first. Recipes without XP sort
+// last by gp_per_xp.
+func Sort(recipes []models.RecipeProfit, by string) {
+	key := func(p models.RecipeProfit) (float64, bool) {
+		switch by {
+		case SortByGPPerHour:
+			return float64(p.GPPerHour), true
+		case SortByGPPerXP:
+			if p.GPPerXP == nil {
+				return 0, false
+			}
+			return *p.GPPerXP, true
+		default:
+			return float64(p.Profit), true
+		}
+	}
+	sort.SliceStable(recipes, func(i, j int) bool {
+		a, aok := key(recipes[i])
+		b, bok := key(recipes[j])
+		if aok != bok {
+			return aok
+		}
+		return a > b
+	})
+}
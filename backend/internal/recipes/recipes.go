@@ -0,0 +1,134 @@
+// Package recipes prices processing methods, such as cleaning herbs or
+// stringing bows, that turn bought items into items worth selling.
+package recipes
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+//go:embed recipes.json
+var defaultRecipesJSON []byte
+
+var recipeIDPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// DefaultRecipes returns the recipes shipped with the application.
+func DefaultRecipes() ([]models.Recipe, error) {
+	var recipes []models.Recipe
+	if err := json.Unmarshal(defaultRecipesJSON, &recipes); err != nil {
+		return nil, fmt.Errorf("parse embedded recipes: %w", err)
+	}
+	for i := range recipes {
+		if err := ValidateRecipe(&recipes[i]); err != nil {
+			return nil, fmt.Errorf("embedded recipe %q: %w", recipes[i].ID, err)
+		}
+		recipes[i].Source = models.RecipeSourceBuiltin
+	}
+	return recipes, nil
+}
+
+// ValidateRecipe checks a recipe definition, trimming its name and skill and
+// defaulting item quantities to 1. Errors wrap models.ErrInvalidRecipe.
+func ValidateRecipe(recipe *models.Recipe) error {
+	recipe.Name = strings.TrimSpace(recipe.Name)
+	recipe.Skill = strings.TrimSpace(recipe.Skill)
+	switch {
+	case len(recipe.ID) > models.MaxRecipeIDLength || !recipeIDPattern.MatchString(recipe.ID):
+		return invalidRecipe("id must be 1-%d lowercase letters, digits and single dashes", models.MaxRecipeIDLength)
+	case recipe.Name == "" || utf8.RuneCountInString(recipe.Name) > models.MaxRecipeNameLength:
+		return invalidRecipe("name must be 1-%d characters", models.MaxRecipeNameLength)
+	case utf8.RuneCountInString(recipe.Skill) > models.MaxRecipeNameLength:
+		return invalidRecipe("skill must be at most %d characters", models.MaxRecipeNameLength)
+	case recipe.ActionsPerHour < 1 || recipe.ActionsPerHour > models.MaxRecipeActionsPerHour:
+		return invalidRecipe("actionsPerHour must be between 1 and %d", models.MaxRecipeActionsPerHour)
+	case recipe.XP != nil && (*recipe.XP <= 0 || math.IsInf(*recipe.XP, 0) || math.IsNaN(*recipe.XP)):
+		return invalidRecipe("xp must be positive when set")
+	}
+	if err := validateRecipeItems("input", recipe.Inputs); err != nil {
+		return err
+	}
+	return validateRecipeItems("output", recipe.Outputs)
+}
+
+func validateRecipeItems(kind string, items []models.RecipeItem) error {
+	if len(items) < 1 || len(items) > models.MaxRecipeItems {
+		return invalidRecipe("a recipe must have 1-%d %ss", models.MaxRecipeItems, kind)
+	}
+	seen := make(map[int]struct{}, len(items))
+	for i := range items {
+		item := &items[i]
+		if item.Quantity == 0 {
+			item.Quantity = 1
+		}
+		switch {
+		case item.ItemID <= 0:
+			return invalidRecipe("%s %d: itemId is required", kind, i+1)
+		case item.Quantity < 0 || item.Quantity > models.MaxRecipeItemQuantity:
+			return invalidRecipe("%s %d: quantity must be between 1 and %d", kind, i+1, models.MaxRecipeItemQuantity)
+		}
+		if _, dup := seen[item.ItemID]; dup {
+			return invalidRecipe("%s %d: item %d is listed twice", kind, i+1, item.ItemID)
+		}
+		seen[item.ItemID] = struct{}{}
+	}
+	return nil
+}
+
+// MergeRecipes applies admin overrides to the shipped recipes: an override
+// replaces the recipe with the same ID or adds a new one, and a removed
+// override hides it. Invalid overrides are skipped and reported in the
+// returned error. The result is ordered by skill, then name.
+func MergeRecipes(defaults []models.Recipe, overrides []models.RecipeOverride) ([]models.Recipe, error) {
+	byID := make(map[string]models.Recipe, len(defaults)+len(overrides))
+	for _, recipe := range defaults {
+		byID[recipe.ID] = recipe
+	}
+
+	var errs []error
+	for _, o := range overrides {
+		if o.Removed {
+			delete(byID, o.RecipeID)
+			continue
+		}
+		var recipe models.Recipe
+		if err := json.Unmarshal(o.Definition, &recipe); err != nil {
+			errs = append(errs, fmt.Errorf("recipe override %q: %w", o.RecipeID, err))
+			continue
+		}
+		recipe.ID = o.RecipeID
+		if err := ValidateRecipe(&recipe); err != nil {
+			errs = append(errs, fmt.Errorf("recipe override %q: %w", o.RecipeID, err))
+			continue
+		}
+		recipe.Source = models.RecipeSourceCustom
+		byID[o.RecipeID] = recipe
+	}
+
+	recipes := make([]models.Recipe, 0, len(byID))
+	for _, recipe := range byID {
+		recipes = append(recipes, recipe)
+	}
+	sort.Slice(recipes, func(i, j int) bool {
+		if recipes[i].Skill != recipes[j].Skill {
+			return recipes[i].Skill < recipes[j].Skill
+		}
+		if recipes[i].Name != recipes[j].Name {
+			return recipes[i].Name < recipes[j].Name
+		}
+		return recipes[i].ID < recipes[j].ID
+	})
+	return recipes, errors.Join(errs...)
+}
+
+func invalidRecipe(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", models.ErrInvalidRecipe, fmt.Sprintf(format, args...))
+}
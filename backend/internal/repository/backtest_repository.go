@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// backtestRepository implements BacktestRepository.
+type backtestRepository struct {
+	dbClient *gorm.DB
+	logger   *zap.SugaredLogger
+}
+
+// NewBacktestRepository creates a new backtest repository.
+func NewBacktestRepository(dbClient *gorm.DB, logger *zap.SugaredLogger) BacktestRepository {
+	return &backtestRepository{
+		dbClient: dbClient,
+		logger:   logger,
+	}
+}
+
+// Create stores a new backtest job.
+func (r *backtestRepository) Create(ctx context.Context, backtest *models.Backtest) error {
+	if err := r.dbClient.WithContext(ctx).Create(backtest).Error; err != nil {
+		r.logger.Errorw("Failed to create backtest", "id", backtest.ID, "error", err)
+		return fmt.Errorf("failed to create backtest: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns a backtest by ID, or nil when it does not exist.
+func (r *backtestRepository) GetByID(ctx context.Context, id string) (*models.Backtest, error) {
+	var backtest models.Backtest
+	if err := r.dbClient.WithContext(ctx).Where("id = ?", id).First(&backtest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Errorw("Failed to get backtest", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to get backtest: %w", err)
+	}
+	return &backtest, nil
+}
+
+// List returns the most recent backtests without their results.
+func (r *backtestRepository) List(ctx context.Context, limit int) ([]models.Backtest, error) {
+	var backtests []models.Backtest
+	err := r.dbClient.WithContext(ctx).
+		Omit("result").
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&backtests).Error
+	if err != nil {
+		r.logger.Errorw("Failed to list backtests", "error", err)
+		return nil, fmt.Errorf("failed to list backtests: %w", err)
+	}
+	return backtests, nil
+}
+
+// Update saves the status, timestamps, result and error of a backtest.
+func (r *backtestRepository) Update(ctx context.Context, backtest *models.Backtest) error {
+	err := r.dbClient.WithContext(ctx).
+		Model(&models.Backtest{}).
+		Where("id = ?", backtest.ID).
+		Select("status", "started_at", "completed_at", "result", "error").
+		Updates(backtest).Error
+	if err != nil {
+		r.logger.Errorw("Failed to update backtest", "id", backtest.ID, "error", err)
+		return fmt.Errorf("failed to update backtest: %w", err)
+	}
+	return nil
+}
+
+// FailUnfinished marks pending and running backtests as failed with message.
+// Jobs run in-process, so any left unfinished at startup were interrupted.
+func (r *backtestRepository) FailUnfinished(ctx context.Context, message string) (int64, error) {
+	result := r.dbClient.WithContext(ctx).
+		Model(&models.Backtest{}).
+		Where("status IN ?", []models.BacktestStatus{models.BacktestStatusPending, models.BacktestStatusRunning}).
+		Updates(map[string]any{
+			"status":       models.BacktestStatusFailed,
+			"error":        message,
+			"completed_at": time.Now().UTC(),
+		})
+	if result.Error != nil {
+		r.logger.Errorw("Failed to fail unfinished backtests", "error", result.Error)
+		return 0, fmt.Errorf("failed to fail unfinished backtests: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
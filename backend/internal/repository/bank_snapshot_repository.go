@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// bankSnapshotInsertBatchSize bounds the rows per INSERT of snapshot items.
+const bankSnapshotInsertBatchSize = 500
+
+// bankSnapshotRepository implements BankSnapshotRepository.
+type bankSnapshotRepository struct {
+	dbClient *gorm.DB
+	logger   *zap.SugaredLogger
+}
+
+// NewBankSnapshotRepository creates a new bank snapshot repository.
+func NewBankSnapshotRepository(dbClient *gorm.DB, logger *zap.SugaredLogger) BankSnapshotRepository {
+	return &bankSnapshotRepository{
+		dbClient: dbClient,
+		logger:   logger,
+	}
+}
+
+// Create stores a snapshot and its items unless the user already has
+// maxPerUser snapshots, in which case it returns false.
+func (r *bankSnapshotRepository) Create(ctx context.Context, snapshot *models.BankSnapshot, maxPerUser int) (bool, error) {
+	created := false
+	err := r.dbClient.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Serialize a user's creates so concurrent requests cannot pass the limit.
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "bank_snapshots:"+snapshot.UserID).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.BankSnapshot{}).Where("user_id = ?", snapshot.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(maxPerUser) {
+			return nil
+		}
+
+		if err := tx.Create(snapshot).Error; err != nil {
+			return err
+		}
+		for i := range snapshot.Items {
+			snapshot.Items[i].SnapshotID = snapshot.ID
+		}
+		if len(snapshot.Items) > 0 {
+			if err := tx.CreateInBatches(&snapshot.Items, bankSnapshotInsertBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		snapshot.ItemCount = len(snapshot.Items)
+		created = true
+		return nil
+	})
+	if err != nil {
+		r.logger.Errorw("Failed to create bank snapshot", "user_id", snapshot.UserID, "error", err)
+		return false, fmt.Errorf("failed to create bank snapshot: %w", err)
+	}
+	return created, nil
+}
+
+// List returns a user's snapshots without their items, newest first.
+func (r *bankSnapshotRepository) List(ctx context.Context, userID string) ([]models.BankSnapshot, error) {
+	var snapshots []models.BankSnapshot
+	err := r.withItemCount(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Find(&snapshots).Error
+	if err != nil {
+		r.logger.Errorw("Failed to list bank snapshots", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("failed to list bank snapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+// Get returns one of a user's snapshots with its items, or nil when it does
+// not exist.
+func (r *bankSnapshotRepository) Get(ctx context.Context, userID string, id int64) (*models.BankSnapshot, error) {
+	var snapshot models.BankSnapshot
+	err := r.withItemCount(ctx).Where("user_id = ? AND id = ?", userID, id).First(&snapshot).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Errorw("Failed to get bank snapshot", "user_id", userID, "id", id, "error", err)
+		return nil, fmt.Errorf("failed to get bank snapshot: %w", err)
+	}
+
+	if err := r.dbClient.WithContext(ctx).Where("snapshot_id = ?", id).Order("item_id").Find(&snapshot.Items).Error; err != nil {
+		r.logger.Errorw("Failed to get bank snapshot items", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to get bank snapshot items: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// ListAll returns every snapshot with its items, oldest first.
+func (r *bankSnapshotRepository) ListAll(ctx context.Context) ([]models.BankSnapshot, error) {
+	var snapshots []models.BankSnapshot
+	if err := r.dbClient.WithContext(ctx).Order("id").Find(&snapshots).Error; err != nil {
+		r.logger.Errorw("Failed to list all bank snapshots", "error", err)
+		return nil, fmt.Errorf("failed to list all bank snapshots: %w", err)
+	}
+
+	var items []models.BankSnapshotItem
+	if err := r.dbClient.WithContext(ctx).Order("snapshot_id, item_id").Find(&items).Error; err != nil {
+		r.logger.Errorw("Failed to list all bank snapshot items", "error", err)
+		return nil, fmt.Errorf("failed to list all bank snapshot items: %w", err)
+	}
+
+	byID := make(map[int64]*models.BankSnapshot, len(snapshots))
+	for i := range snapshots {
+		byID[snapshots[i].ID] = &snapshots[i]
+	}
+	for _, item := range items {
+		if snapshot, ok := byID[item.SnapshotID]; ok {
+			snapshot.Items = append(snapshot.Items, item)
+			snapshot.ItemCount++
+		}
+	}
+	return snapshots, nil
+}
+
+// Delete removes one of a user's snapshots and reports whether it existed.
+// Items and recorded values are removed by the foreign key cascade.
+func (r *bankSnapshotRepository) Delete(ctx context.Context, userID string, id int64) (bool, error) {
+	result := r.dbClient.WithContext(ctx).
+		Where("user_id = ? AND id = ?", userID, id).
+		Delete(&models.BankSnapshot{})
+	if result.Error != nil {
+		r.logger.Errorw("Failed to delete bank snapshot", "user_id", userID, "id", id, "error", result.Error)
+		return false, fmt.Errorf("failed to delete bank snapshot: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UpsertValues records snapshot values, replacing any recorded for the same
+// snapshot and day.
+func (r *bankSnapshotRepository) UpsertValues(ctx context.Context, values []models.BankSnapshotValue) error {
+	if len(values) == 0 {
+		return nil
+	}
+	err := r.dbClient.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "snapshot_id"}, {Name: "valued_on"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"recorded_at", "total_high", "total_low", "total_mid", "total_high_alch", "unpriced_items",
+			}),
+		}).
+		CreateInBatches(&values, bankSnapshotInsertBatchSize).Error
+	if err != nil {
+		r.logger.Errorw("Failed to record bank snapshot values", "count", len(values), "error", err)
+		return fmt.Errorf("failed to record bank snapshot values: %w", err)
+	}
+	return nil
+}
+
+// ListValues returns a snapshot's recorded values between two days
+// (inclusive), oldest first.
+func (r *bankSnapshotRepository) ListValues(ctx context.Context, snapshotID int64, from, to time.Time) ([]models.BankSnapshotValue, error) {
+	var values []models.BankSnapshotValue
+	err := r.dbClient.WithContext(ctx).
+		Where("snapshot_id = ? AND valued_on BETWEEN ? AND ?", snapshotID, from.Format(time.DateOnly), to.Format(time.DateOnly)).
+		Order("valued_on").
+		Find(&values).Error
+	if err != nil {
+		r.logger.Errorw("Failed to list bank snapshot values", "id", snapshotID, "error", err)
+		return nil, fmt.Errorf("failed to list bank snapshot values: %w", err)
+	}
+	return values, nil
+}
+
+// withItemCount selects snapshots along with the number of stacks in each.
+func (r *bankSnapshotRepository) withItemCount(ctx context.Context) *gorm.DB {
+	return r.dbClient.WithContext(ctx).
+		Model(&models.BankSnapshot{}).
+		Select("bank_snapshots.*, " +
+			"(SELECT COUNT(*) FROM bank_snapshot_items i WHERE i.snapshot_id = bank_snapshots.id) AS item_count")
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// buyLimitRepository implements BuyLimitRepository.
+type buyLimitRepository struct {
+	dbClient *gorm.DB
+	logger   *zap.SugaredLogger
+}
+
+// NewBuyLimitRepository creates a new buy limit repository.
+func NewBuyLimitRepository(dbClient *gorm.DB, logger *zap.SugaredLogger) BuyLimitRepository {
+	return &buyLimitRepository{
+		dbClient: dbClient,
+		logger:   logger,
+	}
+}
+
+// windowExpired is true in an upsert when the stored window closed before
+// the incoming purchase, which then opens a new window.
+const windowExpired = "buy_limit_timers.resets_at <= EXCLUDED.window_start"
+
+// RecordPurchase stores a purchase and counts it against the user's window
+// for the item, opening a new window when none is open at PurchasedAt.
+// Returns the updated timer.
+func (r *buyLimitRepository) RecordPurchase(ctx context.Context, purchase *models.BuyLimitPurchase) (*models.BuyLimitTimer, error) {
+	timer := models.BuyLimitTimer{
+		UserID:      purchase.UserID,
+		ItemID:      purchase.ItemID,
+		WindowStart: purchase.PurchasedAt,
+		ResetsAt:    purchase.PurchasedAt.Add(models.BuyLimitWindow),
+		Bought:      purchase.Quantity,
+	}
+
+	err := r.dbClient.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(purchase).Error; err != nil {
+			return err
+		}
+		// A single upsert so concurrent first purchases cannot both open a window.
+		return tx.Clauses(
+			clause.OnConflict{
+				Columns: []clause.Column{{Name: "user_id"}, {Name: "item_id"}},
+				DoUpdates: clause.Assignments(map[string]any{
+					"window_start": gorm.Expr("CASE WHEN " + windowExpired + " THEN EXCLUDED.window_start ELSE buy_limit_timers.window_start END"),
+					"resets_at":    gorm.Expr("CASE WHEN " + windowExpired + " THEN EXCLUDED.resets_at ELSE buy_limit_timers.resets_at END"),
+					"bought":       gorm.Expr("CASE WHEN " + windowExpired + " THEN EXCLUDED.bought ELSE buy_limit_timers.bought + EXCLUDED.bought END"),
+					"notified":     gorm.Expr("CASE WHEN " + windowExpired + " THEN FALSE ELSE buy_limit_timers.notified END"),
+				}),
+			},
+			clause.Returning{},
+		).Create(&timer).Error
+	})
+	if err != nil {
+		r.logger.Errorw("Failed to record buy limit purchase", "user_id", purchase.UserID, "item_id", purchase.ItemID, "error", err)
+		return nil, fmt.Errorf("failed to record buy limit purchase: %w", err)
+	}
+	return &timer, nil
+}
+
+// DeletePurchase removes one of a user's purchases, no longer counting it
+// against the window it fell in, and reports whether it existed.
+func (r *buyLimitRepository) DeletePurchase(ctx context.Context, userID string, id int64) (bool, error) {
+	err := r.dbClient.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var purchase models.BuyLimitPurchase
+		if err := tx.Clauses(forUpdate).Where("id = ? AND user_id = ?", id, userID).First(&purchase).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&purchase).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.BuyLimitTimer{}).
+			Where("user_id = ? AND item_id = ?", userID, purchase.ItemID).
+			Where("window_start <= ? AND resets_at > ?", purchase.PurchasedAt, purchase.PurchasedAt).
+			Update("bought", gorm.Expr("GREATEST(bought - ?, 0)", purchase.Quantity)).Error; err != nil {
+			return err
+		}
+		// A window with nothing bought in it is no window at all.
+		return tx.Where("user_id = ? AND item_id = ? AND bought = 0", userID, purchase.ItemID).
+			Delete(&models.BuyLimitTimer{}).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		r.logger.Errorw("Failed to delete buy limit purchase", "user_id", userID, "id", id, "error", err)
+		return false, fmt.Errorf("failed to delete buy limit purchase: %w", err)
+	}
+	return true, nil
+}
+
+// GetTimer returns a user's timer for an item, or nil when there is none.
+func (r *buyLimitRepository) GetTimer(ctx context.Context, userID string, itemID int) (*models.BuyLimitTimer, error) {
+	var timer models.BuyLimitTimer
+	err := r.dbClient.WithContext(ctx).Where("user_id = ? AND item_id = ?", userID, itemID).First(&timer).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Errorw("Failed to get buy limit timer", "user_id", userID, "item_id", itemID, "error", err)
+		return nil, fmt.Errorf("failed to get buy limit timer: %w", err)
+	}
+	return &timer, nil
+}
+
+// ListTimers returns every timer of a user, soonest reset first.
+func (r *buyLimitRepository) ListTimers(ctx context.Context, userID string) ([]models.BuyLimitTimer, error) {
+	var timers []models.BuyLimitTimer
+	err := r.dbClient.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("resets_at, item_id").
+		Find(&timers).Error
+	if err != nil {
+		r.logger.Errorw("Failed to list buy limit timers", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("failed to list buy limit timers: %w", err)
+	}
+	return timers, nil
+}
+
+// ListPurchases returns a user's purchases of an item made at or after since,
+// oldest first.
+func (r *buyLimitRepository) ListPurchases(
+	ctx context.Context,
+	userID string,
+	itemID int,
+	since time.Time,
+) ([]models.BuyLimitPurchase, error) {
+	var purchases []models.BuyLimitPurchase
+	err := r.dbClient.WithContext(ctx).
+		Where("user_id = ? AND item_id = ? AND purchased_at >= ?", userID, itemID, since).
+		Order("purchased_at, id").
+		Find(&purchases).Error
+	if err != nil {
+		r.logger.Errorw("Failed to list buy limit purchases", "user_id", userID, "item_id", itemID, "error", err)
+		return nil, fmt.Errorf("failed to list buy limit purchases: %w", err)
+	}
+	return purchases, nil
+}
+
+// ClaimResets marks every window that closed by now and was not yet
+// announced as notified, and returns them. Each reset is claimed once even
+// with several API instances running the job.
+func (r *buyLimitRepository) ClaimResets(ctx context.Context, now time.Time) ([]models.BuyLimitTimer, error) {
+	var timers []models.BuyLimitTimer
+	err := r.dbClient.WithContext(ctx).
+		Model(&timers).
+		Clauses(clause.Returning{}).
+		Where("notified = ? AND resets_at <= ?", false, now).
+		Update("notified", true).Error
+	if err != nil {
+		r.logger.Errorw("Failed to claim buy limit resets", "error", err)
+		return nil, fmt.Errorf("failed to claim buy limit resets: %w", err)
+	}
+	return timers, nil
+}
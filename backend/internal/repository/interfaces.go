@@ -15,10 +15,19 @@ type ItemRepository interface {
 	// GetByID returns an item by its internal ID
 	GetByID(ctx context.Context, id uint) (*models.Item, error)
 
-	// GetByItemID returns an item by its OSRS item ID
+	// GetByItemID returns an item by its OSRS item ID, without its category and tags
 	GetByItemID(ctx context.Context, itemID int) (*models.Item, error)
 
-	// Search searches for items by name
+	// GetByItemIDs returns the items with the given OSRS item IDs and their category and tags, skipping unknown IDs
+	GetByItemIDs(ctx context.Context, itemIDs []int) ([]models.Item, error)
+
+	// GetByNames returns the items whose name matches one of names, ignoring case
+	GetByNames(ctx context.Context, names []string) ([]models.Item, error)
+
+	// GetBySlugs returns the items whose name slug (see aliases.Slug) is one of slugs
+	GetBySlugs(ctx context.Context, slugs []string) ([]models.Item, error)
+
+	// Search searches for items by name and approved alias
 	Search(ctx context.Context, params models.ItemSearchParams) ([]models.Item, int64, error)
 
 	// Create creates a new item
@@ -70,6 +79,25 @@ type PriceRepository interface {
 	// GetDailyPoints returns daily rollup points for an item.
 	GetDailyPoints(ctx context.Context, itemID int, params models.PriceHistoryParams) ([]models.PriceTimeseriesDaily, error)
 
+	// GetDailyPointsForItems returns the daily rollup points of several items between two days (inclusive),
+	// ordered by item and then day, in one query.
+	GetDailyPointsForItems(ctx context.Context, itemIDs []int, from, to time.Time) ([]models.PriceTimeseriesDaily, error)
+
+	// GetTimeseriesPointsForItems returns the bucketed points of several items with timestamps between from and
+	// to (inclusive), ordered by item and then timestamp, in one query.
+	GetTimeseriesPointsForItems(ctx context.Context, itemIDs []int, timestep string, from, to time.Time) ([]models.PriceTimeseriesPoint, error)
+
+	// GetPricesAt returns, per item, the most recent row of a single resolution that covers ts.
+	// resolution must be one of: latest, 5m, 1h, 6h, 24h, daily. Items without a covering row are omitted.
+	GetPricesAt(ctx context.Context, itemIDs []int, resolution models.PriceResolution, ts time.Time) ([]models.PriceAt, error)
+
+	// GetHourlySeries returns hourly average prices and total volume since the given time, oldest first.
+	// Hours covered by the 5m table are aggregated from it; older hours come from the 1h table.
+	GetHourlySeries(ctx context.Context, itemID int, since time.Time) ([]models.HourlyPricePoint, error)
+
+	// GetTradeVolumes returns the units traded per item since the given time, from the 1h buckets.
+	GetTradeVolumes(ctx context.Context, since time.Time) (map[int]int64, error)
+
 	// Rollup24hToDailyBefore inserts daily rollups for 24h buckets older than the cutoff.
 	Rollup24hToDailyBefore(ctx context.Context, cutoff time.Time) (int64, error)
 
@@ -83,3 +111,250 @@ type PriceRepository interface {
 	// EnsureFuturePartitions creates partitions for price_latest for the next N days
 	EnsureFuturePartitions(ctx context.Context, daysAhead int) error
 }
+
+// BacktestRepository defines the interface for stored backtest jobs.
+type BacktestRepository interface {
+	// Create stores a new backtest job
+	Create(ctx context.Context, backtest *models.Backtest) error
+
+	// GetByID returns a backtest by ID, or nil when it does not exist
+	GetByID(ctx context.Context, id string) (*models.Backtest, error)
+
+	// List returns the most recent backtests without their results
+	List(ctx context.Context, limit int) ([]models.Backtest, error)
+
+	// Update saves the status, timestamps, result and error of a backtest
+	Update(ctx context.Context, backtest *models.Backtest) error
+
+	// FailUnfinished marks pending and running backtests as failed with message
+	FailUnfinished(ctx context.Context, message string) (int64, error)
+}
+
+// PaperTradingRepository defines the interface for paper trading accounts, offers and positions.
+type PaperTradingRepository interface {
+	// CreateAccount stores a new paper trading account, or returns false when its user already has maxPerUser accounts
+	CreateAccount(ctx context.Context, account *models.PaperAccount, maxPerUser int) (bool, error)
+
+	// GetAccount returns an account by ID, or nil when it does not exist
+	GetAccount(ctx context.Context, id string) (*models.PaperAccount, error)
+
+	// PlaceOffer stores an open offer, holding its gold or reserving its items
+	PlaceOffer(ctx context.Context, offer *models.PaperOffer) error
+
+	// CancelOffer cancels an open offer and releases what it holds, or returns nil when not found
+	CancelOffer(ctx context.Context, accountID string, offerID int64) (*models.PaperOffer, error)
+
+	// ListOffers returns an account's offers, newest first, optionally filtered by status
+	ListOffers(ctx context.Context, accountID string, status models.PaperOfferStatus, limit int) ([]models.PaperOffer, error)
+
+	// ListOpenOffersForItems returns open offers on the given items across all accounts, oldest first
+	ListOpenOffersForItems(ctx context.Context, itemIDs []int) ([]models.PaperOffer, error)
+
+	// FillOffer fills an open offer at its price, or returns nil when nothing can fill
+	FillOffer(ctx context.Context, offerID int64, at time.Time, limit int) (*models.PaperFill, error)
+
+	// ListPositions returns an account's positions ordered by item ID
+	ListPositions(ctx context.Context, accountID string) ([]models.PaperPosition, error)
+
+	// ListFills returns an account's most recent fills
+	ListFills(ctx context.Context, accountID string, limit int) ([]models.PaperFill, error)
+}
+
+// TradeJournalRepository defines the interface for users' recorded trades.
+type TradeJournalRepository interface {
+	// Create stores one journal entry
+	Create(ctx context.Context, entry *models.TradeJournalEntry) error
+
+	// CreateBatch stores entries in one transaction
+	CreateBatch(ctx context.Context, entries []models.TradeJournalEntry) error
+
+	// List returns a page of a user's entries, newest first, with the filtered total
+	List(ctx context.Context, userID string, params models.JournalListParams) ([]models.TradeJournalEntry, int64, error)
+
+	// ListAll returns every entry of a user in trade order
+	ListAll(ctx context.Context, userID string) ([]models.TradeJournalEntry, error)
+
+	// Delete removes one of a user's entries and reports whether it existed
+	Delete(ctx context.Context, userID string, id int64) (bool, error)
+}
+
+// BuyLimitRepository defines the interface for buy limit tracking data access
+type BuyLimitRepository interface {
+	// RecordPurchase stores a purchase and counts it against the user's window for the item
+	RecordPurchase(ctx context.Context, purchase *models.BuyLimitPurchase) (*models.BuyLimitTimer, error)
+
+	// DeletePurchase removes one of a user's purchases and reports whether it existed
+	DeletePurchase(ctx context.Context, userID string, id int64) (bool, error)
+
+	// GetTimer returns a user's window for an item, or nil when there is none
+	GetTimer(ctx context.Context, userID string, itemID int) (*models.BuyLimitTimer, error)
+
+	// ListTimers returns every window of a user, soonest reset first
+	ListTimers(ctx context.Context, userID string) ([]models.BuyLimitTimer, error)
+
+	// ListPurchases returns a user's purchases of an item since a time, oldest first
+	ListPurchases(ctx context.Context, userID string, itemID int, since time.Time) ([]models.BuyLimitPurchase, error)
+
+	// ClaimResets marks closed, unannounced windows as notified and returns them
+	ClaimResets(ctx context.Context, now time.Time) ([]models.BuyLimitTimer, error)
+}
+
+// BankSnapshotRepository defines the interface for saved bank data access
+type BankSnapshotRepository interface {
+	// Create stores a snapshot and its items, returning false when the user already has maxPerUser snapshots
+	Create(ctx context.Context, snapshot *models.BankSnapshot, maxPerUser int) (bool, error)
+
+	// List returns a user's snapshots without their items, newest first
+	List(ctx context.Context, userID string) ([]models.BankSnapshot, error)
+
+	// Get returns one of a user's snapshots with its items, or nil when it does not exist
+	Get(ctx context.Context, userID string, id int64) (*models.BankSnapshot, error)
+
+	// ListAll returns every snapshot with its items
+	ListAll(ctx context.Context) ([]models.BankSnapshot, error)
+
+	// Delete removes one of a user's snapshots and reports whether it existed
+	Delete(ctx context.Context, userID string, id int64) (bool, error)
+
+	// UpsertValues records snapshot values, replacing any recorded for the same day
+	UpsertValues(ctx context.Context, values []models.BankSnapshotValue) error
+
+	// ListValues returns a snapshot's recorded values between two days (inclusive), oldest first
+	ListValues(ctx context.Context, snapshotID int64, from, to time.Time) ([]models.BankSnapshotValue, error)
+}
+
+// ItemSetRepository defines the interface for admin edits to the item set registry
+type ItemSetRepository interface {
+	// ListOverrides returns every admin edit to the set registry
+	ListOverrides(ctx context.Context) ([]models.ItemSetOverride, error)
+
+	// UpsertOverride stores an admin edit, replacing any earlier edit of the set
+	UpsertOverride(ctx context.Context, override *models.ItemSetOverride) error
+
+	// DeleteOverride removes an admin edit and reports whether it existed
+	DeleteOverride(ctx context.Context, setItemID int) (bool, error)
+}
+
+// RecipeRepository defines the interface for admin edits to the recipe registry
+type RecipeRepository interface {
+	// ListOverrides returns every admin edit to the recipe registry
+	ListOverrides(ctx context.Context) ([]models.RecipeOverride, error)
+
+	// UpsertOverride stores an admin edit, replacing any earlier edit of the recipe
+	UpsertOverride(ctx context.Context, override *models.RecipeOverride) error
+
+	// DeleteOverride removes an admin edit and reports whether it existed
+	DeleteOverride(ctx context.Context, recipeID string) (bool, error)
+}
+
+// MarketIndexRepository defines the interface for custom market indices and computed index values
+type MarketIndexRepository interface {
+	// CreateCustom stores a custom index, returning false when the user already has maxPerUser indices
+	CreateCustom(ctx context.Context, index *models.CustomMarketIndex, maxPerUser int) (bool, error)
+
+	// ListCustom returns a user's custom indices, oldest first
+	ListCustom(ctx context.Context, userID string) ([]models.CustomMarketIndex, error)
+
+	// ListAllCustom returns every user's custom indices
+	ListAllCustom(ctx context.Context) ([]models.CustomMarketIndex, error)
+
+	// GetCustom returns one of a user's custom indices, or nil when it does not exist
+	GetCustom(ctx context.Context, userID string, id int64) (*models.CustomMarketIndex, error)
+
+	// DeleteCustom removes one of a user's custom indices and its values, reporting whether it existed
+	DeleteCustom(ctx context.Context, userID string, id int64) (bool, error)
+
+	// LatestValue returns an index's most recent value, or nil before it is first computed
+	LatestValue(ctx context.Context, indexID string) (*models.MarketIndexValue, error)
+
+	// UpsertValues records index values, replacing any stored for the same bucket
+	UpsertValues(ctx context.Context, values []models.MarketIndexValue) error
+
+	// ListValues returns an index's values between two times (inclusive), oldest first
+	ListValues(ctx context.Context, indexID string, from, to time.Time) ([]models.MarketIndexValue, error)
+}
+
+// ItemTagRepository defines the interface for item categories and tags
+type ItemTagRepository interface {
+	// ReplaceAutoTags replaces every classifier tag with tags, keeping admin tags and removals
+	ReplaceAutoTags(ctx context.Context, tags []models.ItemTag) error
+
+	// ListForItem returns an item's tags, including admin removals
+	ListForItem(ctx context.Context, itemID int) ([]models.ItemTag, error)
+
+	// Upsert stores an admin tag, replacing any classifier tag or removal of it
+	Upsert(ctx context.Context, tag *models.ItemTag) error
+
+	// Remove records an admin removal of an item's tag and reports whether the item had it
+	Remove(ctx context.Context, itemID int, tag string) (bool, error)
+
+	// CountTags returns every tag in use with the number of items carrying it
+	CountTags(ctx context.Context) ([]models.ItemTagCount, error)
+}
+
+// ItemAliasRepository defines the interface for item aliases
+type ItemAliasRepository interface {
+	// InsertMissing stores the aliases that do not exist yet in any status and returns how many were added
+	InsertMissing(ctx context.Context, aliases []models.ItemAlias) (int64, error)
+
+	// Create stores a new alias, enforcing maxPending for user submissions
+	Create(ctx context.Context, alias *models.ItemAlias, maxPending int) error
+
+	// GetByID returns an alias, or nil if it does not exist
+	GetByID(ctx context.Context, id uint) (*models.ItemAlias, error)
+
+	// List returns up to limit aliases in a status, oldest first
+	List(ctx context.Context, status string, limit int) ([]models.ItemAlias, error)
+
+	// ListForItem returns an item's approved aliases
+	ListForItem(ctx context.Context, itemID int) ([]models.ItemAlias, error)
+
+	// ListApproved returns every approved alias
+	ListApproved(ctx context.Context) ([]models.ItemAlias, error)
+
+	// FindApproved returns the approved aliases among names
+	FindApproved(ctx context.Context, names []string) ([]models.ItemAlias, error)
+
+	// SetStatus records a review of an alias and returns it, or nil if it does not exist
+	SetStatus(ctx context.Context, id uint, status string) (*models.ItemAlias, error)
+
+	// Delete removes an alias and reports whether it existed
+	Delete(ctx context.Context, id uint) (bool, error)
+}
+
+// ScreenRepository stores users' saved screens
+type ScreenRepository interface {
+	// Create stores a screen, returning false when the user already has maxPerUser screens
+	Create(ctx context.Context, screen *models.Screen, maxPerUser int) (bool, error)
+
+	// List returns a user's screens, oldest first
+	List(ctx context.Context, userID string) ([]models.Screen, error)
+
+	// Get returns one of a user's screens, or nil when it does not exist
+	Get(ctx context.Context, userID string, id int64) (*models.Screen, error)
+
+	// Update saves a screen's name, expression and alert flag and clears its alert state,
+	// reporting whether the screen exists
+	Update(ctx context.Context, screen *models.Screen) (bool, error)
+
+	// Delete removes one of a user's screens and reports whether it existed
+	Delete(ctx context.Context, userID string, id int64) (bool, error)
+
+	// ListAlerting returns every screen with alerts enabled
+	ListAlerting(ctx context.Context) ([]models.Screen, error)
+
+	// SetAlertState records the items a screen matched at its last alert check, and when it last alerted if alertedAt is set
+	SetAlertState(ctx context.Context, id int64, itemIDs []int, alertedAt *time.Time) error
+}
+
+// ItemStatsRepository stores the precomputed market table
+type ItemStatsRepository interface {
+	// Replace writes the rows of one refresh, stamped asOf, and removes rows it did not write
+	Replace(ctx context.Context, stats []models.ItemStats, asOf time.Time) error
+
+	// LastUpdated returns the time of the last refresh, or nil before the first one
+	LastUpdated(ctx context.Context) (*time.Time, error)
+
+	// Query returns one page of rows matching params and the total number of matching rows
+	Query(ctx context.Context, params models.MarketTableParams) ([]models.ItemStats, int64, error)
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// itemAliasRepository implements ItemAliasRepository.
+type itemAliasRepository struct {
+	dbClient *gorm.DB
+	logger   *zap.SugaredLogger
+}
+
+// NewItemAliasRepository creates a new item alias repository.
+func NewItemAliasRepository(dbClient *gorm.DB, logger *zap.SugaredLogger) ItemAliasRepository {
+	return &itemAliasRepository{
+		dbClient: dbClient,
+		logger:   logger,
+	}
+}
+
+// InsertMissing stores every alias that does not exist yet, in any status,
+// and returns how many were added. Reseeding this way leaves aliases an
+// admin rejected rejected.
+func (r *itemAliasRepository) InsertMissing(ctx context.Context, aliases []models.ItemAlias) (int64, error) {
+	if len(aliases) == 0 {
+		return 0, nil
+	}
+	result := r.dbClient.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "alias"}}, DoNothing: true}).
+		Create(&aliases)
+	if result.Error != nil {
+		r.logger.Errorw("Failed to insert item aliases", "count", len(aliases), "error", result.Error)
+		return 0, fmt.Errorf("failed to insert item aliases: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// Create stores a new alias. It returns models.ErrItemAliasTaken if the
+// alias exists in any status, and models.ErrItemAliasLimit if the submitter
+// already has maxPending aliases awaiting review.
+func (r *itemAliasRepository) Create(ctx context.Context, alias *models.ItemAlias, maxPending int) error {
+	err := r.dbClient.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if alias.SubmittedBy != nil {
+			// Serialize a user's submissions so concurrent requests cannot pass the limit.
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "item_aliases:"+*alias.SubmittedBy).Error; err != nil {
+				return err
+			}
+			var pending int64
+			err := tx.Model(&models.ItemAlias{}).
+				Where("submitted_by = ? AND status = ?", *alias.SubmittedBy, models.ItemAliasStatusPending).
+				Count(&pending).Error
+			if err != nil {
+				return err
+			}
+			if pending >= int64(maxPending) {
+				return models.ErrItemAliasLimit
+			}
+		}
+		result := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "alias"}}, DoNothing: true}).Create(alias)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return models.ErrItemAliasTaken
+		}
+		return nil
+	})
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, models.ErrItemAliasTaken), errors.Is(err, models.ErrItemAliasLimit):
+		return err
+	}
+	r.logger.Errorw("Failed to create item alias", "alias", alias.Alias, "itemID", alias.ItemID, "error", err)
+	return fmt.Errorf("failed to create item alias: %w", err)
+}
+
+// GetByID returns an alias, or nil if it does not exist.
+func (r *itemAliasRepository) GetByID(ctx context.Context, id uint) (*models.ItemAlias, error) {
+	var alias models.ItemAlias
+	err := r.dbClient.WithContext(ctx).Where("id = ?", id).First(&alias).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Errorw("Failed to get item alias", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to get item alias: %w", err)
+	}
+	return &alias, nil
+}
+
+// List returns up to limit aliases in status, oldest first.
+func (r *itemAliasRepository) List(ctx context.Context, status string, limit int) ([]models.ItemAlias, error) {
+	var aliases []models.ItemAlias
+	err := r.dbClient.WithContext(ctx).
+		Where("status = ?", status).
+		Order("created_at, id").
+		Limit(limit).
+		Find(&aliases).Error
+	if err != nil {
+		r.logger.Errorw("Failed to list item aliases", "status", status, "error", err)
+		return nil, fmt.Errorf("failed to list item aliases: %w", err)
+	}
+	return aliases, nil
+}
+
+// ListForItem returns an item's approved aliases, alphabetically.
+func (r *itemAliasRepository) ListForItem(ctx context.Context, itemID int) ([]models.ItemAlias, error) {
+	var aliases []models.ItemAlias
+	err := r.dbClient.WithContext(ctx).
+		Where("item_id = ? AND status = ?", itemID, models.ItemAliasStatusApproved).
+		Order("alias").
+		Find(&aliases).Error
+	if err != nil {
+		r.logger.Errorw("Failed to list item aliases", "itemID", itemID, "error", err)
+		return nil, fmt.Errorf("failed to list item aliases: %w", err)
+	}
+	return aliases, nil
+}
+
+// ListApproved returns every approved alias.
+func (r *itemAliasRepository) ListApproved(ctx context.Context) ([]models.ItemAlias, error) {
+	var aliases []models.ItemAlias
+	err := r.dbClient.WithContext(ctx).
+		Where("status = ?", models.ItemAliasStatusApproved).
+		Order("item_id, alias").
+		Find(&aliases).Error
+	if err != nil {
+		r.logger.Errorw("Failed to list approved item aliases", "error", err)
+		return nil, fmt.Errorf("failed to list approved item aliases: %w", err)
+	}
+	return aliases, nil
+}
+
+// FindApproved returns the approved aliases among the given folded names.
+func (r *itemAliasRepository) FindApproved(ctx context.Context, names []string) ([]models.ItemAlias, error) {
+	if len(names) == 0 {
+		return []models.ItemAlias{}, nil
+	}
+	var aliases []models.ItemAlias
+	err := r.dbClient.WithContext(ctx).
+		Where("alias IN ? AND status = ?", names, models.ItemAliasStatusApproved).
+		Find(&aliases).Error
+	if err != nil {
+		r.logger.Errorw("Failed to find item aliases", "count", len(names), "error", err)
+		return nil, fmt.Errorf("failed to find item aliases: %w", err)
+	}
+	return aliases, nil
+}
+
+// SetStatus records a review of an alias and returns it, or nil if it does
+// not exist.
+func (r *itemAliasRepository) SetStatus(ctx context.Context, id uint, status string) (*models.ItemAlias, error) {
+	result := r.dbClient.WithContext(ctx).
+		Model(&models.ItemAlias{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"status": status, "reviewed_at": time.Now().UTC()})
+	if result.Error != nil {
+		r.logger.Errorw("Failed to review item alias", "id", id, "status", status, "error", result.Error)
+		return nil, fmt.Errorf("failed to review item alias: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return r.GetByID(ctx, id)
+}
+
+// Delete removes an alias and reports whether it existed. A deleted seed
+// alias comes back on the next start; reject it to keep it out.
+func (r *itemAliasRepository) Delete(ctx context.Context, id uint) (bool, error) {
+	result := r.dbClient.WithContext(ctx).Where("id = ?", id).Delete(&models.ItemAlias{})
+	if result.Error != nil {
+		r.logger.Errorw("Failed to delete item alias", "id", id, "error", result.Error)
+		return false, fmt.Errorf("failed to delete item alias: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 
+	"github.com/guavi/osrs-ge-tracker/internal/aliases"
 	"github.com/guavi/osrs-ge-tracker/internal/models"
 )
 
@@ -37,6 +40,7 @@ func (r *itemRepository) GetAll(ctx context.Context, params models.ItemListParam
 	if params.Members != nil {
 		query = query.Where("members = ?", *params.Members)
 	}
+	query = whereTagged(query, params.Category, params.Tags)
 
 	// Get total count
 	if err := query.Count(&total).Error; err != nil {
@@ -68,6 +72,9 @@ func (r *itemRepository) GetAll(ctx context.Context, params models.ItemListParam
 		r.logger.Errorw("Failed to get items", "error", err)
 		return nil, 0, fmt.Errorf("failed to get items: %w", err)
 	}
+	if err := r.attachTags(ctx, items); err != nil {
+		return nil, 0, err
+	}
 
 	return items, total, nil
 }
@@ -85,7 +92,8 @@ func (r *itemRepository) GetByID(ctx context.Context, id uint) (*models.Item, er
 	return &item, nil
 }
 
-// GetByItemID returns an item by its OSRS item ID.
+// GetByItemID returns an item by its OSRS item ID, without its category and
+// tags; GetByItemIDs loads those.
 func (r *itemRepository) GetByItemID(ctx context.Context, itemID int) (*models.Item, error) {
 	var item models.Item
 	if err := r.dbClient.WithContext(ctx).Where("item_id = ?", itemID).First(&item).Error; err != nil {
@@ -98,7 +106,62 @@ func (r *itemRepository) GetByItemID(ctx context.Context, itemID int) (*models.I
 	return &item, nil
 }
 
-// Search searches for items by name.
+// GetByItemIDs returns the items with the given OSRS item IDs, with their
+// category and tags, skipping unknown IDs.
+func (r *itemRepository) GetByItemIDs(ctx context.Context, itemIDs []int) ([]models.Item, error) {
+	if len(itemIDs) == 0 {
+		return []models.Item{}, nil
+	}
+
+	var items []models.Item
+	if err := r.dbClient.WithContext(ctx).Where("item_id IN ?", itemIDs).Find(&items).Error; err != nil {
+		r.logger.Errorw("Failed to get items by item_id", "count", len(itemIDs), "error", err)
+		return nil, fmt.Errorf("failed to get items by item_id: %w", err)
+	}
+	if err := r.attachTags(ctx, items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetByNames returns the items whose name matches one of names, ignoring case.
+func (r *itemRepository) GetByNames(ctx context.Context, names []string) ([]models.Item, error) {
+	if len(names) == 0 {
+		return []models.Item{}, nil
+	}
+
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+	}
+
+	var items []models.Item
+	if err := r.dbClient.WithContext(ctx).Where("LOWER(name) IN ?", lowered).Order("item_id").Find(&items).Error; err != nil {
+		r.logger.Errorw("Failed to get items by name", "count", len(names), "error", err)
+		return nil, fmt.Errorf("failed to get items by name: %w", err)
+	}
+	return items, nil
+}
+
+// itemSlugSQL is the SQL form of aliases.Slug over items.name.
+const itemSlugSQL = `trim(both '-' from regexp_replace(regexp_replace(lower(items.name), '[''’]', '', 'g'), '[^a-z0-9]+', '-', 'g'))`
+
+// GetBySlugs returns items whose name slug is one of slugs.
+func (r *itemRepository) GetBySlugs(ctx context.Context, slugs []string) ([]models.Item, error) {
+	if len(slugs) == 0 {
+		return []models.Item{}, nil
+	}
+
+	var items []models.Item
+	if err := r.dbClient.WithContext(ctx).Where(itemSlugSQL+" IN ?", slugs).Order("item_id").Find(&items).Error; err != nil {
+		r.logger.Errorw("Failed to get items by slug", "count", len(slugs), "error", err)
+		return nil, fmt.Errorf("failed to get items by slug: %w", err)
+	}
+	return items, nil
+}
+
+// Search ranks items matching params.Query by name or approved alias
+// similarity, prefix and exact matches, and trade volume; see searchScoreSQL.
 func (r *itemRepository) Search(ctx context.Context, params models.ItemSearchParams) ([]models.Item, int64, error) {
 	var items []models.Item
 	var total int64
@@ -106,15 +169,16 @@ func (r *itemRepository) Search(ctx context.Context, params models.ItemSearchPar
 	query := r.dbClient.WithContext(ctx).Model(&models.Item{})
 
 	// Apply search filter
-	if params.Query != "" {
-		searchPattern := "%" + params.Query + "%"
-		query = query.Where("name ILIKE ?", searchPattern)
+	name := aliases.Fold(params.Query)
+	if name != "" {
+		query = whereNameMatches(query, params.Mode, name)
 	}
 
 	// Apply members filter
 	if params.Members != nil {
 		query = query.Where("members = ?", *params.Members)
 	}
+	query = whereTagged(query, params.Category, params.Tags)
 
 	// Get total count
 	if err := query.Count(&total).Error; err != nil {
@@ -122,16 +186,26 @@ func (r *itemRepository) Search(ctx context.Context, params models.ItemSearchPar
 		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
 	}
 
-	// Apply sorting
-	sortBy := params.SortBy
-	if sortBy == "" {
-		sortBy = "name"
+	if name != "" {
+		query = query.
+			Select("items.*, "+searchScoreSQL+" AS search_score", name, name, name, escapeLike(name)+"%").
+			Joins(searchVolumeJoinSQL, time.Now().Add(-searchVolumeWindow)).
+			Joins(searchAliasJoinSQL, name, name, escapeLike(name)+"%")
 	}
-	sortOrder := params.SortOrder
-	if sortOrder == "" {
-		sortOrder = "asc"
+
+	// Apply sorting
+	switch {
+	case params.SortBy != "":
+		sortOrder := params.SortOrder
+		if sortOrder == "" {
+			sortOrder = "asc"
+		}
+		query = query.Order(fmt.Sprintf("%s %s", params.SortBy, sortOrder))
+	case name != "":
+		query = query.Order("search_score DESC, items.name ASC")
+	default:
+		query = query.Order("name asc")
 	}
-	query = query.Order(fmt.Sprintf("%s %s", sortBy, sortOrder))
 
 	// Apply pagination
 	if params.Limit > 0 {
@@ -146,10 +220,126 @@ func (r *itemRepository) Search(ctx context.Context, params models.ItemSearchPar
 		r.logger.Errorw("Failed to search items", "error", err)
 		return nil, 0, fmt.Errorf("failed to search items: %w", err)
 	}
+	if err := r.attachTags(ctx, items); err != nil {
+		return nil, 0, err
+	}
 
 	return items, total, nil
 }
 
+// searchScoreSQL scores a search result between 0 and 1: up to 0.6 for
+// trigram similarity (whole name, best-matching words or an approved alias),
+// 0.25 for an exact or 0.15 for a prefix match of the name or an alias, and
+// up to 0.15 for the log of the units traded over searchVolumeWindow,
+// saturating at 10M. Arguments: the folded query three times, then the
+// escaped prefix pattern.
+const searchScoreSQL = `ROUND((
+	0.6 * GREATEST(
+		similarity(lower(items.name), ?),
+		word_similarity(?, lower(items.name)),
+		COALESCE(search_alias.similarity, 0))
+	+ CASE
+		WHEN lower(items.name) = ? OR search_alias.exact THEN 0.25
+		WHEN lower(items.name) LIKE ? OR search_alias.prefix THEN 0.15
+		ELSE 0
+	END
+	+ 0.15 * LEAST(ln(1 + COALESCE(search_volume.volume, 0)) / ln(10000000), 1)
+)::numeric, 4)::double precision`
+
+// searchVolumeJoinSQL joins each item's traded units since the argument
+// from the stored 1h buckets.
+const searchVolumeJoinSQL = `LEFT JOIN LATERAL (
+	SELECT SUM(ts.high_price_volume + ts.low_price_volume) AS volume
+	FROM price_timeseries_1h ts
+	WHERE ts.item_id = items.item_id AND ts.timestamp >= ?
+) search_volume ON TRUE`
+
+// searchAliasJoinSQL joins how well each item's approved aliases match.
+// Arguments: the folded query twice, then the escaped prefix pattern.
+const searchAliasJoinSQL = `LEFT JOIN LATERAL (
+	SELECT MAX(similarity(a.alias, ?)) AS similarity, bool_or(a.alias = ?) AS exact, bool_or(a.alias LIKE ?) AS prefix
+	FROM item_aliases a
+	WHERE a.item_id = items.item_id AND a.status = 'approved'
+) search_alias ON TRUE`
+
+// searchVolumeWindow is how far back trade volume counts toward the score.
+const searchVolumeWindow = 24 * time.Hour
+
+// aliasMatchSQL filters to items with an approved alias matching the
+// condition, in which the alias is a.alias.
+const aliasMatchSQL = "EXISTS (SELECT 1 FROM item_aliases a WHERE a.item_id = items.item_id AND a.status = 'approved' AND "
+
+// whereNameMatches filters query to items whose name or an approved alias
+// matches the folded name (see aliases.Fold) in the given mode. Fuzzy matches similar
+// names, similar words, substrings and similar aliases.
+func whereNameMatches(query *gorm.DB, mode, name string) *gorm.DB {
+	switch mode {
+	case models.ItemSearchModeExact:
+		return query.Where("(lower(items.name) = ? OR "+aliasMatchSQL+"a.alias = ?))", name, name)
+	case models.ItemSearchModePrefix:
+		prefix := escapeLike(name) + "%"
+		return query.Where("(lower(items.name) LIKE ? OR "+aliasMatchSQL+"a.alias LIKE ?))", prefix, prefix)
+	default:
+		return query.Where("(lower(items.name) % ? OR ? <% lower(items.name) OR lower(items.name) LIKE ? OR "+aliasMatchSQL+"a.alias % ?))",
+			name, name, "%"+escapeLike(name)+"%", name)
+	}
+}
+
+// escapeLike escapes the LIKE wildcards in s.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// whereTagged narrows query to items in category that carry every tag. A
+// tag filter matches categories too.
+func whereTagged(query *gorm.DB, category string, tags []string) *gorm.DB {
+	const tagged = "EXISTS (SELECT 1 FROM item_tags t WHERE t.item_id = items.item_id AND NOT t.removed AND t.tag = ?"
+	if category != "" {
+		query = query.Where(tagged+" AND t.kind = ?)", category, models.ItemTagKindCategory)
+	}
+	for _, tag := range tags {
+		query = query.Where(tagged+")", tag)
+	}
+	return query
+}
+
+// attachTags fills in the category and tags of each item.
+func (r *itemRepository) attachTags(ctx context.Context, items []models.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+	itemIDs := make([]int, len(items))
+	for i := range items {
+		itemIDs[i] = items[i].ItemID
+	}
+
+	var tags []models.ItemTag
+	err := r.dbClient.WithContext(ctx).
+		Where("item_id IN ? AND NOT removed", itemIDs).
+		Order("item_id, source, tag").
+		Find(&tags).Error
+	if err != nil {
+		r.logger.Errorw("Failed to get item tags", "count", len(itemIDs), "error", err)
+		return fmt.Errorf("failed to get item tags: %w", err)
+	}
+
+	byItem := make(map[int][]models.ItemTag, len(items))
+	for _, tag := range tags {
+		byItem[tag.ItemID] = append(byItem[tag.ItemID], tag)
+	}
+	for i := range items {
+		for _, tag := range byItem[items[i].ItemID] {
+			// Admin categories sort first, so they win over the classifier's.
+			if tag.Kind == models.ItemTagKindCategory && items[i].Category == "" {
+				items[i].Category = tag.Tag
+				continue
+			}
+			items[i].Tags = append(items[i].Tags, tag.Tag)
+		}
+	}
+	return nil
+}
+
 // Create creates a new item.
 func (r *itemRepository) Create(ctx context.Context, item *models.Item) error {
 	if err := r.dbClient.WithContext(ctx).Create(item).Error; err != nil {
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// itemSetRepository implements ItemSetRepository.
+type itemSetRepository struct {
+	dbClient *gorm.DB
+	logger   *zap.SugaredLogger
+}
+
+// NewItemSetRepository creates a new item set repository.
+func NewItemSetRepository(dbClient *gorm.DB, logger *zap.SugaredLogger) ItemSetRepository {
+	return &itemSetRepository{
+		dbClient: dbClient,
+		logger:   logger,
+	}
+}
+
+// ListOverrides returns every admin edit to the set registry.
+func (r *itemSetRepository) ListOverrides(ctx context.Context) ([]models.ItemSetOverride, error) {
+	var overrides []models.ItemSetOverride
+	if err := r.dbClient.WithContext(ctx).Order("set_item_id").Find(&overrides).Error; err != nil {
+		r.logger.Errorw("Failed to list item set overrides", "error", err)
+		return nil, fmt.Errorf("failed to list item set overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+// UpsertOverride stores an admin edit, replacing any earlier edit of the set.
+func (r *itemSetRepository) UpsertOverride(ctx context.Context, override *models.ItemSetOverride) error {
+	err := r.dbClient.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "set_item_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "components", "removed", "updated_at"}),
+		}).
+		Create(override).Error
+	if err != nil {
+		r.logger.Errorw("Failed to save item set override", "set_item_id", override.SetItemID, "error", err)
+		return fmt.Errorf("failed to save item set override: %w", err)
+	}
+	return nil
+}
+
+// DeleteOverride removes an admin edit and reports whether it existed.
+func (r *itemSetRepository) DeleteOverride(ctx context.Context, setItemID int) (bool, error) {
+	result := r.dbClient.WithContext(ctx).
+		Where("set_item_id = ?", setItemID).
+		Delete(&models.ItemSetOverride{})
+	if result.Error != nil {
+		r.logger.Errorw("Failed to delete item set override", "set_item_id", setItemID, "error", result.Error)
+		return false, fmt.Errorf("failed to delete item set override: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+const itemStatsInsertBatchSize = 1000
+
+// itemStatsRepository implements ItemStatsRepository.
+type itemStatsRepository struct {
+	dbClient *gorm.DB
+	logger   *zap.SugaredLogger
+}
+
+// NewItemStatsRepository creates a new item stats repository.
+func NewItemStatsRepository(dbClient *gorm.DB, logger *zap.SugaredLogger) ItemStatsRepository {
+	return &itemStatsRepository{
+		dbClient: dbClient,
+		logger:   logger,
+	}
+}
+
+// Replace upserts every row stamped asOf and deletes rows left over from
+// earlier refreshes, such as items that no longer exist, in one transaction.
+func (r *itemStatsRepository) Replace(ctx context.Context, stats []models.ItemStats, asOf time.Time) error {
+	// Postgres keeps microseconds; a finer asOf would match no stored row.
+	asOf = asOf.Truncate(time.Microsecond)
+	for i := range stats {
+		stats[i].UpdatedAt = asOf
+	}
+	err := r.dbClient.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(stats) > 0 {
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "item_id"}},
+				UpdateAll: true,
+			}).CreateInBatches(&stats, itemStatsInsertBatchSize).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Where("updated_at <> ?", asOf).Delete(&models.ItemStats{}).Error
+	})
+	if err != nil {
+		r.logger.Errorw("Failed to replace item stats", "count", len(stats), "error", err)
+		return fmt.Errorf("failed to replace item stats: %w", err)
+	}
+	return nil
+}
+
+// LastUpdated returns the time of the last refresh, or nil while the table
+// is empty.
+func (r *itemStatsRepository) LastUpdated(ctx context.Context) (*time.Time, error) {
+	var updatedAt *time.Time
+	err := r.dbClient.WithContext(ctx).
+		Model(&models.ItemStats{}).
+		Select("MAX(updated_at)").
+		Scan(&updatedAt).Error
+	if err != nil {
+		r.logger.Errorw("Failed to get item stats refresh time", "error", err)
+		return nil, fmt.Errorf("failed to get item stats refresh time: %w", err)
+	}
+	return updatedAt, nil
+}
+
+// Query returns one page of rows matching params. Sort and filter columns
+// must be keys of models.MarketTableColumns. Rows without a value for the
+// sort column come last, and ties are broken by item ID so pages are stable.
+func (r *itemStatsRepository) Query(ctx context.Context, params models.MarketTableParams) ([]models.ItemStats, int64, error) {
+	query := r.dbClient.WithContext(ctx).Model(&models.ItemStats{})
+
+	if params.Members != nil {
+		query = query.Where("members = ?", *params.Members)
+	}
+	if params.Category != "" {
+		query = query.Where("category = ?", params.Category)
+	}
+	if params.Query != "" {
+		query = query.Where("name ILIKE ?", "%"+escapeLike(params.Query)+"%")
+	}
+	for column, bound := range params.Min {
+		if !models.MarketTableColumns[column] {
+			return nil, 0, fmt.Errorf("cannot filter item stats on %q", column)
+		}
+		query = query.Where(column+" >= ?", bound)
+	}
+	for column, bound := range params.Max {
+		if !models.MarketTableColumns[column] {
+			return nil, 0, fmt.Errorf("cannot filter item stats on %q", column)
+		}
+		query = query.Where(column+" <= ?", bound)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.Errorw("Failed to count item stats", "error", err)
+		return nil, 0, fmt.Errorf("failed to count item stats: %w", err)
+	}
+
+	if _, ok := models.MarketTableColumns[params.SortBy]; !ok {
+		return nil, 0, fmt.Errorf("cannot sort item stats by %q", params.SortBy)
+	}
+	order := "ASC"
+	if params.Order == "desc" {
+		order = "DESC"
+	}
+	query = query.Order(fmt.Sprintf("%s %s NULLS LAST, item_id", params.SortBy, order))
+	if params.Limit > 0 {
+		query = query.Limit(params.Limit)
+	}
+	if params.Offset > 0 {
+		query = query.Offset(params.Offset)
+	}
+
+	var stats []models.ItemStats
+	if err := query.Find(&stats).Error; err != nil {
+		r.logger.Errorw("Failed to query item stats", "error", err)
+		return nil, 0, fmt.Errorf("failed to query item stats: %w", err)
+	}
+	return stats, total, nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// itemTagInsertBatchSize bounds the rows per INSERT of classifier tags.
+const itemTagInsertBatchSize = 1000
+
+// itemTagRepository implements ItemTagRepository.
+type itemTagRepository struct {
+	dbClient *gorm.DB
+	logger   *zap.SugaredLogger
+}
+
+// NewItemTagRepository creates a new item tag repository.
+func NewItemTagRepository(dbClient *gorm.DB, logger *zap.SugaredLogger) ItemTagRepository {
+	return &itemTagRepository{
+		dbClient: dbClient,
+		logger:   logger,
+	}
+}
+
+// ReplaceAutoTags swaps every classifier tag for tags in one transaction.
+// Tags an admin added or removed are left as they are.
+func (r *itemTagRepository) ReplaceAutoTags(ctx context.Context, tags []models.ItemTag) error {
+	err := r.dbClient.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("source = ?", models.ItemTagSourceAuto).Delete(&models.ItemTag{}).Error; err != nil {
+			return err
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).
+			CreateInBatches(&tags, itemTagInsertBatchSize).Error
+	})
+	if err != nil {
+		r.logger.Errorw("Failed to replace classifier item tags", "count", len(tags), "error", err)
+		return fmt.Errorf("failed to replace classifier item tags: %w", err)
+	}
+	return nil
+}
+
+// ListForItem returns an item's tags, categories first, including admin
+// removals.
+func (r *itemTagRepository) ListForItem(ctx context.Context, itemID int) ([]models.ItemTag, error) {
+	var tags []models.ItemTag
+	err := r.dbClient.WithContext(ctx).
+		Where("item_id = ?", itemID).
+		Order("kind, source, tag").
+		Find(&tags).Error
+	if err != nil {
+		r.logger.Errorw("Failed to list item tags", "itemID", itemID, "error", err)
+		return nil, fmt.Errorf("failed to list item tags: %w", err)
+	}
+	return tags, nil
+}
+
+// Upsert stores an admin tag, replacing any classifier tag or removal of the
+// same tag on the item.
+func (r *itemTagRepository) Upsert(ctx context.Context, tag *models.ItemTag) error {
+	err := r.dbClient.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "item_id"}, {Name: "tag"}},
+			DoUpdates: clause.AssignmentColumns([]string{"kind", "source", "removed"}),
+		}).
+		Create(tag).Error
+	if err != nil {
+		r.logger.Errorw("Failed to save item tag", "itemID", tag.ItemID, "tag", tag.Tag, "error", err)
+		return fmt.Errorf("failed to save item tag: %w", err)
+	}
+	return nil
+}
+
+// Remove marks an item's tag as removed by an admin, so the classifier does
+// not add it back, and reports whether the item had the tag.
+func (r *itemTagRepository) Remove(ctx context.Context, itemID int, tag string) (bool, error) {
+	result := r.dbClient.WithContext(ctx).
+		Model(&models.ItemTag{}).
+		Where("item_id = ? AND tag = ? AND NOT removed", itemID, tag).
+		Updates(map[string]any{"removed": true, "source": models.ItemTagSourceAdmin})
+	if result.Error != nil {
+		r.logger.Errorw("Failed to remove item tag", "itemID", itemID, "tag", tag, "error", result.Error)
+		return false, fmt.Errorf("failed to remove item tag: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CountTags returns every tag in use with its item count, categories first.
+func (r *itemTagRepository) CountTags(ctx context.Context) ([]models.ItemTagCount, error) {
+	var counts []models.ItemTagCount
+	err := r.dbClient.WithContext(ctx).
+		Model(&models.ItemTag{}).
+		Select("tag, kind, COUNT(*) AS items").
+		Where("NOT removed").
+		Group("tag, kind").
+		Order("kind, tag").
+		Scan(&counts).Error
+	if err != nil {
+		r.logger.Errorw("Failed to count item tags", "error", err)
+		return nil, fmt.Errorf("failed to count item tags: %w", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// marketIndexInsertBatchSize bounds the rows per INSERT of index values.
+const marketIndexInsertBatchSize = 500
+
+// marketIndexRepository implements MarketIndexRepository.
+type marketIndexRepository struct {
+	dbClient *gorm.DB
+	logger   *zap.SugaredLogger
+}
+
+// NewMarketIndexRepository creates a new market index repository.
+func NewMarketIndexRepository(dbClient *gorm.DB, logger *zap.SugaredLogger) MarketIndexRepository {
+	return &marketIndexRepository{
+		dbClient: dbClient,
+		logger:   logger,
+	}
+}
+
+// CreateCustom stores a custom index unless the user already has maxPerUser
+// indices, in which case it returns false.
+func (r *marketIndexRepository) CreateCustom(ctx context.Context, index *models.CustomMarketIndex, maxPerUser int) (bool, error) {
+	created := false
+	err := r.dbClient.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Serialize a user's creates so concurrent requests cannot pass the limit.
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "market_indices:"+index.UserID).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.CustomMarketIndex{}).Where("user_id = ?", index.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(maxPerUser) {
+			return nil
+		}
+		if err := tx.Create(index).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	if err != nil {
+		r.logger.Errorw("Failed to create custom market index", "user_id", index.UserID, "error", err)
+		return false, fmt.Errorf("failed to create custom market index: %w", err)
+	}
+	return created, nil
+}
+
+// ListCustom returns a user's custom indices, oldest first.
+func (r *marketIndexRepository) ListCustom(ctx context.Context, userID string) ([]models.CustomMarketIndex, error) {
+	var indices []models.CustomMarketIndex
+	err := r.dbClient.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at, id").
+		Find(&indices).Error
+	if err != nil {
+		r.logger.Errorw("Failed to list custom market indices", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("failed to list custom market indices: %w", err)
+	}
+	return indices, nil
+}
+
+// ListAllCustom returns every user's custom indices, oldest first.
+func (r *marketIndexRepository) ListAllCustom(ctx context.Context) ([]models.CustomMarketIndex, error) {
+	var indices []models.CustomMarketIndex
+	if err := r.dbClient.WithContext(ctx).Order("id").Find(&indices).Error; err != nil {
+		r.logger.Errorw("Failed to list all custom market indices", "error", err)
+		return nil, fmt.Errorf("failed to list all custom market indices: %w", err)
+	}
+	return indices, nil
+}
+
+// GetCustom returns one of a user's custom indices, or nil when it does not
+// exist.
+func (r *marketIndexRepository) GetCustom(ctx context.Context, userID string, id int64) (*models.CustomMarketIndex, error) {
+	var index models.CustomMarketIndex
+	err := r.dbClient.WithContext(ctx).Where("user_id = ? AND id = ?", userID, id).First(&index).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Errorw("Failed to get custom market index", "user_id", userID, "id", id, "error", err)
+		return nil, fmt.Errorf("failed to get custom market index: %w", err)
+	}
+	return &index, nil
+}
+
+// DeleteCustom removes one of a user's custom indices along with its
+// computed values, and reports whether it existed.
+func (r *marketIndexRepository) DeleteCustom(ctx context.Context, userID string, id int64) (bool, error) {
+	deleted := false
+	err := r.dbClient.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND id = ?", userID, id).Delete(&models.CustomMarketIndex{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		deleted = true
+		return tx.Where("index_id = ?", models.CustomMarketIndexID(id)).Delete(&models.MarketIndexValue{}).Error
+	})
+	if err != nil {
+		r.logger.Errorw("Failed to delete custom market index", "user_id", userID, "id", id, "error", err)
+		return false, fmt.Errorf("failed to delete custom market index: %w", err)
+	}
+	return deleted, nil
+}
+
+// LatestValue returns an index's most recent value, or nil before it is
+// first computed.
+func (r *marketIndexRepository) LatestValue(ctx context.Context, indexID string) (*models.MarketIndexValue, error) {
+	var value models.MarketIndexValue
+	err := r.dbClient.WithContext(ctx).
+		Where("index_id = ?", indexID).
+		Order("timestamp DESC").
+		First(&value).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Errorw("Failed to get latest market index value", "index_id", indexID, "error", err)
+		return nil, fmt.Errorf("failed to get latest market index value: %w", err)
+	}
+	return &value, nil
+}
+
+// UpsertValues records index values, replacing any stored for the same
+// index and bucket.
+func (r *marketIndexRepository) UpsertValues(ctx context.Context, values []models.MarketIndexValue) error {
+	if len(values) == 0 {
+		return nil
+	}
+	err := r.dbClient.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "index_id"}, {Name: "timestamp"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "constituents"}),
+		}).
+		CreateInBatches(&values, marketIndexInsertBatchSize).Error
+	if err != nil {
+		r.logger.Errorw("Failed to record market index values", "count", len(values), "error", err)
+		return fmt.Errorf("failed to record market index values: %w", err)
+	}
+	return nil
+}
+
+// ListValues returns an index's values between two times (inclusive),
+// oldest first.
+func (r *marketIndexRepository) ListValues(ctx context.Context, indexID string, from, to time.Time) ([]models.MarketIndexValue, error) {
+	var values []models.MarketIndexValue
+	err := r.dbClient.WithContext(ctx).
+		Where("index_id = ? AND timestamp BETWEEN ? AND ?", indexID, from, to).
+		Order("timestamp").
+		Find(&values).Error
+	if err != nil {
+		r.logger.Errorw("Failed to list market index values", "index_id", indexID, "error", err)
+		return nil, fmt.Errorf("failed to list market index values: %w", err)
+	}
+	return values, nil
+}
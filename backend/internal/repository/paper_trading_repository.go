@@ -0,0 +1,365 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
+)
+
+// paperTradingRepository implements PaperTradingRepository.
+//
+// Every write that moves gold or items locks the account row first, then the
+// offer and position rows, so concurrent requests and price syncs serialize
+// per account without deadlocking.
+type paperTradingRepository struct {
+	dbClient *gorm.DB
+	logger   *zap.SugaredLogger
+}
+
+// NewPaperTradingRepository creates a new paper trading repository.
+func NewPaperTradingRepository(dbClient *gorm.DB, logger *zap.SugaredLogger) PaperTradingRepository {
+	return &paperTradingRepository{
+		dbClient: dbClient,
+		logger:   logger,
+	}
+}
+
+var forUpdate = clause.Locking{Strength: "UPDATE"}
+
+// CreateAccount stores a new paper trading account unless its user already
+// has maxPerUser accounts, in which case it returns false.
+func (r *paperTradingRepository) CreateAccount(ctx context.Context, account *models.PaperAccount, maxPerUser int) (bool, error) {
+	created := false
+	err := r.dbClient.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Serialize a user's creates so concurrent requests cannot pass the limit.
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "paper_accounts:"+account.UserID).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.PaperAccount{}).Where("user_id = ?", account.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(maxPerUser) {
+			return nil
+		}
+		if err := tx.Create(account).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	if err != nil {
+		r.logger.Errorw("Failed to create paper account", "id", account.ID, "user_id", account.UserID, "error", err)
+		return false, fmt.Errorf("failed to create paper account: %w", err)
+	}
+	return created, nil
+}
+
+// GetAccount returns an account by ID, or nil when it does not exist.
+func (r *paperTradingRepository) GetAccount(ctx context.Context, id string) (*models.PaperAccount, error) {
+	var account models.PaperAccount
+	if err := r.dbClient.WithContext(ctx).Where("id = ?", id).First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Errorw("Failed to get paper account", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to get paper account: %w", err)
+	}
+	return &account, nil
+}
+
+// PlaceOffer stores a new open offer. Buy offers move Price * Quantity from
+// the account's cash into the offer; sell offers reserve held items.
+// Returns models.ErrPaperInsufficientFunds, models.ErrPaperInsufficientItems
+// or models.ErrPaperOfferSlotsFull when the offer cannot be placed.
+func (r *paperTradingRepository) PlaceOffer(ctx context.Context, offer *models.PaperOffer) error {
+	err := r.dbClient.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var account models.PaperAccount
+		if err := tx.Clauses(forUpdate).Where("id = ?", offer.AccountID).First(&account).Error; err != nil {
+			return err
+		}
+
+		var open int64
+		if err := tx.Model(&models.PaperOffer{}).
+			Where("account_id = ? AND status = ?", offer.AccountID, models.PaperOfferOpen).
+			Count(&open).Error; err != nil {
+			return err
+		}
+		if open >= models.MaxPaperOpenOffers {
+			return models.ErrPaperOfferSlotsFull
+		}
+
+		switch offer.Side {
+		case models.PaperOfferBuy:
+			cost := offer.Price * offer.Quantity
+			if account.Cash < cost {
+				return models.ErrPaperInsufficientFunds
+			}
+			if err := tx.Model(&account).Update("cash", gorm.Expr("cash - ?", cost)).Error; err != nil {
+				return err
+			}
+		case models.PaperOfferSell:
+			var position models.PaperPosition
+			err := tx.Clauses(forUpdate).
+				Where("account_id = ? AND item_id = ?", offer.AccountID, offer.ItemID).
+				First(&position).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrPaperInsufficientItems
+			}
+			if err != nil {
+				return err
+			}
+			if position.Quantity-position.Reserved < offer.Quantity {
+				return models.ErrPaperInsufficientItems
+			}
+			if err := tx.Model(&position).Update("reserved", gorm.Expr("reserved + ?", offer.Quantity)).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(offer).Error
+	})
+	if err != nil {
+		if isPaperTradingError(err) {
+			return err
+		}
+		r.logger.Errorw("Failed to place paper offer", "account_id", offer.AccountID, "item_id", offer.ItemID, "error", err)
+		return fmt.Errorf("failed to place paper offer: %w", err)
+	}
+	return nil
+}
+
+// CancelOffer cancels an open offer and returns its unfilled gold or items.
+// Returns nil when the account has no such offer, and
+// models.ErrPaperOfferClosed when it has already filled or been cancelled.
+func (r *paperTradingRepository) CancelOffer(ctx context.Context, accountID string, offerID int64) (*models.PaperOffer, error) {
+	var offer models.PaperOffer
+	err := r.dbClient.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var account models.PaperAccount
+		if err := tx.Clauses(forUpdate).Where("id = ?", accountID).First(&account).Error; err != nil {
+			return err
+		}
+		err := tx.Clauses(forUpdate).Where("id = ? AND account_id = ?", offerID, accountID).First(&offer).Error
+		if err != nil {
+			return err
+		}
+		if offer.Status != models.PaperOfferOpen {
+			return models.ErrPaperOfferClosed
+		}
+
+		remaining := offer.Remaining()
+		switch offer.Side {
+		case models.PaperOfferBuy:
+			if err := tx.Model(&account).Update("cash", gorm.Expr("cash + ?", remaining*offer.Price)).Error; err != nil {
+				return err
+			}
+		case models.PaperOfferSell:
+			if err := tx.Model(&models.PaperPosition{}).
+				Where("account_id = ? AND item_id = ?", accountID, offer.ItemID).
+				Update("reserved", gorm.Expr("reserved - ?", remaining)).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now().UTC()
+		offer.Status = models.PaperOfferCancelled
+		offer.CompletedAt = &now
+		return tx.Save(&offer).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		if isPaperTradingError(err) {
+			return nil, err
+		}
+		r.logger.Errorw("Failed to cancel paper offer", "account_id", accountID, "offer_id", offerID, "error", err)
+		return nil, fmt.Errorf("failed to cancel paper offer: %w", err)
+	}
+	return &offer, nil
+}
+
+// ListOffers returns an account's offers, newest first. An empty status
+// returns offers in every state.
+func (r *paperTradingRepository) ListOffers(
+	ctx context.Context,
+	accountID string,
+	status models.PaperOfferStatus,
+	limit int,
+) ([]models.PaperOffer, error) {
+	query := r.dbClient.WithContext(ctx).Where("account_id = ?", accountID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var offers []models.PaperOffer
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&offers).Error; err != nil {
+		r.logger.Errorw("Failed to list paper offers", "account_id", accountID, "error", err)
+		return nil, fmt.Errorf("failed to list paper offers: %w", err)
+	}
+	return offers, nil
+}
+
+// ListOpenOffersForItems returns open offers on the given items across all
+// accounts, oldest first so earlier offers fill first.
+func (r *paperTradingRepository) ListOpenOffersForItems(ctx context.Context, itemIDs []int) ([]models.PaperOffer, error) {
+	if len(itemIDs) == 0 {
+		return []models.PaperOffer{}, nil
+	}
+
+	var offers []models.PaperOffer
+	err := r.dbClient.WithContext(ctx).
+		Where("status = ? AND item_id IN ?", models.PaperOfferOpen, itemIDs).
+		Order("created_at ASC, id ASC").
+		Find(&offers).Error
+	if err != nil {
+		r.logger.Errorw("Failed to list open paper offers", "item_count", len(itemIDs), "error", err)
+		return nil, fmt.Errorf("failed to list open paper offers: %w", err)
+	}
+	return offers, nil
+}
+
+// FillOffer fills as much of an open offer as possible at its own price.
+// Buys are capped by the item's buy limit (limit <= 0 means unlimited); sells
+// pay GE tax per unit and credit the proceeds to the account. Returns nil when
+// the offer is no longer open or the buy limit is used up.
+func (r *paperTradingRepository) FillOffer(ctx context.Context, offerID int64, at time.Time, limit int) (*models.PaperFill, error) {
+	var fill *models.PaperFill
+	err := r.dbClient.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var accountID string
+		if err := tx.Model(&models.PaperOffer{}).Where("id = ?", offerID).Pluck("account_id", &accountID).Error; err != nil {
+			return err
+		}
+		if accountID == "" {
+			return nil
+		}
+
+		var account models.PaperAccount
+		if err := tx.Clauses(forUpdate).Where("id = ?", accountID).First(&account).Error; err != nil {
+			return err
+		}
+		var offer models.PaperOffer
+		if err := tx.Clauses(forUpdate).Where("id = ?", offerID).First(&offer).Error; err != nil {
+			return err
+		}
+		if offer.Status != models.PaperOfferOpen {
+			return nil
+		}
+
+		position := models.PaperPosition{AccountID: offer.AccountID, ItemID: offer.ItemID}
+		err := tx.Clauses(forUpdate).
+			Where("account_id = ? AND item_id = ?", offer.AccountID, offer.ItemID).
+			First(&position).Error
+		exists := err == nil
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		quantity := offer.Remaining()
+		var tax int64
+		var profit *int64
+		switch offer.Side {
+		case models.PaperOfferBuy:
+			if allowance := position.BuyAllowance(limit, at); allowance >= 0 {
+				quantity = min(quantity, allowance)
+			}
+			if quantity == 0 {
+				return nil
+			}
+			position.RecordBuy(quantity, offer.Price, at)
+		case models.PaperOfferSell:
+			tax = quantity * utils.GETax(offer.ItemID, offer.Price)
+			realized := position.RecordSell(quantity, offer.Price, tax)
+			profit = &realized
+			if err := tx.Model(&account).Update("cash", gorm.Expr("cash + ?", quantity*offer.Price-tax)).Error; err != nil {
+				return err
+			}
+		}
+
+		if exists {
+			err = tx.Save(&position).Error
+		} else {
+			err = tx.Create(&position).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		offer.Filled += quantity
+		offer.TaxPaid += tax
+		if offer.Remaining() == 0 {
+			offer.Status = models.PaperOfferFilled
+			completed := at
+			offer.CompletedAt = &completed
+		}
+		if err := tx.Save(&offer).Error; err != nil {
+			return err
+		}
+
+		fill = &models.PaperFill{
+			OfferID:     offer.ID,
+			AccountID:   offer.AccountID,
+			ItemID:      offer.ItemID,
+			Side:        offer.Side,
+			Quantity:    quantity,
+			Price:       offer.Price,
+			Tax:         tax,
+			Profit:      profit,
+			FilledAt:    at,
+			OfferStatus: offer.Status,
+		}
+		return tx.Create(fill).Error
+	})
+	if err != nil {
+		r.logger.Errorw("Failed to fill paper offer", "offer_id", offerID, "error", err)
+		return nil, fmt.Errorf("failed to fill paper offer: %w", err)
+	}
+	return fill, nil
+}
+
+// ListPositions returns an account's positions that still hold items or have
+// realized profit, ordered by item ID.
+func (r *paperTradingRepository) ListPositions(ctx context.Context, accountID string) ([]models.PaperPosition, error) {
+	var positions []models.PaperPosition
+	err := r.dbClient.WithContext(ctx).
+		Where("account_id = ? AND (quantity > 0 OR realized_profit <> 0)", accountID).
+		Order("item_id ASC").
+		Find(&positions).Error
+	if err != nil {
+		r.logger.Errorw("Failed to list paper positions", "account_id", accountID, "error", err)
+		return nil, fmt.Errorf("failed to list paper positions: %w", err)
+	}
+	return positions, nil
+}
+
+// ListFills returns an account's most recent fills.
+func (r *paperTradingRepository) ListFills(ctx context.Context, accountID string, limit int) ([]models.PaperFill, error) {
+	var fills []models.PaperFill
+	err := r.dbClient.WithContext(ctx).
+		Where("account_id = ?", accountID).
+		Order("filled_at DESC, id DESC").
+		Limit(limit).
+		Find(&fills).Error
+	if err != nil {
+		r.logger.Errorw("Failed to list paper fills", "account_id", accountID, "error", err)
+		return nil, fmt.Errorf("failed to list paper fills: %w", err)
+	}
+	return fills, nil
+}
+
+// isPaperTradingError reports whether err is an expected business rule
+// violation rather than a database failure.
+func isPaperTradingError(err error) bool {
+	return errors.Is(err, models.ErrPaperInsufficientFunds) ||
+		errors.Is(err, models.ErrPaperInsufficientItems) ||
+		errors.Is(err, models.ErrPaperOfferSlotsFull) ||
+		errors.Is(err, models.ErrPaperOfferClosed)
+}
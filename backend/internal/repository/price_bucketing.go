@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
+)
+
+// sqlBucketingThreshold is the estimated row count above which
+// GetTimeseriesPoints downsamples in SQL instead of loading every row and
+// sampling in memory.
+const sqlBucketingThreshold = 2000
+
+// bucketPlan describes a SQL downsampling query: rows in [start, end] are
+// grouped into date_bin buckets of width anchored at start.
+type bucketPlan struct {
+	start   time.Time
+	end     time.Time
+	width   time.Duration
+	buckets int
+}
+
+// timeseriesWindow resolves the time bounds of a history query. start is nil
+// when the query is unbounded (PeriodAll without StartTime).
+func timeseriesWindow(params models.PriceHistoryParams) (start, end *time.Time) {
+	if params.StartTime != nil {
+		s := params.StartTime.UTC()
+		start = &s
+	} else if params.Period != "" && params.Period != models.PeriodAll {
+		if d := params.Period.Duration(); d > 0 {
+			s := time.Now().UTC().Add(-d)
+			start = &s
+		}
+	}
+	if params.EndTime != nil {
+		e := params.EndTime.UTC()
+		end = &e
+	}
+	return start, end
+}
+
+// planBuckets decides whether a timeseries query should be downsampled in SQL.
+// It returns nil when the estimated row count is small enough for in-memory
+// sampling (or the range holds no data).
+func (r *priceRepository) planBuckets(
+	ctx context.Context,
+	table, timestep string,
+	itemID int,
+	params models.PriceHistoryParams,
+) (*bucketPlan, error) {
+	if params.MaxPoints == nil || *params.MaxPoints < 1 || params.Limit > 0 {
+		return nil, nil
+	}
+
+	start, end := timeseriesWindow(params)
+	windowEnd := time.Now().UTC()
+	if end != nil {
+		windowEnd = *end
+	}
+
+	windowStart := time.Time{}
+	if start != nil {
+		windowStart = *start
+	} else {
+		var first sql.NullTime
+		err := r.dbClient.WithContext(ctx).
+			Raw(fmt.Sprintf(`SELECT MIN(timestamp) FROM %s WHERE item_id = ? AND timestamp <= ?`, table), itemID, windowEnd).
+			Row().Scan(&first)
+		if err != nil {
+			r.logger.Errorw("Failed to get first timeseries timestamp", "itemID", itemID, "timestep", timestep, "error", err)
+			return nil, fmt.Errorf("get first timeseries timestamp: %w", err)
+		}
+		if !first.Valid {
+			return nil, nil
+		}
+		windowStart = first.Time.UTC()
+	}
+
+	span := windowEnd.Sub(windowStart)
+	step := models.PriceResolution(normalizeTimestep(timestep)).Coverage()
+	if span <= 0 || step <= 0 || int(span/step) <= sqlBucketingThreshold {
+		return nil, nil
+	}
+
+	buckets := *params.MaxPoints
+	if params.Sampling == utils.SamplingMinMax {
+		// Each bucket contributes up to two rows (its high and its low).
+		buckets = max(buckets/2, 1)
+	}
+
+	// Whole seconds, rounded up past span/buckets so the row at windowEnd
+	// never opens an extra bucket.
+	width := span.Truncate(time.Second)/time.Duration(buckets) + time.Second
+	width = width.Truncate(time.Second)
+
+	return &bucketPlan{start: windowStart, end: windowEnd, width: width, buckets: buckets}, nil
+}
+
+// getBucketedTimeseriesPoints downsamples in Postgres, returning at most
+// MaxPoints rows ordered newest-first like GetTimeseriesPoints:
+//   - voronoi: per bucket, the row nearest the bucket centre, with a missing
+//     side filled from the nearest row in the bucket that has it
+//   - average: per bucket, the mean prices and volumes at the rows' mean time
+//   - minmax: per bucket, the row with the highest high and the row with the
+//     lowest low
+//   - lttb: minmax preselection into MaxPoints buckets, then LTTB in memory
+func (r *priceRepository) getBucketedTimeseriesPoints(
+	ctx context.Context,
+	table string,
+	itemID int,
+	plan bucketPlan,
+	mode utils.SamplingMode,
+) ([]models.PriceTimeseriesPoint, error) {
+	binned := fmt.Sprintf(`
+		WITH binned AS (
+			SELECT item_id, timestamp, inserted_at, avg_high_price, avg_low_price,
+			       high_price_volume, low_price_volume,
+			       date_bin(CAST(@width AS interval), timestamp, CAST(@origin AS timestamptz)) AS bin
+			FROM %s
+			WHERE item_id = @itemID
+			  AND timestamp >= @start AND timestamp <= @end
+			  AND (avg_high_price IS NOT NULL OR avg_low_price IS NOT NULL)
+		)`, table)
+
+	var query string
+	switch mode.OrDefault() {
+	case utils.SamplingAverage:
+		query = binned + `
+			SELECT item_id,
+			       to_timestamp(AVG(EXTRACT(EPOCH FROM timestamp))) AS timestamp,
+			       MAX(inserted_at) AS inserted_at,
+			       ROUND(AVG(avg_high_price))::bigint AS avg_high_price,
+			       ROUND(AVG(avg_low_price))::bigint AS avg_low_price,
+			       ROUND(AVG(high_price_volume))::bigint AS high_price_volume,
+			       ROUND(AVG(low_price_volume))::bigint AS low_price_volume
+			FROM binned
+			GROUP BY item_id, bin
+			ORDER BY bin DESC`
+	case utils.SamplingMinMax, utils.SamplingLTTB:
+		query = binned + `,
+		highs AS (
+			SELECT DISTINCT ON (bin) * FROM binned
+			ORDER BY bin, COALESCE(avg_high_price, avg_low_price) DESC, timestamp
+		),
+		lows AS (
+			SELECT DISTINCT ON (bin) * FROM binned
+			ORDER BY bin, COALESCE(avg_low_price, avg_high_price) ASC, timestamp
+		)
+			SELECT item_id, timestamp, inserted_at, avg_high_price, avg_low_price,
+			       high_price_volume, low_price_volume
+			FROM (SELECT * FROM highs UNION SELECT * FROM lows) extremes
+			ORDER BY timestamp DESC`
+	default:
+		query = binned + `,
+		ranked AS (
+			SELECT *, ABS(EXTRACT(EPOCH FROM timestamp - (bin + CAST(@width AS interval) / 2))) AS dist
+			FROM binned
+		)
+			SELECT item_id, timestamp, inserted_at, avg_high_price, avg_low_price,
+			       high_price_volume, low_price_volume
+			FROM (
+				SELECT DISTINCT ON (bin) bin, item_id, timestamp, inserted_at,
+				       COALESCE(avg_high_price, FIRST_VALUE(avg_high_price)
+				           OVER (PARTITION BY bin ORDER BY avg_high_price IS NULL, dist)) AS avg_high_price,
+				       COALESCE(avg_low_price, FIRST_VALUE(avg_low_price)
+				           OVER (PARTITION BY bin ORDER BY avg_low_price IS NULL, dist)) AS avg_low_price,
+				       high_price_volume, low_price_volume
+				FROM ranked
+				ORDER BY bin, dist
+			) nearest
+			ORDER BY timestamp DESC`
+	}
+
+	args := map[string]any{
+		"width":  fmt.Sprintf("%d seconds", int64(plan.width/time.Second)),
+		"origin": plan.start,
+		"itemID": itemID,
+		"start":  plan.start,
+		"end":    plan.end,
+	}
+
+	var points []models.PriceTimeseriesPoint
+	if err := r.dbClient.WithContext(ctx).Raw(query, args).Scan(&points).Error; err != nil {
+		r.logger.Errorw("Failed to get bucketed timeseries points",
+			"itemID", itemID, "table", table, "buckets", plan.buckets, "error", err)
+		return nil, fmt.Errorf("get bucketed timeseries points: %w", err)
+	}
+
+	if mode == utils.SamplingLTTB && len(points) > plan.buckets {
+		points = sampleDescending(points, plan.buckets, utils.SamplingLTTB, timeseriesAccessor)
+	}
+	return points, nil
+}
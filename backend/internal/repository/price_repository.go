@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -286,6 +287,16 @@ func SampleDailyPoints(points []models.PriceTimeseriesDaily, targetPoints int) [
 	return utils.SampleByTime(points, targetPoints, dailyAccessor)
 }
 
+// sampleDescending applies the requested sampling mode to points ordered
+// newest-first. The samplers expect ascending time, so the slice is reversed
+// before sampling and the result is returned newest-first again.
+func sampleDescending[T any](points []T, targetPoints int, mode utils.SamplingMode, accessor utils.TimeAccessor[T]) []T {
+	slices.Reverse(points)
+	sampled := utils.Sample(points, targetPoints, mode, accessor)
+	slices.Reverse(sampled)
+	return sampled
+}
+
 // batchInsertTimeseries inserts price timeseries points in batches using GORM's
 // Create with ON CONFLICT DO NOTHING. The generic type T must be one of the
 // timeseries models (PriceTimeseries5m, PriceTimeseries1h, PriceTimeseries6h,
@@ -389,19 +400,23 @@ func (r *priceRepository) GetTimeseriesPoints(ctx context.Context, itemID int, t
 		return nil, err
 	}
 
+	// Large ranges are bucketed in SQL so only MaxPoints rows leave the database.
+	plan, err := r.planBuckets(ctx, table, timestep, itemID, params)
+	if err != nil {
+		return nil, err
+	}
+	if plan != nil {
+		return r.getBucketedTimeseriesPoints(ctx, table, itemID, *plan, params.Sampling)
+	}
+
 	query := r.dbClient.WithContext(ctx).Table(table).Where("item_id = ?", itemID)
 
-	if params.StartTime != nil {
-		query = query.Where("timestamp >= ?", params.StartTime.UTC())
-	} else if params.Period != "" && params.Period != models.PeriodAll {
-		d := params.Period.Duration()
-		if d > 0 {
-			start := time.Now().UTC().Add(-d)
-			query = query.Where("timestamp >= ?", start)
-		}
+	start, end := timeseriesWindow(params)
+	if start != nil {
+		query = query.Where("timestamp >= ?", *start)
 	}
-	if params.EndTime != nil {
-		query = query.Where("timestamp <= ?", params.EndTime.UTC())
+	if end != nil {
+		query = query.Where("timestamp <= ?", *end)
 	}
 
 	query = query.Order("timestamp DESC")
@@ -416,7 +431,7 @@ func (r *priceRepository) GetTimeseriesPoints(ctx context.Context, itemID int, t
 	}
 
 	if params.MaxPoints != nil && len(points) > *params.MaxPoints {
-		points = SampleTimeseriesPoints(points, *params.MaxPoints)
+		points = sampleDescending(points, *params.MaxPoints, params.Sampling, timeseriesAccessor)
 	}
 
 	return points, nil
@@ -452,12 +467,210 @@ func (r *priceRepository) GetDailyPoints(ctx context.Context, itemID int, params
 	}
 
 	if params.MaxPoints != nil && len(points) > *params.MaxPoints {
-		points = SampleDailyPoints(points, *params.MaxPoints)
+		points = sampleDescending(points, *params.MaxPoints, params.Sampling, dailyAccessor)
 	}
 
 	return points, nil
 }
 
+func (r *priceRepository) GetDailyPointsForItems(ctx context.Context, itemIDs []int, from, to time.Time) ([]models.PriceTimeseriesDaily, error) {
+	if len(itemIDs) == 0 {
+		return []models.PriceTimeseriesDaily{}, nil
+	}
+
+	var points []models.PriceTimeseriesDaily
+	err := r.dbClient.WithContext(ctx).
+		Table("price_timeseries_daily").
+		Where("item_id IN ?", itemIDs).
+		Where("day >= ? AND day <= ?", from.UTC().Format("2006-01-02"), to.UTC().Format("2006-01-02")).
+		Order("item_id, day").
+		Find(&points).Error
+	if err != nil {
+		r.logger.Errorw("Failed to get daily timeseries points", "itemCount", len(itemIDs), "error", err)
+		return nil, fmt.Errorf("get daily points for items: %w", err)
+	}
+	return points, nil
+}
+
+func (r *priceRepository) GetTimeseriesPointsForItems(
+	ctx context.Context,
+	itemIDs []int,
+	timestep string,
+	from, to time.Time,
+) ([]models.PriceTimeseriesPoint, error) {
+	if len(itemIDs) == 0 {
+		return []models.PriceTimeseriesPoint{}, nil
+	}
+	table, err := timeseriesTableForTimestep(timestep)
+	if err != nil {
+		return nil, err
+	}
+
+	var points []models.PriceTimeseriesPoint
+	err = r.dbClient.WithContext(ctx).
+		Table(table).
+		Where("item_id IN ?", itemIDs).
+		Where("timestamp >= ? AND timestamp <= ?", from.UTC(), to.UTC()).
+		Order("item_id, timestamp").
+		Find(&points).Error
+	if err != nil {
+		r.logger.Errorw("Failed to get timeseries points for items", "itemCount", len(itemIDs), "timestep", timestep, "error", err)
+		return nil, fmt.Errorf("get timeseries points for items: %w", err)
+	}
+	return points, nil
+}
+
+// GetPricesAt returns, per item, the most recent row of one resolution whose
+// coverage window contains ts. Rows with neither price set are ignored.
+func (r *priceRepository) GetPricesAt(
+	ctx context.Context,
+	itemIDs []int,
+	resolution models.PriceResolution,
+	ts time.Time,
+) ([]models.PriceAt, error) {
+	if len(itemIDs) == 0 {
+		return []models.PriceAt{}, nil
+	}
+
+	ts = ts.UTC()
+	from := ts.Add(-resolution.Coverage())
+
+	var query string
+	args := []interface{}{itemIDs}
+	switch resolution {
+	case models.ResolutionLatest:
+		query = `
+			SELECT DISTINCT ON (item_id)
+				item_id,
+				observed_at AS timestamp,
+				high_price,
+				low_price
+			FROM price_latest
+			WHERE item_id IN ?
+				AND observed_at <= ? AND observed_at > ?
+				AND (high_price IS NOT NULL OR low_price IS NOT NULL)
+			ORDER BY item_id, observed_at DESC
+		`
+		args = append(args, ts, from)
+	case models.ResolutionDaily:
+		query = `
+			SELECT
+				item_id,
+				(day::timestamp AT TIME ZONE 'UTC') AS timestamp,
+				avg_high_price AS high_price,
+				avg_low_price AS low_price
+			FROM price_timeseries_daily
+			WHERE item_id IN ?
+				AND day = ?
+				AND (avg_high_price IS NOT NULL OR avg_low_price IS NOT NULL)
+		`
+		args = append(args, ts.Format("2006-01-02"))
+	default:
+		table, err := timeseriesTableForTimestep(string(resolution))
+		if err != nil {
+			return nil, err
+		}
+		query = fmt.Sprintf(`
+			SELECT DISTINCT ON (item_id)
+				item_id,
+				timestamp,
+				avg_high_price AS high_price,
+				avg_low_price AS low_price
+			FROM %s
+			WHERE item_id IN ?
+				AND timestamp <= ? AND timestamp > ?
+				AND (avg_high_price IS NOT NULL OR avg_low_price IS NOT NULL)
+			ORDER BY item_id, timestamp DESC
+		`, table)
+		args = append(args, ts, from)
+	}
+
+	var prices []models.PriceAt
+	if err := r.dbClient.WithContext(ctx).Raw(query, args...).Scan(&prices).Error; err != nil {
+		r.logger.Errorw("Failed to get prices at timestamp", "resolution", resolution, "ts", ts, "error", err)
+		return nil, fmt.Errorf("get prices at %s (%s): %w", ts.Format(time.RFC3339), resolution, err)
+	}
+
+	for i := range prices {
+		prices[i].Timestamp = prices[i].Timestamp.UTC()
+		prices[i].Resolution = resolution
+		prices[i].RequestedAt = ts
+	}
+	return prices, nil
+}
+
+// GetHourlySeries returns an item's hourly average prices and total volume since the
+// given time, oldest first. Hours covered by the 5m table are aggregated from it;
+// older hours come from the 1h table.
+func (r *priceRepository) GetHourlySeries(ctx context.Context, itemID int, since time.Time) ([]models.HourlyPricePoint, error) {
+	since = since.UTC()
+
+	query := `
+		WITH fine AS (
+			SELECT
+				date_trunc('hour', timestamp) AS hour,
+				AVG(avg_high_price)::double precision AS high_price,
+				AVG(avg_low_price)::double precision AS low_price,
+				AVG(COALESCE((avg_high_price + avg_low_price) / 2.0, avg_high_price, avg_low_price))::double precision AS mid_price,
+				SUM(high_price_volume + low_price_volume)::bigint AS volume
+			FROM price_timeseries_5m
+			WHERE item_id = ? AND timestamp >= ?
+			GROUP BY 1
+		),
+		coarse AS (
+			SELECT
+				timestamp AS hour,
+				avg_high_price::double precision AS high_price,
+				avg_low_price::double precision AS low_price,
+				COALESCE((avg_high_price + avg_low_price) / 2.0, avg_high_price, avg_low_price)::double precision AS mid_price,
+				high_price_volume + low_price_volume AS volume
+			FROM price_timeseries_1h
+			WHERE item_id = ? AND timestamp >= ?
+				AND timestamp < COALESCE((SELECT MIN(hour) FROM fine), 'infinity'::timestamptz)
+		)
+		SELECT hour AS timestamp, high_price, low_price, mid_price, volume FROM fine WHERE mid_price IS NOT NULL
+		UNION ALL
+		SELECT hour AS timestamp, high_price, low_price, mid_price, volume FROM coarse WHERE mid_price IS NOT NULL
+		ORDER BY timestamp
+	`
+
+	var points []models.HourlyPricePoint
+	if err := r.dbClient.WithContext(ctx).Raw(query, itemID, since, itemID, since).Scan(&points).Error; err != nil {
+		r.logger.Errorw("Failed to get hourly series", "itemID", itemID, "since", since, "error", err)
+		return nil, fmt.Errorf("get hourly series: %w", err)
+	}
+
+	for i := range points {
+		points[i].Timestamp = points[i].Timestamp.UTC()
+	}
+	return points, nil
+}
+
+// GetTradeVolumes returns the units traded per item since the given time,
+// summed from the 1h buckets. Items without buckets are omitted.
+func (r *priceRepository) GetTradeVolumes(ctx context.Context, since time.Time) (map[int]int64, error) {
+	var rows []struct {
+		ItemID int
+		Volume int64
+	}
+	err := r.dbClient.WithContext(ctx).Raw(`
+		SELECT item_id, SUM(high_price_volume + low_price_volume)::bigint AS volume
+		FROM price_timeseries_1h
+		WHERE timestamp >= ?
+		GROUP BY item_id
+	`, since.UTC()).Scan(&rows).Error
+	if err != nil {
+		r.logger.Errorw("Failed to get trade volumes", "since", since, "error", err)
+		return nil, fmt.Errorf("get trade volumes: %w", err)
+	}
+
+	volumes := make(map[int]int64, len(rows))
+	for _, row := range rows {
+		volumes[row.ItemID] = row.Volume
+	}
+	return volumes, nil
+}
+
 func (r *priceRepository) Rollup24hToDailyBefore(ctx context.Context, cutoff time.Time) (int64, error) {
 	cutoff = cutoff.UTC()
 
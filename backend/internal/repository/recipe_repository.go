@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// recipeRepository implements RecipeRepository.
+type recipeRepository struct {
+	dbClient *gorm.DB
+	logger   *zap.SugaredLogger
+}
+
+// NewRecipeRepository creates a new recipe repository.
+func NewRecipeRepository(dbClient *gorm.DB, logger *zap.SugaredLogger) RecipeRepository {
+	return &recipeRepository{
+		dbClient: dbClient,
+		logger:   logger,
+	}
+}
+
+// ListOverrides returns every admin edit to the recipe registry.
+func (r *recipeRepository) ListOverrides(ctx context.Context) ([]models.RecipeOverride, error) {
+	var overrides []models.RecipeOverride
+	if err := r.dbClient.WithContext(ctx).Order("recipe_id").Find(&overrides).Error; err != nil {
+		r.logger.Errorw("Failed to list recipe overrides", "error", err)
+		return nil, fmt.Errorf("failed to list recipe overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+// UpsertOverride stores an admin edit, replacing any earlier edit of the recipe.
+func (r *recipeRepository) UpsertOverride(ctx context.Context, override *models.RecipeOverride) error {
+	err := r.dbClient.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "recipe_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"definition", "removed", "updated_at"}),
+		}).
+		Create(override).Error
+	if err != nil {
+		r.logger.Errorw("Failed to save recipe override", "recipe_id", override.RecipeID, "error", err)
+		return fmt.Errorf("failed to save recipe override: %w", err)
+	}
+	return nil
+}
+
+// DeleteOverride removes an admin edit and reports whether it existed.
+func (r *recipeRepository) DeleteOverride(ctx context.Context, recipeID string) (bool, error) {
+	result := r.dbClient.WithContext(ctx).
+		Where("recipe_id = ?", recipeID).
+		Delete(&models.RecipeOverride{})
+	if result.Error != nil {
+		r.logger.Errorw("Failed to delete recipe override", "recipe_id", recipeID, "error", result.Error)
+		return false, fmt.Errorf("failed to delete recipe override: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
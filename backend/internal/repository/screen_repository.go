@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// screenRepository implements ScreenRepository.
+type screenRepository struct {
+	dbClient *gorm.DB
+	logger   *zap.SugaredLogger
+}
+
+// NewScreenRepository creates a new saved screen repository.
+func NewScreenRepository(dbClient *gorm.DB, logger *zap.SugaredLogger) ScreenRepository {
+	return &screenRepository{
+		dbClient: dbClient,
+		logger:   logger,
+	}
+}
+
+// Create stores a screen unless the user already has maxPerUser screens, in
+// which case it returns false.
+func (r *screenRepository) Create(ctx context.Context, screen *models.Screen, maxPerUser int) (bool, error) {
+	created := false
+	err := r.dbClient.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Serialize a user's creates so concurrent requests cannot pass the limit.
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "screens:"+screen.UserID).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.Screen{}).Where("user_id = ?", screen.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(maxPerUser) {
+			return nil
+		}
+		if err := tx.Create(screen).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	if err != nil {
+		r.logger.Errorw("Failed to create screen", "user_id", screen.UserID, "error", err)
+		return false, fmt.Errorf("failed to create screen: %w", err)
+	}
+	return created, nil
+}
+
+// List returns a user's screens, oldest first.
+func (r *screenRepository) List(ctx context.Context, userID string) ([]models.Screen, error) {
+	var screens []models.Screen
+	err := r.dbClient.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at, id").
+		Find(&screens).Error
+	if err != nil {
+		r.logger.Errorw("Failed to list screens", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("failed to list screens: %w", err)
+	}
+	return screens, nil
+}
+
+// Get returns one of a user's screens, or nil when it does not exist.
+func (r *screenRepository) Get(ctx context.Context, userID string, id int64) (*models.Screen, error) {
+	var screen models.Screen
+	err := r.dbClient.WithContext(ctx).Where("user_id = ? AND id = ?", userID, id).First(&screen).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Errorw("Failed to get screen", "user_id", userID, "id", id, "error", err)
+		return nil, fmt.Errorf("failed to get screen: %w", err)
+	}
+	return &screen, nil
+}
+
+// Update saves a screen's name, expression and alert flag. The alert state is
+// cleared so the next check records a fresh baseline instead of alerting on
+// every item the edited expression matches.
+func (r *screenRepository) Update(ctx context.Context, screen *models.Screen) (bool, error) {
+	result := r.dbClient.WithContext(ctx).
+		Model(&models.Screen{}).
+		Where("user_id = ? AND id = ?", screen.UserID, screen.ID).
+		Updates(map[string]any{
+			"name":           screen.Name,
+			"expression":     screen.Expression,
+			"alert":          screen.Alert,
+			"alert_item_ids": nil,
+			"updated_at":     time.Now(),
+		})
+	if result.Error != nil {
+		r.logger.Errorw("Failed to update screen", "user_id", screen.UserID, "id", screen.ID, "error", result.Error)
+		return false, fmt.Errorf("failed to update screen: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Delete removes one of a user's screens and reports whether it existed.
+func (r *screenRepository) Delete(ctx context.Context, userID string, id int64) (bool, error) {
+	result := r.dbClient.WithContext(ctx).Where("user_id = ? AND id = ?", userID, id).Delete(&models.Screen{})
+	if result.Error != nil {
+		r.logger.Errorw("Failed to delete screen", "user_id", userID, "id", id, "error", result.Error)
+		return false, fmt.Errorf("failed to delete screen: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListAlerting returns every screen with alerts enabled.
+func (r *screenRepository) ListAlerting(ctx context.Context) ([]models.Screen, error) {
+	var screens []models.Screen
+	if err := r.dbClient.WithContext(ctx).Where("alert").Order("id").Find(&screens).Error; err != nil {
+		r.logger.Errorw("Failed to list alerting screens", "error", err)
+		return nil, fmt.Errorf("failed to list alerting screens: %w", err)
+	}
+	return screens, nil
+}
+
+// SetAlertState records the items a screen matched at its last alert check,
+// and when it last alerted if alertedAt is set.
+func (r *screenRepository) SetAlertState(ctx context.Context, id int64, itemIDs []int, alertedAt *time.Time) error {
+	if itemIDs == nil {
+		itemIDs = []int{}
+	}
+	encoded, err := json.Marshal(itemIDs)
+	if err != nil {
+		return fmt.Errorf("encode screen alert items: %w", err)
+	}
+	updates := map[string]any{"alert_item_ids": datatypes.JSON(encoded)}
+	if alertedAt != nil {
+		updates["last_alert_at"] = *alertedAt
+	}
+	if err := r.dbClient.WithContext(ctx).Model(&models.Screen{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		r.logger.Errorw("Failed to record screen alert state", "id", id, "error", err)
+		return fmt.Errorf("failed to record screen alert state: %w", err)
+	}
+	return nil
+}
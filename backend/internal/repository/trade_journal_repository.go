@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// journalInsertBatchSize bounds the rows per INSERT on import.
+const journalInsertBatchSize = 500
+
+// tradeJournalRepository implements TradeJournalRepository.
+type tradeJournalRepository struct {
+	dbClient *gorm.DB
+	logger   *zap.SugaredLogger
+}
+
+// NewTradeJournalRepository creates a new trade journal repository.
+func NewTradeJournalRepository(dbClient *gorm.DB, logger *zap.SugaredLogger) TradeJournalRepository {
+	return &tradeJournalRepository{
+		dbClient: dbClient,
+		logger:   logger,
+	}
+}
+
+// Create stores one journal entry.
+func (r *tradeJournalRepository) Create(ctx context.Context, entry *models.TradeJournalEntry) error {
+	if err := r.dbClient.WithContext(ctx).Create(entry).Error; err != nil {
+		r.logger.Errorw("Failed to create journal entry", "user_id", entry.UserID, "error", err)
+		return fmt.Errorf("failed to create journal entry: %w", err)
+	}
+	return nil
+}
+
+// CreateBatch stores entries in one transaction, so an import is all or nothing.
+func (r *tradeJournalRepository) CreateBatch(ctx context.Context, entries []models.TradeJournalEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	err := r.dbClient.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(&entries, journalInsertBatchSize).Error
+	})
+	if err != nil {
+		r.logger.Errorw("Failed to import journal entries", "count", len(entries), "error", err)
+		return fmt.Errorf("failed to import journal entries: %w", err)
+	}
+	return nil
+}
+
+// List returns a page of a user's entries, newest first, and the total
+// number of entries matching the filters.
+func (r *tradeJournalRepository) List(
+	ctx context.Context,
+	userID string,
+	params models.JournalListParams,
+) ([]models.TradeJournalEntry, int64, error) {
+	query := r.filtered(ctx, userID, params)
+
+	var total int64
+	if err := query.Model(&models.TradeJournalEntry{}).Count(&total).Error; err != nil {
+		r.logger.Errorw("Failed to count journal entries", "user_id", userID, "error", err)
+		return nil, 0, fmt.Errorf("failed to count journal entries: %w", err)
+	}
+
+	var entries []models.TradeJournalEntry
+	err := r.filtered(ctx, userID, params).
+		Order("traded_at DESC, id DESC").
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Find(&entries).Error
+	if err != nil {
+		r.logger.Errorw("Failed to list journal entries", "user_id", userID, "error", err)
+		return nil, 0, fmt.Errorf("failed to list journal entries: %w", err)
+	}
+	return entries, total, nil
+}
+
+// ListAll returns every entry of a user in trade order.
+func (r *tradeJournalRepository) ListAll(ctx context.Context, userID string) ([]models.TradeJournalEntry, error) {
+	var entries []models.TradeJournalEntry
+	err := r.dbClient.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("traded_at ASC, id ASC").
+		Find(&entries).Error
+	if err != nil {
+		r.logger.Errorw("Failed to load journal", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("failed to load journal: %w", err)
+	}
+	return entries, nil
+}
+
+// Delete removes one of a user's entries and reports whether it existed.
+func (r *tradeJournalRepository) Delete(ctx context.Context, userID string, id int64) (bool, error) {
+	result := r.dbClient.WithContext(ctx).
+		Where("user_id = ? AND id = ?", userID, id).
+		Delete(&models.TradeJournalEntry{})
+	if result.Error != nil {
+		r.logger.Errorw("Failed to delete journal entry", "user_id", userID, "id", id, "error", result.Error)
+		return false, fmt.Errorf("failed to delete journal entry: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *tradeJournalRepository) filtered(ctx context.Context, userID string, params models.JournalListParams) *gorm.DB {
+	query := r.dbClient.WithContext(ctx).Where("user_id = ?", userID)
+	if params.ItemID != nil {
+		query = query.Where("item_id = ?", *params.ItemID)
+	}
+	if params.From != nil {
+		query = query.Where("traded_at >= ?", *params.From)
+	}
+	if params.To != nil {
+		query = query.Where("traded_at <= ?", *params.To)
+	}
+	return query
+}
@@ -18,6 +18,16 @@ type Scheduler struct {
 	priceService     services.PriceService
 	itemService      services.ItemService
 	watchlistService services.WatchlistService
+	analyticsService services.AnalyticsService
+	paperService     services.PaperTradingService
+	buyLimitService  services.BuyLimitService
+	bankService      services.BankSnapshotService
+	arbitrageService services.ArbitrageService
+	indexService     services.MarketIndexService
+	tagService       services.ItemTagService
+	autocomplete     services.AutocompleteService
+	screenerService  services.ScreenerService
+	marketTable      services.MarketTableService
 	sseHub           *services.SSEHub
 	logger           *zap.SugaredLogger
 	itemsSynced      atomic.Bool
@@ -44,6 +54,65 @@ func NewScheduler(
 	}
 }
 
+// SetAnalyticsService enables the analytics cache refresh jobs. Must be called before Start.
+func (s *Scheduler) SetAnalyticsService(analyticsService services.AnalyticsService) {
+	s.analyticsService = analyticsService
+}
+
+// SetPaperTradingService enables filling paper offers after each current prices sync.
+// Must be called before Start.
+func (s *Scheduler) SetPaperTradingService(paperService services.PaperTradingService) {
+	s.paperService = paperService
+}
+
+// SetBuyLimitService enables buy limit reset notifications. Must be called before Start.
+func (s *Scheduler) SetBuyLimitService(buyLimitService services.BuyLimitService) {
+	s.buyLimitService = buyLimitService
+}
+
+// SetBankSnapshotService enables recording each saved bank's value once a day.
+// Must be called before Start.
+func (s *Scheduler) SetBankSnapshotService(bankService services.BankSnapshotService) {
+	s.bankService = bankService
+}
+
+// SetArbitrageService enables refreshing arbitrage reports after each current
+// prices sync. Must be called before Start.
+func (s *Scheduler) SetArbitrageService(arbitrageService services.ArbitrageService) {
+	s.arbitrageService = arbitrageService
+}
+
+// SetMarketIndexService enables extending market indices every hour and
+// backfilling new custom indices every minute.
+// Must be called before Start.
+func (s *Scheduler) SetMarketIndexService(indexService services.MarketIndexService) {
+	s.indexService = indexService
+}
+
+// SetItemTagService enables classifying items after each items sync.
+// Must be called before Start.
+func (s *Scheduler) SetItemTagService(tagService services.ItemTagService) {
+	s.tagService = tagService
+}
+
+// SetAutocompleteService enables rebuilding the autocomplete index after each
+// items sync and keeping its prices current. Must be called before Start.
+func (s *Scheduler) SetAutocompleteService(autocomplete services.AutocompleteService) {
+	s.autocomplete = autocomplete
+}
+
+// SetScreenerService enables refreshing the screener snapshot and checking
+// screen alerts after each current prices sync. Must be called before Start.
+func (s *Scheduler) SetScreenerService(screenerService services.ScreenerService) {
+	s.screenerService = screenerService
+}
+
+// SetMarketTableService enables refreshing the market table after each
+// current prices sync. Must be called before Start.
+func (s *Scheduler) SetMarketTableService(marketTable services.MarketTableService) {
+	s.marketTable = marketTable
+}
+
 // Start starts all scheduled jobs.
 func (s *Scheduler) Start() error {
 	s.logger.Info("Starting scheduler...")
@@ -88,6 +157,59 @@ func (s *Scheduler) Start() error {
 	}
 	s.logger.Info("Scheduled: Watchlist shares cleanup (daily at 02:00)")
 
+	// Job 5: Refresh cached seasonality profiles every 6 hours at :15
+	if s.analyticsService != nil {
+		_, err = s.cron.AddFunc("0 15 */6 * * *", s.refreshSeasonalityJob)
+		if err != nil {
+			return err
+		}
+		s.logger.Info("Scheduled: Seasonality refresh (every 6 hours)")
+	}
+
+	// Job 6: Announce buy limit resets every minute at :30
+	if s.buyLimitService != nil {
+		_, err = s.cron.AddFunc("30 * * * * *", s.notifyBuyLimitResetsJob)
+		if err != nil {
+			return err
+		}
+		s.logger.Info("Scheduled: Buy limit reset notifications (every 1 minute)")
+	}
+
+	// Job 7: Record saved bank values daily at 00:30, after the daily rollups
+	if s.bankService != nil {
+		_, err = s.cron.AddFunc("0 30 0 * * *", s.recordBankValuesJob)
+		if err != nil {
+			return err
+		}
+		s.logger.Info("Scheduled: Bank snapshot values (daily at 00:30)")
+	}
+
+	// Job 8: Extend market indices every hour at :10, once the last 1h bucket has closed
+	if s.indexService != nil {
+		_, err = s.cron.AddFunc("0 10 * * * *", s.refreshMarketIndicesJob)
+		if err != nil {
+			return err
+		}
+		s.logger.Info("Scheduled: Market index refresh (every 1 hour)")
+
+		// New custom indices are backfilled here instead of in the request
+		_, err = s.cron.AddFunc("45 * * * * *", s.backfillMarketIndicesJob)
+		if err != nil {
+			return err
+		}
+		s.logger.Info("Scheduled: New market index backfill (every 1 minute)")
+	}
+
+	// Job 9: Rebuild the autocomplete index every 15 minutes to pick up alias
+	// approvals and shifts in trade volume between item syncs
+	if s.autocomplete != nil {
+		_, err = s.cron.AddFunc("0 */15 * * * *", s.rebuildAutocompleteJob)
+		if err != nil {
+			return err
+		}
+		s.logger.Info("Scheduled: Autocomplete index rebuild (every 15 minutes)")
+	}
+
 	// Start the cron scheduler
 	s.cron.Start()
 	s.logger.Info("Scheduler started successfully")
@@ -116,6 +238,8 @@ func (s *Scheduler) syncItemsJob() {
 		s.logger.Errorf("Items sync failed: %v", err)
 		return
 	}
+	s.classifyItems(ctx)
+	s.rebuildAutocomplete(ctx)
 
 	// Mark items as ready for price syncing; on the first successful sync, trigger an immediate
 	// price sync so the API has data without waiting for the next cron tick.
@@ -131,6 +255,34 @@ func (s *Scheduler) syncItemsJob() {
 	)
 }
 
+// classifyItems re-runs the item classifier over the items just synced.
+func (s *Scheduler) classifyItems(ctx context.Context) {
+	if s.tagService == nil {
+		return
+	}
+	if _, err := s.tagService.ClassifyItems(ctx); err != nil {
+		s.logger.Errorf("Item classification failed: %v", err)
+	}
+}
+
+// rebuildAutocomplete rebuilds the autocomplete index from the stored items.
+func (s *Scheduler) rebuildAutocomplete(ctx context.Context) {
+	if s.autocomplete == nil {
+		return
+	}
+	if err := s.autocomplete.Rebuild(ctx); err != nil {
+		s.logger.Errorf("Autocomplete index rebuild failed: %v", err)
+	}
+}
+
+// rebuildAutocompleteJob rebuilds the autocomplete index on its own schedule.
+func (s *Scheduler) rebuildAutocompleteJob() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	s.rebuildAutocomplete(ctx)
+}
+
 // syncCurrentPricesJob syncs current prices from OSRS Wiki /latest.
 func (s *Scheduler) syncCurrentPricesJob() {
 	if !s.itemsSynced.Load() {
@@ -156,7 +308,7 @@ func (s *Scheduler) syncCurrentPricesJob() {
 		"price_updates", len(updates),
 	)
 
-	// Broadcast SSE events if hub is available and clients are connected
+	// Clients hear about the new prices before the slower refreshes below run.
 	if s.sseHub != nil && s.sseHub.ClientCount() > 0 {
 		s.logger.Debugw("Broadcasting price updates to SSE clients",
 			"client_count", s.sseHub.ClientCount(),
@@ -172,6 +324,97 @@ func (s *Scheduler) syncCurrentPricesJob() {
 		// Broadcast price updates
 		s.broadcastPriceUpdates(updates)
 	}
+
+	s.fillPaperOffers(ctx, updates)
+	s.refreshArbitrage(ctx)
+	if s.autocomplete != nil {
+		s.autocomplete.UpdatePrices(updates)
+	}
+	// The market table is written from the snapshot checkScreens refreshes.
+	s.checkScreens(ctx)
+	s.refreshMarketTable(ctx)
+}
+
+// fillPaperOffers fills paper offers crossed by the latest prices and
+// announces each fill to SSE clients watching the item.
+func (s *Scheduler) fillPaperOffers(ctx context.Context, updates []models.BulkPriceUpdate) {
+	if s.paperService == nil || len(updates) == 0 {
+		return
+	}
+
+	fills, err := s.paperService.ProcessPriceUpdates(ctx, updates)
+	if err != nil {
+		s.logger.Errorf("Paper offer fill failed: %v", err)
+		return
+	}
+
+	if s.sseHub == nil {
+		return
+	}
+	for _, fill := range fills {
+		itemID := fill.ItemID
+		s.sseHub.Broadcast(services.SSEMessage{
+			Event:     "paper-fill",
+			Data:      fill,
+			Timestamp: time.Now(),
+			ItemID:    &itemID,
+		})
+	}
+}
+
+// checkScreens refreshes the screener snapshot with the latest prices and
+// notifies the owners of alerting screens that items started matching.
+func (s *Scheduler) checkScreens(ctx context.Context) {
+	if s.screenerService == nil {
+		return
+	}
+	if err := s.screenerService.RefreshSnapshot(ctx); err != nil {
+		s.logger.Errorf("Screener snapshot refresh failed: %v", err)
+		return
+	}
+
+	alerts, err := s.screenerService.CheckAlerts(ctx)
+	if err != nil {
+		s.logger.Errorf("Screen alert check finished with errors: %v", err)
+	}
+	if len(alerts) == 0 {
+		return
+	}
+	if s.sseHub != nil {
+		for _, alert := range alerts {
+			s.sseHub.Broadcast(services.SSEMessage{
+				Event:     "screen-alert",
+				Data:      alert,
+				Timestamp: time.Now(),
+				UserID:    alert.UserID,
+			})
+		}
+	}
+	s.logger.Infow("Screen alerts announced", "alerts", len(alerts))
+}
+
+// refreshMarketTable stores the screener snapshot as the market table.
+func (s *Scheduler) refreshMarketTable(ctx context.Context) {
+	if s.marketTable == nil {
+		return
+	}
+	if err := s.marketTable.Refresh(ctx); err != nil {
+		s.logger.Errorf("Market table refresh failed: %v", err)
+	}
+}
+
+// refreshArbitrage recomputes the cached arbitrage reports from the prices
+// just synced.
+func (s *Scheduler) refreshArbitrage(ctx context.Context) {
+	if s.arbitrageService == nil {
+		return
+	}
+	if err := s.arbitrageService.RefreshSetArbitrage(ctx); err != nil {
+		s.logger.Errorf("Set arbitrage refresh failed: %v", err)
+	}
+	if err := s.arbitrageService.RefreshDoseArbitrage(ctx); err != nil {
+		s.logger.Errorf("Dose arbitrage refresh failed: %v", err)
+	}
 }
 
 // broadcastPriceUpdates broadcasts price updates through SSE in batches.
@@ -278,3 +521,135 @@ func (s *Scheduler) cleanupWatchlistSharesJob() {
 		"deleted_count", count,
 	)
 }
+
+// refreshSeasonalityJob recomputes cached seasonality profiles for recently requested items.
+func (s *Scheduler) refreshSeasonalityJob() {
+	if s.analyticsService == nil {
+		return
+	}
+
+	s.logger.Info("Starting seasonality refresh job")
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	refreshed, err := s.analyticsService.RefreshSeasonality(ctx)
+	if err != nil {
+		s.logger.Errorf("Seasonality refresh finished with errors: %v", err)
+	}
+
+	duration := time.Since(start)
+	s.logger.Infow("Seasonality refresh completed",
+		"duration_ms", duration.Milliseconds(),
+		"refreshed", refreshed,
+	)
+}
+
+// notifyBuyLimitResetsJob announces closed buy limit windows to their users'
+// SSE clients.
+func (s *Scheduler) notifyBuyLimitResetsJob() {
+	if s.buyLimitService == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resets, err := s.buyLimitService.ProcessResets(ctx)
+	if err != nil {
+		s.logger.Errorf("Buy limit reset notification failed: %v", err)
+		return
+	}
+	if len(resets) == 0 {
+		return
+	}
+
+	if s.sseHub != nil {
+		for _, reset := range resets {
+			itemID := reset.ItemID
+			s.sseHub.Broadcast(services.SSEMessage{
+				Event:     "buy-limit-reset",
+				Data:      reset,
+				Timestamp: time.Now(),
+				ItemID:    &itemID,
+				UserID:    reset.UserID,
+			})
+		}
+	}
+	s.logger.Infow("Buy limit resets announced", "resets", len(resets))
+}
+
+// recordBankValuesJob records every saved bank's value at current prices.
+func (s *Scheduler) recordBankValuesJob() {
+	if s.bankService == nil {
+		return
+	}
+
+	s.logger.Info("Starting bank snapshot values job")
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	recorded, err := s.bankService.RecordDailyValues(ctx)
+	if err != nil {
+		s.logger.Errorf("Bank snapshot values failed: %v", err)
+		return
+	}
+
+	duration := time.Since(start)
+	s.logger.Infow("Bank snapshot values completed",
+		"duration_ms", duration.Milliseconds(),
+		"recorded", recorded,
+	)
+}
+
+// refreshMarketIndicesJob extends every market index over the 1h buckets
+// stored since its last value.
+func (s *Scheduler) refreshMarketIndicesJob() {
+	if s.indexService == nil {
+		return
+	}
+
+	s.logger.Info("Starting market index refresh job")
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Minute)
+	defer cancel()
+
+	stored, err := s.indexService.RefreshIndices(ctx)
+	if err != nil {
+		s.logger.Errorf("Market index refresh finished with errors: %v", err)
+	}
+
+	duration := time.Since(start)
+	s.logger.Infow("Market index refresh completed",
+		"duration_ms", duration.Milliseconds(),
+		"values", stored,
+	)
+}
+
+// backfillMarketIndicesJob computes the history of custom indices created
+// since the last run.
+func (s *Scheduler) backfillMarketIndicesJob() {
+	if s.indexService == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	start := time.Now()
+	stored, err := s.indexService.BackfillNewIndices(ctx)
+	if err != nil {
+		s.logger.Errorf("Market index backfill finished with errors: %v", err)
+	}
+	if stored == 0 {
+		return
+	}
+	s.logger.Infow("Market index backfill completed",
+		"duration_ms", time.Since(start).Milliseconds(),
+		"values", stored,
+	)
+}
@@ -0,0 +1,230 @@
+package screener
+
+import (
+	"strings"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// value is the result of evaluating an expression against a row. Invalid
+// values are unknown, like SQL NULL: arithmetic on them stays unknown,
+// comparisons with them are unknown, and AND, OR and NOT follow three-valued
+// logic. A row matches only when the condition is known to be true.
+type value struct {
+	str   string
+	tags  []string
+	num   float64
+	typ   string
+	b     bool
+	valid bool
+}
+
+func number(n float64) value { return value{typ: models.ScreenerFieldNumber, num: n, valid: true} }
+func str(s string) value     { return value{typ: models.ScreenerFieldString, str: s, valid: true} }
+func boolean(b bool) value   { return value{typ: models.ScreenerFieldBool, b: b, valid: true} }
+func unknown(typ string) value {
+	return value{typ: typ}
+}
+
+func intPtr(v *int64) value {
+	if v == nil {
+		return unknown(models.ScreenerFieldNumber)
+	}
+	return number(float64(*v))
+}
+
+func floatPtr(v *float64) value {
+	if v == nil {
+		return unknown(models.ScreenerFieldNumber)
+	}
+	return number(*v)
+}
+
+// node is a type-checked expression.
+type node interface {
+	typ() string
+	eval(row *models.ScreenerRow) value
+}
+
+type literal struct{ v value }
+
+func (n literal) typ() string                      { return n.v.typ }
+func (n literal) eval(_ *models.ScreenerRow) value { return n.v }
+
+type fieldRef struct{ f *field }
+
+func (n fieldRef) typ() string                        { return n.f.typ }
+func (n fieldRef) eval(row *models.ScreenerRow) value { return n.f.get(row) }
+
+type negate struct{ x node }
+
+func (n negate) typ() string { return models.ScreenerFieldNumber }
+func (n negate) eval(row *models.ScreenerRow) value {
+	v := n.x.eval(row)
+	if !v.valid {
+		return v
+	}
+	return number(-v.num)
+}
+
+// arith is + - * / over numbers. Division by zero is unknown.
+type arith struct {
+	l, r node
+	op   string
+}
+
+func (n arith) typ() string { return models.ScreenerFieldNumber }
+func (n arith) eval(row *models.ScreenerRow) value {
+	l, r := n.l.eval(row), n.r.eval(row)
+	if !l.valid || !r.valid {
+		return unknown(models.ScreenerFieldNumber)
+	}
+	switch n.op {
+	case "+":
+		return number(l.num + r.num)
+	case "-":
+		return number(l.num - r.num)
+	case "*":
+		return number(l.num * r.num)
+	default:
+		if r.num == 0 {
+			return unknown(models.ScreenerFieldNumber)
+		}
+		return number(l.num / r.num)
+	}
+}
+
+// compare orders two values of the same type. Strings compare ignoring case.
+type compare struct {
+	l, r node
+	op   string
+}
+
+func (n compare) typ() string { return models.ScreenerFieldBool }
+func (n compare) eval(row *models.ScreenerRow) value {
+	l, r := n.l.eval(row), n.r.eval(row)
+	if !l.valid || !r.valid {
+		return unknown(models.ScreenerFieldBool)
+	}
+	c := cmpValues(l, r)
+	switch n.op {
+	case "=":
+		return boolean(c == 0)
+	case "!=":
+		return boolean(c != 0)
+	case "<":
+		return boolean(c < 0)
+	case "<=":
+		return boolean(c <= 0)
+	case ">":
+		return boolean(c > 0)
+	default:
+		return boolean(c >= 0)
+	}
+}
+
+// contains tests whether a string contains another, ignoring case.
+type contains struct{ l, r node }
+
+func (n contains) typ() string { return models.ScreenerFieldBool }
+func (n contains) eval(row *models.ScreenerRow) value {
+	l, r := n.l.eval(row), n.r.eval(row)
+	if !l.valid || !r.valid {
+		return unknown(models.ScreenerFieldBool)
+	}
+	return boolean(strings.Contains(strings.ToLower(l.str), strings.ToLower(r.str)))
+}
+
+// membership is "x IN (...)", and "tag = 'x'" with a one-element list. On
+// tags it matches when any tag is listed.
+type membership struct {
+	x    node
+	list []value
+	not  bool
+}
+
+func (n membership) typ() string { return models.ScreenerFieldBool }
+func (n membership) eval(row *models.ScreenerRow) value {
+	x := n.x.eval(row)
+	if !x.valid {
+		return unknown(models.ScreenerFieldBool)
+	}
+	found := false
+	if x.typ == models.ScreenerFieldTags {
+		for _, tag := range x.tags {
+			if found = n.listed(str(tag)); found {
+				break
+			}
+		}
+	} else {
+		found = n.listed(x)
+	}
+	return boolean(found != n.not)
+}
+
+func (n membership) listed(v value) bool {
+	for _, item := range n.list {
+		if cmpValues(v, item) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+type logical struct {
+	l, r node
+	and  bool
+}
+
+func (n logical) typ() string { return models.ScreenerFieldBool }
+func (n logical) eval(row *models.ScreenerRow) value {
+	l := n.l.eval(row)
+	// Short-circuit on a known false AND or a known true OR.
+	if l.valid && l.b != n.and {
+		return l
+	}
+	r := n.r.eval(row)
+	switch {
+	case r.valid && r.b != n.and:
+		return r
+	case l.valid && r.valid:
+		return boolean(n.and)
+	default:
+		return unknown(models.ScreenerFieldBool)
+	}
+}
+
+type not struct{ x node }
+
+func (n not) typ() string { return models.ScreenerFieldBool }
+func (n not) eval(row *models.ScreenerRow) value {
+	v := n.x.eval(row)
+	if !v.valid {
+		return v
+	}
+	return boolean(!v.b)
+}
+
+// cmpValues orders two known values of the same type.
+func cmpValues(a, b value) int {
+	switch a.typ {
+	case models.ScreenerFieldNumber:
+		switch {
+		case a.num < b.num:
+			return -1
+		case a.num > b.num:
+			return 1
+		}
+		return 0
+	case models.ScreenerFieldBool:
+		switch {
+		case a.b == b.b:
+			return 0
+		case b.b:
+			return -1
+		}
+		return 1
+	default:
+		return strings.Compare(strings.ToLower(a.str), strings.ToLower(b.str))
+	}
+}
@@ -0,0 +1,179 @@
+package screener
+
+import (
+	"strings"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
+)
+
+// natureRuneItemID is the nature rune, whose price is part of every high alch.
+const natureRuneItemID = 561
+
+// field is a column of models.ScreenerRow that expressions can refer to.
+type field struct {
+	get         func(row *models.ScreenerRow) value
+	name        string
+	typ         string
+	description string
+}
+
+// fields lists every field in the order Fields documents them.
+var fields = []field{
+	{name: "itemId", typ: models.ScreenerFieldNumber, description: "Item ID",
+		get: func(r *models.ScreenerRow) value { return number(float64(r.ItemID)) }},
+	{name: "name", typ: models.ScreenerFieldString, description: "Item name",
+		get: func(r *models.ScreenerRow) value { return str(r.Name) }},
+	{name: "members", typ: models.ScreenerFieldBool, description: "Members-only item",
+		get: func(r *models.ScreenerRow) value { return boolean(r.Members) }},
+	{name: "category", typ: models.ScreenerFieldString, description: "Item category",
+		get: func(r *models.ScreenerRow) value { return str(r.Category) }},
+	{name: "tag", typ: models.ScreenerFieldTags, description: "Item tags; tag = 'x' matches items carrying x",
+		get: func(r *models.ScreenerRow) value {
+			return value{typ: models.ScreenerFieldTags, tags: r.Tags, valid: true}
+		}},
+	{name: "buyLimit", typ: models.ScreenerFieldNumber, description: "Units per 4-hour buy limit window",
+		get: func(r *models.ScreenerRow) value { return intPtr(r.BuyLimit) }},
+	{name: "high", typ: models.ScreenerFieldNumber, description: "Instant buy price",
+		get: func(r *models.ScreenerRow) value { return intPtr(r.HighPrice) }},
+	{name: "low", typ: models.ScreenerFieldNumber, description: "Instant sell price",
+		get: func(r *models.ScreenerRow) value { return intPtr(r.LowPrice) }},
+	{name: "mid", typ: models.ScreenerFieldNumber, description: "Midpoint of high and low",
+		get: func(r *models.ScreenerRow) value { return intPtr(r.MidPrice) }},
+	{name: "spread", typ: models.ScreenerFieldNumber, description: "high - low",
+		get: func(r *models.ScreenerRow) value { return intPtr(r.Spread) }},
+	{name: "margin", typ: models.ScreenerFieldNumber, description: "Profit per unit bought at low and sold at high, after GE tax",
+		get: func(r *models.ScreenerRow) value { return intPtr(r.Margin) }},
+	{name: "roi", typ: models.ScreenerFieldNumber, description: "margin as a percentage of low",
+		get: func(r *models.ScreenerRow) value { return floatPtr(r.ROI) }},
+	{name: "limitProfit", typ: models.ScreenerFieldNumber, description: "margin times buyLimit",
+		get: func(r *models.ScreenerRow) value { return intPtr(r.LimitProfit) }},
+	{name: "highAlch", typ: models.ScreenerFieldNumber, description: "High alchemy value",
+		get: func(r *models.ScreenerRow) value { return intPtr(r.HighAlch) }},
+	{name: "alchProfit", typ: models.ScreenerFieldNumber, description: "highAlch minus the item and a nature rune at their high prices",
+		get: func(r *models.ScreenerRow) value { return intPtr(r.AlchProfit) }},
+	{name: "volume1h", typ: models.ScreenerFieldNumber, description: "Units traded over the last hour",
+		get: func(r *models.ScreenerRow) value { return number(float64(r.Volume1h)) }},
+	{name: "volume24h", typ: models.ScreenerFieldNumber, description: "Units traded over the last 24 hours",
+		get: func(r *models.ScreenerRow) value { return number(float64(r.Volume24h)) }},
+	{name: "change1h", typ: models.ScreenerFieldNumber, description: "Percentage change of mid over the last hour",
+		get: func(r *models.ScreenerRow) value { return floatPtr(r.Change1h) }},
+	{name: "change24h", typ: models.ScreenerFieldNumber, description: "Percentage change of mid over the last 24 hours",
+		get: func(r *models.ScreenerRow) value { return floatPtr(r.Change24h) }},
+	{name: "change7d", typ: models.ScreenerFieldNumber, description: "Percentage change of mid over the last 7 days",
+		get: func(r *models.ScreenerRow) value { return floatPtr(r.Change7d) }},
+}
+
+// fieldsByName indexes fields by lower-cased name; field names ignore case.
+var fieldsByName = func() map[string]*field {
+	byName := make(map[string]*field, len(fields))
+	for i := range fields {
+		byName[strings.ToLower(fields[i].name)] = &fields[i]
+	}
+	return byName
+}()
+
+// Fields returns the fields expressions can use.
+func Fields() []models.ScreenerField {
+	out := make([]models.ScreenerField, len(fields))
+	for i, f := range fields {
+		out[i] = models.ScreenerField{Name: f.name, Type: f.typ, Description: f.description}
+	}
+	return out
+}
+
+// Market is the data screener rows are computed from. Past mids are keyed by
+// item ID; items missing from a map have no value for it.
+type Market struct {
+	Prices    map[int]models.CurrentPrice
+	Volume1h  map[int]int64
+	Volume24h map[int]int64
+	Mid1hAgo  map[int]int64
+	Mid24hAgo map[int]int64
+	Mid7dAgo  map[int]int64
+	Items     []models.Item
+}
+
+// BuildRows computes a row for every item in the market.
+func BuildRows(m Market) []models.ScreenerRow {
+	var natureRune *int64
+	if p, ok := m.Prices[natureRuneItemID]; ok {
+		natureRune = p.HighPrice
+	}
+
+	rows := make([]models.ScreenerRow, len(m.Items))
+	for i, item := range m.Items {
+		row := &rows[i]
+		row.ItemID = item.ItemID
+		row.Name = item.Name
+		row.IconURL = item.IconURL
+		row.Members = item.Members
+		row.Category = item.Category
+		row.Tags = item.Tags
+		row.BuyLimit = widen(item.BuyLimit)
+		row.HighAlch = widen(item.HighAlch)
+		row.Volume1h = m.Volume1h[item.ItemID]
+		row.Volume24h = m.Volume24h[item.ItemID]
+
+		price := m.Prices[item.ItemID]
+		row.HighPrice, row.LowPrice = price.HighPrice, price.LowPrice
+		row.MidPrice = midPrice(price.HighPrice, price.LowPrice)
+		if high, low := price.HighPrice, price.LowPrice; high != nil && low != nil {
+			spread := *high - *low
+			margin := spread - utils.GETax(item.ItemID, *high)
+			row.Spread, row.Margin = &spread, &margin
+			if *low > 0 {
+				roi := float64(margin) / float64(*low) * 100
+				row.ROI = &roi
+			}
+			if row.BuyLimit != nil {
+				limitProfit := margin * *row.BuyLimit
+				row.LimitProfit = &limitProfit
+			}
+		}
+		if row.HighAlch != nil && price.HighPrice != nil && natureRune != nil {
+			profit := *row.HighAlch - *price.HighPrice - *natureRune
+			row.AlchProfit = &profit
+		}
+
+		row.Change1h = change(row.MidPrice, m.Mid1hAgo, item.ItemID)
+		row.Change24h = change(row.MidPrice, m.Mid24hAgo, item.ItemID)
+		row.Change7d = change(row.MidPrice, m.Mid7dAgo, item.ItemID)
+	}
+	return rows
+}
+
+// MidPrice returns the midpoint of high and low, or whichever is known.
+func MidPrice(high, low *int64) *int64 {
+	return midPrice(high, low)
+}
+
+func midPrice(high, low *int64) *int64 {
+	switch {
+	case high != nil && low != nil:
+		mid := (*high + *low) / 2
+		return &mid
+	case high != nil:
+		return high
+	default:
+		return low
+	}
+}
+
+// change returns the percentage change from an item's past mid to mid.
+func change(mid *int64, past map[int]int64, itemID int) *float64 {
+	then, ok := past[itemID]
+	if mid == nil || !ok || then <= 0 {
+		return nil
+	}
+	pct := (float64(*mid)/float64(then) - 1) * 100
+	return &pct
+}
+
+func widen(v *int) *int64 {
+	if v == nil {
+		return nil
+	}
+	w := int64(*v)
+	return &w
+}
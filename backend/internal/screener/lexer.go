@@ -0,0 +1,169 @@
+package screener
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenOperator
+	tokenKeyword
+)
+
+// token is one lexeme. Keywords and identifiers keep their source spelling in
+// text; keywords are matched upper-cased.
+type token struct {
+	text string
+	num  float64
+	kind tokenKind
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return strconv.Quote(t.text)
+	default:
+		return "'" + t.text + "'"
+	}
+}
+
+var keywords = map[string]struct{}{
+	"AND": {}, "OR": {}, "NOT": {}, "IN": {}, "CONTAINS": {},
+	"TRUE": {}, "FALSE": {}, "ORDER": {}, "BY": {}, "ASC": {}, "DESC": {}, "LIMIT": {},
+}
+
+// numberSuffixes are the Grand Exchange shorthands for large amounts.
+var numberSuffixes = map[byte]float64{'k': 1e3, 'm': 1e6, 'b': 1e9}
+
+// lex splits src into tokens, ending with a tokenEOF.
+func lex(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isIdentStart(c):
+			start := i
+			for i < len(src) && isIdentPart(src[i]) {
+				i++
+			}
+			text := src[start:i]
+			kind := tokenIdent
+			if _, ok := keywords[strings.ToUpper(text)]; ok {
+				kind = tokenKeyword
+				text = strings.ToUpper(text)
+			}
+			tokens = append(tokens, token{kind: kind, text: text, pos: start})
+		case isDigit(c) || (c == '.' && i+1 < len(src) && isDigit(src[i+1])):
+			tok, next, err := lexNumber(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i = next
+		case c == '\'' || c == '"':
+			tok, next, err := lexString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i = next
+		default:
+			op := lexOperator(src[i:])
+			if op == "" {
+				return nil, syntaxError(i, "unexpected character %q", rune(c))
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(src)}), nil
+}
+
+// lexNumber reads a decimal number with an optional k, m or b suffix.
+func lexNumber(src string, start int) (token, int, error) {
+	i := start
+	for i < len(src) && (isDigit(src[i]) || src[i] == '.' || src[i] == '_') {
+		i++
+	}
+	num, err := strconv.ParseFloat(strings.ReplaceAll(src[start:i], "_", ""), 64)
+	if err != nil {
+		return token{}, 0, syntaxError(start, "invalid number %q", src[start:i])
+	}
+	if i < len(src) {
+		if scale, ok := numberSuffixes[toLower(src[i])]; ok && (i+1 == len(src) || !isIdentPart(src[i+1])) {
+			num *= scale
+			i++
+		}
+	}
+	if i < len(src) && isIdentPart(src[i]) {
+		return token{}, 0, syntaxError(start, "invalid number %q", src[start:i+1])
+	}
+	return token{kind: tokenNumber, text: src[start:i], num: num, pos: start}, i, nil
+}
+
+// lexString reads a quoted string. A doubled quote stands for one quote.
+func lexString(src string, start int) (token, int, error) {
+	quote := src[start]
+	var b strings.Builder
+	for i := start + 1; i < len(src); i++ {
+		if src[i] != quote {
+			b.WriteByte(src[i])
+			continue
+		}
+		if i+1 < len(src) && src[i+1] == quote {
+			b.WriteByte(quote)
+			i++
+			continue
+		}
+		return token{kind: tokenString, text: b.String(), pos: start}, i + 1, nil
+	}
+	return token{}, 0, syntaxError(start, "unterminated string")
+}
+
+// lexOperator returns the operator at the start of s, longest first.
+func lexOperator(s string) string {
+	for _, op := range []string{"<=", ">=", "!=", "<>", "==", "=", "<", ">", "+", "-", "*", "/", "(", ")", ","} {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func toLower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// syntaxError reports a problem at a 0-based offset, shown 1-based.
+func syntaxError(pos int, format string, args ...any) error {
+	return fmt.Errorf("%w: %s at position %d", models.ErrInvalidScreen, fmt.Sprintf(format, args...), pos+1)
+}
@@ -0,0 +1,369 @@
+package screener
+
+import (
+	"strings"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// maxDepth bounds nesting so hostile expressions cannot exhaust the stack.
+const maxDepth = 32
+
+// parser is a recursive descent parser over the grammar documented on Parse.
+// It type-checks as it goes, so a parsed query cannot fail at evaluation.
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is the keyword or operator text.
+func (p *parser) accept(text string) bool {
+	t := p.peek()
+	if (t.kind == tokenKeyword || t.kind == tokenOperator) && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		return p.unexpected("expected '" + text + "'")
+	}
+	return nil
+}
+
+func (p *parser) unexpected(hint string) error {
+	t := p.peek()
+	if hint == "" {
+		return syntaxError(t.pos, "unexpected %s", t)
+	}
+	return syntaxError(t.pos, "%s, found %s", hint, t)
+}
+
+// enter descends one nesting level for the token at pos.
+func (p *parser) enter(pos int) error {
+	p.depth++
+	if p.depth > maxDepth {
+		return syntaxError(pos, "expression nested too deeply")
+	}
+	return nil
+}
+
+func (p *parser) leave() { p.depth-- }
+
+// condition parses an expression that must be true or false.
+func (p *parser) condition() (node, error) {
+	pos := p.peek().pos
+	n, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if n.typ() != models.ScreenerFieldBool {
+		return nil, syntaxError(pos, "expected a condition, found a %s expression", n.typ())
+	}
+	return n, nil
+}
+
+func (p *parser) or() (node, error) {
+	l, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().text == "OR" && p.peek().kind == tokenKeyword {
+		op := p.next()
+		r, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		if err := requireBools(op, l, r); err != nil {
+			return nil, err
+		}
+		l = logical{l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) and() (node, error) {
+	l, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().text == "AND" && p.peek().kind == tokenKeyword {
+		op := p.next()
+		r, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		if err := requireBools(op, l, r); err != nil {
+			return nil, err
+		}
+		l = logical{l: l, r: r, and: true}
+	}
+	return l, nil
+}
+
+func (p *parser) not() (node, error) {
+	if p.peek().kind != tokenKeyword || p.peek().text != "NOT" {
+		return p.comparison()
+	}
+	op := p.next()
+	if err := p.enter(op.pos); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+	x, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	if err := requireBools(op, x); err != nil {
+		return nil, err
+	}
+	return not{x: x}, nil
+}
+
+func (p *parser) comparison() (node, error) {
+	l, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	op := p.peek()
+	switch {
+	case op.kind == tokenOperator && isComparison(op.text):
+		p.next()
+		r, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		return compareNodes(op, l, r)
+	case op.kind == tokenKeyword && op.text == "CONTAINS":
+		p.next()
+		r, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		if l.typ() != models.ScreenerFieldString || r.typ() != models.ScreenerFieldString {
+			return nil, syntaxError(op.pos, "CONTAINS compares strings")
+		}
+		return contains{l: l, r: r}, nil
+	case op.kind == tokenKeyword && (op.text == "IN" || op.text == "NOT"):
+		return p.in(l)
+	}
+	return l, nil
+}
+
+// in parses "x [NOT] IN (literal, ...)".
+func (p *parser) in(x node) (node, error) {
+	negated := p.accept("NOT")
+	op := p.peek()
+	if err := p.expect("IN"); err != nil {
+		return nil, err
+	}
+	if x.typ() == models.ScreenerFieldBool {
+		return nil, syntaxError(op.pos, "IN needs a number, string or tag")
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	elemType := x.typ()
+	if elemType == models.ScreenerFieldTags {
+		elemType = models.ScreenerFieldString
+	}
+	var list []value
+	for {
+		t := p.peek()
+		lit, err := p.literal()
+		if err != nil {
+			return nil, err
+		}
+		if lit.typ != elemType {
+			return nil, syntaxError(t.pos, "IN list values must be %ss", elemType)
+		}
+		list = append(list, lit)
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return membership{x: x, list: list, not: negated}, nil
+}
+
+// literal parses a number, string or boolean, allowing a leading minus.
+func (p *parser) literal() (value, error) {
+	negative := p.accept("-")
+	t := p.next()
+	switch {
+	case t.kind == tokenNumber && negative:
+		return number(-t.num), nil
+	case t.kind == tokenNumber:
+		return number(t.num), nil
+	case negative:
+	case t.kind == tokenString:
+		return str(t.text), nil
+	case t.kind == tokenKeyword && (t.text == "TRUE" || t.text == "FALSE"):
+		return boolean(t.text == "TRUE"), nil
+	}
+	return value{}, syntaxError(t.pos, "expected a value, found %s", t)
+}
+
+func (p *parser) sum() (node, error) {
+	l, err := p.product()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.peek(); op.kind == tokenOperator && (op.text == "+" || op.text == "-"); op = p.peek() {
+		p.next()
+		r, err := p.product()
+		if err != nil {
+			return nil, err
+		}
+		if err := requireNumbers(op, l, r); err != nil {
+			return nil, err
+		}
+		l = arith{l: l, r: r, op: op.text}
+	}
+	return l, nil
+}
+
+func (p *parser) product() (node, error) {
+	l, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.peek(); op.kind == tokenOperator && (op.text == "*" || op.text == "/"); op = p.peek() {
+		p.next()
+		r, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		if err := requireNumbers(op, l, r); err != nil {
+			return nil, err
+		}
+		l = arith{l: l, r: r, op: op.text}
+	}
+	return l, nil
+}
+
+func (p *parser) unary() (node, error) {
+	if p.peek().kind != tokenOperator || p.peek().text != "-" {
+		return p.primary()
+	}
+	op := p.next()
+	if err := p.enter(op.pos); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	if err := requireNumbers(op, x); err != nil {
+		return nil, err
+	}
+	return negate{x: x}, nil
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenOperator && t.text == "(":
+		p.next()
+		if err := p.enter(t.pos); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return x, nil
+	case t.kind == tokenIdent:
+		p.next()
+		f, ok := fieldsByName[strings.ToLower(t.text)]
+		if !ok {
+			return nil, syntaxError(t.pos, "unknown field '%s'", t.text)
+		}
+		return fieldRef{f: f}, nil
+	case t.kind == tokenNumber || t.kind == tokenString || t.text == "TRUE" || t.text == "FALSE":
+		v, err := p.literal()
+		if err != nil {
+			return nil, err
+		}
+		return literal{v: v}, nil
+	}
+	return nil, p.unexpected("expected a field or value")
+}
+
+// compareNodes type-checks a comparison. Comparing tags with a string tests
+// membership; strings and booleans only support equality.
+func compareNodes(op token, l, r node) (node, error) {
+	operator := op.text
+	switch operator {
+	case "==":
+		operator = "="
+	case "<>":
+		operator = "!="
+	}
+	equality := operator == "=" || operator == "!="
+
+	if l.typ() == models.ScreenerFieldTags || r.typ() == models.ScreenerFieldTags {
+		tags, other := l, r
+		if r.typ() == models.ScreenerFieldTags {
+			tags, other = r, l
+		}
+		lit, ok := other.(literal)
+		if !equality || !ok || lit.v.typ != models.ScreenerFieldString {
+			return nil, syntaxError(op.pos, "tag can only be compared with = or != and a string")
+		}
+		return membership{x: tags, list: []value{lit.v}, not: operator == "!="}, nil
+	}
+	if l.typ() != r.typ() {
+		return nil, syntaxError(op.pos, "cannot compare %s with %s", l.typ(), r.typ())
+	}
+	if !equality && l.typ() != models.ScreenerFieldNumber {
+		return nil, syntaxError(op.pos, "%ss can only be compared with = or !=", l.typ())
+	}
+	return compare{l: l, r: r, op: operator}, nil
+}
+
+func isComparison(op string) bool {
+	switch op {
+	case "=", "==", "!=", "<>", "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
+
+func requireBools(op token, operands ...node) error {
+	for _, n := range operands {
+		if n.typ() != models.ScreenerFieldBool {
+			return syntaxError(op.pos, "%s needs conditions, found a %s expression", op.text, n.typ())
+		}
+	}
+	return nil
+}
+
+func requireNumbers(op token, operands ...node) error {
+	for _, n := range operands {
+		if n.typ() != models.ScreenerFieldNumber {
+			return syntaxError(op.pos, "'%s' needs numbers, found a %s expression", op.text, n.typ())
+		}
+	}
+	return nil
+}
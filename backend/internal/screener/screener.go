@@ -0,0 +1,168 @@
+// Package screener filters and ranks items with a small expression language
+// over computed market fields, evaluated against an in-memory snapshot of
+// screener rows.
+package screener
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// Query is a parsed screener expression. It is immutable and safe for
+// concurrent use.
+type Query struct {
+	where node
+	order []orderTerm
+	limit int
+}
+
+type orderTerm struct {
+	expr node
+	desc bool
+}
+
+// defaultOrder ranks matches by liquidity when the expression has no ORDER BY.
+var defaultOrder = []orderTerm{{expr: fieldRef{f: fieldsByName["volume24h"]}, desc: true}}
+
+// Parse compiles an expression such as
+//
+//	margin > 50k AND volume24h > 500 AND members = true ORDER BY roi DESC LIMIT 20
+//
+// The grammar, with keywords in any case:
+//
+//	query      = [ condition ] [ ORDER BY term { "," term } ] [ LIMIT number ]
+//	term       = sum [ ASC | DESC ]
+//	condition  = and { OR and }
+//	and        = not { AND not }
+//	not        = NOT not | comparison
+//	comparison = sum [ ( "=" | "!=" | "<" | "<=" | ">" | ">=" | CONTAINS ) sum ]
+//	           | sum [ NOT ] IN "(" value { "," value } ")"
+//	sum        = product { ( "+" | "-" ) product }
+//	product    = unary { ( "*" | "/" ) unary }
+//	unary      = "-" unary | field | value | "(" condition ")"
+//	value      = number [ k | m | b ] | 'string' | TRUE | FALSE
+//
+// Errors wrap models.ErrInvalidScreen and give the position of the problem.
+func Parse(src string) (*Query, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, fmt.Errorf("%w: expression is required", models.ErrInvalidScreen)
+	}
+	if len(src) > models.MaxScreenExpressionLength {
+		return nil, fmt.Errorf("%w: expression must be at most %d characters", models.ErrInvalidScreen, models.MaxScreenExpressionLength)
+	}
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	q := &Query{}
+	if t := p.peek(); t.kind != tokenEOF && !(t.kind == tokenKeyword && (t.text == "ORDER" || t.text == "LIMIT")) {
+		if q.where, err = p.condition(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("ORDER") {
+		if err := p.expect("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			pos := p.peek().pos
+			expr, err := p.sum()
+			if err != nil {
+				return nil, err
+			}
+			if expr.typ() == models.ScreenerFieldTags {
+				return nil, syntaxError(pos, "cannot order by tag")
+			}
+			term := orderTerm{expr: expr, desc: p.accept("DESC")}
+			if !term.desc {
+				p.accept("ASC")
+			}
+			q.order = append(q.order, term)
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+	if p.accept("LIMIT") {
+		t := p.next()
+		if t.kind != tokenNumber || t.num != float64(int(t.num)) || t.num < 1 || t.num > models.MaxScreenerLimit {
+			return nil, syntaxError(t.pos, "LIMIT must be a whole number from 1 to %d", models.MaxScreenerLimit)
+		}
+		q.limit = int(t.num)
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, p.unexpected("")
+	}
+	return q, nil
+}
+
+// Limit returns the expression's LIMIT, or 0 when it has none.
+func (q *Query) Limit() int {
+	return q.limit
+}
+
+// Match reports whether the row satisfies the expression's condition.
+func (q *Query) Match(row *models.ScreenerRow) bool {
+	if q.where == nil {
+		return true
+	}
+	v := q.where.eval(row)
+	return v.valid && v.b
+}
+
+// Run returns the first limit matching rows in the expression's order, and
+// how many rows matched in total. Rows without a value for an ORDER BY term
+// sort after those with one; remaining ties keep the order of rows.
+func (q *Query) Run(rows []models.ScreenerRow, limit int) ([]models.ScreenerRow, int) {
+	order := q.order
+	if len(order) == 0 {
+		order = defaultOrder
+	}
+
+	type match struct {
+		keys []value
+		row  int
+	}
+	var matches []match
+	for i := range rows {
+		if !q.Match(&rows[i]) {
+			continue
+		}
+		keys := make([]value, len(order))
+		for j, term := range order {
+			keys[j] = term.expr.eval(&rows[i])
+		}
+		matches = append(matches, match{row: i, keys: keys})
+	}
+
+	sort.SliceStable(matches, func(a, b int) bool {
+		for j, term := range order {
+			ka, kb := matches[a].keys[j], matches[b].keys[j]
+			if ka.valid != kb.valid {
+				return ka.valid
+			}
+			if !ka.valid {
+				continue
+			}
+			if c := cmpValues(ka, kb); c != 0 {
+				return (c < 0) != term.desc
+			}
+		}
+		return false
+	})
+
+	total := len(matches)
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	out := make([]models.ScreenerRow, len(matches))
+	for i, m := range matches {
+		out[i] = rows[m.row]
+	}
+	return out, total
+}
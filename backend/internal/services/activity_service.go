@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/activities"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+// activityService implements ActivityService.
+type activityService struct {
+	itemRepo   repository.ItemRepository
+	priceRepo  repository.PriceRepository
+	logger     *zap.SugaredLogger
+	activities map[string]models.Activity
+	ids        []string
+}
+
+// NewActivityService creates a new activity service over the loaded
+// activity definitions.
+func NewActivityService(
+	list []models.Activity,
+	itemRepo repository.ItemRepository,
+	priceRepo repository.PriceRepository,
+	logger *zap.SugaredLogger,
+) ActivityService {
+	s := &activityService{
+		itemRepo:   itemRepo,
+		priceRepo:  priceRepo,
+		logger:     logger,
+		activities: make(map[string]models.Activity, len(list)),
+		ids:        make([]string, 0, len(list)),
+	}
+	for _, a := range list {
+		s.activities[a.ID] = a
+		s.ids = append(s.ids, a.ID)
+	}
+	return s
+}
+
+// ListActivities returns every activity definition.
+func (s *activityService) ListActivities(_ context.Context) []models.Activity {
+	list := make([]models.Activity, 0, len(s.ids))
+	for _, id := range s.ids {
+		list = append(list, s.activities[id])
+	}
+	return list
+}
+
+// ActivityValues values every activity at current prices, highest value
+// per hour first.
+func (s *activityService) ActivityValues(ctx context.Context, mode models.PricingMode) ([]models.ActivityValue, error) {
+	list := s.ListActivities(ctx)
+	ids := make([]int, 0, len(list)*8)
+	for _, a := range list {
+		ids = append(ids, activities.ItemIDs(a)...)
+	}
+	items, prices, err := s.itemsAndPrices(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	values := make([]models.ActivityValue, 0, len(list))
+	for _, a := range list {
+		v := activities.Value(a, items, prices, mode)
+		v.ComputedAt = now
+		values = append(values, v)
+	}
+	sort.SliceStable(values, func(i, j int) bool { return values[i].ValuePerHour > values[j].ValuePerHour })
+	return values, nil
+}
+
+// ActivityValue values one activity at current prices. Returns nil if the
+// activity does not exist.
+func (s *activityService) ActivityValue(ctx context.Context, id string, mode models.PricingMode) (*models.ActivityValue, error) {
+	a, ok := s.activities[id]
+	if !ok {
+		return nil, nil
+	}
+	items, prices, err := s.itemsAndPrices(ctx, activities.ItemIDs(a))
+	if err != nil {
+		return nil, err
+	}
+	v := activities.Value(a, items, prices, mode)
+	v.ComputedAt = time.Now().UTC()
+	return &v, nil
+}
+
+// ActivityValueHistory values an activity on each day that has a 24h bucket
+// or daily rollup for any of its drops. Returns nil if the activity does not
+// exist.
+// Parameter errors wrap models.ErrInvalidActivity.
+func (s *activityService) ActivityValueHistory(
+	ctx context.Context,
+	id string,
+	params models.ActivityHistoryParams,
+) (*models.ActivityValueHistory, error) {
+	now := time.Now().UTC()
+	if params.To.IsZero() || params.To.After(now) {
+		params.To = now
+	}
+	if params.From.IsZero() {
+		params.From = params.To.Add(-models.DefaultActivityHistoryRange)
+	}
+	if params.Pricing == "" {
+		params.Pricing = models.PricingOffer
+	}
+	switch {
+	case !params.From.Before(params.To):
+		return nil, invalidActivityQuery("from must be before to")
+	case params.To.Sub(params.From) > models.MaxActivityHistoryRange:
+		return nil, invalidActivityQuery("range must span at most %d days", int(models.MaxActivityHistoryRange/(24*time.Hour)))
+	case !params.Pricing.IsValid():
+		return nil, invalidActivityQuery("pricing must be offer or instant")
+	}
+
+	a, ok := s.activities[id]
+	if !ok {
+		return nil, nil
+	}
+
+	ids := activities.ItemIDs(a)
+	itemList, err := s.itemRepo.GetByItemIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[int]models.Item, len(itemList))
+	for _, item := range itemList {
+		items[item.ItemID] = item
+	}
+
+	// 24h buckets are only rolled up into the daily table after 30 days, so
+	// recent days come from the 24h table.
+	points, err := s.priceRepo.GetDailyPointsForItems(ctx, ids, params.From, params.To)
+	if err != nil {
+		return nil, fmt.Errorf("fetch daily points: %w", err)
+	}
+	buckets, err := s.priceRepo.GetTimeseriesPointsForItems(ctx, ids, "24h", params.From.Truncate(24*time.Hour), params.To)
+	if err != nil {
+		return nil, fmt.Errorf("fetch 24h points: %w", err)
+	}
+	for _, p := range buckets {
+		points = append(points, models.PriceTimeseriesDaily{
+			Day:             p.Timestamp,
+			AvgHighPrice:    p.AvgHighPrice,
+			AvgLowPrice:     p.AvgLowPrice,
+			ItemID:          p.ItemID,
+			HighPriceVolume: p.HighPriceVolume,
+			LowPriceVolume:  p.LowPriceVolume,
+		})
+	}
+
+	daily := make(map[int]map[time.Time]models.PriceTimeseriesDaily, len(ids))
+	daySet := make(map[time.Time]struct{})
+	for _, p := range points {
+		byDay, ok := daily[p.ItemID]
+		if !ok {
+			byDay = make(map[time.Time]models.PriceTimeseriesDaily)
+			daily[p.ItemID] = byDay
+		}
+		day := time.Date(p.Day.Year(), p.Day.Month(), p.Day.Day(), 0, 0, 0, 0, time.UTC)
+		byDay[day] = p
+		daySet[day] = struct{}{}
+	}
+
+	days := make([]time.Time, 0, len(daySet))
+	for day := range daySet {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	return &models.ActivityValueHistory{
+		ID:      a.ID,
+		From:    params.From,
+		To:      params.To,
+		Pricing: params.Pricing,
+		Points:  activities.History(a, items, daily, days, params.Pricing),
+	}, nil
+}
+
+// itemsAndPrices loads the items and current prices of ids, keyed by item ID.
+func (s *activityService) itemsAndPrices(
+	ctx context.Context,
+	ids []int,
+) (map[int]models.Item, map[int]models.CurrentPrice, error) {
+	itemList, err := s.itemRepo.GetByItemIDs(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	items := make(map[int]models.Item, len(itemList))
+	for _, item := range itemList {
+		items[item.ItemID] = item
+	}
+	priceList, err := s.priceRepo.GetCurrentPrices(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	prices := make(map[int]models.CurrentPrice, len(priceList))
+	for _, p := range priceList {
+		prices[p.ItemID] = p
+	}
+	return items, prices, nil
+}
+
+func invalidActivityQuery(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", models.ErrInvalidActivity, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
+)
+
+// correlationCacheTTL keeps matrices for a fraction of the 1h bucket width.
+const correlationCacheTTL = 30 * time.Minute
+
+// GetCorrelation computes Pearson and Spearman correlations of log returns for
+// every pair of items over the window. Prices come from the 1h table for short
+// windows and from 24h buckets plus daily rollups otherwise, aligned on a
+// common grid with gaps forward-filled.
+func (s *analyticsService) GetCorrelation(ctx context.Context, params models.CorrelationParams) (*models.CorrelationResponse, error) {
+	if len(params.ItemIDs) < 2 {
+		return nil, fmt.Errorf("at least 2 item IDs are required")
+	}
+	if len(params.ItemIDs) > models.MaxCorrelationItems {
+		return nil, fmt.Errorf("maximum %d items per correlation", models.MaxCorrelationItems)
+	}
+	if params.WindowDays <= 0 {
+		params.WindowDays = models.DefaultCorrelationWindowDays
+	}
+	if params.Field == "" {
+		params.Field = models.PriceFieldMid
+	}
+
+	ids := make([]string, len(params.ItemIDs))
+	for i, id := range params.ItemIDs {
+		ids[i] = strconv.Itoa(id)
+	}
+	cacheKey := fmt.Sprintf("analytics:correlation:%s:%d:%s", strings.Join(ids, ","), params.WindowDays, params.Field)
+	var cached models.CorrelationResponse
+	if err := s.cache.GetJSON(ctx, cacheKey, &cached); err == nil {
+		return &cached, nil
+	}
+
+	timestep := "24h"
+	if params.WindowDays <= models.CorrelationHourlyMaxDays {
+		timestep = "1h"
+	}
+	step := timestepDuration(timestep)
+
+	end := time.Now().UTC()
+	start := end.Add(-time.Duration(params.WindowDays) * 24 * time.Hour)
+	grid := utils.BuildTimeGrid(start, end, step)
+
+	returns := make([][]*float64, len(params.ItemIDs))
+	for i, itemID := range params.ItemIDs {
+		points, err := s.correlationPoints(ctx, itemID, timestep, params.Field, start, end)
+		if err != nil {
+			return nil, err
+		}
+		prices := utils.ForwardFill(grid, points,
+			func(p comparePoint) time.Time { return p.Timestamp },
+			func(p comparePoint) *float64 { return p.Value },
+		)
+		returns[i] = utils.LogReturns(prices)
+	}
+
+	n := len(params.ItemIDs)
+	response := &models.CorrelationResponse{
+		GeneratedAt:     end,
+		Start:           grid[0],
+		End:             grid[len(grid)-1],
+		ItemIDs:         params.ItemIDs,
+		Pearson:         make([][]*float64, n),
+		Spearman:        make([][]*float64, n),
+		Observations:    make([][]int, n),
+		Pairs:           make([]models.CorrelationPair, 0, n*(n-1)/2),
+		Field:           string(params.Field),
+		Timestep:        timestep,
+		IntervalSeconds: int64(step / time.Second),
+		WindowDays:      params.WindowDays,
+	}
+	for i := range params.ItemIDs {
+		response.Pearson[i] = make([]*float64, n)
+		response.Spearman[i] = make([]*float64, n)
+		response.Observations[i] = make([]int, n)
+	}
+
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			x, y := utils.PairwiseComplete(returns[i], returns[j])
+			var pearson, spearman *float64
+			if len(x) >= models.CorrelationMinObservations {
+				pearson = utils.Pearson(x, y)
+				spearman = utils.Spearman(x, y)
+			}
+			response.Pearson[i][j], response.Pearson[j][i] = pearson, pearson
+			response.Spearman[i][j], response.Spearman[j][i] = spearman, spearman
+			response.Observations[i][j], response.Observations[j][i] = len(x), len(x)
+
+			if i != j {
+				response.Pairs = append(response.Pairs, models.CorrelationPair{
+					ItemA:        params.ItemIDs[i],
+					ItemB:        params.ItemIDs[j],
+					Pearson:      pearson,
+					Spearman:     spearman,
+					Observations: len(x),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(response.Pairs, func(a, b int) bool {
+		pa, pb := response.Pairs[a].Pearson, response.Pairs[b].Pearson
+		if pa == nil || pb == nil {
+			return pb == nil && pa != nil
+		}
+		return math.Abs(*pa) > math.Abs(*pb)
+	})
+
+	//nolint:errcheck // Cache write failures are non-critical
+	_ = s.cache.SetJSON(ctx, cacheKey, response, correlationCacheTTL)
+
+	return response, nil
+}
+
+// correlationPoints loads one item's observations of field between start and
+// end. Daily rollups are merged in for the 24h timestep because 24h buckets
+// are only retained for 30 days.
+func (s *analyticsService) correlationPoints(
+	ctx context.Context,
+	itemID int,
+	timestep string,
+	field models.PriceField,
+	start, end time.Time,
+) ([]comparePoint, error) {
+	params := models.PriceHistoryParams{ItemID: itemID, StartTime: &start, EndTime: &end}
+
+	byTime := make(map[time.Time]*float64)
+	if timestep == "24h" {
+		daily, err := s.priceRepo.GetDailyPoints(ctx, itemID, params)
+		if err != nil {
+			return nil, fmt.Errorf("fetch daily points: %w", err)
+		}
+		for _, p := range daily {
+			day := time.Date(p.Day.Year(), p.Day.Month(), p.Day.Day(), 0, 0, 0, 0, time.UTC)
+			byTime[day] = field.Value(p.AvgHighPrice, p.AvgLowPrice)
+		}
+	}
+
+	points, err := s.priceRepo.GetTimeseriesPoints(ctx, itemID, timestep, params)
+	if err != nil {
+		return nil, fmt.Errorf("fetch timeseries: %w", err)
+	}
+	for _, p := range points {
+		if v := field.Value(p.AvgHighPrice, p.AvgLowPrice); v != nil {
+			byTime[p.Timestamp.UTC()] = v
+		}
+	}
+
+	result := make([]comparePoint, 0, len(byTime))
+	for ts, v := range byTime {
+		result = append(result, comparePoint{Timestamp: ts, Value: v})
+	}
+	return result, nil
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/guavi/osrs-ge-tracker/internal/forecast"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
+)
+
+// forecastCacheTTL roughly matches how often a new hourly bucket can change the input.
+const forecastCacheTTL = 5 * time.Minute
+
+// GetForecast predicts the next params.Horizon hourly values of an item from
+// its stored 5m/1h history, with 95% prediction intervals and the rolling-origin
+// backtest error of every candidate model. Returns an error wrapping
+// forecast.ErrInsufficientData when the history is too short.
+func (s *analyticsService) GetForecast(ctx context.Context, params models.ForecastParams) (*models.ForecastResponse, error) {
+	if params.Horizon <= 0 {
+		params.Horizon = models.DefaultForecastHorizon
+	}
+	if params.Field == "" {
+		params.Field = models.PriceFieldMid
+	}
+	if params.Model == "" {
+		params.Model = models.ForecastModelAuto
+	}
+
+	cacheKey := fmt.Sprintf("analytics:forecast:%d:%s:%s:%d", params.ItemID, params.Field, params.Model, params.Horizon)
+	var cached models.ForecastResponse
+	if err := s.cache.GetJSON(ctx, cacheKey, &cached); err == nil {
+		return &cached, nil
+	}
+
+	now := time.Now().UTC()
+	since := now.Add(-models.ForecastLookbackDays * 24 * time.Hour).Truncate(time.Hour)
+	points, err := s.priceRepo.GetHourlySeries(ctx, params.ItemID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	series, lastTimestamp := hourlyFieldSeries(points, params.Field)
+	if len(series) < models.ForecastMinObservations {
+		return nil, fmt.Errorf("forecast item %d: %w", params.ItemID, forecast.ErrInsufficientData)
+	}
+
+	// The seasonal-naive baseline comes first so it wins ties in auto mode.
+	candidates := []forecast.Model{
+		forecast.SeasonalNaive{Period: models.ForecastSeasonalPeriod},
+		forecast.HoltWinters{Period: models.ForecastSeasonalPeriod},
+	}
+
+	response := &models.ForecastResponse{
+		ItemID:          params.ItemID,
+		Field:           string(params.Field),
+		Horizon:         params.Horizon,
+		IntervalSeconds: int64(time.Hour / time.Second),
+		GeneratedAt:     now,
+		LastTimestamp:   lastTimestamp,
+		LastValue:       series[len(series)-1],
+		Observations:    len(series),
+		Backtests:       make([]models.ForecastAccuracy, 0, len(candidates)),
+	}
+
+	var chosen forecast.Model
+	bestMAE := 0.0
+	for _, candidate := range candidates {
+		acc, err := forecast.Backtest(candidate, series, params.Horizon, models.ForecastBacktestFolds)
+		if err != nil {
+			continue
+		}
+		response.Backtests = append(response.Backtests, models.ForecastAccuracy{
+			Model: candidate.Name(),
+			MAE:   acc.MAE,
+			RMSE:  acc.RMSE,
+			MAPE:  acc.MAPE,
+			Folds: acc.Folds,
+		})
+
+		switch {
+		case params.Model != models.ForecastModelAuto:
+			if candidate.Name() == string(params.Model) {
+				chosen = candidate
+			}
+		case chosen == nil || acc.MAE < bestMAE:
+			chosen, bestMAE = candidate, acc.MAE
+		}
+	}
+	if chosen == nil {
+		for _, candidate := range candidates {
+			if params.Model == models.ForecastModelAuto || candidate.Name() == string(params.Model) {
+				chosen = candidate
+				break
+			}
+		}
+	}
+
+	result, err := chosen.Forecast(series, params.Horizon)
+	if err != nil {
+		return nil, fmt.Errorf("forecast item %d with %s: %w", params.ItemID, chosen.Name(), err)
+	}
+
+	response.Model = chosen.Name()
+	response.Parameters = result.Params
+	response.Points = make([]models.ForecastPoint, len(result.Values))
+	for i, v := range result.Values {
+		response.Points[i] = models.ForecastPoint{
+			Timestamp: lastTimestamp.Add(time.Duration(i+1) * time.Hour),
+			Value:     v,
+			Lower:     result.Lower[i],
+			Upper:     result.Upper[i],
+		}
+	}
+
+	//nolint:errcheck // Cache write failures are non-critical
+	_ = s.cache.SetJSON(ctx, cacheKey, response, forecastCacheTTL)
+
+	return response, nil
+}
+
+// hourlyFieldSeries aligns hourly points on a gap-free hourly grid,
+// forward-filling missing hours, and drops hours before the first value.
+// It returns the series and the timestamp of its last value.
+func hourlyFieldSeries(points []models.HourlyPricePoint, field models.PriceField) ([]float64, time.Time) {
+	if len(points) == 0 {
+		return nil, time.Time{}
+	}
+
+	grid := utils.BuildTimeGrid(points[0].Timestamp, points[len(points)-1].Timestamp, time.Hour)
+	filled := utils.ForwardFill(grid, points,
+		func(p models.HourlyPricePoint) time.Time { return p.Timestamp },
+		func(p models.HourlyPricePoint) *float64 { return p.FieldValue(field) },
+	)
+
+	series := make([]float64, 0, len(filled))
+	for _, v := range filled {
+		if v != nil {
+			series = append(series, *v)
+		}
+	}
+	return series, grid[len(grid)-1]
+}
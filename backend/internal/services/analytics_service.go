@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+const (
+	// seasonalityCacheTTL outlives the refresh job interval so profiles never go cold.
+	seasonalityCacheTTL = 12 * time.Hour
+	// seasonalityTrackTTL is how long a profile keeps being refreshed after its last request.
+	seasonalityTrackTTL = 7 * 24 * time.Hour
+	// seasonalityMinDayHours is the number of hours a UTC day needs before it is used
+	// as the baseline for relative prices.
+	seasonalityMinDayHours = 12
+	// seasonalityWeekWindow is the half-width, in days, of the centred moving average
+	// that day-of-week values are compared against.
+	seasonalityWeekWindow = 3
+	// z95 is the two-sided 95% normal quantile used for confidence intervals.
+	z95 = 1.96
+)
+
+type seasonalityKey struct {
+	itemID       int
+	lookbackDays int
+}
+
+// analyticsService implements AnalyticsService.
+type analyticsService struct {
+	priceRepo repository.PriceRepository
+	cache     CacheService
+	logger    *zap.SugaredLogger
+	tracked   map[seasonalityKey]time.Time
+	mu        sync.Mutex
+}
+
+// NewAnalyticsService creates a new analytics service.
+func NewAnalyticsService(
+	priceRepo repository.PriceRepository,
+	cache CacheService,
+	logger *zap.SugaredLogger,
+) AnalyticsService {
+	return &analyticsService{
+		priceRepo: priceRepo,
+		cache:     cache,
+		logger:    logger,
+		tracked:   make(map[seasonalityKey]time.Time),
+	}
+}
+
+func seasonalityCacheKey(itemID, lookbackDays int) string {
+	return fmt.Sprintf("analytics:seasonality:%d:%d", itemID, lookbackDays)
+}
+
+// GetSeasonality returns the hour-of-day and day-of-week profile of an item,
+// served from cache unless params.Refresh is set. Profiles with observations
+// are kept warm by RefreshSeasonality; IDs without price history are not, so
+// unknown items cannot grow the refresh job.
+func (s *analyticsService) GetSeasonality(ctx context.Context, params models.SeasonalityParams) (*models.SeasonalityProfile, error) {
+	if params.LookbackDays <= 0 {
+		params.LookbackDays = models.DefaultSeasonalityLookbackDays
+	}
+	if !models.IsSeasonalityLookback(params.LookbackDays) {
+		return nil, fmt.Errorf("unsupported seasonality lookback of %d days", params.LookbackDays)
+	}
+	key := seasonalityKey{itemID: params.ItemID, lookbackDays: params.LookbackDays}
+
+	cacheKey := seasonalityCacheKey(params.ItemID, params.LookbackDays)
+	if !params.Refresh {
+		var cached models.SeasonalityProfile
+		if err := s.cache.GetJSON(ctx, cacheKey, &cached); err == nil {
+			if cached.Observations > 0 {
+				s.track(key)
+			}
+			return &cached, nil
+		}
+	}
+
+	profile, err := s.computeSeasonality(ctx, key, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if profile.Observations > 0 {
+		s.track(key)
+	}
+
+	//nolint:errcheck // Cache write failures are non-critical
+	_ = s.cache.SetJSON(ctx, cacheKey, profile, seasonalityCacheTTL)
+
+	return profile, nil
+}
+
+// track marks a profile as requested now. Once MaxTrackedSeasonalityProfiles
+// are tracked, a new profile replaces the least recently requested one.
+func (s *analyticsService) track(key seasonalityKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tracked[key]; !ok && len(s.tracked) >= models.MaxTrackedSeasonalityProfiles {
+		var oldest seasonalityKey
+		var oldestAt time.Time
+		for k, at := range s.tracked {
+			if oldestAt.IsZero() || at.Before(oldestAt) {
+				oldest, oldestAt = k, at
+			}
+		}
+		delete(s.tracked, oldest)
+	}
+	s.tracked[key] = time.Now()
+}
+
+// RefreshSeasonality recomputes and re-caches every profile requested within
+// seasonalityTrackTTL, and forgets the rest. It returns how many were refreshed.
+func (s *analyticsService) RefreshSeasonality(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	keys := make([]seasonalityKey, 0, len(s.tracked))
+	for key, lastRequested := range s.tracked {
+		if now.Sub(lastRequested) > seasonalityTrackTTL {
+			delete(s.tracked, key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	s.mu.Unlock()
+
+	refreshed := 0
+	var errs []error
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+		profile, err := s.computeSeasonality(ctx, key, now.UTC())
+		if err != nil {
+			s.logger.Warnw("Failed to refresh seasonality profile",
+				"itemID", key.itemID, "lookbackDays", key.lookbackDays, "error", err)
+			errs = append(errs, err)
+			continue
+		}
+		//nolint:errcheck // Cache write failures are non-critical
+		_ = s.cache.SetJSON(ctx, seasonalityCacheKey(key.itemID, key.lookbackDays), profile, seasonalityCacheTTL)
+		refreshed++
+	}
+
+	return refreshed, errors.Join(errs...)
+}
+
+func (s *analyticsService) computeSeasonality(ctx context.Context, key seasonalityKey, now time.Time) (*models.SeasonalityProfile, error) {
+	since := now.Add(-time.Duration(key.lookbackDays) * 24 * time.Hour).Truncate(time.Hour)
+	points, err := s.priceRepo.GetHourlySeries(ctx, key.itemID, since)
+	if err != nil {
+		return nil, err
+	}
+	return buildSeasonalityProfile(points, key.itemID, key.lookbackDays, now), nil
+}
+
+type seasonalityDay struct {
+	start     time.Time
+	priceSum  float64
+	volumeSum float64
+	hours     int
+}
+
+func (d *seasonalityDay) meanPrice() float64  { return d.priceSum / float64(d.hours) }
+func (d *seasonalityDay) meanVolume() float64 { return d.volumeSum / float64(d.hours) }
+
+// buildSeasonalityProfile turns an hourly series into hour-of-day and
+// day-of-week profiles. Hourly values are relative to their UTC day's mean, so
+// long-term trends cancel out; daily values are relative to a centred 7-day
+// moving average of daily means.
+func buildSeasonalityProfile(points []models.HourlyPricePoint, itemID, lookbackDays int, now time.Time) *models.SeasonalityProfile {
+	profile := &models.SeasonalityProfile{
+		ItemID:       itemID,
+		LookbackDays: lookbackDays,
+		GeneratedAt:  now,
+	}
+	if len(points) > 0 {
+		first := points[0].Timestamp
+		last := points[len(points)-1].Timestamp
+		profile.FirstTimestamp = &first
+		profile.LastTimestamp = &last
+		profile.Coverage = math.Min(1, float64(len(points))/float64(lookbackDays*24))
+	}
+
+	// Group hours by UTC day.
+	dayIndex := make(map[time.Time]*seasonalityDay)
+	for _, p := range points {
+		start := p.Timestamp.Truncate(24 * time.Hour)
+		day, ok := dayIndex[start]
+		if !ok {
+			day = &seasonalityDay{start: start}
+			dayIndex[start] = day
+		}
+		day.priceSum += p.MidPrice
+		day.volumeSum += float64(p.Volume)
+		day.hours++
+	}
+
+	hourPrices := make([][]float64, 24)
+	hourVolumes := make([][]float64, 24)
+	for _, p := range points {
+		day := dayIndex[p.Timestamp.Truncate(24*time.Hour)]
+		if day.hours < seasonalityMinDayHours || day.meanPrice() <= 0 {
+			continue
+		}
+		hour := p.Timestamp.Hour()
+		hourPrices[hour] = append(hourPrices[hour], p.MidPrice/day.meanPrice())
+		if meanVolume := day.meanVolume(); meanVolume > 0 {
+			hourVolumes[hour] = append(hourVolumes[hour], float64(p.Volume)/meanVolume)
+		}
+		profile.Observations++
+	}
+	profile.HourOfDay = summarizeSeasonality(hourPrices, hourVolumes)
+
+	days := make([]*seasonalityDay, 0, len(dayIndex))
+	for _, day := range dayIndex {
+		if day.hours >= seasonalityMinDayHours && day.meanPrice() > 0 {
+			days = append(days, day)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].start.Before(days[j].start) })
+	profile.Days = len(days)
+
+	weekdayPrices := make([][]float64, 7)
+	weekdayVolumes := make([][]float64, 7)
+	for _, day := range days {
+		var priceSum, volumeSum float64
+		n := 0
+		for _, other := range days {
+			if math.Abs(other.start.Sub(day.start).Hours()) <= float64(seasonalityWeekWindow*24) {
+				priceSum += other.meanPrice()
+				volumeSum += other.meanVolume()
+				n++
+			}
+		}
+		// Without most of a week around it, a day cannot be told apart from the trend.
+		if n < seasonalityWeekWindow+2 {
+			continue
+		}
+		weekday := int(day.start.Weekday())
+		weekdayPrices[weekday] = append(weekdayPrices[weekday], day.meanPrice()/(priceSum/float64(n)))
+		if volumeSum > 0 {
+			weekdayVolumes[weekday] = append(weekdayVolumes[weekday], day.meanVolume()/(volumeSum/float64(n)))
+		}
+	}
+	profile.DayOfWeek = summarizeSeasonality(weekdayPrices, weekdayVolumes)
+
+	return profile
+}
+
+// summarizeSeasonality reduces per-bucket relative values to means with
+// standard errors and 95% confidence intervals, plus the share of variance
+// the buckets explain (eta squared).
+func summarizeSeasonality(prices, volumes [][]float64) models.SeasonalityPattern {
+	pattern := models.SeasonalityPattern{Buckets: make([]models.SeasonalityBucket, len(prices))}
+
+	var total, count float64
+	for _, values := range prices {
+		for _, v := range values {
+			total += v
+			count++
+		}
+	}
+
+	var between, within float64
+	for idx, values := range prices {
+		bucket := models.SeasonalityBucket{Index: idx, Samples: len(values)}
+		if len(values) > 0 {
+			mean := meanOf(values)
+			bucket.RelativePrice = &mean
+
+			var ss float64
+			for _, v := range values {
+				ss += (v - mean) * (v - mean)
+			}
+			within += ss
+			between += float64(len(values)) * (mean - total/count) * (mean - total/count)
+
+			if len(values) > 1 {
+				stdErr := math.Sqrt(ss/float64(len(values)-1)) / math.Sqrt(float64(len(values)))
+				low, high := mean-z95*stdErr, mean+z95*stdErr
+				bucket.PriceStdErr = &stdErr
+				bucket.PriceCILow = &low
+				bucket.PriceCIHigh = &high
+			}
+
+			if pattern.PeakIndex == nil || mean > *pattern.Buckets[*pattern.PeakIndex].RelativePrice {
+				peak := idx
+				pattern.PeakIndex = &peak
+			}
+			if pattern.TroughIndex == nil || mean < *pattern.Buckets[*pattern.TroughIndex].RelativePrice {
+				trough := idx
+				pattern.TroughIndex = &trough
+			}
+		}
+		if len(volumes[idx]) > 0 {
+			volume := meanOf(volumes[idx])
+			bucket.RelativeVolume = &volume
+		}
+		pattern.Buckets[idx] = bucket
+	}
+
+	if count > 1 && between+within > 0 {
+		strength := between / (between + within)
+		pattern.Strength = &strength
+	}
+	return pattern
+}
+
+func meanOf(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/arbitrage"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+// arbitrageCacheTTL outlives several price syncs, which each refresh the
+// arbitrage reports.
+const arbitrageCacheTTL = 5 * time.Minute
+
+// arbitrageService implements ArbitrageService.
+type arbitrageService struct {
+	itemSetRepo repository.ItemSetRepository
+	itemRepo    repository.ItemRepository
+	priceRepo   repository.PriceRepository
+	cache       CacheService
+	logger      *zap.SugaredLogger
+}
+
+// NewArbitrageService creates a new arbitrage service.
+func NewArbitrageService(
+	itemSetRepo repository.ItemSetRepository,
+	itemRepo repository.ItemRepository,
+	priceRepo repository.PriceRepository,
+	cache CacheService,
+	logger *zap.SugaredLogger,
+) ArbitrageService {
+	return &arbitrageService{
+		itemSetRepo: itemSetRepo,
+		itemRepo:    itemRepo,
+		priceRepo:   priceRepo,
+		cache:       cache,
+		logger:      logger,
+	}
+}
+
+func setArbitrageCacheKey(mode models.PricingMode) string {
+	return fmt.Sprintf("arbitrage:sets:%s", mode)
+}
+
+func doseArbitrageCacheKey(mode models.PricingMode) string {
+	return fmt.Sprintf("arbitrage:doses:%s", mode)
+}
+
+// ListSets returns the set registry: the shipped sets with admin edits applied.
+func (s *arbitrageService) ListSets(ctx context.Context) ([]models.ItemSet, error) {
+	defaults, err := arbitrage.DefaultSets()
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := s.itemSetRepo.ListOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sets, err := arbitrage.MergeSets(defaults, overrides)
+	if err != nil {
+		// A bad row only drops that set; the rest of the registry is usable.
+		s.logger.Warnw("Skipped invalid item set overrides", "error", err)
+	}
+	return sets, nil
+}
+
+// SaveSet adds a set to the registry or replaces the definition with the same
+// set item ID. Validation errors wrap models.ErrInvalidItemSet.
+func (s *arbitrageService) SaveSet(ctx context.Context, set models.ItemSet) (*models.ItemSet, error) {
+	if err := arbitrage.ValidateSet(&set); err != nil {
+		return nil, err
+	}
+	components, err := json.Marshal(set.Components)
+	if err != nil {
+		return nil, fmt.Errorf("encode item set components: %w", err)
+	}
+	override := models.ItemSetOverride{SetItemID: set.SetItemID, Name: set.Name, Components: components}
+	if err := s.itemSetRepo.UpsertOverride(ctx, &override); err != nil {
+		return nil, err
+	}
+	s.invalidateSetArbitrage(ctx)
+
+	set.Source = models.ItemSetSourceCustom
+	return &set, nil
+}
+
+// DeleteSet removes a set from the registry and reports whether it was in
+// it. Shipped sets are hidden rather than deleted so they stay removed
+// across upgrades.
+func (s *arbitrageService) DeleteSet(ctx context.Context, setItemID int) (bool, error) {
+	defaults, err := arbitrage.DefaultSets()
+	if err != nil {
+		return false, err
+	}
+	builtin := slices.ContainsFunc(defaults, func(set models.ItemSet) bool { return set.SetItemID == setItemID })
+
+	var deleted bool
+	if builtin {
+		sets, err := s.ListSets(ctx)
+		if err != nil {
+			return false, err
+		}
+		if !slices.ContainsFunc(sets, func(set models.ItemSet) bool { return set.SetItemID == setItemID }) {
+			return false, nil
+		}
+		override := models.ItemSetOverride{SetItemID: setItemID, Name: "removed", Components: []byte("[]"), Removed: true}
+		if err := s.itemSetRepo.UpsertOverride(ctx, &override); err != nil {
+			return false, err
+		}
+		deleted = true
+	} else if deleted, err = s.itemSetRepo.DeleteOverride(ctx, setItemID); err != nil {
+		return false, err
+	}
+
+	if deleted {
+		s.invalidateSetArbitrage(ctx)
+	}
+	return deleted, nil
+}
+
+// SetArbitrage returns every set compared with its pieces at current prices,
+// served from the report cached after the last price sync when there is one.
+func (s *arbitrageService) SetArbitrage(ctx context.Context, mode models.PricingMode) (*models.SetArbitrageReport, error) {
+	var cached models.SetArbitrageReport
+	if err := s.cache.GetJSON(ctx, setArbitrageCacheKey(mode), &cached); err == nil {
+		return &cached, nil
+	}
+
+	reports, err := s.computeSetArbitrage(ctx, []models.PricingMode{mode})
+	if err != nil {
+		return nil, err
+	}
+	return &reports[0], nil
+}
+
+// RefreshSetArbitrage recomputes and caches the set report for every
+// pricing mode. Called after each price sync.
+func (s *arbitrageService) RefreshSetArbitrage(ctx context.Context) error {
+	_, err := s.computeSetArbitrage(ctx, []models.PricingMode{models.PricingOffer, models.PricingInstant})
+	return err
+}
+
+func (s *arbitrageService) computeSetArbitrage(ctx context.Context, modes []models.PricingMode) ([]models.SetArbitrageReport, error) {
+	sets, err := s.ListSets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(sets)*5)
+	for _, set := range sets {
+		ids = append(ids, set.SetItemID)
+		ids = append(ids, componentIDs(set)...)
+	}
+	items, prices, err := s.itemsAndPrices(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	reports := make([]models.SetArbitrageReport, 0, len(modes))
+	for _, mode := range modes {
+		results, unpriced := arbitrage.EvaluateSets(sets, items, prices, mode)
+		report := models.SetArbitrageReport{ComputedAt: now, Pricing: mode, Sets: results, Unpriced: unpriced}
+		//nolint:errcheck // Cache write failures are non-critical
+		_ = s.cache.SetJSON(ctx, setArbitrageCacheKey(mode), report, arbitrageCacheTTL)
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// ListDoseGroups returns the potion dose group registry.
+func (s *arbitrageService) ListDoseGroups(_ context.Context) ([]models.DoseGroup, error) {
+	return arbitrage.DefaultDoseGroups()
+}
+
+// DoseArbitrage returns every potion's decanting trades at current prices,
+// served from the report cached after the last price sync when there is one.
+func (s *arbitrageService) DoseArbitrage(ctx context.Context, mode models.PricingMode) (*models.DoseArbitrageReport, error) {
+	var cached models.DoseArbitrageReport
+	if err := s.cache.GetJSON(ctx, doseArbitrageCacheKey(mode), &cached); err == nil {
+		return &cached, nil
+	}
+
+	reports, err := s.computeDoseArbitrage(ctx, []models.PricingMode{mode})
+	if err != nil {
+		return nil, err
+	}
+	return &reports[0], nil
+}
+
+// RefreshDoseArbitrage recomputes and caches the decanting report for every
+// pricing mode. Called after each price sync.
+func (s *arbitrageService) RefreshDoseArbitrage(ctx context.Context) error {
+	_, err := s.computeDoseArbitrage(ctx, []models.PricingMode{models.PricingOffer, models.PricingInstant})
+	return err
+}
+
+func (s *arbitrageService) computeDoseArbitrage(ctx context.Context, modes []models.PricingMode) ([]models.DoseArbitrageReport, error) {
+	groups, err := arbitrage.DefaultDoseGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(groups)*4)
+	for _, g := range groups {
+		for _, v := range g.Variants {
+			ids = append(ids, v.ItemID)
+		}
+	}
+	items, prices, err := s.itemsAndPrices(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	reports := make([]models.DoseArbitrageReport, 0, len(modes))
+	for _, mode := range modes {
+		results, unpriced := arbitrage.EvaluateDoseGroups(groups, items, prices, mode)
+		report := models.DoseArbitrageReport{ComputedAt: now, Pricing: mode, Groups: results, Unpriced: unpriced}
+		//nolint:errcheck // Cache write failures are non-critical
+		_ = s.cache.SetJSON(ctx, doseArbitrageCacheKey(mode), report, arbitrageCacheTTL)
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// itemsAndPrices loads the items and current prices of ids, keyed by item
+// ID. Duplicate IDs are looked up once.
+func (s *arbitrageService) itemsAndPrices(
+	ctx context.Context,
+	ids []int,
+) (map[int]models.Item, map[int]models.CurrentPrice, error) {
+	seen := make(map[int]struct{}, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if _, dup := seen[id]; !dup {
+			seen[id] = struct{}{}
+			unique = append(unique, id)
+		}
+	}
+
+	itemList, err := s.itemRepo.GetByItemIDs(ctx, unique)
+	if err != nil {
+		return nil, nil, err
+	}
+	items := make(map[int]models.Item, len(itemList))
+	for _, item := range itemList {
+		items[item.ItemID] = item
+	}
+	priceList, err := s.priceRepo.GetCurrentPrices(ctx, unique)
+	if err != nil {
+		return nil, nil, err
+	}
+	prices := make(map[int]models.CurrentPrice, len(priceList))
+	for _, p := range priceList {
+		prices[p.ItemID] = p
+	}
+	return items, prices, nil
+}
+
+// invalidateSetArbitrage drops cached reports after a registry edit so the
+// next request sees it.
+func (s *arbitrageService) invalidateSetArbitrage(ctx context.Context) {
+	if err := s.cache.DeletePattern(ctx, "arbitrage:sets:*"); err != nil {
+		s.logger.Warnw("Failed to invalidate set arbitrage cache", "error", err)
+	}
+}
+
+func componentIDs(set models.ItemSet) []int {
+	ids := make([]int, 0, len(set.Components))
+	for _, c := range set.Components {
+		ids = append(ids, c.ItemID)
+	}
+	return ids
+}
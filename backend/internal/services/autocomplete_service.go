@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/autocomplete"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+// autocompletePopularityWindow is how far back trade volume counts toward
+// an item's popularity.
+const autocompletePopularityWindow = 24 * time.Hour
+
+// autocompletePrice is the last known instant buy and sell price of an item.
+type autocompletePrice struct {
+	high *int64
+	low  *int64
+}
+
+// autocompleteService implements AutocompleteService. Lookups read an
+// immutable index and a price map, so they never touch the database.
+type autocompleteService struct {
+	index     atomic.Pointer[autocomplete.Index]
+	prices    map[int]autocompletePrice
+	itemRepo  repository.ItemRepository
+	aliasRepo repository.ItemAliasRepository
+	priceRepo repository.PriceRepository
+	logger    *zap.SugaredLogger
+	pricesMu  sync.RWMutex
+}
+
+// NewAutocompleteService creates a new autocomplete service. It answers
+// nothing until the first Rebuild.
+func NewAutocompleteService(
+	itemRepo repository.ItemRepository,
+	aliasRepo repository.ItemAliasRepository,
+	priceRepo repository.PriceRepository,
+	logger *zap.SugaredLogger,
+) AutocompleteService {
+	return &autocompleteService{
+		prices:    make(map[int]autocompletePrice),
+		itemRepo:  itemRepo,
+		aliasRepo: aliasRepo,
+		priceRepo: priceRepo,
+		logger:    logger,
+	}
+}
+
+// Rebuild reloads items, approved aliases, trade volumes and current prices
+// and swaps in a new index. Lookups keep using the old index meanwhile.
+func (s *autocompleteService) Rebuild(ctx context.Context) error {
+	start := time.Now()
+
+	items, _, err := s.itemRepo.GetAll(ctx, models.ItemListParams{})
+	if err != nil {
+		return err
+	}
+	approved, err := s.aliasRepo.ListApproved(ctx)
+	if err != nil {
+		return err
+	}
+	volumes, err := s.priceRepo.GetTradeVolumes(ctx, time.Now().Add(-autocompletePopularityWindow))
+	if err != nil {
+		return err
+	}
+	current, err := s.priceRepo.GetAllCurrentPrices(ctx)
+	if err != nil {
+		return err
+	}
+
+	aliasesByItem := make(map[int][]string)
+	for _, alias := range approved {
+		aliasesByItem[alias.ItemID] = append(aliasesByItem[alias.ItemID], alias.Alias)
+	}
+	entries := make([]autocomplete.Entry, len(items))
+	for i, item := range items {
+		entries[i] = autocomplete.Entry{
+			ItemID:     item.ItemID,
+			Name:       item.Name,
+			IconURL:    normalizeItemIconURL(item.ItemID, item.IconURL),
+			Aliases:    aliasesByItem[item.ItemID],
+			Popularity: volumes[item.ItemID],
+		}
+	}
+	index := autocomplete.NewIndex(entries)
+
+	prices := make(map[int]autocompletePrice, len(current))
+	for _, p := range current {
+		prices[p.ItemID] = autocompletePrice{high: p.HighPrice, low: p.LowPrice}
+	}
+	s.pricesMu.Lock()
+	s.prices = prices
+	s.pricesMu.Unlock()
+	s.index.Store(index)
+
+	s.logger.Infow("Rebuilt autocomplete index",
+		"items", index.Len(),
+		"aliases", len(approved),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// UpdatePrices applies synced price updates to the prices returned with
+// completions.
+func (s *autocompleteService) UpdatePrices(updates []models.BulkPriceUpdate) {
+	s.pricesMu.Lock()
+	defer s.pricesMu.Unlock()
+	for _, u := range updates {
+		price := s.prices[u.ItemID]
+		if u.HighPrice != nil {
+			price.high = u.HighPrice
+		}
+		if u.LowPrice != nil {
+			price.low = u.LowPrice
+		}
+		s.prices[u.ItemID] = price
+	}
+}
+
+// Complete returns up to limit items whose name words or aliases start with
+// query, or nil before the first Rebuild.
+func (s *autocompleteService) Complete(query string, limit int) []models.AutocompleteItem {
+	index := s.index.Load()
+	if index == nil {
+		return nil
+	}
+
+	entries := index.Complete(query, limit)
+	items := make([]models.AutocompleteItem, len(entries))
+	s.pricesMu.RLock()
+	defer s.pricesMu.RUnlock()
+	for i, entry := range entries {
+		price := s.prices[entry.ItemID]
+		items[i] = models.AutocompleteItem{
+			ItemID:    entry.ItemID,
+			Name:      entry.Name,
+			IconURL:   entry.IconURL,
+			HighPrice: price.high,
+			LowPrice:  price.low,
+		}
+	}
+	return items
+}
+
+// Ready reports whether the index has been built.
+func (s *autocompleteService) Ready() bool {
+	return s.index.Load() != nil
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/backtest"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+const (
+	// backtestWorkers caps how many backtests simulate at once.
+	backtestWorkers = 2
+	// backtestTimeout bounds a single job, including loading its buckets.
+	backtestTimeout = 2 * time.Minute
+	// backtestInterruptedMessage is recorded on jobs a restart or shutdown cut short.
+	backtestInterruptedMessage = "interrupted by server shutdown"
+)
+
+// backtestService implements BacktestService.
+type backtestService struct {
+	backtestRepo repository.BacktestRepository
+	priceRepo    repository.PriceRepository
+	itemRepo     repository.ItemRepository
+	logger       *zap.SugaredLogger
+	ctx          context.Context
+	cancel       context.CancelFunc
+	slots        chan struct{}
+	wg           sync.WaitGroup
+	mu           sync.Mutex
+	unfinished   int
+	closed       bool
+}
+
+// NewBacktestService creates a new backtest service. Jobs run in background
+// goroutines until Shutdown is called.
+func NewBacktestService(
+	backtestRepo repository.BacktestRepository,
+	priceRepo repository.PriceRepository,
+	itemRepo repository.ItemRepository,
+	logger *zap.SugaredLogger,
+) BacktestService {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &backtestService{
+		backtestRepo: backtestRepo,
+		priceRepo:    priceRepo,
+		itemRepo:     itemRepo,
+		logger:       logger,
+		ctx:          ctx,
+		cancel:       cancel,
+		slots:        make(chan struct{}, backtestWorkers),
+	}
+}
+
+// Submit validates a strategy, stores a pending job and starts it in the
+// background. Validation errors wrap backtest.ErrInvalidStrategy; a full
+// queue returns models.ErrBacktestQueueFull.
+func (s *backtestService) Submit(ctx context.Context, strategy models.BacktestStrategy) (*models.Backtest, error) {
+	normalized, err := backtest.Normalize(strategy, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("encode strategy: %w", err)
+	}
+
+	job := &models.Backtest{
+		ID:        uuid.New().String(),
+		Name:      normalized.Name,
+		Status:    models.BacktestStatusPending,
+		Strategy:  data,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	// Reserve the job's slot before inserting, so the insert does not hold
+	// the lock.
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, models.ErrBacktestShuttingDown
+	}
+	if s.unfinished >= models.MaxUnfinishedBacktests {
+		s.mu.Unlock()
+		return nil, models.ErrBacktestQueueFull
+	}
+	s.unfinished++
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	if err := s.backtestRepo.Create(ctx, job); err != nil {
+		s.release()
+		return nil, err
+	}
+	go s.run(job.ID, normalized)
+
+	return job, nil
+}
+
+// Get returns a backtest with its result, or nil when it does not exist.
+func (s *backtestService) Get(ctx context.Context, id string) (*models.Backtest, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, nil
+	}
+	return s.backtestRepo.GetByID(ctx, id)
+}
+
+// List returns the most recent backtests without their results.
+func (s *backtestService) List(ctx context.Context, limit int) ([]models.Backtest, error) {
+	return s.backtestRepo.List(ctx, limit)
+}
+
+// FailInterrupted marks jobs left unfinished by a previous process as failed.
+// Call it once at startup, before accepting submissions.
+func (s *backtestService) FailInterrupted(ctx context.Context) (int64, error) {
+	return s.backtestRepo.FailUnfinished(ctx, backtestInterruptedMessage)
+}
+
+// Shutdown cancels running jobs and waits until they have recorded their
+// outcome or ctx expires.
+func (s *backtestService) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	s.cancel()
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run executes one job once a worker slot is free and stores its outcome.
+func (s *backtestService) run(id string, strategy models.BacktestStrategy) {
+	defer s.release()
+
+	// Status updates must land even when the job itself was cancelled.
+	storeCtx := context.WithoutCancel(s.ctx)
+	job := &models.Backtest{ID: id}
+
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-s.ctx.Done():
+		s.finish(storeCtx, job, nil, errors.New(backtestInterruptedMessage))
+		return
+	}
+
+	started := time.Now().UTC()
+	job.Status = models.BacktestStatusRunning
+	job.StartedAt = &started
+	if err := s.backtestRepo.Update(storeCtx, job); err != nil {
+		s.logger.Warnw("Failed to mark backtest running", "id", id, "error", err)
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, backtestTimeout)
+	defer cancel()
+
+	result, err := s.execute(ctx, strategy)
+	if err != nil && s.ctx.Err() != nil {
+		err = errors.New(backtestInterruptedMessage)
+	}
+	s.finish(storeCtx, job, result, err)
+}
+
+// release frees a slot reserved by Submit.
+func (s *backtestService) release() {
+	s.mu.Lock()
+	s.unfinished--
+	s.mu.Unlock()
+	s.wg.Done()
+}
+
+// execute loads the strategy's buckets and buy limits and simulates it.
+func (s *backtestService) execute(ctx context.Context, strategy models.BacktestStrategy) (*models.BacktestResult, error) {
+	bars := make(map[int][]models.PriceTimeseriesPoint, len(strategy.ItemIDs))
+	buyLimits := make(map[int]int, len(strategy.ItemIDs))
+
+	for _, itemID := range strategy.ItemIDs {
+		points, err := s.priceRepo.GetTimeseriesPoints(ctx, itemID, strategy.Timestep, models.PriceHistoryParams{
+			ItemID:    itemID,
+			StartTime: &strategy.Start,
+			EndTime:   &strategy.End,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("load %s buckets for item %d: %w", strategy.Timestep, itemID, err)
+		}
+		bars[itemID] = points
+
+		item, err := s.itemRepo.GetByItemID(ctx, itemID)
+		if err != nil {
+			return nil, fmt.Errorf("load item %d: %w", itemID, err)
+		}
+		if item != nil && item.BuyLimit != nil {
+			buyLimits[itemID] = *item.BuyLimit
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := backtest.Run(strategy, bars, buyLimits)
+	return &result, nil
+}
+
+// finish records a job's result or failure.
+func (s *backtestService) finish(ctx context.Context, job *models.Backtest, result *models.BacktestResult, runErr error) {
+	completed := time.Now().UTC()
+	job.CompletedAt = &completed
+
+	if runErr == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			runErr = fmt.Errorf("encode result: %w", err)
+		} else {
+			job.Status = models.BacktestStatusCompleted
+			job.Result = data
+		}
+	}
+	if runErr != nil {
+		message := runErr.Error()
+		job.Status = models.BacktestStatusFailed
+		job.Error = &message
+		s.logger.Warnw("Backtest failed", "id", job.ID, "error", runErr)
+	}
+
+	if err := s.backtestRepo.Update(ctx, job); err != nil {
+		s.logger.Errorw("Failed to store backtest outcome", "id", job.ID, "status", job.Status, "error", err)
+	}
+}
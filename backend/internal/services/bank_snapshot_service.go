@@ -0,0 +1,393 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+	"github.com/guavi/osrs-ge-tracker/internal/valuation"
+)
+
+// revaluedLookback is how far before the first point revalued history loads
+// prices, so the first point can use the bucket that covers it.
+const revaluedLookback = 24 * time.Hour
+
+// bankSnapshotService implements BankSnapshotService.
+type bankSnapshotService struct {
+	snapshotRepo     repository.BankSnapshotRepository
+	itemRepo         repository.ItemRepository
+	priceRepo        repository.PriceRepository
+	valuationService ValuationService
+	logger           *zap.SugaredLogger
+}
+
+// NewBankSnapshotService creates a new bank snapshot service.
+func NewBankSnapshotService(
+	snapshotRepo repository.BankSnapshotRepository,
+	itemRepo repository.ItemRepository,
+	priceRepo repository.PriceRepository,
+	valuationService ValuationService,
+	logger *zap.SugaredLogger,
+) BankSnapshotService {
+	return &bankSnapshotService{
+		snapshotRepo:     snapshotRepo,
+		itemRepo:         itemRepo,
+		priceRepo:        priceRepo,
+		valuationService: valuationService,
+		logger:           logger,
+	}
+}
+
+// Create values a bank export and saves the stacks that resolved to an item.
+// Unresolved rows and placeholders are reported in the valuation but not
+// saved. An empty name defaults to the creation date. Validation errors wrap
+// models.ErrInvalidBankSnapshot.
+func (s *bankSnapshotService) Create(
+	ctx context.Context,
+	userID, name string,
+	entries []models.BankEntry,
+) (*models.BankSnapshotDetail, error) {
+	now := time.Now().UTC()
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Bank " + now.Format(time.DateOnly)
+	}
+	if utf8.RuneCountInString(name) > models.MaxBankSnapshotNameLength {
+		return nil, invalidSnapshot("name must be at most %d characters", models.MaxBankSnapshotNameLength)
+	}
+
+	value, err := s.valuationService.ValueBank(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+	if len(value.Items) == 0 {
+		return nil, invalidSnapshot("export has no items that could be valued")
+	}
+
+	snapshot := models.BankSnapshot{
+		UserID: userID,
+		Name:   name,
+		Items:  make([]models.BankSnapshotItem, 0, len(value.Items)),
+	}
+	for _, item := range value.Items {
+		snapshot.Items = append(snapshot.Items, models.BankSnapshotItem{ItemID: item.ItemID, Quantity: item.Quantity})
+	}
+
+	created, err := s.snapshotRepo.Create(ctx, &snapshot, models.MaxBankSnapshotsPerUser)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, invalidSnapshot("at most %d snapshots can be saved; delete one first", models.MaxBankSnapshotsPerUser)
+	}
+	return &models.BankSnapshotDetail{BankSnapshot: snapshot, Valuation: *value}, nil
+}
+
+// List returns a user's snapshots, newest first.
+func (s *bankSnapshotService) List(ctx context.Context, userID string) ([]models.BankSnapshot, error) {
+	snapshots, err := s.snapshotRepo.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if snapshots == nil {
+		snapshots = []models.BankSnapshot{}
+	}
+	return snapshots, nil
+}
+
+// Get returns one of a user's snapshots valued at current prices, or nil
+// when it does not exist.
+func (s *bankSnapshotService) Get(ctx context.Context, userID string, id int64) (*models.BankSnapshotDetail, error) {
+	snapshot, err := s.snapshotRepo.Get(ctx, userID, id)
+	if err != nil || snapshot == nil {
+		return nil, err
+	}
+
+	value, err := s.valuationService.ValueBank(ctx, snapshotEntries(snapshot))
+	if err != nil {
+		return nil, err
+	}
+	return &models.BankSnapshotDetail{BankSnapshot: *snapshot, Valuation: *value}, nil
+}
+
+// Delete removes one of a user's snapshots and reports whether it existed.
+func (s *bankSnapshotService) Delete(ctx context.Context, userID string, id int64) (bool, error) {
+	return s.snapshotRepo.Delete(ctx, userID, id)
+}
+
+// History returns a snapshot's value over a time range, or nil when the
+// snapshot does not exist. Revalued history prices the saved stacks at each
+// point with the last stored price at or before it, stepping back from To by
+// Interval; recorded history returns the values stored by the daily job.
+func (s *bankSnapshotService) History(
+	ctx context.Context,
+	userID string,
+	id int64,
+	params models.NetWorthHistoryParams,
+) (*models.NetWorthHistory, error) {
+	now := time.Now().UTC()
+	if params.To.IsZero() || params.To.After(now) {
+		params.To = now
+	}
+	if params.From.IsZero() {
+		params.From = params.To.Add(-models.DefaultNetWorthHistoryRange)
+	}
+	if params.Source == "" {
+		params.Source = models.NetWorthSourceRevalued
+	}
+	if params.Interval <= 0 {
+		params.Interval = models.NetWorthIntervals["1d"]
+	}
+	if !params.From.Before(params.To) {
+		return nil, invalidSnapshot("from must be before to")
+	}
+
+	switch params.Source {
+	case models.NetWorthSourceRevalued:
+		if points := params.To.Sub(params.From) / params.Interval; points >= models.MaxNetWorthPoints {
+			return nil, invalidSnapshot("range must span at most %d intervals", models.MaxNetWorthPoints)
+		}
+	case models.NetWorthSourceRecorded:
+	default:
+		return nil, invalidSnapshot("source must be %s or %s", models.NetWorthSourceRevalued, models.NetWorthSourceRecorded)
+	}
+
+	snapshot, err := s.snapshotRepo.Get(ctx, userID, id)
+	if err != nil || snapshot == nil {
+		return nil, err
+	}
+
+	history := &models.NetWorthHistory{SnapshotID: snapshot.ID, Source: params.Source}
+	if params.Source == models.NetWorthSourceRecorded {
+		history.Points, err = s.recordedHistory(ctx, snapshot.ID, params)
+	} else {
+		history.Points, err = s.revaluedHistory(ctx, snapshot, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// RecordDailyValues stores every snapshot's value at current prices for
+// today (UTC), replacing a value already recorded today. Returns the number
+// of snapshots recorded.
+func (s *bankSnapshotService) RecordDailyValues(ctx context.Context) (int, error) {
+	snapshots, err := s.snapshotRepo.ListAll(ctx)
+	if err != nil || len(snapshots) == 0 {
+		return 0, err
+	}
+
+	seen := make(map[int]struct{})
+	ids := make([]int, 0)
+	for _, snapshot := range snapshots {
+		for _, item := range snapshot.Items {
+			if _, dup := seen[item.ItemID]; !dup {
+				seen[item.ItemID] = struct{}{}
+				ids = append(ids, item.ItemID)
+			}
+		}
+	}
+	matches, err := s.directMatches(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+	current, err := s.priceRepo.GetCurrentPrices(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+	prices := make(map[int]models.CurrentPrice, len(current))
+	for _, p := range current {
+		prices[p.ItemID] = p
+	}
+
+	now := time.Now().UTC()
+	today := now.Truncate(24 * time.Hour)
+	values := make([]models.BankSnapshotValue, 0, len(snapshots))
+	for i := range snapshots {
+		v := valuation.Compute(snapshotEntries(&snapshots[i]), matches, prices)
+		values = append(values, models.BankSnapshotValue{
+			SnapshotID:    snapshots[i].ID,
+			ValuedOn:      today,
+			RecordedAt:    now,
+			TotalHigh:     v.TotalHigh,
+			TotalLow:      v.TotalLow,
+			TotalMid:      v.TotalMid,
+			TotalHighAlch: v.TotalHighAlch,
+			UnpricedItems: v.UnpricedItems,
+		})
+	}
+	if err := s.snapshotRepo.UpsertValues(ctx, values); err != nil {
+		return 0, err
+	}
+	return len(values), nil
+}
+
+func (s *bankSnapshotService) revaluedHistory(
+	ctx context.Context,
+	snapshot *models.BankSnapshot,
+	params models.NetWorthHistoryParams,
+) ([]models.NetWorthPoint, error) {
+	entries := snapshotEntries(snapshot)
+	ids := make([]int, 0, len(entries))
+	for _, e := range entries {
+		if !valuation.IsCurrency(e.ItemID) {
+			ids = append(ids, e.ItemID)
+		}
+	}
+	matches, err := s.directMatches(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step back from To so the newest point is the end of the range.
+	count := int(params.To.Sub(params.From)/params.Interval) + 1
+	first := params.To.Add(-time.Duration(count-1) * params.Interval)
+	series, err := s.priceSeries(ctx, ids, netWorthTimestep(params.Interval), first.Add(-revaluedLookback), params.To)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]models.NetWorthPoint, count)
+	for i := 0; i < count; i++ {
+		ts := first.Add(time.Duration(i) * params.Interval)
+		prices := make(map[int]models.CurrentPrice, len(series))
+		for id, observed := range series {
+			if p, ok := priceAsOf(observed, ts); ok {
+				prices[id] = p
+			}
+		}
+		points[i] = models.NewNetWorthPoint(ts, valuation.Compute(entries, matches, prices))
+	}
+	return points, nil
+}
+
+// priceSeries loads every stored price of ids between from and to in one
+// query per table: the timestep's buckets, the daily rollups that replace 24h
+// buckets after 30 days, and the current prices. Each item's prices are
+// returned oldest first, stamped with the start of their bucket.
+func (s *bankSnapshotService) priceSeries(
+	ctx context.Context,
+	ids []int,
+	timestep string,
+	from, to time.Time,
+) (map[int][]models.CurrentPrice, error) {
+	series := make(map[int][]models.CurrentPrice, len(ids))
+	if len(ids) == 0 {
+		return series, nil
+	}
+	add := func(itemID int, ts time.Time, high, low *int64) {
+		if high != nil || low != nil {
+			series[itemID] = append(series[itemID], models.CurrentPrice{ItemID: itemID, UpdatedAt: ts, HighPrice: high, LowPrice: low})
+		}
+	}
+
+	daily, err := s.priceRepo.GetDailyPointsForItems(ctx, ids, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("fetch daily points: %w", err)
+	}
+	for _, p := range daily {
+		day := time.Date(p.Day.Year(), p.Day.Month(), p.Day.Day(), 0, 0, 0, 0, time.UTC)
+		add(p.ItemID, day, p.AvgHighPrice, p.AvgLowPrice)
+	}
+	buckets, err := s.priceRepo.GetTimeseriesPointsForItems(ctx, ids, timestep, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("fetch timeseries points: %w", err)
+	}
+	for _, p := range buckets {
+		add(p.ItemID, p.Timestamp.UTC(), p.AvgHighPrice, p.AvgLowPrice)
+	}
+	current, err := s.priceRepo.GetCurrentPrices(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range current {
+		if !p.UpdatedAt.After(to) {
+			add(p.ItemID, p.UpdatedAt.UTC(), p.HighPrice, p.LowPrice)
+		}
+	}
+
+	for _, observed := range series {
+		sort.SliceStable(observed, func(i, j int) bool { return observed[i].UpdatedAt.Before(observed[j].UpdatedAt) })
+	}
+	return series, nil
+}
+
+// netWorthTimestep returns the widest timeseries bucket that is no wider
+// than a history interval.
+func netWorthTimestep(interval time.Duration) string {
+	switch {
+	case interval < 6*time.Hour:
+		return "1h"
+	case interval < 24*time.Hour:
+		return "6h"
+	default:
+		return "24h"
+	}
+}
+
+// priceAsOf returns the last price at or before ts from prices sorted oldest
+// first.
+func priceAsOf(prices []models.CurrentPrice, ts time.Time) (models.CurrentPrice, bool) {
+	i := sort.Search(len(prices), func(i int) bool { return prices[i].UpdatedAt.After(ts) })
+	if i == 0 {
+		return models.CurrentPrice{}, false
+	}
+	return prices[i-1], true
+}
+
+func (s *bankSnapshotService) recordedHistory(
+	ctx context.Context,
+	snapshotID int64,
+	params models.NetWorthHistoryParams,
+) ([]models.NetWorthPoint, error) {
+	values, err := s.snapshotRepo.ListValues(ctx, snapshotID, params.From, params.To)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]models.NetWorthPoint, 0, len(values))
+	for _, v := range values {
+		points = append(points, models.NetWorthPoint{
+			Timestamp:     v.RecordedAt,
+			TotalHigh:     v.TotalHigh,
+			TotalLow:      v.TotalLow,
+			TotalMid:      v.TotalMid,
+			TotalHighAlch: v.TotalHighAlch,
+			UnpricedItems: v.UnpricedItems,
+		})
+	}
+	return points, nil
+}
+
+// directMatches looks up saved item IDs, which were resolved when the
+// snapshot was created.
+func (s *bankSnapshotService) directMatches(ctx context.Context, ids []int) (map[int]valuation.Match, error) {
+	items, err := s.itemRepo.GetByItemIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	matches := make(map[int]valuation.Match, len(items))
+	for _, item := range items {
+		matches[item.ItemID] = valuation.Match{Item: item, Resolution: models.BankResolutionDirect}
+	}
+	return matches, nil
+}
+
+func snapshotEntries(snapshot *models.BankSnapshot) []models.BankEntry {
+	entries := make([]models.BankEntry, 0, len(snapshot.Items))
+	for _, item := range snapshot.Items {
+		entries = append(entries, models.BankEntry{ItemID: item.ItemID, Quantity: item.Quantity})
+	}
+	return entries
+}
+
+func invalidSnapshot(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", models.ErrInvalidBankSnapshot, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+// buyLimitClockSkew is how far in the future a purchase time may be, to allow
+// for client clocks running ahead.
+const buyLimitClockSkew = 5 * time.Minute
+
+// buyLimitService implements BuyLimitService.
+type buyLimitService struct {
+	buyLimitRepo repository.BuyLimitRepository
+	itemRepo     repository.ItemRepository
+	logger       *zap.SugaredLogger
+}
+
+// NewBuyLimitService creates a new buy limit service.
+func NewBuyLimitService(
+	buyLimitRepo repository.BuyLimitRepository,
+	itemRepo repository.ItemRepository,
+	logger *zap.SugaredLogger,
+) BuyLimitService {
+	return &buyLimitService{
+		buyLimitRepo: buyLimitRepo,
+		itemRepo:     itemRepo,
+		logger:       logger,
+	}
+}
+
+// RecordPurchase logs a purchase against an item's buy limit and returns the
+// item's updated status. Validation errors wrap models.ErrInvalidBuyLimitPurchase.
+func (s *buyLimitService) RecordPurchase(
+	ctx context.Context,
+	userID string,
+	req models.BuyLimitPurchaseRequest,
+) (*models.BuyLimitStatus, error) {
+	now := time.Now().UTC()
+	purchasedAt := now
+	if req.PurchasedAt != nil {
+		purchasedAt = req.PurchasedAt.UTC()
+	}
+
+	switch {
+	case req.ItemID <= 0:
+		return nil, invalidPurchase("itemId is required")
+	case req.Quantity < 1 || req.Quantity > math.MaxInt32:
+		return nil, invalidPurchase("quantity must be between 1 and %d", math.MaxInt32)
+	case purchasedAt.After(now.Add(buyLimitClockSkew)):
+		return nil, invalidPurchase("purchasedAt must not be in the future")
+	case !purchasedAt.After(now.Add(-models.BuyLimitWindow)):
+		return nil, invalidPurchase("purchasedAt must be within the last 4 hours")
+	}
+
+	item, err := s.itemRepo.GetByItemID(ctx, req.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, invalidPurchase("unknown item ID: %d", req.ItemID)
+	}
+
+	timer, err := s.buyLimitRepo.RecordPurchase(ctx, &models.BuyLimitPurchase{
+		UserID:      userID,
+		ItemID:      req.ItemID,
+		Quantity:    req.Quantity,
+		PurchasedAt: purchasedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	status := models.NewBuyLimitStatus(item.ItemID, item.BuyLimit, timer, now)
+	status.ItemName = item.Name
+	return &status, nil
+}
+
+// DeletePurchase removes one of a user's purchases and reports whether it existed.
+func (s *buyLimitService) DeletePurchase(ctx context.Context, userID string, id int64) (bool, error) {
+	return s.buyLimitRepo.DeletePurchase(ctx, userID, id)
+}
+
+// GetStatus returns a user's status for one item, including the purchases in
+// the open window. Returns nil when the item does not exist.
+func (s *buyLimitService) GetStatus(ctx context.Context, userID string, itemID int) (*models.BuyLimitStatus, error) {
+	item, err := s.itemRepo.GetByItemID(ctx, itemID)
+	if err != nil || item == nil {
+		return nil, err
+	}
+	timer, err := s.buyLimitRepo.GetTimer(ctx, userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	status := models.NewBuyLimitStatus(itemID, item.BuyLimit, timer, now)
+	status.ItemName = item.Name
+	if timer.Active(now) {
+		status.Purchases, err = s.buyLimitRepo.ListPurchases(ctx, userID, itemID, timer.WindowStart)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &status, nil
+}
+
+// ListActive returns the items with an open window, soonest reset first.
+func (s *buyLimitService) ListActive(ctx context.Context, userID string) ([]models.BuyLimitStatus, error) {
+	timers, err := s.buyLimitRepo.ListTimers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	active := make([]*models.BuyLimitTimer, 0, len(timers))
+	ids := make([]int, 0, len(timers))
+	for i := range timers {
+		if timers[i].Active(now) {
+			active = append(active, &timers[i])
+			ids = append(ids, timers[i].ItemID)
+		}
+	}
+	items, err := s.items(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]models.BuyLimitStatus, 0, len(active))
+	for _, timer := range active {
+		statuses = append(statuses, statusFor(timer.ItemID, items, timer, now))
+	}
+	return statuses, nil
+}
+
+// ListAvailable returns the items a user can buy right now, ordered by item
+// ID. Without itemIDs it checks every item the user has logged purchases of;
+// with them it checks exactly those, skipping unknown IDs.
+func (s *buyLimitService) ListAvailable(ctx context.Context, userID string, itemIDs []int) ([]models.BuyLimitStatus, error) {
+	timers, err := s.buyLimitRepo.ListTimers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byItem := make(map[int]*models.BuyLimitTimer, len(timers))
+	for i := range timers {
+		byItem[timers[i].ItemID] = &timers[i]
+	}
+
+	checkAll := len(itemIDs) == 0
+	if checkAll {
+		itemIDs = make([]int, 0, len(timers))
+		for id := range byItem {
+			itemIDs = append(itemIDs, id)
+		}
+	}
+	sorted := append([]int(nil), itemIDs...)
+	sort.Ints(sorted)
+	items, err := s.items(ctx, sorted)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	statuses := make([]models.BuyLimitStatus, 0, len(sorted))
+	for _, id := range sorted {
+		status := statusFor(id, items, byItem[id], now)
+		if status.ItemName == "" && !checkAll {
+			continue
+		}
+		if status.Available {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses, nil
+}
+
+// ProcessResets claims every window that has closed since the last run and
+// returns one reset per window for notification.
+func (s *buyLimitService) ProcessResets(ctx context.Context) ([]models.BuyLimitReset, error) {
+	timers, err := s.buyLimitRepo.ClaimResets(ctx, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(timers))
+	for _, timer := range timers {
+		ids = append(ids, timer.ItemID)
+	}
+	items, err := s.items(ctx, ids)
+	if err != nil {
+		// The windows are already claimed; announce them without item details.
+		s.logger.Warnw("Failed to look up items for buy limit resets", "count", len(ids), "error", err)
+	}
+
+	resets := make([]models.BuyLimitReset, 0, len(timers))
+	for _, timer := range timers {
+		reset := models.BuyLimitReset{
+			UserID:  timer.UserID,
+			ItemID:  timer.ItemID,
+			Bought:  timer.Bought,
+			ResetAt: timer.ResetsAt,
+		}
+		if item, ok := items[timer.ItemID]; ok {
+			reset.ItemName = item.Name
+			reset.Limit = item.BuyLimit
+		}
+		resets = append(resets, reset)
+	}
+	return resets, nil
+}
+
+// items looks up ids in one query, keyed by item ID. Unknown IDs are
+// missing from the map.
+func (s *buyLimitService) items(ctx context.Context, ids []int) (map[int]models.Item, error) {
+	if len(ids) == 0 {
+		return map[int]models.Item{}, nil
+	}
+	list, err := s.itemRepo.GetByItemIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[int]models.Item, len(list))
+	for _, item := range list {
+		items[item.ItemID] = item
+	}
+	return items, nil
+}
+
+// statusFor computes an item's status, leaving ItemName empty when the item
+// is not in items.
+func statusFor(itemID int, items map[int]models.Item, timer *models.BuyLimitTimer, now time.Time) models.BuyLimitStatus {
+	item, ok := items[itemID]
+	if !ok {
+		return models.NewBuyLimitStatus(itemID, nil, timer, now)
+	}
+	status := models.NewBuyLimitStatus(itemID, item.BuyLimit, timer, now)
+	status.ItemName = item.Name
+	return status
+}
+
+func invalidPurchase(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", models.ErrInvalidBuyLimitPurchase, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// bondHistoryMaxPoints is how finely the bond series is sampled when
+// converting a price history, so each point finds a bond price close to it.
+const bondHistoryMaxPoints = 1000
+
+// denominationService implements DenominationService.
+type denominationService struct {
+	priceService PriceService
+	logger       *zap.SugaredLogger
+	realPrice    float64
+	currency     string
+}
+
+// NewDenominationService creates a new denomination service. realPrice is
+// the real-money price of one bond in currency; zero disables the real
+// denomination.
+func NewDenominationService(
+	priceService PriceService,
+	realPrice float64,
+	currency string,
+	logger *zap.SugaredLogger,
+) DenominationService {
+	return &denominationService{
+		priceService: priceService,
+		logger:       logger,
+		realPrice:    realPrice,
+		currency:     currency,
+	}
+}
+
+// CurrentRate returns the bond's live price.
+func (s *denominationService) CurrentRate(ctx context.Context, d models.Denomination) (*models.BondRate, error) {
+	if err := s.validate(d); err != nil {
+		return nil, err
+	}
+	price, err := s.priceService.GetCurrentPrice(ctx, models.BondItemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bond price: %w", err)
+	}
+	if price == nil {
+		return nil, models.ErrBondPriceUnavailable
+	}
+	rate, ok := s.rate(price.UpdatedAt, price.HighPrice, price.LowPrice)
+	if !ok {
+		return nil, models.ErrBondPriceUnavailable
+	}
+	return rate, nil
+}
+
+// DenominateCurrentPrices converts current prices at the bond's live price.
+func (s *denominationService) DenominateCurrentPrices(
+	ctx context.Context,
+	prices []models.CurrentPrice,
+	d models.Denomination,
+) ([]models.DenominatedCurrentPrice, *models.BondRate, error) {
+	rate, err := s.CurrentRate(ctx, d)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	converted := make([]models.DenominatedCurrentPrice, 0, len(prices))
+	for _, p := range prices {
+		converted = append(converted, models.DenominatedCurrentPrice{
+			UpdatedAt:     p.UpdatedAt,
+			HighPrice:     rate.ConvertPtr(p.HighPrice, d),
+			HighPriceTime: p.HighPriceTime,
+			LowPrice:      rate.ConvertPtr(p.LowPrice, d),
+			LowPriceTime:  p.LowPriceTime,
+			ItemID:        p.ItemID,
+		})
+	}
+	return converted, rate, nil
+}
+
+// DenominatePriceHistory converts each history point at the last bond price
+// stored at or before its timestamp. The bond series is read from the same
+// tables as the history, so both share a resolution.
+func (s *denominationService) DenominatePriceHistory(
+	ctx context.Context,
+	history *models.PriceHistoryResponse,
+	d models.Denomination,
+) (*models.DenominatedPriceHistory, error) {
+	if err := s.validate(d); err != nil {
+		return nil, err
+	}
+
+	rates, err := s.bondRates(ctx, models.PriceHistoryParams{Period: models.TimePeriod(history.Period)})
+	if err != nil {
+		return nil, err
+	}
+	if len(rates) == 0 {
+		return nil, models.ErrBondPriceUnavailable
+	}
+
+	result := &models.DenominatedPriceHistory{Points: make([]models.DenominatedPricePoint, 0, len(history.Data))}
+	for _, p := range history.Data {
+		rate, ok := rateAsOf(rates, p.Timestamp)
+		if !ok {
+			result.Unconverted++
+			continue
+		}
+		result.Points = append(result.Points, models.DenominatedPricePoint{
+			Timestamp: p.Timestamp,
+			HighPrice: rate.Convert(p.HighPrice, d),
+			LowPrice:  rate.Convert(p.LowPrice, d),
+			BondPrice: rate.Price,
+		})
+	}
+	return result, nil
+}
+
+// DenominateValuation converts a bank valuation at the bond's live price.
+func (s *denominationService) DenominateValuation(
+	ctx context.Context,
+	v *models.BankValuation,
+	d models.Denomination,
+) (*models.DenominatedValuation, *models.BondRate, error) {
+	rate, err := s.CurrentRate(ctx, d)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	converted := &models.DenominatedValuation{
+		Items:         make([]models.DenominatedValuationItem, 0, len(v.Items)),
+		Unresolved:    v.Unresolved,
+		TotalHigh:     rate.Convert(v.TotalHigh, d),
+		TotalLow:      rate.Convert(v.TotalLow, d),
+		TotalMid:      rate.Convert(v.TotalMid, d),
+		TotalHighAlch: rate.Convert(v.TotalHighAlch, d),
+		Placeholders:  v.Placeholders,
+		UnpricedItems: v.UnpricedItems,
+	}
+	for _, item := range v.Items {
+		converted.Items = append(converted.Items, models.DenominatedValuationItem{
+			HighPrice:     rate.ConvertPtr(item.HighPrice, d),
+			LowPrice:      rate.ConvertPtr(item.LowPrice, d),
+			MidPrice:      rate.ConvertPtr(item.MidPrice, d),
+			HighValue:     rate.ConvertPtr(item.HighValue, d),
+			LowValue:      rate.ConvertPtr(item.LowValue, d),
+			MidValue:      rate.ConvertPtr(item.MidValue, d),
+			HighAlchValue: rate.ConvertPtr(item.HighAlchValue, d),
+			Name:          item.Name,
+			Resolution:    item.Resolution,
+			SourceItemIDs: item.SourceItemIDs,
+			Quantity:      item.Quantity,
+			ItemID:        item.ItemID,
+		})
+	}
+	return converted, rate, nil
+}
+
+// DenominateNetWorth converts each net worth point at the last bond price
+// stored at or before its timestamp. The bond series is loaded once, from the
+// shortest period that reaches back to the oldest point.
+func (s *denominationService) DenominateNetWorth(
+	ctx context.Context,
+	history *models.NetWorthHistory,
+	d models.Denomination,
+) (*models.DenominatedNetWorthHistory, error) {
+	if err := s.validate(d); err != nil {
+		return nil, err
+	}
+
+	result := &models.DenominatedNetWorthHistory{
+		Points:     make([]models.DenominatedNetWorthPoint, 0, len(history.Points)),
+		Source:     history.Source,
+		SnapshotID: history.SnapshotID,
+	}
+	if len(history.Points) == 0 {
+		return result, nil
+	}
+
+	oldest, newest := history.Points[0].Timestamp, history.Points[0].Timestamp
+	for _, p := range history.Points[1:] {
+		if p.Timestamp.Before(oldest) {
+			oldest = p.Timestamp
+		}
+		if p.Timestamp.After(newest) {
+			newest = p.Timestamp
+		}
+	}
+	// Start a day early so the oldest point has a bond price before it.
+	start := oldest.Add(-24 * time.Hour)
+	rates, err := s.bondRates(ctx, models.PriceHistoryParams{
+		Period:    periodReaching(start),
+		StartTime: &start,
+		EndTime:   &newest,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range history.Points {
+		rate, ok := rateAsOf(rates, p.Timestamp)
+		if !ok {
+			result.Unconverted++
+			continue
+		}
+		result.Points = append(result.Points, models.DenominatedNetWorthPoint{
+			Timestamp:     p.Timestamp,
+			TotalHigh:     rate.Convert(p.TotalHigh, d),
+			TotalLow:      rate.Convert(p.TotalLow, d),
+			TotalMid:      rate.Convert(p.TotalMid, d),
+			TotalHighAlch: rate.Convert(p.TotalHighAlch, d),
+			BondPrice:     rate.Price,
+			UnpricedItems: p.UnpricedItems,
+		})
+	}
+	return result, nil
+}
+
+// bondRates loads the bond's price history for params, sampled to
+// bondHistoryMaxPoints, as rates sorted oldest first.
+func (s *denominationService) bondRates(ctx context.Context, params models.PriceHistoryParams) ([]models.BondRate, error) {
+	maxPoints := bondHistoryMaxPoints
+	params.ItemID = models.BondItemID
+	params.MaxPoints = &maxPoints
+	bond, err := s.priceService.GetPriceHistory(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bond price history: %w", err)
+	}
+
+	rates := make([]models.BondRate, 0, len(bond.Data))
+	for _, p := range bond.Data {
+		if rate, ok := s.rate(p.Timestamp, &p.HighPrice, &p.LowPrice); ok {
+			rates = append(rates, *rate)
+		}
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Timestamp.Before(rates[j].Timestamp) })
+	return rates, nil
+}
+
+// periodReaching returns the shortest history period that reaches back to
+// since, so the bond series is read from the finest table that still holds it.
+func periodReaching(since time.Time) models.TimePeriod {
+	age := time.Since(since)
+	for _, period := range []models.TimePeriod{
+		models.Period24Hours,
+		models.Period3Days,
+		models.Period7Days,
+		models.Period30Days,
+		models.Period90Days,
+		models.Period1Year,
+	} {
+		if period.Duration() >= age {
+			return period
+		}
+	}
+	return models.PeriodAll
+}
+
+// validate rejects unknown denominations, and the real denomination when
+// no real-money bond price is configured.
+func (s *denominationService) validate(d models.Denomination) error {
+	switch {
+	case !d.IsValid():
+		return fmt.Errorf("%w: denomination must be %s, %s or %s",
+			models.ErrInvalidDenomination, models.DenominationGP, models.DenominationBonds, models.DenominationReal)
+	case d == models.DenominationReal && s.realPrice <= 0:
+		return fmt.Errorf("%w: no real-money bond price is configured", models.ErrInvalidDenomination)
+	}
+	return nil
+}
+
+// rate builds a BondRate from the bond's mid price, or from whichever side
+// is present. It reports false when neither side has a positive price.
+func (s *denominationService) rate(ts time.Time, high, low *int64) (*models.BondRate, bool) {
+	var price int64
+	switch {
+	case high != nil && *high > 0 && low != nil && *low > 0:
+		price = (*high + *low) / 2
+	case high != nil && *high > 0:
+		price = *high
+	case low != nil && *low > 0:
+		price = *low
+	default:
+		return nil, false
+	}
+
+	rate := &models.BondRate{Timestamp: ts, Price: price}
+	if s.realPrice > 0 {
+		realPrice := s.realPrice
+		rate.RealPrice = &realPrice
+		rate.Currency = s.currency
+	}
+	return rate, true
+}
+
+// rateAsOf returns the last rate at or before ts from rates sorted oldest
+// first.
+func rateAsOf(rates []models.BondRate, ts time.Time) (models.BondRate, bool) {
+	i := sort.Search(len(rates), func(i int) bool { return rates[i].Timestamp.After(ts) })
+	if i == 0 {
+		return models.BondRate{}, false
+	}
+	return rates[i-1], true
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/guavi/osrs-ge-tracker/internal/models"
@@ -57,6 +58,12 @@ type PriceService interface {
 	// GetPriceHistory returns historical price data for an item
 	GetPriceHistory(ctx context.Context, params models.PriceHistoryParams) (*models.PriceHistoryResponse, error)
 
+	// ComparePrices aligns several items on a common grid and returns rebased and ratio series
+	ComparePrices(ctx context.Context, params models.PriceCompareParams) (*models.PriceCompareResponse, error)
+
+	// GetPricesAt returns each item's price at a point in time from the finest resolution covering it
+	GetPricesAt(ctx context.Context, itemIDs []int, ts time.Time) ([]models.PriceAt, error)
+
 	// UpdateCurrentPrice updates the current price for an item
 	UpdateCurrentPrice(ctx context.Context, price *models.CurrentPrice) error
 
@@ -64,6 +71,10 @@ type PriceService interface {
 	// Returns the list of price updates that were synced for SSE broadcasting.
 	SyncCurrentPrices(ctx context.Context) ([]models.BulkPriceUpdate, error)
 
+	// SyncTimeseries stores an item's latest bucketed points for a timestep from the OSRS Wiki /timeseries endpoint.
+	// Buckets that are already stored are left untouched.
+	SyncTimeseries(ctx context.Context, itemID int, timestep string) error
+
 	// RunMaintenance performs retention pruning and rollups for realtime price tables.
 	RunMaintenance(ctx context.Context) error
 
@@ -71,6 +82,21 @@ type PriceService interface {
 	EnsureFuturePartitions(ctx context.Context, daysAhead int) error
 }
 
+// AnalyticsService defines the interface for derived price analytics.
+type AnalyticsService interface {
+	// GetSeasonality returns the hour-of-day and day-of-week price/volume profile for an item
+	GetSeasonality(ctx context.Context, params models.SeasonalityParams) (*models.SeasonalityProfile, error)
+
+	// RefreshSeasonality recomputes cached seasonality profiles for recently requested items
+	RefreshSeasonality(ctx context.Context) (int, error)
+
+	// GetForecast predicts the next hours of an item's price with prediction intervals and backtest errors
+	GetForecast(ctx context.Context, params models.ForecastParams) (*models.ForecastResponse, error)
+
+	// GetCorrelation computes return correlation matrices for a set of items
+	GetCorrelation(ctx context.Context, params models.CorrelationParams) (*models.CorrelationResponse, error)
+}
+
 // CacheService defines the interface for caching operations.
 type CacheService interface {
 	// Get retrieves a value from cache
@@ -94,3 +120,333 @@ type CacheService interface {
 	// Exists checks if a key exists in cache
 	Exists(ctx context.Context, key string) (bool, error)
 }
+
+// BacktestService runs strategy backtests asynchronously and stores their results.
+type BacktestService interface {
+	// Submit validates a strategy, stores a pending job and starts it in the background
+	Submit(ctx context.Context, strategy models.BacktestStrategy) (*models.Backtest, error)
+
+	// Get returns a backtest with its result, or nil when it does not exist
+	Get(ctx context.Context, id string) (*models.Backtest, error)
+
+	// List returns the most recent backtests without their results
+	List(ctx context.Context, limit int) ([]models.Backtest, error)
+
+	// FailInterrupted marks jobs left unfinished by a previous process as failed
+	FailInterrupted(ctx context.Context) (int64, error)
+
+	// Shutdown cancels running jobs and waits for them to stop
+	Shutdown(ctx context.Context) error
+}
+
+// PaperTradingService runs practice accounts whose simulated offers fill against synced prices.
+type PaperTradingService interface {
+	// CreateAccount opens a paper account for a user funded with startingBalance GP (0 means the default)
+	CreateAccount(ctx context.Context, userID, name string, startingBalance int64) (*models.PaperAccount, error)
+
+	// GetAccount returns an account with its marked positions and open offers, or nil when it does not exist
+	GetAccount(ctx context.Context, id string) (*models.PaperAccountSummary, error)
+
+	// PlaceOffer validates and places a simulated buy or sell offer
+	PlaceOffer(ctx context.Context, accountID string, req models.PaperOfferRequest) (*models.PaperOffer, error)
+
+	// CancelOffer cancels an open offer, or returns nil when the account has no such offer
+	CancelOffer(ctx context.Context, accountID string, offerID int64) (*models.PaperOffer, error)
+
+	// ListOffers returns an account's offers, newest first, optionally filtered by status
+	ListOffers(ctx context.Context, accountID string, status models.PaperOfferStatus, limit int) ([]models.PaperOffer, error)
+
+	// ListPositions returns an account's positions marked to current prices
+	ListPositions(ctx context.Context, accountID string) ([]models.PaperPositionView, error)
+
+	// ListFills returns an account's most recent fills
+	ListFills(ctx context.Context, accountID string, limit int) ([]models.PaperFill, error)
+
+	// ProcessPriceUpdates fills open offers crossed by freshly synced prices
+	ProcessPriceUpdates(ctx context.Context, updates []models.BulkPriceUpdate) ([]models.PaperFill, error)
+}
+
+// PortfolioService keeps users' trade journals and computes their profit and loss.
+type PortfolioService interface {
+	// AddEntry validates and records one trade for a user
+	AddEntry(ctx context.Context, userID string, entry models.TradeJournalEntry) (*models.TradeJournalEntry, error)
+
+	// ListEntries returns a page of a user's journal, newest first, with the filtered total
+	ListEntries(ctx context.Context, userID string, params models.JournalListParams) ([]models.TradeJournalEntry, int64, error)
+
+	// DeleteEntry removes one of a user's entries and reports whether it existed
+	DeleteEntry(ctx context.Context, userID string, id int64) (bool, error)
+
+	// ImportCSV appends the trades in a journal CSV, all or nothing, and returns how many were imported
+	ImportCSV(ctx context.Context, userID string, r io.Reader) (int, error)
+
+	// ExportCSV writes a user's whole journal as CSV in trade order
+	ExportCSV(ctx context.Context, userID string, w io.Writer) error
+
+	// GetSummary computes FIFO realized and unrealized P&L with per-item breakdowns
+	GetSummary(ctx context.Context, userID string) (*models.PortfolioSummary, error)
+}
+
+// BuyLimitService tracks per-user purchases against Grand Exchange buy limits
+type BuyLimitService interface {
+	// RecordPurchase logs a purchase and returns the item's updated status
+	RecordPurchase(ctx context.Context, userID string, req models.BuyLimitPurchaseRequest) (*models.BuyLimitStatus, error)
+
+	// DeletePurchase removes one of a user's purchases and reports whether it existed
+	DeletePurchase(ctx context.Context, userID string, id int64) (bool, error)
+
+	// GetStatus returns a user's remaining quantity and reset time for an item, or nil for unknown items
+	GetStatus(ctx context.Context, userID string, itemID int) (*models.BuyLimitStatus, error)
+
+	// ListActive returns the items with an open window, soonest reset first
+	ListActive(ctx context.Context, userID string) ([]models.BuyLimitStatus, error)
+
+	// ListAvailable returns the items a user can buy right now, from their tracked items or the given IDs
+	ListAvailable(ctx context.Context, userID string, itemIDs []int) ([]models.BuyLimitStatus, error)
+
+	// ProcessResets claims windows that closed since the last run and returns them for notification
+	ProcessResets(ctx context.Context) ([]models.BuyLimitReset, error)
+}
+
+// ValuationService values bank exports at current prices
+type ValuationService interface {
+	// ValueBank values parsed bank export rows, reporting IDs that match no Grand Exchange item
+	ValueBank(ctx context.Context, entries []models.BankEntry) (*models.BankValuation, error)
+}
+
+// BankSnapshotService manages saved banks and their net worth history
+type BankSnapshotService interface {
+	// Create values a bank export and saves the stacks that resolved to an item
+	Create(ctx context.Context, userID, name string, entries []models.BankEntry) (*models.BankSnapshotDetail, error)
+
+	// List returns a user's snapshots, newest first
+	List(ctx context.Context, userID string) ([]models.BankSnapshot, error)
+
+	// Get returns one of a user's snapshots valued at current prices, or nil when it does not exist
+	Get(ctx context.Context, userID string, id int64) (*models.BankSnapshotDetail, error)
+
+	// Delete removes one of a user's snapshots and reports whether it existed
+	Delete(ctx context.Context, userID string, id int64) (bool, error)
+
+	// History returns a snapshot's value over a time range, or nil when the snapshot does not exist
+	History(ctx context.Context, userID string, id int64, params models.NetWorthHistoryParams) (*models.NetWorthHistory, error)
+
+	// RecordDailyValues stores every snapshot's value at current prices for today
+	RecordDailyValues(ctx context.Context) (int, error)
+}
+
+// ArbitrageService finds price gaps between items that convert into one another
+type ArbitrageService interface {
+	// ListSets returns the item set registry with admin edits applied
+	ListSets(ctx context.Context) ([]models.ItemSet, error)
+
+	// SaveSet adds a set to the registry or replaces the definition with the same set item ID
+	SaveSet(ctx context.Context, set models.ItemSet) (*models.ItemSet, error)
+
+	// DeleteSet removes a set from the registry and reports whether it was in it
+	DeleteSet(ctx context.Context, setItemID int) (bool, error)
+
+	// SetArbitrage compares every set with its pieces at current prices, most profitable first
+	SetArbitrage(ctx context.Context, mode models.PricingMode) (*models.SetArbitrageReport, error)
+
+	// RefreshSetArbitrage recomputes and caches the set report for every pricing mode
+	RefreshSetArbitrage(ctx context.Context) error
+
+	// ListDoseGroups returns the potion dose group registry
+	ListDoseGroups(ctx context.Context) ([]models.DoseGroup, error)
+
+	// DoseArbitrage prices decanting between the dose variants of every potion, best first
+	DoseArbitrage(ctx context.Context, mode models.PricingMode) (*models.DoseArbitrageReport, error)
+
+	// RefreshDoseArbitrage recomputes and caches the decanting report for every pricing mode
+	RefreshDoseArbitrage(ctx context.Context) error
+}
+
+// RecipeService prices processing recipes and manages the recipe registry
+type RecipeService interface {
+	// ListRecipes returns the recipe registry with admin edits applied
+	ListRecipes(ctx context.Context) ([]models.Recipe, error)
+
+	// SaveRecipe adds a recipe to the registry or replaces the one with the same ID
+	SaveRecipe(ctx context.Context, recipe models.Recipe) (*models.Recipe, error)
+
+	// DeleteRecipe removes a recipe from the registry and reports whether it was in it
+	DeleteRecipe(ctx context.Context, id string) (bool, error)
+
+	// RecipeProfits prices every recipe at current prices, most profit per action first
+	RecipeProfits(ctx context.Context, mode models.PricingMode) (*models.RecipeReport, error)
+}
+
+// ActivityService values PvM activities by the expected worth of their drop tables
+type ActivityService interface {
+	// ListActivities returns every activity definition
+	ListActivities(ctx context.Context) []models.Activity
+
+	// ActivityValues values every activity at current prices, highest value per hour first
+	ActivityValues(ctx context.Context, mode models.PricingMode) ([]models.ActivityValue, error)
+
+	// ActivityValue values one activity at current prices
+	ActivityValue(ctx context.Context, id string, mode models.PricingMode) (*models.ActivityValue, error)
+
+	// ActivityValueHistory values an activity on each day from the 24h price buckets and daily rollups
+	ActivityValueHistory(ctx context.Context, id string, params models.ActivityHistoryParams) (*models.ActivityValueHistory, error)
+}
+
+// DenominationService converts GP amounts into Old School Bonds or the configured real-money price
+type DenominationService interface {
+	// CurrentRate returns the bond's live price after checking the denomination can be served
+	CurrentRate(ctx context.Context, d models.Denomination) (*models.BondRate, error)
+
+	// DenominateCurrentPrices converts current prices at the bond's live price
+	DenominateCurrentPrices(ctx context.Context, prices []models.CurrentPrice, d models.Denomination) ([]models.DenominatedCurrentPrice, *models.BondRate, error)
+
+	// DenominatePriceHistory converts each history point at the bond price stored at its timestamp
+	DenominatePriceHistory(ctx context.Context, history *models.PriceHistoryResponse, d models.Denomination) (*models.DenominatedPriceHistory, error)
+
+	// DenominateValuation converts a bank valuation at the bond's live price
+	DenominateValuation(ctx context.Context, v *models.BankValuation, d models.Denomination) (*models.DenominatedValuation, *models.BondRate, error)
+
+	// DenominateNetWorth converts each net worth point at the bond's point-in-time price
+	DenominateNetWorth(ctx context.Context, history *models.NetWorthHistory, d models.Denomination) (*models.DenominatedNetWorthHistory, error)
+}
+
+// MarketIndexService computes market indices and manages users' custom indices
+type MarketIndexService interface {
+	// ListIndices returns the builtin indices, or a user's custom indices when userID is set, with their latest values
+	ListIndices(ctx context.Context, userID string) ([]models.MarketIndexSummary, error)
+
+	// GetIndex returns a builtin index, or one of a user's custom indices when userID is set, or nil
+	GetIndex(ctx context.Context, userID, id string) (*models.MarketIndex, error)
+
+	// Summary returns an index with its latest value and recent changes
+	Summary(ctx context.Context, index models.MarketIndex) (*models.MarketIndexSummary, error)
+
+	// History returns an index's stored values between two times
+	History(ctx context.Context, index models.MarketIndex, from, to time.Time) (*models.MarketIndexHistory, error)
+
+	// Constituents returns an index's items with their current weights, heaviest first
+	Constituents(ctx context.Context, index models.MarketIndex) (*models.MarketIndexConstituents, error)
+
+	// CreateCustomIndex stores a custom index for a user and queues its history for BackfillNewIndices
+	CreateCustomIndex(ctx context.Context, userID string, index models.MarketIndex) (*models.MarketIndex, error)
+
+	// DeleteCustomIndex removes one of a user's custom indices and reports whether it existed
+	DeleteCustomIndex(ctx context.Context, userID, id string) (bool, error)
+
+	// BackfillNewIndices computes the history of custom indices created since its last run
+	BackfillNewIndices(ctx context.Context) (int, error)
+
+	// RefreshIndices extends every index over the 1h buckets stored since its last value
+	RefreshIndices(ctx context.Context) (int, error)
+}
+
+// ItemTagService classifies items into categories and tags and lets admins curate them
+type ItemTagService interface {
+	// Categories returns the classifier's category names
+	Categories() []string
+
+	// ListTags returns every tag in use with the number of items carrying it
+	ListTags(ctx context.Context) ([]models.ItemTagCount, error)
+
+	// ItemTags returns an item's tags, including admin removals, or nil when the item does not exist
+	ItemTags(ctx context.Context, itemID int) ([]models.ItemTag, error)
+
+	// AddTag tags an item as an admin, or returns nil when the item does not exist
+	AddTag(ctx context.Context, itemID int, tag string) (*models.ItemTag, error)
+
+	// RemoveTag removes an item's tag as an admin and reports whether the item had it
+	RemoveTag(ctx context.Context, itemID int, tag string) (bool, error)
+
+	// ClassifyItems replaces the classifier's tags for every item and returns how many it assigned
+	ClassifyItems(ctx context.Context) (int, error)
+}
+
+// ItemAliasService resolves item nicknames and moderates the aliases users submit
+type ItemAliasService interface {
+	// SeedAliases stores the shipped aliases missing from the database and returns how many were added
+	SeedAliases(ctx context.Context) (int64, error)
+
+	// Resolve maps each name, alias or slug to an item, in the order given
+	Resolve(ctx context.Context, names []string) ([]models.ItemResolution, error)
+
+	// ItemAliases returns an item's approved aliases, or nil when the item does not exist
+	ItemAliases(ctx context.Context, itemID int) ([]models.ItemAlias, error)
+
+	// SubmitAlias records a user's alias for review, or returns nil when the item does not exist
+	SubmitAlias(ctx context.Context, userID string, itemID int, alias string) (*models.ItemAlias, error)
+
+	// AddAlias adds an approved alias as an admin, or returns nil when the item does not exist
+	AddAlias(ctx context.Context, itemID int, alias string) (*models.ItemAlias, error)
+
+	// ListAliases returns the oldest aliases in a status, for moderation
+	ListAliases(ctx context.Context, status string) ([]models.ItemAlias, error)
+
+	// ReviewAlias approves or rejects an alias, or returns nil when it does not exist
+	ReviewAlias(ctx context.Context, id uint, status string) (*models.ItemAlias, error)
+
+	// DeleteAlias removes an alias and reports whether it existed
+	DeleteAlias(ctx context.Context, id uint) (bool, error)
+}
+
+// AutocompleteService completes item names from an in-memory index
+type AutocompleteService interface {
+	// Rebuild reloads items, aliases, popularity and prices into a new index
+	Rebuild(ctx context.Context) error
+
+	// UpdatePrices applies synced price updates to the prices returned with completions
+	UpdatePrices(updates []models.BulkPriceUpdate)
+
+	// Complete returns up to limit items whose name words or aliases start with query
+	Complete(query string, limit int) []models.AutocompleteItem
+
+	// Ready reports whether the index has been built
+	Ready() bool
+}
+
+// ScreenerService runs screener expressions and manages users' saved screens
+type ScreenerService interface {
+	// Fields returns the fields screener expressions can use
+	Fields() []models.ScreenerField
+
+	// Run evaluates an expression against the market snapshot; a positive limit overrides its LIMIT
+	Run(ctx context.Context, expression string, limit int) (*models.ScreenerResult, error)
+
+	// RefreshSnapshot recomputes the market snapshot expressions are evaluated against
+	RefreshSnapshot(ctx context.Context) error
+
+	// Snapshot returns the market snapshot's rows, which must not be modified, and when it was taken
+	Snapshot(ctx context.Context) ([]models.ScreenerRow, time.Time, error)
+
+	// ListScreens returns a user's saved screens, oldest first
+	ListScreens(ctx context.Context, userID string) ([]models.Screen, error)
+
+	// GetScreen returns one of a user's screens, or nil when it does not exist
+	GetScreen(ctx context.Context, userID string, id int64) (*models.Screen, error)
+
+	// CreateScreen validates and saves a screen for a user
+	CreateScreen(ctx context.Context, userID string, screen models.Screen) (*models.Screen, error)
+
+	// UpdateScreen replaces a screen's name, expression and alert flag, or returns nil when it does not exist
+	UpdateScreen(ctx context.Context, userID string, id int64, screen models.Screen) (*models.Screen, error)
+
+	// DeleteScreen removes one of a user's screens and reports whether it existed
+	DeleteScreen(ctx context.Context, userID string, id int64) (bool, error)
+
+	// RunScreen evaluates a saved screen, or returns nil when it does not exist
+	RunScreen(ctx context.Context, userID string, id int64, limit int) (*models.ScreenerResult, error)
+
+	// CheckAlerts returns an alert for each alerting screen that items started matching since its last check
+	CheckAlerts(ctx context.Context) ([]models.ScreenAlert, error)
+}
+
+// MarketTableService serves the dashboard's market table from precomputed per-item stats
+type MarketTableService interface {
+	// Refresh stores every item's stats from the screener's market snapshot, unless that snapshot is already stored
+	Refresh(ctx context.Context) error
+
+	// LastUpdated returns the time of the last refresh, or nil before the first one
+	LastUpdated(ctx context.Context) (*time.Time, error)
+
+	// Query returns one page of the table and the total number of matching rows
+	Query(ctx context.Context, params models.MarketTableParams) ([]models.ItemStats, int64, error)
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/aliases"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+// itemAliasListLimit bounds the aliases returned by one moderation listing.
+const itemAliasListLimit = 200
+
+// itemAliasService implements ItemAliasService.
+type itemAliasService struct {
+	seed      []models.ItemAlias
+	aliasRepo repository.ItemAliasRepository
+	itemRepo  repository.ItemRepository
+	logger    *zap.SugaredLogger
+}
+
+// NewItemAliasService creates a new item alias service. seed holds the
+// aliases stored by SeedAliases.
+func NewItemAliasService(
+	seed []models.ItemAlias,
+	aliasRepo repository.ItemAliasRepository,
+	itemRepo repository.ItemRepository,
+	logger *zap.SugaredLogger,
+) ItemAliasService {
+	return &itemAliasService{
+		seed:      seed,
+		aliasRepo: aliasRepo,
+		itemRepo:  itemRepo,
+		logger:    logger,
+	}
+}
+
+// SeedAliases stores the shipped aliases that are not in the database yet,
+// in any status, and returns how many were added.
+func (s *itemAliasService) SeedAliases(ctx context.Context) (int64, error) {
+	added, err := s.aliasRepo.InsertMissing(ctx, s.seed)
+	if err != nil {
+		return 0, err
+	}
+	if added > 0 {
+		s.logger.Infow("Seeded item aliases", "added", added, "shipped", len(s.seed))
+	}
+	return added, nil
+}
+
+// Resolve maps each name to an item, trying the item name, then approved
+// aliases, then the name slug, all ignoring case. Items sharing a name
+// resolve to the lowest item ID. Results follow the order of names.
+func (s *itemAliasService) Resolve(ctx context.Context, names []string) ([]models.ItemResolution, error) {
+	folded := make([]string, len(names))
+	for i, name := range names {
+		folded[i] = aliases.Fold(name)
+	}
+
+	matches := make(map[string]models.ItemResolution, len(names))
+	unmatched := func() []string {
+		var left []string
+		for _, name := range folded {
+			if _, ok := matches[name]; !ok && name != "" {
+				left = append(left, name)
+			}
+		}
+		return left
+	}
+	match := func(key string, item models.Item, by string) {
+		if _, ok := matches[key]; ok {
+			return
+		}
+		itemID := item.ItemID
+		matches[key] = models.ItemResolution{ItemID: &itemID, Name: item.Name, MatchedBy: by}
+	}
+
+	byName, err := s.itemRepo.GetByNames(ctx, unmatched())
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range byName {
+		match(aliases.Fold(item.Name), item, models.ItemMatchName)
+	}
+
+	if left := unmatched(); len(left) > 0 {
+		found, err := s.aliasRepo.FindApproved(ctx, left)
+		if err != nil {
+			return nil, err
+		}
+		itemIDs := make([]int, len(found))
+		for i, alias := range found {
+			itemIDs[i] = alias.ItemID
+		}
+		items, err := s.itemRepo.GetByItemIDs(ctx, itemIDs)
+		if err != nil {
+			return nil, err
+		}
+		itemsByID := make(map[int]models.Item, len(items))
+		for _, item := range items {
+			itemsByID[item.ItemID] = item
+		}
+		for _, alias := range found {
+			if item, ok := itemsByID[alias.ItemID]; ok {
+				match(alias.Alias, item, models.ItemMatchAlias)
+			}
+		}
+	}
+
+	if left := unmatched(); len(left) > 0 {
+		slugs := make(map[string][]string, len(left))
+		for _, name := range left {
+			if slug := aliases.Slug(name); slug != "" {
+				slugs[slug] = append(slugs[slug], name)
+			}
+		}
+		keys := make([]string, 0, len(slugs))
+		for slug := range slugs {
+			keys = append(keys, slug)
+		}
+		items, err := s.itemRepo.GetBySlugs(ctx, keys)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			for _, name := range slugs[aliases.Slug(item.Name)] {
+				match(name, item, models.ItemMatchSlug)
+			}
+		}
+	}
+
+	resolutions := make([]models.ItemResolution, len(names))
+	for i, name := range names {
+		resolution := matches[folded[i]]
+		resolution.Query = name
+		resolutions[i] = resolution
+	}
+	return resolutions, nil
+}
+
+// ItemAliases returns an item's approved aliases, or nil when the item does
+// not exist.
+func (s *itemAliasService) ItemAliases(ctx context.Context, itemID int) ([]models.ItemAlias, error) {
+	item, err := s.itemRepo.GetByItemID(ctx, itemID)
+	if err != nil || item == nil {
+		return nil, err
+	}
+	found, err := s.aliasRepo.ListForItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		found = []models.ItemAlias{}
+	}
+	return found, nil
+}
+
+// SubmitAlias records a user's alias for review. Returns nil when the item
+// does not exist.
+func (s *itemAliasService) SubmitAlias(ctx context.Context, userID string, itemID int, alias string) (*models.ItemAlias, error) {
+	itemAlias, err := s.newAlias(ctx, itemID, alias)
+	if err != nil || itemAlias == nil {
+		return nil, err
+	}
+	itemAlias.Status = models.ItemAliasStatusPending
+	itemAlias.Source = models.ItemAliasSourceUser
+	itemAlias.SubmittedBy = &userID
+	if err := s.aliasRepo.Create(ctx, itemAlias, models.MaxPendingItemAliases); err != nil {
+		return nil, err
+	}
+	return itemAlias, nil
+}
+
+// AddAlias adds an approved alias as an admin. Returns nil when the item
+// does not exist.
+func (s *itemAliasService) AddAlias(ctx context.Context, itemID int, alias string) (*models.ItemAlias, error) {
+	itemAlias, err := s.newAlias(ctx, itemID, alias)
+	if err != nil || itemAlias == nil {
+		return nil, err
+	}
+	itemAlias.Status = models.ItemAliasStatusApproved
+	itemAlias.Source = models.ItemAliasSourceAdmin
+	if err := s.aliasRepo.Create(ctx, itemAlias, 0); err != nil {
+		return nil, err
+	}
+	return itemAlias, nil
+}
+
+// newAlias validates alias for the item and returns it unsaved, or nil
+// when the item does not exist. An alias may not be any item's name, which
+// would always resolve to that item instead.
+func (s *itemAliasService) newAlias(ctx context.Context, itemID int, alias string) (*models.ItemAlias, error) {
+	alias, err := aliases.Normalize(alias)
+	if err != nil {
+		return nil, err
+	}
+	item, err := s.itemRepo.GetByItemID(ctx, itemID)
+	if err != nil || item == nil {
+		return nil, err
+	}
+	named, err := s.itemRepo.GetByNames(ctx, []string{alias})
+	if err != nil {
+		return nil, err
+	}
+	if len(named) > 0 {
+		return nil, fmt.Errorf("%w: alias is already the name of an item", models.ErrInvalidItemAlias)
+	}
+	return &models.ItemAlias{ItemID: itemID, Alias: alias}, nil
+}
+
+// ListAliases returns the oldest aliases in status, for moderation.
+func (s *itemAliasService) ListAliases(ctx context.Context, status string) ([]models.ItemAlias, error) {
+	switch status {
+	case models.ItemAliasStatusPending, models.ItemAliasStatusApproved, models.ItemAliasStatusRejected:
+	default:
+		return nil, fmt.Errorf("%w: status must be pending, approved or rejected", models.ErrInvalidItemAlias)
+	}
+	found, err := s.aliasRepo.List(ctx, status, itemAliasListLimit)
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		found = []models.ItemAlias{}
+	}
+	return found, nil
+}
+
+// ReviewAlias approves or rejects an alias and returns it, or nil when it
+// does not exist.
+func (s *itemAliasService) ReviewAlias(ctx context.Context, id uint, status string) (*models.ItemAlias, error) {
+	if status != models.ItemAliasStatusApproved && status != models.ItemAliasStatusRejected {
+		return nil, fmt.Errorf("%w: status must be approved or rejected", models.ErrInvalidItemAlias)
+	}
+	return s.aliasRepo.SetStatus(ctx, id, status)
+}
+
+// DeleteAlias removes an alias and reports whether it existed.
+func (s *itemAliasService) DeleteAlias(ctx context.Context, id uint) (bool, error) {
+	return s.aliasRepo.Delete(ctx, id)
+}
@@ -105,15 +105,17 @@ func (s *itemService) GetItemByItemID(ctx context.Context, itemID int) (*models.
 		return &item, nil
 	}
 
-	// Fetch from database
-	dbItem, err := s.itemRepo.GetByItemID(ctx, itemID)
+	// Fetch from database, with the item's category and tags
+	items, err := s.itemRepo.GetByItemIDs(ctx, []int{itemID})
 	if err != nil {
 		return nil, err
 	}
-	if dbItem != nil {
-		dbItem.IconURL = normalizeItemIconURL(dbItem.ItemID, dbItem.IconURL)
-		// Note: Intentionally not caching item here to reduce cache complexity
+	if len(items) == 0 {
+		return nil, nil
 	}
+	dbItem := &items[0]
+	dbItem.IconURL = normalizeItemIconURL(dbItem.ItemID, dbItem.IconURL)
+	// Note: Intentionally not caching item here to reduce cache complexity
 
 	return dbItem, nil
 }
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+	"github.com/guavi/osrs-ge-tracker/internal/tagging"
+)
+
+// itemTagService implements ItemTagService.
+type itemTagService struct {
+	classifier *tagging.Classifier
+	tagRepo    repository.ItemTagRepository
+	itemRepo   repository.ItemRepository
+	logger     *zap.SugaredLogger
+}
+
+// NewItemTagService creates a new item tag service.
+func NewItemTagService(
+	classifier *tagging.Classifier,
+	tagRepo repository.ItemTagRepository,
+	itemRepo repository.ItemRepository,
+	logger *zap.SugaredLogger,
+) ItemTagService {
+	return &itemTagService{
+		classifier: classifier,
+		tagRepo:    tagRepo,
+		itemRepo:   itemRepo,
+		logger:     logger,
+	}
+}
+
+// Categories returns the classifier's category names, sorted.
+func (s *itemTagService) Categories() []string {
+	return s.classifier.Categories()
+}
+
+// ListTags returns every tag in use with its item count.
+func (s *itemTagService) ListTags(ctx context.Context) ([]models.ItemTagCount, error) {
+	return s.tagRepo.CountTags(ctx)
+}
+
+// ItemTags returns an item's tags, including admin removals, or nil when
+// the item does not exist.
+func (s *itemTagService) ItemTags(ctx context.Context, itemID int) ([]models.ItemTag, error) {
+	item, err := s.itemRepo.GetByItemID(ctx, itemID)
+	if err != nil || item == nil {
+		return nil, err
+	}
+	tags, err := s.tagRepo.ListForItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if tags == nil {
+		tags = []models.ItemTag{}
+	}
+	return tags, nil
+}
+
+// AddTag tags an item as an admin. A tag named after a category sets the
+// item's category. Returns nil when the item does not exist.
+func (s *itemTagService) AddTag(ctx context.Context, itemID int, tag string) (*models.ItemTag, error) {
+	tag, err := tagging.NormalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	item, err := s.itemRepo.GetByItemID(ctx, itemID)
+	if err != nil || item == nil {
+		return nil, err
+	}
+
+	itemTag := &models.ItemTag{
+		ItemID: itemID,
+		Tag:    tag,
+		Kind:   s.classifier.Kind(tag),
+		Source: models.ItemTagSourceAdmin,
+	}
+	if err := s.tagRepo.Upsert(ctx, itemTag); err != nil {
+		return nil, err
+	}
+	return itemTag, nil
+}
+
+// RemoveTag removes an item's tag as an admin, so the classifier does not
+// add it back, and reports whether the item had it.
+func (s *itemTagService) RemoveTag(ctx context.Context, itemID int, tag string) (bool, error) {
+	tag, err := tagging.NormalizeTag(tag)
+	if err != nil {
+		return false, nil
+	}
+	return s.tagRepo.Remove(ctx, itemID, tag)
+}
+
+// ClassifyItems runs the classifier over every item and replaces its
+// earlier tags. Returns the number of tags assigned.
+func (s *itemTagService) ClassifyItems(ctx context.Context) (int, error) {
+	items, _, err := s.itemRepo.GetAll(ctx, models.ItemListParams{})
+	if err != nil {
+		return 0, err
+	}
+
+	var tags []models.ItemTag
+	for _, item := range items {
+		tags = append(tags, s.classifier.Classify(item)...)
+	}
+	if err := s.tagRepo.ReplaceAutoTags(ctx, tags); err != nil {
+		return 0, err
+	}
+	s.logger.Infow("Classified items", "items", len(items), "tags", len(tags))
+	return len(tags), nil
+}
@@ -0,0 +1,504 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/marketindex"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+// marketIndexSeedWindow is how far before an index's last value the 1h
+// table is read, so constituents have a previous price to move from.
+const marketIndexSeedWindow = 24 * time.Hour
+
+// marketIndexService implements MarketIndexService.
+type marketIndexService struct {
+	indexRepo    repository.MarketIndexRepository
+	itemRepo     repository.ItemRepository
+	priceRepo    repository.PriceRepository
+	priceService PriceService
+	logger       *zap.SugaredLogger
+	builtins     map[string]models.MarketIndex
+	ids          []string
+	mu           sync.Mutex
+	pending      map[string]models.MarketIndex
+}
+
+// NewMarketIndexService creates a new market index service over the loaded
+// builtin index definitions. priceService keeps the constituents' 1h
+// buckets current before each refresh.
+func NewMarketIndexService(
+	builtins []models.MarketIndex,
+	indexRepo repository.MarketIndexRepository,
+	itemRepo repository.ItemRepository,
+	priceRepo repository.PriceRepository,
+	priceService PriceService,
+	logger *zap.SugaredLogger,
+) MarketIndexService {
+	s := &marketIndexService{
+		indexRepo:    indexRepo,
+		itemRepo:     itemRepo,
+		priceRepo:    priceRepo,
+		priceService: priceService,
+		logger:       logger,
+		builtins:     make(map[string]models.MarketIndex, len(builtins)),
+		ids:          make([]string, 0, len(builtins)),
+		pending:      make(map[string]models.MarketIndex),
+	}
+	for _, index := range builtins {
+		s.builtins[index.ID] = index
+		s.ids = append(s.ids, index.ID)
+	}
+	return s
+}
+
+// ListIndices returns the builtin indices, or a user's custom indices when
+// userID is set, each with its latest value.
+func (s *marketIndexService) ListIndices(ctx context.Context, userID string) ([]models.MarketIndexSummary, error) {
+	var indices []models.MarketIndex
+	if userID == "" {
+		for _, id := range s.ids {
+			indices = append(indices, s.builtins[id])
+		}
+	} else {
+		custom, err := s.indexRepo.ListCustom(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range custom {
+			index, err := customIndex(c)
+			if err != nil {
+				return nil, err
+			}
+			indices = append(indices, index)
+		}
+	}
+
+	summaries := make([]models.MarketIndexSummary, 0, len(indices))
+	for _, index := range indices {
+		summary, err := s.Summary(ctx, index)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, *summary)
+	}
+	return summaries, nil
+}
+
+// GetIndex returns a builtin index, or one of a user's custom indices when
+// userID is set. Custom indices are looked up by their numeric ID. Returns
+// nil when there is no such index.
+func (s *marketIndexService) GetIndex(ctx context.Context, userID, id string) (*models.MarketIndex, error) {
+	if userID == "" {
+		index, ok := s.builtins[id]
+		if !ok {
+			return nil, nil
+		}
+		return &index, nil
+	}
+
+	customID, err := strconv.ParseInt(strings.TrimPrefix(id, models.MarketIndexSourceCustom+"-"), 10, 64)
+	if err != nil || customID <= 0 {
+		return nil, nil
+	}
+	custom, err := s.indexRepo.GetCustom(ctx, userID, customID)
+	if err != nil || custom == nil {
+		return nil, err
+	}
+	index, err := customIndex(*custom)
+	if err != nil {
+		return nil, err
+	}
+	return &index, nil
+}
+
+// Summary returns an index with its latest value and its change over the
+// last hour, day and week of stored values.
+func (s *marketIndexService) Summary(ctx context.Context, index models.MarketIndex) (*models.MarketIndexSummary, error) {
+	summary := &models.MarketIndexSummary{MarketIndex: index}
+	latest, err := s.indexRepo.LatestValue(ctx, index.ID)
+	if err != nil || latest == nil {
+		return summary, err
+	}
+	summary.Timestamp = &latest.Timestamp
+	summary.Value = &latest.Value
+
+	week := 7 * 24 * time.Hour
+	values, err := s.indexRepo.ListValues(ctx, index.ID, latest.Timestamp.Add(-week-time.Hour), latest.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	summary.Change1h = indexChange(values, *latest, time.Hour)
+	summary.Change24h = indexChange(values, *latest, 24*time.Hour)
+	summary.Change7d = indexChange(values, *latest, week)
+	return summary, nil
+}
+
+// History returns an index's stored values between from and to. A zero to
+// means now and a zero from means DefaultMarketIndexHistoryRange before to.
+func (s *marketIndexService) History(
+	ctx context.Context,
+	index models.MarketIndex,
+	from, to time.Time,
+) (*models.MarketIndexHistory, error) {
+	now := time.Now().UTC()
+	if to.IsZero() || to.After(now) {
+		to = now
+	}
+	if from.IsZero() {
+		from = to.Add(-models.DefaultMarketIndexHistoryRange)
+	}
+	switch {
+	case !from.Before(to):
+		return nil, invalidMarketIndex("from must be before to")
+	case to.Sub(from) > models.MaxMarketIndexHistoryRange:
+		return nil, invalidMarketIndex("range must span at most %d days", int(models.MaxMarketIndexHistoryRange/(24*time.Hour)))
+	}
+
+	values, err := s.indexRepo.ListValues(ctx, index.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &models.MarketIndexHistory{IndexID: index.ID, From: from, To: to, Points: values}, nil
+}
+
+// Constituents returns an index's items with their weight, price and volume
+// over the last 24 hours of 1h buckets, heaviest first.
+func (s *marketIndexService) Constituents(ctx context.Context, index models.MarketIndex) (*models.MarketIndexConstituents, error) {
+	now := time.Now().UTC()
+	since := now.Add(-24 * time.Hour)
+	series, err := s.hourlySeries(ctx, index.ItemIDs, func(int) time.Time { return since })
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.itemRepo.GetByItemIDs(ctx, index.ItemIDs)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int]string, len(items))
+	for _, item := range items {
+		names[item.ItemID] = item.Name
+	}
+
+	weights := marketindex.Weights(index.Weighting, series)
+	result := &models.MarketIndexConstituents{
+		ComputedAt:   now,
+		IndexID:      index.ID,
+		Weighting:    index.Weighting,
+		Constituents: make([]models.MarketIndexConstituent, 0, len(index.ItemIDs)),
+	}
+	for _, itemID := range index.ItemIDs {
+		c := models.MarketIndexConstituent{ItemID: itemID, Name: names[itemID], Weight: weights[itemID]}
+		points := series[itemID]
+		sort.Slice(points, func(i, j int) bool { return points[i].Timestamp.Before(points[j].Timestamp) })
+		var first int64
+		for _, p := range points {
+			c.Volume24h += p.HighPriceVolume + p.LowPriceVolume
+			mid, ok := marketindex.Mid(p)
+			if !ok {
+				continue
+			}
+			if first == 0 {
+				first = mid
+			}
+			c.Price = &mid
+		}
+		if c.Price != nil && first > 0 {
+			change := (float64(*c.Price)/float64(first) - 1) * 100
+			c.Change24h = &change
+		}
+		result.Constituents = append(result.Constituents, c)
+	}
+	sort.SliceStable(result.Constituents, func(i, j int) bool {
+		return result.Constituents[i].Weight > result.Constituents[j].Weight
+	})
+	return result, nil
+}
+
+// CreateCustomIndex stores a custom index for a user and queues it for
+// BackfillNewIndices, so its history appears within a minute rather than
+// holding up the request.
+func (s *marketIndexService) CreateCustomIndex(ctx context.Context, userID string, index models.MarketIndex) (*models.MarketIndex, error) {
+	if err := marketindex.Validate(&index); err != nil {
+		return nil, err
+	}
+	items, err := s.itemRepo.GetByItemIDs(ctx, index.ItemIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) < len(index.ItemIDs) {
+		known := make(map[int]struct{}, len(items))
+		for _, item := range items {
+			known[item.ItemID] = struct{}{}
+		}
+		var unknown []string
+		for _, id := range index.ItemIDs {
+			if _, ok := known[id]; !ok {
+				unknown = append(unknown, strconv.Itoa(id))
+			}
+		}
+		return nil, invalidMarketIndex("unknown item IDs: %s", strings.Join(unknown, ", "))
+	}
+
+	itemIDs, err := json.Marshal(index.ItemIDs)
+	if err != nil {
+		return nil, fmt.Errorf("encode item IDs: %w", err)
+	}
+	custom := models.CustomMarketIndex{
+		UserID:    userID,
+		Name:      index.Name,
+		Weighting: string(index.Weighting),
+		ItemIDs:   itemIDs,
+	}
+	created, err := s.indexRepo.CreateCustom(ctx, &custom, models.MaxCustomMarketIndices)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, invalidMarketIndex("at most %d custom indices can be defined; delete one first", models.MaxCustomMarketIndices)
+	}
+
+	result, err := customIndex(custom)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.pending[result.ID] = result
+	s.mu.Unlock()
+	return &result, nil
+}
+
+// DeleteCustomIndex removes one of a user's custom indices and reports
+// whether it existed.
+func (s *marketIndexService) DeleteCustomIndex(ctx context.Context, userID, id string) (bool, error) {
+	customID, err := strconv.ParseInt(strings.TrimPrefix(id, models.MarketIndexSourceCustom+"-"), 10, 64)
+	if err != nil || customID <= 0 {
+		return false, nil
+	}
+	deleted, err := s.indexRepo.DeleteCustom(ctx, userID, customID)
+	if deleted {
+		s.mu.Lock()
+		delete(s.pending, models.CustomMarketIndexID(customID))
+		s.mu.Unlock()
+	}
+	return deleted, err
+}
+
+// BackfillNewIndices computes the history of the custom indices created
+// since it last ran, from stored 1h buckets only. Indices whose values fail
+// to store are left to the next RefreshIndices. Returns the number of values
+// stored.
+func (s *marketIndexService) BackfillNewIndices(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	indices := make([]models.MarketIndex, 0, len(s.pending))
+	for _, index := range s.pending {
+		indices = append(indices, index)
+	}
+	clear(s.pending)
+	s.mu.Unlock()
+
+	if len(indices) == 0 {
+		return 0, nil
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i].ID < indices[j].ID })
+	return s.refresh(ctx, indices, nil)
+}
+
+// RefreshIndices extends every builtin and custom index over the 1h buckets
+// stored since it was last computed. Returns the number of values stored.
+func (s *marketIndexService) RefreshIndices(ctx context.Context) (int, error) {
+	indices := make([]models.MarketIndex, 0, len(s.ids))
+	for _, id := range s.ids {
+		indices = append(indices, s.builtins[id])
+	}
+	custom, err := s.indexRepo.ListAllCustom(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range custom {
+		index, err := customIndex(c)
+		if err != nil {
+			s.logger.Warnw("Skipping unreadable custom market index", "id", c.ID, "error", err)
+			continue
+		}
+		indices = append(indices, index)
+	}
+	return s.refresh(ctx, indices, s.syncedItems(indices))
+}
+
+// syncedItems picks the constituents whose 1h buckets a refresh syncs from
+// the Wiki: every builtin constituent, plus up to MaxSyncedCustomIndexItems
+// items held only by custom indices, those in the most indices first.
+func (s *marketIndexService) syncedItems(indices []models.MarketIndex) []int {
+	builtin := make(map[int]struct{})
+	for _, id := range s.ids {
+		for _, itemID := range s.builtins[id].ItemIDs {
+			builtin[itemID] = struct{}{}
+		}
+	}
+	ids := make([]int, 0, len(builtin))
+	for itemID := range builtin {
+		ids = append(ids, itemID)
+	}
+	sort.Ints(ids)
+
+	uses := make(map[int]int)
+	for _, index := range indices {
+		if index.Source != models.MarketIndexSourceCustom {
+			continue
+		}
+		for _, itemID := range index.ItemIDs {
+			if _, ok := builtin[itemID]; !ok {
+				uses[itemID]++
+			}
+		}
+	}
+	custom := make([]int, 0, len(uses))
+	for itemID := range uses {
+		custom = append(custom, itemID)
+	}
+	sort.Slice(custom, func(i, j int) bool {
+		if uses[custom[i]] != uses[custom[j]] {
+			return uses[custom[i]] > uses[custom[j]]
+		}
+		return custom[i] < custom[j]
+	})
+	if len(custom) > models.MaxSyncedCustomIndexItems {
+		s.logger.Infow("Syncing only the most used custom market index items",
+			"items", len(custom), "synced", models.MaxSyncedCustomIndexItems)
+		custom = custom[:models.MaxSyncedCustomIndexItems]
+	}
+	return append(ids, custom...)
+}
+
+// refresh extends each index from its last stored value, or backfills it
+// from MarketIndexBackfill ago. The items in synced have their 1h buckets
+// synced from the Wiki first; each constituent's buckets are then read once,
+// from the earliest time any of its indices needs.
+func (s *marketIndexService) refresh(ctx context.Context, indices []models.MarketIndex, synced []int) (int, error) {
+	now := time.Now().UTC()
+	lasts := make([]*models.MarketIndexValue, len(indices))
+	starts := make(map[int]time.Time)
+	ids := make([]int, 0)
+	for i, index := range indices {
+		last, err := s.indexRepo.LatestValue(ctx, index.ID)
+		if err != nil {
+			return 0, err
+		}
+		lasts[i] = last
+		start := now.Add(-models.MarketIndexBackfill)
+		if last != nil {
+			start = last.Timestamp.Add(-marketIndexSeedWindow)
+		}
+		for _, itemID := range index.ItemIDs {
+			earliest, seen := starts[itemID]
+			if !seen {
+				ids = append(ids, itemID)
+			}
+			if !seen || start.Before(earliest) {
+				starts[itemID] = start
+			}
+		}
+	}
+
+	for _, itemID := range synced {
+		if _, used := starts[itemID]; !used {
+			continue
+		}
+		if err := s.priceService.SyncTimeseries(ctx, itemID, "1h"); err != nil {
+			// Stored buckets still move the index; the next refresh retries.
+			s.logger.Warnw("Failed to sync 1h timeseries for market index", "itemID", itemID, "error", err)
+		}
+	}
+	series, err := s.hourlySeries(ctx, ids, func(itemID int) time.Time { return starts[itemID] })
+	if err != nil {
+		return 0, err
+	}
+
+	stored := 0
+	var errs []error
+	for i, index := range indices {
+		subset := make(map[int][]models.PriceTimeseriesPoint, len(index.ItemIDs))
+		for _, itemID := range index.ItemIDs {
+			subset[itemID] = series[itemID]
+		}
+		values := marketindex.Compute(index.ID, index.Weighting, subset, lasts[i])
+		if err := s.indexRepo.UpsertValues(ctx, values); err != nil {
+			errs = append(errs, fmt.Errorf("index %s: %w", index.ID, err))
+			continue
+		}
+		stored += len(values)
+	}
+	return stored, errors.Join(errs...)
+}
+
+// hourlySeries reads each item's 1h points since the time start returns
+// for it, in one query from the earliest start.
+func (s *marketIndexService) hourlySeries(
+	ctx context.Context,
+	itemIDs []int,
+	start func(itemID int) time.Time,
+) (map[int][]models.PriceTimeseriesPoint, error) {
+	series := make(map[int][]models.PriceTimeseriesPoint, len(itemIDs))
+	if len(itemIDs) == 0 {
+		return series, nil
+	}
+	from := start(itemIDs[0])
+	for _, itemID := range itemIDs[1:] {
+		if since := start(itemID); since.Before(from) {
+			from = since
+		}
+	}
+
+	points, err := s.priceRepo.GetTimeseriesPointsForItems(ctx, itemIDs, "1h", from, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range points {
+		if !p.Timestamp.Before(start(p.ItemID)) {
+			series[p.ItemID] = append(series[p.ItemID], p)
+		}
+	}
+	return series, nil
+}
+
+// customIndex converts a stored custom index into its definition.
+func customIndex(c models.CustomMarketIndex) (models.MarketIndex, error) {
+	index := models.MarketIndex{
+		ID:        models.CustomMarketIndexID(c.ID),
+		Name:      c.Name,
+		Weighting: models.MarketIndexWeighting(c.Weighting),
+		Source:    models.MarketIndexSourceCustom,
+	}
+	if err := json.Unmarshal(c.ItemIDs, &index.ItemIDs); err != nil {
+		return index, fmt.Errorf("decode custom market index %d: %w", c.ID, err)
+	}
+	return index, nil
+}
+
+// indexChange returns the percentage change from the last value at least d
+// before latest, or nil when values do not reach back that far.
+func indexChange(values []models.MarketIndexValue, latest models.MarketIndexValue, d time.Duration) *float64 {
+	cutoff := latest.Timestamp.Add(-d)
+	i := sort.Search(len(values), func(i int) bool { return values[i].Timestamp.After(cutoff) })
+	if i == 0 || values[i-1].Value <= 0 {
+		return nil
+	}
+	change := (latest.Value/values[i-1].Value - 1) * 100
+	return &change
+}
+
+func invalidMarketIndex(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", models.ErrInvalidMarketIndex, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+// marketTableService implements MarketTableService. Rows come from the
+// screener's market snapshot, so they are built once per price sync.
+type marketTableService struct {
+	statsRepo       repository.ItemStatsRepository
+	screenerService ScreenerService
+	logger          *zap.SugaredLogger
+	mu              sync.Mutex
+	// updated is the refresh time of the stored table, served to every
+	// request without touching the database.
+	updated atomic.Pointer[time.Time]
+}
+
+// NewMarketTableService creates a new market table service.
+func NewMarketTableService(
+	statsRepo repository.ItemStatsRepository,
+	screenerService ScreenerService,
+	logger *zap.SugaredLogger,
+) MarketTableService {
+	return &marketTableService{
+		statsRepo:       statsRepo,
+		screenerService: screenerService,
+		logger:          logger,
+	}
+}
+
+// Refresh replaces the stored table with the rows of the screener's market
+// snapshot. A snapshot this service already stored is not written again.
+func (s *marketTableService) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := time.Now()
+	rows, asOf, err := s.screenerService.Snapshot(ctx)
+	if err != nil {
+		return err
+	}
+	if updated := s.updated.Load(); updated != nil && !asOf.After(*updated) {
+		return nil
+	}
+
+	stats := make([]models.ItemStats, len(rows))
+	for i, row := range rows {
+		stats[i] = models.ItemStats{
+			ItemID:    row.ItemID,
+			Name:      row.Name,
+			IconURL:   row.IconURL,
+			Members:   row.Members,
+			Category:  row.Category,
+			BuyLimit:  row.BuyLimit,
+			HighPrice: row.HighPrice,
+			LowPrice:  row.LowPrice,
+			Spread:    row.Spread,
+			Change1h:  row.Change1h,
+			Change24h: row.Change24h,
+			Change7d:  row.Change7d,
+			Volume1h:  row.Volume1h,
+			Volume24h: row.Volume24h,
+		}
+	}
+	if err := s.statsRepo.Replace(ctx, stats, asOf); err != nil {
+		return err
+	}
+	s.updated.Store(&asOf)
+
+	s.logger.Debugw("Refreshed market table",
+		"items", len(stats),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// LastUpdated returns the time of the last refresh, or nil before the first
+// one. The time is kept in memory; the table is only asked for it after a
+// restart, until a refresh time is known.
+func (s *marketTableService) LastUpdated(ctx context.Context) (*time.Time, error) {
+	if updated := s.updated.Load(); updated != nil {
+		t := *updated
+		return &t, nil
+	}
+	updated, err := s.statsRepo.LastUpdated(ctx)
+	if err != nil || updated == nil {
+		return nil, err
+	}
+	s.updated.CompareAndSwap(nil, updated)
+	t := *s.updated.Load()
+	return &t, nil
+}
+
+// Query returns one page of the table and the total number of matching rows.
+func (s *marketTableService) Query(ctx context.Context, params models.MarketTableParams) ([]models.ItemStats, int64, error) {
+	stats, total, err := s.statsRepo.Query(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	if stats == nil {
+		stats = []models.ItemStats{}
+	}
+	return stats, total, nil
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
+)
+
+// maxPaperAccountNameLength matches the paper_accounts.name column.
+const maxPaperAccountNameLength = 100
+
+// paperTradingService implements PaperTradingService.
+type paperTradingService struct {
+	paperRepo repository.PaperTradingRepository
+	priceRepo repository.PriceRepository
+	itemRepo  repository.ItemRepository
+	logger    *zap.SugaredLogger
+}
+
+// NewPaperTradingService creates a new paper trading service.
+func NewPaperTradingService(
+	paperRepo repository.PaperTradingRepository,
+	priceRepo repository.PriceRepository,
+	itemRepo repository.ItemRepository,
+	logger *zap.SugaredLogger,
+) PaperTradingService {
+	return &paperTradingService{
+		paperRepo: paperRepo,
+		priceRepo: priceRepo,
+		itemRepo:  itemRepo,
+		logger:    logger,
+	}
+}
+
+func invalidPaperRequest(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", models.ErrInvalidPaperRequest, fmt.Sprintf(format, args...))
+}
+
+// CreateAccount opens a paper account for a user funded with startingBalance
+// GP. A zero balance means models.DefaultPaperStartingBalance. Returns
+// models.ErrPaperAccountLimit once the user has MaxPaperAccountsPerUser
+// accounts.
+func (s *paperTradingService) CreateAccount(ctx context.Context, userID, name string, startingBalance int64) (*models.PaperAccount, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, invalidPaperRequest("name is required")
+	}
+	if len(name) > maxPaperAccountNameLength {
+		return nil, invalidPaperRequest("name must be at most %d characters", maxPaperAccountNameLength)
+	}
+	if startingBalance == 0 {
+		startingBalance = models.DefaultPaperStartingBalance
+	}
+	if startingBalance < 0 || startingBalance > models.MaxPaperAmount {
+		return nil, invalidPaperRequest("startingBalance must be between 1 and %d", models.MaxPaperAmount)
+	}
+
+	account := &models.PaperAccount{
+		ID:              uuid.New().String(),
+		UserID:          userID,
+		Name:            name,
+		StartingBalance: startingBalance,
+		Cash:            startingBalance,
+	}
+	created, err := s.paperRepo.CreateAccount(ctx, account, models.MaxPaperAccountsPerUser)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, models.ErrPaperAccountLimit
+	}
+
+	s.logger.Infow("Paper account created", "id", account.ID, "user_id", userID, "starting_balance", startingBalance)
+	return account, nil
+}
+
+// GetAccount returns an account with its marked positions and open offers,
+// or nil when it does not exist.
+func (s *paperTradingService) GetAccount(ctx context.Context, id string) (*models.PaperAccountSummary, error) {
+	account, err := s.getAccount(ctx, id)
+	if err != nil || account == nil {
+		return nil, err
+	}
+
+	positions, err := s.positionViews(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+	offers, err := s.paperRepo.ListOffers(ctx, account.ID, models.PaperOfferOpen, models.MaxPaperOpenOffers)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.PaperAccountSummary{
+		PaperAccount: *account,
+		Positions:    positions,
+		OpenOffers:   offers,
+	}
+	for _, o := range offers {
+		if o.Side == models.PaperOfferBuy {
+			summary.Escrow += o.Remaining() * o.Price
+		}
+	}
+	summary.Equity = account.Cash + summary.Escrow
+	for _, p := range positions {
+		if p.MarketValue != nil {
+			summary.Equity += *p.MarketValue
+		} else {
+			summary.Equity += p.CostBasis
+		}
+	}
+	summary.ProfitLoss = summary.Equity - account.StartingBalance
+	return summary, nil
+}
+
+// PlaceOffer validates and places a simulated offer. It fills on later price
+// syncs that cross its price.
+func (s *paperTradingService) PlaceOffer(ctx context.Context, accountID string, req models.PaperOfferRequest) (*models.PaperOffer, error) {
+	account, err := s.getAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, models.ErrPaperAccountNotFound
+	}
+
+	if !req.Side.IsValid() {
+		return nil, invalidPaperRequest("side must be one of: buy, sell")
+	}
+	if req.Price < 1 || req.Price > models.MaxPaperAmount {
+		return nil, invalidPaperRequest("price must be between 1 and %d", models.MaxPaperAmount)
+	}
+	if req.Quantity < 1 || req.Quantity > models.MaxPaperAmount {
+		return nil, invalidPaperRequest("quantity must be between 1 and %d", models.MaxPaperAmount)
+	}
+	if req.ItemID <= 0 {
+		return nil, invalidPaperRequest("itemId is required")
+	}
+	item, err := s.itemRepo.GetByItemID(ctx, req.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, invalidPaperRequest("unknown item ID: %d", req.ItemID)
+	}
+
+	offer := &models.PaperOffer{
+		AccountID: account.ID,
+		ItemID:    req.ItemID,
+		Side:      req.Side,
+		Status:    models.PaperOfferOpen,
+		Price:     req.Price,
+		Quantity:  req.Quantity,
+	}
+	if err := s.paperRepo.PlaceOffer(ctx, offer); err != nil {
+		return nil, err
+	}
+	return offer, nil
+}
+
+// CancelOffer cancels an open offer, or returns nil when the account has no
+// such offer.
+func (s *paperTradingService) CancelOffer(ctx context.Context, accountID string, offerID int64) (*models.PaperOffer, error) {
+	if _, err := uuid.Parse(accountID); err != nil {
+		return nil, nil
+	}
+	return s.paperRepo.CancelOffer(ctx, accountID, offerID)
+}
+
+// ListOffers returns an account's offers, newest first.
+func (s *paperTradingService) ListOffers(
+	ctx context.Context,
+	accountID string,
+	status models.PaperOfferStatus,
+	limit int,
+) ([]models.PaperOffer, error) {
+	if err := s.requireAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return s.paperRepo.ListOffers(ctx, accountID, status, limit)
+}
+
+// ListPositions returns an account's positions marked to current prices.
+func (s *paperTradingService) ListPositions(ctx context.Context, accountID string) ([]models.PaperPositionView, error) {
+	if err := s.requireAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return s.positionViews(ctx, accountID)
+}
+
+// ListFills returns an account's most recent fills.
+func (s *paperTradingService) ListFills(ctx context.Context, accountID string, limit int) ([]models.PaperFill, error) {
+	if err := s.requireAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return s.paperRepo.ListFills(ctx, accountID, limit)
+}
+
+// ProcessPriceUpdates fills open offers crossed by freshly synced prices and
+// returns the fills. Offers fill oldest first at their own price, as a
+// standing Grand Exchange offer would. A failed fill is logged and skipped so
+// one bad offer cannot hold up the rest.
+func (s *paperTradingService) ProcessPriceUpdates(ctx context.Context, updates []models.BulkPriceUpdate) ([]models.PaperFill, error) {
+	byItem := make(map[int]models.BulkPriceUpdate, len(updates))
+	itemIDs := make([]int, 0, len(updates))
+	for _, u := range updates {
+		if _, seen := byItem[u.ItemID]; !seen {
+			itemIDs = append(itemIDs, u.ItemID)
+		}
+		byItem[u.ItemID] = u
+	}
+
+	offers, err := s.paperRepo.ListOpenOffersForItems(ctx, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	crossed := make([]models.PaperOffer, 0)
+	at := make(map[int64]time.Time)
+	buyItems := make([]int, 0)
+	for _, offer := range offers {
+		ts, ok := offer.CrossedBy(byItem[offer.ItemID])
+		if !ok {
+			continue
+		}
+		crossed = append(crossed, offer)
+		at[offer.ID] = ts
+		if offer.Side == models.PaperOfferBuy {
+			buyItems = append(buyItems, offer.ItemID)
+		}
+	}
+	limits := s.buyLimits(ctx, buyItems)
+
+	fills := make([]models.PaperFill, 0)
+	for _, offer := range crossed {
+		limit := 0
+		if offer.Side == models.PaperOfferBuy {
+			limit = limits[offer.ItemID]
+		}
+		fill, err := s.paperRepo.FillOffer(ctx, offer.ID, at[offer.ID], limit)
+		if err != nil {
+			s.logger.Warnw("Failed to fill paper offer", "offer_id", offer.ID, "error", err)
+			continue
+		}
+		if fill != nil {
+			fills = append(fills, *fill)
+		}
+	}
+
+	if len(fills) > 0 {
+		s.logger.Infow("Paper offers filled", "fills", len(fills), "open_offers", len(offers))
+	}
+	return fills, nil
+}
+
+// buyLimits returns the buy limits of itemIDs in one lookup. Items without a
+// known limit are missing, which fills without one; so does a failed lookup.
+func (s *paperTradingService) buyLimits(ctx context.Context, itemIDs []int) map[int]int {
+	limits := make(map[int]int)
+	if len(itemIDs) == 0 {
+		return limits
+	}
+	items, err := s.itemRepo.GetByItemIDs(ctx, itemIDs)
+	if err != nil {
+		s.logger.Warnw("Failed to load buy limits; filling without them", "items", len(itemIDs), "error", err)
+		return limits
+	}
+	for _, item := range items {
+		if item.BuyLimit != nil {
+			limits[item.ItemID] = *item.BuyLimit
+		}
+	}
+	return limits
+}
+
+// positionViews loads an account's positions and marks them to the current
+// instant-buy price after GE tax.
+func (s *paperTradingService) positionViews(ctx context.Context, accountID string) ([]models.PaperPositionView, error) {
+	positions, err := s.paperRepo.ListPositions(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	itemIDs := make([]int, len(positions))
+	for i, p := range positions {
+		itemIDs[i] = p.ItemID
+	}
+	prices := make(map[int]models.CurrentPrice, len(itemIDs))
+	items := make(map[int]models.Item, len(itemIDs))
+	if len(itemIDs) > 0 {
+		current, err := s.priceRepo.GetCurrentPrices(ctx, itemIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range current {
+			prices[p.ItemID] = p
+		}
+		itemList, err := s.itemRepo.GetByItemIDs(ctx, itemIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range itemList {
+			items[item.ItemID] = item
+		}
+	}
+
+	now := time.Now().UTC()
+	views := make([]models.PaperPositionView, 0, len(positions))
+	for _, p := range positions {
+		view := models.PaperPositionView{PaperPosition: p}
+		if item, ok := items[p.ItemID]; ok {
+			view.ItemName = item.Name
+			if item.BuyLimit != nil {
+				if remaining := p.BuyAllowance(*item.BuyLimit, now); remaining >= 0 {
+					view.BuyLimitRemaining = &remaining
+				}
+			}
+		}
+		if price, ok := prices[p.ItemID]; ok && price.HighPrice != nil {
+			mark := *price.HighPrice
+			value := p.Quantity * (mark - utils.GETax(p.ItemID, mark))
+			unrealized := value - p.CostBasis
+			view.MarkPrice = &mark
+			view.MarketValue = &value
+			view.UnrealizedProfit = &unrealized
+		}
+		views = append(views, view)
+	}
+	return views, nil
+}
+
+// getAccount returns an account, or nil when id is not a known account.
+func (s *paperTradingService) getAccount(ctx context.Context, id string) (*models.PaperAccount, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, nil
+	}
+	return s.paperRepo.GetAccount(ctx, id)
+}
+
+// requireAccount returns models.ErrPaperAccountNotFound when id is not a known account.
+func (s *paperTradingService) requireAccount(ctx context.Context, id string) error {
+	account, err := s.getAccount(ctx, id)
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		return models.ErrPaperAccountNotFound
+	}
+	return nil
+}
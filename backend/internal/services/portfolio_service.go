@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/portfolio"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+// portfolioService implements PortfolioService.
+type portfolioService struct {
+	journalRepo repository.TradeJournalRepository
+	priceRepo   repository.PriceRepository
+	itemRepo    repository.ItemRepository
+	logger      *zap.SugaredLogger
+}
+
+// NewPortfolioService creates a new portfolio service.
+func NewPortfolioService(
+	journalRepo repository.TradeJournalRepository,
+	priceRepo repository.PriceRepository,
+	itemRepo repository.ItemRepository,
+	logger *zap.SugaredLogger,
+) PortfolioService {
+	return &portfolioService{
+		journalRepo: journalRepo,
+		priceRepo:   priceRepo,
+		itemRepo:    itemRepo,
+		logger:      logger,
+	}
+}
+
+// AddEntry validates and records one trade for a user; an unset TradedAt
+// means now. Validation errors wrap models.ErrInvalidJournalEntry.
+func (s *portfolioService) AddEntry(ctx context.Context, userID string, entry models.TradeJournalEntry) (*models.TradeJournalEntry, error) {
+	now := time.Now().UTC()
+	if entry.TradedAt.IsZero() {
+		entry.TradedAt = now
+	}
+	if err := portfolio.Validate(&entry, now); err != nil {
+		return nil, err
+	}
+	if _, err := s.itemNames(ctx, []int{entry.ItemID}, true); err != nil {
+		return nil, err
+	}
+
+	entry.ID = 0
+	entry.UserID = userID
+	if err := s.journalRepo.Create(ctx, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// ListEntries returns a page of a user's journal, newest first, and the
+// number of entries matching the filters.
+func (s *portfolioService) ListEntries(
+	ctx context.Context,
+	userID string,
+	params models.JournalListParams,
+) ([]models.TradeJournalEntry, int64, error) {
+	return s.journalRepo.List(ctx, userID, params)
+}
+
+// DeleteEntry removes one of a user's entries and reports whether it existed.
+func (s *portfolioService) DeleteEntry(ctx context.Context, userID string, id int64) (bool, error) {
+	return s.journalRepo.Delete(ctx, userID, id)
+}
+
+// ImportCSV appends the trades in a journal CSV to a user's journal and
+// returns how many were imported. Nothing is stored unless every row is valid.
+func (s *portfolioService) ImportCSV(ctx context.Context, userID string, r io.Reader) (int, error) {
+	entries, err := portfolio.ReadCSV(r, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := s.itemNames(ctx, distinctItemIDs(entries), true); err != nil {
+		return 0, err
+	}
+	for i := range entries {
+		entries[i].UserID = userID
+	}
+	if err := s.journalRepo.CreateBatch(ctx, entries); err != nil {
+		return 0, err
+	}
+
+	s.logger.Infow("Journal imported", "user_id", userID, "entries", len(entries))
+	return len(entries), nil
+}
+
+// ExportCSV writes a user's whole journal as CSV in trade order.
+func (s *portfolioService) ExportCSV(ctx context.Context, userID string, w io.Writer) error {
+	entries, err := s.journalRepo.ListAll(ctx, userID)
+	if err != nil {
+		return err
+	}
+	names, err := s.itemNames(ctx, distinctItemIDs(entries), false)
+	if err != nil {
+		return err
+	}
+	return portfolio.WriteCSV(w, entries, names)
+}
+
+// GetSummary computes FIFO profit and loss over a user's whole journal,
+// marking open lots to current prices.
+func (s *portfolioService) GetSummary(ctx context.Context, userID string) (*models.PortfolioSummary, error) {
+	entries, err := s.journalRepo.ListAll(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	itemIDs := distinctItemIDs(entries)
+	marks := make(map[int]int64, len(itemIDs))
+	if len(itemIDs) > 0 {
+		prices, err := s.priceRepo.GetCurrentPrices(ctx, itemIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range prices {
+			if p.HighPrice != nil {
+				marks[p.ItemID] = *p.HighPrice
+			}
+		}
+	}
+
+	summary := portfolio.Summarize(entries, marks)
+	names, err := s.itemNames(ctx, itemIDs, false)
+	if err != nil {
+		return nil, err
+	}
+	for i := range summary.Items {
+		summary.Items[i].ItemName = names[summary.Items[i].ItemID]
+	}
+	return &summary, nil
+}
+
+// itemNames looks up item names in one query. With requireAll, an unknown
+// item is a validation error.
+func (s *portfolioService) itemNames(ctx context.Context, itemIDs []int, requireAll bool) (map[int]string, error) {
+	items, err := s.itemRepo.GetByItemIDs(ctx, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int]string, len(items))
+	for _, item := range items {
+		names[item.ItemID] = item.Name
+	}
+	if requireAll {
+		for _, id := range itemIDs {
+			if _, ok := names[id]; !ok {
+				return nil, invalidJournalEntry("unknown item ID: %d", id)
+			}
+		}
+	}
+	return names, nil
+}
+
+func invalidJournalEntry(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", models.ErrInvalidJournalEntry, fmt.Sprintf(format, args...))
+}
+
+// distinctItemIDs returns the sorted item IDs appearing in entries.
+func distinctItemIDs(entries []models.TradeJournalEntry) []int {
+	seen := make(map[int]struct{})
+	ids := make([]int, 0)
+	for _, e := range entries {
+		if _, ok := seen[e.ItemID]; !ok {
+			seen[e.ItemID] = struct{}{}
+			ids = append(ids, e.ItemID)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
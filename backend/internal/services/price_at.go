@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// GetPricesAt resolves each item's price at ts using the finest stored
+// resolution that covers it: price_latest, then 5m, 1h, 6h, 24h and finally
+// the daily rollup. Items with no covering row at any resolution are omitted.
+// Results are ordered by item ID.
+func (s *priceService) GetPricesAt(ctx context.Context, itemIDs []int, ts time.Time) ([]models.PriceAt, error) {
+	ts = ts.UTC()
+	results := make([]models.PriceAt, 0, len(itemIDs))
+
+	remaining := make(map[int]struct{}, len(itemIDs))
+	for _, id := range itemIDs {
+		remaining[id] = struct{}{}
+	}
+
+	for _, resolution := range models.PointInTimeResolutions {
+		if len(remaining) == 0 {
+			break
+		}
+
+		pending := make([]int, 0, len(remaining))
+		for id := range remaining {
+			pending = append(pending, id)
+		}
+		sort.Ints(pending)
+
+		prices, err := s.priceRepo.GetPricesAt(ctx, pending, resolution, ts)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range prices {
+			if _, ok := remaining[p.ItemID]; !ok {
+				continue
+			}
+			delete(remaining, p.ItemID)
+			results = append(results, p)
+		}
+	}
+
+	if len(remaining) > 0 {
+		s.logger.Debugw("No stored price covers timestamp",
+			"ts", ts,
+			"missing", len(remaining),
+		)
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].ItemID < results[j].ItemID })
+	return results, nil
+}
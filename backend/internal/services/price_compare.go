@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
+)
+
+// compareIndexBase is the value every rebased series starts at.
+const compareIndexBase = 100.0
+
+// comparePoint is a single observation of the selected price field.
+type comparePoint struct {
+	Value     *float64
+	Timestamp time.Time
+}
+
+// timestepDuration returns the bucket width for a timeseries timestep.
+func timestepDuration(timestep string) time.Duration {
+	switch timestep {
+	case "5m":
+		return 5 * time.Minute
+	case "1h":
+		return time.Hour
+	case "6h":
+		return 6 * time.Hour
+	default:
+		return 24 * time.Hour
+	}
+}
+
+// ComparePrices aligns several items onto a common timestamp grid and returns
+// a rebased index (first value = 100) per item plus optional A/B ratio series.
+// Missing buckets are forward-filled from the previous observation.
+func (s *priceService) ComparePrices(ctx context.Context, params models.PriceCompareParams) (*models.PriceCompareResponse, error) {
+	if len(params.ItemIDs) == 0 {
+		return nil, fmt.Errorf("at least one item ID is required")
+	}
+	if params.Period == "" {
+		params.Period = models.Period30Days
+	}
+	if params.Field == "" {
+		params.Field = models.PriceFieldMid
+	}
+
+	source := periodToTimeseriesSource(params.Period)
+	step := timestepDuration(source.timestep)
+
+	end := time.Now().UTC()
+	if params.EndTime != nil {
+		end = params.EndTime.UTC()
+	}
+	var start *time.Time
+	if params.StartTime != nil {
+		t := params.StartTime.UTC()
+		start = &t
+	} else if d := params.Period.Duration(); d > 0 {
+		t := end.Add(-d)
+		start = &t
+	}
+
+	from := time.Time{}
+	if start != nil {
+		from = *start
+	}
+	observations, err := s.fetchComparePoints(ctx, params.ItemIDs, source.timestep, params.Field, from, end)
+	if err != nil {
+		return nil, err
+	}
+
+	// Open-ended periods start at the earliest observation of any item.
+	if start == nil {
+		for _, points := range observations {
+			for _, p := range points {
+				if start == nil || p.Timestamp.Before(*start) {
+					t := p.Timestamp
+					start = &t
+				}
+			}
+		}
+	}
+
+	response := &models.PriceCompareResponse{
+		Period:     string(params.Period),
+		Field:      string(params.Field),
+		Timestep:   source.timestep,
+		Timestamps: []time.Time{},
+		Series:     make([]models.CompareSeries, 0, len(params.ItemIDs)),
+	}
+	if start == nil {
+		response.IntervalSeconds = int64(step / time.Second)
+		for _, itemID := range params.ItemIDs {
+			response.Series = append(response.Series, models.CompareSeries{
+				ItemID: itemID,
+				Prices: []*float64{},
+				Index:  []*float64{},
+			})
+		}
+		return response, nil
+	}
+
+	maxPoints := getDefaultMaxPoints(params.Period)
+	if params.MaxPoints != nil {
+		maxPoints = *params.MaxPoints
+	}
+	step = compareGridStep(*start, end, step, maxPoints)
+	grid := utils.BuildTimeGrid(*start, end, step)
+
+	response.Timestamps = grid
+	response.IntervalSeconds = int64(step / time.Second)
+	response.Count = len(grid)
+
+	aligned := make(map[int][]*float64, len(params.ItemIDs))
+	for _, itemID := range params.ItemIDs {
+		prices := utils.ForwardFill(grid, observations[itemID],
+			func(p comparePoint) time.Time { return p.Timestamp },
+			func(p comparePoint) *float64 { return p.Value },
+		)
+		aligned[itemID] = prices
+
+		index, baseIdx := utils.Rebase(prices, compareIndexBase)
+		series := models.CompareSeries{
+			ItemID: itemID,
+			Prices: prices,
+			Index:  index,
+		}
+		if baseIdx >= 0 {
+			ts := grid[baseIdx]
+			series.BaseTimestamp = &ts
+			series.BasePrice = prices[baseIdx]
+		}
+		response.Series = append(response.Series, series)
+	}
+
+	for _, pair := range comparePairs(params) {
+		response.Ratios = append(response.Ratios, models.CompareRatio{
+			ItemPair: pair,
+			Values:   utils.RatioSeries(aligned[pair.NumeratorID], aligned[pair.DenominatorID]),
+		})
+	}
+
+	return response, nil
+}
+
+// fetchComparePoints loads the items' observations for the chosen timestep
+// with one query per table. Daily rollups are merged in for the 24h timestep
+// because 24h buckets are only retained for 30 days before being rolled up.
+// Items with no buckets at all are seeded from the Wiki, as for price history.
+func (s *priceService) fetchComparePoints(
+	ctx context.Context,
+	itemIDs []int,
+	timestep string,
+	field models.PriceField,
+	from, to time.Time,
+) (map[int][]comparePoint, error) {
+	points, err := s.priceRepo.GetTimeseriesPointsForItems(ctx, itemIDs, timestep, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("fetch timeseries: %w", err)
+	}
+
+	found := make(map[int]bool, len(itemIDs))
+	for _, p := range points {
+		found[p.ItemID] = true
+	}
+	var seeded []int
+	for _, itemID := range itemIDs {
+		if found[itemID] {
+			continue
+		}
+		if err := s.seedTimeseriesFromWiki(ctx, itemID, timestep); err != nil {
+			s.logger.Warnw("failed to seed timeseries data",
+				"itemId", itemID,
+				"timestep", timestep,
+				"error", err,
+			)
+			continue
+		}
+		seeded = append(seeded, itemID)
+	}
+	if len(seeded) > 0 {
+		more, err := s.priceRepo.GetTimeseriesPointsForItems(ctx, seeded, timestep, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("fetch after seed: %w", err)
+		}
+		points = append(points, more...)
+	}
+
+	byItem := make(map[int]map[time.Time]*float64, len(itemIDs))
+	observe := func(itemID int, ts time.Time, v *float64) {
+		byTime, ok := byItem[itemID]
+		if !ok {
+			byTime = make(map[time.Time]*float64)
+			byItem[itemID] = byTime
+		}
+		byTime[ts] = v
+	}
+	if timestep == "24h" {
+		daily, err := s.priceRepo.GetDailyPointsForItems(ctx, itemIDs, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("fetch daily points: %w", err)
+		}
+		for _, p := range daily {
+			day := time.Date(p.Day.Year(), p.Day.Month(), p.Day.Day(), 0, 0, 0, 0, time.UTC)
+			observe(p.ItemID, day, field.Value(p.AvgHighPrice, p.AvgLowPrice))
+		}
+	}
+	for _, p := range points {
+		if v := field.Value(p.AvgHighPrice, p.AvgLowPrice); v != nil {
+			observe(p.ItemID, p.Timestamp.UTC(), v)
+		}
+	}
+
+	observations := make(map[int][]comparePoint, len(itemIDs))
+	for itemID, byTime := range byItem {
+		result := make([]comparePoint, 0, len(byTime))
+		for ts, v := range byTime {
+			result = append(result, comparePoint{Timestamp: ts, Value: v})
+		}
+		observations[itemID] = result
+	}
+	return observations, nil
+}
+
+// compareGridStep widens the grid step (in multiples of the bucket width) so
+// the grid does not exceed maxPoints timestamps.
+func compareGridStep(start, end time.Time, bucket time.Duration, maxPoints int) time.Duration {
+	if maxPoints < 2 {
+		return bucket
+	}
+	span := end.Sub(start.Truncate(bucket))
+	if span <= 0 || int(span/bucket)+1 <= maxPoints {
+		return bucket
+	}
+	perPoint := span / time.Duration(maxPoints-1)
+	multiple := (perPoint + bucket - 1) / bucket
+	return multiple * bucket
+}
+
+// comparePairs returns the ratio pairs requested for a comparison.
+func comparePairs(params models.PriceCompareParams) []models.ItemPair {
+	if len(params.Pairs) > 0 {
+		return params.Pairs
+	}
+	if !params.AllRatios {
+		return nil
+	}
+	pairs := make([]models.ItemPair, 0, len(params.ItemIDs)*(len(params.ItemIDs)-1)/2)
+	for i := 0; i < len(params.ItemIDs); i++ {
+		for j := i + 1; j < len(params.ItemIDs); j++ {
+			pairs = append(pairs, models.ItemPair{
+				NumeratorID:   params.ItemIDs[i],
+				DenominatorID: params.ItemIDs[j],
+			})
+		}
+	}
+	return pairs
+}
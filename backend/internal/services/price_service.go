@@ -9,6 +9,7 @@ import (
 
 	"github.com/guavi/osrs-ge-tracker/internal/models"
 	"github.com/guavi/osrs-ge-tracker/internal/repository"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
 )
 
 // priceService implements PriceService.
@@ -268,9 +269,14 @@ func (s *priceService) GetPriceHistory(ctx context.Context, params models.PriceH
 		params.MaxPoints = &defaultMaxPoints
 	}
 
+	params.Sampling = params.Sampling.OrDefault()
+
 	// 1. Try cache if period-based and not forcing refresh
 	if params.Period != "" && !params.Refresh {
 		cacheKey := fmt.Sprintf("price:history:%d:%s", params.ItemID, params.Period)
+		if params.Sampling != utils.DefaultSamplingMode {
+			cacheKey += ":" + string(params.Sampling)
+		}
 		var cached models.PriceHistoryResponse
 		err := s.cache.GetJSON(ctx, cacheKey, &cached)
 		if err == nil {
@@ -308,6 +314,7 @@ func (s *priceService) GetPriceHistory(ctx context.Context, params models.PriceH
 	response := &models.PriceHistoryResponse{
 		ItemID:    params.ItemID,
 		Period:    string(params.Period),
+		Sampling:  string(params.Sampling),
 		Data:      dataPoints,
 		Count:     len(dataPoints),
 		FirstDate: firstDate,
@@ -361,6 +368,13 @@ func periodToTimeseriesSource(period models.TimePeriod) timeseriesSource {
 	}
 }
 
+// SyncTimeseries stores an item's latest /timeseries points for timestep.
+// Inserts skip buckets that are already stored, so it is safe to call
+// repeatedly to keep a table current.
+func (s *priceService) SyncTimeseries(ctx context.Context, itemID int, timestep string) error {
+	return s.seedTimeseriesFromWiki(ctx, itemID, timestep)
+}
+
 func (s *priceService) seedTimeseriesFromWiki(ctx context.Context, itemID int, timestep string) error {
 	points, err := s.wikiClient.FetchTimeseries(ctx, itemID, timestep)
 	if err != nil {
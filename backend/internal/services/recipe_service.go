@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/recipes"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+// recipeService implements RecipeService.
+type recipeService struct {
+	recipeRepo repository.RecipeRepository
+	itemRepo   repository.ItemRepository
+	priceRepo  repository.PriceRepository
+	logger     *zap.SugaredLogger
+}
+
+// NewRecipeService creates a new recipe service.
+func NewRecipeService(
+	recipeRepo repository.RecipeRepository,
+	itemRepo repository.ItemRepository,
+	priceRepo repository.PriceRepository,
+	logger *zap.SugaredLogger,
+) RecipeService {
+	return &recipeService{
+		recipeRepo: recipeRepo,
+		itemRepo:   itemRepo,
+		priceRepo:  priceRepo,
+		logger:     logger,
+	}
+}
+
+// ListRecipes returns the recipe registry: the shipped recipes with admin
+// edits applied.
+func (s *recipeService) ListRecipes(ctx context.Context) ([]models.Recipe, error) {
+	defaults, err := recipes.DefaultRecipes()
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := s.recipeRepo.ListOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := recipes.MergeRecipes(defaults, overrides)
+	if err != nil {
+		// A bad row only drops that recipe; the rest of the registry is usable.
+		s.logger.Warnw("Skipped invalid recipe overrides", "error", err)
+	}
+	return merged, nil
+}
+
+// SaveRecipe adds a recipe to the registry or replaces the one with the same
+// ID. Validation errors wrap models.ErrInvalidRecipe.
+func (s *recipeService) SaveRecipe(ctx context.Context, recipe models.Recipe) (*models.Recipe, error) {
+	if err := recipes.ValidateRecipe(&recipe); err != nil {
+		return nil, err
+	}
+	recipe.Source = ""
+	definition, err := json.Marshal(recipe)
+	if err != nil {
+		return nil, fmt.Errorf("encode recipe: %w", err)
+	}
+	override := models.RecipeOverride{RecipeID: recipe.ID, Definition: definition}
+	if err := s.recipeRepo.UpsertOverride(ctx, &override); err != nil {
+		return nil, err
+	}
+
+	recipe.Source = models.RecipeSourceCustom
+	return &recipe, nil
+}
+
+// DeleteRecipe removes a recipe from the registry and reports whether it was
+// in it. Shipped recipes are hidden rather than deleted so they stay removed
+// across upgrades.
+func (s *recipeService) DeleteRecipe(ctx context.Context, id string) (bool, error) {
+	defaults, err := recipes.DefaultRecipes()
+	if err != nil {
+		return false, err
+	}
+	if !slices.ContainsFunc(defaults, func(r models.Recipe) bool { return r.ID == id }) {
+		return s.recipeRepo.DeleteOverride(ctx, id)
+	}
+
+	current, err := s.ListRecipes(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !slices.ContainsFunc(current, func(r models.Recipe) bool { return r.ID == id }) {
+		return false, nil
+	}
+	override := models.RecipeOverride{RecipeID: id, Definition: []byte("{}"), Removed: true}
+	if err := s.recipeRepo.UpsertOverride(ctx, &override); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RecipeProfits prices every recipe in the registry at current prices, most
+// profit per action first.
+func (s *recipeService) RecipeProfits(ctx context.Context, mode models.PricingMode) (*models.RecipeReport, error) {
+	list, err := s.ListRecipes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]struct{})
+	ids := make([]int, 0, len(list)*3)
+	for _, recipe := range list {
+		for _, item := range slices.Concat(recipe.Inputs, recipe.Outputs) {
+			if _, dup := seen[item.ItemID]; !dup {
+				seen[item.ItemID] = struct{}{}
+				ids = append(ids, item.ItemID)
+			}
+		}
+	}
+
+	itemList, err := s.itemRepo.GetByItemIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[int]models.Item, len(itemList))
+	for _, item := range itemList {
+		items[item.ItemID] = item
+	}
+	priceList, err := s.priceRepo.GetCurrentPrices(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	prices := make(map[int]models.CurrentPrice, len(priceList))
+	for _, p := range priceList {
+		prices[p.ItemID] = p
+	}
+
+	results, unpriced := recipes.Evaluate(list, items, prices, mode)
+	return &models.RecipeReport{
+		ComputedAt: time.Now().UTC(),
+		Pricing:    mode,
+		Recipes:    results,
+		Unpriced:   unpriced,
+	}, nil
+}
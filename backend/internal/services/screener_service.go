@@ -0,0 +1,385 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+	"github.com/guavi/osrs-ge-tracker/internal/screener"
+)
+
+// screenerSnapshot is the market as of one refresh. It is never modified
+// after it is published.
+type screenerSnapshot struct {
+	asOf time.Time
+	rows []models.ScreenerRow
+}
+
+// screenerService implements ScreenerService. Expressions are evaluated in
+// memory against a snapshot of every item, refreshed after each price sync.
+type screenerService struct {
+	snapshot     atomic.Pointer[screenerSnapshot]
+	screenRepo   repository.ScreenRepository
+	itemRepo     repository.ItemRepository
+	priceRepo    repository.PriceRepository
+	priceService PriceService
+	logger       *zap.SugaredLogger
+	refreshMu    sync.Mutex
+}
+
+// NewScreenerService creates a new screener service.
+func NewScreenerService(
+	screenRepo repository.ScreenRepository,
+	itemRepo repository.ItemRepository,
+	priceRepo repository.PriceRepository,
+	priceService PriceService,
+	logger *zap.SugaredLogger,
+) ScreenerService {
+	return &screenerService{
+		screenRepo:   screenRepo,
+		itemRepo:     itemRepo,
+		priceRepo:    priceRepo,
+		priceService: priceService,
+		logger:       logger,
+	}
+}
+
+// Fields returns the fields screener expressions can use.
+func (s *screenerService) Fields() []models.ScreenerField {
+	return screener.Fields()
+}
+
+// Run evaluates an expression against the market snapshot. A positive limit
+// overrides the expression's LIMIT. Invalid expressions wrap
+// models.ErrInvalidScreen.
+func (s *screenerService) Run(ctx context.Context, expression string, limit int) (*models.ScreenerResult, error) {
+	query, err := screener.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := s.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = query.Limit()
+	}
+	if limit <= 0 {
+		limit = models.DefaultScreenerLimit
+	}
+	rows, total := query.Run(snap.rows, limit)
+	return &models.ScreenerResult{
+		Expression: expression,
+		AsOf:       snap.asOf,
+		Rows:       rows,
+		Total:      total,
+	}, nil
+}
+
+// RefreshSnapshot recomputes every item's screener row from the stored items,
+// current prices, trade volumes and past prices.
+func (s *screenerService) RefreshSnapshot(ctx context.Context) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	return s.refresh(ctx)
+}
+
+func (s *screenerService) refresh(ctx context.Context) error {
+	start := time.Now()
+	rows, err := buildMarketRows(ctx, s.itemRepo, s.priceRepo, s.priceService, start)
+	if err != nil {
+		return err
+	}
+	s.snapshot.Store(&screenerSnapshot{asOf: start, rows: rows})
+
+	s.logger.Debugw("Refreshed screener snapshot",
+		"items", len(rows),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// buildMarketRows computes every item's screener row as of now from the
+// stored items, current prices, trade volumes and past prices.
+func buildMarketRows(
+	ctx context.Context,
+	itemRepo repository.ItemRepository,
+	priceRepo repository.PriceRepository,
+	priceService PriceService,
+	now time.Time,
+) ([]models.ScreenerRow, error) {
+	items, _, err := itemRepo.GetAll(ctx, models.ItemListParams{})
+	if err != nil {
+		return nil, err
+	}
+	current, err := priceRepo.GetAllCurrentPrices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// The 1h buckets start on the hour, so the last full hour starts one
+	// hour before the current one.
+	volume1h, err := priceRepo.GetTradeVolumes(ctx, now.Truncate(time.Hour).Add(-time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	volume24h, err := priceRepo.GetTradeVolumes(ctx, now.Add(-24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	market := screener.Market{
+		Items:     items,
+		Prices:    make(map[int]models.CurrentPrice, len(current)),
+		Volume1h:  volume1h,
+		Volume24h: volume24h,
+	}
+	for _, p := range current {
+		market.Prices[p.ItemID] = p
+	}
+	ids := make([]int, len(items))
+	for i, item := range items {
+		ids[i] = item.ItemID
+	}
+	for _, past := range []struct {
+		mids *map[int]int64
+		ago  time.Duration
+	}{
+		{&market.Mid1hAgo, time.Hour},
+		{&market.Mid24hAgo, 24 * time.Hour},
+		{&market.Mid7dAgo, 7 * 24 * time.Hour},
+	} {
+		if *past.mids, err = midsAt(ctx, priceService, ids, now.Add(-past.ago)); err != nil {
+			return nil, err
+		}
+	}
+
+	rows := screener.BuildRows(market)
+	for i := range rows {
+		rows[i].IconURL = normalizeItemIconURL(rows[i].ItemID, rows[i].IconURL)
+	}
+	return rows, nil
+}
+
+// midsAt returns each item's mid price at ts, for items with a stored price
+// covering it.
+func midsAt(ctx context.Context, priceService PriceService, itemIDs []int, ts time.Time) (map[int]int64, error) {
+	prices, err := priceService.GetPricesAt(ctx, itemIDs, ts)
+	if err != nil {
+		return nil, err
+	}
+	mids := make(map[int]int64, len(prices))
+	for _, p := range prices {
+		if mid := screener.MidPrice(p.HighPrice, p.LowPrice); mid != nil {
+			mids[p.ItemID] = *mid
+		}
+	}
+	return mids, nil
+}
+
+// Snapshot returns the rows of the market snapshot and when it was taken,
+// building the first one on demand. The rows are shared with every reader of
+// the snapshot and must not be modified.
+func (s *screenerService) Snapshot(ctx context.Context) ([]models.ScreenerRow, time.Time, error) {
+	snap, err := s.current(ctx)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return snap.rows, snap.asOf, nil
+}
+
+// current returns the snapshot, building the first one on demand.
+func (s *screenerService) current(ctx context.Context) (*screenerSnapshot, error) {
+	if snap := s.snapshot.Load(); snap != nil {
+		return snap, nil
+	}
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	if snap := s.snapshot.Load(); snap != nil {
+		return snap, nil
+	}
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s.snapshot.Load(), nil
+}
+
+// ListScreens returns a user's saved screens, oldest first.
+func (s *screenerService) ListScreens(ctx context.Context, userID string) ([]models.Screen, error) {
+	screens, err := s.screenRepo.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if screens == nil {
+		screens = []models.Screen{}
+	}
+	return screens, nil
+}
+
+// GetScreen returns one of a user's screens, or nil when it does not exist.
+func (s *screenerService) GetScreen(ctx context.Context, userID string, id int64) (*models.Screen, error) {
+	return s.screenRepo.Get(ctx, userID, id)
+}
+
+// CreateScreen validates and saves a screen for a user.
+func (s *screenerService) CreateScreen(ctx context.Context, userID string, screen models.Screen) (*models.Screen, error) {
+	if err := validateScreen(&screen); err != nil {
+		return nil, err
+	}
+	screen.ID = 0
+	screen.UserID = userID
+	screen.AlertItemIDs = nil
+	screen.LastAlertAt = nil
+
+	created, err := s.screenRepo.Create(ctx, &screen, models.MaxSavedScreens)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, invalidScreen("at most %d screens can be saved; delete one first", models.MaxSavedScreens)
+	}
+	return &screen, nil
+}
+
+// UpdateScreen replaces a screen's name, expression and alert flag, or
+// returns nil when the screen does not exist.
+func (s *screenerService) UpdateScreen(ctx context.Context, userID string, id int64, screen models.Screen) (*models.Screen, error) {
+	if err := validateScreen(&screen); err != nil {
+		return nil, err
+	}
+	screen.ID = id
+	screen.UserID = userID
+	updated, err := s.screenRepo.Update(ctx, &screen)
+	if err != nil || !updated {
+		return nil, err
+	}
+	return s.screenRepo.Get(ctx, userID, id)
+}
+
+// DeleteScreen removes one of a user's screens and reports whether it existed.
+func (s *screenerService) DeleteScreen(ctx context.Context, userID string, id int64) (bool, error) {
+	return s.screenRepo.Delete(ctx, userID, id)
+}
+
+// RunScreen evaluates a saved screen, or returns nil when it does not exist.
+func (s *screenerService) RunScreen(ctx context.Context, userID string, id int64, limit int) (*models.ScreenerResult, error) {
+	screen, err := s.screenRepo.Get(ctx, userID, id)
+	if err != nil || screen == nil {
+		return nil, err
+	}
+	return s.Run(ctx, screen.Expression, limit)
+}
+
+// CheckAlerts evaluates every alerting screen against the snapshot and
+// returns an alert for each screen that items started matching since its
+// last check. A screen's first check only records what it matches, so
+// creating or editing a screen does not alert on everything it already
+// matches. The expression's ORDER BY and LIMIT apply: a screen limited to
+// 10 rows alerts when items enter its top 10. Screens without a LIMIT track
+// their first MaxScreenerLimit rows.
+func (s *screenerService) CheckAlerts(ctx context.Context) ([]models.ScreenAlert, error) {
+	screens, err := s.screenRepo.ListAlerting(ctx)
+	if err != nil || len(screens) == 0 {
+		return nil, err
+	}
+	snap, err := s.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []models.ScreenAlert
+	var errs []error
+	for _, screen := range screens {
+		query, err := screener.Parse(screen.Expression)
+		if err != nil {
+			// Saved expressions were valid when stored; a grammar change may
+			// have broken this one, which only its owner can fix.
+			s.logger.Warnw("Skipping screen with invalid expression", "screen_id", screen.ID, "error", err)
+			continue
+		}
+		limit := query.Limit()
+		if limit == 0 {
+			limit = models.MaxScreenerLimit
+		}
+		rows, _ := query.Run(snap.rows, limit)
+		matched := make([]int, len(rows))
+		for i, row := range rows {
+			matched[i] = row.ItemID
+		}
+
+		var previous []int
+		baseline := len(screen.AlertItemIDs) == 0
+		if !baseline {
+			if err := json.Unmarshal(screen.AlertItemIDs, &previous); err != nil {
+				s.logger.Warnw("Resetting unreadable screen alert state", "screen_id", screen.ID, "error", err)
+				baseline = true
+			}
+		}
+		var entered []int
+		if !baseline {
+			seen := make(map[int]struct{}, len(previous))
+			for _, id := range previous {
+				seen[id] = struct{}{}
+			}
+			for _, id := range matched {
+				if _, ok := seen[id]; !ok {
+					entered = append(entered, id)
+				}
+			}
+		}
+
+		slices.Sort(matched)
+		if !baseline && len(entered) == 0 && slices.Equal(matched, previous) {
+			continue
+		}
+		var alertedAt *time.Time
+		if len(entered) > 0 {
+			alertedAt = &snap.asOf
+		}
+		if err := s.screenRepo.SetAlertState(ctx, screen.ID, matched, alertedAt); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(entered) == 0 {
+			continue
+		}
+		alerts = append(alerts, models.ScreenAlert{
+			ScreenID:  screen.ID,
+			UserID:    screen.UserID,
+			Name:      screen.Name,
+			ItemIDs:   entered[:min(len(entered), models.MaxScreenAlertItems)],
+			Count:     len(entered),
+			Timestamp: snap.asOf,
+		})
+	}
+	return alerts, errors.Join(errs...)
+}
+
+// validateScreen trims and checks a screen's name and expression.
+func validateScreen(screen *models.Screen) error {
+	screen.Name = strings.TrimSpace(screen.Name)
+	if screen.Name == "" {
+		return invalidScreen("name is required")
+	}
+	if utf8.RuneCountInString(screen.Name) > models.MaxScreenNameLength {
+		return invalidScreen("name must be at most %d characters", models.MaxScreenNameLength)
+	}
+	screen.Expression = strings.TrimSpace(screen.Expression)
+	_, err := screener.Parse(screen.Expression)
+	return err
+}
+
+func invalidScreen(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", models.ErrInvalidScreen, fmt.Sprintf(format, args...))
+}
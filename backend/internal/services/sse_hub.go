@@ -14,6 +14,8 @@ type SSEMessage struct {
 	Data      any       `json:"data"`
 	ItemID    *int      `json:"-"`
 	Event     string    `json:"event"`
+	// UserID, when set, limits delivery to clients connected as that user.
+	UserID string `json:"-"`
 }
 
 type PriceUpdatePayload struct {
@@ -30,6 +32,7 @@ type SSEClient struct {
 	MessageChan chan SSEMessage
 	ItemFilters map[int]struct{}
 	ID          string
+	UserID      string
 }
 
 type SSEHub struct {
@@ -224,6 +227,9 @@ func clientWantsMessage(c *SSEClient, msg SSEMessage) bool {
 	if c == nil {
 		return false
 	}
+	if msg.UserID != "" && msg.UserID != c.UserID {
+		return false
+	}
 	if len(c.ItemFilters) == 0 {
 		return true
 	}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+	"github.com/guavi/osrs-ge-tracker/internal/valuation"
+)
+
+// valuationService implements ValuationService.
+type valuationService struct {
+	itemRepo  repository.ItemRepository
+	priceRepo repository.PriceRepository
+	logger    *zap.SugaredLogger
+}
+
+// NewValuationService creates a new bank valuation service.
+func NewValuationService(
+	itemRepo repository.ItemRepository,
+	priceRepo repository.PriceRepository,
+	logger *zap.SugaredLogger,
+) ValuationService {
+	return &valuationService{
+		itemRepo:  itemRepo,
+		priceRepo: priceRepo,
+		logger:    logger,
+	}
+}
+
+// ValueBank values parsed bank export rows at current prices.
+//
+// IDs the item mapping does not know are usually noted items or
+// placeholders. When the export names the row, the item with that name is
+// used, and counts as the note of that item when its ID is the one before
+// the row's. The mapping has no note metadata, so rows without a name are
+// left unresolved rather than guessed at: the ID before an unknown ID is
+// often an unrelated item.
+func (s *valuationService) ValueBank(ctx context.Context, entries []models.BankEntry) (*models.BankValuation, error) {
+	ids := make([]int, 0, len(entries))
+	seen := make(map[int]struct{}, len(entries))
+	for _, e := range entries {
+		if _, dup := seen[e.ItemID]; dup || e.Quantity == 0 || valuation.IsCurrency(e.ItemID) {
+			continue
+		}
+		seen[e.ItemID] = struct{}{}
+		ids = append(ids, e.ItemID)
+	}
+
+	matches := make(map[int]valuation.Match, len(ids))
+	items, err := s.itemRepo.GetByItemIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		matches[item.ItemID] = valuation.Match{Item: item, Resolution: models.BankResolutionDirect}
+	}
+
+	if err := s.resolveMissing(ctx, entries, matches); err != nil {
+		return nil, err
+	}
+
+	resolved := make([]int, 0, len(matches))
+	added := make(map[int]struct{}, len(matches))
+	for _, m := range matches {
+		if _, dup := added[m.Item.ItemID]; !dup {
+			added[m.Item.ItemID] = struct{}{}
+			resolved = append(resolved, m.Item.ItemID)
+		}
+	}
+	prices := make(map[int]models.CurrentPrice, len(resolved))
+	if len(resolved) > 0 {
+		current, err := s.priceRepo.GetCurrentPrices(ctx, resolved)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range current {
+			prices[p.ItemID] = p
+		}
+	}
+
+	result := valuation.Compute(entries, matches, prices)
+	return &result, nil
+}
+
+// resolveMissing matches the export IDs the mapping does not know by the
+// name on the row. Rows without a name stay unmatched.
+func (s *valuationService) resolveMissing(ctx context.Context, entries []models.BankEntry, matches map[int]valuation.Match) error {
+	byName := make(map[int]string)
+	for _, e := range entries {
+		if _, ok := matches[e.ItemID]; ok || e.Quantity == 0 || valuation.IsCurrency(e.ItemID) {
+			continue
+		}
+		if e.Name != "" {
+			byName[e.ItemID] = e.Name
+		}
+	}
+	if len(byName) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(byName))
+	for _, name := range byName {
+		names = append(names, name)
+	}
+	items, err := s.itemRepo.GetByNames(ctx, names)
+	if err != nil {
+		return err
+	}
+	// Items come back in ID order, so a shared name resolves to the oldest item.
+	named := make(map[string]models.Item, len(items))
+	for _, item := range items {
+		key := strings.ToLower(item.Name)
+		if _, dup := named[key]; !dup {
+			named[key] = item
+		}
+	}
+	for id, name := range byName {
+		item, ok := named[strings.ToLower(name)]
+		if !ok {
+			continue
+		}
+		// A note's ID directly follows its item's, and both share a name.
+		resolution := models.BankResolutionName
+		if item.ItemID == id-1 {
+			resolution = models.BankResolutionNoted
+		}
+		matches[id] = valuation.Match{Item: item, Resolution: resolution}
+	}
+	return nil
+}
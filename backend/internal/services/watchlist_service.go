@@ -18,6 +18,7 @@ import (
 type WatchlistService interface {
 	CreateShare(ctx context.Context, watchlistData interface{}) (*models.WatchlistShareResponse, error)
 	GetShare(ctx context.Context, token string) (*models.WatchlistShareDetailResponse, error)
+	GetShareItemIDs(ctx context.Context, token string) ([]int, error)
 	CleanupExpiredShares(ctx context.Context) (int64, error)
 }
 
@@ -107,14 +108,14 @@ func (s *watchlistService) CreateShare(
 func (s *watchlistService) GetShare(ctx context.Context, token string) (*models.WatchlistShareDetailResponse, error) {
 	// Validate token format
 	if !utils.ValidateShareToken(token) {
-		return nil, fmt.Errorf("invalid token format")
+		return nil, models.ErrInvalidShareToken
 	}
 
 	var share models.WatchlistShare
 	if err := s.db.WithContext(ctx).Where("token = ?", token).First(&share).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			s.logger.Debugw("Share not found", "token", token)
-			return nil, fmt.Errorf("share not found")
+			return nil, models.ErrShareNotFound
 		}
 		s.logger.Errorw("Database error retrieving share", "error", err, "token", token)
 		return nil, fmt.Errorf("database error: %w", err)
@@ -123,7 +124,7 @@ func (s *watchlistService) GetShare(ctx context.Context, token string) (*models.
 	// Check if expired
 	if share.IsExpired() {
 		s.logger.Debugw("Share has expired", "token", token, "expires_at", share.ExpiresAt)
-		return nil, fmt.Errorf("share has expired")
+		return nil, models.ErrShareExpired
 	}
 
 	// Parse watchlist data
@@ -152,6 +153,64 @@ func (s *watchlistService) GetShare(ctx context.Context, token string) (*models.
 	return response, nil
 }
 
+// sharedWatchlistItems is the subset of shared watchlist data needed to read item IDs.
+// Shares are stored either as the watchlist itself or wrapped in a "watchlist" key.
+type sharedWatchlistItems struct {
+	Watchlist *struct {
+		Items []struct {
+			ItemID int `json:"itemId"`
+		} `json:"items"`
+	} `json:"watchlist"`
+	Items []struct {
+		ItemID int `json:"itemId"`
+	} `json:"items"`
+}
+
+// GetShareItemIDs returns the distinct item IDs contained in a shared watchlist.
+// Unlike GetShare it does not count as an access.
+func (s *watchlistService) GetShareItemIDs(ctx context.Context, token string) ([]int, error) {
+	if !utils.ValidateShareToken(token) {
+		return nil, models.ErrInvalidShareToken
+	}
+
+	var share models.WatchlistShare
+	if err := s.db.WithContext(ctx).Where("token = ?", token).First(&share).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrShareNotFound
+		}
+		s.logger.Errorw("Database error retrieving share", "error", err, "token", token)
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if share.IsExpired() {
+		return nil, models.ErrShareExpired
+	}
+
+	var data sharedWatchlistItems
+	if err := json.Unmarshal(share.WatchlistData, &data); err != nil {
+		s.logger.Errorw("Failed to unmarshal watchlist data", "error", err, "token", token)
+		return nil, fmt.Errorf("invalid watchlist data: %w", err)
+	}
+
+	items := data.Items
+	if data.Watchlist != nil {
+		items = append(items, data.Watchlist.Items...)
+	}
+
+	seen := make(map[int]struct{}, len(items))
+	itemIDs := make([]int, 0, len(items))
+	for _, item := range items {
+		if item.ItemID <= 0 {
+			continue
+		}
+		if _, dup := seen[item.ItemID]; dup {
+			continue
+		}
+		seen[item.ItemID] = struct{}{}
+		itemIDs = append(itemIDs, item.ItemID)
+	}
+	return itemIDs, nil
+}
+
 // CleanupExpiredShares deletes all expired shares
 func (s *watchlistService) CleanupExpiredShares(ctx context.Context) (int64, error) {
 	result := s.db.WithContext(ctx).
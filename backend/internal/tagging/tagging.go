@@ -0,0 +1,197 @@
+// Package tagging classifies Grand Exchange items into categories and tags
+// from their names and Wiki metadata.
+package tagging
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// Tags derived from the Wiki mapping's members flag.
+const (
+	TagMembers = "members"
+	TagF2P     = "f2p"
+)
+
+//go:embed rules.json
+var defaultRulesJSON []byte
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// rulesFile is the layout of rules.json. Patterns are matched against the
+// lowercased item name and examine text.
+type rulesFile struct {
+	Categories []struct {
+		Category string   `json:"category"`
+		Names    []string `json:"names"`
+		Examine  []string `json:"examine"`
+	} `json:"categories"`
+	Tags []struct {
+		Tag     string   `json:"tag"`
+		Names   []string `json:"names"`
+		Examine []string `json:"examine"`
+	} `json:"tags"`
+}
+
+type rule struct {
+	tag     string
+	names   []*regexp.Regexp
+	examine []*regexp.Regexp
+}
+
+func (r rule) matches(name, examine string) bool {
+	for _, p := range r.names {
+		if p.MatchString(name) {
+			return true
+		}
+	}
+	if examine == "" {
+		return false
+	}
+	for _, p := range r.examine {
+		if p.MatchString(examine) {
+			return true
+		}
+	}
+	return false
+}
+
+// Classifier assigns categories and tags to items. An item gets the first
+// category whose rule matches, every matching tag, and members or f2p.
+type Classifier struct {
+	categories   []rule
+	tags         []rule
+	isCategory   map[string]struct{}
+	categoryList []string
+}
+
+// DefaultClassifier returns a classifier over the rules shipped with the
+// application.
+func DefaultClassifier() (*Classifier, error) {
+	var file rulesFile
+	if err := json.Unmarshal(defaultRulesJSON, &file); err != nil {
+		return nil, fmt.Errorf("parse embedded tagging rules: %w", err)
+	}
+
+	c := &Classifier{isCategory: make(map[string]struct{}, len(file.Categories))}
+	for _, def := range file.Categories {
+		r, err := compileRule(def.Category, def.Names, def.Examine)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := c.isCategory[r.tag]; dup {
+			return nil, fmt.Errorf("embedded tagging rules: category %q is defined twice", r.tag)
+		}
+		c.isCategory[r.tag] = struct{}{}
+		c.categories = append(c.categories, r)
+		c.categoryList = append(c.categoryList, r.tag)
+	}
+	for _, def := range file.Tags {
+		r, err := compileRule(def.Tag, def.Names, def.Examine)
+		if err != nil {
+			return nil, err
+		}
+		if _, clash := c.isCategory[r.tag]; clash {
+			return nil, fmt.Errorf("embedded tagging rules: tag %q is also a category", r.tag)
+		}
+		c.tags = append(c.tags, r)
+	}
+	sort.Strings(c.categoryList)
+	return c, nil
+}
+
+func compileRule(tag string, names, examine []string) (rule, error) {
+	if _, err := NormalizeTag(tag); err != nil {
+		return rule{}, fmt.Errorf("embedded tagging rules: %w", err)
+	}
+	r := rule{tag: tag}
+	for _, p := range names {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return rule{}, fmt.Errorf("embedded tagging rules: %s: %w", tag, err)
+		}
+		r.names = append(r.names, re)
+	}
+	for _, p := range examine {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return rule{}, fmt.Errorf("embedded tagging rules: %s: %w", tag, err)
+		}
+		r.examine = append(r.examine, re)
+	}
+	return r, nil
+}
+
+// Categories returns the category names, sorted.
+func (c *Classifier) Categories() []string {
+	return c.categoryList
+}
+
+// IsCategory reports whether tag is one of the classifier's categories.
+func (c *Classifier) IsCategory(tag string) bool {
+	_, ok := c.isCategory[tag]
+	return ok
+}
+
+// Kind returns models.ItemTagKindCategory for categories and
+// models.ItemTagKindTag for anything else.
+func (c *Classifier) Kind(tag string) string {
+	if c.IsCategory(tag) {
+		return models.ItemTagKindCategory
+	}
+	return models.ItemTagKindTag
+}
+
+// Classify returns the auto tags for an item.
+func (c *Classifier) Classify(item models.Item) []models.ItemTag {
+	name := strings.ToLower(strings.TrimSpace(item.Name))
+	examine := ""
+	if item.Examine != nil {
+		examine = strings.ToLower(*item.Examine)
+	}
+
+	var tags []models.ItemTag
+	add := func(tag, kind string) {
+		tags = append(tags, models.ItemTag{
+			ItemID: item.ItemID,
+			Tag:    tag,
+			Kind:   kind,
+			Source: models.ItemTagSourceAuto,
+		})
+	}
+	for _, r := range c.categories {
+		if r.matches(name, examine) {
+			add(r.tag, models.ItemTagKindCategory)
+			break
+		}
+	}
+	for _, r := range c.tags {
+		if r.matches(name, examine) {
+			add(r.tag, models.ItemTagKindTag)
+		}
+	}
+	if item.Members {
+		add(TagMembers, models.ItemTagKindTag)
+	} else {
+		add(TagF2P, models.ItemTagKindTag)
+	}
+	return tags
+}
+
+// NormalizeTag trims and lowercases a tag and checks that it is 1-32
+// lowercase letters, digits and single dashes. Errors wrap
+// models.ErrInvalidItemTag.
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if len(tag) > models.MaxItemTagLength || !tagPattern.MatchString(tag) {
+		return "", fmt.Errorf("%w: tag must be 1-%d lowercase letters, digits and single dashes",
+			models.ErrInvalidItemTag, models.MaxItemTagLength)
+	}
+	return tag, nil
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"sort"
+	"time"
+)
+
+// BuildTimeGrid returns evenly spaced timestamps covering [start, end] at the
+// given step. The first grid point is start truncated to a step boundary so
+// grids built for different items always line up with bucket timestamps.
+func BuildTimeGrid(start, end time.Time, step time.Duration) []time.Time {
+	if step <= 0 || end.Before(start) {
+		return []time.Time{}
+	}
+
+	first := start.UTC().Truncate(step)
+	count := int(end.UTC().Sub(first)/step) + 1
+
+	grid := make([]time.Time, 0, count)
+	for i := 0; i < count; i++ {
+		grid = append(grid, first.Add(time.Duration(i)*step))
+	}
+	return grid
+}
+
+// ForwardFill maps observations onto a time grid. Each grid point takes the
+// value of the latest observation at or before it; observations with a nil
+// value are ignored so gaps carry the previous value forward. Grid points that
+// precede the first observation remain nil.
+//
+// Points may be supplied in any order; the grid must be ascending.
+func ForwardFill[T any](
+	grid []time.Time,
+	points []T,
+	getTime func(T) time.Time,
+	getValue func(T) *float64,
+) []*float64 {
+	result := make([]*float64, len(grid))
+	if len(grid) == 0 || len(points) == 0 {
+		return result
+	}
+
+	ordered := make([]T, len(points))
+	copy(ordered, points)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return getTime(ordered[i]).Before(getTime(ordered[j]))
+	})
+
+	var last *float64
+	idx := 0
+	for i, ts := range grid {
+		for idx < len(ordered) && !getTime(ordered[idx]).After(ts) {
+			if v := getValue(ordered[idx]); v != nil {
+				value := *v
+				last = &value
+			}
+			idx++
+		}
+		if last != nil {
+			value := *last
+			result[i] = &value
+		}
+	}
+	return result
+}
+
+// Rebase converts a series to an index where the first non-nil value equals
+// base. Returns the rebased series and the index of the base value (-1 when
+// the series has no usable value).
+func Rebase(values []*float64, base float64) ([]*float64, int) {
+	result := make([]*float64, len(values))
+
+	baseIdx := -1
+	for i, v := range values {
+		if v != nil && *v != 0 {
+			baseIdx = i
+			break
+		}
+	}
+	if baseIdx == -1 {
+		return result, -1
+	}
+
+	denominator := *values[baseIdx]
+	for i := baseIdx; i < len(values); i++ {
+		if values[i] == nil {
+			continue
+		}
+		rebased := *values[i] / denominator * base
+		result[i] = &rebased
+	}
+	return result, baseIdx
+}
+
+// RatioSeries divides two aligned series point by point. A point is nil when
+// either side is missing or the denominator is zero.
+func RatioSeries(numerator, denominator []*float64) []*float64 {
+	n := len(numerator)
+	if len(denominator) < n {
+		n = len(denominator)
+	}
+
+	result := make([]*float64, n)
+	for i := 0; i < n; i++ {
+		if numerator[i] == nil || denominator[i] == nil || *denominator[i] == 0 {
+			continue
+		}
+		ratio := *numerator[i] / *denominator[i]
+		result[i] = &ratio
+	}
+	return result
+}
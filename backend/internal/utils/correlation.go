@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"math"
+	"sort"
+)
+
+// LogReturns converts an aligned price series into log returns. Element i is
+// ln(values[i] / values[i-1]) and is nil when either price is missing or not
+// positive; element 0 is always nil so the result stays aligned with the input.
+func LogReturns(values []*float64) []*float64 {
+	returns := make([]*float64, len(values))
+	for i := 1; i < len(values); i++ {
+		prev, cur := values[i-1], values[i]
+		if prev == nil || cur == nil || *prev <= 0 || *cur <= 0 {
+			continue
+		}
+		r := math.Log(*cur / *prev)
+		returns[i] = &r
+	}
+	return returns
+}
+
+// PairwiseComplete returns the values of a and b at the indexes where both are
+// present.
+func PairwiseComplete(a, b []*float64) (x, y []float64) {
+	n := min(len(a), len(b))
+	x = make([]float64, 0, n)
+	y = make([]float64, 0, n)
+	for i := 0; i < n; i++ {
+		if a[i] != nil && b[i] != nil {
+			x = append(x, *a[i])
+			y = append(y, *b[i])
+		}
+	}
+	return x, y
+}
+
+// Pearson returns the Pearson correlation coefficient of x and y, or nil when
+// there are fewer than two pairs or either side has zero variance.
+func Pearson(x, y []float64) *float64 {
+	n := min(len(x), len(y))
+	if n < 2 {
+		return nil
+	}
+
+	var meanX, meanY float64
+	for i := 0; i < n; i++ {
+		meanX += x[i]
+		meanY += y[i]
+	}
+	meanX /= float64(n)
+	meanY /= float64(n)
+
+	var cov, varX, varY float64
+	for i := 0; i < n; i++ {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return nil
+	}
+
+	r := cov / math.Sqrt(varX*varY)
+	// Guard against rounding pushing |r| just past 1.
+	r = math.Max(-1, math.Min(1, r))
+	return &r
+}
+
+// Spearman returns the Spearman rank correlation of x and y: the Pearson
+// correlation of their ranks, with ties given their average rank.
+func Spearman(x, y []float64) *float64 {
+	n := min(len(x), len(y))
+	return Pearson(Ranks(x[:n]), Ranks(y[:n]))
+}
+
+// Ranks returns the 1-based rank of every value, averaging the ranks of ties.
+func Ranks(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return values[order[a]] < values[order[b]]
+	})
+
+	ranks := make([]float64, len(values))
+	for i := 0; i < len(order); {
+		j := i + 1
+		for j < len(order) && values[order[j]] == values[order[i]] {
+			j++
+		}
+		// Positions i..j-1 are tied; ranks are 1-based.
+		avg := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			ranks[order[k]] = avg
+		}
+		i = j
+	}
+	return ranks
+}
@@ -0,0 +1,24 @@
+package utils
+
+// Grand Exchange tax: 2% of the sale price of each unit, rounded down and
+// capped at 5M per unit. A few items, such as Old school bonds, are exempt.
+const (
+	GETaxPercent       = 2
+	GETaxCap     int64 = 5_000_000
+)
+
+// geTaxExemptItems lists item IDs sold without tax.
+var geTaxExemptItems = map[int]struct{}{
+	13190: {}, // Old school bond
+}
+
+// GETax returns the tax charged on selling one unit of an item at price.
+func GETax(itemID int, price int64) int64 {
+	if price <= 0 {
+		return 0
+	}
+	if _, exempt := geTaxExemptItems[itemID]; exempt {
+		return 0
+	}
+	return min(price*GETaxPercent/100, GETaxCap)
+}
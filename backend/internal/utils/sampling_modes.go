@@ -0,0 +1,282 @@
+package utils
+
+import (
+	"math"
+	"time"
+)
+
+// SamplingMode selects the downsampling algorithm used for chart data.
+type SamplingMode string
+
+const (
+	// SamplingVoronoi picks the point nearest to evenly spaced target times (SampleByTime).
+	SamplingVoronoi SamplingMode = "voronoi"
+	// SamplingLTTB keeps the points that best preserve the visual shape (Largest-Triangle-Three-Buckets).
+	SamplingLTTB SamplingMode = "lttb"
+	// SamplingMinMax keeps the highest high and lowest low of every time bucket.
+	SamplingMinMax SamplingMode = "minmax"
+	// SamplingAverage replaces every time bucket with its mean high and low.
+	SamplingAverage SamplingMode = "average"
+)
+
+// DefaultSamplingMode is used when no mode is requested.
+const DefaultSamplingMode = SamplingVoronoi
+
+// IsValid checks if the sampling mode is supported.
+func (m SamplingMode) IsValid() bool {
+	switch m {
+	case SamplingVoronoi, SamplingLTTB, SamplingMinMax, SamplingAverage:
+		return true
+	default:
+		return false
+	}
+}
+
+// OrDefault returns the mode, or DefaultSamplingMode when unset.
+func (m SamplingMode) OrDefault() SamplingMode {
+	if m == "" {
+		return DefaultSamplingMode
+	}
+	return m
+}
+
+// Sample reduces time-ordered (ascending) points to at most targetPoints using
+// the requested mode. Unknown or empty modes fall back to SampleByTime.
+func Sample[T any](points []T, targetPoints int, mode SamplingMode, accessor TimeAccessor[T]) []T {
+	switch mode {
+	case SamplingLTTB:
+		return SampleLTTB(points, targetPoints, accessor)
+	case SamplingMinMax:
+		return SampleMinMax(points, targetPoints, accessor)
+	case SamplingAverage:
+		return SampleAverage(points, targetPoints, accessor)
+	default:
+		return SampleByTime(points, targetPoints, accessor)
+	}
+}
+
+// midValue returns the midpoint of high and low, or whichever side is present.
+func midValue[T any](p T, accessor TimeAccessor[T]) (float64, bool) {
+	high := accessor.GetHigh(p)
+	low := accessor.GetLow(p)
+	switch {
+	case high != nil && low != nil:
+		return (float64(*high) + float64(*low)) / 2, true
+	case high != nil:
+		return float64(*high), true
+	case low != nil:
+		return float64(*low), true
+	default:
+		return 0, false
+	}
+}
+
+// pricedPoints drops points where both high and low are missing.
+func pricedPoints[T any](points []T, accessor TimeAccessor[T]) []T {
+	result := make([]T, 0, len(points))
+	for _, p := range points {
+		if accessor.GetHigh(p) != nil || accessor.GetLow(p) != nil {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// timeBuckets splits time-ordered points into bucketCount equal-width time
+// buckets and returns the index ranges [start, end) of the non-empty ones.
+func timeBuckets[T any](points []T, bucketCount int, accessor TimeAccessor[T]) [][2]int {
+	if len(points) == 0 || bucketCount < 1 {
+		return nil
+	}
+
+	start := accessor.GetTime(points[0])
+	span := accessor.GetTime(points[len(points)-1]).Sub(start)
+	width := span / time.Duration(bucketCount)
+	if width <= 0 {
+		return [][2]int{{0, len(points)}}
+	}
+
+	ranges := make([][2]int, 0, bucketCount)
+	lo := 0
+	for b := 0; b < bucketCount && lo < len(points); b++ {
+		bound := start.Add(time.Duration(b+1) * width)
+		hi := lo
+		for hi < len(points) && (b == bucketCount-1 || accessor.GetTime(points[hi]).Before(bound)) {
+			hi++
+		}
+		if hi > lo {
+			ranges = append(ranges, [2]int{lo, hi})
+		}
+		lo = hi
+	}
+	return ranges
+}
+
+// SampleLTTB reduces time-ordered points to targetPoints using the
+// Largest-Triangle-Three-Buckets algorithm on the mid price. The first and
+// last points are always kept; from every bucket in between the point forming
+// the largest triangle with the previous pick and the next bucket's average is
+// kept, which preserves spikes that nearest-point sampling drops.
+func SampleLTTB[T any](points []T, targetPoints int, accessor TimeAccessor[T]) []T {
+	points = pricedPoints(points, accessor)
+	if len(points) <= targetPoints || targetPoints < 3 {
+		if targetPoints < 3 && len(points) > targetPoints {
+			return SampleByTime(points, targetPoints, accessor)
+		}
+		return points
+	}
+
+	xs := make([]float64, len(points))
+	ys := make([]float64, len(points))
+	for i, p := range points {
+		xs[i] = float64(accessor.GetTime(p).Unix())
+		ys[i], _ = midValue(p, accessor)
+	}
+
+	sampled := make([]T, 0, targetPoints)
+	sampled = append(sampled, points[0])
+
+	bucketSize := float64(len(points)-2) / float64(targetPoints-2)
+	selected := 0
+	for b := 0; b < targetPoints-2; b++ {
+		start := int(math.Floor(float64(b)*bucketSize)) + 1
+		end := int(math.Floor(float64(b+1)*bucketSize)) + 1
+
+		// Average of the next bucket (or the last point for the final bucket).
+		nextStart := end
+		nextEnd := int(math.Floor(float64(b+2)*bucketSize)) + 1
+		if nextEnd > len(points) {
+			nextEnd = len(points)
+		}
+		if nextStart >= nextEnd {
+			nextStart, nextEnd = len(points)-1, len(points)
+		}
+		var avgX, avgY float64
+		for i := nextStart; i < nextEnd; i++ {
+			avgX += xs[i]
+			avgY += ys[i]
+		}
+		n := float64(nextEnd - nextStart)
+		avgX /= n
+		avgY /= n
+
+		best := start
+		bestArea := -1.0
+		for i := start; i < end; i++ {
+			area := math.Abs((xs[selected]-avgX)*(ys[i]-ys[selected]) - (xs[selected]-xs[i])*(avgY-ys[selected]))
+			if area > bestArea {
+				bestArea = area
+				best = i
+			}
+		}
+
+		sampled = append(sampled, points[best])
+		selected = best
+	}
+
+	return append(sampled, points[len(points)-1])
+}
+
+// SampleMinMax reduces time-ordered points to at most targetPoints by
+// splitting the range into targetPoints/2 equal time buckets and keeping, for
+// each bucket, the point with the highest high and the point with the lowest
+// low (in time order, once if they coincide).
+func SampleMinMax[T any](points []T, targetPoints int, accessor TimeAccessor[T]) []T {
+	points = pricedPoints(points, accessor)
+	if len(points) <= targetPoints {
+		return points
+	}
+
+	bucketCount := targetPoints / 2
+	if bucketCount < 1 {
+		return SampleByTime(points, targetPoints, accessor)
+	}
+
+	sampled := make([]T, 0, targetPoints)
+	for _, r := range timeBuckets(points, bucketCount, accessor) {
+		maxIdx, minIdx := -1, -1
+		var maxVal, minVal float64
+		for i := r[0]; i < r[1]; i++ {
+			high := accessor.GetHigh(points[i])
+			low := accessor.GetLow(points[i])
+			if high == nil {
+				high = low
+			}
+			if low == nil {
+				low = high
+			}
+			if maxIdx == -1 || float64(*high) > maxVal {
+				maxIdx, maxVal = i, float64(*high)
+			}
+			if minIdx == -1 || float64(*low) < minVal {
+				minIdx, minVal = i, float64(*low)
+			}
+		}
+
+		first, second := minIdx, maxIdx
+		if maxIdx < minIdx {
+			first, second = maxIdx, minIdx
+		}
+		sampled = append(sampled, points[first])
+		if second != first {
+			sampled = append(sampled, points[second])
+		}
+	}
+	return sampled
+}
+
+// SampleAverage reduces time-ordered points to at most targetPoints by
+// splitting the range into equal time buckets and replacing each bucket with
+// the mean of its highs and lows. The bucket's point nearest to the bucket's
+// midpoint in time carries the averaged values.
+func SampleAverage[T any](points []T, targetPoints int, accessor TimeAccessor[T]) []T {
+	points = pricedPoints(points, accessor)
+	if len(points) <= targetPoints || targetPoints < 1 {
+		return points
+	}
+
+	sampled := make([]T, 0, targetPoints)
+	for _, r := range timeBuckets(points, targetPoints, accessor) {
+		var highSum, lowSum float64
+		var highCount, lowCount int
+		for i := r[0]; i < r[1]; i++ {
+			if h := accessor.GetHigh(points[i]); h != nil {
+				highSum += float64(*h)
+				highCount++
+			}
+			if l := accessor.GetLow(points[i]); l != nil {
+				lowSum += float64(*l)
+				lowCount++
+			}
+		}
+
+		first := accessor.GetTime(points[r[0]])
+		mid := first.Add(accessor.GetTime(points[r[1]-1]).Sub(first) / 2)
+		carrier := r[0]
+		bestDist := time.Duration(math.MaxInt64)
+		for i := r[0]; i < r[1]; i++ {
+			dist := accessor.GetTime(points[i]).Sub(mid)
+			if dist < 0 {
+				dist = -dist
+			}
+			if dist < bestDist {
+				bestDist, carrier = dist, i
+			}
+		}
+
+		point := points[carrier]
+		accessor.SetHigh(&point, roundedMean(highSum, highCount))
+		accessor.SetLow(&point, roundedMean(lowSum, lowCount))
+		sampled = append(sampled, point)
+	}
+	return sampled
+}
+
+// roundedMean returns sum/count rounded to the nearest integer, or nil when count is zero.
+func roundedMean(sum float64, count int) *int64 {
+	if count == 0 {
+		return nil
+	}
+	v := int64(math.Round(sum / float64(count)))
+	return &v
+}
@@ -0,0 +1,229 @@
+// Package valuation parses bank exports and values them at current prices.
+package valuation
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// Export formats reported back to the caller.
+const (
+	FormatJSON = "json"
+	FormatTSV  = "tsv"
+	FormatCSV  = "csv"
+)
+
+// Header names accepted for each column, lowercased with spaces and
+// underscores removed. RuneLite's bank export uses "Item id", "Item name" and
+// "Item quantity".
+var (
+	idHeaders       = map[string]bool{"id": true, "itemid": true}
+	nameHeaders     = map[string]bool{"name": true, "itemname": true}
+	quantityHeaders = map[string]bool{"quantity": true, "qty": true, "amount": true, "itemquantity": true}
+)
+
+// Parse reads a bank export. JSON is an array of {"id"|"itemId", "quantity"|
+// "qty", "name"} objects, optionally wrapped in {"items": [...]}. Delimited
+// exports are tab- or comma-separated with an optional header row; without
+// one the columns are id,quantity or id,name,quantity. Errors wrap
+// models.ErrInvalidBankExport.
+func Parse(body []byte) ([]models.BankEntry, string, error) {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(body, []byte("\ufeff")))
+	if len(trimmed) == 0 {
+		return nil, "", invalid("export is empty")
+	}
+
+	var entries []models.BankEntry
+	var format string
+	var err error
+	switch trimmed[0] {
+	case '[', '{':
+		format = FormatJSON
+		entries, err = parseJSON(trimmed)
+	default:
+		format = FormatCSV
+		if firstLine, _, _ := bytes.Cut(trimmed, []byte("\n")); bytes.ContainsRune(firstLine, '\t') {
+			format = FormatTSV
+		}
+		entries, err = parseDelimited(trimmed, format == FormatTSV)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(entries) == 0 {
+		return nil, "", invalid("export has no items")
+	}
+	if len(entries) > models.MaxBankExportEntries {
+		return nil, "", invalid("export must have at most %d rows", models.MaxBankExportEntries)
+	}
+	return entries, format, nil
+}
+
+// jsonEntry accepts the common spellings of each field.
+type jsonEntry struct {
+	ID       *int   `json:"id"`
+	ItemID   *int   `json:"itemId"`
+	Quantity *int64 `json:"quantity"`
+	Qty      *int64 `json:"qty"`
+	Name     string `json:"name"`
+}
+
+func parseJSON(body []byte) ([]models.BankEntry, error) {
+	var rows []jsonEntry
+	if body[0] == '{' {
+		var wrapper struct {
+			Items []jsonEntry `json:"items"`
+		}
+		if err := json.Unmarshal(body, &wrapper); err != nil {
+			return nil, invalid("malformed JSON: %v", err)
+		}
+		rows = wrapper.Items
+	} else if err := json.Unmarshal(body, &rows); err != nil {
+		return nil, invalid("malformed JSON: %v", err)
+	}
+
+	entries := make([]models.BankEntry, 0, len(rows))
+	for i, row := range rows {
+		id := row.ID
+		if id == nil {
+			id = row.ItemID
+		}
+		quantity := row.Quantity
+		if quantity == nil {
+			quantity = row.Qty
+		}
+		if id == nil || quantity == nil {
+			return nil, invalid("item %d: id and quantity are required", i+1)
+		}
+		entry := models.BankEntry{ItemID: *id, Quantity: *quantity, Name: strings.TrimSpace(row.Name)}
+		if err := check(entry); err != nil {
+			return nil, invalid("item %d: %s", i+1, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func parseDelimited(body []byte, tabs bool) ([]models.BankEntry, error) {
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.LazyQuotes = true
+	if tabs {
+		reader.Comma = '\t'
+	}
+
+	idCol, nameCol, quantityCol := -1, -1, -1
+	entries := make([]models.BankEntry, 0)
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			return nil, invalid("malformed export: %v", err)
+		}
+		if isBlank(record) {
+			continue
+		}
+
+		if first {
+			if _, err := strconv.Atoi(strings.TrimSpace(record[0])); err != nil {
+				idCol, nameCol, quantityCol = headerColumns(record)
+				if idCol < 0 || quantityCol < 0 {
+					return nil, invalid("header must name an item id and a quantity column")
+				}
+				continue
+			}
+		}
+		if idCol < 0 {
+			switch len(record) {
+			case 2:
+				idCol, quantityCol = 0, 1
+			case 3:
+				idCol, nameCol, quantityCol = 0, 1, 2
+			default:
+				return nil, invalid("line %d: expected id,quantity or id,name,quantity", line)
+			}
+		}
+
+		entry, err := parseRecord(record, idCol, nameCol, quantityCol)
+		if err != nil {
+			return nil, invalid("line %d: %s", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func headerColumns(header []string) (idCol, nameCol, quantityCol int) {
+	idCol, nameCol, quantityCol = -1, -1, -1
+	for i, raw := range header {
+		name := strings.NewReplacer(" ", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(raw)))
+		switch {
+		case idHeaders[name] && idCol < 0:
+			idCol = i
+		case nameHeaders[name] && nameCol < 0:
+			nameCol = i
+		case quantityHeaders[name] && quantityCol < 0:
+			quantityCol = i
+		}
+	}
+	return idCol, nameCol, quantityCol
+}
+
+func parseRecord(record []string, idCol, nameCol, quantityCol int) (models.BankEntry, error) {
+	field := func(i int) string {
+		if i >= 0 && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var entry models.BankEntry
+	id, err := strconv.Atoi(field(idCol))
+	if err != nil {
+		return entry, errors.New("item id must be a number")
+	}
+	quantity, err := strconv.ParseInt(strings.NewReplacer(",", "", "_", "", " ", "").Replace(field(quantityCol)), 10, 64)
+	if err != nil {
+		return entry, errors.New("quantity must be a whole number")
+	}
+	entry = models.BankEntry{ItemID: id, Quantity: quantity, Name: field(nameCol)}
+	return entry, check(entry)
+}
+
+// check rejects values no bank can hold. A stack is capped at 2^31-1.
+func check(entry models.BankEntry) error {
+	switch {
+	case entry.ItemID < 0:
+		return errors.New("item id must not be negative")
+	case entry.Quantity < 0 || entry.Quantity > math.MaxInt32:
+		return fmt.Errorf("quantity must be between 0 and %d", math.MaxInt32)
+	}
+	return nil
+}
+
+func isBlank(record []string) bool {
+	for _, f := range record {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", models.ErrInvalidBankExport, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,161 @@
+package valuation
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+)
+
+// currencies are bank items that never trade on the Grand Exchange but have a
+// fixed value in coins.
+var currencies = map[int]struct {
+	name  string
+	value int64
+}{
+	995:   {name: "Coins", value: 1},
+	13204: {name: "Platinum token", value: 1000},
+}
+
+// IsCurrency reports whether an item ID is valued at a fixed coin amount
+// rather than looked up.
+func IsCurrency(itemID int) bool {
+	_, ok := currencies[itemID]
+	return ok
+}
+
+// CurrencyValue returns the coin value of one unit of a currency item.
+func CurrencyValue(itemID int) (int64, bool) {
+	c, ok := currencies[itemID]
+	return c.value, ok
+}
+
+// Match is the Grand Exchange item an export ID resolved to.
+type Match struct {
+	Resolution string
+	Item       models.Item
+}
+
+// unresolvedReason is reported for IDs that match no Grand Exchange item.
+const unresolvedReason = "unknown or untradeable item"
+
+// Compute values a bank. matches maps export item IDs to the items they
+// resolved to; prices holds current prices by resolved item ID. Rows that
+// resolve to the same item are merged. Zero-quantity rows are placeholders
+// and only counted.
+func Compute(entries []models.BankEntry, matches map[int]Match, prices map[int]models.CurrentPrice) models.BankValuation {
+	result := models.BankValuation{
+		Items:      make([]models.BankValuationItem, 0),
+		Unresolved: make([]models.BankValuationUnresolved, 0),
+	}
+
+	lines := make(map[int]*models.BankValuationItem)
+	order := make([]int, 0)
+	line := func(itemID int, name, resolution string) *models.BankValuationItem {
+		l, ok := lines[itemID]
+		if !ok {
+			l = &models.BankValuationItem{ItemID: itemID, Name: name, Resolution: resolution, SourceItemIDs: []int{}}
+			lines[itemID] = l
+			order = append(order, itemID)
+		}
+		return l
+	}
+
+	for _, e := range entries {
+		if e.Quantity == 0 {
+			result.Placeholders++
+			continue
+		}
+
+		var l *models.BankValuationItem
+		if c, ok := currencies[e.ItemID]; ok {
+			l = line(e.ItemID, c.name, models.BankResolutionCurrency)
+			value := c.value
+			l.HighPrice, l.LowPrice, l.MidPrice = &value, &value, &value
+		} else if m, ok := matches[e.ItemID]; ok {
+			l = line(m.Item.ItemID, m.Item.Name, m.Resolution)
+			l.HighAlch = m.Item.HighAlch
+			if p, ok := prices[m.Item.ItemID]; ok {
+				l.HighPrice, l.LowPrice, l.MidPrice = p.HighPrice, p.LowPrice, midPrice(p.HighPrice, p.LowPrice)
+			}
+		} else {
+			result.Unresolved = append(result.Unresolved, models.BankValuationUnresolved{
+				ItemID:   e.ItemID,
+				Name:     e.Name,
+				Quantity: e.Quantity,
+				Reason:   unresolvedReason,
+			})
+			continue
+		}
+
+		l.Quantity += e.Quantity
+		if !slices.Contains(l.SourceItemIDs, e.ItemID) {
+			l.SourceItemIDs = append(l.SourceItemIDs, e.ItemID)
+		}
+		// A stack direct from the mapping wins over one found by name or note.
+		if e.ItemID == l.ItemID && l.Resolution != models.BankResolutionCurrency {
+			l.Resolution = models.BankResolutionDirect
+		}
+	}
+
+	for _, itemID := range order {
+		l := lines[itemID]
+		l.HighValue = times(l.HighPrice, l.Quantity)
+		l.LowValue = times(l.LowPrice, l.Quantity)
+		l.MidValue = times(l.MidPrice, l.Quantity)
+		if l.Resolution == models.BankResolutionCurrency {
+			// Coins are worth their face value however they are spent.
+			l.HighAlchValue = l.MidValue
+		} else if l.HighAlch != nil {
+			alch := int64(*l.HighAlch)
+			l.HighAlchValue = times(&alch, l.Quantity)
+		}
+
+		if l.HighValue == nil || l.LowValue == nil {
+			result.UnpricedItems++
+		}
+		result.TotalHigh += deref(l.HighValue)
+		result.TotalLow += deref(l.LowValue)
+		result.TotalMid += deref(l.MidValue)
+		result.TotalHighAlch += deref(l.HighAlchValue)
+		result.Items = append(result.Items, *l)
+	}
+
+	// Most valuable first, then by item ID for a stable order.
+	sort.Slice(result.Items, func(i, j int) bool {
+		a, b := deref(result.Items[i].MidValue), deref(result.Items[j].MidValue)
+		if a != b {
+			return a > b
+		}
+		return result.Items[i].ItemID < result.Items[j].ItemID
+	})
+	return result
+}
+
+// midPrice averages high and low, falling back to whichever side is known.
+func midPrice(high, low *int64) *int64 {
+	switch {
+	case high != nil && low != nil:
+		mid := (*high + *low) / 2
+		return &mid
+	case high != nil:
+		return high
+	default:
+		return low
+	}
+}
+
+func times(price *int64, quantity int64) *int64 {
+	if price == nil {
+		return nil
+	}
+	v := *price * quantity
+	return &v
+}
+
+func deref(v *int64) int64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
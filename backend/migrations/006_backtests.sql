@@ -0,0 +1,28 @@
+-- Migration 006: Backtests
+-- Stores asynchronous strategy backtest jobs and their results
+
+CREATE TABLE IF NOT EXISTS backtests (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100),
+    status VARCHAR(20) NOT NULL,
+    strategy JSONB NOT NULL,
+    result JSONB,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT backtest_status_check CHECK (status IN ('pending', 'running', 'completed', 'failed'))
+);
+
+-- Listing shows the most recent jobs first
+CREATE INDEX IF NOT EXISTS idx_backtests_created_at
+ON backtests(created_at DESC);
+
+-- Startup recovery looks up unfinished jobs
+CREATE INDEX IF NOT EXISTS idx_backtests_unfinished
+ON backtests(status) WHERE status IN ('pending', 'running');
+
+COMMENT ON TABLE backtests IS 'Strategy backtest jobs replayed against stored price buckets';
+COMMENT ON COLUMN backtests.strategy IS 'Normalized strategy definition as JSONB';
+COMMENT ON COLUMN backtests.result IS 'Trade log, equity curve and summary metrics once completed';
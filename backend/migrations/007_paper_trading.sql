@@ -0,0 +1,77 @@
+-- Migration 007: Paper trading
+-- Practice accounts with simulated Grand Exchange offers filled from live price syncs
+
+CREATE TABLE IF NOT EXISTS paper_accounts (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    starting_balance BIGINT NOT NULL,
+    cash BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT paper_account_cash_check CHECK (cash >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS paper_offers (
+    id BIGSERIAL PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES paper_accounts(id) ON DELETE CASCADE,
+    item_id INTEGER NOT NULL,
+    side VARCHAR(4) NOT NULL,
+    status VARCHAR(10) NOT NULL,
+    price BIGINT NOT NULL,
+    quantity BIGINT NOT NULL,
+    filled BIGINT NOT NULL DEFAULT 0,
+    tax_paid BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT paper_offer_side_check CHECK (side IN ('buy', 'sell')),
+    CONSTRAINT paper_offer_status_check CHECK (status IN ('open', 'filled', 'cancelled')),
+    CONSTRAINT paper_offer_fill_check CHECK (price > 0 AND quantity > 0 AND filled BETWEEN 0 AND quantity)
+);
+
+-- Price syncs look up open offers for the items that traded
+CREATE INDEX IF NOT EXISTS idx_paper_offers_open_item
+ON paper_offers(item_id, created_at) WHERE status = 'open';
+
+CREATE INDEX IF NOT EXISTS idx_paper_offers_account
+ON paper_offers(account_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS paper_positions (
+    account_id UUID NOT NULL REFERENCES paper_accounts(id) ON DELETE CASCADE,
+    item_id INTEGER NOT NULL,
+    quantity BIGINT NOT NULL DEFAULT 0,
+    reserved BIGINT NOT NULL DEFAULT 0,
+    cost_basis BIGINT NOT NULL DEFAULT 0,
+    realized_profit BIGINT NOT NULL DEFAULT 0,
+    tax_paid BIGINT NOT NULL DEFAULT 0,
+    buy_window_start TIMESTAMP WITH TIME ZONE,
+    buy_window_bought BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    PRIMARY KEY (account_id, item_id),
+    CONSTRAINT paper_position_quantity_check CHECK (reserved BETWEEN 0 AND quantity)
+);
+
+CREATE TABLE IF NOT EXISTS paper_fills (
+    id BIGSERIAL PRIMARY KEY,
+    offer_id BIGINT NOT NULL REFERENCES paper_offers(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES paper_accounts(id) ON DELETE CASCADE,
+    item_id INTEGER NOT NULL,
+    side VARCHAR(4) NOT NULL,
+    quantity BIGINT NOT NULL,
+    price BIGINT NOT NULL,
+    tax BIGINT NOT NULL DEFAULT 0,
+    profit BIGINT,
+    filled_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_paper_fills_account
+ON paper_fills(account_id, filled_at DESC);
+
+COMMENT ON TABLE paper_accounts IS 'Practice accounts for simulated flipping; cash excludes gold held by open buy offers';
+COMMENT ON TABLE paper_offers IS 'Simulated GE offers, filled when later price syncs cross the offer price';
+COMMENT ON COLUMN paper_positions.reserved IS 'Units committed to open sell offers';
+COMMENT ON COLUMN paper_positions.buy_window_start IS 'Start of the current 4-hour buy limit window';
+COMMENT ON TABLE paper_fills IS 'Execution log of simulated offers';
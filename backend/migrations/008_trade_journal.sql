@@ -0,0 +1,28 @@
+-- Migration 008: Trade journal
+-- Per-user log of real Grand Exchange trades used for portfolio P&L
+
+CREATE TABLE IF NOT EXISTS trade_journal_entries (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(64) NOT NULL,
+    item_id INTEGER NOT NULL,
+    side VARCHAR(4) NOT NULL,
+    quantity BIGINT NOT NULL,
+    price BIGINT NOT NULL,
+    traded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT trade_journal_side_check CHECK (side IN ('buy', 'sell')),
+    CONSTRAINT trade_journal_amount_check CHECK (quantity > 0 AND price > 0)
+);
+
+-- P&L replays a user's journal in trade order; listings filter by item
+CREATE INDEX IF NOT EXISTS idx_trade_journal_user_traded
+ON trade_journal_entries(user_id, traded_at, id);
+
+CREATE INDEX IF NOT EXISTS idx_trade_journal_user_item
+ON trade_journal_entries(user_id, item_id, traded_at);
+
+COMMENT ON TABLE trade_journal_entries IS 'Real trades recorded per user for FIFO portfolio P&L';
+COMMENT ON COLUMN trade_journal_entries.user_id IS 'Opaque client-chosen ID from the X-User-ID header';
+COMMENT ON COLUMN trade_journal_entries.price IS 'Price per unit before GE tax';
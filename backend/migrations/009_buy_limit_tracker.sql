@@ -0,0 +1,38 @@
+-- Migration 009: Buy limit tracker
+-- Per-user purchase log and 4-hour buy limit windows
+
+CREATE TABLE IF NOT EXISTS buy_limit_purchases (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(64) NOT NULL,
+    item_id INTEGER NOT NULL,
+    quantity BIGINT NOT NULL,
+    purchased_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT buy_limit_purchases_quantity_check CHECK (quantity > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_buy_limit_purchases_user_item
+ON buy_limit_purchases(user_id, item_id, purchased_at);
+
+CREATE TABLE IF NOT EXISTS buy_limit_timers (
+    user_id VARCHAR(64) NOT NULL,
+    item_id INTEGER NOT NULL,
+    window_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    resets_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    bought BIGINT NOT NULL DEFAULT 0,
+    notified BOOLEAN NOT NULL DEFAULT FALSE,
+
+    PRIMARY KEY (user_id, item_id),
+    CONSTRAINT buy_limit_timers_bought_check CHECK (bought >= 0)
+);
+
+-- The reset job scans for windows that closed but were not announced yet
+CREATE INDEX IF NOT EXISTS idx_buy_limit_timers_pending
+ON buy_limit_timers(resets_at)
+WHERE notified = FALSE;
+
+COMMENT ON TABLE buy_limit_purchases IS 'Purchases logged per user against Grand Exchange buy limits';
+COMMENT ON TABLE buy_limit_timers IS 'Current 4-hour buy limit window per user and item';
+COMMENT ON COLUMN buy_limit_timers.window_start IS 'First purchase of the window';
+COMMENT ON COLUMN buy_limit_timers.notified IS 'Whether the reset at resets_at has been announced';
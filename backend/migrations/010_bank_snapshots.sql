@@ -0,0 +1,38 @@
+-- Migration 010: Bank snapshots
+-- Saved banks per user and their recorded daily value
+
+CREATE TABLE IF NOT EXISTS bank_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(64) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_bank_snapshots_user
+ON bank_snapshots(user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS bank_snapshot_items (
+    snapshot_id BIGINT NOT NULL REFERENCES bank_snapshots(id) ON DELETE CASCADE,
+    item_id INTEGER NOT NULL,
+    quantity BIGINT NOT NULL,
+
+    PRIMARY KEY (snapshot_id, item_id),
+    CONSTRAINT bank_snapshot_items_quantity_check CHECK (quantity > 0)
+);
+
+CREATE TABLE IF NOT EXISTS bank_snapshot_values (
+    snapshot_id BIGINT NOT NULL REFERENCES bank_snapshots(id) ON DELETE CASCADE,
+    valued_on DATE NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    total_high BIGINT NOT NULL,
+    total_low BIGINT NOT NULL,
+    total_mid BIGINT NOT NULL,
+    total_high_alch BIGINT NOT NULL,
+    unpriced_items INTEGER NOT NULL,
+
+    PRIMARY KEY (snapshot_id, valued_on)
+);
+
+COMMENT ON TABLE bank_snapshots IS 'Banks saved per user for net worth tracking';
+COMMENT ON COLUMN bank_snapshot_items.item_id IS 'Resolved Grand Exchange item ID, or a currency such as coins';
+COMMENT ON TABLE bank_snapshot_values IS 'Value of each snapshot at current prices, recorded once per UTC day';
@@ -0,0 +1,14 @@
+-- Migration 011: Item set overrides
+-- Admin edits layered over the item set registry shipped with the application
+
+CREATE TABLE IF NOT EXISTS item_set_overrides (
+    set_item_id INTEGER PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    components JSONB NOT NULL,
+    removed BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+COMMENT ON TABLE item_set_overrides IS 'Item set definitions added, replaced or hidden by admins';
+COMMENT ON COLUMN item_set_overrides.components IS 'Array of {"itemId", "quantity"} pieces';
+COMMENT ON COLUMN item_set_overrides.removed IS 'Hides the shipped set with this ID';
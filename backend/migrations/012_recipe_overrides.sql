@@ -0,0 +1,13 @@
+-- Migration 012: Recipe overrides
+-- Admin edits layered over the recipe registry shipped with the application
+
+CREATE TABLE IF NOT EXISTS recipe_overrides (
+    recipe_id VARCHAR(64) PRIMARY KEY,
+    definition JSONB NOT NULL,
+    removed BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+COMMENT ON TABLE recipe_overrides IS 'Processing recipes added, replaced or hidden by admins';
+COMMENT ON COLUMN recipe_overrides.definition IS 'Recipe as {"name", "skill", "inputs", "outputs", "actionsPerHour", "xp"}';
+COMMENT ON COLUMN recipe_overrides.removed IS 'Hides the shipped recipe with this ID';
@@ -0,0 +1,30 @@
+-- Migration 013: Market indices
+-- User-defined indices and the computed value of every index per 1h bucket
+
+CREATE TABLE IF NOT EXISTS market_indices (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(64) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    weighting VARCHAR(16) NOT NULL,
+    item_ids JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT market_indices_weighting_check CHECK (weighting IN ('volume', 'equal'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_market_indices_user
+ON market_indices(user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS market_index_values (
+    index_id VARCHAR(64) NOT NULL,
+    timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    constituents INTEGER NOT NULL,
+
+    PRIMARY KEY (index_id, timestamp)
+);
+
+COMMENT ON TABLE market_indices IS 'Custom market indices defined per user';
+COMMENT ON COLUMN market_indices.item_ids IS 'Constituent item IDs as a JSON array';
+COMMENT ON TABLE market_index_values IS 'Chain-linked value of each index at the end of every 1h bucket';
+COMMENT ON COLUMN market_index_values.index_id IS 'Builtin index ID, or custom-<id> for rows of market_indices';
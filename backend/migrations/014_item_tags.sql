@@ -0,0 +1,22 @@
+-- Migration 014: Item tags
+-- Categories and tags per item, seeded by the classifier and curated by admins
+
+CREATE TABLE IF NOT EXISTS item_tags (
+    item_id INTEGER NOT NULL,
+    tag VARCHAR(32) NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    source VARCHAR(16) NOT NULL,
+    removed BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    PRIMARY KEY (item_id, tag),
+    CONSTRAINT item_tags_kind_check CHECK (kind IN ('category', 'tag')),
+    CONSTRAINT item_tags_source_check CHECK (source IN ('auto', 'admin'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_tags_tag
+ON item_tags(tag, item_id) WHERE NOT removed;
+
+COMMENT ON TABLE item_tags IS 'Many-to-many item categories and tags';
+COMMENT ON COLUMN item_tags.source IS 'auto rows are replaced whenever the classifier runs; admin rows are kept';
+COMMENT ON COLUMN item_tags.removed IS 'Admin removal that stops the classifier from adding the tag again';
@@ -0,0 +1,13 @@
+-- Migration 015: Fuzzy item search
+-- Trigram and prefix indexes over lowercased item names
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_items_name_trgm
+ON items USING GIN (lower(name) gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS idx_items_name_prefix
+ON items (lower(name) text_pattern_ops);
+
+COMMENT ON INDEX idx_items_name_trgm IS 'Serves fuzzy (similarity) and substring item search';
+COMMENT ON INDEX idx_items_name_prefix IS 'Serves exact and prefix item search';
@@ -0,0 +1,31 @@
+-- Migration 016: Item aliases
+-- Community nicknames for items, seeded from a shipped list and moderated by admins
+
+CREATE TABLE IF NOT EXISTS item_aliases (
+    id BIGSERIAL PRIMARY KEY,
+    alias VARCHAR(48) NOT NULL,
+    item_id INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    source VARCHAR(16) NOT NULL,
+    submitted_by VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT item_aliases_alias_unique UNIQUE (alias),
+    CONSTRAINT item_aliases_status_check CHECK (status IN ('pending', 'approved', 'rejected')),
+    CONSTRAINT item_aliases_source_check CHECK (source IN ('seed', 'admin', 'user'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_aliases_item
+ON item_aliases(item_id) WHERE status = 'approved';
+
+CREATE INDEX IF NOT EXISTS idx_item_aliases_status
+ON item_aliases(status, created_at);
+
+CREATE INDEX IF NOT EXISTS idx_item_aliases_alias_trgm
+ON item_aliases USING GIN (alias gin_trgm_ops) WHERE status = 'approved';
+
+COMMENT ON TABLE item_aliases IS 'Nicknames that resolve to an item, e.g. bgs for Bandos godsword';
+COMMENT ON COLUMN item_aliases.alias IS 'Lowercased with single spaces; unique across every status, so a rejected alias stays blocked until deleted';
+COMMENT ON COLUMN item_aliases.status IS 'Only approved aliases are used by search and resolution';
+COMMENT ON COLUMN item_aliases.submitted_by IS 'X-User-ID of a user submission';
@@ -0,0 +1,24 @@
+-- Migration 017: Saved screens
+-- Screener expressions saved per user, optionally checked after every price sync as alerts
+
+CREATE TABLE IF NOT EXISTS screens (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(64) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    expression TEXT NOT NULL,
+    alert BOOLEAN NOT NULL DEFAULT FALSE,
+    alert_item_ids JSONB,
+    last_alert_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_screens_user
+ON screens(user_id, created_at);
+
+CREATE INDEX IF NOT EXISTS idx_screens_alert
+ON screens(id) WHERE alert;
+
+COMMENT ON TABLE screens IS 'Saved screener expressions, owned by one user';
+COMMENT ON COLUMN screens.alert_item_ids IS 'Item IDs that matched at the last alert check, as a JSON array; NULL until the first check';
+COMMENT ON COLUMN screens.last_alert_at IS 'When items last started matching and the owner was notified';
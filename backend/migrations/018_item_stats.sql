@@ -0,0 +1,29 @@
+-- Migration 018: Item stats
+-- Per-item market figures behind the dashboard table, rewritten after every current prices sync
+
+CREATE TABLE IF NOT EXISTS item_stats (
+    item_id INTEGER PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    icon_url TEXT NOT NULL DEFAULT '',
+    members BOOLEAN NOT NULL DEFAULT FALSE,
+    category VARCHAR(64) NOT NULL DEFAULT '',
+    buy_limit BIGINT,
+    high_price BIGINT,
+    low_price BIGINT,
+    spread BIGINT,
+    change_1h DOUBLE PRECISION,
+    change_24h DOUBLE PRECISION,
+    change_7d DOUBLE PRECISION,
+    volume_1h BIGINT NOT NULL DEFAULT 0,
+    volume_24h BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- The table's default order
+CREATE INDEX IF NOT EXISTS idx_item_stats_volume_24h
+ON item_stats(volume_24h DESC, item_id);
+
+COMMENT ON TABLE item_stats IS 'Precomputed market table row per item; every row carries the time of the refresh that wrote it';
+COMMENT ON COLUMN item_stats.spread IS 'high_price - low_price, or NULL when either is unknown';
+COMMENT ON COLUMN item_stats.change_1h IS 'Percentage change of the mid price over the last hour';
+COMMENT ON COLUMN item_stats.volume_24h IS 'Units traded over the last 24 hours';
@@ -0,0 +1,9 @@
+-- Migration 019: Paper account owners
+-- Accounts record the X-User-ID that opened them so each user can be capped
+
+ALTER TABLE paper_accounts ADD COLUMN IF NOT EXISTS user_id VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_paper_accounts_user_id
+ON paper_accounts(user_id);
+
+COMMENT ON COLUMN paper_accounts.user_id IS 'User that opened the account; empty for accounts opened before owners were recorded';
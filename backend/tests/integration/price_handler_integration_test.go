@@ -56,7 +56,7 @@ func TestPriceHandler_ReadEndpoints_PostgresBacked(t *testing.T) {
 
 	cache := testutil.NewNoopCache()
 	priceSvc := services.NewPriceService(priceRepo, itemRepo, cache, "", logger)
-	priceHandler := handlers.NewPriceHandler(priceSvc, logger)
+	priceHandler := handlers.NewPriceHandler(priceSvc, nil, nil, logger)
 
 	app := fiber.New()
 	app.Get("/api/v1/prices/current", priceHandler.GetAllCurrentPrices)
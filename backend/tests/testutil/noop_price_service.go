@@ -49,3 +49,7 @@ func (n *NoopPriceService) SyncCurrentPrices(_ context.Context) error {
 func (n *NoopPriceService) RunMaintenance(_ context.Context) error {
 	return nil
 }
+
+func (n *NoopPriceService) SyncTimeseries(_ context.Context, itemID int, timestep string) error {
+	return nil
+}
@@ -47,7 +47,7 @@ var sharedPG struct {
 // necessary for reliable CI/CD execution. Complexity: 24 (acceptable for test infrastructure).
 //
 //nolint:revive,gocognit // Test container initialization requires retry logic with multiple
-func SharedPostgres(t *testing.T) (*gorm.DB, func()) {
+func SharedPostgres(t testing.TB) (*gorm.DB, func()) {
 	t.Helper()
 
 	sharedPG.once.Do(func() {
@@ -224,7 +224,7 @@ func RunMigrations(ctx context.Context, dbClient *gorm.DB) error {
 	return nil
 }
 
-func TruncateAllTables(t *testing.T, dbClient *gorm.DB) {
+func TruncateAllTables(t testing.TB, dbClient *gorm.DB) {
 	t.Helper()
 
 	// Truncate parent partitioned tables; partitions truncate too.
@@ -234,7 +234,15 @@ func TruncateAllTables(t *testing.T, dbClient *gorm.DB) {
 			"price_latest, " +
 			"price_timeseries_5m, price_timeseries_1h, price_timeseries_6h, price_timeseries_24h, price_timeseries_daily, " +
 			"items, " +
-			"watchlist_shares " +
+			"watchlist_shares, " +
+			"backtests, " +
+			"paper_accounts, paper_offers, paper_positions, paper_fills, " +
+			"trade_journal_entries, " +
+			"buy_limit_purchases, buy_limit_timers, " +
+			"bank_snapshots, bank_snapshot_items, bank_snapshot_values, " +
+			"item_set_overrides, recipe_overrides, " +
+			"market_indices, market_index_values, " +
+			"item_tags, item_aliases, screens, item_stats " +
 			"CASCADE",
 	).Error; err != nil {
 		t.Fatalf("failed to truncate tables: %v", err)
@@ -0,0 +1,307 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/activities"
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+func TestActivitiesParse(t *testing.T) {
+	yamlDoc := []byte(`
+- id: test-boss
+  name: Test boss
+  killsPerHour: 20
+  drops:
+    - {itemId: 1, rate: 1/128}
+    - {itemId: 2, minQuantity: 10, maxQuantity: 20, rate: 0.5}
+`)
+	list, err := activities.Parse(yamlDoc, ".yaml")
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.InDelta(t, 1.0/128, float64(list[0].Drops[0].Rate), 1e-12)
+	assert.Equal(t, 1, list[0].Drops[0].MinQuantity)
+	assert.Equal(t, 1, list[0].Drops[0].MaxQuantity)
+	assert.Equal(t, 20, list[0].Drops[1].MaxQuantity)
+
+	jsonDoc := []byte(`[{"id": "test-boss", "name": "Test boss", "killsPerHour": 20,
+		"drops": [{"itemId": 1, "rate": "1/64"}, {"itemId": 2, "minQuantity": 5, "rate": 1}]}]`)
+	list, err = activities.Parse(jsonDoc, ".json")
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0/64, float64(list[0].Drops[0].Rate), 1e-12)
+	assert.Equal(t, 5, list[0].Drops[1].MaxQuantity)
+
+	tests := []struct {
+		name     string
+		doc      string
+		expected string
+	}{
+		{name: "bad id", doc: `[{"id": "Test Boss", "name": "x", "killsPerHour": 1, "drops": [{"itemId": 1, "rate": 1}]}]`, expected: "id must be"},
+		{name: "no drops", doc: `[{"id": "a", "name": "x", "killsPerHour": 1}]`, expected: "a: an activity must have 1-200 drops"},
+		{name: "rate above 1", doc: `[{"id": "a", "name": "x", "killsPerHour": 1, "drops": [{"itemId": 1, "rate": 2}]}]`, expected: "rate must be above 0 and at most 1"},
+		{name: "bad fraction", doc: `[{"id": "a", "name": "x", "killsPerHour": 1, "drops": [{"itemId": 1, "rate": "1/0"}]}]`, expected: "must be a number or a fraction"},
+		{name: "bad range", doc: `[{"id": "a", "name": "x", "killsPerHour": 1, "drops": [{"itemId": 1, "minQuantity": 5, "maxQuantity": 2, "rate": 1}]}]`, expected: "quantity must be a range"},
+		{name: "no kills", doc: `[{"id": "a", "name": "x", "drops": [{"itemId": 1, "rate": 1}]}]`, expected: "killsPerHour must be above 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := activities.Parse([]byte(tt.doc), ".json")
+			require.ErrorIs(t, err, models.ErrInvalidActivity)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestActivitiesLoad(t *testing.T) {
+	builtin, err := activities.Load("")
+	require.NoError(t, err)
+	require.NotEmpty(t, builtin)
+	for _, a := range builtin {
+		assert.Equal(t, models.ActivitySourceBuiltin, a.Source)
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "custom.yml"), []byte(`
+- id: zulrah
+  name: Zulrah (scales only)
+  killsPerHour: 35
+  drops:
+    - {itemId: 12934, minQuantity: 100, maxQuantity: 299, rate: 1}
+`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "extra.json"),
+		[]byte(`[{"id": "barrows", "name": "Barrows", "killsPerHour": 8, "drops": [{"itemId": 4708, "rate": "1/392"}]}]`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("ignored"), 0o600))
+
+	list, err := activities.Load(dir)
+	require.NoError(t, err)
+	assert.Len(t, list, len(builtin)+1)
+	byID := map[string]models.Activity{}
+	for _, a := range list {
+		byID[a.ID] = a
+	}
+	assert.Equal(t, "custom.yml", byID["zulrah"].Source)
+	assert.Len(t, byID["zulrah"].Drops, 1)
+	assert.Equal(t, "extra.json", byID["barrows"].Source)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dupe.json"),
+		[]byte(`[{"id": "barrows", "name": "Barrows", "killsPerHour": 8, "drops": [{"itemId": 4708, "rate": 1}]}]`), 0o600))
+	_, err = activities.Load(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `activity "barrows" is also defined in`)
+}
+
+func testActivity() models.Activity {
+	return models.Activity{
+		ID:           "test-boss",
+		Name:         "Test boss",
+		KillsPerHour: 20,
+		Drops: []models.ActivityDrop{
+			{ItemID: 995, MinQuantity: 1_000, MaxQuantity: 3_000, Rate: 0.5}, // Coins
+			{ItemID: 10, MinQuantity: 1, MaxQuantity: 1, Rate: 0.01},         // GE priced
+			{ItemID: 11, MinQuantity: 2, MaxQuantity: 4, Rate: 1},            // High alch only
+			{ItemID: 12, MinQuantity: 1, MaxQuantity: 1, Rate: 0.1},          // No price
+		},
+	}
+}
+
+func TestActivitiesValue(t *testing.T) {
+	alch := 300
+	items := map[int]models.Item{
+		10: {ItemID: 10, Name: "Rare"},
+		11: {ItemID: 11, Name: "Junk", HighAlch: &alch},
+	}
+	prices := map[int]models.CurrentPrice{
+		10: {ItemID: 10, HighPrice: int64Ptr(1_000_000), LowPrice: int64Ptr(900_000)},
+	}
+
+	v := activities.Value(testActivity(), items, prices, models.PricingOffer)
+	require.Len(t, v.Drops, 4)
+	// Coins: 0.5 * 2,000 at face value.
+	assert.InDelta(t, 1_000, v.Drops[0].ExpectedValue, 1e-9)
+	assert.Equal(t, models.DropPriceCoins, v.Drops[0].PriceSource)
+	// Rare: 1% of 1,000,000 less 20,000 tax.
+	assert.Equal(t, int64(980_000), *v.Drops[1].UnitPrice)
+	assert.InDelta(t, 9_800, v.Drops[1].ExpectedValue, 1e-9)
+	assert.Equal(t, models.DropPriceGE, v.Drops[1].PriceSource)
+	// Junk: 3 per kill at its 300 alch value.
+	assert.InDelta(t, 900, v.Drops[2].ExpectedValue, 1e-9)
+	assert.Equal(t, models.DropPriceAlch, v.Drops[2].PriceSource)
+	assert.Nil(t, v.Drops[3].UnitPrice)
+	assert.Equal(t, []int{12}, v.Unpriced)
+
+	assert.InDelta(t, 11_700, v.ValuePerKill, 1e-9)
+	assert.InDelta(t, 234_000, v.ValuePerHour, 1e-9)
+
+	instant := activities.Value(testActivity(), items, prices, models.PricingInstant)
+	assert.Equal(t, int64(882_000), *instant.Drops[1].UnitPrice)
+}
+
+func TestActivityService_ValueHistory(t *testing.T) {
+	day1 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	// Day 1 has been rolled up; day 2 is still a 24h bucket.
+	prices := &fakePriceRepo{
+		dailyPoints: map[int][]models.PriceTimeseriesDaily{
+			10: {{ItemID: 10, Day: day1, AvgHighPrice: int64Ptr(1_000_000)}},
+		},
+		timeseriesPoints: map[int][]models.PriceTimeseriesPoint{
+			10: {{ItemID: 10, Timestamp: day2, AvgHighPrice: int64Ptr(500_000)}},
+		},
+	}
+	alch := 300
+	itemRepo := &fakeItemRepo{itemsByID: map[int]*models.Item{11: {ItemID: 11, HighAlch: &alch}}}
+	svc := services.NewActivityService([]models.Activity{testActivity()}, itemRepo, prices, zap.NewNop().Sugar())
+	ctx := context.Background()
+
+	history, err := svc.ActivityValueHistory(ctx, "test-boss", models.ActivityHistoryParams{From: day1, To: day2.Add(time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, history.Points, 2)
+	assert.Equal(t, day1, history.Points[0].Day)
+	assert.InDelta(t, 11_700, history.Points[0].ValuePerKill, 1e-9)
+	assert.InDelta(t, 1_000+4_900+900, history.Points[1].ValuePerKill, 1e-9)
+	assert.Equal(t, 1, history.Points[1].Unpriced)
+	// Every drop's history comes from one query per table.
+	assert.Equal(t, 1, prices.dailyPointsCalls)
+	assert.Equal(t, 1, prices.timeseriesForItemsCalls)
+
+	missing, err := svc.ActivityValueHistory(ctx, "nope", models.ActivityHistoryParams{})
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	_, err = svc.ActivityValueHistory(ctx, "test-boss", models.ActivityHistoryParams{From: day2, To: day1})
+	require.ErrorIs(t, err, models.ErrInvalidActivity)
+
+	_, err = svc.ActivityValueHistory(ctx, "test-boss", models.ActivityHistoryParams{From: day1.AddDate(-2, 0, 0), To: day1})
+	require.ErrorIs(t, err, models.ErrInvalidActivity)
+}
+
+func TestActivityService_ActivityValues(t *testing.T) {
+	list := []models.Activity{
+		{ID: "slow", Name: "Slow", KillsPerHour: 1, Drops: []models.ActivityDrop{{ItemID: 995, MinQuantity: 100, MaxQuantity: 100, Rate: 1}}},
+		{ID: "fast", Name: "Fast", KillsPerHour: 10, Drops: []models.ActivityDrop{{ItemID: 995, MinQuantity: 100, MaxQuantity: 100, Rate: 1}}},
+	}
+	svc := services.NewActivityService(list, &fakeItemRepo{}, &fakePriceRepo{}, zap.NewNop().Sugar())
+
+	values, err := svc.ActivityValues(context.Background(), models.PricingOffer)
+	require.NoError(t, err)
+	require.Len(t, values, 2)
+	assert.Equal(t, "fast", values[0].ID)
+	assert.InDelta(t, 1_000, values[0].ValuePerHour, 1e-9)
+
+	value, err := svc.ActivityValue(context.Background(), "nope", models.PricingOffer)
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestActivityHandler(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		setup    func(m *MockActivityService)
+		name     string
+		path     string
+		expected string
+		status   int
+	}{
+		{
+			name:   "list",
+			path:   "/activities",
+			status: 200,
+			setup: func(m *MockActivityService) {
+				m.On("ActivityValues", mock.Anything, models.PricingOffer).
+					Return([]models.ActivityValue{{ID: "zulrah", Drops: []models.ActivityDropValue{{ItemID: 1}}}}, nil)
+			},
+		},
+		{name: "list bad pricing", path: "/activities?pricing=x", status: 400, expected: "pricing must be offer or instant"},
+		{
+			name:   "definitions",
+			path:   "/activities/definitions",
+			status: 200,
+			setup: func(m *MockActivityService) {
+				m.On("ListActivities", mock.Anything).Return([]models.Activity{})
+			},
+		},
+		{
+			name:   "value",
+			path:   "/activities/zulrah/value?pricing=instant",
+			status: 200,
+			setup: func(m *MockActivityService) {
+				m.On("ActivityValue", mock.Anything, "zulrah", models.PricingInstant).Return(&models.ActivityValue{ID: "zulrah"}, nil)
+			},
+		},
+		{
+			name:   "value missing",
+			path:   "/activities/nope/value",
+			status: 404,
+			setup: func(m *MockActivityService) {
+				m.On("ActivityValue", mock.Anything, "nope", models.PricingOffer).Return(nil, nil)
+			},
+			expected: "activity not found",
+		},
+		{
+			name:   "history",
+			path:   "/activities/zulrah/value/history?from=2026-03-01",
+			status: 200,
+			setup: func(m *MockActivityService) {
+				m.On("ActivityValueHistory", mock.Anything, "zulrah", models.ActivityHistoryParams{From: from}).
+					Return(&models.ActivityValueHistory{ID: "zulrah", Points: []models.ActivityValuePoint{}}, nil)
+			},
+		},
+		{name: "history bad from", path: "/activities/zulrah/value/history?from=soon", status: 400, expected: "invalid from timestamp"},
+		{
+			name:   "history bad range",
+			path:   "/activities/zulrah/value/history",
+			status: 400,
+			setup: func(m *MockActivityService) {
+				m.On("ActivityValueHistory", mock.Anything, "zulrah", models.ActivityHistoryParams{}).
+					Return(nil, fmt.Errorf("%w: from must be before to", models.ErrInvalidActivity))
+			},
+			expected: "from must be before to",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockActivityService := new(MockActivityService)
+			if tt.setup != nil {
+				tt.setup(mockActivityService)
+			}
+			handler := handlers.NewActivityHandler(mockActivityService, logger)
+
+			app := fiber.New()
+			app.Get("/activities", handler.ListActivityValues)
+			app.Get("/activities/definitions", handler.ListActivities)
+			app.Get("/activities/:id/value", handler.GetActivityValue)
+			app.Get("/activities/:id/value/history", handler.GetActivityValueHistory)
+
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var result map[string]any
+			_ = json.NewDecoder(resp.Body).Decode(&result)
+			if tt.expected != "" {
+				assert.Equal(t, tt.expected, result["error"])
+			} else {
+				assert.NotNil(t, result["data"])
+			}
+			mockActivityService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,581 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/arbitrage"
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/middleware"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// fakeItemSetRepo keeps set registry overrides in memory.
+type fakeItemSetRepo struct {
+	overrides map[int]models.ItemSetOverride
+}
+
+func (r *fakeItemSetRepo) ListOverrides(_ context.Context) ([]models.ItemSetOverride, error) {
+	overrides := make([]models.ItemSetOverride, 0, len(r.overrides))
+	for _, o := range r.overrides {
+		overrides = append(overrides, o)
+	}
+	return overrides, nil
+}
+
+func (r *fakeItemSetRepo) UpsertOverride(_ context.Context, override *models.ItemSetOverride) error {
+	if r.overrides == nil {
+		r.overrides = map[int]models.ItemSetOverride{}
+	}
+	r.overrides[override.SetItemID] = *override
+	return nil
+}
+
+func (r *fakeItemSetRepo) DeleteOverride(_ context.Context, setItemID int) (bool, error) {
+	_, ok := r.overrides[setItemID]
+	delete(r.overrides, setItemID)
+	return ok, nil
+}
+
+func TestDefaultSets_AreValidAndUnique(t *testing.T) {
+	sets, err := arbitrage.DefaultSets()
+	require.NoError(t, err)
+	require.NotEmpty(t, sets)
+
+	seen := map[int]bool{}
+	for _, set := range sets {
+		assert.False(t, seen[set.SetItemID], "duplicate set %d", set.SetItemID)
+		seen[set.SetItemID] = true
+		assert.Equal(t, models.ItemSetSourceBuiltin, set.Source)
+	}
+}
+
+func TestValidateSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		set      models.ItemSet
+	}{
+		{name: "missing id", set: models.ItemSet{Name: "x"}, expected: "setItemId is required"},
+		{name: "blank name", set: models.ItemSet{SetItemID: 1, Name: "  "}, expected: "name must be 1-100 characters"},
+		{
+			name:     "one component",
+			set:      models.ItemSet{SetItemID: 1, Name: "x", Components: []models.ItemSetComponent{{ItemID: 2}}},
+			expected: "a set must have 2-10 components",
+		},
+		{
+			name:     "contains itself",
+			set:      models.ItemSet{SetItemID: 1, Name: "x", Components: []models.ItemSetComponent{{ItemID: 2}, {ItemID: 1}}},
+			expected: "component 2: a set cannot contain itself",
+		},
+		{
+			name:     "duplicate piece",
+			set:      models.ItemSet{SetItemID: 1, Name: "x", Components: []models.ItemSetComponent{{ItemID: 2}, {ItemID: 2}}},
+			expected: "component 2: item 2 is listed twice",
+		},
+		{
+			name:     "bad quantity",
+			set:      models.ItemSet{SetItemID: 1, Name: "x", Components: []models.ItemSetComponent{{ItemID: 2}, {ItemID: 3, Quantity: -1}}},
+			expected: "component 2: quantity must be between 1 and 1000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := arbitrage.ValidateSet(&tt.set)
+			require.ErrorIs(t, err, models.ErrInvalidItemSet)
+			assert.Equal(t, tt.expected, err.Error()[len(models.ErrInvalidItemSet.Error())+2:])
+		})
+	}
+
+	set := models.ItemSet{SetItemID: 1, Name: " Set ", Components: []models.ItemSetComponent{{ItemID: 2}, {ItemID: 3, Quantity: 2}}}
+	require.NoError(t, arbitrage.ValidateSet(&set))
+	assert.Equal(t, "Set", set.Name)
+	assert.Equal(t, 1, set.Components[0].Quantity)
+}
+
+func TestMergeSets(t *testing.T) {
+	defaults := []models.ItemSet{
+		{SetItemID: 10, Name: "B set", Source: models.ItemSetSourceBuiltin, Components: []models.ItemSetComponent{{ItemID: 1, Quantity: 1}, {ItemID: 2, Quantity: 1}}},
+		{SetItemID: 20, Name: "C set", Source: models.ItemSetSourceBuiltin, Components: []models.ItemSetComponent{{ItemID: 3, Quantity: 1}, {ItemID: 4, Quantity: 1}}},
+	}
+	overrides := []models.ItemSetOverride{
+		{SetItemID: 10, Name: "B set", Components: []byte(`[{"itemId":1,"quantity":2},{"itemId":2,"quantity":1}]`)},
+		{SetItemID: 20, Removed: true, Components: []byte(`[]`)},
+		{SetItemID: 30, Name: "A set", Components: []byte(`[{"itemId":5},{"itemId":6}]`)},
+		{SetItemID: 40, Name: "Broken", Components: []byte(`[{"itemId":7}]`)},
+	}
+
+	sets, err := arbitrage.MergeSets(defaults, overrides)
+	require.ErrorIs(t, err, models.ErrInvalidItemSet)
+	require.Len(t, sets, 2)
+	assert.Equal(t, 30, sets[0].SetItemID)
+	assert.Equal(t, models.ItemSetSourceCustom, sets[0].Source)
+	assert.Equal(t, 1, sets[0].Components[0].Quantity)
+	assert.Equal(t, 10, sets[1].SetItemID)
+	assert.Equal(t, models.ItemSetSourceCustom, sets[1].Source)
+	assert.Equal(t, 2, sets[1].Components[0].Quantity)
+}
+
+func TestEvaluateSets(t *testing.T) {
+	sets := []models.ItemSet{
+		{SetItemID: 100, Name: "Pair set", Components: []models.ItemSetComponent{{ItemID: 1, Quantity: 1}, {ItemID: 2, Quantity: 2}}},
+		{SetItemID: 200, Name: "Unpriced set", Components: []models.ItemSetComponent{{ItemID: 3, Quantity: 1}, {ItemID: 4, Quantity: 1}}},
+	}
+	setLimit, leftLimit, rightLimit := 8, 10, 6
+	items := map[int]models.Item{
+		100: {ItemID: 100, Name: "Pair set", BuyLimit: &setLimit},
+		1:   {ItemID: 1, Name: "Left", BuyLimit: &leftLimit},
+		2:   {ItemID: 2, Name: "Right", BuyLimit: &rightLimit},
+	}
+	prices := map[int]models.CurrentPrice{
+		100: {ItemID: 100, HighPrice: int64Ptr(10_000), LowPrice: int64Ptr(9_000)},
+		1:   {ItemID: 1, HighPrice: int64Ptr(5_000), LowPrice: int64Ptr(4_000)},
+		2:   {ItemID: 2, HighPrice: int64Ptr(2_500), LowPrice: int64Ptr(2_000)},
+	}
+
+	results, unpriced := arbitrage.EvaluateSets(sets, items, prices, models.PricingOffer)
+	require.Len(t, results, 1)
+	assert.Equal(t, []int{200}, unpriced)
+
+	a := results[0]
+	require.NotNil(t, a.Combine)
+	// Buy the pieces at their low prices, sell the set at its high price less 2% tax.
+	assert.Equal(t, int64(8_000), a.Combine.Cost)
+	assert.Equal(t, int64(200), a.Combine.Tax)
+	assert.Equal(t, int64(9_800), a.Combine.Revenue)
+	assert.Equal(t, int64(1_800), a.Combine.Profit)
+	assert.InDelta(t, 22.5, a.Combine.ROI, 0.001)
+	// The right piece's limit of 6 allows 3 sets per window.
+	require.NotNil(t, a.Combine.MaxSets)
+	assert.Equal(t, int64(3), *a.Combine.MaxSets)
+	assert.Equal(t, int64(5_400), *a.Combine.ProfitPerWindow)
+
+	require.NotNil(t, a.Split)
+	// Buy the set at its low price, sell the pieces at their high prices less tax.
+	assert.Equal(t, int64(9_000), a.Split.Cost)
+	assert.Equal(t, int64(200), a.Split.Tax)
+	assert.Equal(t, int64(800), a.Split.Profit)
+	assert.Equal(t, int64(8), *a.Split.MaxSets)
+	assert.Equal(t, models.SetDirectionCombine, a.Best)
+	assert.Equal(t, "Right", a.Components[1].Name)
+
+	instant, _ := arbitrage.EvaluateSets(sets, items, prices, models.PricingInstant)
+	require.Len(t, instant, 1)
+	assert.Equal(t, int64(9_000-180-10_000), instant[0].Combine.Profit)
+	assert.Equal(t, int64(8_000-160-10_000), instant[0].Split.Profit)
+}
+
+func TestSortSets(t *testing.T) {
+	trade := func(profit int64, roi float64, perWindow *int64) *models.SetArbitrageTrade {
+		return &models.SetArbitrageTrade{Profit: profit, ROI: roi, ProfitPerWindow: perWindow}
+	}
+	sets := []models.SetArbitrage{
+		{SetItemID: 1, Best: models.SetDirectionCombine, Combine: trade(100, 1, nil)},
+		{SetItemID: 2, Best: models.SetDirectionCombine, Combine: trade(50, 5, int64Ptr(500))},
+		{SetItemID: 3, Best: models.SetDirectionSplit, Split: trade(10, 2, int64Ptr(1_000))},
+	}
+	ids := func() []int {
+		out := make([]int, 0, len(sets))
+		for _, s := range sets {
+			out = append(out, s.SetItemID)
+		}
+		return out
+	}
+
+	arbitrage.SortSets(sets, arbitrage.SortByROI)
+	assert.Equal(t, []int{2, 3, 1}, ids())
+	arbitrage.SortSets(sets, arbitrage.SortByProfitPerWindow)
+	assert.Equal(t, []int{3, 2, 1}, ids())
+	arbitrage.SortSets(sets, arbitrage.SortByProfit)
+	assert.Equal(t, []int{1, 2, 3}, ids())
+}
+
+func TestDefaultDoseGroups_AreValid(t *testing.T) {
+	groups, err := arbitrage.DefaultDoseGroups()
+	require.NoError(t, err)
+	require.NotEmpty(t, groups)
+
+	seen := map[int]bool{}
+	for _, g := range groups {
+		for _, v := range g.Variants {
+			assert.False(t, seen[v.ItemID], "item %d is in two groups", v.ItemID)
+			seen[v.ItemID] = true
+		}
+	}
+}
+
+func TestEvaluateDoseGroups(t *testing.T) {
+	groups := []models.DoseGroup{
+		{Name: "Prayer potion", Variants: []models.DoseVariant{{ItemID: 2434, Doses: 4}, {ItemID: 139, Doses: 3}, {ItemID: 143, Doses: 1}}},
+		{Name: "Unpriced potion", Variants: []models.DoseVariant{{ItemID: 1, Doses: 4}, {ItemID: 2, Doses: 2}}},
+	}
+	fourLimit, threeLimit := 10_000, 2_000
+	items := map[int]models.Item{
+		2434: {ItemID: 2434, Name: "Prayer potion(4)", BuyLimit: &fourLimit},
+		139:  {ItemID: 139, Name: "Prayer potion(3)", BuyLimit: &threeLimit},
+		143:  {ItemID: 143, Name: "Prayer potion(1)"},
+	}
+	prices := map[int]models.CurrentPrice{
+		2434: {ItemID: 2434, HighPrice: int64Ptr(10_000), LowPrice: int64Ptr(9_800)},
+		139:  {ItemID: 139, HighPrice: int64Ptr(7_000), LowPrice: int64Ptr(6_600)},
+		143:  {ItemID: 143, LowPrice: int64Ptr(2_000)},
+	}
+
+	results, unpriced := arbitrage.EvaluateDoseGroups(groups, items, prices, models.PricingOffer)
+	require.Len(t, results, 1)
+	assert.Equal(t, []string{"Unpriced potion"}, unpriced)
+
+	a := results[0]
+	require.Len(t, a.Variants, 3)
+	assert.InDelta(t, 2_200, *a.Variants[1].BuyPerDose, 0.001)
+	assert.InDelta(t, (7_000-140)/3.0, *a.Variants[1].SellPerDose, 0.001)
+	assert.Nil(t, a.Variants[2].SellPerDose)
+
+	// The (1) has no high price to sell at, so it can only be bought.
+	require.Len(t, a.Trades, 4)
+	require.NotNil(t, a.Best)
+	// 4 doses: buy four (1) at 2,000 and sell one (4) at 10,000 less 200 tax.
+	assert.Equal(t, 143, a.Best.FromItemID)
+	assert.Equal(t, 2434, a.Best.ToItemID)
+	assert.Equal(t, int64(1_800), a.Best.Profit)
+	assert.InDelta(t, 450, a.Best.ProfitPerDose, 0.001)
+	assert.Nil(t, a.Best.MaxBatches, "the (1) has no known buy limit")
+
+	var threeToFour *models.DecantTrade
+	for i := range a.Trades {
+		if a.Trades[i].FromItemID == 139 && a.Trades[i].ToItemID == 2434 {
+			threeToFour = &a.Trades[i]
+		}
+	}
+	require.NotNil(t, threeToFour)
+	// 12 doses: buy four (3) at 6,600 and sell three (4) at 10,000 less 200 tax each.
+	assert.Equal(t, int64(4), threeToFour.Bought)
+	assert.Equal(t, int64(3), threeToFour.Sold)
+	assert.Equal(t, int64(26_400), threeToFour.Cost)
+	assert.Equal(t, int64(600), threeToFour.Tax)
+	assert.Equal(t, int64(29_400), threeToFour.Revenue)
+	assert.Equal(t, int64(3_000), threeToFour.Profit)
+	assert.InDelta(t, 250, threeToFour.ProfitPerDose, 0.001)
+	// The (3) limit of 2,000 buys 500 batches.
+	assert.Equal(t, int64(500), *threeToFour.MaxBatches)
+	assert.Equal(t, int64(1_500_000), *threeToFour.ProfitPerWindow)
+}
+
+func TestSortDoseGroups(t *testing.T) {
+	groups := []models.DoseArbitrage{
+		{Name: "a", Best: &models.DecantTrade{ProfitPerDose: 10, ROI: 1}},
+		{Name: "b", Best: &models.DecantTrade{ProfitPerDose: 5, ROI: 3, ProfitPerWindow: int64Ptr(100)}},
+	}
+	arbitrage.SortDoseGroups(groups, arbitrage.SortByROI)
+	assert.Equal(t, "b", groups[0].Name)
+	arbitrage.SortDoseGroups(groups, arbitrage.SortByProfit)
+	assert.Equal(t, "a", groups[0].Name)
+	arbitrage.SortDoseGroups(groups, arbitrage.SortByProfitPerWindow)
+	assert.Equal(t, "b", groups[0].Name)
+}
+
+func TestArbitrageService_SetRegistry(t *testing.T) {
+	defaults, err := arbitrage.DefaultSets()
+	require.NoError(t, err)
+	builtin := defaults[0].SetItemID
+
+	repo := &fakeItemSetRepo{}
+	cache := newMemoryCache()
+	svc := services.NewArbitrageService(repo, &fakeItemRepo{}, &fakePriceRepo{}, cache, zap.NewNop().Sugar())
+	ctx := context.Background()
+
+	_, err = svc.SaveSet(ctx, models.ItemSet{SetItemID: 1, Name: "Bad"})
+	require.ErrorIs(t, err, models.ErrInvalidItemSet)
+
+	require.NoError(t, svc.RefreshSetArbitrage(ctx))
+	assert.Len(t, cache.kv, 2)
+
+	saved, err := svc.SaveSet(ctx, models.ItemSet{
+		SetItemID:  99_999,
+		Name:       "Custom set",
+		Components: []models.ItemSetComponent{{ItemID: 1}, {ItemID: 2}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.ItemSetSourceCustom, saved.Source)
+	assert.Empty(t, cache.kv, "saving a set invalidates cached reports")
+
+	sets, err := svc.ListSets(ctx)
+	require.NoError(t, err)
+	assert.Len(t, sets, len(defaults)+1)
+
+	deleted, err := svc.DeleteSet(ctx, builtin)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	assert.True(t, repo.overrides[builtin].Removed)
+
+	deleted, err = svc.DeleteSet(ctx, builtin)
+	require.NoError(t, err)
+	assert.False(t, deleted, "a hidden builtin set is already gone")
+
+	deleted, err = svc.DeleteSet(ctx, 99_999)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	sets, err = svc.ListSets(ctx)
+	require.NoError(t, err)
+	assert.Len(t, sets, len(defaults)-1)
+}
+
+func TestArbitrageService_SetArbitrageUsesCache(t *testing.T) {
+	cache := newMemoryCache()
+	svc := services.NewArbitrageService(&fakeItemSetRepo{}, &fakeItemRepo{}, &fakePriceRepo{}, cache, zap.NewNop().Sugar())
+	ctx := context.Background()
+
+	cached := models.SetArbitrageReport{Pricing: models.PricingInstant, Sets: []models.SetArbitrage{{SetItemID: 7, Best: "combine"}}}
+	require.NoError(t, cache.SetJSON(ctx, "arbitrage:sets:instant", cached, 0))
+
+	report, err := svc.SetArbitrage(ctx, models.PricingInstant)
+	require.NoError(t, err)
+	require.Len(t, report.Sets, 1)
+	assert.Equal(t, 7, report.Sets[0].SetItemID)
+
+	// Without prices every shipped set is unpriced.
+	report, err = svc.SetArbitrage(ctx, models.PricingOffer)
+	require.NoError(t, err)
+	assert.Empty(t, report.Sets)
+	assert.NotEmpty(t, report.Unpriced)
+}
+
+func TestArbitrageService_DoseArbitrage(t *testing.T) {
+	cache := newMemoryCache()
+	prices := &fakePriceRepo{currentPrices: []models.CurrentPrice{
+		{ItemID: 2434, HighPrice: int64Ptr(10_000), LowPrice: int64Ptr(9_800)},
+		{ItemID: 139, HighPrice: int64Ptr(7_000), LowPrice: int64Ptr(6_600)},
+	}}
+	svc := services.NewArbitrageService(&fakeItemSetRepo{}, &fakeItemRepo{}, prices, cache, zap.NewNop().Sugar())
+	ctx := context.Background()
+
+	require.NoError(t, svc.RefreshDoseArbitrage(ctx))
+	assert.Contains(t, cache.kv, "arbitrage:doses:offer")
+	assert.Contains(t, cache.kv, "arbitrage:doses:instant")
+
+	report, err := svc.DoseArbitrage(ctx, models.PricingOffer)
+	require.NoError(t, err)
+	require.Len(t, report.Groups, 1)
+	assert.Equal(t, "Prayer potion", report.Groups[0].Name)
+	assert.Equal(t, 139, report.Groups[0].Best.FromItemID)
+	assert.NotContains(t, report.Unpriced, "Prayer potion")
+}
+
+func TestArbitrageHandler(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	report := func() *models.SetArbitrageReport {
+		return &models.SetArbitrageReport{
+			Pricing: models.PricingOffer,
+			Sets: []models.SetArbitrage{
+				{SetItemID: 1, Best: models.SetDirectionCombine, Combine: &models.SetArbitrageTrade{Profit: 100, ROI: 1}},
+				{SetItemID: 2, Best: models.SetDirectionCombine, Combine: &models.SetArbitrageTrade{Profit: 50, ROI: 5}},
+			},
+		}
+	}
+
+	tests := []struct {
+		setup    func(m *MockArbitrageService)
+		check    func(t *testing.T, result map[string]any)
+		name     string
+		method   string
+		path     string
+		body     string
+		adminKey string
+		expected string
+		status   int
+	}{
+		{
+			name:   "sets default pricing",
+			method: "GET",
+			path:   "/arbitrage/sets",
+			status: 200,
+			setup: func(m *MockArbitrageService) {
+				m.On("SetArbitrage", mock.Anything, models.PricingOffer).Return(report(), nil)
+			},
+		},
+		{
+			name:   "sets by roi with limit",
+			method: "GET",
+			path:   "/arbitrage/sets?pricing=instant&sort=roi&limit=1",
+			status: 200,
+			setup: func(m *MockArbitrageService) {
+				m.On("SetArbitrage", mock.Anything, models.PricingInstant).Return(report(), nil)
+			},
+			check: func(t *testing.T, result map[string]any) {
+				sets := result["data"].(map[string]any)["sets"].([]any)
+				require.Len(t, sets, 1)
+				assert.InDelta(t, 2, sets[0].(map[string]any)["setItemId"], 0)
+				assert.InDelta(t, 2, result["meta"].(map[string]any)["total"], 0)
+			},
+		},
+		{name: "bad pricing", method: "GET", path: "/arbitrage/sets?pricing=cheap", status: 400, expected: "pricing must be offer or instant"},
+		{name: "bad sort", method: "GET", path: "/arbitrage/sets?sort=name", status: 400, expected: "sort must be one of profit, roi, profit_per_window"},
+		{name: "bad limit", method: "GET", path: "/arbitrage/sets?limit=0", status: 400, expected: "limit must be a positive integer"},
+		{
+			name:   "sets error",
+			method: "GET",
+			path:   "/arbitrage/sets",
+			status: 500,
+			setup: func(m *MockArbitrageService) {
+				m.On("SetArbitrage", mock.Anything, models.PricingOffer).Return(nil, errors.New("db down"))
+			},
+			expected: "failed to compute set arbitrage",
+		},
+		{
+			name:   "definitions",
+			method: "GET",
+			path:   "/arbitrage/sets/definitions",
+			status: 200,
+			setup: func(m *MockArbitrageService) {
+				m.On("ListSets", mock.Anything).Return([]models.ItemSet{}, nil)
+			},
+		},
+		{
+			name:   "doses",
+			method: "GET",
+			path:   "/arbitrage/doses?sort=profit_per_window&limit=1",
+			status: 200,
+			setup: func(m *MockArbitrageService) {
+				m.On("DoseArbitrage", mock.Anything, models.PricingOffer).Return(&models.DoseArbitrageReport{
+					Groups: []models.DoseArbitrage{
+						{Name: "a", Best: &models.DecantTrade{ProfitPerDose: 10}},
+						{Name: "b", Best: &models.DecantTrade{ProfitPerDose: 5, ProfitPerWindow: int64Ptr(100)}},
+					},
+				}, nil)
+			},
+			check: func(t *testing.T, result map[string]any) {
+				groups := result["data"].(map[string]any)["groups"].([]any)
+				require.Len(t, groups, 1)
+				assert.Equal(t, "b", groups[0].(map[string]any)["name"])
+			},
+		},
+		{name: "doses bad pricing", method: "GET", path: "/arbitrage/doses?pricing=x", status: 400, expected: "pricing must be offer or instant"},
+		{
+			name:   "dose definitions",
+			method: "GET",
+			path:   "/arbitrage/doses/definitions",
+			status: 200,
+			setup: func(m *MockArbitrageService) {
+				m.On("ListDoseGroups", mock.Anything).Return([]models.DoseGroup{}, nil)
+			},
+		},
+		{name: "admin without key", method: "PUT", path: "/admin/arbitrage/sets/5", body: "{}", status: 401, expected: "invalid or missing X-Admin-Key header"},
+		{name: "admin wrong key", method: "PUT", path: "/admin/arbitrage/sets/5", body: "{}", adminKey: "nope", status: 401, expected: "invalid or missing X-Admin-Key header"},
+		{
+			name:     "save",
+			method:   "PUT",
+			path:     "/admin/arbitrage/sets/5",
+			body:     `{"name":"Five set","components":[{"itemId":1},{"itemId":2}]}`,
+			adminKey: "secret",
+			status:   200,
+			setup: func(m *MockArbitrageService) {
+				m.On("SaveSet", mock.Anything, models.ItemSet{
+					SetItemID:  5,
+					Name:       "Five set",
+					Components: []models.ItemSetComponent{{ItemID: 1}, {ItemID: 2}},
+				}).Return(&models.ItemSet{SetItemID: 5}, nil)
+			},
+		},
+		{
+			name:     "save invalid",
+			method:   "PUT",
+			path:     "/admin/arbitrage/sets/5",
+			body:     `{"name":"Five set"}`,
+			adminKey: "secret",
+			status:   400,
+			setup: func(m *MockArbitrageService) {
+				m.On("SaveSet", mock.Anything, mock.Anything).
+					Return(nil, fmt.Errorf("%w: a set must have 2-10 components", models.ErrInvalidItemSet))
+			},
+			expected: "a set must have 2-10 components",
+		},
+		{name: "save bad id", method: "PUT", path: "/admin/arbitrage/sets/abc", body: "{}", adminKey: "secret", status: 400, expected: "invalid set item ID"},
+		{
+			name:     "delete",
+			method:   "DELETE",
+			path:     "/admin/arbitrage/sets/5",
+			adminKey: "secret",
+			status:   204,
+			setup: func(m *MockArbitrageService) {
+				m.On("DeleteSet", mock.Anything, 5).Return(true, nil)
+			},
+		},
+		{
+			name:     "delete missing",
+			method:   "DELETE",
+			path:     "/admin/arbitrage/sets/6",
+			adminKey: "secret",
+			status:   404,
+			setup: func(m *MockArbitrageService) {
+				m.On("DeleteSet", mock.Anything, 6).Return(false, nil)
+			},
+			expected: "item set not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockArbitrageService := new(MockArbitrageService)
+			if tt.setup != nil {
+				tt.setup(mockArbitrageService)
+			}
+			handler := handlers.NewArbitrageHandler(mockArbitrageService, logger)
+
+			app := fiber.New()
+			app.Get("/arbitrage/sets/definitions", handler.ListSets)
+			app.Get("/arbitrage/sets", handler.GetSetArbitrage)
+			app.Get("/arbitrage/doses/definitions", handler.ListDoseGroups)
+			app.Get("/arbitrage/doses", handler.GetDoseArbitrage)
+			admin := app.Group("/admin", middleware.RequireAdminKey("secret"))
+			admin.Put("/arbitrage/sets/:setItemId", handler.SaveSet)
+			admin.Delete("/arbitrage/sets/:setItemId", handler.DeleteSet)
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.adminKey != "" {
+				req.Header.Set(middleware.AdminKeyHeader, tt.adminKey)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var result map[string]any
+			_ = json.NewDecoder(resp.Body).Decode(&result)
+			switch {
+			case tt.expected != "":
+				assert.Equal(t, tt.expected, result["error"])
+			case tt.status < 300 && tt.status != 204:
+				assert.NotNil(t, result["data"])
+			}
+			if tt.check != nil {
+				tt.check(t, result)
+			}
+			mockArbitrageService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRequireAdminKey_DisabledWithoutKey(t *testing.T) {
+	app := fiber.New()
+	app.Get("/admin", middleware.RequireAdminKey(""), func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	req := httptest.NewRequest("GET", "/admin", nil)
+	req.Header.Set(middleware.AdminKeyHeader, "")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
@@ -0,0 +1,246 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/autocomplete"
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+func autocompleteTestEntries() []autocomplete.Entry {
+	return []autocomplete.Entry{
+		{ItemID: 4151, Name: "Abyssal whip", Popularity: 900},
+		{ItemID: 12006, Name: "Abyssal tentacle", Aliases: []string{"tent"}, Popularity: 400},
+		{ItemID: 11804, Name: "Bandos godsword", Aliases: []string{"bgs"}, Popularity: 300},
+		{ItemID: 11802, Name: "Armadyl godsword", Aliases: []string{"ags"}, Popularity: 500},
+		{ItemID: 2434, Name: "Prayer potion(4)", Aliases: []string{"ppot"}, Popularity: 5000},
+		{ItemID: 6685, Name: "Saradomin brew(4)", Aliases: []string{"sara brew"}, Popularity: 2000},
+		{ItemID: 1215, Name: "Dragon dagger", Popularity: 50},
+		{ItemID: 5698, Name: "Dragon dagger(p++)", Popularity: 700},
+		{ItemID: 2528, Name: "Lamp", Popularity: 1},
+		{ItemID: 3048, Name: "Black d'hide body", Popularity: 10},
+	}
+}
+
+func completionIDs(entries []autocomplete.Entry) []int {
+	ids := make([]int, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ItemID
+	}
+	return ids
+}
+
+func TestAutocompleteIndex_Complete(t *testing.T) {
+	index := autocomplete.NewIndex(autocompleteTestEntries())
+	require.Equal(t, 10, index.Len())
+
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  []int
+	}{
+		{name: "name prefix by popularity", query: "aby", limit: 10, want: []int{4151, 12006}},
+		{name: "later word", query: "godsw", limit: 10, want: []int{11802, 11804}},
+		{name: "alias", query: "bg", limit: 10, want: []int{11804}},
+		{name: "multi-word alias", query: "sara b", limit: 10, want: []int{6685}},
+		{name: "case and spacing", query: "  ABYSSAL   Wh ", limit: 10, want: []int{4151}},
+		{name: "exact match first", query: "dragon dagger", limit: 10, want: []int{1215, 5698}},
+		{name: "apostrophes ignored", query: "dhide", limit: 10, want: []int{3048}},
+		{name: "apostrophe in query", query: "d'hi", limit: 10, want: []int{3048}},
+		{name: "limit", query: "a", limit: 2, want: []int{4151, 11802}},
+		{name: "alias and name match once", query: "t", limit: 10, want: []int{12006}},
+		{name: "mid-word does not match", query: "yssal", limit: 10, want: []int{}},
+		{name: "no match", query: "zzz", limit: 10, want: []int{}},
+		{name: "blank", query: "   ", limit: 10, want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, completionIDs(index.Complete(tt.query, tt.limit)))
+		})
+	}
+}
+
+func TestAutocompleteIndex_LimitCapped(t *testing.T) {
+	entries := make([]autocomplete.Entry, 50)
+	for i := range entries {
+		entries[i] = autocomplete.Entry{ItemID: i + 1, Name: fmt.Sprintf("Rune item %d", i), Popularity: int64(i)}
+	}
+	index := autocomplete.NewIndex(entries)
+
+	got := index.Complete("rune", 100)
+	require.Len(t, got, autocomplete.MaxResults)
+	assert.Equal(t, 50, got[0].ItemID)
+	assert.Equal(t, 41, got[autocomplete.MaxResults-1].ItemID)
+}
+
+func TestAutocompleteService(t *testing.T) {
+	ctx := context.Background()
+	high, low := int64(3_000_000), int64(2_900_000)
+	itemRepo := listingItemRepo{&fakeItemRepo{itemsByID: map[int]*models.Item{
+		4151: {ItemID: 4151, Name: "Abyssal whip", IconURL: "Abyssal whip.png"},
+		2434: {ItemID: 2434, Name: "Prayer potion(4)", IconURL: "Prayer potion(4).png"},
+	}}}
+	aliasRepo := &fakeItemAliasRepo{aliases: []models.ItemAlias{
+		{ID: 1, Alias: "whip", ItemID: 4151, Status: models.ItemAliasStatusApproved},
+		{ID: 2, Alias: "ppot", ItemID: 2434, Status: models.ItemAliasStatusApproved},
+		{ID: 3, Alias: "pray pot", ItemID: 2434, Status: models.ItemAliasStatusPending},
+	}}
+	priceRepo := &fakePriceRepo{
+		tradeVolumes:            map[int]int64{2434: 10_000, 4151: 100},
+		getAllCurrentPricesResp: []models.CurrentPrice{{ItemID: 4151, HighPrice: &high, LowPrice: &low}},
+	}
+
+	svc := services.NewAutocompleteService(itemRepo, aliasRepo, priceRepo, zap.NewNop().Sugar())
+	assert.False(t, svc.Ready())
+	assert.Nil(t, svc.Complete("whip", 5))
+
+	require.NoError(t, svc.Rebuild(ctx))
+	require.True(t, svc.Ready())
+
+	got := svc.Complete("whip", 5)
+	require.Len(t, got, 1)
+	assert.Equal(t, 4151, got[0].ItemID)
+	assert.Equal(t, "Abyssal whip", got[0].Name)
+	assert.Contains(t, got[0].IconURL, "https://")
+	require.NotNil(t, got[0].HighPrice)
+	assert.Equal(t, high, *got[0].HighPrice)
+
+	assert.Equal(t, 2434, svc.Complete("ppot", 5)[0].ItemID)
+	assert.Empty(t, svc.Complete("pray pot", 5), "pending aliases are not indexed")
+
+	// "p" matches both; the potion trades far more.
+	assert.Equal(t, 2434, svc.Complete("p", 5)[0].ItemID)
+
+	newHigh := int64(3_100_000)
+	svc.UpdatePrices([]models.BulkPriceUpdate{{ItemID: 4151, HighPrice: &newHigh}})
+	got = svc.Complete("abyssal", 5)
+	require.Len(t, got, 1)
+	assert.Equal(t, newHigh, *got[0].HighPrice)
+	assert.Equal(t, low, *got[0].LowPrice)
+}
+
+func TestAutocompleteHandler(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	high := int64(3_000_000)
+
+	tests := []struct {
+		setup    func(m *MockAutocompleteService)
+		name     string
+		path     string
+		expected string
+		status   int
+	}{
+		{
+			name:   "default limit",
+			path:   "/items/autocomplete?q=whi",
+			status: 200,
+			setup: func(m *MockAutocompleteService) {
+				m.On("Ready").Return(true)
+				m.On("Complete", "whi", models.DefaultAutocompleteLimit).Return([]models.AutocompleteItem{
+					{ItemID: 4151, Name: "Abyssal whip", HighPrice: &high},
+				})
+			},
+		},
+		{
+			name:   "custom limit",
+			path:   "/items/autocomplete?q=bgs&limit=3",
+			status: 200,
+			setup: func(m *MockAutocompleteService) {
+				m.On("Ready").Return(true)
+				m.On("Complete", "bgs", 3).Return([]models.AutocompleteItem{})
+			},
+		},
+		{name: "missing query", path: "/items/autocomplete", status: 400, expected: "search query parameter 'q' is required"},
+		{name: "blank query", path: "/items/autocomplete?q=%20", status: 400, expected: "search query parameter 'q' is required"},
+		{
+			name:     "limit too large",
+			path:     "/items/autocomplete?q=a&limit=11",
+			status:   400,
+			expected: fmt.Sprintf("limit must be between 1 and %d", autocomplete.MaxResults),
+		},
+		{
+			name:     "not ready",
+			path:     "/items/autocomplete?q=a",
+			status:   503,
+			expected: "autocomplete index is not ready",
+			setup: func(m *MockAutocompleteService) {
+				m.On("Ready").Return(false)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAutocompleteService)
+			if tt.setup != nil {
+				tt.setup(mockService)
+			}
+			handler := handlers.NewAutocompleteHandler(mockService, logger)
+
+			app := fiber.New()
+			app.Get("/items/autocomplete", handler.Complete)
+
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var result map[string]any
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			if tt.expected != "" {
+				assert.Equal(t, tt.expected, result["error"])
+			} else {
+				assert.NotNil(t, result["data"])
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// benchmarkAutocompleteIndex builds an index the size of the item mapping.
+func benchmarkAutocompleteIndex() *autocomplete.Index {
+	words := []string{"rune", "dragon", "abyssal", "bandos", "armadyl", "potion", "scimitar", "platebody", "shield", "arrow"}
+	entries := make([]autocomplete.Entry, 15000)
+	for i := range entries {
+		entries[i] = autocomplete.Entry{
+			ItemID:     i + 1,
+			Name:       fmt.Sprintf("%s %s %d", words[i%len(words)], words[(i/len(words))%len(words)], i),
+			Popularity: int64(i % 997),
+		}
+	}
+	return autocomplete.NewIndex(entries)
+}
+
+func BenchmarkAutocompleteIndex_Complete(b *testing.B) {
+	index := benchmarkAutocompleteIndex()
+	queries := []string{"r", "dra", "abyssal sc", "plate", "potion 12", "zzz"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		index.Complete(queries[i%len(queries)], models.DefaultAutocompleteLimit)
+	}
+}
+
+func TestAutocompleteIndex_Latency(t *testing.T) {
+	index := benchmarkAutocompleteIndex()
+
+	start := time.Now()
+	for i := 0; i < 1000; i++ {
+		index.Complete("dragon", autocomplete.MaxResults)
+	}
+	assert.Less(t, time.Since(start)/1000, time.Millisecond)
+}
@@ -0,0 +1,246 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guavi/osrs-ge-tracker/internal/backtest"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
+)
+
+var backtestEpoch = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+// backtestBar is one hourly bar: buys fill at low, sells at high.
+func backtestBar(itemID, hour int, high, low, volume int64) models.PriceTimeseriesPoint {
+	return models.PriceTimeseriesPoint{
+		ItemID:          itemID,
+		Timestamp:       backtestEpoch.Add(time.Duration(hour) * time.Hour),
+		AvgHighPrice:    &high,
+		AvgLowPrice:     &low,
+		HighPriceVolume: volume,
+		LowPriceVolume:  volume,
+	}
+}
+
+func ptrFloat(v float64) *float64 { return &v }
+
+// spreadStrategy buys whenever the spread exceeds 5% and sells after one bar.
+func spreadStrategy(t *testing.T, itemIDs ...int) models.BacktestStrategy {
+	t.Helper()
+	strategy, err := backtest.Normalize(models.BacktestStrategy{
+		ItemIDs:           itemIDs,
+		Start:             backtestEpoch,
+		End:               backtestEpoch.Add(48 * time.Hour),
+		Entry:             []models.BacktestCondition{{Indicator: "spread_pct", Operator: ">", Value: 5}},
+		MaxHoldBars:       1,
+		InitialCapital:    1_000_000,
+		PositionSizePct:   100,
+		MaxVolumeSharePct: 100,
+	}, backtestEpoch.Add(72*time.Hour))
+	require.NoError(t, err)
+	return strategy
+}
+
+func TestGETax(t *testing.T) {
+	assert.Equal(t, int64(0), utils.GETax(4151, 49), "rounds down to zero below 50gp")
+	assert.Equal(t, int64(1), utils.GETax(4151, 50))
+	assert.Equal(t, int64(20_000), utils.GETax(4151, 1_000_000))
+	assert.Equal(t, utils.GETaxCap, utils.GETax(20997, 1_500_000_000), "capped per unit")
+	assert.Equal(t, int64(0), utils.GETax(13190, 10_000_000), "bonds are exempt")
+}
+
+func TestBacktestRun_FillsOnNextBarAndPaysTax(t *testing.T) {
+	strategy := spreadStrategy(t, 4151)
+	bars := []models.PriceTimeseriesPoint{
+		backtestBar(4151, 0, 1100, 1000, 1000), // spread 10%: signal
+		backtestBar(4151, 1, 1200, 1000, 1000), // buy at 1000
+		backtestBar(4151, 2, 1100, 1090, 1000), // held 1 bar: exit signal
+		backtestBar(4151, 3, 1100, 1090, 1000), // sell at 1100
+	}
+
+	result := backtest.Run(strategy, map[int][]models.PriceTimeseriesPoint{4151: bars}, nil)
+
+	require.Len(t, result.Trades, 1)
+	trade := result.Trades[0]
+	assert.Equal(t, bars[1].Timestamp, trade.EntryTime, "filled on the bar after the signal")
+	assert.Equal(t, bars[3].Timestamp, trade.ExitTime)
+	assert.Equal(t, backtest.ExitMaxHold, trade.ExitReason)
+	assert.Equal(t, int64(1000), trade.Quantity)
+	assert.InDelta(t, 1000, trade.EntryPrice, 0)
+	assert.InDelta(t, 1100, trade.ExitPrice, 0)
+	assert.Equal(t, int64(1000*22), trade.Tax)
+	assert.Equal(t, int64(1000*(1100-22-1000)), trade.Profit)
+
+	summary := result.Summary
+	assert.Equal(t, 1, summary.Trades)
+	assert.Equal(t, 1, summary.Wins)
+	assert.Equal(t, int64(78_000), summary.NetProfit)
+	assert.Equal(t, int64(1_078_000), summary.FinalEquity)
+	assert.Equal(t, int64(22_000), summary.TaxPaid)
+	assert.InDelta(t, 7.8, summary.TotalReturnPct, 1e-9)
+	assert.Equal(t, 4, summary.Bars)
+	require.Len(t, result.Equity, 4)
+	assert.Equal(t, int64(1_078_000), result.Equity[3].Equity)
+}
+
+func TestBacktestRun_RespectsBuyLimitWindow(t *testing.T) {
+	strategy := spreadStrategy(t, 4151)
+	var bars []models.PriceTimeseriesPoint
+	for h := 0; h < 12; h++ {
+		bars = append(bars, backtestBar(4151, h, 1100, 1000, 10_000))
+	}
+
+	result := backtest.Run(strategy, map[int][]models.PriceTimeseriesPoint{4151: bars}, map[int]int{4151: 70})
+
+	require.NotEmpty(t, result.Trades)
+	windowStart := result.Trades[0].EntryTime
+	var boughtInWindow int64
+	for _, trade := range result.Trades {
+		assert.LessOrEqual(t, trade.Quantity, int64(70))
+		if trade.EntryTime.Sub(windowStart) < backtest.BuyLimitWindow {
+			boughtInWindow += trade.Quantity
+		}
+	}
+	assert.Equal(t, int64(70), boughtInWindow, "the 4-hour window allows exactly the buy limit")
+
+	// Entries resume once the window has passed.
+	last := result.Trades[len(result.Trades)-1]
+	assert.GreaterOrEqual(t, last.EntryTime.Sub(windowStart), backtest.BuyLimitWindow)
+	assert.Equal(t, int64(70), last.Quantity)
+}
+
+func TestBacktestRun_VolumeCapAndTakeProfit(t *testing.T) {
+	strategy := spreadStrategy(t, 4151)
+	strategy.MaxHoldBars = 0
+	strategy.TakeProfitPct = ptrFloat(5)
+	strategy.MaxVolumeSharePct = 10
+
+	bars := []models.PriceTimeseriesPoint{
+		backtestBar(4151, 0, 1100, 1000, 500), // signal
+		backtestBar(4151, 1, 1010, 1000, 500), // buy 50 (10% of 500)
+		backtestBar(4151, 2, 1200, 1150, 500), // +15% after tax: take profit signal
+		backtestBar(4151, 3, 1200, 1150, 200), // sell 20
+		backtestBar(4151, 4, 1200, 1150, 200), // sell 20
+		backtestBar(4151, 5, 1200, 1150, 200), // sell the last 10
+	}
+
+	result := backtest.Run(strategy, map[int][]models.PriceTimeseriesPoint{4151: bars}, nil)
+
+	require.Len(t, result.Trades, 1)
+	trade := result.Trades[0]
+	assert.Equal(t, int64(50), trade.Quantity)
+	assert.Equal(t, backtest.ExitTakeProfit, trade.ExitReason)
+	assert.Equal(t, bars[5].Timestamp, trade.ExitTime, "thin volume spreads the sale over three bars")
+}
+
+func TestBacktestRun_ClosesOpenPositionsAtEndAndIsDeterministic(t *testing.T) {
+	strategy := spreadStrategy(t, 4151, 11802)
+	strategy.MaxHoldBars = 100
+	bars := map[int][]models.PriceTimeseriesPoint{
+		4151:  {backtestBar(4151, 0, 1100, 1000, 100), backtestBar(4151, 1, 1050, 1000, 100), backtestBar(4151, 2, 900, 880, 100)},
+		11802: {backtestBar(11802, 1, 2000, 1990, 100), backtestBar(11802, 2, 2000, 1990, 100)},
+	}
+
+	result := backtest.Run(strategy, bars, nil)
+
+	require.Len(t, result.Trades, 1)
+	assert.Equal(t, backtest.ExitEndOfData, result.Trades[0].ExitReason)
+	assert.Less(t, result.Trades[0].Profit, int64(0))
+	assert.Equal(t, 1, result.Summary.Losses)
+	require.NotNil(t, result.Summary.ProfitFactor)
+	assert.Zero(t, *result.Summary.ProfitFactor, "no winning trades")
+	assert.Greater(t, result.Summary.MaxDrawdownPct, 0.0)
+	assert.Equal(t, 3, result.Summary.Bars, "the clock is the union of item timestamps")
+
+	assert.Equal(t, result, backtest.Run(strategy, bars, nil))
+}
+
+func TestBacktestRun_RSIEntry(t *testing.T) {
+	strategy, err := backtest.Normalize(models.BacktestStrategy{
+		ItemIDs:           []int{4151},
+		Start:             backtestEpoch,
+		End:               backtestEpoch.Add(72 * time.Hour),
+		Entry:             []models.BacktestCondition{{Indicator: "rsi", Operator: "<", Value: 30, Period: 3}},
+		Exit:              []models.BacktestCondition{{Indicator: "rsi", Operator: ">", Value: 70, Period: 3}},
+		MaxVolumeSharePct: 100,
+	}, backtestEpoch.Add(72*time.Hour))
+	require.NoError(t, err)
+
+	prices := []int64{1000, 990, 980, 970, 960, 970, 990, 1010, 1030}
+	bars := make([]models.PriceTimeseriesPoint, len(prices))
+	for i, p := range prices {
+		bars[i] = backtestBar(4151, i, p+5, p-5, 1000)
+	}
+
+	result := backtest.Run(strategy, map[int][]models.PriceTimeseriesPoint{4151: bars}, nil)
+
+	require.Len(t, result.Trades, 1)
+	assert.Equal(t, bars[4].Timestamp, result.Trades[0].EntryTime, "oversold after three falling bars")
+	assert.Equal(t, backtest.ExitSignal, result.Trades[0].ExitReason)
+	assert.Greater(t, result.Trades[0].Profit, int64(0))
+}
+
+func TestBacktestNormalize(t *testing.T) {
+	now := backtestEpoch.Add(30 * 24 * time.Hour)
+	valid := func() models.BacktestStrategy {
+		return models.BacktestStrategy{
+			ItemIDs:     []int{4151},
+			Entry:       []models.BacktestCondition{{Indicator: "rsi", Operator: "<", Value: 30}},
+			MaxHoldBars: 12,
+		}
+	}
+
+	strategy, err := backtest.Normalize(valid(), now)
+	require.NoError(t, err)
+	assert.Equal(t, "1h", strategy.Timestep)
+	assert.Equal(t, now, strategy.End)
+	assert.Equal(t, now.Add(-backtest.Max1hWindow), strategy.Start)
+	assert.Equal(t, 14, strategy.Entry[0].Period)
+	assert.Equal(t, int64(backtest.DefaultCapital), strategy.InitialCapital)
+	assert.InDelta(t, backtest.DefaultSizePct, strategy.PositionSizePct, 0)
+
+	tests := []struct {
+		mutate   func(s *models.BacktestStrategy)
+		name     string
+		expected string
+	}{
+		{name: "timestep", mutate: func(s *models.BacktestStrategy) { s.Timestep = "6h" }, expected: "timestep must be one of: 5m, 1h"},
+		{name: "no items", mutate: func(s *models.BacktestStrategy) { s.ItemIDs = nil }, expected: "itemIds is required"},
+		{name: "duplicate item", mutate: func(s *models.BacktestStrategy) { s.ItemIDs = []int{1, 1} }, expected: "duplicate item ID: 1"},
+		{
+			name: "5m window",
+			mutate: func(s *models.BacktestStrategy) {
+				s.Timestep = "5m"
+				s.Start = now.Add(-8 * 24 * time.Hour)
+			},
+			expected: "window for 5m buckets must not exceed 7 days",
+		},
+		{name: "no entry", mutate: func(s *models.BacktestStrategy) { s.Entry = nil }, expected: "at least one entry condition is required"},
+		{
+			name:     "no exit",
+			mutate:   func(s *models.BacktestStrategy) { s.MaxHoldBars = 0 },
+			expected: "an exit condition, takeProfitPct, stopLossPct or maxHoldBars is required",
+		},
+		{
+			name:     "indicator",
+			mutate:   func(s *models.BacktestStrategy) { s.Entry[0].Indicator = "macd" },
+			expected: `unknown indicator "macd"`,
+		},
+		{name: "operator", mutate: func(s *models.BacktestStrategy) { s.Entry[0].Operator = "==" }, expected: "op must be one of: <, <=, >, >="},
+		{name: "stop loss", mutate: func(s *models.BacktestStrategy) { s.StopLossPct = ptrFloat(150) }, expected: "stopLossPct must be between 0 and 100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := valid()
+			tt.mutate(&s)
+			_, err := backtest.Normalize(s, now)
+			require.ErrorIs(t, err, backtest.ErrInvalidStrategy)
+			assert.Equal(t, "invalid strategy: "+tt.expected, err.Error())
+		})
+	}
+}
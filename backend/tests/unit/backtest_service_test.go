@@ -0,0 +1,386 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/backtest"
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// fakeBacktestRepo is an in-memory BacktestRepository.
+type fakeBacktestRepo struct {
+	jobs      map[string]models.Backtest
+	createErr error
+	mu        sync.Mutex
+}
+
+func newFakeBacktestRepo() *fakeBacktestRepo {
+	return &fakeBacktestRepo{jobs: map[string]models.Backtest{}}
+}
+
+func (r *fakeBacktestRepo) Create(_ context.Context, backtest *models.Backtest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.createErr != nil {
+		return r.createErr
+	}
+	r.jobs[backtest.ID] = *backtest
+	return nil
+}
+
+func (r *fakeBacktestRepo) GetByID(_ context.Context, id string) (*models.Backtest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, nil
+	}
+	return &job, nil
+}
+
+func (r *fakeBacktestRepo) List(_ context.Context, limit int) ([]models.Backtest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]models.Backtest, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		job.Result = nil
+		out = append(out, job)
+	}
+	return out[:min(limit, len(out))], nil
+}
+
+func (r *fakeBacktestRepo) Update(_ context.Context, backtest *models.Backtest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job := r.jobs[backtest.ID]
+	job.Status = backtest.Status
+	job.StartedAt = backtest.StartedAt
+	job.CompletedAt = backtest.CompletedAt
+	job.Result = backtest.Result
+	job.Error = backtest.Error
+	r.jobs[backtest.ID] = job
+	return nil
+}
+
+func (r *fakeBacktestRepo) FailUnfinished(_ context.Context, message string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for id, job := range r.jobs {
+		if job.Status == models.BacktestStatusPending || job.Status == models.BacktestStatusRunning {
+			job.Status = models.BacktestStatusFailed
+			job.Error = &message
+			r.jobs[id] = job
+			n++
+		}
+	}
+	return n, nil
+}
+
+// waitForBacktest polls until the job leaves the pending and running states.
+func waitForBacktest(t *testing.T, svc services.BacktestService, id string) *models.Backtest {
+	t.Helper()
+	var job *models.Backtest
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = svc.Get(context.Background(), id)
+		require.NoError(t, err)
+		return job != nil && (job.Status == models.BacktestStatusCompleted || job.Status == models.BacktestStatusFailed)
+	}, 5*time.Second, 10*time.Millisecond)
+	return job
+}
+
+func TestBacktestService_SubmitRunsAndStoresResult(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	now := time.Now().UTC().Truncate(time.Hour)
+	var bars []models.PriceTimeseriesPoint
+	for h := 10; h >= 0; h-- {
+		high, low := int64(1100), int64(1000)
+		bars = append(bars, models.PriceTimeseriesPoint{
+			ItemID: 4151, Timestamp: now.Add(-time.Duration(h) * time.Hour),
+			AvgHighPrice: &high, AvgLowPrice: &low, HighPriceVolume: 10_000, LowPriceVolume: 10_000,
+		})
+	}
+	buyLimit := 70
+	backtestRepo := newFakeBacktestRepo()
+	svc := services.NewBacktestService(
+		backtestRepo,
+		&fakePriceRepo{timeseriesPoints: map[int][]models.PriceTimeseriesPoint{4151: bars}},
+		&fakeItemRepo{getByItemIDItem: &models.Item{ItemID: 4151, BuyLimit: &buyLimit}},
+		logger,
+	)
+
+	job, err := svc.Submit(context.Background(), models.BacktestStrategy{
+		Name:        "wide spreads",
+		ItemIDs:     []int{4151},
+		Entry:       []models.BacktestCondition{{Indicator: "spread_pct", Operator: ">", Value: 5}},
+		MaxHoldBars: 1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.BacktestStatusPending, job.Status)
+
+	var stored models.BacktestStrategy
+	require.NoError(t, json.Unmarshal(job.Strategy, &stored))
+	assert.Equal(t, "1h", stored.Timestep, "the normalized strategy is stored")
+
+	done := waitForBacktest(t, svc, job.ID)
+	require.Equal(t, models.BacktestStatusCompleted, done.Status, "error: %v", done.Error)
+	require.NotNil(t, done.StartedAt)
+	require.NotNil(t, done.CompletedAt)
+
+	var result models.BacktestResult
+	require.NoError(t, json.Unmarshal(done.Result, &result))
+	require.NotEmpty(t, result.Trades)
+	assert.Equal(t, int64(70), result.Trades[0].Quantity, "buy limit from the item is applied")
+	assert.Equal(t, 11, result.Summary.Bars)
+
+	require.NoError(t, svc.Shutdown(context.Background()))
+	_, err = svc.Submit(context.Background(), stored)
+	assert.ErrorIs(t, err, models.ErrBacktestShuttingDown)
+}
+
+// blockingPriceRepo holds every bucket load until release is closed.
+type blockingPriceRepo struct {
+	*fakePriceRepo
+	release chan struct{}
+}
+
+func (r *blockingPriceRepo) GetTimeseriesPoints(ctx context.Context, itemID int, timestep string, params models.PriceHistoryParams) ([]models.PriceTimeseriesPoint, error) {
+	select {
+	case <-r.release:
+		return r.fakePriceRepo.GetTimeseriesPoints(ctx, itemID, timestep, params)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestBacktestService_RejectsSubmissionsWhenQueueIsFull(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	priceRepo := &blockingPriceRepo{fakePriceRepo: &fakePriceRepo{}, release: make(chan struct{})}
+	backtestRepo := newFakeBacktestRepo()
+	svc := services.NewBacktestService(backtestRepo, priceRepo, &fakeItemRepo{}, logger)
+	t.Cleanup(func() { _ = svc.Shutdown(context.Background()) })
+
+	strategy := models.BacktestStrategy{
+		ItemIDs:     []int{4151},
+		Entry:       []models.BacktestCondition{{Indicator: "spread_pct", Operator: ">", Value: 5}},
+		MaxHoldBars: 1,
+	}
+	var ids []string
+	for range models.MaxUnfinishedBacktests {
+		job, err := svc.Submit(context.Background(), strategy)
+		require.NoError(t, err)
+		ids = append(ids, job.ID)
+	}
+	_, err := svc.Submit(context.Background(), strategy)
+	assert.ErrorIs(t, err, models.ErrBacktestQueueFull)
+	assert.Len(t, backtestRepo.jobs, models.MaxUnfinishedBacktests, "rejected jobs are not stored")
+
+	close(priceRepo.release)
+	for _, id := range ids {
+		waitForBacktest(t, svc, id)
+	}
+	require.Eventually(t, func() bool {
+		job, err := svc.Submit(context.Background(), strategy)
+		return err == nil && job != nil
+	}, 5*time.Second, 10*time.Millisecond, "finished jobs free their place in the queue")
+}
+
+func TestBacktestService_FailedInsertFreesItsPlace(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	backtestRepo := newFakeBacktestRepo()
+	backtestRepo.createErr = errors.New("db down")
+	svc := services.NewBacktestService(backtestRepo, &fakePriceRepo{}, &fakeItemRepo{}, logger)
+	t.Cleanup(func() { _ = svc.Shutdown(context.Background()) })
+
+	strategy := models.BacktestStrategy{
+		ItemIDs:     []int{4151},
+		Entry:       []models.BacktestCondition{{Indicator: "spread_pct", Operator: ">", Value: 5}},
+		MaxHoldBars: 1,
+	}
+	for range models.MaxUnfinishedBacktests + 1 {
+		_, err := svc.Submit(context.Background(), strategy)
+		require.EqualError(t, err, "db down")
+	}
+
+	backtestRepo.createErr = nil
+	job, err := svc.Submit(context.Background(), strategy)
+	require.NoError(t, err, "failed inserts do not hold a place in the queue")
+	waitForBacktest(t, svc, job.ID)
+}
+
+func TestBacktestService_ValidationAndLookup(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	backtestRepo := newFakeBacktestRepo()
+	svc := services.NewBacktestService(backtestRepo, &fakePriceRepo{}, &fakeItemRepo{}, logger)
+	t.Cleanup(func() { _ = svc.Shutdown(context.Background()) })
+
+	_, err := svc.Submit(context.Background(), models.BacktestStrategy{ItemIDs: []int{4151}})
+	assert.ErrorIs(t, err, backtest.ErrInvalidStrategy)
+	assert.Empty(t, backtestRepo.jobs, "invalid strategies are not stored")
+
+	job, err := svc.Get(context.Background(), "not-a-uuid")
+	require.NoError(t, err)
+	assert.Nil(t, job)
+
+	backtestRepo.jobs["11111111-1111-1111-1111-111111111111"] = models.Backtest{
+		ID: "11111111-1111-1111-1111-111111111111", Status: models.BacktestStatusRunning,
+	}
+	failed, err := svc.FailInterrupted(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), failed)
+	interrupted := backtestRepo.jobs["11111111-1111-1111-1111-111111111111"]
+	assert.Equal(t, models.BacktestStatusFailed, interrupted.Status)
+	require.NotNil(t, interrupted.Error)
+	assert.Equal(t, "interrupted by server shutdown", *interrupted.Error)
+}
+
+func TestBacktestHandler(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	jobID := "0b6c7f52-8f0e-4d5c-9a55-8f3f2ad1a001"
+
+	tests := []struct {
+		setup    func(m *MockBacktestService)
+		name     string
+		method   string
+		path     string
+		body     string
+		expected string
+		status   int
+	}{
+		{name: "bad body", method: "POST", path: "/backtests", body: "{", status: 400, expected: "invalid request body"},
+		{
+			name:   "invalid strategy",
+			method: "POST",
+			path:   "/backtests",
+			body:   `{"itemIds":[4151]}`,
+			status: 400,
+			setup: func(m *MockBacktestService) {
+				m.On("Submit", mock.Anything, mock.Anything).
+					Return(nil, fmt.Errorf("%w: at least one entry condition is required", backtest.ErrInvalidStrategy))
+			},
+			expected: "at least one entry condition is required",
+		},
+		{
+			name:   "shutting down",
+			method: "POST",
+			path:   "/backtests",
+			body:   `{"itemIds":[4151]}`,
+			status: 503,
+			setup: func(m *MockBacktestService) {
+				m.On("Submit", mock.Anything, mock.Anything).Return(nil, models.ErrBacktestShuttingDown)
+			},
+			expected: "backtest service is shutting down",
+		},
+		{
+			name:   "queue full",
+			method: "POST",
+			path:   "/backtests",
+			body:   `{"itemIds":[4151]}`,
+			status: 429,
+			setup: func(m *MockBacktestService) {
+				m.On("Submit", mock.Anything, mock.Anything).Return(nil, models.ErrBacktestQueueFull)
+			},
+			expected: "too many backtests are queued; try again later",
+		},
+		{
+			name:   "accepted",
+			method: "POST",
+			path:   "/backtests",
+			body:   `{"itemIds":[4151],"timestep":"5m","entry":[{"indicator":"rsi","op":"<","value":30}],"takeProfitPct":3}`,
+			status: 202,
+			setup: func(m *MockBacktestService) {
+				m.On("Submit", mock.Anything, mock.MatchedBy(func(s models.BacktestStrategy) bool {
+					return s.Timestep == "5m" && len(s.Entry) == 1 && s.Entry[0].Operator == "<" &&
+						s.TakeProfitPct != nil && *s.TakeProfitPct == 3
+				})).Return(&models.Backtest{ID: jobID, Status: models.BacktestStatusPending}, nil)
+			},
+		},
+		{
+			name:   "not found",
+			method: "GET",
+			path:   "/backtests/" + jobID,
+			status: 404,
+			setup: func(m *MockBacktestService) {
+				m.On("Get", mock.Anything, jobID).Return(nil, nil)
+			},
+			expected: "backtest not found",
+		},
+		{
+			name:   "get",
+			method: "GET",
+			path:   "/backtests/" + jobID,
+			status: 200,
+			setup: func(m *MockBacktestService) {
+				m.On("Get", mock.Anything, jobID).
+					Return(&models.Backtest{ID: jobID, Status: models.BacktestStatusCompleted, Result: []byte(`{"trades":[]}`)}, nil)
+			},
+		},
+		{name: "bad limit", method: "GET", path: "/backtests?limit=500", status: 400, expected: "limit must be between 1 and 100"},
+		{
+			name:   "list error",
+			method: "GET",
+			path:   "/backtests",
+			status: 500,
+			setup: func(m *MockBacktestService) {
+				m.On("List", mock.Anything, 20).Return(nil, errors.New("db down"))
+			},
+			expected: "failed to list backtests",
+		},
+		{
+			name:   "list",
+			method: "GET",
+			path:   "/backtests?limit=5",
+			status: 200,
+			setup: func(m *MockBacktestService) {
+				m.On("List", mock.Anything, 5).Return([]models.Backtest{{ID: jobID}}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBacktestService := new(MockBacktestService)
+			if tt.setup != nil {
+				tt.setup(mockBacktestService)
+			}
+			handler := handlers.NewBacktestHandler(mockBacktestService, logger)
+
+			app := fiber.New()
+			app.Post("/backtests", handler.CreateBacktest)
+			app.Get("/backtests", handler.ListBacktests)
+			app.Get("/backtests/:id", handler.GetBacktest)
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var result map[string]any
+			_ = json.NewDecoder(resp.Body).Decode(&result)
+			if tt.expected != "" {
+				assert.Equal(t, tt.expected, result["error"])
+			} else {
+				assert.NotNil(t, result["data"])
+			}
+			mockBacktestService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,387 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/middleware"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// fakeBankSnapshotRepo keeps snapshots and recorded values in memory.
+type fakeBankSnapshotRepo struct {
+	snapshots []models.BankSnapshot
+	values    []models.BankSnapshotValue
+}
+
+func (r *fakeBankSnapshotRepo) Create(_ context.Context, snapshot *models.BankSnapshot, maxPerUser int) (bool, error) {
+	count := 0
+	for _, s := range r.snapshots {
+		if s.UserID == snapshot.UserID {
+			count++
+		}
+	}
+	if count >= maxPerUser {
+		return false, nil
+	}
+	snapshot.ID = int64(len(r.snapshots) + 1)
+	for i := range snapshot.Items {
+		snapshot.Items[i].SnapshotID = snapshot.ID
+	}
+	snapshot.ItemCount = len(snapshot.Items)
+	r.snapshots = append(r.snapshots, *snapshot)
+	return true, nil
+}
+
+func (r *fakeBankSnapshotRepo) List(_ context.Context, userID string) ([]models.BankSnapshot, error) {
+	snapshots := make([]models.BankSnapshot, 0)
+	for _, s := range r.snapshots {
+		if s.UserID == userID {
+			s.Items = nil
+			snapshots = append(snapshots, s)
+		}
+	}
+	return snapshots, nil
+}
+
+func (r *fakeBankSnapshotRepo) Get(_ context.Context, userID string, id int64) (*models.BankSnapshot, error) {
+	for _, s := range r.snapshots {
+		if s.UserID == userID && s.ID == id {
+			return &s, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeBankSnapshotRepo) ListAll(_ context.Context) ([]models.BankSnapshot, error) {
+	return r.snapshots, nil
+}
+
+func (r *fakeBankSnapshotRepo) Delete(_ context.Context, _ string, _ int64) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeBankSnapshotRepo) UpsertValues(_ context.Context, values []models.BankSnapshotValue) error {
+	r.values = append(r.values, values...)
+	return nil
+}
+
+func (r *fakeBankSnapshotRepo) ListValues(_ context.Context, snapshotID int64, _, _ time.Time) ([]models.BankSnapshotValue, error) {
+	values := make([]models.BankSnapshotValue, 0)
+	for _, v := range r.values {
+		if v.SnapshotID == snapshotID {
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+func newBankSnapshotTestService(repo *fakeBankSnapshotRepo, priceRepo *fakePriceRepo) services.BankSnapshotService {
+	logger := zap.NewNop().Sugar()
+	itemRepo := &fakeItemRepo{itemsByID: map[int]*models.Item{
+		4151: {ItemID: 4151, Name: "Abyssal whip"},
+		385:  {ItemID: 385, Name: "Shark"},
+	}}
+	if priceRepo == nil {
+		priceRepo = &fakePriceRepo{currentPrices: []models.CurrentPrice{
+			{ItemID: 4151, HighPrice: int64Ptr(1_500_000), LowPrice: int64Ptr(1_400_000)},
+			{ItemID: 385, HighPrice: int64Ptr(900), LowPrice: int64Ptr(850)},
+		}}
+	}
+	valuationService := services.NewValuationService(itemRepo, priceRepo, logger)
+	return services.NewBankSnapshotService(repo, itemRepo, priceRepo, valuationService, logger)
+}
+
+func TestBankSnapshotService_Create(t *testing.T) {
+	repo := &fakeBankSnapshotRepo{}
+	svc := newBankSnapshotTestService(repo, nil)
+	ctx := context.Background()
+
+	detail, err := svc.Create(ctx, "alice", "  Main  ", []models.BankEntry{
+		{ItemID: 4151, Quantity: 1},
+		{ItemID: 4152, Name: "Abyssal whip", Quantity: 2},
+		{ItemID: 995, Quantity: 1000},
+		{ItemID: 777777, Quantity: 1},
+		{ItemID: 385, Quantity: 0},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Main", detail.Name)
+	assert.Equal(t, int64(1), detail.ID)
+	assert.Equal(t, []models.BankSnapshotItem{
+		{SnapshotID: 1, ItemID: 4151, Quantity: 3},
+		{SnapshotID: 1, ItemID: 995, Quantity: 1000},
+	}, repo.snapshots[0].Items)
+	assert.Len(t, detail.Valuation.Unresolved, 1)
+	assert.Equal(t, 1, detail.Valuation.Placeholders)
+
+	// An unnamed snapshot is named after the day it was saved.
+	detail, err = svc.Create(ctx, "alice", "", []models.BankEntry{{ItemID: 385, Quantity: 10}})
+	require.NoError(t, err)
+	assert.Equal(t, "Bank "+time.Now().UTC().Format(time.DateOnly), detail.Name)
+
+	_, err = svc.Create(ctx, "alice", "", []models.BankEntry{{ItemID: 777777, Quantity: 1}})
+	require.ErrorIs(t, err, models.ErrInvalidBankSnapshot)
+	assert.Contains(t, err.Error(), "export has no items that could be valued")
+
+	long := make([]byte, models.MaxBankSnapshotNameLength+1)
+	for i := range long {
+		long[i] = 'a'
+	}
+	_, err = svc.Create(ctx, "alice", string(long), []models.BankEntry{{ItemID: 385, Quantity: 1}})
+	require.ErrorIs(t, err, models.ErrInvalidBankSnapshot)
+}
+
+func TestBankSnapshotService_CreateLimit(t *testing.T) {
+	repo := &fakeBankSnapshotRepo{}
+	for i := 0; i < models.MaxBankSnapshotsPerUser; i++ {
+		repo.snapshots = append(repo.snapshots, models.BankSnapshot{ID: int64(i + 1), UserID: "alice"})
+	}
+	svc := newBankSnapshotTestService(repo, nil)
+
+	_, err := svc.Create(context.Background(), "alice", "", []models.BankEntry{{ItemID: 385, Quantity: 1}})
+	require.ErrorIs(t, err, models.ErrInvalidBankSnapshot)
+	assert.Contains(t, err.Error(), fmt.Sprintf("at most %d snapshots", models.MaxBankSnapshotsPerUser))
+
+	_, err = svc.Create(context.Background(), "bob", "", []models.BankEntry{{ItemID: 385, Quantity: 1}})
+	assert.NoError(t, err)
+}
+
+func TestBankSnapshotService_RevaluedHistory(t *testing.T) {
+	repo := &fakeBankSnapshotRepo{snapshots: []models.BankSnapshot{{
+		ID:     1,
+		UserID: "alice",
+		Items: []models.BankSnapshotItem{
+			{SnapshotID: 1, ItemID: 4151, Quantity: 2},
+			{SnapshotID: 1, ItemID: 385, Quantity: 10},
+			{SnapshotID: 1, ItemID: 995, Quantity: 500},
+		},
+	}}}
+	to := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	// The whip has a rolled-up day, a 24h bucket and a current price; the
+	// shark has no stored prices at all.
+	priceRepo := &fakePriceRepo{
+		dailyPoints: map[int][]models.PriceTimeseriesDaily{
+			4151: {{ItemID: 4151, Day: time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), AvgHighPrice: int64Ptr(1_200_000), AvgLowPrice: int64Ptr(1_000_000)}},
+		},
+		timeseriesPoints: map[int][]models.PriceTimeseriesPoint{
+			4151: {{ItemID: 4151, Timestamp: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), AvgHighPrice: int64Ptr(1_400_000)}},
+		},
+		currentPrices: []models.CurrentPrice{
+			{ItemID: 4151, UpdatedAt: to.Add(-time.Hour), HighPrice: int64Ptr(1_600_000), LowPrice: int64Ptr(1_400_000)},
+		},
+	}
+	svc := newBankSnapshotTestService(repo, priceRepo)
+
+	history, err := svc.History(context.Background(), "alice", 1, models.NetWorthHistoryParams{
+		From:     to.Add(-48 * time.Hour),
+		To:       to,
+		Interval: 24 * time.Hour,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.NetWorthSourceRevalued, history.Source)
+	require.Len(t, history.Points, 3)
+	assert.True(t, history.Points[0].Timestamp.Equal(to.Add(-48*time.Hour)))
+	assert.True(t, history.Points[2].Timestamp.Equal(to))
+	// 8 March uses that day's rollup, and 9 March carries it forward.
+	assert.Equal(t, int64(2_200_000+500), history.Points[0].TotalMid)
+	assert.Equal(t, int64(2_200_000+500), history.Points[1].TotalMid)
+	assert.Equal(t, 1, history.Points[0].UnpricedItems)
+	// The newest point uses the current price, not the day's bucket.
+	assert.Equal(t, int64(3_000_000+500), history.Points[2].TotalMid)
+	assert.Equal(t, int64(3_200_000+500), history.Points[2].TotalHigh)
+	assert.Equal(t, 1, history.Points[2].UnpricedItems)
+	assert.Equal(t, 1, priceRepo.timeseriesForItemsCalls, "buckets are loaded once for the range")
+	assert.Equal(t, 1, priceRepo.dailyPointsCalls, "daily rollups are loaded once for the range")
+
+	missing, err := svc.History(context.Background(), "bob", 1, models.NetWorthHistoryParams{})
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestBankSnapshotService_HistoryValidation(t *testing.T) {
+	svc := newBankSnapshotTestService(&fakeBankSnapshotRepo{}, nil)
+	to := time.Now().UTC().Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		expected string
+		params   models.NetWorthHistoryParams
+	}{
+		{name: "from after to", params: models.NetWorthHistoryParams{From: to, To: to.Add(-time.Hour)}, expected: "from must be before to"},
+		{name: "unknown source", params: models.NetWorthHistoryParams{Source: "guessed"}, expected: "source must be revalued or recorded"},
+		{
+			name:     "too many points",
+			params:   models.NetWorthHistoryParams{From: to.Add(-130 * time.Hour), To: to, Interval: time.Hour},
+			expected: "range must span at most 120 intervals",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.History(context.Background(), "alice", 1, tt.params)
+			require.ErrorIs(t, err, models.ErrInvalidBankSnapshot)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestBankSnapshotService_RecordDailyValues(t *testing.T) {
+	repo := &fakeBankSnapshotRepo{snapshots: []models.BankSnapshot{
+		{ID: 1, UserID: "alice", Items: []models.BankSnapshotItem{{SnapshotID: 1, ItemID: 4151, Quantity: 1}}},
+		{ID: 2, UserID: "bob", Items: []models.BankSnapshotItem{{SnapshotID: 2, ItemID: 385, Quantity: 100}}},
+	}}
+	svc := newBankSnapshotTestService(repo, nil)
+
+	recorded, err := svc.RecordDailyValues(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, recorded)
+	require.Len(t, repo.values, 2)
+	assert.Equal(t, int64(1_450_000), repo.values[0].TotalMid)
+	assert.Equal(t, int64(87_500), repo.values[1].TotalMid)
+	assert.True(t, repo.values[0].ValuedOn.Equal(time.Now().UTC().Truncate(24*time.Hour)))
+
+	history, err := svc.History(context.Background(), "bob", 2, models.NetWorthHistoryParams{Source: models.NetWorthSourceRecorded})
+	require.NoError(t, err)
+	require.Len(t, history.Points, 1)
+	assert.Equal(t, int64(90_000), history.Points[0].TotalHigh)
+}
+
+func TestBankSnapshotHandler(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		setup    func(m *MockBankSnapshotService)
+		name     string
+		method   string
+		path     string
+		body     string
+		expected string
+		status   int
+	}{
+		{name: "create bad export", method: "POST", path: "/bank-snapshots", body: "4151", status: 400, expected: "line 1: expected id,quantity or id,name,quantity"},
+		{
+			name:   "create",
+			method: "POST",
+			path:   "/bank-snapshots?name=Main",
+			body:   "4151,1\n",
+			status: 201,
+			setup: func(m *MockBankSnapshotService) {
+				m.On("Create", mock.Anything, "alice", "Main", []models.BankEntry{{ItemID: 4151, Quantity: 1}}).
+					Return(&models.BankSnapshotDetail{BankSnapshot: models.BankSnapshot{ID: 1}}, nil)
+			},
+		},
+		{
+			name:   "create over limit",
+			method: "POST",
+			path:   "/bank-snapshots",
+			body:   "4151,1\n",
+			status: 400,
+			setup: func(m *MockBankSnapshotService) {
+				m.On("Create", mock.Anything, "alice", "", mock.Anything).
+					Return(nil, fmt.Errorf("%w: at most 25 snapshots can be saved; delete one first", models.ErrInvalidBankSnapshot))
+			},
+			expected: "at most 25 snapshots can be saved; delete one first",
+		},
+		{
+			name:   "list",
+			method: "GET",
+			path:   "/bank-snapshots",
+			status: 200,
+			setup: func(m *MockBankSnapshotService) {
+				m.On("List", mock.Anything, "alice").Return([]models.BankSnapshot{}, nil)
+			},
+		},
+		{name: "get bad id", method: "GET", path: "/bank-snapshots/abc", status: 400, expected: "invalid snapshot ID"},
+		{
+			name:   "get missing",
+			method: "GET",
+			path:   "/bank-snapshots/9",
+			status: 404,
+			setup: func(m *MockBankSnapshotService) {
+				m.On("Get", mock.Anything, "alice", int64(9)).Return(nil, nil)
+			},
+			expected: "snapshot not found",
+		},
+		{
+			name:   "history",
+			method: "GET",
+			path:   "/bank-snapshots/1/history?from=2026-03-01&interval=6h&source=revalued",
+			status: 200,
+			setup: func(m *MockBankSnapshotService) {
+				m.On("History", mock.Anything, "alice", int64(1), models.NetWorthHistoryParams{
+					From: from, Interval: 6 * time.Hour, Source: models.NetWorthSourceRevalued,
+				}).Return(&models.NetWorthHistory{SnapshotID: 1, Points: []models.NetWorthPoint{}}, nil)
+			},
+		},
+		{name: "history bad interval", method: "GET", path: "/bank-snapshots/1/history?interval=2h", status: 400, expected: "interval must be one of 1h, 6h, 1d, 7d"},
+		{name: "history bad from", method: "GET", path: "/bank-snapshots/1/history?from=yesterday", status: 400, expected: "invalid from timestamp"},
+		{
+			name:   "history error",
+			method: "GET",
+			path:   "/bank-snapshots/1/history",
+			status: 500,
+			setup: func(m *MockBankSnapshotService) {
+				m.On("History", mock.Anything, "alice", int64(1), models.NetWorthHistoryParams{}).Return(nil, errors.New("db down"))
+			},
+			expected: "failed to get net worth history",
+		},
+		{
+			name:   "delete",
+			method: "DELETE",
+			path:   "/bank-snapshots/1",
+			status: 204,
+			setup: func(m *MockBankSnapshotService) {
+				m.On("Delete", mock.Anything, "alice", int64(1)).Return(true, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBankSnapshotService := new(MockBankSnapshotService)
+			if tt.setup != nil {
+				tt.setup(mockBankSnapshotService)
+			}
+			handler := handlers.NewBankSnapshotHandler(mockBankSnapshotService, nil, logger)
+
+			app := fiber.New()
+			group := app.Group("/bank-snapshots", middleware.RequireUserID())
+			group.Post("/", handler.CreateSnapshot)
+			group.Get("/", handler.ListSnapshots)
+			group.Get("/:id", handler.GetSnapshot)
+			group.Get("/:id/history", handler.GetHistory)
+			group.Delete("/:id", handler.DeleteSnapshot)
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "text/csv")
+			req.Header.Set(middleware.UserIDHeader, "alice")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var result map[string]any
+			_ = json.NewDecoder(resp.Body).Decode(&result)
+			switch {
+			case tt.expected != "":
+				assert.Equal(t, tt.expected, result["error"])
+			case tt.status < 300 && tt.status != 204:
+				assert.NotNil(t, result["data"])
+			}
+			mockBankSnapshotService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,378 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/middleware"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// fakeBuyLimitRepo is a BuyLimitRepository that applies purchases to
+// in-memory timers the way the database upsert does.
+type fakeBuyLimitRepo struct {
+	timers    map[int]*models.BuyLimitTimer
+	purchases []models.BuyLimitPurchase
+	resets    []models.BuyLimitTimer
+}
+
+func (r *fakeBuyLimitRepo) RecordPurchase(_ context.Context, purchase *models.BuyLimitPurchase) (*models.BuyLimitTimer, error) {
+	purchase.ID = int64(len(r.purchases) + 1)
+	r.purchases = append(r.purchases, *purchase)
+	if r.timers == nil {
+		r.timers = make(map[int]*models.BuyLimitTimer)
+	}
+	timer, ok := r.timers[purchase.ItemID]
+	if !ok || !timer.ResetsAt.After(purchase.PurchasedAt) {
+		timer = &models.BuyLimitTimer{
+			UserID:      purchase.UserID,
+			ItemID:      purchase.ItemID,
+			WindowStart: purchase.PurchasedAt,
+			ResetsAt:    purchase.PurchasedAt.Add(models.BuyLimitWindow),
+		}
+		r.timers[purchase.ItemID] = timer
+	}
+	timer.Bought += purchase.Quantity
+	copied := *timer
+	return &copied, nil
+}
+
+func (r *fakeBuyLimitRepo) DeletePurchase(_ context.Context, _ string, _ int64) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeBuyLimitRepo) GetTimer(_ context.Context, _ string, itemID int) (*models.BuyLimitTimer, error) {
+	return r.timers[itemID], nil
+}
+
+func (r *fakeBuyLimitRepo) ListTimers(_ context.Context, _ string) ([]models.BuyLimitTimer, error) {
+	timers := make([]models.BuyLimitTimer, 0, len(r.timers))
+	for _, t := range r.timers {
+		timers = append(timers, *t)
+	}
+	return timers, nil
+}
+
+func (r *fakeBuyLimitRepo) ListPurchases(_ context.Context, _ string, itemID int, since time.Time) ([]models.BuyLimitPurchase, error) {
+	purchases := make([]models.BuyLimitPurchase, 0)
+	for _, p := range r.purchases {
+		if p.ItemID == itemID && !p.PurchasedAt.Before(since) {
+			purchases = append(purchases, p)
+		}
+	}
+	return purchases, nil
+}
+
+func (r *fakeBuyLimitRepo) ClaimResets(_ context.Context, _ time.Time) ([]models.BuyLimitTimer, error) {
+	return r.resets, nil
+}
+
+func TestNewBuyLimitStatus(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	limit := 70
+	timer := &models.BuyLimitTimer{ItemID: 4151, Bought: 50, WindowStart: now.Add(-time.Hour), ResetsAt: now.Add(3 * time.Hour)}
+
+	status := models.NewBuyLimitStatus(4151, &limit, timer, now)
+	assert.True(t, status.Available)
+	assert.Equal(t, int64(50), status.Bought)
+	require.NotNil(t, status.Remaining)
+	assert.Equal(t, int64(20), *status.Remaining)
+	require.NotNil(t, status.ResetsInSeconds)
+	assert.Equal(t, int64(3*60*60), *status.ResetsInSeconds)
+
+	timer.Bought = 90
+	status = models.NewBuyLimitStatus(4151, &limit, timer, now)
+	assert.False(t, status.Available)
+	assert.Equal(t, int64(0), *status.Remaining, "overbuying never goes negative")
+
+	status = models.NewBuyLimitStatus(4151, &limit, timer, timer.ResetsAt)
+	assert.True(t, status.Available, "the limit resets when the window closes")
+	assert.Equal(t, int64(70), *status.Remaining)
+	assert.Nil(t, status.ResetsAt)
+
+	status = models.NewBuyLimitStatus(4151, nil, timer, now)
+	assert.True(t, status.Available, "unknown limits never block")
+	assert.Nil(t, status.Remaining)
+	assert.Equal(t, int64(90), status.Bought)
+}
+
+func TestBuyLimitService_RecordPurchase(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	ctx := context.Background()
+	limit := 70
+	repo := &fakeBuyLimitRepo{}
+	itemRepo := &fakeItemRepo{itemsByID: map[int]*models.Item{
+		4151: {ItemID: 4151, Name: "Abyssal whip", BuyLimit: &limit},
+	}}
+	svc := services.NewBuyLimitService(repo, itemRepo, logger)
+
+	earlier := time.Now().UTC().Add(-time.Hour)
+	status, err := svc.RecordPurchase(ctx, "alice", models.BuyLimitPurchaseRequest{ItemID: 4151, Quantity: 40, PurchasedAt: &earlier})
+	require.NoError(t, err)
+	assert.Equal(t, "Abyssal whip", status.ItemName)
+	assert.Equal(t, int64(30), *status.Remaining)
+
+	status, err = svc.RecordPurchase(ctx, "alice", models.BuyLimitPurchaseRequest{ItemID: 4151, Quantity: 30})
+	require.NoError(t, err)
+	assert.False(t, status.Available)
+	require.NotNil(t, status.ResetsAt)
+	assert.WithinDuration(t, earlier.Add(models.BuyLimitWindow), *status.ResetsAt, time.Second,
+		"the window runs from the first purchase")
+
+	full, err := svc.GetStatus(ctx, "alice", 4151)
+	require.NoError(t, err)
+	assert.Len(t, full.Purchases, 2)
+
+	unknown, err := svc.GetStatus(ctx, "alice", 999999)
+	require.NoError(t, err)
+	assert.Nil(t, unknown)
+
+	stale := time.Now().UTC().Add(-5 * time.Hour)
+	future := time.Now().UTC().Add(time.Hour)
+	tests := []struct {
+		req      models.BuyLimitPurchaseRequest
+		expected string
+	}{
+		{models.BuyLimitPurchaseRequest{Quantity: 1}, "itemId is required"},
+		{models.BuyLimitPurchaseRequest{ItemID: 4151}, "quantity must be between 1 and 2147483647"},
+		{models.BuyLimitPurchaseRequest{ItemID: 4151, Quantity: 1, PurchasedAt: &future}, "purchasedAt must not be in the future"},
+		{models.BuyLimitPurchaseRequest{ItemID: 4151, Quantity: 1, PurchasedAt: &stale}, "purchasedAt must be within the last 4 hours"},
+		{models.BuyLimitPurchaseRequest{ItemID: 999999, Quantity: 1}, "unknown item ID: 999999"},
+	}
+	for _, tt := range tests {
+		_, err := svc.RecordPurchase(ctx, "alice", tt.req)
+		require.ErrorIs(t, err, models.ErrInvalidBuyLimitPurchase)
+		assert.Equal(t, "invalid purchase: "+tt.expected, err.Error())
+	}
+}
+
+func TestBuyLimitService_ListsAndResets(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	ctx := context.Background()
+	now := time.Now().UTC()
+	whipLimit, dragonLimit := 70, 8
+	repo := &fakeBuyLimitRepo{timers: map[int]*models.BuyLimitTimer{
+		4151:  {ItemID: 4151, Bought: 70, WindowStart: now.Add(-time.Hour), ResetsAt: now.Add(3 * time.Hour)},
+		11802: {ItemID: 11802, Bought: 8, WindowStart: now.Add(-5 * time.Hour), ResetsAt: now.Add(-time.Hour)},
+		2:     {ItemID: 2, Bought: 100, WindowStart: now.Add(-time.Hour), ResetsAt: now.Add(3 * time.Hour)},
+	}}
+	itemRepo := &fakeItemRepo{itemsByID: map[int]*models.Item{
+		4151:  {ItemID: 4151, Name: "Abyssal whip", BuyLimit: &whipLimit},
+		11802: {ItemID: 11802, Name: "Armadyl godsword", BuyLimit: &dragonLimit},
+		2:     {ItemID: 2, Name: "Cannonball"},
+	}}
+	svc := services.NewBuyLimitService(repo, itemRepo, logger)
+
+	active, err := svc.ListActive(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, active, 2, "closed windows are not active")
+	assert.ElementsMatch(t, []int{2, 4151}, []int{active[0].ItemID, active[1].ItemID})
+
+	available, err := svc.ListAvailable(ctx, "alice", nil)
+	require.NoError(t, err)
+	require.Len(t, available, 2)
+	assert.Equal(t, 2, available[0].ItemID, "items without a known limit are always available")
+	assert.Equal(t, 11802, available[1].ItemID)
+	assert.Equal(t, int64(8), *available[1].Remaining)
+
+	available, err = svc.ListAvailable(ctx, "alice", []int{999999, 4151, 11802})
+	require.NoError(t, err)
+	require.Len(t, available, 1, "unknown and exhausted items are skipped")
+	assert.Equal(t, 11802, available[0].ItemID)
+
+	repo.resets = []models.BuyLimitTimer{{UserID: "alice", ItemID: 11802, Bought: 8, ResetsAt: now.Add(-time.Minute)}}
+	resets, err := svc.ProcessResets(ctx)
+	require.NoError(t, err)
+	require.Len(t, resets, 1)
+	assert.Equal(t, "alice", resets[0].UserID)
+	assert.Equal(t, "Armadyl godsword", resets[0].ItemName)
+	assert.Equal(t, &dragonLimit, resets[0].Limit)
+	assert.Equal(t, 4, itemRepo.getByItemIDCalls, "each call looks its items up in one query")
+}
+
+func TestSSEHub_UserMessagesOnlyReachThatUser(t *testing.T) {
+	hub := services.NewSSEHub(zap.NewNop().Sugar(), 10)
+	go hub.Run()
+	defer hub.Stop()
+
+	alice := &services.SSEClient{ID: "a", UserID: "alice", MessageChan: make(chan services.SSEMessage, 10)}
+	anonymous := &services.SSEClient{ID: "b", MessageChan: make(chan services.SSEMessage, 10)}
+	hub.Register(alice)
+	hub.Register(anonymous)
+
+	itemID := 4151
+	hub.Broadcast(services.SSEMessage{Event: "buy-limit-reset", UserID: "alice", ItemID: &itemID})
+	hub.Broadcast(services.SSEMessage{Event: "price-update", ItemID: &itemID})
+
+	require.Eventually(t, func() bool { return len(alice.MessageChan) == 2 && len(anonymous.MessageChan) == 1 },
+		time.Second, 10*time.Millisecond)
+	assert.Equal(t, "price-update", (<-anonymous.MessageChan).Event)
+}
+
+func TestBuyLimitHandler(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	purchasedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		setup    func(m *MockBuyLimitService)
+		name     string
+		method   string
+		path     string
+		body     string
+		expected string
+		status   int
+	}{
+		{
+			name:   "active",
+			method: "GET",
+			path:   "/buy-limits",
+			status: 200,
+			setup: func(m *MockBuyLimitService) {
+				m.On("ListActive", mock.Anything, "alice").Return([]models.BuyLimitStatus{}, nil)
+			},
+		},
+		{
+			name:   "available tracked",
+			method: "GET",
+			path:   "/buy-limits/available",
+			status: 200,
+			setup: func(m *MockBuyLimitService) {
+				m.On("ListAvailable", mock.Anything, "alice", []int(nil)).Return([]models.BuyLimitStatus{}, nil)
+			},
+		},
+		{
+			name:   "available ids",
+			method: "GET",
+			path:   "/buy-limits/available?ids=4151,11802",
+			status: 200,
+			setup: func(m *MockBuyLimitService) {
+				m.On("ListAvailable", mock.Anything, "alice", []int{4151, 11802}).Return([]models.BuyLimitStatus{}, nil)
+			},
+		},
+		{name: "available bad ids", method: "GET", path: "/buy-limits/available?ids=abc", status: 400, expected: "invalid item ID: abc"},
+		{name: "available empty ids", method: "GET", path: "/buy-limits/available?ids=,", status: 400, expected: "at least one item ID is required"},
+		{name: "status bad id", method: "GET", path: "/buy-limits/abc", status: 400, expected: "invalid item ID"},
+		{
+			name:   "status unknown item",
+			method: "GET",
+			path:   "/buy-limits/999999",
+			status: 404,
+			setup: func(m *MockBuyLimitService) {
+				m.On("GetStatus", mock.Anything, "alice", 999999).Return(nil, nil)
+			},
+			expected: "item not found",
+		},
+		{
+			name:   "status",
+			method: "GET",
+			path:   "/buy-limits/4151",
+			status: 200,
+			setup: func(m *MockBuyLimitService) {
+				m.On("GetStatus", mock.Anything, "alice", 4151).Return(&models.BuyLimitStatus{ItemID: 4151, Available: true}, nil)
+			},
+		},
+		{name: "record bad body", method: "POST", path: "/buy-limits/purchases", body: "{", status: 400, expected: "invalid request body"},
+		{
+			name:   "record invalid",
+			method: "POST",
+			path:   "/buy-limits/purchases",
+			body:   `{"itemId":4151}`,
+			status: 400,
+			setup: func(m *MockBuyLimitService) {
+				m.On("RecordPurchase", mock.Anything, "alice", models.BuyLimitPurchaseRequest{ItemID: 4151}).
+					Return(nil, fmt.Errorf("%w: quantity must be between 1 and 2147483647", models.ErrInvalidBuyLimitPurchase))
+			},
+			expected: "quantity must be between 1 and 2147483647",
+		},
+		{
+			name:   "record",
+			method: "POST",
+			path:   "/buy-limits/purchases",
+			body:   `{"itemId":4151,"quantity":40,"purchasedAt":"2026-03-01T12:00:00Z"}`,
+			status: 201,
+			setup: func(m *MockBuyLimitService) {
+				m.On("RecordPurchase", mock.Anything, "alice", models.BuyLimitPurchaseRequest{ItemID: 4151, Quantity: 40, PurchasedAt: &purchasedAt}).
+					Return(&models.BuyLimitStatus{ItemID: 4151, Bought: 40}, nil)
+			},
+		},
+		{
+			name:   "record error",
+			method: "POST",
+			path:   "/buy-limits/purchases",
+			body:   `{"itemId":4151,"quantity":1}`,
+			status: 500,
+			setup: func(m *MockBuyLimitService) {
+				m.On("RecordPurchase", mock.Anything, "alice", mock.Anything).Return(nil, errors.New("db down"))
+			},
+			expected: "failed to record purchase",
+		},
+		{
+			name:   "delete missing",
+			method: "DELETE",
+			path:   "/buy-limits/purchases/9",
+			status: 404,
+			setup: func(m *MockBuyLimitService) {
+				m.On("DeletePurchase", mock.Anything, "alice", int64(9)).Return(false, nil)
+			},
+			expected: "purchase not found",
+		},
+		{
+			name:   "delete",
+			method: "DELETE",
+			path:   "/buy-limits/purchases/9",
+			status: 204,
+			setup: func(m *MockBuyLimitService) {
+				m.On("DeletePurchase", mock.Anything, "alice", int64(9)).Return(true, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBuyLimitService := new(MockBuyLimitService)
+			if tt.setup != nil {
+				tt.setup(mockBuyLimitService)
+			}
+			handler := handlers.NewBuyLimitHandler(mockBuyLimitService, nil, logger)
+
+			app := fiber.New()
+			group := app.Group("/buy-limits", middleware.RequireUserID())
+			group.Get("/", handler.ListActive)
+			group.Get("/available", handler.ListAvailable)
+			group.Post("/purchases", handler.RecordPurchase)
+			group.Delete("/purchases/:id", handler.DeletePurchase)
+			group.Get("/:itemId", handler.GetStatus)
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(middleware.UserIDHeader, "alice")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var result map[string]any
+			_ = json.NewDecoder(resp.Body).Decode(&result)
+			switch {
+			case tt.expected != "":
+				assert.Equal(t, tt.expected, result["error"])
+			case tt.status < 300 && tt.status != 204:
+				assert.NotNil(t, result["data"])
+			}
+			mockBuyLimitService.AssertExpectations(t)
+		})
+	}
+}
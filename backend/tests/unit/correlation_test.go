@@ -0,0 +1,241 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
+)
+
+func TestPearsonAndSpearman(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 5}
+
+	r := utils.Pearson(x, []float64{2, 4, 6, 8, 10})
+	require.NotNil(t, r)
+	assert.InDelta(t, 1, *r, 1e-12)
+
+	r = utils.Pearson(x, []float64{5, 4, 3, 2, 1})
+	require.NotNil(t, r)
+	assert.InDelta(t, -1, *r, 1e-12)
+
+	assert.Nil(t, utils.Pearson(x, []float64{3, 3, 3, 3, 3}), "zero variance has no correlation")
+	assert.Nil(t, utils.Pearson([]float64{1}, []float64{1}))
+
+	// A monotone but non-linear relation is a perfect rank correlation only.
+	cubes := []float64{1, 8, 27, 64, 125}
+	pearson := utils.Pearson(x, cubes)
+	spearman := utils.Spearman(x, cubes)
+	require.NotNil(t, pearson)
+	require.NotNil(t, spearman)
+	assert.Less(t, *pearson, 0.99)
+	assert.InDelta(t, 1, *spearman, 1e-12)
+
+	assert.Equal(t, []float64{1, 2.5, 2.5, 4}, utils.Ranks([]float64{10, 20, 20, 30}))
+}
+
+func TestLogReturnsAndPairwiseComplete(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+
+	returns := utils.LogReturns([]*float64{f(100), f(110), nil, f(121), f(0), f(10)})
+	require.Len(t, returns, 6)
+	assert.Nil(t, returns[0])
+	require.NotNil(t, returns[1])
+	assert.InDelta(t, math.Log(1.1), *returns[1], 1e-12)
+	assert.Nil(t, returns[2])
+	assert.Nil(t, returns[3], "no return across a missing price")
+	assert.Nil(t, returns[5], "no return from a zero price")
+
+	x, y := utils.PairwiseComplete([]*float64{f(1), nil, f(3)}, []*float64{f(4), f(5), nil})
+	assert.Equal(t, []float64{1}, x)
+	assert.Equal(t, []float64{4}, y)
+}
+
+// correlatedTimeseries builds hourly points ending now for a base price path
+// transformed by price.
+func correlatedTimeseries(itemID, hours int, step time.Duration, price func(base float64) float64) []models.PriceTimeseriesPoint {
+	end := time.Now().UTC().Truncate(step)
+	points := make([]models.PriceTimeseriesPoint, 0, hours)
+	base := 1000.0
+	for i := hours - 1; i >= 0; i-- {
+		base *= math.Exp(0.01 * math.Sin(float64(i)*1.3) * math.Cos(float64(i)*0.7))
+		v := int64(math.Round(price(base)))
+		points = append(points, models.PriceTimeseriesPoint{
+			ItemID:       itemID,
+			Timestamp:    end.Add(-time.Duration(i) * step),
+			AvgHighPrice: &v,
+			AvgLowPrice:  &v,
+		})
+	}
+	return points
+}
+
+func TestAnalyticsService_GetCorrelation(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	ctx := context.Background()
+	hours := 30 * 24
+	priceRepo := &fakePriceRepo{timeseriesPoints: map[int][]models.PriceTimeseriesPoint{
+		1: correlatedTimeseries(1, hours, time.Hour, func(b float64) float64 { return b * 1000 }),
+		2: correlatedTimeseries(2, hours, time.Hour, func(b float64) float64 { return b * 3000 }),
+		3: correlatedTimeseries(3, hours, time.Hour, func(b float64) float64 { return 1e12 / b }),
+	}}
+	svc := services.NewAnalyticsService(priceRepo, newMemoryCache(), logger)
+
+	result, err := svc.GetCorrelation(ctx, models.CorrelationParams{ItemIDs: []int{1, 2, 3, 4}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "1h", result.Timestep)
+	assert.Equal(t, int64(3600), result.IntervalSeconds)
+	assert.Equal(t, models.DefaultCorrelationWindowDays, result.WindowDays)
+	require.Len(t, result.Pearson, 4)
+
+	require.NotNil(t, result.Pearson[0][1])
+	assert.InDelta(t, 1, *result.Pearson[0][1], 1e-4, "scaled prices move together")
+	require.NotNil(t, result.Pearson[0][2])
+	assert.InDelta(t, -1, *result.Pearson[0][2], 1e-4, "inverse prices move opposite")
+	require.NotNil(t, result.Spearman[2][0])
+	assert.InDelta(t, -1, *result.Spearman[2][0], 1e-3)
+	assert.Equal(t, result.Pearson[0][2], result.Pearson[2][0])
+	assert.Greater(t, result.Observations[0][1], hours-5)
+
+	assert.Nil(t, result.Pearson[0][3], "item without history")
+	assert.Zero(t, result.Observations[0][3])
+	assert.Nil(t, result.Pearson[3][3])
+
+	require.Len(t, result.Pairs, 6)
+	assert.NotNil(t, result.Pairs[0].Pearson)
+	assert.InDelta(t, 1, math.Abs(*result.Pairs[0].Pearson), 1e-4)
+	assert.Nil(t, result.Pairs[5].Pearson, "pairs without a correlation sort last")
+
+	_, err = svc.GetCorrelation(ctx, models.CorrelationParams{ItemIDs: []int{1}})
+	assert.Error(t, err)
+}
+
+func TestAnalyticsService_GetCorrelation_LongWindowUsesDaily(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	days := 120
+	daily := func(itemID int, scale float64) []models.PriceTimeseriesDaily {
+		out := make([]models.PriceTimeseriesDaily, 0, days)
+		for _, p := range correlatedTimeseries(itemID, days, 24*time.Hour, func(b float64) float64 { return b * scale }) {
+			out = append(out, models.PriceTimeseriesDaily{
+				ItemID:       itemID,
+				Day:          p.Timestamp,
+				AvgHighPrice: p.AvgHighPrice,
+				AvgLowPrice:  p.AvgLowPrice,
+			})
+		}
+		return out
+	}
+	priceRepo := &fakePriceRepo{dailyPoints: map[int][]models.PriceTimeseriesDaily{
+		1: daily(1, 1000),
+		2: daily(2, 5000),
+	}}
+	svc := services.NewAnalyticsService(priceRepo, newMemoryCache(), logger)
+
+	result, err := svc.GetCorrelation(context.Background(), models.CorrelationParams{
+		ItemIDs: []int{1, 2}, WindowDays: 90, Field: models.PriceFieldLow,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "24h", result.Timestep)
+	assert.Equal(t, "low", result.Field)
+	require.NotNil(t, result.Pearson[0][1])
+	assert.InDelta(t, 1, *result.Pearson[0][1], 1e-3)
+	assert.InDelta(t, 90, result.Observations[0][1], 1)
+}
+
+func TestAnalyticsHandler_GetCorrelation(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+
+	tests := []struct {
+		setup    func(a *MockAnalyticsService, w *MockWatchlistService)
+		name     string
+		path     string
+		expected string
+		status   int
+	}{
+		{name: "missing ids", path: "/analytics/correlation", status: 400, expected: "ids or share query parameter is required"},
+		{name: "single item", path: "/analytics/correlation?ids=4151,4151", status: 400, expected: "at least 2 distinct item IDs are required"},
+		{
+			name:     "too many items",
+			path:     "/analytics/correlation?ids=1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21",
+			status:   400,
+			expected: "maximum 20 items per request",
+		},
+		{name: "short window", path: "/analytics/correlation?ids=1,2&window=3d", status: 400, expected: "window must be between 7d and 365d"},
+		{name: "bad field", path: "/analytics/correlation?ids=1,2&field=open", status: 400, expected: "field must be one of: mid, high, low"},
+		{
+			name:   "expired share",
+			path:   "/analytics/correlation?share=gone-grey-goblin",
+			status: 404,
+			setup: func(_ *MockAnalyticsService, w *MockWatchlistService) {
+				w.On("GetShareItemIDs", mock.Anything, "gone-grey-goblin").Return(nil, models.ErrShareExpired)
+			},
+			expected: "share has expired",
+		},
+		{
+			name:   "service error",
+			path:   "/analytics/correlation?ids=1,2",
+			status: 500,
+			setup: func(a *MockAnalyticsService, _ *MockWatchlistService) {
+				a.On("GetCorrelation", mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+			},
+			expected: "failed to compute correlation",
+		},
+		{
+			name:   "share ok",
+			path:   "/analytics/correlation?share=swift-golden-dragon&window=60d&field=high",
+			status: 200,
+			setup: func(a *MockAnalyticsService, w *MockWatchlistService) {
+				w.On("GetShareItemIDs", mock.Anything, "swift-golden-dragon").Return([]int{4151, 11802}, nil)
+				a.On("GetCorrelation", mock.Anything, models.CorrelationParams{ItemIDs: []int{4151, 11802}, WindowDays: 60, Field: "high"}).
+					Return(&models.CorrelationResponse{ItemIDs: []int{4151, 11802}, WindowDays: 60, Field: "high", Timestep: "24h"}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAnalyticsService := new(MockAnalyticsService)
+			mockWatchlistService := new(MockWatchlistService)
+			if tt.setup != nil {
+				tt.setup(mockAnalyticsService, mockWatchlistService)
+			}
+			handler := handlers.NewAnalyticsHandler(mockAnalyticsService, mockWatchlistService, logger)
+
+			app := fiber.New()
+			app.Get("/analytics/correlation", handler.GetCorrelation)
+
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path, http.NoBody))
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var result map[string]any
+			_ = json.NewDecoder(resp.Body).Decode(&result)
+			if tt.expected != "" {
+				assert.Equal(t, tt.expected, result["error"])
+			} else {
+				meta := result["meta"].(map[string]any)
+				assert.Equal(t, float64(2), meta["item_count"])
+				assert.Equal(t, float64(60), meta["window_days"])
+				assert.Equal(t, "24h", meta["timestep"])
+			}
+			mockAnalyticsService.AssertExpectations(t)
+			mockWatchlistService.AssertExpectations(t)
+		})
+	}
+}
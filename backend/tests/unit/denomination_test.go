@@ -0,0 +1,244 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+func bondPrice(high, low int64) *models.CurrentPrice {
+	return &models.CurrentPrice{ItemID: models.BondItemID, HighPrice: int64Ptr(high), LowPrice: int64Ptr(low)}
+}
+
+func TestBondRate_Convert(t *testing.T) {
+	realPrice := 7.0
+	rate := models.BondRate{Price: 10_000_000, RealPrice: &realPrice}
+
+	assert.Equal(t, 25_000_000.0, rate.Convert(25_000_000, models.DenominationGP))
+	assert.InDelta(t, 2.5, rate.Convert(25_000_000, models.DenominationBonds), 1e-9)
+	assert.InDelta(t, 17.5, rate.Convert(25_000_000, models.DenominationReal), 1e-9)
+	assert.Nil(t, rate.ConvertPtr(nil, models.DenominationBonds))
+	assert.InDelta(t, 0.5, *rate.ConvertPtr(int64Ptr(5_000_000), models.DenominationBonds), 1e-9)
+}
+
+func TestDenominationService_DenominateCurrentPrices(t *testing.T) {
+	ctx := context.Background()
+	mockPriceService := new(MockPriceService)
+	mockPriceService.On("GetCurrentPrice", ctx, models.BondItemID).Return(bondPrice(10_200_000, 9_800_000), nil)
+	svc := services.NewDenominationService(mockPriceService, 8, "USD", zap.NewNop().Sugar())
+
+	prices := []models.CurrentPrice{{ItemID: 4151, HighPrice: int64Ptr(2_000_000)}}
+	converted, rate, err := svc.DenominateCurrentPrices(ctx, prices, models.DenominationReal)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10_000_000), rate.Price)
+	assert.Equal(t, "USD", rate.Currency)
+	require.Len(t, converted, 1)
+	assert.InDelta(t, 1.6, *converted[0].HighPrice, 1e-9)
+	assert.Nil(t, converted[0].LowPrice)
+}
+
+func TestDenominationService_Errors(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop().Sugar()
+
+	mockPriceService := new(MockPriceService)
+	svc := services.NewDenominationService(mockPriceService, 0, "USD", logger)
+	_, err := svc.CurrentRate(ctx, models.DenominationReal)
+	assert.ErrorIs(t, err, models.ErrInvalidDenomination)
+	_, err = svc.CurrentRate(ctx, models.Denomination("euros"))
+	assert.ErrorIs(t, err, models.ErrInvalidDenomination)
+
+	mockPriceService.On("GetCurrentPrice", ctx, models.BondItemID).Return(nil, nil).Once()
+	_, err = svc.CurrentRate(ctx, models.DenominationBonds)
+	assert.ErrorIs(t, err, models.ErrBondPriceUnavailable)
+
+	mockPriceService.On("GetCurrentPrice", ctx, models.BondItemID).Return(nil, errors.New("db down")).Once()
+	_, err = svc.CurrentRate(ctx, models.DenominationBonds)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, models.ErrBondPriceUnavailable)
+}
+
+func TestDenominationService_DenominatePriceHistory(t *testing.T) {
+	ctx := context.Background()
+	t0 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mockPriceService := new(MockPriceService)
+	mockPriceService.On("GetPriceHistory", ctx, mock.MatchedBy(func(p models.PriceHistoryParams) bool {
+		return p.ItemID == models.BondItemID && p.Period == models.Period7Days
+	})).Return(&models.PriceHistoryResponse{
+		ItemID: models.BondItemID,
+		Data: []models.PricePoint{
+			// Out of order on purpose; the service sorts the bond series.
+			{Timestamp: t0.Add(2 * time.Hour), HighPrice: 20_000_000, LowPrice: 20_000_000},
+			{Timestamp: t0.Add(time.Hour), HighPrice: 10_000_000, LowPrice: 10_000_000},
+			{Timestamp: t0.Add(90 * time.Minute)}, // no trades, skipped
+		},
+	}, nil)
+	svc := services.NewDenominationService(mockPriceService, 0, "USD", zap.NewNop().Sugar())
+
+	history := &models.PriceHistoryResponse{
+		ItemID: 4151,
+		Period: string(models.Period7Days),
+		Data: []models.PricePoint{
+			{Timestamp: t0, HighPrice: 2_000_000, LowPrice: 1_000_000},                        // before the first bond price
+			{Timestamp: t0.Add(time.Hour), HighPrice: 2_000_000, LowPrice: 1_000_000},         // exact match
+			{Timestamp: t0.Add(100 * time.Minute), HighPrice: 2_000_000, LowPrice: 1_000_000}, // as of 1h
+			{Timestamp: t0.Add(3 * time.Hour), HighPrice: 2_000_000, LowPrice: 1_000_000},     // as of 2h
+		},
+	}
+
+	converted, err := svc.DenominatePriceHistory(ctx, history, models.DenominationBonds)
+	require.NoError(t, err)
+	assert.Equal(t, 1, converted.Unconverted)
+	require.Len(t, converted.Points, 3)
+	assert.InDelta(t, 0.2, converted.Points[0].HighPrice, 1e-9)
+	assert.InDelta(t, 0.1, converted.Points[0].LowPrice, 1e-9)
+	assert.Equal(t, int64(10_000_000), converted.Points[1].BondPrice)
+	assert.InDelta(t, 0.1, converted.Points[2].HighPrice, 1e-9)
+	assert.Equal(t, int64(20_000_000), converted.Points[2].BondPrice)
+}
+
+func TestDenominationService_DenominateNetWorth(t *testing.T) {
+	ctx := context.Background()
+	day3 := time.Now().UTC().Truncate(24 * time.Hour)
+	day2 := day3.Add(-24 * time.Hour)
+	day1 := day2.Add(-24 * time.Hour)
+
+	// The whole range is loaded in one call, from the shortest period that
+	// reaches a day before the oldest point.
+	mockPriceService := new(MockPriceService)
+	mockPriceService.On("GetPriceHistory", ctx, mock.MatchedBy(func(p models.PriceHistoryParams) bool {
+		return p.ItemID == models.BondItemID && p.Period == models.Period7Days &&
+			p.StartTime.Equal(day1.Add(-24*time.Hour)) && p.EndTime.Equal(day3)
+	})).Return(&models.PriceHistoryResponse{
+		ItemID: models.BondItemID,
+		Data: []models.PricePoint{
+			{Timestamp: day2.Add(time.Hour), HighPrice: 10_000_000, LowPrice: 10_000_000},
+			{Timestamp: day2, HighPrice: 12_000_000},
+		},
+	}, nil).Once()
+	svc := services.NewDenominationService(mockPriceService, 0, "USD", zap.NewNop().Sugar())
+
+	history := &models.NetWorthHistory{
+		SnapshotID: 3,
+		Source:     models.NetWorthSourceRevalued,
+		Points: []models.NetWorthPoint{
+			{Timestamp: day1, TotalMid: 60_000_000},
+			{Timestamp: day2, TotalMid: 60_000_000, TotalHigh: 66_000_000, UnpricedItems: 2},
+			{Timestamp: day3, TotalMid: 60_000_000},
+		},
+	}
+	converted, err := svc.DenominateNetWorth(ctx, history, models.DenominationBonds)
+	require.NoError(t, err)
+	assert.Equal(t, 1, converted.Unconverted)
+	assert.Equal(t, int64(3), converted.SnapshotID)
+	require.Len(t, converted.Points, 2)
+	assert.InDelta(t, 5.0, converted.Points[0].TotalMid, 1e-9)
+	assert.InDelta(t, 5.5, converted.Points[0].TotalHigh, 1e-9)
+	assert.Equal(t, 2, converted.Points[0].UnpricedItems)
+	assert.Equal(t, int64(10_000_000), converted.Points[1].BondPrice)
+	mockPriceService.AssertExpectations(t)
+}
+
+func TestPriceHandler_Denomination(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+
+	tests := []struct {
+		setup    func(m *MockPriceService)
+		name     string
+		query    string
+		expected string
+		status   int
+		high     float64
+	}{
+		{
+			name:   "gp is unchanged",
+			query:  "",
+			status: 200,
+			high:   2_000_000,
+			setup: func(m *MockPriceService) {
+				m.On("GetCurrentPrice", mock.Anything, 4151).
+					Return(&models.CurrentPrice{ItemID: 4151, HighPrice: int64Ptr(2_000_000)}, nil)
+			},
+		},
+		{
+			name:   "bonds",
+			query:  "?denomination=bonds",
+			status: 200,
+			high:   0.2,
+			setup: func(m *MockPriceService) {
+				m.On("GetCurrentPrice", mock.Anything, 4151).
+					Return(&models.CurrentPrice{ItemID: 4151, HighPrice: int64Ptr(2_000_000)}, nil)
+				m.On("GetCurrentPrice", mock.Anything, models.BondItemID).Return(bondPrice(10_000_000, 10_000_000), nil)
+			},
+		},
+		{
+			name:     "unknown denomination",
+			query:    "?denomination=euros",
+			status:   400,
+			expected: "denomination must be gp, bonds or real",
+		},
+		{
+			name:     "real without a configured price",
+			query:    "?denomination=real",
+			status:   400,
+			expected: "no real-money bond price is configured",
+			setup: func(m *MockPriceService) {
+				m.On("GetCurrentPrice", mock.Anything, 4151).
+					Return(&models.CurrentPrice{ItemID: 4151, HighPrice: int64Ptr(2_000_000)}, nil)
+			},
+		},
+		{
+			name:     "no bond price",
+			query:    "?denomination=bonds",
+			status:   503,
+			expected: "no bond price is available to convert with",
+			setup: func(m *MockPriceService) {
+				m.On("GetCurrentPrice", mock.Anything, 4151).
+					Return(&models.CurrentPrice{ItemID: 4151, HighPrice: int64Ptr(2_000_000)}, nil)
+				m.On("GetCurrentPrice", mock.Anything, models.BondItemID).Return(nil, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPriceService := new(MockPriceService)
+			if tt.setup != nil {
+				tt.setup(mockPriceService)
+			}
+			denominations := services.NewDenominationService(mockPriceService, 0, "USD", logger)
+			handler := handlers.NewPriceHandler(mockPriceService, nil, denominations, logger)
+
+			app := fiber.New()
+			app.Get("/prices/current/:id", handler.GetCurrentPrice)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/prices/current/4151"+tt.query, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var result map[string]any
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			if tt.expected != "" {
+				assert.Equal(t, tt.expected, result["error"])
+			} else {
+				data := result["data"].(map[string]any)
+				assert.InDelta(t, tt.high, data["highPrice"], 1e-9)
+			}
+			mockPriceService.AssertExpectations(t)
+		})
+	}
+}
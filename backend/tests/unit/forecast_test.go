@@ -0,0 +1,250 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/forecast"
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// trendingSeasonalValues is an hourly series with a steady uptrend, a 24-hour
+// sine cycle and small deterministic jitter.
+func trendingSeasonalValues(n int) []float64 {
+	values := make([]float64, n)
+	for t := range values {
+		values[t] = 1000 + 0.5*float64(t) + 20*math.Sin(2*math.Pi*float64(t)/24) + 2*math.Sin(float64(t)*1.7)
+	}
+	return values
+}
+
+func TestSeasonalNaive_RepeatsLastSeason(t *testing.T) {
+	series := []float64{1, 2, 3, 4, 11, 12, 13, 14}
+	result, err := forecast.SeasonalNaive{Period: 4}.Forecast(series, 6)
+	require.NoError(t, err)
+
+	assert.Equal(t, []float64{11, 12, 13, 14, 11, 12}, result.Values)
+	assert.InDelta(t, 10, result.Sigma, 1e-9)
+	// The second season ahead is one more season of uncertainty.
+	assert.Greater(t, result.Upper[4]-result.Lower[4], result.Upper[3]-result.Lower[3])
+
+	_, err = forecast.SeasonalNaive{Period: 4}.Forecast(series[:4], 1)
+	assert.ErrorIs(t, err, forecast.ErrInsufficientData)
+}
+
+func TestHoltWinters_TracksTrendAndSeason(t *testing.T) {
+	series := trendingSeasonalValues(24 * 10)
+	train, actual := series[:len(series)-12], series[len(series)-12:]
+
+	model := forecast.HoltWinters{Period: 24}
+	result, err := model.Forecast(train, 12)
+	require.NoError(t, err)
+	require.Len(t, result.Values, 12)
+
+	for h, v := range result.Values {
+		assert.InDelta(t, actual[h], v, 8, "h=%d", h+1)
+		assert.LessOrEqual(t, result.Lower[h], v)
+		assert.GreaterOrEqual(t, result.Upper[h], v)
+	}
+	assert.Greater(t, result.Upper[11]-result.Lower[11], result.Upper[0]-result.Lower[0],
+		"intervals widen with the horizon")
+	assert.Contains(t, result.Params, "alpha")
+	assert.InDelta(t, 24, result.Params["period"], 0)
+
+	again, err := model.Forecast(train, 12)
+	require.NoError(t, err)
+	assert.Equal(t, result, again, "fitting is deterministic")
+}
+
+func TestHoltWinters_FallsBackToHoltWithoutFullSeasons(t *testing.T) {
+	series := make([]float64, 30)
+	for i := range series {
+		series[i] = 100 + 2*float64(i)
+	}
+	result, err := forecast.HoltWinters{Period: 24}.Forecast(series, 3)
+	require.NoError(t, err)
+
+	assert.NotContains(t, result.Params, "gamma")
+	assert.InDelta(t, 160, result.Values[0], 0.5)
+	assert.InDelta(t, 164, result.Values[2], 0.5)
+}
+
+func TestBacktest(t *testing.T) {
+	series := trendingSeasonalValues(24 * 8)
+
+	naive, err := forecast.Backtest(forecast.SeasonalNaive{Period: 24}, series, 6, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 3, naive.Folds)
+	// Seasonal naive lags a full day of trend behind.
+	assert.InDelta(t, 12, naive.MAE, 4)
+	assert.GreaterOrEqual(t, naive.RMSE, naive.MAE)
+	assert.Greater(t, naive.MAPE, 0.0)
+
+	hw, err := forecast.Backtest(forecast.HoltWinters{Period: 24}, series, 6, 3)
+	require.NoError(t, err)
+	assert.Less(t, hw.MAE, naive.MAE)
+
+	_, err = forecast.Backtest(forecast.SeasonalNaive{Period: 24}, series[:20], 6, 3)
+	assert.ErrorIs(t, err, forecast.ErrInsufficientData)
+}
+
+// forecastHistory turns values into hourly points ending at the current hour,
+// leaving a gap at gapAt (when non-negative) to exercise forward-fill.
+func forecastHistory(values []float64, gapAt int) []models.HourlyPricePoint {
+	end := time.Now().UTC().Truncate(time.Hour)
+	points := make([]models.HourlyPricePoint, 0, len(values))
+	for i, v := range values {
+		if i == gapAt {
+			continue
+		}
+		high := v * 1.01
+		points = append(points, models.HourlyPricePoint{
+			Timestamp: end.Add(-time.Duration(len(values)-1-i) * time.Hour),
+			MidPrice:  v,
+			HighPrice: &high,
+			Volume:    100,
+		})
+	}
+	return points
+}
+
+func TestAnalyticsService_GetForecast(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	ctx := context.Background()
+	values := trendingSeasonalValues(24 * 10)
+	priceRepo := &fakePriceRepo{hourlySeries: map[int][]models.HourlyPricePoint{4151: forecastHistory(values, 100)}}
+	svc := services.NewAnalyticsService(priceRepo, newMemoryCache(), logger)
+
+	result, err := svc.GetForecast(ctx, models.ForecastParams{ItemID: 4151})
+	require.NoError(t, err)
+
+	assert.Equal(t, models.DefaultForecastHorizon, result.Horizon)
+	assert.Equal(t, "mid", result.Field)
+	assert.Equal(t, "holt_winters", result.Model, "auto picks the lower backtest error on a trending series")
+	assert.Equal(t, len(values), result.Observations, "the missing hour is forward-filled")
+	assert.Equal(t, int64(3600), result.IntervalSeconds)
+	assert.InDelta(t, values[len(values)-1], result.LastValue, 1e-9)
+	require.Len(t, result.Backtests, 2)
+	assert.Equal(t, "seasonal_naive", result.Backtests[0].Model)
+	require.Len(t, result.Points, models.DefaultForecastHorizon)
+	assert.Equal(t, result.LastTimestamp.Add(time.Hour), result.Points[0].Timestamp)
+	for _, p := range result.Points {
+		assert.Less(t, p.Lower, p.Value)
+		assert.Greater(t, p.Upper, p.Value)
+	}
+
+	again, err := svc.GetForecast(ctx, models.ForecastParams{ItemID: 4151, Horizon: 6, Field: "mid", Model: "auto"})
+	require.NoError(t, err)
+	assert.Equal(t, result.Points, again.Points)
+	assert.Equal(t, 1, priceRepo.hourlySeriesCalls, "second request is served from cache")
+
+	high, err := svc.GetForecast(ctx, models.ForecastParams{
+		ItemID: 4151, Horizon: 3, Field: models.PriceFieldHigh, Model: models.ForecastModelSeasonalNaive,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "seasonal_naive", high.Model)
+	assert.InDelta(t, values[len(values)-24]*1.01, high.Points[0].Value, 1e-9)
+}
+
+func TestAnalyticsService_GetForecast_InsufficientData(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	values := trendingSeasonalValues(models.ForecastMinObservations - 1)
+	priceRepo := &fakePriceRepo{hourlySeries: map[int][]models.HourlyPricePoint{1: forecastHistory(values, -1)}}
+	svc := services.NewAnalyticsService(priceRepo, newMemoryCache(), logger)
+
+	_, err := svc.GetForecast(context.Background(), models.ForecastParams{ItemID: 1})
+	assert.ErrorIs(t, err, forecast.ErrInsufficientData)
+
+	_, err = svc.GetForecast(context.Background(), models.ForecastParams{ItemID: 2})
+	assert.ErrorIs(t, err, forecast.ErrInsufficientData)
+}
+
+func TestAnalyticsHandler_GetForecast(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+
+	tests := []struct {
+		setup    func(m *MockAnalyticsService)
+		name     string
+		path     string
+		expected string
+		status   int
+	}{
+		{name: "bad id", path: "/analytics/forecast/abc", status: 400, expected: "invalid item ID"},
+		{name: "zero horizon", path: "/analytics/forecast/4151?horizon=0", status: 400, expected: "horizon must be between 1 and 48 hours"},
+		{name: "long horizon", path: "/analytics/forecast/4151?horizon=49", status: 400, expected: "horizon must be between 1 and 48 hours"},
+		{name: "bad field", path: "/analytics/forecast/4151?field=open", status: 400, expected: "field must be one of: mid, high, low"},
+		{name: "bad model", path: "/analytics/forecast/4151?model=arima", status: 400, expected: "model must be one of: auto, holt_winters, seasonal_naive"},
+		{
+			name:   "no history",
+			path:   "/analytics/forecast/4151",
+			status: 404,
+			setup: func(m *MockAnalyticsService) {
+				m.On("GetForecast", mock.Anything, models.ForecastParams{ItemID: 4151, Horizon: 6, Field: "mid", Model: "auto"}).
+					Return(nil, fmt.Errorf("forecast item 4151: %w", forecast.ErrInsufficientData))
+			},
+			expected: "not enough price history to forecast",
+		},
+		{
+			name:   "service error",
+			path:   "/analytics/forecast/4151",
+			status: 500,
+			setup: func(m *MockAnalyticsService) {
+				m.On("GetForecast", mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+			},
+			expected: "failed to compute forecast",
+		},
+		{
+			name:   "ok",
+			path:   "/analytics/forecast/4151?horizon=12&field=low&model=holt_winters",
+			status: 200,
+			setup: func(m *MockAnalyticsService) {
+				m.On("GetForecast", mock.Anything, models.ForecastParams{ItemID: 4151, Horizon: 12, Field: "low", Model: "holt_winters"}).
+					Return(&models.ForecastResponse{ItemID: 4151, Horizon: 12, Field: "low", Model: "holt_winters", IntervalSeconds: 3600}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAnalyticsService := new(MockAnalyticsService)
+			if tt.setup != nil {
+				tt.setup(mockAnalyticsService)
+			}
+			handler := handlers.NewAnalyticsHandler(mockAnalyticsService, nil, logger)
+
+			app := fiber.New()
+			app.Get("/analytics/forecast/:id", handler.GetForecast)
+
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path, http.NoBody))
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var result map[string]any
+			_ = json.NewDecoder(resp.Body).Decode(&result)
+			if tt.expected != "" {
+				assert.Equal(t, tt.expected, result["error"])
+			} else {
+				meta := result["meta"].(map[string]any)
+				assert.Equal(t, float64(12), meta["horizon"])
+				assert.Equal(t, "holt_winters", meta["model"])
+				assert.Equal(t, float64(3600), meta["interval_seconds"])
+			}
+			mockAnalyticsService.AssertExpectations(t)
+		})
+	}
+}
@@ -277,6 +277,54 @@ func TestItemHandler_SearchItems_ServiceError(t *testing.T) {
 	mockItemService.AssertExpectations(t)
 }
 
+func TestItemHandler_SearchItems_Mode(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	mockItemService := new(MockItemService)
+	mockPriceService := new(MockPriceService)
+	handler := handlers.NewItemHandler(mockItemService, mockPriceService, logger)
+
+	score := 0.8123
+	mockItemService.On("SearchItems", mock.Anything, mock.MatchedBy(func(p models.ItemSearchParams) bool {
+		return p.Mode == models.ItemSearchModeFuzzy
+	})).Return([]models.Item{{ItemID: 536, Name: "Dragon bones", SearchScore: &score}}, nil)
+	mockItemService.On("SearchItems", mock.Anything, mock.MatchedBy(func(p models.ItemSearchParams) bool {
+		return p.Mode == models.ItemSearchModePrefix
+	})).Return([]models.Item{}, nil)
+
+	app := fiber.New()
+	app.Get("/items/search", handler.SearchItems)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/items/search?q=dragn%20bones", http.NoBody))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Meta map[string]any `json:"meta"`
+		Data []struct {
+			Score *float64 `json:"score"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, "fuzzy", result.Meta["mode"])
+	if assert.Len(t, result.Data, 1) && assert.NotNil(t, result.Data[0].Score) {
+		assert.InDelta(t, score, *result.Data[0].Score, 1e-9)
+	}
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/items/search?q=dragon&mode=prefix", http.NoBody))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/items/search?q=dragon&mode=regex", http.NoBody))
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+
+	var errResult map[string]any
+	_ = json.NewDecoder(resp.Body).Decode(&errResult)
+	assert.Equal(t, "mode must be fuzzy, exact or prefix", errResult["error"])
+
+	mockItemService.AssertExpectations(t)
+}
+
 func TestItemHandler_GetItemCount_OK(t *testing.T) {
 	logger := zap.NewNop().Sugar()
 	mockItemService := new(MockItemService)
@@ -0,0 +1,495 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/aliases"
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/middleware"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// fakeItemAliasRepo keeps item aliases in memory.
+type fakeItemAliasRepo struct {
+	aliases []models.ItemAlias
+}
+
+func (r *fakeItemAliasRepo) InsertMissing(_ context.Context, aliases []models.ItemAlias) (int64, error) {
+	var added int64
+	for _, alias := range aliases {
+		if r.find(alias.Alias) == nil {
+			alias.ID = uint(len(r.aliases) + 1)
+			r.aliases = append(r.aliases, alias)
+			added++
+		}
+	}
+	return added, nil
+}
+
+func (r *fakeItemAliasRepo) Create(_ context.Context, alias *models.ItemAlias, maxPending int) error {
+	if alias.SubmittedBy != nil {
+		pending := 0
+		for _, a := range r.aliases {
+			if a.SubmittedBy != nil && *a.SubmittedBy == *alias.SubmittedBy && a.Status == models.ItemAliasStatusPending {
+				pending++
+			}
+		}
+		if pending >= maxPending {
+			return models.ErrItemAliasLimit
+		}
+	}
+	if r.find(alias.Alias) != nil {
+		return models.ErrItemAliasTaken
+	}
+	alias.ID = uint(len(r.aliases) + 1)
+	r.aliases = append(r.aliases, *alias)
+	return nil
+}
+
+func (r *fakeItemAliasRepo) GetByID(_ context.Context, id uint) (*models.ItemAlias, error) {
+	for i := range r.aliases {
+		if r.aliases[i].ID == id {
+			alias := r.aliases[i]
+			return &alias, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeItemAliasRepo) List(_ context.Context, status string, limit int) ([]models.ItemAlias, error) {
+	var found []models.ItemAlias
+	for _, alias := range r.aliases {
+		if alias.Status == status && len(found) < limit {
+			found = append(found, alias)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeItemAliasRepo) ListForItem(_ context.Context, itemID int) ([]models.ItemAlias, error) {
+	var found []models.ItemAlias
+	for _, alias := range r.aliases {
+		if alias.ItemID == itemID && alias.Status == models.ItemAliasStatusApproved {
+			found = append(found, alias)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeItemAliasRepo) ListApproved(_ context.Context) ([]models.ItemAlias, error) {
+	var found []models.ItemAlias
+	for _, alias := range r.aliases {
+		if alias.Status == models.ItemAliasStatusApproved {
+			found = append(found, alias)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeItemAliasRepo) FindApproved(_ context.Context, names []string) ([]models.ItemAlias, error) {
+	var found []models.ItemAlias
+	for _, name := range names {
+		if alias := r.find(name); alias != nil && alias.Status == models.ItemAliasStatusApproved {
+			found = append(found, *alias)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeItemAliasRepo) SetStatus(ctx context.Context, id uint, status string) (*models.ItemAlias, error) {
+	for i := range r.aliases {
+		if r.aliases[i].ID == id {
+			r.aliases[i].Status = status
+			return r.GetByID(ctx, id)
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeItemAliasRepo) Delete(_ context.Context, id uint) (bool, error) {
+	for i := range r.aliases {
+		if r.aliases[i].ID == id {
+			r.aliases = append(r.aliases[:i], r.aliases[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeItemAliasRepo) find(alias string) *models.ItemAlias {
+	for i := range r.aliases {
+		if r.aliases[i].Alias == alias {
+			return &r.aliases[i]
+		}
+	}
+	return nil
+}
+
+func TestAliasesSeed(t *testing.T) {
+	seed, err := aliases.Seed()
+	require.NoError(t, err)
+	require.NotEmpty(t, seed)
+
+	byAlias := make(map[string]int, len(seed))
+	for _, alias := range seed {
+		assert.Equal(t, models.ItemAliasStatusApproved, alias.Status)
+		assert.Equal(t, models.ItemAliasSourceSeed, alias.Source)
+		normalized, err := aliases.Normalize(alias.Alias)
+		require.NoError(t, err)
+		assert.Equal(t, normalized, alias.Alias)
+		byAlias[alias.Alias] = alias.ItemID
+	}
+	assert.Equal(t, 11804, byAlias["bgs"])
+	assert.Equal(t, 20997, byAlias["tbow"])
+	assert.Equal(t, 6685, byAlias["sara brew"])
+	assert.Equal(t, 2434, byAlias["ppot"])
+}
+
+func TestAliasesNormalizeAndSlug(t *testing.T) {
+	alias, err := aliases.Normalize("  Sara   BREW ")
+	require.NoError(t, err)
+	assert.Equal(t, "sara brew", alias)
+
+	for _, bad := range []string{"", "   ", "-bgs", "bgs!", strings.Repeat("a", models.MaxItemAliasLength+1)} {
+		_, err := aliases.Normalize(bad)
+		assert.ErrorIs(t, err, models.ErrInvalidItemAlias, bad)
+	}
+
+	assert.Equal(t, "saradomin-brew-4", aliases.Slug("Saradomin brew(4)"))
+	assert.Equal(t, "zulrahs-scales", aliases.Slug("Zulrah's scales"))
+	assert.Equal(t, "3rd-age-platebody", aliases.Slug(" 3rd age platebody "))
+}
+
+func newItemAliasTestService(t *testing.T) (services.ItemAliasService, *fakeItemAliasRepo) {
+	t.Helper()
+	itemRepo := &fakeItemRepo{itemsByID: map[int]*models.Item{
+		6685:  {ItemID: 6685, Name: "Saradomin brew(4)"},
+		11804: {ItemID: 11804, Name: "Bandos godsword"},
+		12934: {ItemID: 12934, Name: "Zulrah's scales"},
+		4151:  {ItemID: 4151, Name: "Abyssal whip"},
+	}}
+	aliasRepo := &fakeItemAliasRepo{}
+	seed := []models.ItemAlias{
+		{Alias: "bgs", ItemID: 11804, Status: models.ItemAliasStatusApproved, Source: models.ItemAliasSourceSeed},
+		{Alias: "sara brew", ItemID: 6685, Status: models.ItemAliasStatusApproved, Source: models.ItemAliasSourceSeed},
+		{Alias: "tbow", ItemID: 20997, Status: models.ItemAliasStatusApproved, Source: models.ItemAliasSourceSeed},
+	}
+	svc := services.NewItemAliasService(seed, aliasRepo, itemRepo, zap.NewNop().Sugar())
+	added, err := svc.SeedAliases(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(3), added)
+	return svc, aliasRepo
+}
+
+func TestItemAliasService_Resolve(t *testing.T) {
+	svc, _ := newItemAliasTestService(t)
+
+	resolutions, err := svc.Resolve(context.Background(), []string{
+		"BGS", "abyssal  WHIP", "Sara brew", "zulrahs-scales", "tbow", "nothing",
+	})
+	require.NoError(t, err)
+	require.Len(t, resolutions, 6)
+
+	expect := []struct {
+		itemID    int
+		matchedBy string
+	}{
+		{11804, models.ItemMatchAlias},
+		{4151, models.ItemMatchName},
+		{6685, models.ItemMatchAlias},
+		{12934, models.ItemMatchSlug},
+		{0, ""}, // the seed points at an item that is not synced
+		{0, ""},
+	}
+	for i, want := range expect {
+		got := resolutions[i]
+		if want.itemID == 0 {
+			assert.Nil(t, got.ItemID, got.Query)
+			assert.Empty(t, got.MatchedBy, got.Query)
+			continue
+		}
+		require.NotNil(t, got.ItemID, got.Query)
+		assert.Equal(t, want.itemID, *got.ItemID, got.Query)
+		assert.Equal(t, want.matchedBy, got.MatchedBy, got.Query)
+	}
+	assert.Equal(t, "BGS", resolutions[0].Query, "the query is echoed as given")
+	assert.Equal(t, "Bandos godsword", resolutions[0].Name)
+}
+
+func TestItemAliasService_Moderation(t *testing.T) {
+	svc, aliasRepo := newItemAliasTestService(t)
+	ctx := context.Background()
+
+	submitted, err := svc.SubmitAlias(ctx, "alice", 4151, "  Whippy ")
+	require.NoError(t, err)
+	require.NotNil(t, submitted)
+	assert.Equal(t, "whippy", submitted.Alias)
+	assert.Equal(t, models.ItemAliasStatusPending, submitted.Status)
+	require.NotNil(t, submitted.SubmittedBy)
+	assert.Equal(t, "alice", *submitted.SubmittedBy)
+
+	// Pending aliases do not resolve.
+	resolutions, err := svc.Resolve(ctx, []string{"whippy"})
+	require.NoError(t, err)
+	assert.Nil(t, resolutions[0].ItemID)
+
+	_, err = svc.SubmitAlias(ctx, "bob", 6685, "whippy")
+	assert.ErrorIs(t, err, models.ErrItemAliasTaken)
+
+	_, err = svc.SubmitAlias(ctx, "bob", 6685, "abyssal whip")
+	assert.ErrorIs(t, err, models.ErrInvalidItemAlias, "an item name cannot be an alias")
+
+	_, err = svc.SubmitAlias(ctx, "bob", 6685, "brew!")
+	assert.ErrorIs(t, err, models.ErrInvalidItemAlias)
+
+	missing, err := svc.SubmitAlias(ctx, "bob", 1, "nope")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	pending, err := svc.ListAliases(ctx, models.ItemAliasStatusPending)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	_, err = svc.ListAliases(ctx, "everything")
+	assert.ErrorIs(t, err, models.ErrInvalidItemAlias)
+	_, err = svc.ReviewAlias(ctx, submitted.ID, models.ItemAliasStatusPending)
+	assert.ErrorIs(t, err, models.ErrInvalidItemAlias)
+
+	reviewed, err := svc.ReviewAlias(ctx, submitted.ID, models.ItemAliasStatusApproved)
+	require.NoError(t, err)
+	assert.Equal(t, models.ItemAliasStatusApproved, reviewed.Status)
+
+	resolutions, err = svc.Resolve(ctx, []string{"whippy"})
+	require.NoError(t, err)
+	require.NotNil(t, resolutions[0].ItemID)
+	assert.Equal(t, 4151, *resolutions[0].ItemID)
+
+	itemAliases, err := svc.ItemAliases(ctx, 4151)
+	require.NoError(t, err)
+	assert.Len(t, itemAliases, 1)
+
+	added, err := svc.AddAlias(ctx, 4151, "abby whip")
+	require.NoError(t, err)
+	assert.Equal(t, models.ItemAliasStatusApproved, added.Status)
+	assert.Equal(t, models.ItemAliasSourceAdmin, added.Source)
+	assert.Nil(t, added.SubmittedBy)
+
+	deleted, err := svc.DeleteAlias(ctx, added.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	// Rejected seed aliases are not reseeded.
+	bgs := aliasRepo.find("bgs")
+	_, err = svc.ReviewAlias(ctx, bgs.ID, models.ItemAliasStatusRejected)
+	require.NoError(t, err)
+	reseeded, err := svc.SeedAliases(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, reseeded)
+	assert.Equal(t, models.ItemAliasStatusRejected, aliasRepo.find("bgs").Status)
+}
+
+func TestItemAliasService_PendingLimit(t *testing.T) {
+	svc, _ := newItemAliasTestService(t)
+	ctx := context.Background()
+
+	for i := 0; i < models.MaxPendingItemAliases; i++ {
+		_, err := svc.SubmitAlias(ctx, "alice", 4151, fmt.Sprintf("whip %d", i))
+		require.NoError(t, err)
+	}
+	_, err := svc.SubmitAlias(ctx, "alice", 4151, "one more whip")
+	assert.ErrorIs(t, err, models.ErrItemAliasLimit)
+
+	_, err = svc.SubmitAlias(ctx, "bob", 4151, "one more whip")
+	assert.NoError(t, err, "the limit is per user")
+}
+
+func TestItemAliasHandler(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	itemID := 11804
+
+	tests := []struct {
+		setup    func(m *MockItemAliasService)
+		name     string
+		method   string
+		path     string
+		body     string
+		expected string
+		status   int
+	}{
+		{
+			name:   "resolve batch",
+			method: "GET",
+			path:   "/items/resolve?name=bgs&name=Dragon%20bones,%20noted",
+			status: 200,
+			setup: func(m *MockItemAliasService) {
+				m.On("Resolve", mock.Anything, []string{"bgs", "Dragon bones, noted"}).Return([]models.ItemResolution{
+					{Query: "bgs", ItemID: &itemID, Name: "Bandos godsword", MatchedBy: models.ItemMatchAlias},
+					{Query: "Dragon bones, noted"},
+				}, nil)
+			},
+		},
+		{name: "resolve without name", method: "GET", path: "/items/resolve", status: 400, expected: "name query parameter is required"},
+		{name: "resolve blank name", method: "GET", path: "/items/resolve?name=bgs&name=%20", status: 400, expected: "name must not be empty"},
+		{
+			name:     "resolve too many",
+			method:   "GET",
+			path:     "/items/resolve?" + strings.Repeat("name=x&", models.MaxResolveNames+1),
+			status:   400,
+			expected: fmt.Sprintf("maximum %d names per request", models.MaxResolveNames),
+		},
+		{
+			name:   "item aliases",
+			method: "GET",
+			path:   "/items/11804/aliases",
+			status: 200,
+			setup: func(m *MockItemAliasService) {
+				m.On("ItemAliases", mock.Anything, 11804).Return([]models.ItemAlias{{Alias: "bgs", ItemID: 11804}}, nil)
+			},
+		},
+		{
+			name:   "item aliases missing",
+			method: "GET",
+			path:   "/items/9/aliases",
+			status: 404,
+			setup: func(m *MockItemAliasService) {
+				m.On("ItemAliases", mock.Anything, 9).Return(nil, nil)
+			},
+			expected: "item not found",
+		},
+		{
+			name:   "submit",
+			method: "POST",
+			path:   "/items/11804/aliases",
+			body:   `{"alias": "bandos gs 2"}`,
+			status: 202,
+			setup: func(m *MockItemAliasService) {
+				m.On("SubmitAlias", mock.Anything, "alice", 11804, "bandos gs 2").
+					Return(&models.ItemAlias{Alias: "bandos gs 2", Status: models.ItemAliasStatusPending}, nil)
+			},
+		},
+		{
+			name:   "submit taken",
+			method: "POST",
+			path:   "/items/11804/aliases",
+			body:   `{"alias": "bgs"}`,
+			status: 409,
+			setup: func(m *MockItemAliasService) {
+				m.On("SubmitAlias", mock.Anything, "alice", 11804, "bgs").Return(nil, models.ErrItemAliasTaken)
+			},
+			expected: "alias already exists",
+		},
+		{
+			name:   "submit invalid",
+			method: "POST",
+			path:   "/items/11804/aliases",
+			body:   `{"alias": "bgs!"}`,
+			status: 400,
+			setup: func(m *MockItemAliasService) {
+				m.On("SubmitAlias", mock.Anything, "alice", 11804, "bgs!").
+					Return(nil, fmt.Errorf("%w: alias must be short", models.ErrInvalidItemAlias))
+			},
+			expected: "alias must be short",
+		},
+		{name: "submit bad body", method: "POST", path: "/items/11804/aliases", body: `{`, status: 400, expected: "invalid request body"},
+		{
+			name:   "admin add",
+			method: "POST",
+			path:   "/admin/items/11804/aliases",
+			body:   `{"alias": "bandos gs 2"}`,
+			status: 201,
+			setup: func(m *MockItemAliasService) {
+				m.On("AddAlias", mock.Anything, 11804, "bandos gs 2").
+					Return(&models.ItemAlias{Alias: "bandos gs 2", Status: models.ItemAliasStatusApproved}, nil)
+			},
+		},
+		{
+			name:   "admin list",
+			method: "GET",
+			path:   "/admin/aliases",
+			status: 200,
+			setup: func(m *MockItemAliasService) {
+				m.On("ListAliases", mock.Anything, models.ItemAliasStatusPending).Return([]models.ItemAlias{}, nil)
+			},
+		},
+		{
+			name:   "admin review",
+			method: "PUT",
+			path:   "/admin/aliases/7",
+			body:   `{"status": "approved"}`,
+			status: 200,
+			setup: func(m *MockItemAliasService) {
+				m.On("ReviewAlias", mock.Anything, uint(7), models.ItemAliasStatusApproved).
+					Return(&models.ItemAlias{ID: 7, Status: models.ItemAliasStatusApproved}, nil)
+			},
+		},
+		{
+			name:   "admin review missing",
+			method: "PUT",
+			path:   "/admin/aliases/8",
+			body:   `{"status": "rejected"}`,
+			status: 404,
+			setup: func(m *MockItemAliasService) {
+				m.On("ReviewAlias", mock.Anything, uint(8), models.ItemAliasStatusRejected).Return(nil, nil)
+			},
+			expected: "item alias not found",
+		},
+		{name: "admin review bad id", method: "PUT", path: "/admin/aliases/x", body: `{}`, status: 400, expected: "invalid alias ID"},
+		{
+			name:   "admin delete",
+			method: "DELETE",
+			path:   "/admin/aliases/7",
+			status: 204,
+			setup: func(m *MockItemAliasService) {
+				m.On("DeleteAlias", mock.Anything, uint(7)).Return(true, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAliasService := new(MockItemAliasService)
+			if tt.setup != nil {
+				tt.setup(mockAliasService)
+			}
+			handler := handlers.NewItemAliasHandler(mockAliasService, logger)
+
+			app := fiber.New()
+			app.Get("/items/resolve", handler.ResolveNames)
+			app.Get("/items/:id/aliases", handler.GetItemAliases)
+			app.Post("/items/:id/aliases", middleware.RequireUserID(), handler.SubmitAlias)
+			app.Get("/admin/aliases", handler.ListAliases)
+			app.Post("/admin/items/:id/aliases", handler.AddAlias)
+			app.Put("/admin/aliases/:id", handler.ReviewAlias)
+			app.Delete("/admin/aliases/:id", handler.DeleteAlias)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-User-ID", "alice")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var result map[string]any
+			_ = json.NewDecoder(resp.Body).Decode(&result)
+			switch {
+			case tt.expected != "":
+				assert.Equal(t, tt.expected, result["error"])
+			case tt.status != 204:
+				assert.NotNil(t, result["data"])
+			}
+			mockAliasService.AssertExpectations(t)
+		})
+	}
+}
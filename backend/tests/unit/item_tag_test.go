@@ -0,0 +1,381 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+	"github.com/guavi/osrs-ge-tracker/internal/tagging"
+)
+
+// fakeItemTagRepo keeps item tags in memory.
+type fakeItemTagRepo struct {
+	tags []models.ItemTag
+}
+
+func (r *fakeItemTagRepo) ReplaceAutoTags(_ context.Context, tags []models.ItemTag) error {
+	kept := make([]models.ItemTag, 0, len(r.tags))
+	held := make(map[[2]any]struct{})
+	for _, t := range r.tags {
+		if t.Source != models.ItemTagSourceAuto {
+			kept = append(kept, t)
+			held[[2]any{t.ItemID, t.Tag}] = struct{}{}
+		}
+	}
+	for _, t := range tags {
+		if _, ok := held[[2]any{t.ItemID, t.Tag}]; !ok {
+			kept = append(kept, t)
+		}
+	}
+	r.tags = kept
+	return nil
+}
+
+func (r *fakeItemTagRepo) ListForItem(_ context.Context, itemID int) ([]models.ItemTag, error) {
+	var tags []models.ItemTag
+	for _, t := range r.tags {
+		if t.ItemID == itemID {
+			tags = append(tags, t)
+		}
+	}
+	return tags, nil
+}
+
+func (r *fakeItemTagRepo) Upsert(_ context.Context, tag *models.ItemTag) error {
+	for i, t := range r.tags {
+		if t.ItemID == tag.ItemID && t.Tag == tag.Tag {
+			r.tags[i] = *tag
+			return nil
+		}
+	}
+	r.tags = append(r.tags, *tag)
+	return nil
+}
+
+func (r *fakeItemTagRepo) Remove(_ context.Context, itemID int, tag string) (bool, error) {
+	for i, t := range r.tags {
+		if t.ItemID == itemID && t.Tag == tag && !t.Removed {
+			r.tags[i].Removed = true
+			r.tags[i].Source = models.ItemTagSourceAdmin
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeItemTagRepo) CountTags(_ context.Context) ([]models.ItemTagCount, error) {
+	return nil, nil
+}
+
+func (r *fakeItemTagRepo) active(itemID int) []string {
+	var tags []string
+	for _, t := range r.tags {
+		if t.ItemID == itemID && !t.Removed {
+			tags = append(tags, t.Tag)
+		}
+	}
+	return tags
+}
+
+// listingItemRepo is a fakeItemRepo whose GetAll lists itemsByID.
+type listingItemRepo struct {
+	*fakeItemRepo
+}
+
+func (r listingItemRepo) GetAll(_ context.Context, _ models.ItemListParams) ([]models.Item, int64, error) {
+	items := make([]models.Item, 0, len(r.itemsByID))
+	for _, item := range r.itemsByID {
+		items = append(items, *item)
+	}
+	return items, int64(len(items)), nil
+}
+
+func TestTaggingClassify(t *testing.T) {
+	classifier, err := tagging.DefaultClassifier()
+	require.NoError(t, err)
+	assert.Contains(t, classifier.Categories(), "herb")
+	assert.True(t, classifier.IsCategory("weapon"))
+	assert.Equal(t, models.ItemTagKindTag, classifier.Kind("barrows"))
+
+	potionExamine := "An unfinished potion."
+	tests := []struct {
+		item     models.Item
+		category string
+		tags     []string
+	}{
+		{item: models.Item{Name: "Nature rune"}, category: "rune", tags: []string{tagging.TagF2P}},
+		{item: models.Item{Name: "Grimy ranarr weed", Members: true}, category: "herb"},
+		{item: models.Item{Name: "Ranarr seed", Members: true}, category: "seed"},
+		{item: models.Item{Name: "Super restore(4)", Members: true}, category: "potion"},
+		{item: models.Item{Name: "Amulet of glory(4)", Members: true}, category: "jewellery", tags: []string{"teleport"}},
+		{item: models.Item{Name: "Abyssal whip", Members: true}, category: "weapon"},
+		{item: models.Item{Name: "Armadyl godsword", Members: true}, category: "weapon", tags: []string{"godsword"}},
+		{item: models.Item{Name: "Dharok's platebody", Members: true}, category: "armour", tags: []string{"barrows"}},
+		{item: models.Item{Name: "Rune platebody"}, category: "armour", tags: []string{"rune-gear"}},
+		{item: models.Item{Name: "Dragon arrow(p++)", Members: true}, category: "ammunition", tags: []string{"dragon", "poisoned"}},
+		{item: models.Item{Name: "Yew logs"}, category: "log"},
+		{item: models.Item{Name: "Shark", Members: true}, category: "food"},
+		{item: models.Item{Name: "Strange brew", Members: true, Examine: &potionExamine}, category: "potion"},
+		{item: models.Item{Name: "Toy kite"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.item.Name, func(t *testing.T) {
+			tt.item.ItemID = 1
+			var category string
+			var tags []string
+			for _, tag := range classifier.Classify(tt.item) {
+				assert.Equal(t, models.ItemTagSourceAuto, tag.Source)
+				if tag.Kind == models.ItemTagKindCategory {
+					assert.Empty(t, category, "at most one category")
+					category = tag.Tag
+					continue
+				}
+				tags = append(tags, tag.Tag)
+			}
+			assert.Equal(t, tt.category, category)
+			for _, want := range tt.tags {
+				assert.Contains(t, tags, want)
+			}
+			if tt.item.Members {
+				assert.Contains(t, tags, tagging.TagMembers)
+			} else {
+				assert.Contains(t, tags, tagging.TagF2P)
+			}
+		})
+	}
+}
+
+func TestTaggingNormalizeTag(t *testing.T) {
+	tag, err := tagging.NormalizeTag("  Rune-Gear ")
+	require.NoError(t, err)
+	assert.Equal(t, "rune-gear", tag)
+
+	for _, bad := range []string{"", "two words", "trailing-", "a--b", "this-tag-is-far-too-long-to-be-stored"} {
+		_, err := tagging.NormalizeTag(bad)
+		assert.ErrorIs(t, err, models.ErrInvalidItemTag, bad)
+	}
+}
+
+func TestItemTagService(t *testing.T) {
+	classifier, err := tagging.DefaultClassifier()
+	require.NoError(t, err)
+	itemRepo := &fakeItemRepo{itemsByID: map[int]*models.Item{
+		4151: {ItemID: 4151, Name: "Abyssal whip", Members: true},
+	}}
+	tagRepo := &fakeItemTagRepo{}
+	svc := services.NewItemTagService(classifier, tagRepo, listingItemRepo{itemRepo}, zap.NewNop().Sugar())
+	ctx := context.Background()
+
+	tag, err := svc.AddTag(ctx, 4151, "Slayer")
+	require.NoError(t, err)
+	assert.Equal(t, "slayer", tag.Tag)
+	assert.Equal(t, models.ItemTagKindTag, tag.Kind)
+	assert.Equal(t, models.ItemTagSourceAdmin, tag.Source)
+
+	category, err := svc.AddTag(ctx, 4151, "armour")
+	require.NoError(t, err)
+	assert.Equal(t, models.ItemTagKindCategory, category.Kind)
+
+	missing, err := svc.AddTag(ctx, 1, "slayer")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	_, err = svc.AddTag(ctx, 4151, "not a tag")
+	require.ErrorIs(t, err, models.ErrInvalidItemTag)
+
+	// An admin removal survives reclassification.
+	removed, err := svc.RemoveTag(ctx, 4151, "armour")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	tagRepo.tags = append(tagRepo.tags, models.ItemTag{ItemID: 4151, Tag: "stale", Kind: models.ItemTagKindTag, Source: models.ItemTagSourceAuto})
+
+	assigned, err := svc.ClassifyItems(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, assigned)
+	assert.ElementsMatch(t, []string{"slayer", "weapon", tagging.TagMembers}, tagRepo.active(4151))
+
+	tags, err := svc.ItemTags(ctx, 4151)
+	require.NoError(t, err)
+	assert.Len(t, tags, 4)
+
+	none, err := svc.ItemTags(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, none)
+
+	removed, err = svc.RemoveTag(ctx, 4151, "armour")
+	require.NoError(t, err)
+	assert.False(t, removed)
+}
+
+func TestItemTagHandler(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+
+	tests := []struct {
+		setup    func(m *MockItemTagService)
+		name     string
+		method   string
+		path     string
+		expected string
+		status   int
+	}{
+		{
+			name:   "list",
+			method: "GET",
+			path:   "/tags",
+			status: 200,
+			setup: func(m *MockItemTagService) {
+				m.On("ListTags", mock.Anything).Return([]models.ItemTagCount{{Tag: "herb", Kind: "category", Items: 28}}, nil)
+				m.On("Categories").Return([]string{"herb"})
+			},
+		},
+		{
+			name:   "item tags",
+			method: "GET",
+			path:   "/items/4151/tags",
+			status: 200,
+			setup: func(m *MockItemTagService) {
+				m.On("ItemTags", mock.Anything, 4151).Return([]models.ItemTag{}, nil)
+			},
+		},
+		{
+			name:   "item tags missing",
+			method: "GET",
+			path:   "/items/9/tags",
+			status: 404,
+			setup: func(m *MockItemTagService) {
+				m.On("ItemTags", mock.Anything, 9).Return(nil, nil)
+			},
+			expected: "item not found",
+		},
+		{name: "item tags bad id", method: "GET", path: "/items/abc/tags", status: 400, expected: "invalid item ID"},
+		{
+			name:   "add",
+			method: "PUT",
+			path:   "/admin/items/4151/tags/slayer",
+			status: 200,
+			setup: func(m *MockItemTagService) {
+				m.On("AddTag", mock.Anything, 4151, "slayer").Return(&models.ItemTag{ItemID: 4151, Tag: "slayer"}, nil)
+			},
+		},
+		{
+			name:   "add invalid",
+			method: "PUT",
+			path:   "/admin/items/4151/tags/a--b",
+			status: 400,
+			setup: func(m *MockItemTagService) {
+				m.On("AddTag", mock.Anything, 4151, "a--b").
+					Return(nil, errors.Join(models.ErrInvalidItemTag))
+			},
+			expected: "invalid item tag",
+		},
+		{
+			name:   "remove",
+			method: "DELETE",
+			path:   "/admin/items/4151/tags/slayer",
+			status: 204,
+			setup: func(m *MockItemTagService) {
+				m.On("RemoveTag", mock.Anything, 4151, "slayer").Return(true, nil)
+			},
+		},
+		{
+			name:   "remove missing",
+			method: "DELETE",
+			path:   "/admin/items/4151/tags/slayer",
+			status: 404,
+			setup: func(m *MockItemTagService) {
+				m.On("RemoveTag", mock.Anything, 4151, "slayer").Return(false, nil)
+			},
+			expected: "item tag not found",
+		},
+		{
+			name:   "classify",
+			method: "POST",
+			path:   "/admin/tags/classify",
+			status: 200,
+			setup: func(m *MockItemTagService) {
+				m.On("ClassifyItems", mock.Anything).Return(12, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTagService := new(MockItemTagService)
+			if tt.setup != nil {
+				tt.setup(mockTagService)
+			}
+			handler := handlers.NewItemTagHandler(mockTagService, logger)
+
+			app := fiber.New()
+			app.Get("/tags", handler.ListTags)
+			app.Get("/items/:id/tags", handler.GetItemTags)
+			app.Put("/admin/items/:id/tags/:tag", handler.AddItemTag)
+			app.Delete("/admin/items/:id/tags/:tag", handler.RemoveItemTag)
+			app.Post("/admin/tags/classify", handler.ClassifyItems)
+
+			resp, err := app.Test(httptest.NewRequest(tt.method, tt.path, http.NoBody))
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var result map[string]any
+			_ = json.NewDecoder(resp.Body).Decode(&result)
+			switch {
+			case tt.expected != "":
+				assert.Equal(t, tt.expected, result["error"])
+			case tt.status != 204:
+				assert.NotNil(t, result["data"])
+			}
+			mockTagService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestItemHandler_TagFilters(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	mockItemService := new(MockItemService)
+	handler := handlers.NewItemHandler(mockItemService, new(MockPriceService), logger)
+
+	mockItemService.On("ListItems", mock.Anything, mock.MatchedBy(func(p models.ItemListParams) bool {
+		return p.Category == "herb" && assert.ObjectsAreEqual([]string{"members", "slayer"}, p.Tags)
+	})).Return([]models.Item{}, int64(0), nil)
+	mockItemService.On("SearchItems", mock.Anything, mock.MatchedBy(func(p models.ItemSearchParams) bool {
+		return p.Category == "weapon" && p.Tags == nil
+	})).Return([]models.Item{}, nil)
+
+	app := fiber.New()
+	app.Get("/items", handler.ListItems)
+	app.Get("/items/search", handler.SearchItems)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/items?category=Herb&tag=members,slayer&tag=members", http.NoBody))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/items/search?q=whip&category=weapon", http.NoBody))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/items?tag=a,b,c,d,e,f", http.NoBody))
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/items/search?q=whip&tag=two%20words", http.NoBody))
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+	var result map[string]any
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(t, "invalid tag: two words", result["error"])
+
+	mockItemService.AssertExpectations(t)
+}
@@ -2,7 +2,9 @@ package unit
 
 import (
 	"context"
+	"io"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 
@@ -122,6 +124,27 @@ func (m *MockPriceService) GetPriceHistory(ctx context.Context, params models.Pr
 	return args.Get(0).(*models.PriceHistoryResponse), args.Error(1)
 }
 
+func (m *MockPriceService) ComparePrices(ctx context.Context, params models.PriceCompareParams) (*models.PriceCompareResponse, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PriceCompareResponse), args.Error(1)
+}
+
+func (m *MockPriceService) GetPricesAt(ctx context.Context, itemIDs []int, ts time.Time) ([]models.PriceAt, error) {
+	args := m.Called(ctx, itemIDs, ts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PriceAt), args.Error(1)
+}
+
+func (m *MockPriceService) SyncTimeseries(ctx context.Context, itemID int, timestep string) error {
+	args := m.Called(ctx, itemID, timestep)
+	return args.Error(0)
+}
+
 func (m *MockPriceService) UpdateCurrentPrice(ctx context.Context, price *models.CurrentPrice) error {
 	args := m.Called(ctx, price)
 	return args.Error(0)
@@ -170,14 +193,780 @@ func (m *MockWatchlistService) GetShare(ctx context.Context, token string) (*mod
 	return args.Get(0).(*models.WatchlistShareDetailResponse), args.Error(1)
 }
 
+func (m *MockWatchlistService) GetShareItemIDs(ctx context.Context, token string) ([]int, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
 func (m *MockWatchlistService) CleanupExpiredShares(ctx context.Context) (int64, error) {
 	args := m.Called(ctx)
 	return args.Get(0).(int64), args.Error(1)
 }
 
+// MockAnalyticsService is a mock implementation of AnalyticsService.
+type MockAnalyticsService struct {
+	mock.Mock
+}
+
+func (m *MockAnalyticsService) GetSeasonality(ctx context.Context, params models.SeasonalityParams) (*models.SeasonalityProfile, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SeasonalityProfile), args.Error(1)
+}
+
+func (m *MockAnalyticsService) RefreshSeasonality(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAnalyticsService) GetForecast(ctx context.Context, params models.ForecastParams) (*models.ForecastResponse, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ForecastResponse), args.Error(1)
+}
+
+func (m *MockAnalyticsService) GetCorrelation(ctx context.Context, params models.CorrelationParams) (*models.CorrelationResponse, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CorrelationResponse), args.Error(1)
+}
+
+// MockBacktestService is a mock implementation of BacktestService.
+type MockBacktestService struct {
+	mock.Mock
+}
+
+func (m *MockBacktestService) Submit(ctx context.Context, strategy models.BacktestStrategy) (*models.Backtest, error) {
+	args := m.Called(ctx, strategy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Backtest), args.Error(1)
+}
+
+func (m *MockBacktestService) Get(ctx context.Context, id string) (*models.Backtest, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Backtest), args.Error(1)
+}
+
+func (m *MockBacktestService) List(ctx context.Context, limit int) ([]models.Backtest, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Backtest), args.Error(1)
+}
+
+func (m *MockBacktestService) FailInterrupted(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockBacktestService) Shutdown(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+// MockPaperTradingService is a mock implementation of PaperTradingService.
+type MockPaperTradingService struct {
+	mock.Mock
+}
+
+func (m *MockPaperTradingService) CreateAccount(ctx context.Context, userID, name string, startingBalance int64) (*models.PaperAccount, error) {
+	args := m.Called(ctx, userID, name, startingBalance)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PaperAccount), args.Error(1)
+}
+
+func (m *MockPaperTradingService) GetAccount(ctx context.Context, id string) (*models.PaperAccountSummary, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PaperAccountSummary), args.Error(1)
+}
+
+func (m *MockPaperTradingService) PlaceOffer(ctx context.Context, accountID string, req models.PaperOfferRequest) (*models.PaperOffer, error) {
+	args := m.Called(ctx, accountID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PaperOffer), args.Error(1)
+}
+
+func (m *MockPaperTradingService) CancelOffer(ctx context.Context, accountID string, offerID int64) (*models.PaperOffer, error) {
+	args := m.Called(ctx, accountID, offerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PaperOffer), args.Error(1)
+}
+
+func (m *MockPaperTradingService) ListOffers(
+	ctx context.Context,
+	accountID string,
+	status models.PaperOfferStatus,
+	limit int,
+) ([]models.PaperOffer, error) {
+	args := m.Called(ctx, accountID, status, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PaperOffer), args.Error(1)
+}
+
+func (m *MockPaperTradingService) ListPositions(ctx context.Context, accountID string) ([]models.PaperPositionView, error) {
+	args := m.Called(ctx, accountID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PaperPositionView), args.Error(1)
+}
+
+func (m *MockPaperTradingService) ListFills(ctx context.Context, accountID string, limit int) ([]models.PaperFill, error) {
+	args := m.Called(ctx, accountID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PaperFill), args.Error(1)
+}
+
+func (m *MockPaperTradingService) ProcessPriceUpdates(ctx context.Context, updates []models.BulkPriceUpdate) ([]models.PaperFill, error) {
+	args := m.Called(ctx, updates)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PaperFill), args.Error(1)
+}
+
+// MockPortfolioService is a mock implementation of PortfolioService.
+type MockPortfolioService struct {
+	mock.Mock
+}
+
+func (m *MockPortfolioService) AddEntry(ctx context.Context, userID string, entry models.TradeJournalEntry) (*models.TradeJournalEntry, error) {
+	args := m.Called(ctx, userID, entry)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TradeJournalEntry), args.Error(1)
+}
+
+func (m *MockPortfolioService) ListEntries(
+	ctx context.Context,
+	userID string,
+	params models.JournalListParams,
+) ([]models.TradeJournalEntry, int64, error) {
+	args := m.Called(ctx, userID, params)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]models.TradeJournalEntry), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockPortfolioService) DeleteEntry(ctx context.Context, userID string, id int64) (bool, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPortfolioService) ImportCSV(ctx context.Context, userID string, r io.Reader) (int, error) {
+	args := m.Called(ctx, userID, r)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPortfolioService) ExportCSV(ctx context.Context, userID string, w io.Writer) error {
+	args := m.Called(ctx, userID, w)
+	return args.Error(0)
+}
+
+func (m *MockPortfolioService) GetSummary(ctx context.Context, userID string) (*models.PortfolioSummary, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PortfolioSummary), args.Error(1)
+}
+
+// MockBuyLimitService is a mock implementation of BuyLimitService.
+type MockBuyLimitService struct {
+	mock.Mock
+}
+
+func (m *MockBuyLimitService) RecordPurchase(
+	ctx context.Context,
+	userID string,
+	req models.BuyLimitPurchaseRequest,
+) (*models.BuyLimitStatus, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BuyLimitStatus), args.Error(1)
+}
+
+func (m *MockBuyLimitService) DeletePurchase(ctx context.Context, userID string, id int64) (bool, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockBuyLimitService) GetStatus(ctx context.Context, userID string, itemID int) (*models.BuyLimitStatus, error) {
+	args := m.Called(ctx, userID, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BuyLimitStatus), args.Error(1)
+}
+
+func (m *MockBuyLimitService) ListActive(ctx context.Context, userID string) ([]models.BuyLimitStatus, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BuyLimitStatus), args.Error(1)
+}
+
+func (m *MockBuyLimitService) ListAvailable(ctx context.Context, userID string, itemIDs []int) ([]models.BuyLimitStatus, error) {
+	args := m.Called(ctx, userID, itemIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BuyLimitStatus), args.Error(1)
+}
+
+func (m *MockBuyLimitService) ProcessResets(ctx context.Context) ([]models.BuyLimitReset, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BuyLimitReset), args.Error(1)
+}
+
+type MockValuationService struct {
+	mock.Mock
+}
+
+func (m *MockValuationService) ValueBank(ctx context.Context, entries []models.BankEntry) (*models.BankValuation, error) {
+	args := m.Called(ctx, entries)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankValuation), args.Error(1)
+}
+
+type MockBankSnapshotService struct {
+	mock.Mock
+}
+
+func (m *MockBankSnapshotService) Create(
+	ctx context.Context,
+	userID, name string,
+	entries []models.BankEntry,
+) (*models.BankSnapshotDetail, error) {
+	args := m.Called(ctx, userID, name, entries)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankSnapshotDetail), args.Error(1)
+}
+
+func (m *MockBankSnapshotService) List(ctx context.Context, userID string) ([]models.BankSnapshot, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BankSnapshot), args.Error(1)
+}
+
+func (m *MockBankSnapshotService) Get(ctx context.Context, userID string, id int64) (*models.BankSnapshotDetail, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankSnapshotDetail), args.Error(1)
+}
+
+func (m *MockBankSnapshotService) Delete(ctx context.Context, userID string, id int64) (bool, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockBankSnapshotService) History(
+	ctx context.Context,
+	userID string,
+	id int64,
+	params models.NetWorthHistoryParams,
+) (*models.NetWorthHistory, error) {
+	args := m.Called(ctx, userID, id, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.NetWorthHistory), args.Error(1)
+}
+
+func (m *MockBankSnapshotService) RecordDailyValues(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+type MockArbitrageService struct {
+	mock.Mock
+}
+
+func (m *MockArbitrageService) ListSets(ctx context.Context) ([]models.ItemSet, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ItemSet), args.Error(1)
+}
+
+func (m *MockArbitrageService) SaveSet(ctx context.Context, set models.ItemSet) (*models.ItemSet, error) {
+	args := m.Called(ctx, set)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ItemSet), args.Error(1)
+}
+
+func (m *MockArbitrageService) DeleteSet(ctx context.Context, setItemID int) (bool, error) {
+	args := m.Called(ctx, setItemID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockArbitrageService) SetArbitrage(ctx context.Context, mode models.PricingMode) (*models.SetArbitrageReport, error) {
+	args := m.Called(ctx, mode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SetArbitrageReport), args.Error(1)
+}
+
+func (m *MockArbitrageService) RefreshSetArbitrage(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockArbitrageService) ListDoseGroups(ctx context.Context) ([]models.DoseGroup, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DoseGroup), args.Error(1)
+}
+
+func (m *MockArbitrageService) DoseArbitrage(ctx context.Context, mode models.PricingMode) (*models.DoseArbitrageReport, error) {
+	args := m.Called(ctx, mode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DoseArbitrageReport), args.Error(1)
+}
+
+func (m *MockArbitrageService) RefreshDoseArbitrage(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+type MockRecipeService struct {
+	mock.Mock
+}
+
+func (m *MockRecipeService) ListRecipes(ctx context.Context) ([]models.Recipe, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Recipe), args.Error(1)
+}
+
+func (m *MockRecipeService) SaveRecipe(ctx context.Context, recipe models.Recipe) (*models.Recipe, error) {
+	args := m.Called(ctx, recipe)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Recipe), args.Error(1)
+}
+
+func (m *MockRecipeService) DeleteRecipe(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRecipeService) RecipeProfits(ctx context.Context, mode models.PricingMode) (*models.RecipeReport, error) {
+	args := m.Called(ctx, mode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeReport), args.Error(1)
+}
+
+type MockActivityService struct {
+	mock.Mock
+}
+
+func (m *MockActivityService) ListActivities(ctx context.Context) []models.Activity {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Activity)
+}
+
+func (m *MockActivityService) ActivityValues(ctx context.Context, mode models.PricingMode) ([]models.ActivityValue, error) {
+	args := m.Called(ctx, mode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ActivityValue), args.Error(1)
+}
+
+func (m *MockActivityService) ActivityValue(ctx context.Context, id string, mode models.PricingMode) (*models.ActivityValue, error) {
+	args := m.Called(ctx, id, mode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ActivityValue), args.Error(1)
+}
+
+func (m *MockActivityService) ActivityValueHistory(
+	ctx context.Context,
+	id string,
+	params models.ActivityHistoryParams,
+) (*models.ActivityValueHistory, error) {
+	args := m.Called(ctx, id, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ActivityValueHistory), args.Error(1)
+}
+
+type MockMarketIndexService struct {
+	mock.Mock
+}
+
+func (m *MockMarketIndexService) ListIndices(ctx context.Context, userID string) ([]models.MarketIndexSummary, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MarketIndexSummary), args.Error(1)
+}
+
+func (m *MockMarketIndexService) GetIndex(ctx context.Context, userID, id string) (*models.MarketIndex, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MarketIndex), args.Error(1)
+}
+
+func (m *MockMarketIndexService) Summary(ctx context.Context, index models.MarketIndex) (*models.MarketIndexSummary, error) {
+	args := m.Called(ctx, index)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MarketIndexSummary), args.Error(1)
+}
+
+func (m *MockMarketIndexService) History(
+	ctx context.Context,
+	index models.MarketIndex,
+	from, to time.Time,
+) (*models.MarketIndexHistory, error) {
+	args := m.Called(ctx, index, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MarketIndexHistory), args.Error(1)
+}
+
+func (m *MockMarketIndexService) Constituents(ctx context.Context, index models.MarketIndex) (*models.MarketIndexConstituents, error) {
+	args := m.Called(ctx, index)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MarketIndexConstituents), args.Error(1)
+}
+
+func (m *MockMarketIndexService) CreateCustomIndex(ctx context.Context, userID string, index models.MarketIndex) (*models.MarketIndex, error) {
+	args := m.Called(ctx, userID, index)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MarketIndex), args.Error(1)
+}
+
+func (m *MockMarketIndexService) DeleteCustomIndex(ctx context.Context, userID, id string) (bool, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMarketIndexService) RefreshIndices(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMarketIndexService) BackfillNewIndices(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+type MockItemTagService struct {
+	mock.Mock
+}
+
+func (m *MockItemTagService) Categories() []string {
+	args := m.Called()
+	return args.Get(0).([]string)
+}
+
+func (m *MockItemTagService) ListTags(ctx context.Context) ([]models.ItemTagCount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ItemTagCount), args.Error(1)
+}
+
+func (m *MockItemTagService) ItemTags(ctx context.Context, itemID int) ([]models.ItemTag, error) {
+	args := m.Called(ctx, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ItemTag), args.Error(1)
+}
+
+func (m *MockItemTagService) AddTag(ctx context.Context, itemID int, tag string) (*models.ItemTag, error) {
+	args := m.Called(ctx, itemID, tag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ItemTag), args.Error(1)
+}
+
+func (m *MockItemTagService) RemoveTag(ctx context.Context, itemID int, tag string) (bool, error) {
+	args := m.Called(ctx, itemID, tag)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockItemTagService) ClassifyItems(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+type MockItemAliasService struct {
+	mock.Mock
+}
+
+func (m *MockItemAliasService) SeedAliases(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockItemAliasService) Resolve(ctx context.Context, names []string) ([]models.ItemResolution, error) {
+	args := m.Called(ctx, names)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ItemResolution), args.Error(1)
+}
+
+func (m *MockItemAliasService) ItemAliases(ctx context.Context, itemID int) ([]models.ItemAlias, error) {
+	args := m.Called(ctx, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ItemAlias), args.Error(1)
+}
+
+func (m *MockItemAliasService) SubmitAlias(ctx context.Context, userID string, itemID int, alias string) (*models.ItemAlias, error) {
+	args := m.Called(ctx, userID, itemID, alias)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ItemAlias), args.Error(1)
+}
+
+func (m *MockItemAliasService) AddAlias(ctx context.Context, itemID int, alias string) (*models.ItemAlias, error) {
+	args := m.Called(ctx, itemID, alias)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ItemAlias), args.Error(1)
+}
+
+func (m *MockItemAliasService) ListAliases(ctx context.Context, status string) ([]models.ItemAlias, error) {
+	args := m.Called(ctx, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ItemAlias), args.Error(1)
+}
+
+func (m *MockItemAliasService) ReviewAlias(ctx context.Context, id uint, status string) (*models.ItemAlias, error) {
+	args := m.Called(ctx, id, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ItemAlias), args.Error(1)
+}
+
+func (m *MockItemAliasService) DeleteAlias(ctx context.Context, id uint) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
 func TestMain(m *testing.M) {
 	// This function is intentionally left empty.
 	// Its purpose is to provide a central place for package-level test setup
 	// and to hold the mock definitions that are shared across multiple test files.
 	m.Run()
 }
+
+// MockAutocompleteService is a mock implementation of AutocompleteService
+type MockAutocompleteService struct {
+	mock.Mock
+}
+
+func (m *MockAutocompleteService) Rebuild(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockAutocompleteService) UpdatePrices(updates []models.BulkPriceUpdate) {
+	m.Called(updates)
+}
+
+func (m *MockAutocompleteService) Complete(query string, limit int) []models.AutocompleteItem {
+	args := m.Called(query, limit)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).([]models.AutocompleteItem)
+}
+
+func (m *MockAutocompleteService) Ready() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
+// MockScreenerService is a mock implementation of ScreenerService
+type MockScreenerService struct {
+	mock.Mock
+}
+
+func (m *MockScreenerService) Fields() []models.ScreenerField {
+	args := m.Called()
+	return args.Get(0).([]models.ScreenerField)
+}
+
+func (m *MockScreenerService) Run(ctx context.Context, expression string, limit int) (*models.ScreenerResult, error) {
+	args := m.Called(ctx, expression, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ScreenerResult), args.Error(1)
+}
+
+func (m *MockScreenerService) RefreshSnapshot(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockScreenerService) Snapshot(ctx context.Context) ([]models.ScreenerRow, time.Time, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(time.Time), args.Error(2)
+	}
+	return args.Get(0).([]models.ScreenerRow), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *MockScreenerService) ListScreens(ctx context.Context, userID string) ([]models.Screen, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Screen), args.Error(1)
+}
+
+func (m *MockScreenerService) GetScreen(ctx context.Context, userID string, id int64) (*models.Screen, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Screen), args.Error(1)
+}
+
+func (m *MockScreenerService) CreateScreen(ctx context.Context, userID string, screen models.Screen) (*models.Screen, error) {
+	args := m.Called(ctx, userID, screen)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Screen), args.Error(1)
+}
+
+func (m *MockScreenerService) UpdateScreen(ctx context.Context, userID string, id int64, screen models.Screen) (*models.Screen, error) {
+	args := m.Called(ctx, userID, id, screen)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Screen), args.Error(1)
+}
+
+func (m *MockScreenerService) DeleteScreen(ctx context.Context, userID string, id int64) (bool, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockScreenerService) RunScreen(ctx context.Context, userID string, id int64, limit int) (*models.ScreenerResult, error) {
+	args := m.Called(ctx, userID, id, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ScreenerResult), args.Error(1)
+}
+
+func (m *MockScreenerService) CheckAlerts(ctx context.Context) ([]models.ScreenAlert, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ScreenAlert), args.Error(1)
+}
+
+// MockMarketTableService is a mock implementation of MarketTableService
+type MockMarketTableService struct {
+	mock.Mock
+}
+
+func (m *MockMarketTableService) Refresh(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockMarketTableService) LastUpdated(ctx context.Context) (*time.Time, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockMarketTableService) Query(ctx context.Context, params models.MarketTableParams) ([]models.ItemStats, int64, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]models.ItemStats), args.Get(1).(int64), args.Error(2)
+}
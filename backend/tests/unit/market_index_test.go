@@ -0,0 +1,587 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/marketindex"
+	"github.com/guavi/osrs-ge-tracker/internal/middleware"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// fakeMarketIndexRepo keeps custom indices and index values in memory.
+type fakeMarketIndexRepo struct {
+	custom []models.CustomMarketIndex
+	values map[string][]models.MarketIndexValue
+}
+
+func (r *fakeMarketIndexRepo) CreateCustom(_ context.Context, index *models.CustomMarketIndex, maxPerUser int) (bool, error) {
+	count := 0
+	for _, c := range r.custom {
+		if c.UserID == index.UserID {
+			count++
+		}
+	}
+	if count >= maxPerUser {
+		return false, nil
+	}
+	index.ID = int64(len(r.custom) + 1)
+	r.custom = append(r.custom, *index)
+	return true, nil
+}
+
+func (r *fakeMarketIndexRepo) ListCustom(_ context.Context, userID string) ([]models.CustomMarketIndex, error) {
+	indices := make([]models.CustomMarketIndex, 0)
+	for _, c := range r.custom {
+		if c.UserID == userID {
+			indices = append(indices, c)
+		}
+	}
+	return indices, nil
+}
+
+func (r *fakeMarketIndexRepo) ListAllCustom(_ context.Context) ([]models.CustomMarketIndex, error) {
+	return r.custom, nil
+}
+
+func (r *fakeMarketIndexRepo) GetCustom(_ context.Context, userID string, id int64) (*models.CustomMarketIndex, error) {
+	for _, c := range r.custom {
+		if c.UserID == userID && c.ID == id {
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeMarketIndexRepo) DeleteCustom(_ context.Context, userID string, id int64) (bool, error) {
+	for i, c := range r.custom {
+		if c.UserID == userID && c.ID == id {
+			r.custom = append(r.custom[:i], r.custom[i+1:]...)
+			delete(r.values, models.CustomMarketIndexID(id))
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeMarketIndexRepo) LatestValue(_ context.Context, indexID string) (*models.MarketIndexValue, error) {
+	values := r.values[indexID]
+	if len(values) == 0 {
+		return nil, nil
+	}
+	latest := values[len(values)-1]
+	return &latest, nil
+}
+
+func (r *fakeMarketIndexRepo) UpsertValues(_ context.Context, values []models.MarketIndexValue) error {
+	if r.values == nil {
+		r.values = make(map[string][]models.MarketIndexValue)
+	}
+	for _, v := range values {
+		r.values[v.IndexID] = append(r.values[v.IndexID], v)
+	}
+	for _, stored := range r.values {
+		sort.Slice(stored, func(i, j int) bool { return stored[i].Timestamp.Before(stored[j].Timestamp) })
+	}
+	return nil
+}
+
+func (r *fakeMarketIndexRepo) ListValues(_ context.Context, indexID string, from, to time.Time) ([]models.MarketIndexValue, error) {
+	values := make([]models.MarketIndexValue, 0)
+	for _, v := range r.values[indexID] {
+		if !v.Timestamp.Before(from) && !v.Timestamp.After(to) {
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+func hourlyPoint(ts time.Time, high, low, volume int64) models.PriceTimeseriesPoint {
+	p := models.PriceTimeseriesPoint{Timestamp: ts, HighPriceVolume: volume}
+	if high > 0 {
+		p.AvgHighPrice = int64Ptr(high)
+	}
+	if low > 0 {
+		p.AvgLowPrice = int64Ptr(low)
+	}
+	return p
+}
+
+// marketIndexSeries is three 1h buckets of two items: both trade in the
+// first two, and only item 2 (up 10%) in the third.
+func marketIndexSeries(t0 time.Time) map[int][]models.PriceTimeseriesPoint {
+	return map[int][]models.PriceTimeseriesPoint{
+		1: {
+			hourlyPoint(t0.Add(time.Hour), 110, 110, 10),
+			hourlyPoint(t0, 110, 90, 10),
+		},
+		2: {
+			hourlyPoint(t0, 200, 0, 10),
+			hourlyPoint(t0.Add(time.Hour), 200, 200, 10),
+			hourlyPoint(t0.Add(2*time.Hour), 220, 220, 10),
+		},
+	}
+}
+
+func TestMarketIndexCompute(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	series := marketIndexSeries(t0)
+
+	values := marketindex.Compute("test", models.WeightingVolume, series, nil)
+	require.Len(t, values, 3)
+	assert.Equal(t, t0, values[0].Timestamp)
+	assert.InDelta(t, models.MarketIndexBaseValue, values[0].Value, 1e-9)
+	assert.Equal(t, 2, values[0].Constituents)
+	// Item 1 traded 1,100 GP at +10% and item 2 traded 2,000 GP flat.
+	expected := models.MarketIndexBaseValue * (1_100*1.1 + 2_000) / 3_100
+	assert.InDelta(t, expected, values[1].Value, 1e-9)
+	// Only item 2 traded in the last bucket.
+	assert.InDelta(t, expected*1.1, values[2].Value, 1e-9)
+	assert.Equal(t, 1, values[2].Constituents)
+
+	equal := marketindex.Compute("test", models.WeightingEqual, series, nil)
+	require.Len(t, equal, 3)
+	assert.InDelta(t, 1_050, equal[1].Value, 1e-9)
+	assert.InDelta(t, 1_155, equal[2].Value, 1e-9)
+
+	// Extending from a stored value only adds later buckets.
+	extended := marketindex.Compute("test", models.WeightingEqual, series, &equal[1])
+	require.Len(t, extended, 1)
+	assert.Equal(t, t0.Add(2*time.Hour), extended[0].Timestamp)
+	assert.InDelta(t, 1_155, extended[0].Value, 1e-9)
+
+	assert.Empty(t, marketindex.Compute("test", models.WeightingEqual, series, &equal[2]))
+}
+
+func TestMarketIndexWeights(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	series := marketIndexSeries(t0)
+	series[3] = []models.PriceTimeseriesPoint{hourlyPoint(t0, 0, 0, 0)}
+
+	volume := marketindex.Weights(models.WeightingVolume, series)
+	// Item 1: 1,000 + 1,100 GP; item 2: 2,000 + 2,000 + 2,200 GP.
+	assert.InDelta(t, 2_100.0/8_300, volume[1], 1e-9)
+	assert.InDelta(t, 6_200.0/8_300, volume[2], 1e-9)
+	assert.Zero(t, volume[3])
+
+	equal := marketindex.Weights(models.WeightingEqual, series)
+	assert.InDelta(t, 0.5, equal[1], 1e-9)
+	assert.InDelta(t, 0.5, equal[2], 1e-9)
+}
+
+func TestMarketIndexLoad(t *testing.T) {
+	builtin, err := marketindex.Load("")
+	require.NoError(t, err)
+	require.NotEmpty(t, builtin)
+	for _, index := range builtin {
+		assert.Equal(t, models.MarketIndexSourceBuiltin, index.Source)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "indices.json")
+	require.NoError(t, os.WriteFile(path,
+		[]byte(`[{"id": "barrows", "name": " Barrows ", "itemIds": [4708, 4710]}]`), 0o600))
+	list, err := marketindex.Load(path)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "Barrows", list[0].Name)
+	assert.Equal(t, models.WeightingVolume, list[0].Weighting)
+
+	tests := []struct {
+		name     string
+		doc      string
+		expected string
+	}{
+		{name: "bad id", doc: `[{"id": "Bad ID", "name": "x", "itemIds": [1, 2]}]`, expected: "id must be"},
+		{name: "reserved id", doc: `[{"id": "custom-1", "name": "x", "itemIds": [1, 2]}]`, expected: "are reserved"},
+		{name: "duplicate", doc: `[{"id": "a", "name": "x", "itemIds": [1, 2]}, {"id": "a", "name": "y", "itemIds": [1, 2]}]`, expected: "defined twice"},
+		{name: "one item", doc: `[{"id": "a", "name": "x", "itemIds": [1]}]`, expected: "an index must have 2-100 items"},
+		{name: "repeated item", doc: `[{"id": "a", "name": "x", "itemIds": [1, 1]}]`, expected: "item 1 is listed twice"},
+		{name: "bad weighting", doc: `[{"id": "a", "name": "x", "weighting": "price", "itemIds": [1, 2]}]`, expected: "weighting must be volume or equal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, os.WriteFile(path, []byte(tt.doc), 0o600))
+			_, err := marketindex.Load(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func newMarketIndexTestService(
+	repo *fakeMarketIndexRepo,
+	priceRepo *fakePriceRepo,
+	priceService services.PriceService,
+) services.MarketIndexService {
+	builtins := []models.MarketIndex{{
+		ID:        "test",
+		Name:      "Test",
+		Weighting: models.WeightingEqual,
+		Source:    models.MarketIndexSourceBuiltin,
+		ItemIDs:   []int{1, 2},
+	}}
+	itemRepo := &fakeItemRepo{itemsByID: map[int]*models.Item{
+		1: {ItemID: 1, Name: "One"},
+		2: {ItemID: 2, Name: "Two"},
+	}}
+	return services.NewMarketIndexService(builtins, repo, itemRepo, priceRepo, priceService, zap.NewNop().Sugar())
+}
+
+func TestMarketIndexService_Refresh(t *testing.T) {
+	t0 := time.Now().UTC().Truncate(time.Hour).Add(-3 * time.Hour)
+	repo := &fakeMarketIndexRepo{}
+	priceService := new(MockPriceService)
+	priceService.On("SyncTimeseries", mock.Anything, 1, "1h").Return(nil)
+	priceService.On("SyncTimeseries", mock.Anything, 2, "1h").Return(errors.New("wiki down"))
+	svc := newMarketIndexTestService(repo, &fakePriceRepo{timeseriesPoints: marketIndexSeries(t0)}, priceService)
+	ctx := context.Background()
+
+	stored, err := svc.RefreshIndices(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stored)
+	priceService.AssertExpectations(t)
+
+	// Buckets already computed are not stored again.
+	stored, err = svc.RefreshIndices(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, stored)
+
+	summaries, err := svc.ListIndices(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	summary := summaries[0]
+	assert.Equal(t, t0.Add(2*time.Hour), *summary.Timestamp)
+	assert.InDelta(t, 1_155, *summary.Value, 1e-9)
+	assert.InDelta(t, 10, *summary.Change1h, 1e-9)
+	assert.Nil(t, summary.Change24h)
+
+	index, err := svc.GetIndex(ctx, "", "test")
+	require.NoError(t, err)
+	history, err := svc.History(ctx, *index, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, history.Points, 3)
+
+	_, err = svc.History(ctx, *index, t0, t0.Add(-time.Hour))
+	require.ErrorIs(t, err, models.ErrInvalidMarketIndex)
+	_, err = svc.History(ctx, *index, t0.AddDate(-2, 0, 0), t0)
+	require.ErrorIs(t, err, models.ErrInvalidMarketIndex)
+
+	missing, err := svc.GetIndex(ctx, "", "nope")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestMarketIndexService_Constituents(t *testing.T) {
+	t0 := time.Now().UTC().Truncate(time.Hour).Add(-3 * time.Hour)
+	priceRepo := &fakePriceRepo{timeseriesPoints: marketIndexSeries(t0)}
+	svc := newMarketIndexTestService(&fakeMarketIndexRepo{}, priceRepo, nil)
+
+	index := models.MarketIndex{ID: "test", Weighting: models.WeightingVolume, ItemIDs: []int{1, 2}}
+	result, err := svc.Constituents(context.Background(), index)
+	require.NoError(t, err)
+	require.Len(t, result.Constituents, 2)
+	assert.Equal(t, 1, priceRepo.timeseriesForItemsCalls, "every constituent's buckets come from one query")
+
+	heaviest := result.Constituents[0]
+	assert.Equal(t, 2, heaviest.ItemID)
+	assert.Equal(t, "Two", heaviest.Name)
+	assert.InDelta(t, 6_200.0/8_300, heaviest.Weight, 1e-9)
+	assert.Equal(t, int64(220), *heaviest.Price)
+	assert.InDelta(t, 10, *heaviest.Change24h, 1e-9)
+	assert.Equal(t, int64(30), heaviest.Volume24h)
+}
+
+func TestMarketIndexService_CustomIndices(t *testing.T) {
+	t0 := time.Now().UTC().Truncate(time.Hour).Add(-3 * time.Hour)
+	repo := &fakeMarketIndexRepo{}
+	// No SyncTimeseries expectations: backfilling an index only reads stored buckets.
+	priceService := new(MockPriceService)
+	svc := newMarketIndexTestService(repo, &fakePriceRepo{timeseriesPoints: marketIndexSeries(t0)}, priceService)
+	ctx := context.Background()
+
+	_, err := svc.CreateCustomIndex(ctx, "alice", models.MarketIndex{Name: "Mine", ItemIDs: []int{1, 3}})
+	require.ErrorIs(t, err, models.ErrInvalidMarketIndex)
+	assert.Contains(t, err.Error(), "unknown item IDs: 3")
+
+	created, err := svc.CreateCustomIndex(ctx, "alice", models.MarketIndex{Name: " Mine ", ItemIDs: []int{2, 1}})
+	require.NoError(t, err)
+	assert.Equal(t, "custom-1", created.ID)
+	assert.Equal(t, "Mine", created.Name)
+	assert.Equal(t, models.WeightingVolume, created.Weighting)
+	assert.Equal(t, models.MarketIndexSourceCustom, created.Source)
+	// The history is computed by the backfill job, not the request.
+	assert.Empty(t, repo.values["custom-1"])
+	stored, err := svc.BackfillNewIndices(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stored)
+	assert.Len(t, repo.values["custom-1"], 3)
+	stored, err = svc.BackfillNewIndices(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, stored)
+
+	index, err := svc.GetIndex(ctx, "alice", "custom-1")
+	require.NoError(t, err)
+	require.NotNil(t, index)
+	assert.Equal(t, []int{2, 1}, index.ItemIDs)
+
+	other, err := svc.GetIndex(ctx, "bob", "1")
+	require.NoError(t, err)
+	assert.Nil(t, other)
+
+	summaries, err := svc.ListIndices(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.NotNil(t, summaries[0].Value)
+
+	for i := 1; i < models.MaxCustomMarketIndices; i++ {
+		_, err = svc.CreateCustomIndex(ctx, "alice", models.MarketIndex{Name: "More", ItemIDs: []int{1, 2}})
+		require.NoError(t, err)
+	}
+	_, err = svc.CreateCustomIndex(ctx, "alice", models.MarketIndex{Name: "Too many", ItemIDs: []int{1, 2}})
+	require.ErrorIs(t, err, models.ErrInvalidMarketIndex)
+
+	deleted, err := svc.DeleteCustomIndex(ctx, "bob", "custom-1")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+	deleted, err = svc.DeleteCustomIndex(ctx, "alice", "custom-1")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	assert.Empty(t, repo.values["custom-1"])
+
+	// An index deleted before its backfill is not computed.
+	deleted, err = svc.DeleteCustomIndex(ctx, "alice", "custom-2")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	_, err = svc.BackfillNewIndices(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, repo.values["custom-2"])
+	assert.Len(t, repo.values["custom-3"], 3)
+	priceService.AssertExpectations(t)
+}
+
+func TestMarketIndexService_RefreshSyncsLimitedCustomItems(t *testing.T) {
+	repo := &fakeMarketIndexRepo{}
+	// Every custom index holds builtin item 1, item 1000 and one item of its own.
+	for i := 0; i <= models.MaxSyncedCustomIndexItems; i++ {
+		itemIDs, err := json.Marshal([]int{1, 1000, 2000 + i})
+		require.NoError(t, err)
+		repo.custom = append(repo.custom, models.CustomMarketIndex{
+			ID:        int64(i + 1),
+			UserID:    "alice",
+			Name:      "Mine",
+			Weighting: string(models.WeightingEqual),
+			ItemIDs:   itemIDs,
+		})
+	}
+	priceService := new(MockPriceService)
+	priceService.On("SyncTimeseries", mock.Anything, mock.Anything, "1h").Return(nil)
+	svc := newMarketIndexTestService(repo, &fakePriceRepo{}, priceService)
+
+	_, err := svc.RefreshIndices(context.Background())
+	require.NoError(t, err)
+
+	synced := make(map[int]bool)
+	for _, call := range priceService.Calls {
+		synced[call.Arguments.Int(1)] = true
+	}
+	// Builtin items always sync; custom-only items up to the cap, most used first.
+	assert.Len(t, synced, 2+models.MaxSyncedCustomIndexItems)
+	assert.True(t, synced[1])
+	assert.True(t, synced[2])
+	assert.True(t, synced[1000])
+	assert.True(t, synced[2000])
+	assert.False(t, synced[2000+models.MaxSyncedCustomIndexItems])
+}
+
+func TestMarketIndexHandler(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	runes := models.MarketIndex{ID: "runes", Name: "Runes", ItemIDs: []int{554, 555}}
+	custom := models.MarketIndex{ID: "custom-1", Name: "Mine", ItemIDs: []int{554, 555}}
+
+	tests := []struct {
+		setup    func(m *MockMarketIndexService)
+		name     string
+		method   string
+		path     string
+		body     string
+		expected string
+		status   int
+	}{
+		{
+			name:   "list builtin",
+			method: "GET",
+			path:   "/market/index",
+			status: 200,
+			setup: func(m *MockMarketIndexService) {
+				m.On("ListIndices", mock.Anything, "").Return([]models.MarketIndexSummary{{MarketIndex: runes}}, nil)
+			},
+		},
+		{
+			name:   "list custom",
+			method: "GET",
+			path:   "/market/index/custom",
+			status: 200,
+			setup: func(m *MockMarketIndexService) {
+				m.On("ListIndices", mock.Anything, "alice").Return([]models.MarketIndexSummary{}, nil)
+			},
+		},
+		{
+			name:   "get",
+			method: "GET",
+			path:   "/market/index/runes",
+			status: 200,
+			setup: func(m *MockMarketIndexService) {
+				m.On("GetIndex", mock.Anything, "", "runes").Return(&runes, nil)
+				m.On("Summary", mock.Anything, runes).Return(&models.MarketIndexSummary{MarketIndex: runes}, nil)
+			},
+		},
+		{
+			name:   "get missing",
+			method: "GET",
+			path:   "/market/index/nope",
+			status: 404,
+			setup: func(m *MockMarketIndexService) {
+				m.On("GetIndex", mock.Anything, "", "nope").Return(nil, nil)
+			},
+			expected: "market index not found",
+		},
+		{
+			name:   "history",
+			method: "GET",
+			path:   "/market/index/runes/history?from=2026-03-01",
+			status: 200,
+			setup: func(m *MockMarketIndexService) {
+				m.On("GetIndex", mock.Anything, "", "runes").Return(&runes, nil)
+				m.On("History", mock.Anything, runes, from, time.Time{}).
+					Return(&models.MarketIndexHistory{IndexID: "runes", Points: []models.MarketIndexValue{}}, nil)
+			},
+		},
+		{name: "history bad from", method: "GET", path: "/market/index/runes/history?from=soon", status: 400, expected: "invalid from timestamp"},
+		{
+			name:   "history bad range",
+			method: "GET",
+			path:   "/market/index/custom/custom-1/history",
+			status: 400,
+			setup: func(m *MockMarketIndexService) {
+				m.On("GetIndex", mock.Anything, "alice", "custom-1").Return(&custom, nil)
+				m.On("History", mock.Anything, custom, time.Time{}, time.Time{}).
+					Return(nil, fmt.Errorf("%w: from must be before to", models.ErrInvalidMarketIndex))
+			},
+			expected: "from must be before to",
+		},
+		{
+			name:   "constituents",
+			method: "GET",
+			path:   "/market/index/runes/constituents",
+			status: 200,
+			setup: func(m *MockMarketIndexService) {
+				m.On("GetIndex", mock.Anything, "", "runes").Return(&runes, nil)
+				m.On("Constituents", mock.Anything, runes).
+					Return(&models.MarketIndexConstituents{IndexID: "runes", Constituents: []models.MarketIndexConstituent{}}, nil)
+			},
+		},
+		{
+			name:   "create",
+			method: "POST",
+			path:   "/market/index/custom",
+			body:   `{"name": "Mine", "weighting": "equal", "itemIds": [554, 555]}`,
+			status: 201,
+			setup: func(m *MockMarketIndexService) {
+				m.On("CreateCustomIndex", mock.Anything, "alice", models.MarketIndex{
+					Name: "Mine", Weighting: models.WeightingEqual, ItemIDs: []int{554, 555},
+				}).Return(&custom, nil)
+			},
+		},
+		{
+			name:   "create invalid",
+			method: "POST",
+			path:   "/market/index/custom",
+			body:   `{"name": "Mine", "itemIds": [554]}`,
+			status: 400,
+			setup: func(m *MockMarketIndexService) {
+				m.On("CreateCustomIndex", mock.Anything, "alice", mock.Anything).
+					Return(nil, models.ErrInvalidMarketIndex)
+			},
+			expected: "invalid market index",
+		},
+		{name: "create bad body", method: "POST", path: "/market/index/custom", body: `{`, status: 400, expected: "invalid request body"},
+		{
+			name:   "delete",
+			method: "DELETE",
+			path:   "/market/index/custom/custom-1",
+			status: 204,
+			setup: func(m *MockMarketIndexService) {
+				m.On("DeleteCustomIndex", mock.Anything, "alice", "custom-1").Return(true, nil)
+			},
+		},
+		{
+			name:   "delete missing",
+			method: "DELETE",
+			path:   "/market/index/custom/custom-9",
+			status: 404,
+			setup: func(m *MockMarketIndexService) {
+				m.On("DeleteCustomIndex", mock.Anything, "alice", "custom-9").Return(false, nil)
+			},
+			expected: "market index not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMarketIndexService := new(MockMarketIndexService)
+			if tt.setup != nil {
+				tt.setup(mockMarketIndexService)
+			}
+			handler := handlers.NewMarketIndexHandler(mockMarketIndexService, logger)
+
+			app := fiber.New()
+			group := app.Group("/market/index")
+			group.Get("/", handler.ListIndices)
+			customGroup := group.Group("/custom", middleware.RequireUserID())
+			customGroup.Get("/", handler.ListIndices)
+			customGroup.Post("/", handler.CreateCustomIndex)
+			customGroup.Get("/:id/history", handler.GetHistory)
+			customGroup.Delete("/:id", handler.DeleteCustomIndex)
+			group.Get("/:id", handler.GetIndex)
+			group.Get("/:id/history", handler.GetHistory)
+			group.Get("/:id/constituents", handler.GetConstituents)
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			// Only the custom routes read the user; builtin routes ignore it.
+			req.Header.Set(middleware.UserIDHeader, "alice")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var result map[string]any
+			_ = json.NewDecoder(resp.Body).Decode(&result)
+			switch {
+			case tt.expected != "":
+				assert.Equal(t, tt.expected, result["error"])
+			case tt.status < 300 && tt.status != 204:
+				assert.NotNil(t, result["data"])
+			}
+			mockMarketIndexService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// fakeItemStatsRepo records the last refresh written to it.
+type fakeItemStatsRepo struct {
+	asOf             *time.Time
+	stats            []models.ItemStats
+	replaces         int
+	lastUpdatedCalls int
+}
+
+func (r *fakeItemStatsRepo) Replace(_ context.Context, stats []models.ItemStats, asOf time.Time) error {
+	r.stats = stats
+	r.asOf = &asOf
+	r.replaces++
+	return nil
+}
+
+func (r *fakeItemStatsRepo) LastUpdated(_ context.Context) (*time.Time, error) {
+	r.lastUpdatedCalls++
+	return r.asOf, nil
+}
+
+func (r *fakeItemStatsRepo) Query(_ context.Context, params models.MarketTableParams) ([]models.ItemStats, int64, error) {
+	if params.Offset >= len(r.stats) {
+		return nil, int64(len(r.stats)), nil
+	}
+	end := min(params.Offset+params.Limit, len(r.stats))
+	return r.stats[params.Offset:end], int64(len(r.stats)), nil
+}
+
+func TestMarketTableService_Refresh(t *testing.T) {
+	ctx := context.Background()
+	buyLimit := 70
+	itemRepo := listingItemRepo{&fakeItemRepo{itemsByID: map[int]*models.Item{
+		4151: {ItemID: 4151, Name: "Abyssal whip", Members: true, BuyLimit: &buyLimit, IconURL: "Abyssal whip.png"},
+		1333: {ItemID: 1333, Name: "Rune scimitar"},
+	}}}
+	priceRepo := &fakePriceRepo{
+		getAllCurrentPricesResp: []models.CurrentPrice{
+			{ItemID: 4151, HighPrice: i64(1_500_000), LowPrice: i64(1_400_000)},
+		},
+		tradeVolumes: map[int]int64{4151: 9_000},
+	}
+	mockPriceService := new(MockPriceService)
+	mockPriceService.On("GetPricesAt", mock.Anything, mock.Anything, mock.Anything).
+		Return([]models.PriceAt{{ItemID: 4151, HighPrice: i64(1_300_000), LowPrice: i64(1_300_000)}}, nil)
+	screenerService := services.NewScreenerService(&fakeScreenRepo{}, itemRepo, priceRepo, mockPriceService, zap.NewNop().Sugar())
+	repo := &fakeItemStatsRepo{}
+	svc := services.NewMarketTableService(repo, screenerService, zap.NewNop().Sugar())
+
+	updatedAt, err := svc.LastUpdated(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, updatedAt)
+
+	require.NoError(t, svc.Refresh(ctx))
+	require.NotNil(t, repo.asOf)
+	require.Len(t, repo.stats, 2)
+
+	byID := make(map[int]models.ItemStats)
+	for _, s := range repo.stats {
+		byID[s.ItemID] = s
+	}
+	whip := byID[4151]
+	assert.Equal(t, "Abyssal whip", whip.Name)
+	assert.True(t, whip.Members)
+	assert.Contains(t, whip.IconURL, "https://")
+	assert.Equal(t, i64(70), whip.BuyLimit)
+	assert.Equal(t, i64(100_000), whip.Spread)
+	assert.Equal(t, int64(9_000), whip.Volume24h)
+	require.NotNil(t, whip.Change24h)
+	assert.InDelta(t, 11.538, *whip.Change24h, 0.001)
+
+	scimitar := byID[1333]
+	assert.Nil(t, scimitar.HighPrice)
+	assert.Nil(t, scimitar.Spread)
+	assert.Nil(t, scimitar.BuyLimit)
+	assert.Nil(t, scimitar.Change1h)
+
+	// The table is written from the screener's snapshot, never rebuilt on
+	// its own, and only once per snapshot.
+	require.NoError(t, svc.Refresh(ctx))
+	assert.Equal(t, 1, repo.replaces)
+	assert.Equal(t, 1, priceRepo.getAllCurrentPricesCalls)
+	_, asOf, err := screenerService.Snapshot(ctx)
+	require.NoError(t, err)
+	assert.True(t, repo.asOf.Equal(asOf))
+
+	require.NoError(t, screenerService.RefreshSnapshot(ctx))
+	require.NoError(t, svc.Refresh(ctx))
+	assert.Equal(t, 2, repo.replaces)
+	assert.Equal(t, 2, priceRepo.getAllCurrentPricesCalls)
+
+	// The refresh time is served from memory once the table is written.
+	updatedAt, err = svc.LastUpdated(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, updatedAt)
+	assert.True(t, updatedAt.Equal(*repo.asOf))
+	assert.Equal(t, 1, repo.lastUpdatedCalls, "only the call before the first refresh reads the table")
+
+	rows, total, err := svc.Query(ctx, models.MarketTableParams{Limit: 1, Offset: 5})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.NotNil(t, rows, "an empty page encodes as []")
+}
+
+func TestMarketTableService_LastUpdatedAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	stored := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &fakeItemStatsRepo{asOf: &stored}
+	svc := services.NewMarketTableService(repo, new(MockScreenerService), zap.NewNop().Sugar())
+
+	for range 3 {
+		updatedAt, err := svc.LastUpdated(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, updatedAt)
+		assert.True(t, updatedAt.Equal(stored))
+	}
+	assert.Equal(t, 1, repo.lastUpdatedCalls, "a table written before the restart is read once")
+}
+
+func TestMarketTableHandler(t *testing.T) {
+	defaults := models.MarketTableParams{SortBy: "volume_24h", Order: "desc", Page: 1, Limit: 50}
+	members := true
+
+	tests := []struct {
+		params   *models.MarketTableParams
+		name     string
+		query    string
+		expected string
+		status   int
+	}{
+		{name: "defaults", params: &defaults, status: 200},
+		{
+			name:   "text column sorts ascending",
+			query:  "?sort_by=name",
+			params: &models.MarketTableParams{SortBy: "name", Order: "asc", Page: 1, Limit: 50},
+			status: 200,
+		},
+		{
+			name:  "filters and paging",
+			query: "?sort_by=change_24h&order=asc&page=3&limit=20&q=+rune+&members=true&category=Weapons&min_volume_24h=100&max_high_price=1.5e6&min_change_24h=-5",
+			params: &models.MarketTableParams{
+				SortBy: "change_24h", Order: "asc", Page: 3, Limit: 20, Offset: 40,
+				Query: "rune", Members: &members, Category: "weapons",
+				Min: map[string]float64{"volume_24h": 100, "change_24h": -5},
+				Max: map[string]float64{"high_price": 1_500_000},
+			},
+			status: 200,
+		},
+		{name: "unknown sort column", query: "?sort_by=margin", status: 400, expected: "invalid sort_by field"},
+		{name: "bad order", query: "?order=up", status: 400, expected: "order must be 'asc' or 'desc'"},
+		{name: "limit too large", query: "?limit=201", status: 400, expected: "limit must be between 1 and 200"},
+		{name: "bad page", query: "?page=0", status: 400, expected: "page must be greater than 0"},
+		{name: "text filter column", query: "?min_name=a", status: 400, expected: "cannot filter on 'name'"},
+		{name: "unknown filter column", query: "?max_margin=5", status: 400, expected: "cannot filter on 'margin'"},
+		{name: "non-numeric bound", query: "?min_high_price=lots", status: 400, expected: "min_high_price must be a number"},
+		{name: "infinite bound", query: "?max_spread=Inf", status: 400, expected: "max_spread must be a number"},
+		{name: "bad category", query: "?category=%21%21", status: 400, expected: "invalid category"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockMarketTableService)
+			asOf := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+			mockService.On("LastUpdated", mock.Anything).Return(&asOf, nil).Maybe()
+			if tt.params != nil {
+				mockService.On("Query", mock.Anything, *tt.params).
+					Return([]models.ItemStats{{ItemID: 4151, Name: "Abyssal whip"}}, int64(41), nil)
+			}
+
+			app := fiber.New()
+			app.Get("/market/table", handlers.NewMarketTableHandler(mockService, zap.NewNop().Sugar()).GetTable)
+			resp, err := app.Test(httptest.NewRequest("GET", "/market/table"+tt.query, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			if tt.expected != "" {
+				assert.Equal(t, tt.expected, body["error"])
+			} else {
+				meta := body["meta"].(map[string]any)
+				assert.EqualValues(t, 41, meta["total"])
+				assert.EqualValues(t, (41+tt.params.Limit-1)/tt.params.Limit, meta["total_pages"])
+				assert.Equal(t, "2026-10-18T12:00:00Z", meta["asOf"])
+				assert.NotEmpty(t, resp.Header.Get("ETag"))
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMarketTableHandler_ETag(t *testing.T) {
+	mockService := new(MockMarketTableService)
+	asOf := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	mockService.On("LastUpdated", mock.Anything).Return(&asOf, nil).Times(3)
+	mockService.On("Query", mock.Anything, mock.Anything).Return([]models.ItemStats{{ItemID: 4151}}, int64(1), nil).Times(3)
+
+	app := fiber.New()
+	app.Get("/market/table", handlers.NewMarketTableHandler(mockService, zap.NewNop().Sugar()).GetTable)
+	get := func(query, etag string) *http.Response {
+		req := httptest.NewRequest("GET", "/market/table"+query, nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	first := get("?sort_by=name", "")
+	require.Equal(t, 200, first.StatusCode)
+	etag := first.Header.Get("ETag")
+	assert.Regexp(t, `^W/"[0-9a-f]{32}"$`, etag)
+
+	unchanged := get("?sort_by=name", etag)
+	assert.Equal(t, 304, unchanged.StatusCode)
+	body, err := io.ReadAll(unchanged.Body)
+	require.NoError(t, err)
+	assert.Empty(t, body)
+
+	otherPage := get("?sort_by=name&page=2", etag)
+	assert.Equal(t, 200, otherPage.StatusCode, "a different query has a different ETag")
+	assert.NotEqual(t, etag, otherPage.Header.Get("ETag"))
+
+	// The next refresh changes every ETag.
+	refreshed := asOf.Add(time.Minute)
+	mockService.On("LastUpdated", mock.Anything).Return(&refreshed, nil)
+	stale := get("?sort_by=name", etag)
+	assert.Equal(t, 200, stale.StatusCode)
+	assert.NotEqual(t, etag, stale.Header.Get("ETag"))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestMarketTableHandler_Errors(t *testing.T) {
+	mockService := new(MockMarketTableService)
+	mockService.On("LastUpdated", mock.Anything).Return(nil, nil)
+	mockService.On("Query", mock.Anything, mock.Anything).Return(nil, int64(0), errors.New("db down"))
+
+	app := fiber.New()
+	app.Get("/market/table", handlers.NewMarketTableHandler(mockService, zap.NewNop().Sugar()).GetTable)
+	resp, err := app.Test(httptest.NewRequest("GET", "/market/table", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+}
@@ -0,0 +1,522 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/middleware"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+const paperAccountID = "5f0c3a52-7d4e-4b7a-9a55-0c9d2a6e1b01"
+
+// paperFillCall records the arguments of a FillOffer call.
+type paperFillCall struct {
+	at      time.Time
+	offerID int64
+	limit   int
+}
+
+// fakePaperRepo is a PaperTradingRepository that serves canned data and
+// records fill requests.
+type fakePaperRepo struct {
+	account        *models.PaperAccount
+	placeErr       error
+	accountsByUser map[string]int
+	openOffers     []models.PaperOffer
+	positions      []models.PaperPosition
+	fillErrs       map[int64]error
+	fillCalls      []paperFillCall
+	listedItems    []int
+}
+
+func (r *fakePaperRepo) CreateAccount(_ context.Context, account *models.PaperAccount, maxPerUser int) (bool, error) {
+	if r.accountsByUser[account.UserID] >= maxPerUser {
+		return false, nil
+	}
+	if r.accountsByUser == nil {
+		r.accountsByUser = make(map[string]int)
+	}
+	r.accountsByUser[account.UserID]++
+	r.account = account
+	return true, nil
+}
+
+func (r *fakePaperRepo) GetAccount(_ context.Context, id string) (*models.PaperAccount, error) {
+	if r.account == nil || r.account.ID != id {
+		return nil, nil
+	}
+	return r.account, nil
+}
+
+func (r *fakePaperRepo) PlaceOffer(_ context.Context, offer *models.PaperOffer) error {
+	if r.placeErr != nil {
+		return r.placeErr
+	}
+	offer.ID = 1
+	return nil
+}
+
+func (r *fakePaperRepo) CancelOffer(_ context.Context, _ string, _ int64) (*models.PaperOffer, error) {
+	return nil, nil
+}
+
+func (r *fakePaperRepo) ListOffers(_ context.Context, _ string, status models.PaperOfferStatus, _ int) ([]models.PaperOffer, error) {
+	out := make([]models.PaperOffer, 0, len(r.openOffers))
+	for _, o := range r.openOffers {
+		if status == "" || o.Status == status {
+			out = append(out, o)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakePaperRepo) ListOpenOffersForItems(_ context.Context, itemIDs []int) ([]models.PaperOffer, error) {
+	r.listedItems = itemIDs
+	return r.openOffers, nil
+}
+
+func (r *fakePaperRepo) FillOffer(_ context.Context, offerID int64, at time.Time, limit int) (*models.PaperFill, error) {
+	r.fillCalls = append(r.fillCalls, paperFillCall{offerID: offerID, at: at, limit: limit})
+	if err := r.fillErrs[offerID]; err != nil {
+		return nil, err
+	}
+	for _, o := range r.openOffers {
+		if o.ID == offerID {
+			return &models.PaperFill{OfferID: o.ID, ItemID: o.ItemID, Side: o.Side, Quantity: o.Remaining(), Price: o.Price, FilledAt: at}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakePaperRepo) ListPositions(_ context.Context, _ string) ([]models.PaperPosition, error) {
+	return r.positions, nil
+}
+
+func (r *fakePaperRepo) ListFills(_ context.Context, _ string, _ int) ([]models.PaperFill, error) {
+	return []models.PaperFill{}, nil
+}
+
+func TestPaperOffer_CrossedBy(t *testing.T) {
+	placed := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	before, after := placed.Add(-time.Minute), placed.Add(time.Minute)
+	buy := models.PaperOffer{Side: models.PaperOfferBuy, Price: 1000, CreatedAt: placed}
+	sell := models.PaperOffer{Side: models.PaperOfferSell, Price: 1100, CreatedAt: placed}
+
+	at, crossed := buy.CrossedBy(models.BulkPriceUpdate{LowPrice: int64Ptr(1000), LowPriceTime: &after})
+	assert.True(t, crossed, "a sale at the bid fills a buy")
+	assert.Equal(t, after, at)
+
+	_, crossed = buy.CrossedBy(models.BulkPriceUpdate{LowPrice: int64Ptr(1001), LowPriceTime: &after})
+	assert.False(t, crossed, "a sale above the bid does not fill a buy")
+
+	_, crossed = buy.CrossedBy(models.BulkPriceUpdate{LowPrice: int64Ptr(900), LowPriceTime: &before})
+	assert.False(t, crossed, "trades before the offer was placed do not count")
+
+	_, crossed = buy.CrossedBy(models.BulkPriceUpdate{HighPrice: int64Ptr(900), HighPriceTime: &after})
+	assert.False(t, crossed, "buys only look at the instant-sell price")
+
+	_, crossed = sell.CrossedBy(models.BulkPriceUpdate{HighPrice: int64Ptr(1150), HighPriceTime: &after})
+	assert.True(t, crossed)
+
+	_, crossed = sell.CrossedBy(models.BulkPriceUpdate{HighPrice: int64Ptr(1099), HighPriceTime: &after, LowPrice: int64Ptr(1200), LowPriceTime: &after})
+	assert.False(t, crossed, "sells only look at the instant-buy price")
+}
+
+func TestPaperPosition_BuyLimitWindow(t *testing.T) {
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	var p models.PaperPosition
+
+	assert.Equal(t, int64(-1), p.BuyAllowance(0, start), "items without a limit are unlimited")
+	assert.Equal(t, int64(70), p.BuyAllowance(70, start))
+
+	p.RecordBuy(50, 1000, start)
+	assert.Equal(t, int64(20), p.BuyAllowance(70, start.Add(time.Hour)))
+
+	p.RecordBuy(20, 1000, start.Add(2*time.Hour))
+	assert.Equal(t, int64(0), p.BuyAllowance(70, start.Add(3*time.Hour)))
+	assert.Equal(t, int64(70), p.BuyAllowance(70, start.Add(models.BuyLimitWindow)), "the window closes 4 hours after the first buy")
+
+	p.RecordBuy(10, 1300, start.Add(models.BuyLimitWindow))
+	assert.Equal(t, int64(60), p.BuyAllowance(70, start.Add(models.BuyLimitWindow+time.Minute)), "a new window opens")
+	assert.Equal(t, int64(80), p.Quantity)
+	assert.Equal(t, int64(70*1000+10*1300), p.CostBasis)
+}
+
+func TestPaperPosition_RecordSellRealizesAgainstAverageCost(t *testing.T) {
+	p := models.PaperPosition{Quantity: 3, Reserved: 2, CostBasis: 3000}
+
+	profit := p.RecordSell(2, 1500, 60)
+	assert.Equal(t, int64(2*1500-60-2000), profit)
+	assert.Equal(t, int64(1), p.Quantity)
+	assert.Equal(t, int64(0), p.Reserved)
+	assert.Equal(t, int64(1000), p.CostBasis)
+	assert.Equal(t, profit, p.RealizedProfit)
+	assert.Equal(t, int64(60), p.TaxPaid)
+
+	// Cost allocation must not overflow for max-cash stacks of expensive items.
+	big := models.PaperPosition{Quantity: math.MaxInt32, Reserved: math.MaxInt32, CostBasis: math.MaxInt32 * 1_000_000}
+	big.RecordSell(math.MaxInt32/2, 1_000_000, 0)
+	assert.Equal(t, int64(math.MaxInt32-math.MaxInt32/2)*1_000_000, big.CostBasis)
+}
+
+func TestPaperTradingService_ProcessPriceUpdates(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	placed := time.Now().UTC().Add(-time.Hour)
+	traded := placed.Add(30 * time.Minute)
+	buyLimit := 70
+
+	repo := &fakePaperRepo{
+		openOffers: []models.PaperOffer{
+			{ID: 1, ItemID: 4151, Side: models.PaperOfferBuy, Status: models.PaperOfferOpen, Price: 1_500_000, Quantity: 100, CreatedAt: placed},
+			{ID: 2, ItemID: 4151, Side: models.PaperOfferBuy, Status: models.PaperOfferOpen, Price: 1_400_000, Quantity: 5, CreatedAt: placed},
+			{ID: 3, ItemID: 11802, Side: models.PaperOfferSell, Status: models.PaperOfferOpen, Price: 20_000_000, Quantity: 1, CreatedAt: placed},
+			{ID: 4, ItemID: 11802, Side: models.PaperOfferSell, Status: models.PaperOfferOpen, Price: 21_000_000, Quantity: 1, CreatedAt: placed},
+		},
+		fillErrs: map[int64]error{},
+	}
+	itemRepo := &fakeItemRepo{getByItemIDItem: &models.Item{ItemID: 4151, BuyLimit: &buyLimit}}
+	svc := services.NewPaperTradingService(repo, &fakePriceRepo{}, itemRepo, logger)
+
+	fills, err := svc.ProcessPriceUpdates(context.Background(), []models.BulkPriceUpdate{
+		{ItemID: 4151, LowPrice: int64Ptr(1_450_000), LowPriceTime: &traded, HighPrice: int64Ptr(1_550_000), HighPriceTime: &traded},
+		{ItemID: 11802, LowPrice: int64Ptr(19_000_000), LowPriceTime: &traded, HighPrice: int64Ptr(20_500_000), HighPriceTime: &traded},
+	})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []int{4151, 11802}, repo.listedItems)
+	require.Len(t, repo.fillCalls, 2, "only crossed offers are filled")
+	assert.Equal(t, paperFillCall{offerID: 1, at: traded, limit: 70}, repo.fillCalls[0], "buys carry the item's buy limit")
+	assert.Equal(t, paperFillCall{offerID: 3, at: traded, limit: 0}, repo.fillCalls[1])
+	require.Len(t, fills, 2)
+	assert.Equal(t, int64(1), fills[0].OfferID)
+	assert.Equal(t, int64(3), fills[1].OfferID)
+	assert.Equal(t, 1, itemRepo.getByItemIDCalls, "buy limits are looked up once per sync")
+
+	// A failing fill is skipped without dropping the others.
+	repo.fillCalls = nil
+	repo.fillErrs[1] = errors.New("deadlock detected")
+	fills, err = svc.ProcessPriceUpdates(context.Background(), []models.BulkPriceUpdate{
+		{ItemID: 4151, LowPrice: int64Ptr(1_000_000), LowPriceTime: &traded},
+	})
+	require.NoError(t, err)
+	assert.Len(t, repo.fillCalls, 2)
+	require.Len(t, fills, 1)
+	assert.Equal(t, int64(2), fills[0].OfferID)
+}
+
+func TestPaperTradingService_AccountsAndOffers(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	repo := &fakePaperRepo{}
+	buyLimit := 70
+	itemRepo := &fakeItemRepo{getByItemIDItem: &models.Item{ItemID: 4151, Name: "Abyssal whip", BuyLimit: &buyLimit}}
+	priceRepo := &fakePriceRepo{currentPrices: []models.CurrentPrice{{ItemID: 4151, HighPrice: int64Ptr(1_600_000)}}}
+	svc := services.NewPaperTradingService(repo, priceRepo, itemRepo, logger)
+	ctx := context.Background()
+
+	_, err := svc.CreateAccount(ctx, "alice", "  ", 0)
+	assert.ErrorIs(t, err, models.ErrInvalidPaperRequest)
+	_, err = svc.CreateAccount(ctx, "alice", "alice", math.MaxInt32+1)
+	assert.ErrorIs(t, err, models.ErrInvalidPaperRequest)
+
+	account, err := svc.CreateAccount(ctx, "alice", " alice ", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", account.Name)
+	assert.Equal(t, "alice", account.UserID)
+	assert.Equal(t, int64(models.DefaultPaperStartingBalance), account.Cash)
+	assert.Equal(t, account.Cash, account.StartingBalance)
+
+	_, err = svc.PlaceOffer(ctx, "not-a-uuid", models.PaperOfferRequest{})
+	assert.ErrorIs(t, err, models.ErrPaperAccountNotFound)
+	_, err = svc.PlaceOffer(ctx, account.ID, models.PaperOfferRequest{ItemID: 4151, Side: "hold", Price: 1, Quantity: 1})
+	assert.ErrorIs(t, err, models.ErrInvalidPaperRequest)
+	_, err = svc.PlaceOffer(ctx, account.ID, models.PaperOfferRequest{ItemID: 4151, Side: models.PaperOfferBuy, Price: 0, Quantity: 1})
+	assert.ErrorIs(t, err, models.ErrInvalidPaperRequest)
+
+	offer, err := svc.PlaceOffer(ctx, account.ID, models.PaperOfferRequest{ItemID: 4151, Side: models.PaperOfferBuy, Price: 1_500_000, Quantity: 2})
+	require.NoError(t, err)
+	assert.Equal(t, models.PaperOfferOpen, offer.Status)
+	assert.Equal(t, account.ID, offer.AccountID)
+
+	repo.placeErr = models.ErrPaperInsufficientFunds
+	_, err = svc.PlaceOffer(ctx, account.ID, models.PaperOfferRequest{ItemID: 4151, Side: models.PaperOfferBuy, Price: 1_500_000, Quantity: 100})
+	assert.ErrorIs(t, err, models.ErrPaperInsufficientFunds)
+
+	// 1 whip held at 1.4M, a buy offer holding 3M and 5.6M cash.
+	account.Cash = 5_600_000
+	repo.openOffers = []models.PaperOffer{
+		{ItemID: 4151, Side: models.PaperOfferBuy, Status: models.PaperOfferOpen, Price: 1_500_000, Quantity: 2},
+	}
+	windowStart := time.Now().UTC().Add(-time.Hour)
+	repo.positions = []models.PaperPosition{
+		{ItemID: 4151, Quantity: 1, CostBasis: 1_400_000, BuyWindowStart: &windowStart, BuyWindowBought: 1},
+	}
+
+	summary, err := svc.GetAccount(ctx, account.ID)
+	require.NoError(t, err)
+	require.NotNil(t, summary)
+	assert.Equal(t, int64(3_000_000), summary.Escrow)
+	require.Len(t, summary.Positions, 1)
+	position := summary.Positions[0]
+	assert.Equal(t, "Abyssal whip", position.ItemName)
+	require.NotNil(t, position.MarketValue)
+	assert.Equal(t, int64(1_600_000-32_000), *position.MarketValue, "marked after GE tax")
+	assert.Equal(t, int64(168_000), *position.UnrealizedProfit)
+	require.NotNil(t, position.BuyLimitRemaining)
+	assert.Equal(t, int64(69), *position.BuyLimitRemaining)
+	assert.Equal(t, int64(5_600_000+3_000_000+1_568_000), summary.Equity)
+	assert.Equal(t, summary.Equity-models.DefaultPaperStartingBalance, summary.ProfitLoss)
+
+	missing, err := svc.GetAccount(ctx, "11111111-1111-1111-1111-111111111111")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+	_, err = svc.ListPositions(ctx, "11111111-1111-1111-1111-111111111111")
+	assert.ErrorIs(t, err, models.ErrPaperAccountNotFound)
+}
+
+func TestPaperTradingService_AccountLimit(t *testing.T) {
+	repo := &fakePaperRepo{}
+	svc := services.NewPaperTradingService(repo, &fakePriceRepo{}, &fakeItemRepo{}, zap.NewNop().Sugar())
+	ctx := context.Background()
+
+	for i := 1; i < models.MaxPaperAccountsPerUser; i++ {
+		_, err := svc.CreateAccount(ctx, "alice", "practice", 0)
+		require.NoError(t, err)
+	}
+	_, err := svc.CreateAccount(ctx, "alice", "last", 0)
+	require.NoError(t, err)
+	_, err = svc.CreateAccount(ctx, "alice", "one too many", 0)
+	require.ErrorIs(t, err, models.ErrPaperAccountLimit)
+
+	_, err = svc.CreateAccount(ctx, "bob", "practice", 0)
+	assert.NoError(t, err, "the limit is per user")
+}
+
+func TestPaperTradingHandler(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	base := "/paper/accounts/" + paperAccountID
+
+	tests := []struct {
+		setup    func(m *MockPaperTradingService)
+		name     string
+		method   string
+		path     string
+		body     string
+		expected string
+		status   int
+		noUser   bool
+	}{
+		{name: "create bad body", method: "POST", path: "/paper/accounts", body: "{", status: 400, expected: "invalid request body"},
+		{
+			name:   "create invalid",
+			method: "POST",
+			path:   "/paper/accounts",
+			body:   `{"name":""}`,
+			status: 400,
+			setup: func(m *MockPaperTradingService) {
+				m.On("CreateAccount", mock.Anything, "alice", "", int64(0)).
+					Return(nil, fmt.Errorf("%w: name is required", models.ErrInvalidPaperRequest))
+			},
+			expected: "name is required",
+		},
+		{
+			name:   "create",
+			method: "POST",
+			path:   "/paper/accounts",
+			body:   `{"name":"alice","startingBalance":5000000}`,
+			status: 201,
+			setup: func(m *MockPaperTradingService) {
+				m.On("CreateAccount", mock.Anything, "alice", "alice", int64(5_000_000)).
+					Return(&models.PaperAccount{ID: paperAccountID, Name: "alice", Cash: 5_000_000}, nil)
+			},
+		},
+		{
+			name:     "create without user",
+			method:   "POST",
+			path:     "/paper/accounts",
+			body:     `{"name":"alice"}`,
+			noUser:   true,
+			status:   400,
+			setup:    func(m *MockPaperTradingService) {},
+			expected: "X-User-ID header is required",
+		},
+		{
+			name:   "create over limit",
+			method: "POST",
+			path:   "/paper/accounts",
+			body:   `{"name":"alice"}`,
+			status: 409,
+			setup: func(m *MockPaperTradingService) {
+				m.On("CreateAccount", mock.Anything, "alice", "alice", int64(0)).Return(nil, models.ErrPaperAccountLimit)
+			},
+			expected: "at most 5 paper accounts can be opened per user",
+		},
+		{
+			name:   "get missing",
+			method: "GET",
+			path:   base,
+			status: 404,
+			setup: func(m *MockPaperTradingService) {
+				m.On("GetAccount", mock.Anything, paperAccountID).Return(nil, nil)
+			},
+			expected: "paper account not found",
+		},
+		{
+			name:   "get",
+			method: "GET",
+			path:   base,
+			status: 200,
+			setup: func(m *MockPaperTradingService) {
+				m.On("GetAccount", mock.Anything, paperAccountID).
+					Return(&models.PaperAccountSummary{PaperAccount: models.PaperAccount{ID: paperAccountID}}, nil)
+			},
+		},
+		{
+			name:   "positions unknown account",
+			method: "GET",
+			path:   base + "/positions",
+			status: 404,
+			setup: func(m *MockPaperTradingService) {
+				m.On("ListPositions", mock.Anything, paperAccountID).Return(nil, models.ErrPaperAccountNotFound)
+			},
+			expected: "paper account not found",
+		},
+		{
+			name:   "positions",
+			method: "GET",
+			path:   base + "/positions",
+			status: 200,
+			setup: func(m *MockPaperTradingService) {
+				m.On("ListPositions", mock.Anything, paperAccountID).Return([]models.PaperPositionView{}, nil)
+			},
+		},
+		{name: "offers bad status", method: "GET", path: base + "/offers?status=done", status: 400, expected: "status must be one of: open, filled, cancelled"},
+		{name: "offers bad limit", method: "GET", path: base + "/offers?limit=0", status: 400, expected: "limit must be between 1 and 500"},
+		{
+			name:   "offers",
+			method: "GET",
+			path:   base + "/offers?status=open",
+			status: 200,
+			setup: func(m *MockPaperTradingService) {
+				m.On("ListOffers", mock.Anything, paperAccountID, models.PaperOfferOpen, 50).Return([]models.PaperOffer{}, nil)
+			},
+		},
+		{
+			name:   "place insufficient funds",
+			method: "POST",
+			path:   base + "/offers",
+			body:   `{"itemId":4151,"side":"buy","price":1500000,"quantity":100}`,
+			status: 409,
+			setup: func(m *MockPaperTradingService) {
+				m.On("PlaceOffer", mock.Anything, paperAccountID, mock.Anything).Return(nil, models.ErrPaperInsufficientFunds)
+			},
+			expected: "insufficient cash for this offer",
+		},
+		{
+			name:   "place",
+			method: "POST",
+			path:   base + "/offers",
+			body:   `{"itemId":4151,"side":"sell","price":1600000,"quantity":1}`,
+			status: 201,
+			setup: func(m *MockPaperTradingService) {
+				m.On("PlaceOffer", mock.Anything, paperAccountID, models.PaperOfferRequest{
+					ItemID: 4151, Side: models.PaperOfferSell, Price: 1_600_000, Quantity: 1,
+				}).Return(&models.PaperOffer{ID: 7, Status: models.PaperOfferOpen}, nil)
+			},
+		},
+		{name: "cancel bad id", method: "DELETE", path: base + "/offers/abc", status: 400, expected: "invalid offer ID"},
+		{
+			name:   "cancel missing",
+			method: "DELETE",
+			path:   base + "/offers/7",
+			status: 404,
+			setup: func(m *MockPaperTradingService) {
+				m.On("CancelOffer", mock.Anything, paperAccountID, int64(7)).Return(nil, nil)
+			},
+			expected: "offer not found",
+		},
+		{
+			name:   "cancel closed",
+			method: "DELETE",
+			path:   base + "/offers/7",
+			status: 409,
+			setup: func(m *MockPaperTradingService) {
+				m.On("CancelOffer", mock.Anything, paperAccountID, int64(7)).Return(nil, models.ErrPaperOfferClosed)
+			},
+			expected: "offer is no longer open",
+		},
+		{
+			name:   "cancel",
+			method: "DELETE",
+			path:   base + "/offers/7",
+			status: 200,
+			setup: func(m *MockPaperTradingService) {
+				m.On("CancelOffer", mock.Anything, paperAccountID, int64(7)).
+					Return(&models.PaperOffer{ID: 7, Status: models.PaperOfferCancelled}, nil)
+			},
+		},
+		{
+			name:   "fills error",
+			method: "GET",
+			path:   base + "/fills?limit=10",
+			status: 500,
+			setup: func(m *MockPaperTradingService) {
+				m.On("ListFills", mock.Anything, paperAccountID, 10).Return(nil, errors.New("db down"))
+			},
+			expected: "failed to list paper fills",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPaperService := new(MockPaperTradingService)
+			if tt.setup != nil {
+				tt.setup(mockPaperService)
+			}
+			handler := handlers.NewPaperTradingHandler(mockPaperService, logger)
+
+			app := fiber.New()
+			app.Post("/paper/accounts", middleware.RequireUserID(), handler.CreateAccount)
+			app.Get("/paper/accounts/:id", handler.GetAccount)
+			app.Get("/paper/accounts/:id/positions", handler.ListPositions)
+			app.Get("/paper/accounts/:id/offers", handler.ListOffers)
+			app.Post("/paper/accounts/:id/offers", handler.PlaceOffer)
+			app.Delete("/paper/accounts/:id/offers/:offerId", handler.CancelOffer)
+			app.Get("/paper/accounts/:id/fills", handler.ListFills)
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if !tt.noUser {
+				req.Header.Set(middleware.UserIDHeader, "alice")
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var result map[string]any
+			_ = json.NewDecoder(resp.Body).Decode(&result)
+			switch {
+			case tt.expected != "":
+				assert.Equal(t, tt.expected, result["error"])
+			case tt.status < 300:
+				assert.NotNil(t, result["data"])
+			}
+			mockPaperService.AssertExpectations(t)
+		})
+	}
+}
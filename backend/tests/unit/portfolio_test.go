@@ -0,0 +1,512 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/middleware"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/portfolio"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// fakeJournalRepo is a TradeJournalRepository backed by a slice.
+type fakeJournalRepo struct {
+	createBatchErr error
+	entries        []models.TradeJournalEntry
+	nextID         int64
+}
+
+func (r *fakeJournalRepo) Create(_ context.Context, entry *models.TradeJournalEntry) error {
+	r.nextID++
+	entry.ID = r.nextID
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *fakeJournalRepo) CreateBatch(ctx context.Context, entries []models.TradeJournalEntry) error {
+	if r.createBatchErr != nil {
+		return r.createBatchErr
+	}
+	for i := range entries {
+		_ = r.Create(ctx, &entries[i])
+	}
+	return nil
+}
+
+func (r *fakeJournalRepo) List(
+	_ context.Context,
+	userID string,
+	_ models.JournalListParams,
+) ([]models.TradeJournalEntry, int64, error) {
+	entries, _ := r.ListAll(context.Background(), userID)
+	return entries, int64(len(entries)), nil
+}
+
+func (r *fakeJournalRepo) ListAll(_ context.Context, userID string) ([]models.TradeJournalEntry, error) {
+	entries := make([]models.TradeJournalEntry, 0)
+	for _, e := range r.entries {
+		if e.UserID == userID {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+func (r *fakeJournalRepo) Delete(_ context.Context, userID string, id int64) (bool, error) {
+	for i, e := range r.entries {
+		if e.ID == id && e.UserID == userID {
+			r.entries = append(r.entries[:i], r.entries[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func journalEntry(id int64, itemID int, side models.TradeSide, quantity, price int64, at time.Time) models.TradeJournalEntry {
+	return models.TradeJournalEntry{ID: id, ItemID: itemID, Side: side, Quantity: quantity, Price: price, TradedAt: at}
+}
+
+func TestPortfolio_SummarizeFIFO(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []models.TradeJournalEntry{
+		// Out of order on purpose: Summarize sorts by time.
+		journalEntry(3, 4151, models.TradeSideSell, 15, 1_600_000, start.Add(2*time.Hour)),
+		journalEntry(1, 4151, models.TradeSideBuy, 10, 1_500_000, start),
+		journalEntry(2, 4151, models.TradeSideBuy, 10, 1_550_000, start.Add(time.Hour)),
+		journalEntry(4, 13190, models.TradeSideSell, 2, 8_000_000, start.Add(2*time.Hour)),
+	}
+
+	summary := portfolio.Summarize(entries, map[int]int64{4151: 1_700_000})
+	require.Len(t, summary.Items, 2)
+
+	whip := summary.Items[0]
+	assert.Equal(t, 4151, whip.ItemID, "biggest realized profit first")
+	// 10 @ 1.5M and 5 @ 1.55M sold at 1.6M minus 32k tax each.
+	assert.Equal(t, int64(10*(1_600_000-32_000-1_500_000)+5*(1_600_000-32_000-1_550_000)), whip.RealizedProfit)
+	assert.Equal(t, int64(15*32_000), whip.TaxPaid)
+	assert.Equal(t, int64(5), whip.HeldQuantity)
+	assert.Equal(t, int64(5*1_550_000), whip.HeldCost)
+	require.NotNil(t, whip.AvgHeldCost)
+	assert.InDelta(t, 1_550_000, *whip.AvgHeldCost, 0.001)
+	require.NotNil(t, whip.UnrealizedProfit)
+	assert.Equal(t, int64(5*(1_700_000-34_000-1_550_000)), *whip.UnrealizedProfit)
+	require.NotNil(t, whip.ProfitPerHour)
+	assert.InDelta(t, float64(whip.RealizedProfit)/2, *whip.ProfitPerHour, 0.001)
+	assert.Equal(t, 3, whip.Trades)
+
+	bond := summary.Items[1]
+	assert.Equal(t, int64(2), bond.UnmatchedSellQuantity, "sells without buys realize nothing")
+	assert.Zero(t, bond.RealizedProfit)
+	assert.Zero(t, bond.TaxPaid, "bonds are tax exempt")
+	assert.Nil(t, bond.MarkPrice)
+	assert.Nil(t, bond.ProfitPerHour, "a single trade has no rate")
+
+	assert.Equal(t, whip.RealizedProfit, summary.RealizedProfit)
+	assert.Equal(t, *whip.UnrealizedProfit, summary.UnrealizedProfit)
+	assert.Equal(t, summary.RealizedProfit+summary.UnrealizedProfit, summary.TotalProfit)
+	assert.Equal(t, 4, summary.Trades)
+	require.NotNil(t, summary.FirstTradeAt)
+	assert.Equal(t, start, *summary.FirstTradeAt)
+}
+
+func TestPortfolio_SummarizeEmpty(t *testing.T) {
+	summary := portfolio.Summarize(nil, nil)
+	assert.NotNil(t, summary.Items)
+	assert.Empty(t, summary.Items)
+	assert.Nil(t, summary.FirstTradeAt)
+	assert.Nil(t, summary.ProfitPerHour)
+}
+
+func TestPortfolio_Validate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	valid := func() models.TradeJournalEntry {
+		return models.TradeJournalEntry{ItemID: 4151, Side: " BUY ", Quantity: 1, Price: 1_500_000, TradedAt: now}
+	}
+
+	entry := valid()
+	require.NoError(t, portfolio.Validate(&entry, now))
+	assert.Equal(t, models.TradeSideBuy, entry.Side, "side is normalized")
+
+	tests := []struct {
+		mutate   func(e *models.TradeJournalEntry)
+		expected string
+	}{
+		{func(e *models.TradeJournalEntry) { e.ItemID = 0 }, "itemId is required"},
+		{func(e *models.TradeJournalEntry) { e.Side = "hold" }, "side must be one of: buy, sell"},
+		{func(e *models.TradeJournalEntry) { e.Quantity = 0 }, "quantity must be between 1 and 2147483647"},
+		{func(e *models.TradeJournalEntry) { e.Price = 1 << 40 }, "price must be between 1 and 2147483647"},
+		{func(e *models.TradeJournalEntry) { e.TradedAt = now.Add(time.Hour) }, "tradedAt must not be in the future"},
+		{func(e *models.TradeJournalEntry) { e.Note = strings.Repeat("x", 501) }, "note must be at most 500 characters"},
+	}
+	for _, tt := range tests {
+		entry := valid()
+		tt.mutate(&entry)
+		err := portfolio.Validate(&entry, now)
+		require.ErrorIs(t, err, models.ErrInvalidJournalEntry)
+		assert.Equal(t, "invalid journal entry: "+tt.expected, err.Error())
+	}
+}
+
+func TestPortfolio_CSVRoundTrip(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	input := "\ufeffItem_ID,side,quantity,price,traded_at,note,extra\n" +
+		"4151,buy,\"1,000\",1500000,2026-02-28 10:30:00,first,ignored\n" +
+		"\n" +
+		"4151,SELL,1000,1_600_000,2026-02-28T11:00:00Z,,\n"
+
+	entries, err := portfolio.ReadCSV(strings.NewReader(input), now)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, int64(1000), entries[0].Quantity)
+	assert.Equal(t, "first", entries[0].Note)
+	assert.Equal(t, time.Date(2026, 2, 28, 10, 30, 0, 0, time.UTC), entries[0].TradedAt)
+	assert.Equal(t, models.TradeSideSell, entries[1].Side)
+	assert.Equal(t, int64(1_600_000), entries[1].Price)
+
+	var buf bytes.Buffer
+	require.NoError(t, portfolio.WriteCSV(&buf, entries, map[int]string{4151: "Abyssal whip"}))
+	assert.True(t, strings.HasPrefix(buf.String(), "id,item_id,item_name,side,quantity,price,traded_at,note\n"))
+	assert.Contains(t, buf.String(), "0,4151,Abyssal whip,buy,1000,1500000,2026-02-28T10:30:00Z,first\n")
+
+	again, err := portfolio.ReadCSV(&buf, now)
+	require.NoError(t, err)
+	assert.Equal(t, entries, again, "exported journals import unchanged")
+}
+
+func TestPortfolio_WriteCSVEscapesFormulas(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tradedAt := time.Date(2026, 2, 28, 10, 30, 0, 0, time.UTC)
+	notes := []string{`=HYPERLINK("http://evil.example","x")`, "+1", "-2+3", "@SUM(A1)", "\tdata", "'quoted", "plain - note"}
+	entries := make([]models.TradeJournalEntry, len(notes))
+	for i, note := range notes {
+		entries[i] = models.TradeJournalEntry{
+			ItemID: 4151, Side: models.TradeSideBuy, Quantity: 1, Price: 1, TradedAt: tradedAt, Note: note,
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, portfolio.WriteCSV(&buf, entries, map[int]string{4151: "=cmd|' /C calc'!A0"}))
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, len(notes)+1)
+	assert.Equal(t, "'=cmd|' /C calc'!A0", records[1][2], "item names are escaped too")
+	var exported []string
+	for _, record := range records[1:] {
+		exported = append(exported, record[7])
+	}
+	assert.Equal(t, []string{
+		`'=HYPERLINK("http://evil.example","x")`, "'+1", "'-2+3", "'@SUM(A1)", "'\tdata", "'quoted", "plain - note",
+	}, exported)
+
+	again, err := portfolio.ReadCSV(&buf, now)
+	require.NoError(t, err)
+	for i, entry := range again {
+		// Import trims surrounding whitespace, as for any note.
+		assert.Equal(t, strings.TrimSpace(notes[i]), entry.Note, "escaped notes import unchanged")
+	}
+}
+
+func TestPortfolio_ReadCSVErrors(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	header := "item_id,side,quantity,price,traded_at\n"
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"empty", "", "CSV is empty"},
+		{"missing column", "item_id,side,quantity,price\n", "CSV header must include item_id, side, quantity, price, traded_at"},
+		{"no rows", header, "CSV has no rows"},
+		{"bad number", header + "4151,buy,1,1,2026-02-28\n4151,buy,many,1,2026-02-28\n", "line 3: quantity must be a whole number"},
+		{"bad time", header + "4151,buy,1,1,yesterday\n", "line 2: traded_at must be an RFC3339 or YYYY-MM-DD HH:MM:SS timestamp"},
+		{"invalid entry", header + "4151,hold,1,1,2026-02-28\n", "line 2: side must be one of: buy, sell"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := portfolio.ReadCSV(strings.NewReader(tt.input), now)
+			require.ErrorIs(t, err, models.ErrInvalidJournalEntry)
+			assert.Equal(t, "invalid journal entry: "+tt.expected, err.Error())
+		})
+	}
+}
+
+func TestPortfolioService(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	ctx := context.Background()
+	repo := &fakeJournalRepo{}
+	itemRepo := &fakeItemRepo{getByItemIDItem: &models.Item{ItemID: 4151, Name: "Abyssal whip"}}
+	priceRepo := &fakePriceRepo{currentPrices: []models.CurrentPrice{{ItemID: 4151, HighPrice: int64Ptr(1_700_000)}}}
+	svc := services.NewPortfolioService(repo, priceRepo, itemRepo, logger)
+
+	entry, err := svc.AddEntry(ctx, "alice", models.TradeJournalEntry{ItemID: 4151, Side: models.TradeSideBuy, Quantity: 2, Price: 1_500_000})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", entry.UserID)
+	assert.False(t, entry.TradedAt.IsZero(), "tradedAt defaults to now")
+
+	imported, err := svc.ImportCSV(ctx, "alice", strings.NewReader(
+		"item_id,side,quantity,price,traded_at\n4151,sell,1,1600000,2020-01-01\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, imported)
+
+	_, err = svc.AddEntry(ctx, "bob", models.TradeJournalEntry{ItemID: 4151, Side: models.TradeSideBuy, Quantity: 1, Price: 1})
+	require.NoError(t, err)
+
+	// The imported sell predates the buy, so it is unmatched and both units stay held.
+	summary, err := svc.GetSummary(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, summary.Items, 1)
+	assert.Equal(t, "Abyssal whip", summary.Items[0].ItemName)
+	assert.Equal(t, int64(1), summary.Items[0].UnmatchedSellQuantity)
+	assert.Equal(t, int64(2), summary.Items[0].HeldQuantity)
+	assert.Equal(t, int64(2*(1_700_000-34_000-1_500_000)), summary.UnrealizedProfit)
+	assert.Equal(t, 2, summary.Trades, "other users' trades are excluded")
+
+	var buf bytes.Buffer
+	require.NoError(t, svc.ExportCSV(ctx, "alice", &buf))
+	assert.Equal(t, 3, strings.Count(buf.String(), "\n"))
+
+	deleted, err := svc.DeleteEntry(ctx, "bob", entry.ID)
+	require.NoError(t, err)
+	assert.False(t, deleted, "users cannot delete each other's entries")
+
+	// Unknown items fail the whole import.
+	itemRepo.getByItemIDItem = nil
+	repo.createBatchErr = errors.New("must not be called")
+	_, err = svc.ImportCSV(ctx, "alice", strings.NewReader(
+		"item_id,side,quantity,price,traded_at\n999999,buy,1,1,2020-01-01\n"))
+	require.ErrorIs(t, err, models.ErrInvalidJournalEntry)
+	assert.Equal(t, "invalid journal entry: unknown item ID: 999999", err.Error())
+}
+
+func TestPortfolioService_ImportLooksUpItemsOnce(t *testing.T) {
+	repo := &fakeJournalRepo{}
+	itemRepo := &fakeItemRepo{itemsByID: map[int]*models.Item{
+		4151:  {ItemID: 4151, Name: "Abyssal whip"},
+		11802: {ItemID: 11802, Name: "Armadyl godsword"},
+		560:   {ItemID: 560, Name: "Death rune"},
+	}}
+	svc := services.NewPortfolioService(repo, &fakePriceRepo{}, itemRepo, zap.NewNop().Sugar())
+
+	imported, err := svc.ImportCSV(context.Background(), "alice", strings.NewReader(
+		"item_id,side,quantity,price,traded_at\n"+
+			"4151,buy,1,1500000,2020-01-01\n"+
+			"11802,buy,1,9000000,2020-01-01\n"+
+			"560,buy,100,200,2020-01-01\n"+
+			"4151,sell,1,1600000,2020-01-02\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 4, imported)
+	assert.Equal(t, 1, itemRepo.getByItemIDCalls)
+}
+
+func TestPortfolioHandler(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	tradedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	itemID := 4151
+
+	tests := []struct {
+		setup       func(m *MockPortfolioService)
+		name        string
+		method      string
+		path        string
+		body        string
+		contentType string
+		userID      string
+		expected    string
+		status      int
+	}{
+		{name: "missing user", method: "GET", path: "/portfolio/summary", status: 400, expected: "X-User-ID header is required"},
+		{name: "invalid user", method: "GET", path: "/portfolio/summary", userID: "a b", status: 400, expected: "X-User-ID must be 1-64 letters, digits or . _ @ -"},
+		{
+			name:   "summary",
+			method: "GET",
+			path:   "/portfolio/summary",
+			status: 200,
+			setup: func(m *MockPortfolioService) {
+				m.On("GetSummary", mock.Anything, "alice").Return(&models.PortfolioSummary{Items: []models.PortfolioItemSummary{}}, nil)
+			},
+		},
+		{name: "list bad limit", method: "GET", path: "/portfolio/journal?limit=5000", status: 400, expected: "limit must be between 1 and 1000"},
+		{name: "list bad from", method: "GET", path: "/portfolio/journal?from=soon", status: 400, expected: "invalid from timestamp"},
+		{
+			name:   "list",
+			method: "GET",
+			path:   "/portfolio/journal?itemId=4151&offset=10",
+			status: 200,
+			setup: func(m *MockPortfolioService) {
+				m.On("ListEntries", mock.Anything, "alice", models.JournalListParams{ItemID: &itemID, Limit: 100, Offset: 10}).
+					Return([]models.TradeJournalEntry{}, int64(0), nil)
+			},
+		},
+		{name: "add bad body", method: "POST", path: "/portfolio/journal", body: "{", status: 400, expected: "invalid request body"},
+		{
+			name:   "add invalid",
+			method: "POST",
+			path:   "/portfolio/journal",
+			body:   `{"itemId":4151,"side":"hold","quantity":1,"price":1}`,
+			status: 400,
+			setup: func(m *MockPortfolioService) {
+				m.On("AddEntry", mock.Anything, "alice", mock.Anything).
+					Return(nil, portfolioError("side must be one of: buy, sell"))
+			},
+			expected: "side must be one of: buy, sell",
+		},
+		{
+			name:   "add",
+			method: "POST",
+			path:   "/portfolio/journal",
+			body:   `{"itemId":4151,"side":"buy","quantity":2,"price":1500000,"tradedAt":"2026-03-01T12:00:00Z","note":"flip"}`,
+			status: 201,
+			setup: func(m *MockPortfolioService) {
+				m.On("AddEntry", mock.Anything, "alice", models.TradeJournalEntry{
+					ItemID: 4151, Side: models.TradeSideBuy, Quantity: 2, Price: 1_500_000, TradedAt: tradedAt, Note: "flip",
+				}).Return(&models.TradeJournalEntry{ID: 1}, nil)
+			},
+		},
+		{name: "delete bad id", method: "DELETE", path: "/portfolio/journal/abc", status: 400, expected: "invalid journal entry ID"},
+		{
+			name:   "delete missing",
+			method: "DELETE",
+			path:   "/portfolio/journal/7",
+			status: 404,
+			setup: func(m *MockPortfolioService) {
+				m.On("DeleteEntry", mock.Anything, "alice", int64(7)).Return(false, nil)
+			},
+			expected: "journal entry not found",
+		},
+		{
+			name:   "delete",
+			method: "DELETE",
+			path:   "/portfolio/journal/7",
+			status: 204,
+			setup: func(m *MockPortfolioService) {
+				m.On("DeleteEntry", mock.Anything, "alice", int64(7)).Return(true, nil)
+			},
+		},
+		{
+			name:        "import",
+			method:      "POST",
+			path:        "/portfolio/journal/import",
+			body:        "item_id,side,quantity,price,traded_at\n",
+			contentType: "text/csv",
+			status:      201,
+			setup: func(m *MockPortfolioService) {
+				m.On("ImportCSV", mock.Anything, "alice", mock.Anything).Return(3, nil)
+			},
+		},
+		{
+			name:        "import invalid",
+			method:      "POST",
+			path:        "/portfolio/journal/import",
+			body:        "nonsense",
+			contentType: "text/csv",
+			status:      400,
+			setup: func(m *MockPortfolioService) {
+				m.On("ImportCSV", mock.Anything, "alice", mock.Anything).
+					Return(0, portfolioError("CSV header must include item_id"))
+			},
+			expected: "CSV header must include item_id",
+		},
+		{
+			name:   "export error",
+			method: "GET",
+			path:   "/portfolio/journal/export",
+			status: 500,
+			setup: func(m *MockPortfolioService) {
+				m.On("ExportCSV", mock.Anything, "alice", mock.Anything).Return(errors.New("db down"))
+			},
+			expected: "failed to export journal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPortfolioService := new(MockPortfolioService)
+			if tt.setup != nil {
+				tt.setup(mockPortfolioService)
+			}
+			app := newPortfolioApp(handlers.NewPortfolioHandler(mockPortfolioService, logger))
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.name != "missing user" {
+				userID := tt.userID
+				if userID == "" {
+					userID = "alice"
+				}
+				req.Header.Set(middleware.UserIDHeader, userID)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var result map[string]any
+			_ = json.NewDecoder(resp.Body).Decode(&result)
+			switch {
+			case tt.expected != "":
+				assert.Equal(t, tt.expected, result["error"])
+			case tt.status < 300 && tt.status != 204:
+				assert.NotNil(t, result["data"])
+			}
+			mockPortfolioService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPortfolioHandler_ExportJournal(t *testing.T) {
+	mockPortfolioService := new(MockPortfolioService)
+	mockPortfolioService.On("ExportCSV", mock.Anything, "alice", mock.Anything).
+		Run(func(args mock.Arguments) {
+			_, _ = io.WriteString(args.Get(2).(io.Writer), "id,item_id\n1,4151\n")
+		}).
+		Return(nil)
+	app := newPortfolioApp(handlers.NewPortfolioHandler(mockPortfolioService, zap.NewNop().Sugar()))
+
+	req := httptest.NewRequest("GET", "/portfolio/journal/export", nil)
+	req.Header.Set(middleware.UserIDHeader, "alice")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Contains(t, resp.Header.Get("Content-Disposition"), "trade-journal.csv")
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "id,item_id\n1,4151\n", string(body))
+}
+
+func newPortfolioApp(handler *handlers.PortfolioHandler) *fiber.App {
+	app := fiber.New()
+	group := app.Group("/portfolio", middleware.RequireUserID())
+	group.Get("/summary", handler.GetSummary)
+	group.Get("/journal/export", handler.ExportJournal)
+	group.Post("/journal/import", handler.ImportJournal)
+	group.Get("/journal", handler.ListJournal)
+	group.Post("/journal", handler.AddJournalEntry)
+	group.Delete("/journal/:id", handler.DeleteJournalEntry)
+	return app
+}
+
+func portfolioError(msg string) error {
+	return fmt.Errorf("%w: %s", models.ErrInvalidJournalEntry, msg)
+}
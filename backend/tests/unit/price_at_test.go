@@ -0,0 +1,192 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+func TestPriceService_GetPricesAt_UsesFinestCoveringResolution(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	ts := time.Date(2026, 3, 14, 18, 0, 0, 0, time.UTC)
+
+	priceRepo := &fakePriceRepo{
+		pricesAt: map[models.PriceResolution][]models.PriceAt{
+			models.ResolutionLatest: {
+				{ItemID: 3, Timestamp: ts.Add(-time.Minute), HighPrice: intPtr(300)},
+			},
+			models.Resolution1h: {
+				{ItemID: 1, Timestamp: ts, HighPrice: intPtr(100)},
+				// Already resolved from price_latest; must not be overwritten.
+				{ItemID: 3, Timestamp: ts, HighPrice: intPtr(999)},
+			},
+			models.ResolutionDaily: {
+				{ItemID: 2, Timestamp: time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC), LowPrice: intPtr(50)},
+			},
+		},
+	}
+	svc := services.NewPriceService(priceRepo, &fakeItemRepo{}, newMemoryCache(), "", logger)
+
+	prices, err := svc.GetPricesAt(context.Background(), []int{3, 2, 1, 4}, ts)
+	require.NoError(t, err)
+	require.Len(t, prices, 3)
+
+	assert.Equal(t, 1, prices[0].ItemID)
+	assert.Equal(t, models.Resolution1h, prices[0].Resolution)
+
+	assert.Equal(t, 2, prices[1].ItemID)
+	assert.Equal(t, models.ResolutionDaily, prices[1].Resolution)
+
+	assert.Equal(t, 3, prices[2].ItemID)
+	assert.Equal(t, models.ResolutionLatest, prices[2].Resolution)
+	assert.Equal(t, int64(300), *prices[2].HighPrice)
+	assert.Equal(t, ts.Add(-time.Minute), prices[2].Timestamp)
+	assert.Equal(t, ts, prices[2].RequestedAt)
+}
+
+func TestPriceResolution_Coverage(t *testing.T) {
+	assert.Equal(t, 2*time.Minute, models.ResolutionLatest.Coverage())
+	assert.Equal(t, 5*time.Minute, models.Resolution5m.Coverage())
+	assert.Equal(t, 6*time.Hour, models.Resolution6h.Coverage())
+	assert.Equal(t, 24*time.Hour, models.ResolutionDaily.Coverage())
+	assert.Equal(t, models.ResolutionLatest, models.PointInTimeResolutions[0])
+	assert.Equal(t, models.ResolutionDaily, models.PointInTimeResolutions[len(models.PointInTimeResolutions)-1])
+}
+
+func TestPriceHandler_GetPriceAt(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	ts := time.Date(2026, 3, 14, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		setup    func(m *MockPriceService)
+		name     string
+		path     string
+		expected string
+		status   int
+	}{
+		{name: "missing ts", path: "/prices/at/4151", status: 400, expected: "ts query parameter is required"},
+		{
+			name:     "bad ts",
+			path:     "/prices/at/4151?ts=noon",
+			status:   400,
+			expected: "invalid ts, expected RFC3339, 'YYYY-MM-DD HH:MM' or unix seconds",
+		},
+		{name: "future ts", path: "/prices/at/4151?ts=2999-01-01", status: 400, expected: "ts must not be in the future"},
+		{name: "bad id", path: "/prices/at/abc?ts=2026-03-14", status: 400, expected: "invalid item ID"},
+		{
+			name:   "not covered",
+			path:   "/prices/at/4151?ts=2026-03-14%2018:00",
+			status: 404,
+			setup: func(m *MockPriceService) {
+				m.On("GetPricesAt", mock.Anything, []int{4151}, ts).Return([]models.PriceAt{}, nil)
+			},
+			expected: "no stored price covers the requested timestamp",
+		},
+		{
+			name:   "found",
+			path:   "/prices/at/4151?ts=2026-03-14T18:00:00Z",
+			status: 200,
+			setup: func(m *MockPriceService) {
+				m.On("GetPricesAt", mock.Anything, []int{4151}, ts).Return([]models.PriceAt{
+					{ItemID: 4151, Timestamp: ts, RequestedAt: ts, Resolution: models.Resolution5m},
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPriceService := new(MockPriceService)
+			if tt.setup != nil {
+				tt.setup(mockPriceService)
+			}
+			handler := handlers.NewPriceHandler(mockPriceService, nil, nil, logger)
+
+			app := fiber.New()
+			app.Get("/prices/at/:id", handler.GetPriceAt)
+
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path, http.NoBody))
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var result map[string]any
+			_ = json.NewDecoder(resp.Body).Decode(&result)
+			if tt.expected != "" {
+				assert.Equal(t, tt.expected, result["error"])
+			} else {
+				data := result["data"].(map[string]any)
+				assert.Equal(t, "5m", data["resolution"])
+			}
+			mockPriceService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPriceHandler_GetBatchPricesAt_Watchlist(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	ts := time.Unix(1773511200, 0).UTC()
+
+	mockPriceService := new(MockPriceService)
+	mockWatchlistService := new(MockWatchlistService)
+	handler := handlers.NewPriceHandler(mockPriceService, mockWatchlistService, nil, logger)
+
+	mockWatchlistService.On("GetShareItemIDs", mock.Anything, "swift-golden-dragon").Return([]int{4151, 11802}, nil)
+	mockWatchlistService.On("GetShareItemIDs", mock.Anything, "gone-grey-goblin").Return(nil, models.ErrShareExpired)
+	mockWatchlistService.On("GetShareItemIDs", mock.Anything, "not-a-token").Return(nil, models.ErrInvalidShareToken)
+	mockWatchlistService.On("GetShareItemIDs", mock.Anything, "lost-blue-moon").Return(nil, fmt.Errorf("database error: %w", errors.New("connection reset")))
+	mockPriceService.On("GetPricesAt", mock.Anything, []int{4151, 11802}, ts).Return([]models.PriceAt{
+		{ItemID: 4151, Timestamp: ts, RequestedAt: ts, Resolution: models.ResolutionLatest},
+	}, nil)
+
+	app := fiber.New()
+	app.Get("/prices/at", handler.GetBatchPricesAt)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/prices/at?share=swift-golden-dragon&ts=1773511200", http.NoBody))
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+
+	var result map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	meta := result["meta"].(map[string]any)
+	assert.Equal(t, float64(2), meta["requested"])
+	assert.Equal(t, float64(1), meta["found"])
+	assert.Equal(t, []any{float64(11802)}, meta["missing"])
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/prices/at?share=gone-grey-goblin&ts=1773511200", http.NoBody))
+	require.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/prices/at?share=not-a-token&ts=1773511200", http.NoBody))
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/prices/at?share=lost-blue-moon&ts=1773511200", http.NoBody))
+	require.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode, "other share errors are not reported as missing shares")
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/prices/at?ids=1,2&share=swift-golden-dragon&ts=1773511200", http.NoBody))
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/prices/at?ts=1773511200", http.NoBody))
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+
+	mockPriceService.AssertExpectations(t)
+	mockWatchlistService.AssertExpectations(t)
+}
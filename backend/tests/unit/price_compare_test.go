@@ -0,0 +1,282 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
+)
+
+func floatPtr(v float64) *float64 { return &v }
+
+func derefFloats(values []*float64) []any {
+	out := make([]any, len(values))
+	for i, v := range values {
+		if v != nil {
+			out[i] = *v
+		}
+	}
+	return out
+}
+
+func TestBuildTimeGrid(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	grid := utils.BuildTimeGrid(base.Add(2*time.Minute), base.Add(20*time.Minute), 5*time.Minute)
+	require.Len(t, grid, 5)
+	assert.Equal(t, base, grid[0], "start is truncated to a step boundary")
+	assert.Equal(t, base.Add(20*time.Minute), grid[4])
+
+	assert.Empty(t, utils.BuildTimeGrid(base, base.Add(-time.Minute), time.Minute))
+	assert.Empty(t, utils.BuildTimeGrid(base, base.Add(time.Hour), 0))
+}
+
+func TestForwardFill(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	grid := utils.BuildTimeGrid(base, base.Add(4*time.Hour), time.Hour)
+
+	type obs struct {
+		v  *float64
+		ts time.Time
+	}
+	// Deliberately descending, as returned by the repository.
+	points := []obs{
+		{ts: base.Add(3 * time.Hour), v: nil},
+		{ts: base.Add(2*time.Hour + 30*time.Minute), v: floatPtr(30)},
+		{ts: base.Add(time.Hour), v: floatPtr(10)},
+	}
+
+	filled := utils.ForwardFill(grid, points,
+		func(o obs) time.Time { return o.ts },
+		func(o obs) *float64 { return o.v },
+	)
+
+	assert.Equal(t, []any{nil, 10.0, 10.0, 30.0, 30.0}, derefFloats(filled))
+}
+
+func TestRebaseAndRatio(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []*float64
+		expected  []any
+		expectIdx int
+	}{
+		{
+			name:      "rebases from first value",
+			values:    []*float64{floatPtr(50), floatPtr(75), nil, floatPtr(25)},
+			expected:  []any{100.0, 150.0, nil, 50.0},
+			expectIdx: 0,
+		},
+		{
+			name:      "leading gaps stay empty",
+			values:    []*float64{nil, floatPtr(200), floatPtr(300)},
+			expected:  []any{nil, 100.0, 150.0},
+			expectIdx: 1,
+		},
+		{
+			name:      "no data",
+			values:    []*float64{nil, nil},
+			expected:  []any{nil, nil},
+			expectIdx: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rebased, idx := utils.Rebase(tt.values, 100)
+			assert.Equal(t, tt.expectIdx, idx)
+			assert.Equal(t, tt.expected, derefFloats(rebased))
+		})
+	}
+
+	ratio := utils.RatioSeries(
+		[]*float64{floatPtr(10), nil, floatPtr(9), floatPtr(4)},
+		[]*float64{floatPtr(5), floatPtr(1), floatPtr(0), floatPtr(8)},
+	)
+	assert.Equal(t, []any{2.0, nil, nil, 0.5}, derefFloats(ratio))
+}
+
+func TestPriceService_ComparePrices_AlignsAndForwardFills(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	priceRepo := &fakePriceRepo{
+		timeseriesPoints: map[int][]models.PriceTimeseriesPoint{
+			1: {
+				{ItemID: 1, Timestamp: base.Add(10 * time.Minute), AvgHighPrice: intPtr(120), AvgLowPrice: intPtr(120)},
+				{ItemID: 1, Timestamp: base, AvgHighPrice: intPtr(110), AvgLowPrice: intPtr(90)},
+			},
+			2: {
+				{ItemID: 2, Timestamp: base.Add(20 * time.Minute), AvgHighPrice: intPtr(40), AvgLowPrice: nil},
+				{ItemID: 2, Timestamp: base.Add(5 * time.Minute), AvgHighPrice: intPtr(50), AvgLowPrice: intPtr(50)},
+			},
+		},
+	}
+	svc := services.NewPriceService(priceRepo, &fakeItemRepo{}, newMemoryCache(), "", logger)
+
+	start := base
+	end := base.Add(20 * time.Minute)
+	resp, err := svc.ComparePrices(context.Background(), models.PriceCompareParams{
+		ItemIDs:   []int{1, 2},
+		Period:    models.Period24Hours,
+		StartTime: &start,
+		EndTime:   &end,
+		AllRatios: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "5m", resp.Timestep)
+	assert.Equal(t, int64(300), resp.IntervalSeconds)
+	require.Len(t, resp.Timestamps, 5)
+	require.Len(t, resp.Series, 2)
+
+	assert.Equal(t, []any{100.0, 100.0, 120.0, 120.0, 120.0}, derefFloats(resp.Series[0].Prices))
+	assert.Equal(t, []any{100.0, 100.0, 120.0, 120.0, 120.0}, derefFloats(resp.Series[0].Index))
+
+	assert.Equal(t, []any{nil, 50.0, 50.0, 50.0, 40.0}, derefFloats(resp.Series[1].Prices))
+	assert.Equal(t, []any{nil, 100.0, 100.0, 100.0, 80.0}, derefFloats(resp.Series[1].Index))
+	require.NotNil(t, resp.Series[1].BaseTimestamp)
+	assert.Equal(t, base.Add(5*time.Minute), *resp.Series[1].BaseTimestamp)
+
+	require.Len(t, resp.Ratios, 1)
+	assert.Equal(t, 1, resp.Ratios[0].NumeratorID)
+	assert.Equal(t, 2, resp.Ratios[0].DenominatorID)
+	assert.Equal(t, []any{nil, 2.0, 2.4, 2.4, 3.0}, derefFloats(resp.Ratios[0].Values))
+	assert.Equal(t, 1, priceRepo.timeseriesForItemsCalls, "every item's buckets come from one query")
+}
+
+func TestPriceService_ComparePrices_MergesDailyRollups(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	end := time.Now().UTC().Truncate(24 * time.Hour)
+	rolledUp := end.AddDate(0, 0, -40)
+
+	priceRepo := &fakePriceRepo{
+		timeseriesPoints: map[int][]models.PriceTimeseriesPoint{
+			1: {{ItemID: 1, Timestamp: end.AddDate(0, 0, -10), AvgHighPrice: intPtr(200), AvgLowPrice: intPtr(200)}},
+			2: {{ItemID: 2, Timestamp: end.AddDate(0, 0, -10), AvgHighPrice: intPtr(50), AvgLowPrice: intPtr(50)}},
+		},
+		dailyPoints: map[int][]models.PriceTimeseriesDaily{
+			1: {{ItemID: 1, Day: rolledUp, AvgHighPrice: intPtr(100), AvgLowPrice: intPtr(100)}},
+		},
+	}
+	svc := services.NewPriceService(priceRepo, &fakeItemRepo{}, newMemoryCache(), "", logger)
+
+	resp, err := svc.ComparePrices(context.Background(), models.PriceCompareParams{
+		ItemIDs: []int{1, 2},
+		Period:  models.Period1Year,
+		EndTime: &end,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Series, 2)
+	require.NotNil(t, resp.Series[0].BasePrice)
+	assert.Equal(t, 100.0, *resp.Series[0].BasePrice, "rolled-up days start the series")
+	assert.Equal(t, 200.0, *resp.Series[0].Prices[len(resp.Series[0].Prices)-1])
+	assert.Equal(t, 1, priceRepo.timeseriesForItemsCalls)
+	assert.Equal(t, 1, priceRepo.dailyPointsCalls)
+}
+
+func TestPriceService_ComparePrices_WidensGridToMaxPoints(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var points []models.PriceTimeseriesPoint
+	for i := 0; i < 288; i++ {
+		points = append(points, models.PriceTimeseriesPoint{
+			ItemID:       1,
+			Timestamp:    base.Add(time.Duration(i) * 5 * time.Minute),
+			AvgHighPrice: intPtr(int64(100 + i)),
+		})
+	}
+	priceRepo := &fakePriceRepo{timeseriesPoints: map[int][]models.PriceTimeseriesPoint{1: points, 2: points}}
+	svc := services.NewPriceService(priceRepo, &fakeItemRepo{}, newMemoryCache(), "", logger)
+
+	start := base
+	end := base.Add(24 * time.Hour)
+	maxPoints := 25
+	resp, err := svc.ComparePrices(context.Background(), models.PriceCompareParams{
+		ItemIDs:   []int{1, 2},
+		Period:    models.Period24Hours,
+		StartTime: &start,
+		EndTime:   &end,
+		MaxPoints: &maxPoints,
+	})
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, resp.Count, maxPoints)
+	assert.Equal(t, int64(3600), resp.IntervalSeconds)
+	assert.Empty(t, resp.Ratios)
+}
+
+func TestPriceHandler_ComparePrices_Validation(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{name: "missing ids", query: "", expected: "ids query parameter is required"},
+		{name: "single id", query: "ids=1", expected: "at least 2 distinct item IDs are required"},
+		{name: "duplicate ids", query: "ids=1,1", expected: "at least 2 distinct item IDs are required"},
+		{name: "bad id", query: "ids=1,abc", expected: "invalid item ID: abc"},
+		{name: "too many", query: "ids=1,2,3,4,5,6,7,8,9,10,11", expected: "maximum 10 items per request"},
+		{name: "bad period", query: "ids=1,2&period=2w", expected: "invalid period, must be one of: 1h, 12h, 24h, 3d, 7d, 30d, 90d, 1y, all"},
+		{name: "bad field", query: "ids=1,2&field=open", expected: "field must be one of: mid, high, low"},
+		{name: "bad pair", query: "ids=1,2&pairs=1:3", expected: `pair "1:3" references an item not listed in ids`},
+		{name: "bad start", query: "ids=1,2&start=yesterday", expected: "invalid start timestamp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPriceService := new(MockPriceService)
+			handler := handlers.NewPriceHandler(mockPriceService, nil, nil, logger)
+
+			app := fiber.New()
+			app.Get("/prices/compare", handler.ComparePrices)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/prices/compare?"+tt.query, http.NoBody))
+			require.NoError(t, err)
+			assert.Equal(t, 400, resp.StatusCode)
+
+			var result map[string]any
+			_ = json.NewDecoder(resp.Body).Decode(&result)
+			assert.Equal(t, tt.expected, result["error"])
+
+			mockPriceService.AssertNotCalled(t, "ComparePrices", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPriceHandler_ComparePrices_OK(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	mockPriceService := new(MockPriceService)
+	handler := handlers.NewPriceHandler(mockPriceService, nil, nil, logger)
+
+	mockPriceService.On("ComparePrices", mock.Anything, mock.MatchedBy(func(p models.PriceCompareParams) bool {
+		return len(p.ItemIDs) == 2 && len(p.Pairs) == 1 && p.Pairs[0].NumeratorID == 11802 && p.Field == models.PriceFieldHigh
+	})).Return(&models.PriceCompareResponse{Period: "7d", Field: "high", Timestep: "1h", Count: 0}, nil)
+
+	app := fiber.New()
+	app.Get("/prices/compare", handler.ComparePrices)
+
+	resp, err := app.Test(httptest.NewRequest("GET",
+		"/prices/compare?ids=11802,11804&period=7d&field=high&pairs=11802:11804", http.NoBody))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	mockPriceService.AssertExpectations(t)
+}
@@ -0,0 +1,398 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/middleware"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/recipes"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// fakeRecipeRepo keeps recipe registry overrides in memory.
+type fakeRecipeRepo struct {
+	overrides map[string]models.RecipeOverride
+}
+
+func (r *fakeRecipeRepo) ListOverrides(_ context.Context) ([]models.RecipeOverride, error) {
+	overrides := make([]models.RecipeOverride, 0, len(r.overrides))
+	for _, o := range r.overrides {
+		overrides = append(overrides, o)
+	}
+	return overrides, nil
+}
+
+func (r *fakeRecipeRepo) UpsertOverride(_ context.Context, override *models.RecipeOverride) error {
+	if r.overrides == nil {
+		r.overrides = map[string]models.RecipeOverride{}
+	}
+	r.overrides[override.RecipeID] = *override
+	return nil
+}
+
+func (r *fakeRecipeRepo) DeleteOverride(_ context.Context, recipeID string) (bool, error) {
+	_, ok := r.overrides[recipeID]
+	delete(r.overrides, recipeID)
+	return ok, nil
+}
+
+func TestDefaultRecipes_AreValidAndUnique(t *testing.T) {
+	list, err := recipes.DefaultRecipes()
+	require.NoError(t, err)
+	require.NotEmpty(t, list)
+
+	seen := map[string]bool{}
+	for _, r := range list {
+		assert.False(t, seen[r.ID], "duplicate recipe %s", r.ID)
+		seen[r.ID] = true
+		assert.Equal(t, models.RecipeSourceBuiltin, r.Source)
+	}
+}
+
+func TestValidateRecipe(t *testing.T) {
+	valid := func() models.Recipe {
+		return models.Recipe{
+			ID:             "clean-ranarr",
+			Name:           "Clean grimy ranarr",
+			Inputs:         []models.RecipeItem{{ItemID: 207}},
+			Outputs:        []models.RecipeItem{{ItemID: 257}},
+			ActionsPerHour: 5000,
+		}
+	}
+	tests := []struct {
+		modify   func(r *models.Recipe)
+		name     string
+		expected string
+	}{
+		{name: "bad id", modify: func(r *models.Recipe) { r.ID = "Clean Ranarr" }, expected: "id must be 1-64 lowercase letters, digits and single dashes"},
+		{name: "blank name", modify: func(r *models.Recipe) { r.Name = " " }, expected: "name must be 1-100 characters"},
+		{name: "no actions", modify: func(r *models.Recipe) { r.ActionsPerHour = 0 }, expected: "actionsPerHour must be between 1 and 100000"},
+		{name: "zero xp", modify: func(r *models.Recipe) { r.XP = floatPtr(0) }, expected: "xp must be positive when set"},
+		{name: "no inputs", modify: func(r *models.Recipe) { r.Inputs = nil }, expected: "a recipe must have 1-10 inputs"},
+		{
+			name:     "duplicate output",
+			modify:   func(r *models.Recipe) { r.Outputs = append(r.Outputs, models.RecipeItem{ItemID: 257}) },
+			expected: "output 2: item 257 is listed twice",
+		},
+		{
+			name:     "bad quantity",
+			modify:   func(r *models.Recipe) { r.Inputs[0].Quantity = -2 },
+			expected: "input 1: quantity must be between 1 and 10000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid()
+			tt.modify(&r)
+			err := recipes.ValidateRecipe(&r)
+			require.ErrorIs(t, err, models.ErrInvalidRecipe)
+			assert.Equal(t, tt.expected, err.Error()[len(models.ErrInvalidRecipe.Error())+2:])
+		})
+	}
+
+	r := valid()
+	require.NoError(t, recipes.ValidateRecipe(&r))
+	assert.Equal(t, 1, r.Inputs[0].Quantity)
+}
+
+func TestMergeRecipes(t *testing.T) {
+	defaults := []models.Recipe{
+		{ID: "a", Name: "A", Skill: "Herblore", Source: models.RecipeSourceBuiltin},
+		{ID: "b", Name: "B", Skill: "Herblore", Source: models.RecipeSourceBuiltin},
+	}
+	overrides := []models.RecipeOverride{
+		{RecipeID: "a", Removed: true},
+		{RecipeID: "b", Definition: []byte(`{"name":"B2","skill":"Herblore","inputs":[{"itemId":1}],"outputs":[{"itemId":2}],"actionsPerHour":10}`)},
+		{RecipeID: "c", Definition: []byte(`{"name":"C","skill":"Cooking","inputs":[{"itemId":3}],"outputs":[{"itemId":4}],"actionsPerHour":10}`)},
+		{RecipeID: "d", Definition: []byte(`{"name":"D"}`)},
+	}
+
+	merged, err := recipes.MergeRecipes(defaults, overrides)
+	require.ErrorIs(t, err, models.ErrInvalidRecipe)
+	require.Len(t, merged, 2)
+	assert.Equal(t, "c", merged[0].ID)
+	assert.Equal(t, "b", merged[1].ID)
+	assert.Equal(t, "B2", merged[1].Name)
+	assert.Equal(t, models.RecipeSourceCustom, merged[1].Source)
+}
+
+func TestEvaluateRecipes(t *testing.T) {
+	list := []models.Recipe{
+		{
+			ID:             "prayer-potion",
+			Name:           "Prayer potion(3)",
+			XP:             floatPtr(87.5),
+			Inputs:         []models.RecipeItem{{ItemID: 99, Quantity: 1}, {ItemID: 231, Quantity: 1}},
+			Outputs:        []models.RecipeItem{{ItemID: 139, Quantity: 1}},
+			ActionsPerHour: 2500,
+		},
+		{
+			ID:             "cannonballs",
+			Name:           "Cannonballs",
+			Inputs:         []models.RecipeItem{{ItemID: 2353, Quantity: 1}},
+			Outputs:        []models.RecipeItem{{ItemID: 2, Quantity: 4}},
+			ActionsPerHour: 1000,
+		},
+		{
+			ID:             "unpriced",
+			Name:           "Unpriced",
+			Inputs:         []models.RecipeItem{{ItemID: 1, Quantity: 1}},
+			Outputs:        []models.RecipeItem{{ItemID: 2, Quantity: 1}},
+			ActionsPerHour: 1,
+		},
+	}
+	items := map[int]models.Item{139: {ItemID: 139, Name: "Prayer potion(3)"}}
+	prices := map[int]models.CurrentPrice{
+		99:   {ItemID: 99, HighPrice: int64Ptr(7_000), LowPrice: int64Ptr(6_800)},
+		231:  {ItemID: 231, HighPrice: int64Ptr(400), LowPrice: int64Ptr(350)},
+		139:  {ItemID: 139, HighPrice: int64Ptr(7_500), LowPrice: int64Ptr(7_300)},
+		2353: {ItemID: 2353, HighPrice: int64Ptr(500), LowPrice: int64Ptr(450)},
+		2:    {ItemID: 2, HighPrice: int64Ptr(200), LowPrice: int64Ptr(190)},
+	}
+
+	results, unpriced := recipes.Evaluate(list, items, prices, models.PricingOffer)
+	assert.Equal(t, []string{"unpriced"}, unpriced)
+	require.Len(t, results, 2)
+
+	// Four cannonballs at 200 less 4 tax each, from a bar bought at 450.
+	balls := results[0]
+	assert.Equal(t, "cannonballs", balls.ID)
+	assert.Equal(t, int64(450), balls.Cost)
+	assert.Equal(t, int64(16), balls.Tax)
+	assert.Equal(t, int64(784), balls.Revenue)
+	assert.Equal(t, int64(334), balls.Profit)
+	assert.Equal(t, int64(334_000), balls.GPPerHour)
+	assert.Nil(t, balls.GPPerXP)
+
+	// 6,800 + 350 bought, one potion sold at 7,500 less 150 tax.
+	prayer := results[1]
+	assert.Equal(t, int64(7_150), prayer.Cost)
+	assert.Equal(t, int64(7_350), prayer.Revenue)
+	assert.Equal(t, int64(200), prayer.Profit)
+	assert.Equal(t, int64(500_000), prayer.GPPerHour)
+	require.NotNil(t, prayer.GPPerXP)
+	assert.InDelta(t, 200/87.5, *prayer.GPPerXP, 0.0001)
+	assert.Equal(t, "Prayer potion(3)", prayer.Outputs[0].Name)
+
+	instant, _ := recipes.Evaluate(list, items, prices, models.PricingInstant)
+	for _, r := range instant {
+		if r.ID == "prayer-potion" {
+			assert.Equal(t, int64(7_300-146-7_400), r.Profit)
+		}
+	}
+
+	recipes.Sort(results, recipes.SortByGPPerHour)
+	assert.Equal(t, "prayer-potion", results[0].ID)
+	recipes.Sort(results, recipes.SortByProfit)
+	assert.Equal(t, "cannonballs", results[0].ID)
+	recipes.Sort(results, recipes.SortByGPPerXP)
+	assert.Equal(t, "prayer-potion", results[0].ID, "recipes without XP sort last")
+}
+
+func TestRecipeService_Registry(t *testing.T) {
+	defaults, err := recipes.DefaultRecipes()
+	require.NoError(t, err)
+	builtin := defaults[0].ID
+
+	repo := &fakeRecipeRepo{}
+	svc := services.NewRecipeService(repo, &fakeItemRepo{}, &fakePriceRepo{}, zap.NewNop().Sugar())
+	ctx := context.Background()
+
+	_, err = svc.SaveRecipe(ctx, models.Recipe{ID: "bad"})
+	require.ErrorIs(t, err, models.ErrInvalidRecipe)
+
+	saved, err := svc.SaveRecipe(ctx, models.Recipe{
+		ID:             "string-bows",
+		Name:           "String bows",
+		Inputs:         []models.RecipeItem{{ItemID: 70}, {ItemID: 1777}},
+		Outputs:        []models.RecipeItem{{ItemID: 859}},
+		ActionsPerHour: 2500,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.RecipeSourceCustom, saved.Source)
+
+	list, err := svc.ListRecipes(ctx)
+	require.NoError(t, err)
+	assert.Len(t, list, len(defaults)+1)
+
+	deleted, err := svc.DeleteRecipe(ctx, builtin)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	assert.True(t, repo.overrides[builtin].Removed)
+
+	deleted, err = svc.DeleteRecipe(ctx, builtin)
+	require.NoError(t, err)
+	assert.False(t, deleted, "a hidden builtin recipe is already gone")
+
+	deleted, err = svc.DeleteRecipe(ctx, "string-bows")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	list, err = svc.ListRecipes(ctx)
+	require.NoError(t, err)
+	assert.Len(t, list, len(defaults)-1)
+
+	// Without prices every recipe is unpriced.
+	report, err := svc.RecipeProfits(ctx, models.PricingOffer)
+	require.NoError(t, err)
+	assert.Empty(t, report.Recipes)
+	assert.Len(t, report.Unpriced, len(defaults)-1)
+}
+
+func TestRecipeHandler(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	report := func() *models.RecipeReport {
+		return &models.RecipeReport{
+			Pricing: models.PricingOffer,
+			Recipes: []models.RecipeProfit{
+				{ID: "a", Skill: "Herblore", Profit: 100, GPPerHour: 1_000},
+				{ID: "b", Skill: "Fletching", Profit: 50, GPPerHour: 5_000},
+				{ID: "c", Skill: "Herblore", Profit: 10, GPPerHour: 20_000},
+			},
+		}
+	}
+
+	tests := []struct {
+		setup    func(m *MockRecipeService)
+		check    func(t *testing.T, result map[string]any)
+		name     string
+		method   string
+		path     string
+		body     string
+		adminKey string
+		expected string
+		status   int
+	}{
+		{
+			name:   "profits filtered by skill",
+			method: "GET",
+			path:   "/recipes?skill=herblore&sort=gp_per_hour&limit=1",
+			status: 200,
+			setup: func(m *MockRecipeService) {
+				m.On("RecipeProfits", mock.Anything, models.PricingOffer).Return(report(), nil)
+			},
+			check: func(t *testing.T, result map[string]any) {
+				list := result["data"].(map[string]any)["recipes"].([]any)
+				require.Len(t, list, 1)
+				assert.Equal(t, "c", list[0].(map[string]any)["id"])
+				assert.InDelta(t, 2, result["meta"].(map[string]any)["total"], 0)
+			},
+		},
+		{name: "bad sort", method: "GET", path: "/recipes?sort=roi", status: 400, expected: "sort must be one of profit, gp_per_hour, gp_per_xp"},
+		{name: "bad pricing", method: "GET", path: "/recipes?pricing=x", status: 400, expected: "pricing must be offer or instant"},
+		{
+			name:   "definitions",
+			method: "GET",
+			path:   "/recipes/definitions",
+			status: 200,
+			setup: func(m *MockRecipeService) {
+				m.On("ListRecipes", mock.Anything).Return([]models.Recipe{}, nil)
+			},
+		},
+		{name: "admin without key", method: "PUT", path: "/admin/recipes/a", body: "{}", status: 401, expected: "invalid or missing X-Admin-Key header"},
+		{
+			name:     "save",
+			method:   "PUT",
+			path:     "/admin/recipes/string-bows",
+			body:     `{"name":"String bows","inputs":[{"itemId":70},{"itemId":1777}],"outputs":[{"itemId":859}],"actionsPerHour":2500,"xp":91.5}`,
+			adminKey: "secret",
+			status:   200,
+			setup: func(m *MockRecipeService) {
+				m.On("SaveRecipe", mock.Anything, models.Recipe{
+					ID:             "string-bows",
+					Name:           "String bows",
+					XP:             floatPtr(91.5),
+					Inputs:         []models.RecipeItem{{ItemID: 70}, {ItemID: 1777}},
+					Outputs:        []models.RecipeItem{{ItemID: 859}},
+					ActionsPerHour: 2500,
+				}).Return(&models.Recipe{ID: "string-bows"}, nil)
+			},
+		},
+		{
+			name:     "save invalid",
+			method:   "PUT",
+			path:     "/admin/recipes/x",
+			body:     `{}`,
+			adminKey: "secret",
+			status:   400,
+			setup: func(m *MockRecipeService) {
+				m.On("SaveRecipe", mock.Anything, mock.Anything).
+					Return(nil, fmt.Errorf("%w: name must be 1-100 characters", models.ErrInvalidRecipe))
+			},
+			expected: "name must be 1-100 characters",
+		},
+		{
+			name:     "delete missing",
+			method:   "DELETE",
+			path:     "/admin/recipes/x",
+			adminKey: "secret",
+			status:   404,
+			setup: func(m *MockRecipeService) {
+				m.On("DeleteRecipe", mock.Anything, "x").Return(false, nil)
+			},
+			expected: "recipe not found",
+		},
+		{
+			name:     "delete",
+			method:   "DELETE",
+			path:     "/admin/recipes/a",
+			adminKey: "secret",
+			status:   204,
+			setup: func(m *MockRecipeService) {
+				m.On("DeleteRecipe", mock.Anything, "a").Return(true, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRecipeService := new(MockRecipeService)
+			if tt.setup != nil {
+				tt.setup(mockRecipeService)
+			}
+			handler := handlers.NewRecipeHandler(mockRecipeService, logger)
+
+			app := fiber.New()
+			app.Get("/recipes/definitions", handler.ListRecipes)
+			app.Get("/recipes", handler.GetRecipeProfits)
+			admin := app.Group("/admin", middleware.RequireAdminKey("secret"))
+			admin.Put("/recipes/:id", handler.SaveRecipe)
+			admin.Delete("/recipes/:id", handler.DeleteRecipe)
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.adminKey != "" {
+				req.Header.Set(middleware.AdminKeyHeader, tt.adminKey)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var result map[string]any
+			_ = json.NewDecoder(resp.Body).Decode(&result)
+			switch {
+			case tt.expected != "":
+				assert.Equal(t, tt.expected, result["error"])
+			case tt.status < 300 && tt.status != 204:
+				assert.NotNil(t, result["data"])
+			}
+			if tt.check != nil {
+				tt.check(t, result)
+			}
+			mockRecipeService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+//go:build slow
+// +build slow
+
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+func TestBacktestRepository_Lifecycle(t *testing.T) {
+	dbClient := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	repo := repository.NewBacktestRepository(dbClient, logger.Sugar())
+	ctx := context.Background()
+
+	job := &models.Backtest{
+		ID:       uuid.New().String(),
+		Name:     "rsi dip",
+		Status:   models.BacktestStatusPending,
+		Strategy: []byte(`{"itemIds":[4151]}`),
+	}
+	require.NoError(t, repo.Create(ctx, job))
+
+	missing, err := repo.GetByID(ctx, uuid.New().String())
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	started := time.Now().UTC()
+	job.Status = models.BacktestStatusCompleted
+	job.StartedAt = &started
+	job.CompletedAt = &started
+	job.Result = []byte(`{"trades":[],"summary":{"trades":0}}`)
+	require.NoError(t, repo.Update(ctx, job))
+
+	stored, err := repo.GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, models.BacktestStatusCompleted, stored.Status)
+	assert.JSONEq(t, `{"trades":[],"summary":{"trades":0}}`, string(stored.Result))
+	require.NotNil(t, stored.CompletedAt)
+
+	running := &models.Backtest{
+		ID:       uuid.New().String(),
+		Status:   models.BacktestStatusRunning,
+		Strategy: []byte(`{"itemIds":[11802]}`),
+	}
+	require.NoError(t, repo.Create(ctx, running))
+
+	failed, err := repo.FailUnfinished(ctx, "interrupted")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), failed)
+
+	list, err := repo.List(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	for _, b := range list {
+		assert.Empty(t, b.Result, "list omits results")
+		if b.ID == running.ID {
+			assert.Equal(t, models.BacktestStatusFailed, b.Status)
+			require.NotNil(t, b.Error)
+			assert.Equal(t, "interrupted", *b.Error)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+//go:build slow
+// +build slow
+
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+func TestBankSnapshotRepository_Lifecycle(t *testing.T) {
+	dbClient := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	repo := repository.NewBankSnapshotRepository(dbClient, logger.Sugar())
+	ctx := context.Background()
+
+	snapshot := &models.BankSnapshot{
+		UserID: "alice",
+		Name:   "Main",
+		Items: []models.BankSnapshotItem{
+			{ItemID: 4151, Quantity: 2},
+			{ItemID: 995, Quantity: 1000},
+		},
+	}
+	created, err := repo.Create(ctx, snapshot, 2)
+	require.NoError(t, err)
+	require.True(t, created)
+	require.NotZero(t, snapshot.ID)
+
+	created, err = repo.Create(ctx, &models.BankSnapshot{UserID: "alice", Name: "Alt"}, 2)
+	require.NoError(t, err)
+	require.True(t, created)
+	// The per-user limit is reached.
+	created, err = repo.Create(ctx, &models.BankSnapshot{UserID: "alice", Name: "Third"}, 2)
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	list, err := repo.List(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "Alt", list[0].Name)
+	assert.Equal(t, 2, list[1].ItemCount)
+	assert.Empty(t, list[1].Items)
+
+	got, err := repo.Get(ctx, "alice", snapshot.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, 2, got.ItemCount)
+	require.Len(t, got.Items, 2)
+	assert.Equal(t, 995, got.Items[0].ItemID)
+
+	other, err := repo.Get(ctx, "bob", snapshot.ID)
+	require.NoError(t, err)
+	assert.Nil(t, other)
+
+	all, err := repo.ListAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Len(t, all[0].Items, 2)
+
+	// A second value on the same day replaces the first.
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	value := models.BankSnapshotValue{SnapshotID: snapshot.ID, ValuedOn: day, RecordedAt: day.Add(30 * time.Minute), TotalMid: 100}
+	require.NoError(t, repo.UpsertValues(ctx, []models.BankSnapshotValue{value}))
+	value.TotalMid = 200
+	require.NoError(t, repo.UpsertValues(ctx, []models.BankSnapshotValue{value}))
+	value.ValuedOn = day.Add(24 * time.Hour)
+	require.NoError(t, repo.UpsertValues(ctx, []models.BankSnapshotValue{value}))
+
+	values, err := repo.ListValues(ctx, snapshot.ID, day, day)
+	require.NoError(t, err)
+	require.Len(t, values, 1)
+	assert.Equal(t, int64(200), values[0].TotalMid)
+
+	deleted, err := repo.Delete(ctx, "alice", snapshot.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	values, err = repo.ListValues(ctx, snapshot.ID, day, day.Add(48*time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, values)
+}
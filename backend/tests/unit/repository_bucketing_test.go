@@ -0,0 +1,155 @@
+//go:build slow
+// +build slow
+
+package unit
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
+	"github.com/guavi/osrs-ge-tracker/tests/testutil"
+)
+
+// seed5mSeries inserts `days` of 5m points ending now for itemID, with a
+// single high spike and low dip in the middle of the range.
+func seed5mSeries(tb testing.TB, dbClient *gorm.DB, itemID int, days int) (spikeHigh, dipLow int64) {
+	tb.Helper()
+	ctx := context.Background()
+	logger := zap.NewNop().Sugar()
+
+	require.NoError(tb, repository.NewItemRepository(dbClient, logger).Create(ctx,
+		&models.Item{ItemID: itemID, Name: "Bucketed Item"}))
+
+	end := time.Now().UTC().Truncate(5 * time.Minute)
+	count := days * 288
+	points := make([]models.PriceTimeseriesPoint, count)
+	for i := range points {
+		high := int64(1000 + i%7)
+		low := int64(900 + i%5)
+		switch i {
+		case count/2 + 1:
+			high = 50000
+		case count/2 + 3:
+			low = 10
+		}
+		points[i] = models.PriceTimeseriesPoint{
+			ItemID:          itemID,
+			Timestamp:       end.Add(-time.Duration(count-1-i) * 5 * time.Minute),
+			AvgHighPrice:    &high,
+			AvgLowPrice:     &low,
+			HighPriceVolume: int64(i % 50),
+			LowPriceVolume:  int64(i % 40),
+		}
+	}
+	require.NoError(tb, repository.NewPriceRepository(dbClient, logger).InsertTimeseriesPoints(ctx, "5m", points))
+	return 50000, 10
+}
+
+func TestPriceRepository_GetTimeseriesPoints_SQLBucketing(t *testing.T) {
+	dbClient := setupTestDB(t)
+	priceRepo := repository.NewPriceRepository(dbClient, zap.NewNop().Sugar())
+	ctx := context.Background()
+
+	spikeHigh, dipLow := seed5mSeries(t, dbClient, 4151, 30)
+	maxPoints := 100
+
+	for _, mode := range []utils.SamplingMode{
+		utils.SamplingVoronoi, utils.SamplingLTTB, utils.SamplingMinMax, utils.SamplingAverage,
+	} {
+		t.Run(string(mode), func(t *testing.T) {
+			points, err := priceRepo.GetTimeseriesPoints(ctx, 4151, "5m", models.PriceHistoryParams{
+				Period:    models.Period30Days,
+				MaxPoints: &maxPoints,
+				Sampling:  mode,
+			})
+			require.NoError(t, err)
+			require.NotEmpty(t, points)
+			assert.LessOrEqual(t, len(points), maxPoints)
+			assert.Greater(t, len(points), maxPoints/2, "buckets should cover the whole range")
+
+			for i := 1; i < len(points); i++ {
+				assert.True(t, points[i].Timestamp.Before(points[i-1].Timestamp), "newest first")
+			}
+			for _, p := range points {
+				assert.NotNil(t, p.AvgHighPrice)
+				assert.NotNil(t, p.AvgLowPrice)
+			}
+
+			if mode == utils.SamplingMinMax || mode == utils.SamplingLTTB {
+				maxHigh, minLow := extremes(points)
+				assert.Equal(t, spikeHigh, maxHigh)
+				assert.Equal(t, dipLow, minLow)
+			}
+		})
+	}
+
+	// Small ranges stay on the in-memory path.
+	points, err := priceRepo.GetTimeseriesPoints(ctx, 4151, "5m", models.PriceHistoryParams{
+		Period:    models.Period24Hours,
+		MaxPoints: &maxPoints,
+	})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(points), maxPoints)
+	assert.True(t, slices.IsSortedFunc(points, func(a, b models.PriceTimeseriesPoint) int {
+		return b.Timestamp.Compare(a.Timestamp)
+	}))
+}
+
+// benchmarkYearOf5m seeds a year of 5m rows (105,120 points) for one item.
+func benchmarkYearOf5m(b *testing.B) repository.PriceRepository {
+	b.Helper()
+	dbClient, release := testutil.SharedPostgres(b)
+	b.Cleanup(release)
+	seed5mSeries(b, dbClient, 4151, 365)
+	return repository.NewPriceRepository(dbClient, zap.NewNop().Sugar())
+}
+
+// BenchmarkTimeseriesSampling_InMemory loads the full year and samples in Go.
+func BenchmarkTimeseriesSampling_InMemory(b *testing.B) {
+	priceRepo := benchmarkYearOf5m(b)
+	ctx := context.Background()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		points, err := priceRepo.GetTimeseriesPoints(ctx, 4151, "5m", models.PriceHistoryParams{Period: models.Period1Year})
+		if err != nil {
+			b.Fatal(err)
+		}
+		slices.Reverse(points)
+		_ = repository.SampleTimeseriesPoints(points, 120)
+	}
+}
+
+// BenchmarkTimeseriesSampling_SQL buckets the same year in Postgres.
+func BenchmarkTimeseriesSampling_SQL(b *testing.B) {
+	priceRepo := benchmarkYearOf5m(b)
+	ctx := context.Background()
+	maxPoints := 120
+
+	for _, mode := range []utils.SamplingMode{
+		utils.SamplingVoronoi, utils.SamplingLTTB, utils.SamplingMinMax, utils.SamplingAverage,
+	} {
+		b.Run(string(mode), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, err := priceRepo.GetTimeseriesPoints(ctx, 4151, "5m", models.PriceHistoryParams{
+					Period:    models.Period1Year,
+					MaxPoints: &maxPoints,
+					Sampling:  mode,
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
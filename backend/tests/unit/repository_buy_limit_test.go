@@ -0,0 +1,71 @@
+//go:build slow
+// +build slow
+
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+func TestBuyLimitRepository_WindowLifecycle(t *testing.T) {
+	dbClient := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	repo := repository.NewBuyLimitRepository(dbClient, logger.Sugar())
+	ctx := context.Background()
+
+	start := time.Now().UTC().Truncate(time.Second).Add(-5 * time.Hour)
+	record := func(quantity int64, at time.Time) *models.BuyLimitTimer {
+		timer, err := repo.RecordPurchase(ctx, &models.BuyLimitPurchase{UserID: "alice", ItemID: 4151, Quantity: quantity, PurchasedAt: at})
+		require.NoError(t, err)
+		return timer
+	}
+
+	// The first purchase opens the window and later ones add to it.
+	timer := record(40, start)
+	assert.Equal(t, int64(40), timer.Bought)
+	timer = record(20, start.Add(time.Hour))
+	assert.Equal(t, int64(60), timer.Bought)
+	assert.True(t, timer.WindowStart.Equal(start))
+	assert.True(t, timer.ResetsAt.Equal(start.Add(models.BuyLimitWindow)))
+
+	// The window closed an hour ago; it is claimed exactly once.
+	resets, err := repo.ClaimResets(ctx, time.Now().UTC())
+	require.NoError(t, err)
+	require.Len(t, resets, 1)
+	assert.Equal(t, "alice", resets[0].UserID)
+	resets, err = repo.ClaimResets(ctx, time.Now().UTC())
+	require.NoError(t, err)
+	assert.Empty(t, resets)
+
+	// A purchase after the reset opens a fresh window.
+	later := start.Add(4*time.Hour + 30*time.Minute)
+	timer = record(5, later)
+	assert.Equal(t, int64(5), timer.Bought)
+	assert.True(t, timer.WindowStart.Equal(later))
+	assert.False(t, timer.Notified)
+
+	purchases, err := repo.ListPurchases(ctx, "alice", 4151, later)
+	require.NoError(t, err)
+	require.Len(t, purchases, 1)
+
+	deleted, err := repo.DeletePurchase(ctx, "bob", purchases[0].ID)
+	require.NoError(t, err)
+	assert.False(t, deleted, "users cannot delete each other's purchases")
+
+	deleted, err = repo.DeletePurchase(ctx, "alice", purchases[0].ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	stored, err := repo.GetTimer(ctx, "alice", 4151)
+	require.NoError(t, err)
+	assert.Nil(t, stored, "an emptied window is removed")
+}
@@ -148,6 +148,35 @@ func TestItemRepository_Count_EmptyDatabase(t *testing.T) {
 	assert.GreaterOrEqual(t, count, int64(0))
 }
 
+func TestItemRepository_GetByItemIDsAndNames(t *testing.T) {
+	dbClient := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	repo := repository.NewItemRepository(dbClient, logger.Sugar())
+
+	ctx := context.Background()
+
+	items := []models.Item{
+		{ItemID: 501, Name: "Cabbage"},
+		{ItemID: 502, Name: "Shark"},
+		{ItemID: 503, Name: "Cabbage"},
+	}
+	require.NoError(t, repo.BulkUpsert(ctx, items))
+
+	byID, err := repo.GetByItemIDs(ctx, []int{502, 503, 99999})
+	require.NoError(t, err)
+	assert.Len(t, byID, 2)
+
+	byName, err := repo.GetByNames(ctx, []string{"cabbage", "Unknown"})
+	require.NoError(t, err)
+	require.Len(t, byName, 2)
+	assert.Equal(t, 501, byName[0].ItemID)
+	assert.Equal(t, 503, byName[1].ItemID)
+
+	none, err := repo.GetByItemIDs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
 func TestItemRepository_BulkUpsert_Success(t *testing.T) {
 	dbClient := setupTestDB(t)
 	logger, _ := zap.NewDevelopment()
@@ -0,0 +1,110 @@
+//go:build slow
+// +build slow
+
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+func TestItemAliasRepository(t *testing.T) {
+	dbClient := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	itemRepo := repository.NewItemRepository(dbClient, logger.Sugar())
+	aliasRepo := repository.NewItemAliasRepository(dbClient, logger.Sugar())
+	ctx := context.Background()
+
+	require.NoError(t, itemRepo.BulkUpsert(ctx, []models.Item{
+		{ItemID: 11804, Name: "Bandos godsword", Members: true},
+		{ItemID: 11806, Name: "Saradomin godsword", Members: true},
+		{ItemID: 12934, Name: "Zulrah's scales", Members: true},
+	}))
+
+	seed := []models.ItemAlias{
+		{Alias: "bgs", ItemID: 11804, Status: models.ItemAliasStatusApproved, Source: models.ItemAliasSourceSeed},
+		{Alias: "sgs", ItemID: 11806, Status: models.ItemAliasStatusApproved, Source: models.ItemAliasSourceSeed},
+	}
+	added, err := aliasRepo.InsertMissing(ctx, seed)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), added)
+	added, err = aliasRepo.InsertMissing(ctx, seed)
+	require.NoError(t, err)
+	assert.Zero(t, added)
+
+	alice := "alice"
+	submitted := &models.ItemAlias{Alias: "bandos gs", ItemID: 11804, Status: models.ItemAliasStatusPending,
+		Source: models.ItemAliasSourceUser, SubmittedBy: &alice}
+	require.NoError(t, aliasRepo.Create(ctx, submitted, 1))
+	assert.NotZero(t, submitted.ID)
+
+	err = aliasRepo.Create(ctx, &models.ItemAlias{Alias: "bandos sword", ItemID: 11804, Status: models.ItemAliasStatusPending,
+		Source: models.ItemAliasSourceUser, SubmittedBy: &alice}, 1)
+	assert.ErrorIs(t, err, models.ErrItemAliasLimit)
+
+	err = aliasRepo.Create(ctx, &models.ItemAlias{Alias: "bgs", ItemID: 11806, Status: models.ItemAliasStatusApproved,
+		Source: models.ItemAliasSourceAdmin}, 0)
+	assert.ErrorIs(t, err, models.ErrItemAliasTaken)
+
+	pending, err := aliasRepo.List(ctx, models.ItemAliasStatusPending, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "bandos gs", pending[0].Alias)
+
+	found, err := aliasRepo.FindApproved(ctx, []string{"bgs", "bandos gs", "nope"})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, 11804, found[0].ItemID)
+
+	reviewed, err := aliasRepo.SetStatus(ctx, submitted.ID, models.ItemAliasStatusApproved)
+	require.NoError(t, err)
+	require.NotNil(t, reviewed)
+	assert.Equal(t, models.ItemAliasStatusApproved, reviewed.Status)
+	assert.NotNil(t, reviewed.ReviewedAt)
+
+	forItem, err := aliasRepo.ListForItem(ctx, 11804)
+	require.NoError(t, err)
+	assert.Len(t, forItem, 2)
+
+	approved, err := aliasRepo.ListApproved(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, approved)
+	for _, alias := range approved {
+		assert.Equal(t, models.ItemAliasStatusApproved, alias.Status)
+	}
+
+	missing, err := aliasRepo.SetStatus(ctx, 999999, models.ItemAliasStatusApproved)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	// Aliases take part in search.
+	results, _, err := itemRepo.Search(ctx, models.ItemSearchParams{Query: "BGS", Limit: 10})
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, 11804, results[0].ItemID)
+
+	results, _, err = itemRepo.Search(ctx, models.ItemSearchParams{Query: "sgs", Mode: models.ItemSearchModeExact})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, 11806, results[0].ItemID)
+
+	bySlug, err := itemRepo.GetBySlugs(ctx, []string{"zulrahs-scales", "bandos-godsword"})
+	require.NoError(t, err)
+	require.Len(t, bySlug, 2)
+	assert.Equal(t, 11804, bySlug[0].ItemID)
+	assert.Equal(t, 12934, bySlug[1].ItemID)
+
+	deleted, err := aliasRepo.Delete(ctx, submitted.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	deleted, err = aliasRepo.Delete(ctx, submitted.ID)
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
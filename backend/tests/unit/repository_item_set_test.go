@@ -0,0 +1,47 @@
+//go:build slow
+// +build slow
+
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+func TestItemSetRepository_Overrides(t *testing.T) {
+	dbClient := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	repo := repository.NewItemSetRepository(dbClient, logger.Sugar())
+	ctx := context.Background()
+
+	override := &models.ItemSetOverride{
+		SetItemID:  12881,
+		Name:       "Ahrim's armour set",
+		Components: []byte(`[{"itemId":4708,"quantity":1},{"itemId":4710,"quantity":1}]`),
+	}
+	require.NoError(t, repo.UpsertOverride(ctx, override))
+
+	override.Removed = true
+	require.NoError(t, repo.UpsertOverride(ctx, override))
+
+	overrides, err := repo.ListOverrides(ctx)
+	require.NoError(t, err)
+	require.Len(t, overrides, 1)
+	assert.True(t, overrides[0].Removed)
+	assert.JSONEq(t, `[{"itemId":4708,"quantity":1},{"itemId":4710,"quantity":1}]`, string(overrides[0].Components))
+
+	deleted, err := repo.DeleteOverride(ctx, 12881)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	deleted, err = repo.DeleteOverride(ctx, 12881)
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
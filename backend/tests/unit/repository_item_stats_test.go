@@ -0,0 +1,87 @@
+//go:build slow
+// +build slow
+
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+func TestItemStatsRepository(t *testing.T) {
+	dbClient := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	repo := repository.NewItemStatsRepository(dbClient, logger.Sugar())
+	ctx := context.Background()
+
+	updatedAt, err := repo.LastUpdated(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, updatedAt)
+
+	first := time.Now()
+	require.NoError(t, repo.Replace(ctx, []models.ItemStats{
+		{ItemID: 1, Name: "Removed item"},
+	}, first))
+
+	second := first.Add(time.Minute)
+	require.NoError(t, repo.Replace(ctx, []models.ItemStats{
+		{ItemID: 4151, Name: "Abyssal whip", Members: true, Category: "weapons", HighPrice: i64(1_500_000), Volume24h: 9_000, Change24h: f64(2.5)},
+		{ItemID: 1333, Name: "Rune scimitar", Category: "weapons", HighPrice: i64(15_000), Volume24h: 20_000, Change24h: f64(-1)},
+		{ItemID: 2, Name: "Cannonball_x", HighPrice: nil, Volume24h: 50},
+	}, second))
+
+	updatedAt, err = repo.LastUpdated(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, updatedAt)
+	assert.WithinDuration(t, second, *updatedAt, time.Millisecond)
+
+	ids := func(rows []models.ItemStats) []int {
+		out := make([]int, len(rows))
+		for i, row := range rows {
+			out[i] = row.ItemID
+		}
+		return out
+	}
+
+	rows, total, err := repo.Query(ctx, models.MarketTableParams{SortBy: "volume_24h", Order: "desc", Limit: 50})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total, "rows from earlier refreshes are removed")
+	assert.Equal(t, []int{1333, 4151, 2}, ids(rows))
+
+	rows, _, err = repo.Query(ctx, models.MarketTableParams{SortBy: "high_price", Order: "asc", Limit: 50})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1333, 4151, 2}, ids(rows), "unknown values sort last")
+
+	rows, total, err = repo.Query(ctx, models.MarketTableParams{SortBy: "name", Order: "asc", Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, []int{1333}, ids(rows))
+
+	rows, total, err = repo.Query(ctx, models.MarketTableParams{
+		SortBy:   "change_24h",
+		Order:    "desc",
+		Limit:    50,
+		Category: "weapons",
+		Min:      map[string]float64{"high_price": 10_000},
+		Max:      map[string]float64{"volume_24h": 10_000},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []int{4151}, ids(rows))
+
+	members := false
+	rows, _, err = repo.Query(ctx, models.MarketTableParams{SortBy: "item_id", Order: "asc", Limit: 50, Members: &members, Query: "_X"})
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, ids(rows), "LIKE wildcards in q match literally")
+
+	_, _, err = repo.Query(ctx, models.MarketTableParams{SortBy: "name; DROP TABLE items", Limit: 50})
+	assert.Error(t, err)
+}
@@ -0,0 +1,100 @@
+//go:build slow
+// +build slow
+
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+func TestItemTagRepository(t *testing.T) {
+	dbClient := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	itemRepo := repository.NewItemRepository(dbClient, logger.Sugar())
+	tagRepo := repository.NewItemTagRepository(dbClient, logger.Sugar())
+	ctx := context.Background()
+
+	require.NoError(t, itemRepo.BulkUpsert(ctx, []models.Item{
+		{ItemID: 257, Name: "Ranarr weed", Members: true},
+		{ItemID: 4151, Name: "Abyssal whip", Members: true},
+	}))
+
+	auto := func(itemID int, tag, kind string) models.ItemTag {
+		return models.ItemTag{ItemID: itemID, Tag: tag, Kind: kind, Source: models.ItemTagSourceAuto}
+	}
+	require.NoError(t, tagRepo.ReplaceAutoTags(ctx, []models.ItemTag{
+		auto(257, "herb", models.ItemTagKindCategory),
+		auto(257, "members", models.ItemTagKindTag),
+		auto(4151, "armour", models.ItemTagKindCategory),
+		auto(4151, "members", models.ItemTagKindTag),
+	}))
+
+	// Admin corrections: whip is a weapon, not armour.
+	removed, err := tagRepo.Remove(ctx, 4151, "armour")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	require.NoError(t, tagRepo.Upsert(ctx, &models.ItemTag{
+		ItemID: 4151, Tag: "weapon", Kind: models.ItemTagKindCategory, Source: models.ItemTagSourceAdmin,
+	}))
+
+	removed, err = tagRepo.Remove(ctx, 4151, "armour")
+	require.NoError(t, err)
+	assert.False(t, removed, "already removed")
+
+	// Reclassifying keeps the corrections.
+	require.NoError(t, tagRepo.ReplaceAutoTags(ctx, []models.ItemTag{
+		auto(257, "herb", models.ItemTagKindCategory),
+		auto(4151, "armour", models.ItemTagKindCategory),
+	}))
+	tags, err := tagRepo.ListForItem(ctx, 4151)
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+	for _, tag := range tags {
+		assert.Equal(t, models.ItemTagSourceAdmin, tag.Source)
+		assert.Equal(t, tag.Tag == "armour", tag.Removed)
+	}
+
+	counts, err := tagRepo.CountTags(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []models.ItemTagCount{
+		{Tag: "herb", Kind: models.ItemTagKindCategory, Items: 1},
+		{Tag: "weapon", Kind: models.ItemTagKindCategory, Items: 1},
+	}, counts)
+
+	items, total, err := itemRepo.GetAll(ctx, models.ItemListParams{Category: "weapon"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, items, 1)
+	assert.Equal(t, "weapon", items[0].Category)
+
+	items, total, err = itemRepo.GetAll(ctx, models.ItemListParams{Category: "armour"})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, items)
+
+	items, _, err = itemRepo.Search(ctx, models.ItemSearchParams{Query: "ranarr", Category: "herb"})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, 257, items[0].ItemID)
+
+	// Batched lookups carry tags; the single-item lookup skips that query.
+	items, err = itemRepo.GetByItemIDs(ctx, []int{257, 4151})
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	for _, item := range items {
+		assert.NotEmpty(t, item.Category, "item %d", item.ItemID)
+	}
+	whip, err := itemRepo.GetByItemID(ctx, 4151)
+	require.NoError(t, err)
+	require.NotNil(t, whip)
+	assert.Empty(t, whip.Category)
+	assert.Empty(t, whip.Tags)
+}
@@ -0,0 +1,74 @@
+//go:build slow
+// +build slow
+
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+func TestMarketIndexRepository_CustomIndices(t *testing.T) {
+	dbClient := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	repo := repository.NewMarketIndexRepository(dbClient, logger.Sugar())
+	ctx := context.Background()
+
+	first := &models.CustomMarketIndex{UserID: "alice", Name: "Mine", Weighting: "volume", ItemIDs: []byte(`[554, 555]`)}
+	created, err := repo.CreateCustom(ctx, first, 1)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.NotZero(t, first.ID)
+
+	created, err = repo.CreateCustom(ctx, &models.CustomMarketIndex{
+		UserID: "alice", Name: "Another", Weighting: "equal", ItemIDs: []byte(`[1, 2]`),
+	}, 1)
+	require.NoError(t, err)
+	assert.False(t, created, "the per-user limit applies")
+
+	index, err := repo.GetCustom(ctx, "alice", first.ID)
+	require.NoError(t, err)
+	require.NotNil(t, index)
+	assert.JSONEq(t, `[554, 555]`, string(index.ItemIDs))
+
+	other, err := repo.GetCustom(ctx, "bob", first.ID)
+	require.NoError(t, err)
+	assert.Nil(t, other)
+
+	t0 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	indexID := models.CustomMarketIndexID(first.ID)
+	require.NoError(t, repo.UpsertValues(ctx, []models.MarketIndexValue{
+		{IndexID: indexID, Timestamp: t0, Value: 1000, Constituents: 2},
+		{IndexID: indexID, Timestamp: t0.Add(time.Hour), Value: 1010, Constituents: 2},
+	}))
+	// Recomputing a bucket replaces it.
+	require.NoError(t, repo.UpsertValues(ctx, []models.MarketIndexValue{
+		{IndexID: indexID, Timestamp: t0.Add(time.Hour), Value: 1020, Constituents: 1},
+	}))
+
+	latest, err := repo.LatestValue(ctx, indexID)
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	assert.InDelta(t, 1020, latest.Value, 1e-9)
+	assert.Equal(t, 1, latest.Constituents)
+
+	values, err := repo.ListValues(ctx, indexID, t0, t0.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, values, 2)
+
+	deleted, err := repo.DeleteCustom(ctx, "alice", first.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	latest, err = repo.LatestValue(ctx, indexID)
+	require.NoError(t, err)
+	assert.Nil(t, latest, "deleting an index removes its values")
+}
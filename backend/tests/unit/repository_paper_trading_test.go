@@ -0,0 +1,130 @@
+//go:build slow
+// +build slow
+
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+func TestPaperTradingRepository_OfferLifecycle(t *testing.T) {
+	dbClient := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	repo := repository.NewPaperTradingRepository(dbClient, logger.Sugar())
+	ctx := context.Background()
+
+	account := &models.PaperAccount{
+		ID:              uuid.New().String(),
+		UserID:          "alice",
+		Name:            "alice",
+		StartingBalance: 10_000_000,
+		Cash:            10_000_000,
+	}
+	created, err := repo.CreateAccount(ctx, account, 1)
+	require.NoError(t, err)
+	require.True(t, created)
+
+	// alice is at the limit of one account.
+	created, err = repo.CreateAccount(ctx, &models.PaperAccount{
+		ID: uuid.New().String(), UserID: "alice", Name: "second", StartingBalance: 1, Cash: 1,
+	}, 1)
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	missing, err := repo.GetAccount(ctx, uuid.New().String())
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	// Buying holds the gold; more than the account has is refused.
+	buy := &models.PaperOffer{AccountID: account.ID, ItemID: 4151, Side: models.PaperOfferBuy, Status: models.PaperOfferOpen, Price: 100_000, Quantity: 80}
+	require.NoError(t, repo.PlaceOffer(ctx, buy))
+	tooBig := &models.PaperOffer{AccountID: account.ID, ItemID: 4151, Side: models.PaperOfferBuy, Status: models.PaperOfferOpen, Price: 100_000, Quantity: 50}
+	assert.ErrorIs(t, repo.PlaceOffer(ctx, tooBig), models.ErrPaperInsufficientFunds)
+
+	stored, err := repo.GetAccount(ctx, account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2_000_000), stored.Cash)
+
+	// Nothing to sell yet.
+	sell := &models.PaperOffer{AccountID: account.ID, ItemID: 4151, Side: models.PaperOfferSell, Status: models.PaperOfferOpen, Price: 120_000, Quantity: 10}
+	assert.ErrorIs(t, repo.PlaceOffer(ctx, sell), models.ErrPaperInsufficientItems)
+
+	// The buy limit caps the first fill; the rest waits for the next window.
+	filledAt := time.Now().UTC().Truncate(time.Second)
+	fill, err := repo.FillOffer(ctx, buy.ID, filledAt, 70)
+	require.NoError(t, err)
+	require.NotNil(t, fill)
+	assert.Equal(t, int64(70), fill.Quantity)
+	assert.Equal(t, models.PaperOfferOpen, fill.OfferStatus)
+
+	fill, err = repo.FillOffer(ctx, buy.ID, filledAt.Add(time.Hour), 70)
+	require.NoError(t, err)
+	assert.Nil(t, fill, "the buy limit is used up")
+
+	fill, err = repo.FillOffer(ctx, buy.ID, filledAt.Add(models.BuyLimitWindow), 70)
+	require.NoError(t, err)
+	require.NotNil(t, fill)
+	assert.Equal(t, int64(10), fill.Quantity)
+	assert.Equal(t, models.PaperOfferFilled, fill.OfferStatus)
+
+	// Selling reserves items, pays tax and credits the proceeds.
+	require.NoError(t, repo.PlaceOffer(ctx, sell))
+	fill, err = repo.FillOffer(ctx, sell.ID, filledAt.Add(5*time.Hour), 0)
+	require.NoError(t, err)
+	require.NotNil(t, fill)
+	assert.Equal(t, int64(10*2_400), fill.Tax)
+	require.NotNil(t, fill.Profit)
+	assert.Equal(t, int64(10*(120_000-2_400-100_000)), *fill.Profit)
+
+	stored, err = repo.GetAccount(ctx, account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2_000_000+10*(120_000-2_400)), stored.Cash)
+
+	positions, err := repo.ListPositions(ctx, account.ID)
+	require.NoError(t, err)
+	require.Len(t, positions, 1)
+	assert.Equal(t, int64(70), positions[0].Quantity)
+	assert.Equal(t, int64(0), positions[0].Reserved)
+	assert.Equal(t, int64(7_000_000), positions[0].CostBasis)
+
+	// Cancelling a sell returns the reserved items; cancelling twice is refused.
+	resting := &models.PaperOffer{AccountID: account.ID, ItemID: 4151, Side: models.PaperOfferSell, Status: models.PaperOfferOpen, Price: 150_000, Quantity: 30}
+	require.NoError(t, repo.PlaceOffer(ctx, resting))
+	cancelled, err := repo.CancelOffer(ctx, account.ID, resting.ID)
+	require.NoError(t, err)
+	require.NotNil(t, cancelled)
+	assert.Equal(t, models.PaperOfferCancelled, cancelled.Status)
+	_, err = repo.CancelOffer(ctx, account.ID, resting.ID)
+	assert.ErrorIs(t, err, models.ErrPaperOfferClosed)
+
+	otherAccount, err := repo.CancelOffer(ctx, uuid.New().String(), resting.ID)
+	require.NoError(t, err)
+	assert.Nil(t, otherAccount)
+
+	positions, err = repo.ListPositions(ctx, account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), positions[0].Reserved)
+
+	open, err := repo.ListOpenOffersForItems(ctx, []int{4151})
+	require.NoError(t, err)
+	assert.Empty(t, open)
+
+	offers, err := repo.ListOffers(ctx, account.ID, "", 10)
+	require.NoError(t, err)
+	assert.Len(t, offers, 3)
+
+	fills, err := repo.ListFills(ctx, account.ID, 10)
+	require.NoError(t, err)
+	assert.Len(t, fills, 3)
+	assert.Equal(t, models.PaperOfferSell, fills[0].Side, "most recent first")
+}
@@ -0,0 +1,45 @@
+//go:build slow
+// +build slow
+
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+func TestRecipeRepository_Overrides(t *testing.T) {
+	dbClient := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	repo := repository.NewRecipeRepository(dbClient, logger.Sugar())
+	ctx := context.Background()
+
+	override := &models.RecipeOverride{
+		RecipeID:   "clean-ranarr",
+		Definition: []byte(`{"name":"Clean grimy ranarr","actionsPerHour":5000}`),
+	}
+	require.NoError(t, repo.UpsertOverride(ctx, override))
+
+	override.Definition = []byte(`{"name":"Clean ranarr","actionsPerHour":4000}`)
+	require.NoError(t, repo.UpsertOverride(ctx, override))
+
+	overrides, err := repo.ListOverrides(ctx)
+	require.NoError(t, err)
+	require.Len(t, overrides, 1)
+	assert.JSONEq(t, `{"name":"Clean ranarr","actionsPerHour":4000}`, string(overrides[0].Definition))
+
+	deleted, err := repo.DeleteOverride(ctx, "clean-ranarr")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	deleted, err = repo.DeleteOverride(ctx, "clean-ranarr")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
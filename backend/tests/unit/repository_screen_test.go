@@ -0,0 +1,77 @@
+//go:build slow
+// +build slow
+
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+func TestScreenRepository(t *testing.T) {
+	dbClient := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	repo := repository.NewScreenRepository(dbClient, logger.Sugar())
+	ctx := context.Background()
+
+	first := &models.Screen{UserID: "alice", Name: "Flips", Expression: "margin > 50k", Alert: true}
+	created, err := repo.Create(ctx, first, 1)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.NotZero(t, first.ID)
+
+	created, err = repo.Create(ctx, &models.Screen{UserID: "alice", Name: "More", Expression: "members"}, 1)
+	require.NoError(t, err)
+	assert.False(t, created, "the per-user limit applies")
+
+	other, err := repo.Get(ctx, "bob", first.ID)
+	require.NoError(t, err)
+	assert.Nil(t, other)
+
+	alerting, err := repo.ListAlerting(ctx)
+	require.NoError(t, err)
+	require.Len(t, alerting, 1)
+	assert.Nil(t, alerting[0].AlertItemIDs)
+
+	alertedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.SetAlertState(ctx, first.ID, []int{4151, 11802}, &alertedAt))
+	screen, err := repo.Get(ctx, "alice", first.ID)
+	require.NoError(t, err)
+	require.NotNil(t, screen)
+	assert.JSONEq(t, `[4151, 11802]`, string(screen.AlertItemIDs))
+	require.NotNil(t, screen.LastAlertAt)
+	assert.True(t, alertedAt.Equal(*screen.LastAlertAt))
+
+	// Editing a screen clears its alert state but keeps when it last alerted.
+	updated, err := repo.Update(ctx, &models.Screen{ID: first.ID, UserID: "alice", Name: "Big flips", Expression: "margin > 1m", Alert: true})
+	require.NoError(t, err)
+	assert.True(t, updated)
+	screen, err = repo.Get(ctx, "alice", first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Big flips", screen.Name)
+	assert.Nil(t, screen.AlertItemIDs)
+	assert.NotNil(t, screen.LastAlertAt)
+
+	updated, err = repo.Update(ctx, &models.Screen{ID: first.ID, UserID: "bob", Name: "Mine", Expression: "members"})
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	screens, err := repo.List(ctx, "alice")
+	require.NoError(t, err)
+	assert.Len(t, screens, 1)
+
+	deleted, err := repo.Delete(ctx, "alice", first.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	deleted, err = repo.Delete(ctx, "alice", first.ID)
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
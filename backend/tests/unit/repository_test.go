@@ -122,6 +122,73 @@ func TestItemRepository_Search(t *testing.T) {
 	assert.Equal(t, "Abyssal whip", results[0].Name)
 }
 
+func TestItemRepository_SearchRanking(t *testing.T) {
+	dbClient := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	repo := repository.NewItemRepository(dbClient, logger.Sugar())
+
+	ctx := context.Background()
+
+	require.NoError(t, repo.BulkUpsert(ctx, []models.Item{
+		{ItemID: 536, Name: "Dragon bones", Members: true},
+		{ItemID: 534, Name: "Babydragon bones", Members: true},
+		{ItemID: 4151, Name: "Abyssal whip", Members: true},
+		{ItemID: 12006, Name: "Abyssal tentacle", Members: true},
+		{ItemID: 4587, Name: "Dragon scimitar", Members: true},
+		{ItemID: 1305, Name: "Dragon longsword", Members: true},
+		{ItemID: 100, Name: "100%_real", Members: false},
+	}))
+
+	// Longsword trades far more than scimitar, so it ranks first on a shared prefix.
+	require.NoError(t, dbClient.Exec(`
+		INSERT INTO price_timeseries_1h (item_id, timestamp, high_price_volume, low_price_volume)
+		VALUES (1305, ?, 500000, 500000)`, time.Now().Add(-time.Hour)).Error)
+
+	search := func(params models.ItemSearchParams) []models.Item {
+		t.Helper()
+		params.Limit = 10
+		results, total, err := repo.Search(ctx, params)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(results)), total)
+		for _, item := range results {
+			require.NotNil(t, item.SearchScore, item.Name)
+			assert.True(t, *item.SearchScore > 0 && *item.SearchScore <= 1, item.Name)
+		}
+		return results
+	}
+
+	results := search(models.ItemSearchParams{Query: "dragn bones"})
+	require.NotEmpty(t, results)
+	assert.Equal(t, "Dragon bones", results[0].Name, "typos still match")
+
+	results = search(models.ItemSearchParams{Query: "whip"})
+	require.Len(t, results, 1)
+	assert.Equal(t, "Abyssal whip", results[0].Name)
+
+	results = search(models.ItemSearchParams{Query: "dragon", Mode: models.ItemSearchModePrefix})
+	require.Len(t, results, 3)
+	assert.Equal(t, "Dragon longsword", results[0].Name)
+	for _, item := range results {
+		assert.NotEqual(t, "Babydragon bones", item.Name)
+	}
+
+	results = search(models.ItemSearchParams{Query: "DRAGON BONES", Mode: models.ItemSearchModeExact})
+	require.Len(t, results, 1)
+	assert.Equal(t, 536, results[0].ItemID)
+
+	// LIKE wildcards in the query match literally.
+	results = search(models.ItemSearchParams{Query: "100%_", Mode: models.ItemSearchModePrefix})
+	require.Len(t, results, 1)
+	assert.Equal(t, 100, results[0].ItemID)
+	assert.Empty(t, search(models.ItemSearchParams{Query: "_", Mode: models.ItemSearchModePrefix}))
+
+	// Listing leaves the score unset.
+	listed, _, err := repo.GetAll(ctx, models.DefaultItemListParams())
+	require.NoError(t, err)
+	require.NotEmpty(t, listed)
+	assert.Nil(t, listed[0].SearchScore)
+}
+
 func TestItemRepository_GetAll(t *testing.T) {
 	dbClient := setupTestDB(t)
 	logger, _ := zap.NewDevelopment()
@@ -501,6 +501,97 @@ func TestPriceRepository_GetDailyPoints_WithPeriodFilter(t *testing.T) {
 	assert.Len(t, resultsAll, 10)
 }
 
+func TestPriceRepository_GetDailyPointsForItems(t *testing.T) {
+	dbClient := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	priceRepo := repository.NewPriceRepository(dbClient, logger.Sugar())
+	itemRepo := repository.NewItemRepository(dbClient, logger.Sugar())
+
+	ctx := context.Background()
+
+	for id, name := range map[int]string{114: "Daily Batch A", 115: "Daily Batch B", 116: "Daily Batch C"} {
+		require.NoError(t, itemRepo.Create(ctx, &models.Item{ItemID: id, Name: name}))
+	}
+
+	high := int64(5000)
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	var dailyPoints []models.PriceTimeseriesDaily
+	for _, id := range []int{114, 115, 116} {
+		for i := 0; i < 5; i++ {
+			dailyPoints = append(dailyPoints, models.PriceTimeseriesDaily{
+				ItemID:       id,
+				Day:          day.AddDate(0, 0, i),
+				AvgHighPrice: &high,
+			})
+		}
+	}
+	require.NoError(t, priceRepo.InsertDailyPoints(ctx, dailyPoints))
+
+	// Both ends are inclusive, and a time of day within the last day still covers it.
+	points, err := priceRepo.GetDailyPointsForItems(ctx, []int{116, 114}, day.AddDate(0, 0, 1), day.AddDate(0, 0, 3).Add(12*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, points, 6)
+	for i, p := range points {
+		expectedID := 114
+		if i >= 3 {
+			expectedID = 116
+		}
+		assert.Equal(t, expectedID, p.ItemID)
+		assert.True(t, p.Day.Equal(day.AddDate(0, 0, 1+i%3)), "point %d is on %s", i, p.Day)
+	}
+
+	points, err = priceRepo.GetDailyPointsForItems(ctx, nil, day, day)
+	require.NoError(t, err)
+	assert.Empty(t, points)
+}
+
+func TestPriceRepository_GetTimeseriesPointsForItems(t *testing.T) {
+	dbClient := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	priceRepo := repository.NewPriceRepository(dbClient, logger.Sugar())
+	itemRepo := repository.NewItemRepository(dbClient, logger.Sugar())
+
+	ctx := context.Background()
+
+	for id, name := range map[int]string{117: "Hourly Batch A", 118: "Hourly Batch B", 119: "Hourly Batch C"} {
+		require.NoError(t, itemRepo.Create(ctx, &models.Item{ItemID: id, Name: name}))
+	}
+
+	high := int64(5000)
+	hour := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	var points []models.PriceTimeseriesPoint
+	for _, id := range []int{117, 118, 119} {
+		for i := 0; i < 5; i++ {
+			points = append(points, models.PriceTimeseriesPoint{
+				ItemID:       id,
+				Timestamp:    hour.Add(time.Duration(i) * time.Hour),
+				AvgHighPrice: &high,
+			})
+		}
+	}
+	require.NoError(t, priceRepo.InsertTimeseriesPoints(ctx, "1h", points))
+
+	// Both ends are inclusive.
+	got, err := priceRepo.GetTimeseriesPointsForItems(ctx, []int{119, 117}, "1h", hour.Add(time.Hour), hour.Add(3*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, got, 6)
+	for i, p := range got {
+		expectedID := 117
+		if i >= 3 {
+			expectedID = 119
+		}
+		assert.Equal(t, expectedID, p.ItemID)
+		assert.True(t, p.Timestamp.Equal(hour.Add(time.Duration(1+i%3)*time.Hour)), "point %d is at %s", i, p.Timestamp)
+	}
+
+	got, err = priceRepo.GetTimeseriesPointsForItems(ctx, nil, "1h", hour, hour)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	_, err = priceRepo.GetTimeseriesPointsForItems(ctx, []int{117}, "2h", hour, hour)
+	assert.Error(t, err)
+}
+
 func TestPriceRepository_Rollup24hToDailyBefore(t *testing.T) {
 	dbClient := setupTestDB(t)
 	logger, _ := zap.NewDevelopment()
@@ -594,3 +685,92 @@ func TestPriceRepository_Rollup24hToDailyBefore_Idempotent(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, dailyPoints, 1)
 }
+
+// ========== GetPricesAt Tests ==========
+
+func TestPriceRepository_GetPricesAt(t *testing.T) {
+	dbClient := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	priceRepo := repository.NewPriceRepository(dbClient, logger.Sugar())
+	itemRepo := repository.NewItemRepository(dbClient, logger.Sugar())
+
+	ctx := context.Background()
+	require.NoError(t, itemRepo.Create(ctx, &models.Item{ItemID: 100, Name: "Test Item"}))
+	require.NoError(t, itemRepo.Create(ctx, &models.Item{ItemID: 200, Name: "Other Item"}))
+
+	high := int64(1000)
+	low := int64(900)
+	bucket := time.Date(2026, 3, 14, 17, 55, 0, 0, time.UTC)
+	require.NoError(t, priceRepo.InsertTimeseriesPoints(ctx, "5m", []models.PriceTimeseriesPoint{
+		{ItemID: 100, Timestamp: bucket, AvgHighPrice: &high, AvgLowPrice: &low},
+		{ItemID: 100, Timestamp: bucket.Add(-5 * time.Minute), AvgHighPrice: &low},
+	}))
+	require.NoError(t, priceRepo.InsertDailyPoints(ctx, []models.PriceTimeseriesDaily{
+		{ItemID: 200, Day: time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC), AvgLowPrice: &low},
+	}))
+
+	ts := time.Date(2026, 3, 14, 17, 59, 30, 0, time.UTC)
+
+	prices, err := priceRepo.GetPricesAt(ctx, []int{100, 200}, models.Resolution5m, ts)
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	assert.Equal(t, 100, prices[0].ItemID)
+	assert.Equal(t, bucket, prices[0].Timestamp)
+	assert.Equal(t, models.Resolution5m, prices[0].Resolution)
+	assert.Equal(t, high, *prices[0].HighPrice)
+
+	// Just past the bucket's coverage window.
+	prices, err = priceRepo.GetPricesAt(ctx, []int{100}, models.Resolution5m, bucket.Add(5*time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, prices)
+
+	prices, err = priceRepo.GetPricesAt(ctx, []int{100, 200}, models.ResolutionDaily, ts)
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	assert.Equal(t, 200, prices[0].ItemID)
+	assert.Equal(t, time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC), prices[0].Timestamp)
+}
+
+// ========== GetHourlySeries Tests ==========
+
+func TestPriceRepository_GetHourlySeries(t *testing.T) {
+	dbClient := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	priceRepo := repository.NewPriceRepository(dbClient, logger.Sugar())
+	itemRepo := repository.NewItemRepository(dbClient, logger.Sugar())
+
+	ctx := context.Background()
+	require.NoError(t, itemRepo.Create(ctx, &models.Item{ItemID: 100, Name: "Test Item"}))
+
+	p := func(v int64) *int64 { return &v }
+	hour := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+
+	// 5m data covers 12:00-12:59; the 1h table covers 10:00-12:00.
+	require.NoError(t, priceRepo.InsertTimeseriesPoints(ctx, "5m", []models.PriceTimeseriesPoint{
+		{ItemID: 100, Timestamp: hour, AvgHighPrice: p(110), AvgLowPrice: p(90), HighPriceVolume: 5, LowPriceVolume: 5},
+		{ItemID: 100, Timestamp: hour.Add(5 * time.Minute), AvgHighPrice: p(130), HighPriceVolume: 2},
+	}))
+	require.NoError(t, priceRepo.InsertTimeseriesPoints(ctx, "1h", []models.PriceTimeseriesPoint{
+		{ItemID: 100, Timestamp: hour.Add(-2 * time.Hour), AvgHighPrice: p(80), AvgLowPrice: p(60), HighPriceVolume: 7},
+		{ItemID: 100, Timestamp: hour.Add(-time.Hour), AvgLowPrice: p(70), LowPriceVolume: 3},
+		// Overlaps the 5m coverage and must be ignored.
+		{ItemID: 100, Timestamp: hour, AvgHighPrice: p(999), AvgLowPrice: p(999)},
+	}))
+
+	series, err := priceRepo.GetHourlySeries(ctx, 100, hour.Add(-90*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, series, 2)
+
+	assert.Equal(t, hour.Add(-time.Hour), series[0].Timestamp)
+	assert.InDelta(t, 70, series[0].MidPrice, 0.001)
+	assert.Equal(t, int64(3), series[0].Volume)
+	assert.Nil(t, series[0].HighPrice)
+	require.NotNil(t, series[0].LowPrice)
+	assert.InDelta(t, 70, *series[0].LowPrice, 0.001)
+
+	assert.Equal(t, hour, series[1].Timestamp)
+	assert.InDelta(t, 115, series[1].MidPrice, 0.001)
+	assert.Equal(t, int64(12), series[1].Volume)
+	require.NotNil(t, series[1].HighPrice)
+	assert.InDelta(t, 120, *series[1].HighPrice, 0.001)
+}
@@ -0,0 +1,62 @@
+//go:build slow
+// +build slow
+
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/repository"
+)
+
+func TestTradeJournalRepository_ScopedToUser(t *testing.T) {
+	dbClient := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+	repo := repository.NewTradeJournalRepository(dbClient, logger.Sugar())
+	ctx := context.Background()
+
+	start := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+	entries := []models.TradeJournalEntry{
+		{UserID: "alice", ItemID: 4151, Side: models.TradeSideBuy, Quantity: 10, Price: 1_500_000, TradedAt: start},
+		{UserID: "alice", ItemID: 4151, Side: models.TradeSideSell, Quantity: 5, Price: 1_600_000, TradedAt: start.Add(30 * time.Minute)},
+		{UserID: "alice", ItemID: 11802, Side: models.TradeSideBuy, Quantity: 1, Price: 20_000_000, TradedAt: start.Add(10 * time.Minute)},
+		{UserID: "bob", ItemID: 4151, Side: models.TradeSideBuy, Quantity: 1, Price: 1, TradedAt: start},
+	}
+	require.NoError(t, repo.CreateBatch(ctx, entries))
+
+	all, err := repo.ListAll(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, 11802, all[1].ItemID, "ListAll is in trade order")
+
+	itemID := 4151
+	page, total, err := repo.List(ctx, "alice", models.JournalListParams{ItemID: &itemID, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, page, 1)
+	assert.Equal(t, models.TradeSideSell, page[0].Side, "List is newest first")
+
+	from := start.Add(5 * time.Minute)
+	_, total, err = repo.List(ctx, "alice", models.JournalListParams{From: &from, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+
+	deleted, err := repo.Delete(ctx, "bob", all[0].ID)
+	require.NoError(t, err)
+	assert.False(t, deleted, "users cannot delete each other's entries")
+
+	deleted, err = repo.Delete(ctx, "alice", all[0].ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	remaining, err := repo.ListAll(ctx, "alice")
+	require.NoError(t, err)
+	assert.Len(t, remaining, 2)
+}
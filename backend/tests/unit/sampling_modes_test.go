@@ -0,0 +1,174 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+	"github.com/guavi/osrs-ge-tracker/internal/utils"
+)
+
+var testTimeseriesAccessor = utils.TimeAccessor[models.PriceTimeseriesPoint]{
+	GetTime: func(p models.PriceTimeseriesPoint) time.Time { return p.Timestamp },
+	GetHigh: func(p models.PriceTimeseriesPoint) *int64 { return p.AvgHighPrice },
+	GetLow:  func(p models.PriceTimeseriesPoint) *int64 { return p.AvgLowPrice },
+	SetHigh: func(p *models.PriceTimeseriesPoint, v *int64) { p.AvgHighPrice = v },
+	SetLow:  func(p *models.PriceTimeseriesPoint, v *int64) { p.AvgLowPrice = v },
+}
+
+// spikySeries returns 100 flat 5m points with a single high spike and a
+// single low dip placed between the Voronoi target times for 10 samples.
+func spikySeries() []models.PriceTimeseriesPoint {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := make([]models.PriceTimeseriesPoint, 100)
+	for i := range points {
+		high, low := int64(1000), int64(900)
+		switch i {
+		case 27:
+			high = 5000
+		case 62:
+			low = 100
+		}
+		points[i] = models.PriceTimeseriesPoint{
+			ItemID:       1,
+			Timestamp:    base.Add(time.Duration(i) * 5 * time.Minute),
+			AvgHighPrice: &high,
+			AvgLowPrice:  &low,
+		}
+	}
+	return points
+}
+
+func extremes(points []models.PriceTimeseriesPoint) (maxHigh, minLow int64) {
+	maxHigh, minLow = 0, 1<<62
+	for _, p := range points {
+		if p.AvgHighPrice != nil && *p.AvgHighPrice > maxHigh {
+			maxHigh = *p.AvgHighPrice
+		}
+		if p.AvgLowPrice != nil && *p.AvgLowPrice < minLow {
+			minLow = *p.AvgLowPrice
+		}
+	}
+	return maxHigh, minLow
+}
+
+func TestSample_SpikeRetention(t *testing.T) {
+	points := spikySeries()
+
+	voronoi := utils.Sample(points, 10, utils.SamplingVoronoi, testTimeseriesAccessor)
+	maxHigh, minLow := extremes(voronoi)
+	assert.Equal(t, int64(1000), maxHigh, "nearest-point sampling drops the spike")
+	assert.Equal(t, int64(900), minLow, "nearest-point sampling drops the dip")
+
+	lttb := utils.Sample(points, 10, utils.SamplingLTTB, testTimeseriesAccessor)
+	require.Len(t, lttb, 10)
+	assert.Equal(t, points[0].Timestamp, lttb[0].Timestamp, "first point is kept")
+	assert.Equal(t, points[99].Timestamp, lttb[9].Timestamp, "last point is kept")
+	maxHigh, minLow = extremes(lttb)
+	assert.Equal(t, int64(5000), maxHigh)
+	assert.Equal(t, int64(100), minLow)
+
+	minmax := utils.Sample(points, 10, utils.SamplingMinMax, testTimeseriesAccessor)
+	assert.LessOrEqual(t, len(minmax), 10)
+	maxHigh, minLow = extremes(minmax)
+	assert.Equal(t, int64(5000), maxHigh)
+	assert.Equal(t, int64(100), minLow)
+
+	for _, sampled := range [][]models.PriceTimeseriesPoint{lttb, minmax} {
+		for i := 1; i < len(sampled); i++ {
+			assert.True(t, sampled[i].Timestamp.After(sampled[i-1].Timestamp), "output stays in time order")
+		}
+	}
+}
+
+func TestSampleAverage(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := []models.PriceTimeseriesPoint{
+		{Timestamp: base, AvgHighPrice: intPtr(10), AvgLowPrice: intPtr(5)},
+		{Timestamp: base.Add(time.Hour), AvgHighPrice: intPtr(20)},
+		{Timestamp: base.Add(2 * time.Hour)},
+		{Timestamp: base.Add(3 * time.Hour), AvgHighPrice: intPtr(30), AvgLowPrice: intPtr(15)},
+		{Timestamp: base.Add(4 * time.Hour), AvgHighPrice: intPtr(50), AvgLowPrice: intPtr(25)},
+	}
+
+	sampled := utils.SampleAverage(points, 2, testTimeseriesAccessor)
+	require.Len(t, sampled, 2)
+
+	assert.Equal(t, int64(15), *sampled[0].AvgHighPrice)
+	assert.Equal(t, int64(5), *sampled[0].AvgLowPrice)
+	assert.Equal(t, int64(40), *sampled[1].AvgHighPrice)
+	assert.Equal(t, int64(20), *sampled[1].AvgLowPrice)
+
+	// The input slice must not be modified.
+	assert.Equal(t, int64(20), *points[1].AvgHighPrice)
+	assert.Nil(t, points[1].AvgLowPrice)
+}
+
+func TestSamplingMode_IsValid(t *testing.T) {
+	for _, mode := range []utils.SamplingMode{utils.SamplingVoronoi, utils.SamplingLTTB, utils.SamplingMinMax, utils.SamplingAverage} {
+		assert.True(t, mode.IsValid(), mode)
+	}
+	assert.False(t, utils.SamplingMode("median").IsValid())
+	assert.Equal(t, utils.SamplingVoronoi, utils.SamplingMode("").OrDefault())
+}
+
+func TestPriceService_GetPriceHistory_ReportsSampling(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	base := time.Now().UTC().Add(-time.Hour)
+	priceRepo := &fakePriceRepo{
+		timeseriesPoints: map[int][]models.PriceTimeseriesPoint{
+			1: {{ItemID: 1, Timestamp: base, AvgHighPrice: intPtr(10), AvgLowPrice: intPtr(9)}},
+		},
+	}
+	svc := services.NewPriceService(priceRepo, &fakeItemRepo{}, newMemoryCache(), "", logger)
+
+	resp, err := svc.GetPriceHistory(context.Background(), models.PriceHistoryParams{ItemID: 1, Period: models.Period24Hours})
+	require.NoError(t, err)
+	assert.Equal(t, "voronoi", resp.Sampling)
+
+	resp, err = svc.GetPriceHistory(context.Background(), models.PriceHistoryParams{
+		ItemID: 1, Period: models.Period24Hours, Sampling: utils.SamplingLTTB,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "lttb", resp.Sampling)
+}
+
+func TestPriceHandler_GetPriceHistory_Sampling(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	mockPriceService := new(MockPriceService)
+	handler := handlers.NewPriceHandler(mockPriceService, nil, nil, logger)
+
+	mockPriceService.On("GetPriceHistory", mock.Anything, mock.MatchedBy(func(p models.PriceHistoryParams) bool {
+		return p.Sampling == utils.SamplingMinMax
+	})).Return(&models.PriceHistoryResponse{ItemID: 4151, Period: "7d", Sampling: "minmax"}, nil)
+
+	app := fiber.New()
+	app.Get("/prices/history/:id", handler.GetPriceHistory)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/prices/history/4151?sampling=minmax", http.NoBody))
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+
+	var result map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	meta := result["meta"].(map[string]any)
+	assert.Equal(t, "minmax", meta["sampling"])
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/prices/history/4151?sampling=median", http.NoBody))
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+
+	mockPriceService.AssertExpectations(t)
+}
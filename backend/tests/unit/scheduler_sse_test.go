@@ -408,3 +408,53 @@ func TestScheduler_SyncCurrentPricesJob_SyncFailure(t *testing.T) {
 func int64Ptr(v int64) *int64 {
 	return &v
 }
+
+func TestScheduler_SyncCurrentPricesJob_BroadcastsPaperFills(t *testing.T) {
+	testSequenceMutex.Lock()
+	defer testSequenceMutex.Unlock()
+
+	logger := zap.NewNop().Sugar()
+	mockPriceService := new(MockPriceService)
+	mockItemService := new(MockItemService)
+	mockWatchlistService := new(MockWatchlistService)
+	mockPaperService := new(MockPaperTradingService)
+	testHub := NewTestSSEHub(logger, 100, 1)
+	defer testHub.StopAndWait()
+	defer time.Sleep(500 * time.Millisecond)
+
+	s := scheduler.NewScheduler(mockPriceService, mockItemService, mockWatchlistService, testHub.SSEHub, logger)
+	s.SetPaperTradingService(mockPaperService)
+
+	updates := []models.BulkPriceUpdate{
+		{ItemID: 4151, HighPrice: int64Ptr(1_600_000), LowPrice: int64Ptr(1_450_000)},
+	}
+	fills := []models.PaperFill{
+		{OfferID: 1, ItemID: 4151, Side: models.PaperOfferBuy, Quantity: 70, Price: 1_500_000},
+	}
+
+	mockItemService.On("SyncItemsFromMapping", mock.AnythingOfType("*context.timerCtx")).Return(nil).Maybe()
+	mockPriceService.On("SyncCurrentPrices", mock.AnythingOfType("*context.timerCtx")).Return(updates, nil)
+	mockPriceService.On("EnsureFuturePartitions", mock.AnythingOfType("*context.timerCtx"), mock.Anything).Return(nil).Maybe()
+	mockPaperService.On("ProcessPriceUpdates", mock.AnythingOfType("*context.timerCtx"), updates).Return(fills, nil)
+
+	err := s.Start()
+	assert.NoError(t, err)
+
+	time.Sleep(2 * time.Second)
+
+	s.Stop()
+	time.Sleep(200 * time.Millisecond)
+
+	paperFills := 0
+	for _, msg := range testHub.GetMessages() {
+		if msg.Event == "paper-fill" {
+			paperFills++
+			if assert.NotNil(t, msg.ItemID, "paper-fill should have ItemID set") {
+				assert.Equal(t, 4151, *msg.ItemID)
+			}
+		}
+	}
+	assert.GreaterOrEqual(t, paperFills, 1, "Expected a paper-fill event")
+
+	mockPaperService.AssertExpectations(t)
+}
@@ -0,0 +1,609 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/middleware"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/screener"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// fakeScreenRepo keeps saved screens in memory.
+type fakeScreenRepo struct {
+	screens []models.Screen
+}
+
+func (r *fakeScreenRepo) Create(_ context.Context, screen *models.Screen, maxPerUser int) (bool, error) {
+	count := 0
+	for _, s := range r.screens {
+		if s.UserID == screen.UserID {
+			count++
+		}
+	}
+	if count >= maxPerUser {
+		return false, nil
+	}
+	screen.ID = int64(len(r.screens) + 1)
+	r.screens = append(r.screens, *screen)
+	return true, nil
+}
+
+func (r *fakeScreenRepo) List(_ context.Context, userID string) ([]models.Screen, error) {
+	var screens []models.Screen
+	for _, s := range r.screens {
+		if s.UserID == userID {
+			screens = append(screens, s)
+		}
+	}
+	return screens, nil
+}
+
+func (r *fakeScreenRepo) Get(_ context.Context, userID string, id int64) (*models.Screen, error) {
+	for _, s := range r.screens {
+		if s.UserID == userID && s.ID == id {
+			return &s, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeScreenRepo) Update(_ context.Context, screen *models.Screen) (bool, error) {
+	for i, s := range r.screens {
+		if s.UserID == screen.UserID && s.ID == screen.ID {
+			r.screens[i].Name = screen.Name
+			r.screens[i].Expression = screen.Expression
+			r.screens[i].Alert = screen.Alert
+			r.screens[i].AlertItemIDs = nil
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeScreenRepo) Delete(_ context.Context, userID string, id int64) (bool, error) {
+	for i, s := range r.screens {
+		if s.UserID == userID && s.ID == id {
+			r.screens = append(r.screens[:i], r.screens[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeScreenRepo) ListAlerting(_ context.Context) ([]models.Screen, error) {
+	var screens []models.Screen
+	for _, s := range r.screens {
+		if s.Alert {
+			screens = append(screens, s)
+		}
+	}
+	return screens, nil
+}
+
+func (r *fakeScreenRepo) SetAlertState(_ context.Context, id int64, itemIDs []int, alertedAt *time.Time) error {
+	for i := range r.screens {
+		if r.screens[i].ID == id {
+			encoded, err := json.Marshal(itemIDs)
+			if err != nil {
+				return err
+			}
+			r.screens[i].AlertItemIDs = encoded
+			if alertedAt != nil {
+				r.screens[i].LastAlertAt = alertedAt
+			}
+		}
+	}
+	return nil
+}
+
+func i64(v int64) *int64 { return &v }
+
+func f64(v float64) *float64 { return &v }
+
+func screenerTestRows() []models.ScreenerRow {
+	return []models.ScreenerRow{
+		{ItemID: 4151, Name: "Abyssal whip", Members: true, Category: "weapon", Tags: []string{"melee", "members"},
+			HighPrice: i64(1_500_000), LowPrice: i64(1_400_000), Margin: i64(70_000), ROI: f64(5), BuyLimit: i64(70),
+			Volume24h: 9_000, Change24h: f64(-2.5)},
+		{ItemID: 11802, Name: "Armadyl godsword", Members: true, Category: "weapon", Tags: []string{"melee", "members"},
+			HighPrice: i64(12_000_000), LowPrice: i64(11_800_000), Margin: i64(-40_000), ROI: f64(-0.3), BuyLimit: i64(8),
+			Volume24h: 600, Change24h: f64(4)},
+		{ItemID: 2434, Name: "Prayer potion(4)", Members: true, Category: "potion", Tags: []string{"members"},
+			HighPrice: i64(9_000), LowPrice: i64(8_800), Margin: i64(20), ROI: f64(0.2), BuyLimit: i64(2_000),
+			Volume24h: 400_000},
+		{ItemID: 1333, Name: "Rune scimitar", Category: "weapon", Tags: []string{"melee", "f2p"},
+			HighPrice: i64(15_000), LowPrice: i64(14_500), Margin: i64(200), ROI: f64(1.4), BuyLimit: i64(70),
+			Volume24h: 20_000},
+		{ItemID: 9999, Name: "Unpriced junk", Category: "misc"},
+	}
+}
+
+func screenIDs(rows []models.ScreenerRow) []int {
+	ids := make([]int, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ItemID
+	}
+	return ids
+}
+
+func TestScreenerQuery(t *testing.T) {
+	rows := screenerTestRows()
+
+	tests := []struct {
+		name  string
+		expr  string
+		want  []int
+		limit int
+	}{
+		{name: "example screen", expr: "margin > 50000 AND volume24h > 500 AND members = true AND buyLimit >= 70 ORDER BY roi DESC", want: []int{4151}},
+		{name: "default order by volume", expr: "members", want: []int{2434, 4151, 11802}},
+		{name: "suffixes", expr: "high >= 1.5m", want: []int{4151, 11802}},
+		{name: "or and parentheses", expr: "(roi > 1 OR category = 'potion') AND NOT members = false ORDER BY itemId", want: []int{2434, 4151}},
+		{name: "tag membership", expr: "tag = 'f2p'", want: []int{1333}},
+		{name: "tag exclusion", expr: "tag != 'members' ORDER BY itemId", want: []int{1333, 9999}},
+		{name: "in list", expr: "category IN ('potion', 'misc') ORDER BY itemId", want: []int{2434, 9999}},
+		{name: "not in numbers", expr: "itemId NOT IN (4151, 11802, 9999) ORDER BY itemId DESC", want: []int{2434, 1333}},
+		{name: "tag in list", expr: "tag IN ('f2p', 'nope')", want: []int{1333}},
+		{name: "contains ignores case", expr: "name CONTAINS 'GODSWORD'", want: []int{11802}},
+		{name: "string equality ignores case", expr: "name = 'rune SCIMITAR'", want: []int{1333}},
+		{name: "arithmetic", expr: "margin * buyLimit > 1m", want: []int{4151}},
+		{name: "negative numbers", expr: "change24h < -1", want: []int{4151}},
+		{name: "unknown never matches", expr: "NOT change24h > 0", want: []int{4151}},
+		{name: "unknown or true matches", expr: "change24h > 0 OR itemId = 9999 ORDER BY itemId", want: []int{9999, 11802}},
+		{name: "division by zero is unknown", expr: "margin / 0 > 0 OR margin / 0 <= 0", want: []int{}},
+		{name: "order nulls last", expr: "category = 'weapon' OR itemId = 9999 ORDER BY change24h DESC, itemId", want: []int{11802, 4151, 1333, 9999}},
+		{name: "order by expression", expr: "members ORDER BY high - low", want: []int{2434, 4151, 11802}},
+		{name: "order only", expr: "ORDER BY name DESC LIMIT 2", want: []int{9999, 1333}, limit: -1},
+		{name: "keywords any case", expr: "members and roi > 1 order by roi desc", want: []int{4151}},
+		{name: "doubled quote", expr: "name != 'it''s' AND itemId = 1333", want: []int{1333}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := screener.Parse(tt.expr)
+			require.NoError(t, err)
+			limit := 0
+			if tt.limit < 0 {
+				limit = query.Limit()
+			}
+			got, total := query.Run(rows, limit)
+			assert.Equal(t, tt.want, screenIDs(got))
+			if limit == 0 {
+				assert.Equal(t, len(tt.want), total)
+			}
+		})
+	}
+}
+
+func TestScreenerParse_Errors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{expr: "", want: "expression is required"},
+		{expr: strings.Repeat("a", models.MaxScreenExpressionLength+1), want: "expression must be at most 1000 characters"},
+		{expr: "margin > ", want: "expected a field or value, found end of expression at position 10"},
+		{expr: "profit > 5", want: "unknown field 'profit' at position 1"},
+		{expr: "margin", want: "expected a condition, found a number expression at position 1"},
+		{expr: "margin > 'a'", want: "cannot compare number with string at position 8"},
+		{expr: "name > 'a'", want: "strings can only be compared with = or != at position 6"},
+		{expr: "name + 1 > 2", want: "'+' needs numbers, found a string expression at position 6"},
+		{expr: "margin > 5 AND roi", want: "AND needs conditions, found a number expression at position 12"},
+		{expr: "tag > 'x'", want: "tag can only be compared with = or != and a string at position 5"},
+		{expr: "tag = name", want: "tag can only be compared with = or != and a string at position 5"},
+		{expr: "category IN (1)", want: "IN list values must be strings at position 14"},
+		{expr: "members IN (true)", want: "IN needs a number, string or tag at position 9"},
+		{expr: "margin > 5 ORDER BY tag", want: "cannot order by tag at position 21"},
+		{expr: "margin > 5 LIMIT 0", want: "LIMIT must be a whole number from 1 to 500 at position 18"},
+		{expr: "margin > 5 LIMIT 2.5", want: "LIMIT must be a whole number from 1 to 500 at position 18"},
+		{expr: "margin > 5 margin", want: "unexpected 'margin' at position 12"},
+		{expr: "(margin > 5", want: "expected ')', found end of expression at position 12"},
+		{expr: "name = 'open", want: "unterminated string at position 8"},
+		{expr: "margin > 5; DROP TABLE items", want: "unexpected character ';' at position 11"},
+		{expr: "margin > 5x", want: "invalid number \"5x\" at position 10"},
+		{expr: strings.Repeat("(", 40) + "members" + strings.Repeat(")", 40), want: "expression nested too deeply at position 33"},
+		{expr: strings.Repeat("NOT ", 40) + "members", want: "expression nested too deeply at position 129"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := screener.Parse(tt.expr)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, models.ErrInvalidScreen))
+			assert.Equal(t, models.ErrInvalidScreen.Error()+": "+tt.want, err.Error())
+		})
+	}
+}
+
+func TestScreenerBuildRows(t *testing.T) {
+	buyLimit, highAlch := 70, 72_000
+	rows := screener.BuildRows(screener.Market{
+		Items: []models.Item{
+			{ItemID: 4151, Name: "Abyssal whip", Members: true, BuyLimit: &buyLimit, HighAlch: &highAlch, Tags: []string{"melee"}},
+			{ItemID: 1, Name: "Unpriced"},
+		},
+		Prices: map[int]models.CurrentPrice{
+			4151: {ItemID: 4151, HighPrice: i64(1_500_000), LowPrice: i64(1_400_000)},
+			561:  {ItemID: 561, HighPrice: i64(200)},
+		},
+		Volume1h:  map[int]int64{4151: 300},
+		Volume24h: map[int]int64{4151: 9_000},
+		Mid1hAgo:  map[int]int64{4151: 1_450_000},
+		Mid24hAgo: map[int]int64{4151: 1_160_000},
+	})
+	require.Len(t, rows, 2)
+
+	whip := rows[0]
+	assert.Equal(t, int64(1_450_000), *whip.MidPrice)
+	assert.Equal(t, int64(100_000), *whip.Spread)
+	// 2% tax on the 1.5m sale.
+	assert.Equal(t, int64(70_000), *whip.Margin)
+	assert.InDelta(t, 5.0, *whip.ROI, 1e-9)
+	assert.Equal(t, int64(70_000*70), *whip.LimitProfit)
+	assert.Equal(t, int64(72_000-1_500_000-200), *whip.AlchProfit)
+	assert.Equal(t, int64(300), whip.Volume1h)
+	assert.Equal(t, int64(9_000), whip.Volume24h)
+	assert.InDelta(t, 0.0, *whip.Change1h, 1e-9)
+	assert.InDelta(t, 25.0, *whip.Change24h, 1e-9)
+	assert.Nil(t, whip.Change7d)
+	assert.Equal(t, []string{"melee"}, whip.Tags)
+
+	unpriced := rows[1]
+	assert.Nil(t, unpriced.MidPrice)
+	assert.Nil(t, unpriced.Margin)
+	assert.Nil(t, unpriced.ROI)
+	assert.Nil(t, unpriced.AlchProfit)
+	assert.Nil(t, unpriced.Change24h)
+}
+
+func TestScreenerFields(t *testing.T) {
+	fields := screener.Fields()
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
+		assert.NotEmpty(t, f.Description)
+		// Every documented field parses.
+		expr := f.Name + " = 1"
+		switch f.Type {
+		case models.ScreenerFieldString, models.ScreenerFieldTags:
+			expr = f.Name + " = 'x'"
+		case models.ScreenerFieldBool:
+			expr = f.Name
+		}
+		_, err := screener.Parse(expr)
+		assert.NoError(t, err, expr)
+	}
+	assert.Subset(t, names, []string{"high", "low", "spread", "margin", "roi", "volume24h", "alchProfit", "change7d", "tag"})
+}
+
+func newTestScreenerService(repo *fakeScreenRepo, prices []models.CurrentPrice) (services.ScreenerService, *fakePriceRepo) {
+	buyLimit := 70
+	itemRepo := listingItemRepo{&fakeItemRepo{itemsByID: map[int]*models.Item{
+		4151: {ItemID: 4151, Name: "Abyssal whip", Members: true, BuyLimit: &buyLimit, IconURL: "Abyssal whip.png"},
+		1333: {ItemID: 1333, Name: "Rune scimitar", BuyLimit: &buyLimit},
+	}}}
+	priceRepo := &fakePriceRepo{
+		getAllCurrentPricesResp: prices,
+		tradeVolumes:            map[int]int64{4151: 9_000, 1333: 20_000},
+	}
+	mockPriceService := new(MockPriceService)
+	mockPriceService.On("GetPricesAt", mock.Anything, mock.Anything, mock.Anything).Return([]models.PriceAt{}, nil)
+	return services.NewScreenerService(repo, itemRepo, priceRepo, mockPriceService, zap.NewNop().Sugar()), priceRepo
+}
+
+func TestScreenerService_Run(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestScreenerService(&fakeScreenRepo{}, []models.CurrentPrice{
+		{ItemID: 4151, HighPrice: i64(1_500_000), LowPrice: i64(1_400_000)},
+		{ItemID: 1333, HighPrice: i64(15_000), LowPrice: i64(14_500)},
+	})
+
+	result, err := svc.Run(ctx, "margin > 100 ORDER BY margin DESC LIMIT 1", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Total)
+	require.Len(t, result.Rows, 1)
+	assert.Equal(t, 4151, result.Rows[0].ItemID)
+	assert.Contains(t, result.Rows[0].IconURL, "https://")
+	assert.False(t, result.AsOf.IsZero())
+
+	result, err = svc.Run(ctx, "margin > 100 LIMIT 1", 5)
+	require.NoError(t, err)
+	assert.Len(t, result.Rows, 2, "the limit parameter overrides LIMIT")
+
+	_, err = svc.Run(ctx, "margin >", 0)
+	assert.ErrorIs(t, err, models.ErrInvalidScreen)
+}
+
+func TestScreenerService_SavedScreens(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestScreenerService(&fakeScreenRepo{}, nil)
+
+	_, err := svc.CreateScreen(ctx, "alice", models.Screen{Name: " ", Expression: "members"})
+	assert.EqualError(t, err, "invalid screen: name is required")
+	_, err = svc.CreateScreen(ctx, "alice", models.Screen{Name: "Bad", Expression: "members >"})
+	assert.ErrorIs(t, err, models.ErrInvalidScreen)
+
+	screen, err := svc.CreateScreen(ctx, "alice", models.Screen{Name: " Flips ", Expression: " margin > 1k ", Alert: true})
+	require.NoError(t, err)
+	assert.Equal(t, "Flips", screen.Name)
+	assert.Equal(t, "margin > 1k", screen.Expression)
+
+	other, err := svc.GetScreen(ctx, "bob", screen.ID)
+	require.NoError(t, err)
+	assert.Nil(t, other)
+
+	updated, err := svc.UpdateScreen(ctx, "alice", screen.ID, models.Screen{Name: "Big flips", Expression: "margin > 1m"})
+	require.NoError(t, err)
+	require.NotNil(t, updated)
+	assert.Equal(t, "Big flips", updated.Name)
+	assert.False(t, updated.Alert)
+
+	missing, err := svc.UpdateScreen(ctx, "bob", screen.ID, models.Screen{Name: "Mine", Expression: "members"})
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	result, err := svc.RunScreen(ctx, "bob", screen.ID, 0)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	for i := 1; i < models.MaxSavedScreens; i++ {
+		_, err := svc.CreateScreen(ctx, "alice", models.Screen{Name: fmt.Sprintf("Screen %d", i), Expression: "members"})
+		require.NoError(t, err)
+	}
+	_, err = svc.CreateScreen(ctx, "alice", models.Screen{Name: "One too many", Expression: "members"})
+	assert.EqualError(t, err, "invalid screen: at most 25 screens can be saved; delete one first")
+
+	deleted, err := svc.DeleteScreen(ctx, "alice", screen.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+}
+
+func TestScreenerService_CheckAlerts(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeScreenRepo{}
+	svc, priceRepo := newTestScreenerService(repo, []models.CurrentPrice{
+		{ItemID: 4151, HighPrice: i64(1_500_000), LowPrice: i64(1_400_000)},
+		{ItemID: 1333, HighPrice: i64(15_000), LowPrice: i64(14_900)},
+	})
+
+	screen, err := svc.CreateScreen(ctx, "alice", models.Screen{Name: "Flips", Expression: "margin > 0", Alert: true})
+	require.NoError(t, err)
+	_, err = svc.CreateScreen(ctx, "alice", models.Screen{Name: "Quiet", Expression: "margin > 0"})
+	require.NoError(t, err)
+
+	// The first check records a baseline without alerting.
+	alerts, err := svc.CheckAlerts(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+	assert.JSONEq(t, `[4151]`, string(repo.screens[0].AlertItemIDs))
+
+	alerts, err = svc.CheckAlerts(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, alerts, "nothing new started matching")
+
+	// The scimitar's spread widens past the tax.
+	priceRepo.getAllCurrentPricesResp[1].LowPrice = i64(14_000)
+	require.NoError(t, svc.RefreshSnapshot(ctx))
+	alerts, err = svc.CheckAlerts(ctx)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, screen.ID, alerts[0].ScreenID)
+	assert.Equal(t, "alice", alerts[0].UserID)
+	assert.Equal(t, "Flips", alerts[0].Name)
+	assert.Equal(t, []int{1333}, alerts[0].ItemIDs)
+	assert.Equal(t, 1, alerts[0].Count)
+	assert.JSONEq(t, `[1333, 4151]`, string(repo.screens[0].AlertItemIDs))
+	assert.NotNil(t, repo.screens[0].LastAlertAt)
+
+	// Items leaving the screen are recorded without alerting.
+	priceRepo.getAllCurrentPricesResp[0].LowPrice = i64(1_499_000)
+	require.NoError(t, svc.RefreshSnapshot(ctx))
+	alerts, err = svc.CheckAlerts(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+	assert.JSONEq(t, `[1333]`, string(repo.screens[0].AlertItemIDs))
+}
+
+func TestScreenerService_CheckAlertsCapsScreensWithoutLimit(t *testing.T) {
+	ctx := context.Background()
+	items := make(map[int]*models.Item, models.MaxScreenerLimit+10)
+	prices := make([]models.CurrentPrice, 0, models.MaxScreenerLimit+10)
+	for id := 1; id <= models.MaxScreenerLimit+10; id++ {
+		items[id] = &models.Item{ItemID: id, Name: fmt.Sprintf("Item %d", id)}
+		prices = append(prices, models.CurrentPrice{ItemID: id, HighPrice: i64(2_000), LowPrice: i64(1_000)})
+	}
+	repo := &fakeScreenRepo{}
+	mockPriceService := new(MockPriceService)
+	mockPriceService.On("GetPricesAt", mock.Anything, mock.Anything, mock.Anything).Return([]models.PriceAt{}, nil)
+	svc := services.NewScreenerService(repo, listingItemRepo{&fakeItemRepo{itemsByID: items}},
+		&fakePriceRepo{getAllCurrentPricesResp: prices}, mockPriceService, zap.NewNop().Sugar())
+
+	_, err := svc.CreateScreen(ctx, "alice", models.Screen{Name: "Everything", Expression: "margin > 0", Alert: true})
+	require.NoError(t, err)
+	_, err = svc.CheckAlerts(ctx)
+	require.NoError(t, err)
+
+	var tracked []int
+	require.NoError(t, json.Unmarshal(repo.screens[0].AlertItemIDs, &tracked))
+	assert.Len(t, tracked, models.MaxScreenerLimit)
+}
+
+func TestScreenerHandler(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	result := &models.ScreenerResult{Expression: "members", Rows: []models.ScreenerRow{{ItemID: 4151}}, Total: 1}
+
+	tests := []struct {
+		setup    func(m *MockScreenerService)
+		name     string
+		method   string
+		path     string
+		body     string
+		expected string
+		status   int
+	}{
+		{
+			name:   "screen",
+			method: "GET",
+			path:   "/screener?q=members%20AND%20margin%20%3E%2050k",
+			status: 200,
+			setup: func(m *MockScreenerService) {
+				m.On("Run", mock.Anything, "members AND margin > 50k", 0).Return(result, nil)
+			},
+		},
+		{
+			name:   "screen with limit",
+			method: "GET",
+			path:   "/screener?q=members&limit=5",
+			status: 200,
+			setup: func(m *MockScreenerService) {
+				m.On("Run", mock.Anything, "members", 5).Return(result, nil)
+			},
+		},
+		{name: "screen without expression", method: "GET", path: "/screener", status: 400, expected: "screener expression parameter 'q' is required"},
+		{name: "screen bad limit", method: "GET", path: "/screener?q=members&limit=501", status: 400, expected: "limit must be between 1 and 500"},
+		{
+			name:   "screen invalid expression",
+			method: "GET",
+			path:   "/screener?q=margin",
+			status: 400,
+			setup: func(m *MockScreenerService) {
+				m.On("Run", mock.Anything, "margin", 0).
+					Return(nil, fmt.Errorf("%w: expected a condition, found a number expression at position 1", models.ErrInvalidScreen))
+			},
+			expected: "expected a condition, found a number expression at position 1",
+		},
+		{
+			name:   "fields",
+			method: "GET",
+			path:   "/screener/fields",
+			status: 200,
+			setup: func(m *MockScreenerService) {
+				m.On("Fields").Return(screener.Fields())
+			},
+		},
+		{
+			name:   "list screens",
+			method: "GET",
+			path:   "/screener/screens",
+			status: 200,
+			setup: func(m *MockScreenerService) {
+				m.On("ListScreens", mock.Anything, "alice").Return([]models.Screen{}, nil)
+			},
+		},
+		{
+			name:   "create screen",
+			method: "POST",
+			path:   "/screener/screens",
+			body:   `{"name": "Flips", "expression": "margin > 50k", "alert": true}`,
+			status: 201,
+			setup: func(m *MockScreenerService) {
+				m.On("CreateScreen", mock.Anything, "alice", models.Screen{Name: "Flips", Expression: "margin > 50k", Alert: true}).
+					Return(&models.Screen{ID: 1, Name: "Flips"}, nil)
+			},
+		},
+		{name: "create bad body", method: "POST", path: "/screener/screens", body: `{`, status: 400, expected: "invalid request body"},
+		{
+			name:   "get screen missing",
+			method: "GET",
+			path:   "/screener/screens/9",
+			status: 404,
+			setup: func(m *MockScreenerService) {
+				m.On("GetScreen", mock.Anything, "alice", int64(9)).Return(nil, nil)
+			},
+			expected: "screen not found",
+		},
+		{name: "get screen bad id", method: "GET", path: "/screener/screens/x", status: 400, expected: "invalid screen ID"},
+		{
+			name:   "update screen",
+			method: "PUT",
+			path:   "/screener/screens/1",
+			body:   `{"name": "Flips", "expression": "margin > 1m"}`,
+			status: 200,
+			setup: func(m *MockScreenerService) {
+				m.On("UpdateScreen", mock.Anything, "alice", int64(1), models.Screen{Name: "Flips", Expression: "margin > 1m"}).
+					Return(&models.Screen{ID: 1}, nil)
+			},
+		},
+		{
+			name:   "delete screen",
+			method: "DELETE",
+			path:   "/screener/screens/1",
+			status: 204,
+			setup: func(m *MockScreenerService) {
+				m.On("DeleteScreen", mock.Anything, "alice", int64(1)).Return(true, nil)
+			},
+		},
+		{
+			name:   "run screen",
+			method: "GET",
+			path:   "/screener/screens/1/results",
+			status: 200,
+			setup: func(m *MockScreenerService) {
+				m.On("RunScreen", mock.Anything, "alice", int64(1), 0).Return(result, nil)
+			},
+		},
+		{
+			name:   "run screen missing",
+			method: "GET",
+			path:   "/screener/screens/2/results",
+			status: 404,
+			setup: func(m *MockScreenerService) {
+				m.On("RunScreen", mock.Anything, "alice", int64(2), 0).Return(nil, nil)
+			},
+			expected: "screen not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockScreenerService)
+			if tt.setup != nil {
+				tt.setup(mockService)
+			}
+			handler := handlers.NewScreenerHandler(mockService, logger)
+
+			app := fiber.New()
+			app.Get("/screener", handler.Screen)
+			app.Get("/screener/fields", handler.ListFields)
+			screens := app.Group("/screener/screens", middleware.RequireUserID())
+			screens.Get("/", handler.ListScreens)
+			screens.Post("/", handler.CreateScreen)
+			screens.Get("/:id", handler.GetScreen)
+			screens.Put("/:id", handler.UpdateScreen)
+			screens.Delete("/:id", handler.DeleteScreen)
+			screens.Get("/:id/results", handler.RunScreen)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-User-ID", "alice")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var body map[string]any
+			_ = json.NewDecoder(resp.Body).Decode(&body)
+			switch {
+			case tt.expected != "":
+				assert.Equal(t, tt.expected, body["error"])
+			case tt.status != 204:
+				assert.NotNil(t, body["data"])
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
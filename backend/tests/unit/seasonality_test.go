@@ -0,0 +1,210 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+)
+
+// seasonalSeries builds 28 days of hourly data with a slow uptrend, a 3% dip
+// and volume surge at 18:00 UTC, and 3% higher prices on Saturdays.
+func seasonalSeries() []models.HourlyPricePoint {
+	end := time.Now().UTC().Truncate(time.Hour)
+	hours := 28 * 24
+	points := make([]models.HourlyPricePoint, 0, hours)
+	for i := hours - 1; i >= 0; i-- {
+		ts := end.Add(-time.Duration(i) * time.Hour)
+		price := 1000 * (1 + 0.001*float64(hours-i)/24)
+		volume := int64(100)
+		if ts.Hour() == 18 {
+			price *= 0.97
+			volume = 300
+		}
+		if ts.Weekday() == time.Saturday {
+			price *= 1.03
+		}
+		points = append(points, models.HourlyPricePoint{Timestamp: ts, MidPrice: price, Volume: volume})
+	}
+	return points
+}
+
+func TestAnalyticsService_GetSeasonality_FindsPatterns(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	priceRepo := &fakePriceRepo{hourlySeries: map[int][]models.HourlyPricePoint{4151: seasonalSeries()}}
+	svc := services.NewAnalyticsService(priceRepo, newMemoryCache(), logger)
+
+	profile, err := svc.GetSeasonality(context.Background(), models.SeasonalityParams{ItemID: 4151, LookbackDays: 28})
+	require.NoError(t, err)
+
+	assert.Equal(t, 4151, profile.ItemID)
+	assert.Greater(t, profile.Days, 25)
+	assert.InDelta(t, 1.0, profile.Coverage, 0.01)
+
+	hourly := profile.HourOfDay
+	require.Len(t, hourly.Buckets, 24)
+	require.NotNil(t, hourly.TroughIndex)
+	assert.Equal(t, 18, *hourly.TroughIndex)
+	dip := hourly.Buckets[18]
+	require.NotNil(t, dip.RelativePrice)
+	assert.InDelta(t, 0.97/(1-0.03/24), *dip.RelativePrice, 0.001)
+	require.NotNil(t, dip.RelativeVolume)
+	assert.Greater(t, *dip.RelativeVolume, 2.5)
+	require.NotNil(t, dip.PriceCILow)
+	require.NotNil(t, dip.PriceCIHigh)
+	assert.LessOrEqual(t, *dip.PriceCILow, *dip.RelativePrice)
+	assert.GreaterOrEqual(t, *dip.PriceCIHigh, *dip.RelativePrice)
+	require.NotNil(t, hourly.Strength)
+	assert.Greater(t, *hourly.Strength, 0.9, "a clean daily dip explains nearly all hourly variance")
+
+	weekly := profile.DayOfWeek
+	require.Len(t, weekly.Buckets, 7)
+	require.NotNil(t, weekly.PeakIndex)
+	assert.Equal(t, int(time.Saturday), *weekly.PeakIndex)
+	assert.InDelta(t, 1.025, *weekly.Buckets[time.Saturday].RelativePrice, 0.005)
+	assert.Greater(t, weekly.Buckets[time.Saturday].Samples, 2)
+}
+
+func TestAnalyticsService_GetSeasonality_CachesAndRefreshes(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	ctx := context.Background()
+	priceRepo := &fakePriceRepo{hourlySeries: map[int][]models.HourlyPricePoint{4151: seasonalSeries()}}
+	svc := services.NewAnalyticsService(priceRepo, newMemoryCache(), logger)
+
+	_, err := svc.GetSeasonality(ctx, models.SeasonalityParams{ItemID: 4151})
+	require.NoError(t, err)
+	_, err = svc.GetSeasonality(ctx, models.SeasonalityParams{ItemID: 4151})
+	require.NoError(t, err)
+	assert.Equal(t, 1, priceRepo.hourlySeriesCalls, "second request is served from cache")
+
+	_, err = svc.GetSeasonality(ctx, models.SeasonalityParams{ItemID: 4151, Refresh: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, priceRepo.hourlySeriesCalls)
+
+	refreshed, err := svc.RefreshSeasonality(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, refreshed, "default lookback is tracked once")
+	assert.Equal(t, 3, priceRepo.hourlySeriesCalls)
+}
+
+func TestAnalyticsService_GetSeasonality_NoData(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	svc := services.NewAnalyticsService(&fakePriceRepo{}, newMemoryCache(), logger)
+
+	profile, err := svc.GetSeasonality(context.Background(), models.SeasonalityParams{ItemID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, models.DefaultSeasonalityLookbackDays, profile.LookbackDays)
+	assert.Zero(t, profile.Observations)
+	assert.Nil(t, profile.HourOfDay.Strength)
+	assert.Nil(t, profile.HourOfDay.PeakIndex)
+}
+
+func TestAnalyticsService_GetSeasonality_Tracking(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	ctx := context.Background()
+	series := seasonalSeries()
+	priceRepo := &fakePriceRepo{hourlySeries: map[int][]models.HourlyPricePoint{}}
+	for id := 1; id <= models.MaxTrackedSeasonalityProfiles+1; id++ {
+		priceRepo.hourlySeries[id] = series
+	}
+	svc := services.NewAnalyticsService(priceRepo, newMemoryCache(), logger)
+
+	// IDs without price history and unsupported lookbacks are never tracked.
+	for id := 100_000; id < 100_010; id++ {
+		profile, err := svc.GetSeasonality(ctx, models.SeasonalityParams{ItemID: id})
+		require.NoError(t, err)
+		assert.Zero(t, profile.Observations)
+	}
+	_, err := svc.GetSeasonality(ctx, models.SeasonalityParams{ItemID: 1, LookbackDays: 29})
+	require.Error(t, err)
+	refreshed, err := svc.RefreshSeasonality(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, refreshed)
+
+	// Tracking is capped; the least recently requested profile is dropped.
+	for id := 1; id <= models.MaxTrackedSeasonalityProfiles+1; id++ {
+		_, err := svc.GetSeasonality(ctx, models.SeasonalityParams{ItemID: id, LookbackDays: 7})
+		require.NoError(t, err)
+	}
+	calls := priceRepo.hourlySeriesCalls
+	refreshed, err = svc.RefreshSeasonality(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.MaxTrackedSeasonalityProfiles, refreshed)
+	assert.Equal(t, calls+models.MaxTrackedSeasonalityProfiles, priceRepo.hourlySeriesCalls)
+}
+
+func TestAnalyticsHandler_GetSeasonality(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+
+	tests := []struct {
+		setup    func(m *MockAnalyticsService)
+		name     string
+		path     string
+		expected string
+		status   int
+	}{
+		{name: "bad id", path: "/analytics/seasonality/abc", status: 400, expected: "invalid item ID"},
+		{name: "short lookback", path: "/analytics/seasonality/4151?lookback=3d", status: 400, expected: "lookback must be one of 7d, 14d, 28d, 56d, 90d"},
+		{name: "unsupported lookback", path: "/analytics/seasonality/4151?lookback=29d", status: 400, expected: "lookback must be one of 7d, 14d, 28d, 56d, 90d"},
+		{name: "bad lookback", path: "/analytics/seasonality/4151?lookback=month", status: 400, expected: "lookback must be one of 7d, 14d, 28d, 56d, 90d"},
+		{
+			name:   "no history",
+			path:   "/analytics/seasonality/4151",
+			status: 404,
+			setup: func(m *MockAnalyticsService) {
+				m.On("GetSeasonality", mock.Anything, models.SeasonalityParams{ItemID: 4151, LookbackDays: 28}).
+					Return(&models.SeasonalityProfile{ItemID: 4151, LookbackDays: 28}, nil)
+			},
+			expected: "not enough price history for seasonality analysis",
+		},
+		{
+			name:   "ok",
+			path:   "/analytics/seasonality/4151?lookback=14d&refresh=true",
+			status: 200,
+			setup: func(m *MockAnalyticsService) {
+				m.On("GetSeasonality", mock.Anything, models.SeasonalityParams{ItemID: 4151, LookbackDays: 14, Refresh: true}).
+					Return(&models.SeasonalityProfile{ItemID: 4151, LookbackDays: 14, Observations: 300}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAnalyticsService := new(MockAnalyticsService)
+			if tt.setup != nil {
+				tt.setup(mockAnalyticsService)
+			}
+			handler := handlers.NewAnalyticsHandler(mockAnalyticsService, nil, logger)
+
+			app := fiber.New()
+			app.Get("/analytics/seasonality/:id", handler.GetSeasonality)
+
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path, http.NoBody))
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var result map[string]any
+			_ = json.NewDecoder(resp.Body).Decode(&result)
+			if tt.expected != "" {
+				assert.Equal(t, tt.expected, result["error"])
+			} else {
+				meta := result["meta"].(map[string]any)
+				assert.Equal(t, float64(14), meta["lookback_days"])
+				assert.Equal(t, "UTC", meta["timezone"])
+			}
+			mockAnalyticsService.AssertExpectations(t)
+		})
+	}
+}
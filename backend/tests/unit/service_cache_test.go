@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/guavi/osrs-ge-tracker/internal/aliases"
 	"github.com/guavi/osrs-ge-tracker/internal/models"
 	"github.com/guavi/osrs-ge-tracker/internal/services"
 )
@@ -100,6 +102,7 @@ type fakeItemRepo struct {
 	upsertErr         error
 	bulkUpsertErr     error
 	getByItemIDItem   *models.Item
+	itemsByID         map[int]*models.Item
 	getByItemIDCalls  int
 	upsertCalls       int
 	bulkUpsertCalls   int
@@ -120,11 +123,53 @@ func (r *fakeItemRepo) GetAll(_ context.Context, params models.ItemListParams) (
 
 func (r *fakeItemRepo) GetByID(_ context.Context, _ uint) (*models.Item, error) { return nil, nil }
 
-func (r *fakeItemRepo) GetByItemID(_ context.Context, _ int) (*models.Item, error) {
+func (r *fakeItemRepo) GetByItemID(_ context.Context, itemID int) (*models.Item, error) {
 	r.getByItemIDCalls++
+	if r.itemsByID != nil {
+		return r.itemsByID[itemID], r.getByItemIDErr
+	}
 	return r.getByItemIDItem, r.getByItemIDErr
 }
 
+func (r *fakeItemRepo) GetByItemIDs(_ context.Context, itemIDs []int) ([]models.Item, error) {
+	r.getByItemIDCalls++
+	items := make([]models.Item, 0, len(itemIDs))
+	for _, id := range itemIDs {
+		if item, ok := r.itemsByID[id]; ok {
+			items = append(items, *item)
+		} else if r.itemsByID == nil && r.getByItemIDItem != nil && r.getByItemIDItem.ItemID == id {
+			items = append(items, *r.getByItemIDItem)
+		}
+	}
+	return items, r.getByItemIDErr
+}
+
+func (r *fakeItemRepo) GetByNames(_ context.Context, names []string) ([]models.Item, error) {
+	items := make([]models.Item, 0)
+	for _, item := range r.itemsByID {
+		for _, name := range names {
+			if strings.EqualFold(item.Name, name) {
+				items = append(items, *item)
+			}
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ItemID < items[j].ItemID })
+	return items, r.getByItemIDErr
+}
+
+func (r *fakeItemRepo) GetBySlugs(_ context.Context, slugs []string) ([]models.Item, error) {
+	items := make([]models.Item, 0)
+	for _, item := range r.itemsByID {
+		for _, slug := range slugs {
+			if aliases.Slug(item.Name) == slug {
+				items = append(items, *item)
+			}
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ItemID < items[j].ItemID })
+	return items, nil
+}
+
 func (r *fakeItemRepo) Search(_ context.Context, _ models.ItemSearchParams) ([]models.Item, int64, error) {
 	return nil, 0, nil
 }
@@ -148,6 +193,12 @@ func (r *fakeItemRepo) Delete(_ context.Context, _ uint) error { return nil }
 func (r *fakeItemRepo) Count(_ context.Context) (int64, error) { return 0, nil }
 
 type fakePriceRepo struct {
+	pricesAt                 map[models.PriceResolution][]models.PriceAt
+	timeseriesPoints         map[int][]models.PriceTimeseriesPoint
+	dailyPoints              map[int][]models.PriceTimeseriesDaily
+	hourlySeries             map[int][]models.HourlyPricePoint
+	tradeVolumes             map[int]int64
+	currentPrices            []models.CurrentPrice
 	getCurrentPriceErr       error
 	getAllCurrentPricesErr   error
 	upsertCurrentPriceErr    error
@@ -156,6 +207,9 @@ type fakePriceRepo struct {
 	getCurrentPriceCalls     int
 	getAllCurrentPricesCalls int
 	upsertCurrentPriceCalls  int
+	hourlySeriesCalls        int
+	dailyPointsCalls         int
+	timeseriesForItemsCalls  int
 }
 
 func (r *fakePriceRepo) GetCurrentPrice(_ context.Context, _ int) (*models.CurrentPrice, error) {
@@ -164,7 +218,7 @@ func (r *fakePriceRepo) GetCurrentPrice(_ context.Context, _ int) (*models.Curre
 }
 
 func (r *fakePriceRepo) GetCurrentPrices(_ context.Context, _ []int) ([]models.CurrentPrice, error) {
-	return nil, nil
+	return r.currentPrices, nil
 }
 
 func (r *fakePriceRepo) GetAllCurrentPrices(_ context.Context) ([]models.CurrentPrice, error) {
@@ -185,16 +239,81 @@ func (r *fakePriceRepo) InsertTimeseriesPoints(_ context.Context, _ string, _ []
 	return nil
 }
 
-func (r *fakePriceRepo) GetTimeseriesPoints(_ context.Context, _ int, _ string, _ models.PriceHistoryParams) ([]models.PriceTimeseriesPoint, error) {
-	return nil, nil
+func (r *fakePriceRepo) GetTimeseriesPoints(_ context.Context, itemID int, _ string, _ models.PriceHistoryParams) ([]models.PriceTimeseriesPoint, error) {
+	return r.timeseriesPoints[itemID], nil
 }
 
 func (r *fakePriceRepo) InsertDailyPoints(_ context.Context, _ []models.PriceTimeseriesDaily) error {
 	return nil
 }
 
-func (r *fakePriceRepo) GetDailyPoints(_ context.Context, _ int, _ models.PriceHistoryParams) ([]models.PriceTimeseriesDaily, error) {
-	return nil, nil
+func (r *fakePriceRepo) GetDailyPoints(_ context.Context, itemID int, _ models.PriceHistoryParams) ([]models.PriceTimeseriesDaily, error) {
+	r.dailyPointsCalls++
+	return r.dailyPoints[itemID], nil
+}
+
+func (r *fakePriceRepo) GetDailyPointsForItems(_ context.Context, itemIDs []int, _, _ time.Time) ([]models.PriceTimeseriesDaily, error) {
+	r.dailyPointsCalls++
+	var out []models.PriceTimeseriesDaily
+	for _, id := range itemIDs {
+		out = append(out, r.dailyPoints[id]...)
+	}
+	return out, nil
+}
+
+func (r *fakePriceRepo) GetTimeseriesPointsForItems(
+	_ context.Context,
+	itemIDs []int,
+	_ string,
+	from, to time.Time,
+) ([]models.PriceTimeseriesPoint, error) {
+	r.timeseriesForItemsCalls++
+	var out []models.PriceTimeseriesPoint
+	for _, id := range itemIDs {
+		for _, p := range r.timeseriesPoints[id] {
+			if !p.Timestamp.Before(from) && !p.Timestamp.After(to) {
+				p.ItemID = id
+				out = append(out, p)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (r *fakePriceRepo) GetPricesAt(
+	_ context.Context,
+	itemIDs []int,
+	resolution models.PriceResolution,
+	ts time.Time,
+) ([]models.PriceAt, error) {
+	wanted := make(map[int]bool, len(itemIDs))
+	for _, id := range itemIDs {
+		wanted[id] = true
+	}
+	var out []models.PriceAt
+	for _, p := range r.pricesAt[resolution] {
+		if wanted[p.ItemID] {
+			p.Resolution = resolution
+			p.RequestedAt = ts
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakePriceRepo) GetHourlySeries(_ context.Context, itemID int, since time.Time) ([]models.HourlyPricePoint, error) {
+	r.hourlySeriesCalls++
+	var out []models.HourlyPricePoint
+	for _, p := range r.hourlySeries[itemID] {
+		if !p.Timestamp.Before(since) {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakePriceRepo) GetTradeVolumes(_ context.Context, _ time.Time) (map[int]int64, error) {
+	return r.tradeVolumes, nil
 }
 
 func (r *fakePriceRepo) Rollup24hToDailyBefore(_ context.Context, _ time.Time) (int64, error) {
@@ -0,0 +1,329 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/guavi/osrs-ge-tracker/internal/handlers"
+	"github.com/guavi/osrs-ge-tracker/internal/models"
+	"github.com/guavi/osrs-ge-tracker/internal/services"
+	"github.com/guavi/osrs-ge-tracker/internal/valuation"
+)
+
+func TestValuationParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		format   string
+		expected []models.BankEntry
+	}{
+		{
+			name:   "runelite tsv",
+			body:   "Item id\tItem name\tItem quantity\n4151\tAbyssal whip\t2\n4152\tAbyssal whip\t10\n",
+			format: valuation.FormatTSV,
+			expected: []models.BankEntry{
+				{ItemID: 4151, Name: "Abyssal whip", Quantity: 2},
+				{ItemID: 4152, Name: "Abyssal whip", Quantity: 10},
+			},
+		},
+		{
+			name:     "csv without header",
+			body:     "\ufeff995,1000000\r\n\r\n4151,0\r\n",
+			format:   valuation.FormatCSV,
+			expected: []models.BankEntry{{ItemID: 995, Quantity: 1000000}, {ItemID: 4151, Quantity: 0}},
+		},
+		{
+			name:     "csv with name column and thousands separator",
+			body:     `4151,"Abyssal whip","1,200"`,
+			format:   valuation.FormatCSV,
+			expected: []models.BankEntry{{ItemID: 4151, Name: "Abyssal whip", Quantity: 1200}},
+		},
+		{
+			name:     "csv header in any order",
+			body:     "qty,name,id\n3,Shark,385\n",
+			format:   valuation.FormatCSV,
+			expected: []models.BankEntry{{ItemID: 385, Name: "Shark", Quantity: 3}},
+		},
+		{
+			name:     "json array",
+			body:     `[{"id":4151,"quantity":1},{"itemId":386,"qty":500,"name":"Shark"}]`,
+			format:   valuation.FormatJSON,
+			expected: []models.BankEntry{{ItemID: 4151, Quantity: 1}, {ItemID: 386, Name: "Shark", Quantity: 500}},
+		},
+		{
+			name:     "json wrapper",
+			body:     `{"items":[{"id":995,"quantity":50}]}`,
+			format:   valuation.FormatJSON,
+			expected: []models.BankEntry{{ItemID: 995, Quantity: 50}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, format, err := valuation.Parse([]byte(tt.body))
+			require.NoError(t, err)
+			assert.Equal(t, tt.format, format)
+			assert.Equal(t, tt.expected, entries)
+		})
+	}
+}
+
+func TestValuationParse_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{name: "empty", body: "  \n", expected: "export is empty"},
+		{name: "no items", body: "[]", expected: "export has no items"},
+		{name: "header only", body: "id,quantity\n", expected: "export has no items"},
+		{name: "malformed json", body: "[{", expected: "malformed JSON"},
+		{name: "json missing quantity", body: `[{"id":4151}]`, expected: "item 1: id and quantity are required"},
+		{name: "bad header", body: "foo,bar\n1,2\n", expected: "header must name an item id and a quantity column"},
+		{name: "wrong column count", body: "1,2,3,4\n", expected: "line 1: expected id,quantity or id,name,quantity"},
+		{name: "bad id", body: "id,quantity\nx,2\n", expected: "line 2: item id must be a number"},
+		{name: "bad quantity", body: "4151,lots\n", expected: "line 1: quantity must be a whole number"},
+		{name: "negative quantity", body: "4151,-1\n", expected: "line 1: quantity must be between 0 and 2147483647"},
+		{name: "quantity over max stack", body: `[{"id":995,"quantity":2147483648}]`, expected: "item 1: quantity must be between 0 and 2147483647"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := valuation.Parse([]byte(tt.body))
+			require.ErrorIs(t, err, models.ErrInvalidBankExport)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestValuationCompute(t *testing.T) {
+	alch := 72000
+	whip := models.Item{ItemID: 4151, Name: "Abyssal whip", HighAlch: &alch}
+	shark := models.Item{ItemID: 385, Name: "Shark"}
+	entries := []models.BankEntry{
+		{ItemID: 4151, Quantity: 1},
+		{ItemID: 4152, Quantity: 2},
+		{ItemID: 995, Quantity: 5000},
+		{ItemID: 385, Quantity: 10},
+		{ItemID: 6512, Quantity: 0},
+		{ItemID: 99999, Name: "Mystery", Quantity: 3},
+	}
+	matches := map[int]valuation.Match{
+		4151: {Item: whip, Resolution: models.BankResolutionDirect},
+		4152: {Item: whip, Resolution: models.BankResolutionNoted},
+		385:  {Item: shark, Resolution: models.BankResolutionDirect},
+	}
+	prices := map[int]models.CurrentPrice{
+		4151: {ItemID: 4151, HighPrice: int64Ptr(1_500_000), LowPrice: int64Ptr(1_400_001)},
+		385:  {ItemID: 385, LowPrice: int64Ptr(800)},
+	}
+
+	result := valuation.Compute(entries, matches, prices)
+
+	require.Len(t, result.Items, 3)
+	whipLine := result.Items[0]
+	assert.Equal(t, 4151, whipLine.ItemID)
+	assert.Equal(t, models.BankResolutionDirect, whipLine.Resolution)
+	assert.Equal(t, []int{4151, 4152}, whipLine.SourceItemIDs)
+	assert.Equal(t, int64(3), whipLine.Quantity)
+	assert.Equal(t, int64(4_500_000), *whipLine.HighValue)
+	assert.Equal(t, int64(4_200_003), *whipLine.LowValue)
+	assert.Equal(t, int64(1_450_000), *whipLine.MidPrice)
+	assert.Equal(t, int64(216_000), *whipLine.HighAlchValue)
+
+	sharkLine := result.Items[1]
+	assert.Equal(t, 385, sharkLine.ItemID)
+	assert.Nil(t, sharkLine.HighValue)
+	assert.Equal(t, int64(8000), *sharkLine.MidValue)
+	assert.Nil(t, sharkLine.HighAlchValue)
+
+	coins := result.Items[2]
+	assert.Equal(t, models.BankResolutionCurrency, coins.Resolution)
+	assert.Equal(t, int64(5000), *coins.HighAlchValue)
+
+	assert.Equal(t, int64(4_500_000+5000), result.TotalHigh)
+	assert.Equal(t, int64(4_200_003+8000+5000), result.TotalLow)
+	assert.Equal(t, int64(4_350_000+8000+5000), result.TotalMid)
+	assert.Equal(t, int64(216_000+5000), result.TotalHighAlch)
+	assert.Equal(t, 1, result.Placeholders)
+	assert.Equal(t, 1, result.UnpricedItems)
+	require.Len(t, result.Unresolved, 1)
+	assert.Equal(t, 99999, result.Unresolved[0].ItemID)
+	assert.Equal(t, "unknown or untradeable item", result.Unresolved[0].Reason)
+}
+
+func TestValuationService_ValueBank(t *testing.T) {
+	itemRepo := &fakeItemRepo{itemsByID: map[int]*models.Item{
+		4151:  {ItemID: 4151, Name: "Abyssal whip"},
+		11802: {ItemID: 11802, Name: "Armadyl godsword"},
+	}}
+	priceRepo := &fakePriceRepo{currentPrices: []models.CurrentPrice{
+		{ItemID: 4151, HighPrice: int64Ptr(1_500_000), LowPrice: int64Ptr(1_400_000)},
+		{ItemID: 11802, HighPrice: int64Ptr(10_000_000), LowPrice: int64Ptr(9_000_000)},
+	}}
+	svc := services.NewValuationService(itemRepo, priceRepo, zap.NewNop().Sugar())
+
+	result, err := svc.ValueBank(context.Background(), []models.BankEntry{
+		{ItemID: 4151, Quantity: 1},
+		{ItemID: 11803, Name: "Armadyl godsword", Quantity: 1}, // noted, confirmed by name
+		{ItemID: 20368, Name: "armadyl godsword", Quantity: 1}, // ornament ID resolved by name
+		{ItemID: 777777, Quantity: 4},
+		{ItemID: 12345, Quantity: 0},
+		{ItemID: 995, Quantity: 100},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, result.Items, 3)
+	ags := result.Items[0]
+	assert.Equal(t, 11802, ags.ItemID)
+	assert.Equal(t, int64(2), ags.Quantity)
+	assert.ElementsMatch(t, []int{11803, 20368}, ags.SourceItemIDs)
+	assert.Equal(t, models.BankResolutionNoted, ags.Resolution)
+	assert.Equal(t, 4151, result.Items[1].ItemID)
+	assert.Equal(t, 995, result.Items[2].ItemID)
+	assert.Equal(t, int64(19_000_000+1_450_000+100), result.TotalMid)
+	assert.Equal(t, 1, result.Placeholders)
+	require.Len(t, result.Unresolved, 1)
+	assert.Equal(t, 777777, result.Unresolved[0].ItemID)
+}
+
+func TestValuationService_ValueBank_UnconfirmedNotes(t *testing.T) {
+	itemRepo := &fakeItemRepo{itemsByID: map[int]*models.Item{
+		4151: {ItemID: 4151, Name: "Abyssal whip"},
+		4587: {ItemID: 4587, Name: "Dragon scimitar"},
+	}}
+	priceRepo := &fakePriceRepo{currentPrices: []models.CurrentPrice{
+		{ItemID: 4151, HighPrice: int64Ptr(1_500_000), LowPrice: int64Ptr(1_400_000)},
+		{ItemID: 4587, HighPrice: int64Ptr(60_000), LowPrice: int64Ptr(58_000)},
+	}}
+	svc := services.NewValuationService(itemRepo, priceRepo, zap.NewNop().Sugar())
+
+	// The ID before each of these is a real item, but nothing says the row
+	// is its note.
+	result, err := svc.ValueBank(context.Background(), []models.BankEntry{
+		{ItemID: 4152, Quantity: 3},
+		{ItemID: 4588, Name: "Mystery box", Quantity: 1},
+		{ItemID: 4590, Name: "Dragon scimitar", Quantity: 1},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, 4587, result.Items[0].ItemID)
+	assert.Equal(t, []int{4590}, result.Items[0].SourceItemIDs)
+	assert.Equal(t, models.BankResolutionName, result.Items[0].Resolution)
+	assert.Equal(t, int64(59_000), result.TotalMid)
+
+	unresolved := make([]int, 0, len(result.Unresolved))
+	for _, u := range result.Unresolved {
+		unresolved = append(unresolved, u.ItemID)
+	}
+	assert.ElementsMatch(t, []int{4152, 4588}, unresolved)
+}
+
+func TestValuationService_RepositoryError(t *testing.T) {
+	itemRepo := &fakeItemRepo{itemsByID: map[int]*models.Item{}, getByItemIDErr: errors.New("db down")}
+	svc := services.NewValuationService(itemRepo, &fakePriceRepo{}, zap.NewNop().Sugar())
+
+	_, err := svc.ValueBank(context.Background(), []models.BankEntry{{ItemID: 4151, Quantity: 1}})
+	assert.Error(t, err)
+}
+
+func TestValuationHandler(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+
+	tests := []struct {
+		setup    func(m *MockValuationService)
+		name     string
+		body     string
+		expected string
+		format   string
+		status   int
+	}{
+		{name: "empty", body: "", status: 400, expected: "export is empty"},
+		{name: "malformed", body: "4151,abc", status: 400, expected: "line 1: quantity must be a whole number"},
+		{
+			name:   "tsv",
+			body:   "4151\t2\n",
+			status: 200,
+			format: valuation.FormatTSV,
+			setup: func(m *MockValuationService) {
+				m.On("ValueBank", mock.Anything, []models.BankEntry{{ItemID: 4151, Quantity: 2}}).
+					Return(&models.BankValuation{TotalMid: 2_900_000}, nil)
+			},
+		},
+		{
+			name:   "service error",
+			body:   `[{"id":4151,"quantity":2}]`,
+			status: 500,
+			setup: func(m *MockValuationService) {
+				m.On("ValueBank", mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+			},
+			expected: "failed to value bank",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockValuationService := new(MockValuationService)
+			if tt.setup != nil {
+				tt.setup(mockValuationService)
+			}
+			handler := handlers.NewValuationHandler(mockValuationService, nil, logger)
+
+			app := fiber.New()
+			app.Post("/valuation", handler.ValueBank)
+
+			req := httptest.NewRequest("POST", "/valuation", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "text/plain")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var result map[string]any
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			if tt.expected != "" {
+				assert.Equal(t, tt.expected, result["error"])
+			} else {
+				assert.NotNil(t, result["data"])
+				assert.Equal(t, tt.format, result["meta"].(map[string]any)["format"])
+			}
+			mockValuationService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestValuationHandler_MultipartUpload(t *testing.T) {
+	mockValuationService := new(MockValuationService)
+	mockValuationService.On("ValueBank", mock.Anything, []models.BankEntry{{ItemID: 995, Quantity: 10}}).
+		Return(&models.BankValuation{TotalMid: 10}, nil)
+	handler := handlers.NewValuationHandler(mockValuationService, nil, zap.NewNop().Sugar())
+
+	app := fiber.New()
+	app.Post("/valuation", handler.ValueBank)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "bank.csv")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("id,quantity\n995,10\n"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest("POST", "/valuation", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	mockValuationService.AssertExpectations(t)
+}